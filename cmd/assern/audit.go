@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/audit"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func runAuditTail(cmd *cobra.Command, args []string) error {
+	path, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+
+	records, err := audit.Tail(path, auditLines)
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		if err := printAuditRecord(rec); err != nil {
+			return err
+		}
+	}
+
+	if !auditFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return audit.Follow(ctx, path, printAuditRecord)
+}
+
+func runAuditStats(cmd *cobra.Command, args []string) error {
+	path, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+
+	var cutoff time.Time
+	if auditSince > 0 {
+		cutoff = time.Now().Add(-auditSince)
+	}
+
+	summarizer := audit.NewSummarizer()
+
+	err = audit.ReadFile(path, func(rec audit.Record) error {
+		if !rec.Time.Before(cutoff) {
+			summarizer.Add(rec)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	summary := summarizer.Summary()
+
+	if auditJSON {
+		return printJSON(summary)
+	}
+
+	printAuditSummary(path, summary)
+
+	return nil
+}
+
+// auditLogPath returns the log to read: --file, or the audit log of the
+// effective configuration for the current directory.
+func auditLogPath() (string, error) {
+	if auditFile != "" {
+		return config.ExpandPath(auditFile), nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+
+	cfg, err := loadEffectiveConfig(cwd)
+	if err != nil {
+		return "", fmt.Errorf("loading config: %w", err)
+	}
+
+	if cfg.Settings == nil || !cfg.Settings.AuditLog.IsEnabled() {
+		return "", errors.New("no audit log configured: set settings.audit_log.path or pass --file")
+	}
+
+	return cfg.Settings.AuditLog.EffectivePath(), nil
+}
+
+// printAuditRecord prints one record as a line, or as JSON with --json.
+func printAuditRecord(rec audit.Record) error {
+	if auditJSON {
+		return json.NewEncoder(os.Stdout).Encode(rec)
+	}
+
+	// Calls over HTTP with an ACL token name its holder
+	caller := rec.Client
+	if rec.Identity != "" {
+		caller = rec.Identity + " (" + rec.Client + ")"
+	}
+
+	line := fmt.Sprintf("%s  %-5s  %8s  %-40s  %s",
+		rec.Time.Local().Format(time.DateTime),
+		rec.Status,
+		rec.Duration().Round(time.Millisecond),
+		rec.Tool,
+		caller,
+	)
+
+	if rec.Error != "" {
+		line += "  " + rec.Error
+	}
+
+	fmt.Println(line)
+
+	return nil
+}
+
+// printAuditSummary prints the totals, then per-tool and per-client counts.
+func printAuditSummary(path string, summary audit.Summary) {
+	fmt.Printf("Audit log: %s\n", path)
+
+	if summary.Calls == 0 {
+		fmt.Println("  (no tool calls recorded)")
+
+		return
+	}
+
+	fmt.Printf("Period:    %s to %s\n",
+		summary.From.Local().Format(time.DateTime), summary.To.Local().Format(time.DateTime))
+	fmt.Printf("Calls:     %d (%d errors)\n", summary.Calls, summary.Errors)
+
+	fmt.Println()
+	fmt.Println("Tools:")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "  TOOL\tSERVER\tCALLS\tERRORS\tAVG\tMAX")
+
+	for _, t := range summary.Tools {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%d\t%d\t%.0fms\t%.0fms\n", t.Tool, t.Server, t.Calls, t.Errors, t.AvgMS, t.MaxMS)
+	}
+
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Println("Clients:")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "  CLIENT\tCALLS\tERRORS")
+
+	for _, c := range summary.Clients {
+		_, _ = fmt.Fprintf(w, "  %s\t%d\t%d\n", c.Client, c.Calls, c.Errors)
+	}
+
+	_ = w.Flush()
+}
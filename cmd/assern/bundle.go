@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/redact"
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// bundlePlatforms are the OS/arch targets a bundle can be built for. They
+// match the release builds in .goreleaser.yml.
+var bundlePlatforms = []string{"darwin/amd64", "darwin/arm64", "linux/amd64", "linux/arm64"}
+
+// bundleManifestFile is the name of the manifest written at the bundle root.
+const bundleManifestFile = "manifest.json"
+
+// bundleOptions configures buildBundle.
+type bundleOptions struct {
+	OS       string
+	Arch     string
+	Output   string // Parent directory of the bundle
+	Binary   string // assern binary to package; defaults to the running one
+	Registry bool   // Include a redacted snapshot of the global mcp.json and config.yaml
+	Force    bool   // Replace an existing bundle directory
+}
+
+// bundleManifest describes a bundle: what it was built from and a checksum
+// for every file, so a distribution can be verified after copying.
+type bundleManifest struct {
+	Name      string       `json:"name"`
+	Version   string       `json:"version"`
+	Commit    string       `json:"commit"`
+	OS        string       `json:"os"`
+	Arch      string       `json:"arch"`
+	CreatedAt time.Time    `json:"created_at"`
+	Files     []bundleFile `json:"files"`
+
+	dir string
+}
+
+// bundleFile is one file of a bundle, relative to the bundle root.
+type bundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	manifest, err := buildBundle(cmd.Root(), bundleOptions{
+		OS:       bundleOS,
+		Arch:     bundleArch,
+		Output:   bundleOutput,
+		Binary:   bundleBinary,
+		Registry: bundleRegistry,
+		Force:    bundleForce,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle: %s\n", manifest.dir)
+	fmt.Printf("  Version:  %s (%s/%s)\n", manifest.Version, manifest.OS, manifest.Arch)
+
+	for _, f := range manifest.Files {
+		fmt.Printf("  - %s\n", f.Path)
+	}
+
+	return nil
+}
+
+// buildBundle writes a ready-to-distribute directory for one platform:
+//
+//	bin/assern               the binary
+//	config/                  default mcp.json and config.yaml (as 'config init')
+//	registry/                redacted global mcp.json and config.yaml (opt-in)
+//	completions/             shell completion scripts
+//	manifest.json            version, platform and file checksums
+func buildBundle(root *cobra.Command, opts bundleOptions) (*bundleManifest, error) {
+	platform := opts.OS + "/" + opts.Arch
+	if !slices.Contains(bundlePlatforms, platform) {
+		return nil, fmt.Errorf("unsupported platform %s (supported: %v)", platform, bundlePlatforms)
+	}
+
+	binary := opts.Binary
+	if binary == "" {
+		if platform != runtime.GOOS+"/"+runtime.GOARCH {
+			return nil, fmt.Errorf("--binary is required to bundle for %s (this binary is %s/%s)",
+				platform, runtime.GOOS, runtime.GOARCH)
+		}
+
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("locating assern binary: %w", err)
+		}
+
+		binary = exe
+	}
+
+	manifest := &bundleManifest{
+		Name:      fmt.Sprintf("assern-%s-%s-%s", version.Version, opts.OS, opts.Arch),
+		Version:   version.Version,
+		Commit:    version.Commit,
+		OS:        opts.OS,
+		Arch:      opts.Arch,
+		CreatedAt: time.Now().UTC(),
+	}
+	manifest.dir = filepath.Join(opts.Output, manifest.Name)
+
+	if err := prepareBundleDir(manifest.dir, opts.Force); err != nil {
+		return nil, err
+	}
+
+	if err := copyFile(binary, filepath.Join(manifest.dir, "bin", "assern"), 0o755); err != nil {
+		return nil, fmt.Errorf("copying binary: %w", err)
+	}
+
+	if err := writeDefaultConfigs(filepath.Join(manifest.dir, "config")); err != nil {
+		return nil, err
+	}
+
+	if opts.Registry {
+		if err := writeRegistrySnapshot(filepath.Join(manifest.dir, "registry")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeCompletions(root, filepath.Join(manifest.dir, "completions")); err != nil {
+		return nil, err
+	}
+
+	files, err := bundleFiles(manifest.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Files = files
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(manifest.dir, bundleManifestFile), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// prepareBundleDir creates dir, refusing to touch an existing bundle unless
+// force is set.
+func prepareBundleDir(dir string, force bool) error {
+	if _, err := os.Stat(dir); err == nil {
+		if !force {
+			return fmt.Errorf("%s already exists (use --force to replace it)", dir)
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("removing existing bundle: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating bundle directory: %w", err)
+	}
+
+	return nil
+}
+
+// writeDefaultConfigs writes the files 'config init' creates.
+func writeDefaultConfigs(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	if err := defaultMCPConfig().Overwrite(filepath.Join(dir, config.GlobalMCPFile)); err != nil {
+		return fmt.Errorf("writing default mcp.json: %w", err)
+	}
+
+	if err := defaultConfig().Save(filepath.Join(dir, config.GlobalConfigFile)); err != nil {
+		return fmt.Errorf("writing default config.yaml: %w", err)
+	}
+
+	return nil
+}
+
+// writeRegistrySnapshot writes the global mcp.json (server definitions) and
+// config.yaml (project registry and settings) with their secrets redacted as
+// 'config show' does: literal env values, credential headers, OAuth client
+// secrets and tokens become <redacted>, while ${VAR} and keyring://
+// references are kept. Missing files are skipped.
+func writeRegistrySnapshot(dir string) error {
+	mcpPath, err := config.GlobalMCPPath()
+	if err != nil {
+		return err
+	}
+
+	cfgPath, err := config.GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating registry directory: %w", err)
+	}
+
+	if config.FileExists(mcpPath) {
+		mcpCfg, err := config.LoadMCPConfig(mcpPath, loadOptions())
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", filepath.Base(mcpPath), err)
+		}
+
+		for name, srv := range mcpCfg.MCPServers {
+			mcpCfg.MCPServers[name] = redact.MCPServer(srv)
+		}
+
+		if err := mcpCfg.Overwrite(filepath.Join(dir, config.GlobalMCPFile)); err != nil {
+			return fmt.Errorf("writing %s: %w", config.GlobalMCPFile, err)
+		}
+	}
+
+	if config.FileExists(cfgPath) {
+		cfg, err := config.Load(cfgPath, loadOptions())
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", filepath.Base(cfgPath), err)
+		}
+
+		if err := redact.Config(cfg).Save(filepath.Join(dir, config.GlobalConfigFile)); err != nil {
+			return fmt.Errorf("writing %s: %w", config.GlobalConfigFile, err)
+		}
+	}
+
+	return nil
+}
+
+// writeCompletions generates completion scripts for every shell cobra
+// supports.
+func writeCompletions(root *cobra.Command, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating completions directory: %w", err)
+	}
+
+	generators := []struct {
+		file string
+		gen  func(io.Writer) error
+	}{
+		{"assern.bash", func(w io.Writer) error { return root.GenBashCompletionV2(w, true) }},
+		{"_assern", root.GenZshCompletion},
+		{"assern.fish", func(w io.Writer) error { return root.GenFishCompletion(w, true) }},
+		{"assern.ps1", root.GenPowerShellCompletionWithDesc},
+	}
+
+	for _, g := range generators {
+		var buf bytes.Buffer
+		if err := g.gen(&buf); err != nil {
+			return fmt.Errorf("generating %s: %w", g.file, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, g.file), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", g.file, err)
+		}
+	}
+
+	return nil
+}
+
+// bundleFiles lists the files under dir with their checksums, sorted by path.
+func bundleFiles(dir string) ([]bundleFile, error) {
+	var files []bundleFile
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		files = append(files, bundleFile{
+			Path:   filepath.ToSlash(rel),
+			Size:   int64(len(data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing bundle files: %w", err)
+	}
+
+	return files, nil
+}
+
+// copyFile copies src to dst with the given mode, creating parent
+// directories.
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+
+		return err
+	}
+
+	return out.Close()
+}
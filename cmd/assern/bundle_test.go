@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestBuildBundle(t *testing.T) {
+	// Not parallel - modifies global homeDirFunc
+
+	tmpHome := t.TempDir()
+	restore := config.SetHomeDirForTesting(tmpHome)
+	defer restore()
+
+	globalDir := filepath.Join(tmpHome, ".valksor", "assern")
+	if err := os.MkdirAll(globalDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	globalMCP := `{
+  // team servers
+  "mcpServers": {
+    "github": {"command": "gh-mcp", "env": {"TOKEN": "${GITHUB_TOKEN}"}},
+    "api": {"url": "https://api.example.com/mcp", "headers": {"Authorization": "Bearer sk-literal-header"}}
+  }
+}`
+	if err := os.WriteFile(filepath.Join(globalDir, "mcp.json"), []byte(globalMCP), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	globalConfig := "projects:\n  work:\n    directories: [~/work]\n    env:\n      API_TOKEN: literal-project-token\n"
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	binary := filepath.Join(t.TempDir(), "assern-linux-arm64")
+	if err := os.WriteFile(binary, []byte("binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("writes bundle", func(t *testing.T) {
+		out := t.TempDir()
+
+		manifest, err := buildBundle(rootCmd, bundleOptions{
+			OS: "linux", Arch: "arm64", Output: out, Binary: binary, Registry: true,
+		})
+		if err != nil {
+			t.Fatalf("buildBundle() error = %v", err)
+		}
+
+		paths := make([]string, 0, len(manifest.Files))
+		for _, f := range manifest.Files {
+			paths = append(paths, f.Path)
+		}
+
+		want := []string{
+			"bin/assern",
+			"completions/_assern",
+			"completions/assern.bash",
+			"completions/assern.fish",
+			"completions/assern.ps1",
+			"config/config.yaml",
+			"config/mcp.json",
+			"registry/config.yaml",
+			"registry/mcp.json",
+		}
+		if !slices.Equal(paths, want) {
+			t.Errorf("files = %v, want %v", paths, want)
+		}
+
+		snapshot, err := os.ReadFile(filepath.Join(manifest.dir, "registry", "mcp.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(snapshot), "sk-literal-header") || !strings.Contains(string(snapshot), "${GITHUB_TOKEN}") {
+			t.Errorf("registry mcp.json = %s, want the literal header redacted and references kept", snapshot)
+		}
+
+		registryConfig, err := os.ReadFile(filepath.Join(manifest.dir, "registry", "config.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(registryConfig), "literal-project-token") || !strings.Contains(string(registryConfig), "<redacted>") {
+			t.Errorf("registry config.yaml = %s, want the literal token redacted", registryConfig)
+		}
+
+		data, err := os.ReadFile(filepath.Join(manifest.dir, bundleManifestFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var written bundleManifest
+		if err := json.Unmarshal(data, &written); err != nil {
+			t.Fatalf("manifest.json: %v", err)
+		}
+
+		if written.OS != "linux" || written.Arch != "arm64" || len(written.Files) != len(want) {
+			t.Errorf("manifest = %+v", written)
+		}
+
+		if !strings.HasSuffix(manifest.dir, "-linux-arm64") {
+			t.Errorf("bundle dir = %s, want platform suffix", manifest.dir)
+		}
+
+		// An existing bundle is only replaced with force
+		_, err = buildBundle(rootCmd, bundleOptions{OS: "linux", Arch: "arm64", Output: out, Binary: binary})
+		if err == nil {
+			t.Error("buildBundle() over existing bundle succeeded, want error")
+		}
+
+		manifest, err = buildBundle(rootCmd, bundleOptions{
+			OS: "linux", Arch: "arm64", Output: out, Binary: binary, Force: true,
+		})
+		if err != nil {
+			t.Fatalf("buildBundle() with force error = %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(manifest.dir, "registry")); !os.IsNotExist(err) {
+			t.Error("registry/ written without Registry option")
+		}
+	})
+
+	errorTests := []struct {
+		name string
+		opts bundleOptions
+		want string
+	}{
+		{
+			name: "unsupported platform",
+			opts: bundleOptions{OS: "plan9", Arch: "386", Binary: binary},
+			want: "unsupported platform",
+		},
+		{
+			name: "cross platform without binary",
+			opts: bundleOptions{OS: otherBundleOS(), Arch: "amd64"},
+			want: "--binary is required",
+		},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Output = t.TempDir()
+
+			_, err := buildBundle(rootCmd, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("buildBundle() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// otherBundleOS returns a supported OS other than the one running the test.
+func otherBundleOS() string {
+	if runtime.GOOS == "linux" {
+		return "darwin"
+	}
+
+	return "linux"
+}
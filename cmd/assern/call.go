@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+// errToolFailed makes 'assern call' exit non-zero for a result with IsError
+// set, after printing it.
+var errToolFailed = errors.New("tool returned an error")
+
+func runCall(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	name := args[0]
+
+	var (
+		result *mcp.CallToolResult
+		err    error
+	)
+
+	// A running instance formats results its own way, so an explicit
+	// --output-format starts the backend here
+	proxied := false
+	if !callFresh && outputFormat == "" {
+		result, proxied, err = callThroughInstance(logger, name)
+	}
+
+	if !proxied {
+		result, err = callOnDemand(name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := printCallResult(result); err != nil {
+		return err
+	}
+
+	if result.IsError {
+		return errToolFailed
+	}
+
+	return nil
+}
+
+// callThroughInstance calls the tool on the running instance. proxied is
+// false when no instance is running, so the caller starts the backend.
+func callThroughInstance(logger *slog.Logger, name string) (*mcp.CallToolResult, bool, error) {
+	existing, err := instance.NewDetector(logger).DetectRunning()
+	if err != nil || existing == nil {
+		logger.Debug("no running instance, starting the backend", "error", err)
+
+		return nil, false, nil
+	}
+
+	logger.Debug("calling tool through running instance", "pid", existing.PID, "socket", existing.SocketPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	client := instance.NewClient(existing.SocketPath)
+	if err := client.Connect(ctx); err != nil {
+		return nil, true, err
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := client.Initialize(ctx); err != nil {
+		return nil, true, err
+	}
+
+	// The schema only types --arg values; aliases are not listed
+	var schema json.RawMessage
+
+	if list, err := client.ListTools(ctx); err == nil {
+		if i := slices.IndexFunc(list.Tools, func(t instance.ToolInfo) bool { return t.Name == name }); i >= 0 {
+			schema = list.Tools[i].InputSchema
+		}
+	}
+
+	arguments, err := callArguments(schemaProperties(schema), callJSON, callArgs)
+	if err != nil {
+		return nil, true, err
+	}
+
+	result, err := client.CallTool(ctx, name, arguments)
+
+	return result, true, err
+}
+
+// callOnDemand starts the servers that can expose the tool and calls it
+// through a temporary aggregator.
+func callOnDemand(name string) (*mcp.CallToolResult, error) {
+	agg, stop, err := startAggregatorFor(name)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	entry, ok := agg.Tool(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (see 'assern list')", aggregator.ErrToolNotFound, name)
+	}
+
+	schema, err := json.Marshal(entry.Tool)
+	if err != nil {
+		return nil, fmt.Errorf("encoding tool: %w", err)
+	}
+
+	var tool struct {
+		InputSchema json.RawMessage `json:"inputSchema"`
+	}
+	_ = json.Unmarshal(schema, &tool)
+
+	arguments, err := callArguments(schemaProperties(tool.InputSchema), callJSON, callArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	return agg.CallTool(callCtx, name, arguments)
+}
+
+// startAggregatorFor starts a temporary aggregator with only the servers
+// that can expose the tool name. stop shuts it down.
+func startAggregatorFor(name string) (agg *aggregator.Aggregator, stop func(), err error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting working directory: %w", err)
+	}
+
+	cfg, err := loadEffectiveConfig(cwd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	return startAggregator(callServers(cfg, name))
+}
+
+// startAggregator starts a temporary aggregator with the servers only, or
+// all servers when only is nil. stop shuts it down.
+func startAggregator(only []string) (agg *aggregator.Aggregator, stop func(), err error) {
+	agg, ctx, logger, err := setupAggregator(false, nil, only)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cancel := func() {
+		if cancel, ok := ctx.Value(cancelKey).(context.CancelFunc); ok {
+			cancel()
+		}
+
+		agg.Events().Close()
+	}
+
+	if err := agg.Start(ctx); err != nil {
+		cancel()
+
+		return nil, nil, fmt.Errorf("starting aggregator: %w", err)
+	}
+
+	stop = func() {
+		if err := agg.Stop(); err != nil {
+			logger.Warn("error stopping aggregator", "error", err)
+		}
+
+		shutdownTracer(agg.TracerProvider(), logger)
+		cancel()
+	}
+
+	return agg, stop, nil
+}
+
+// callServers returns the servers that can expose the tool name (or the
+// target of the alias name), so only those are started; nil starts every
+// server when none can tell.
+func callServers(cfg *config.Config, name string) []string {
+	if cfg.Settings != nil {
+		if target, ok := cfg.Settings.Aliases[name]; ok {
+			name = target
+		}
+	}
+
+	naming := cfg.Settings.ToolNaming()
+
+	var names []string
+
+	for server, srv := range config.GetEffectiveServers(cfg) {
+		if naming.Exposes(server, srv.Prefix, name) {
+			names = append(names, server)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// schemaProperties returns the properties of a tool's input schema.
+func schemaProperties(schema json.RawMessage) map[string]any {
+	var s struct {
+		Properties map[string]any `json:"properties"`
+	}
+
+	if len(schema) > 0 {
+		_ = json.Unmarshal(schema, &s)
+	}
+
+	return s.Properties
+}
+
+// callArguments builds the tool arguments from --json and the --arg
+// key=value pairs, which override keys of --json. A value is decoded as
+// JSON unless the schema types the property as a string; for properties the
+// schema does not type, values that are not valid JSON stay strings.
+func callArguments(properties map[string]any, jsonArgs string, pairs []string) (map[string]any, error) {
+	arguments := make(map[string]any)
+
+	if jsonArgs != "" {
+		if err := json.Unmarshal([]byte(jsonArgs), &arguments); err != nil {
+			return nil, fmt.Errorf("--json: want a JSON object: %w", err)
+		}
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("--arg %q: want key=value", pair)
+		}
+
+		typ := propertyType(properties[key])
+		if typ == "string" {
+			arguments[key] = value
+
+			continue
+		}
+
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			if typ != "" {
+				return nil, fmt.Errorf("--arg %s: %q is not a valid %s", key, value, typ)
+			}
+
+			decoded = value
+		}
+
+		arguments[key] = decoded
+	}
+
+	return arguments, nil
+}
+
+// propertyType returns the JSON Schema type of a property, or "" when it
+// has none or several.
+func propertyType(property any) string {
+	p, _ := property.(map[string]any)
+	typ, _ := p["type"].(string)
+
+	return typ
+}
+
+// printCallResult prints the content of a tool result: text as is, other
+// content as a one-line placeholder, and structured content as JSON when
+// there is no content. --raw prints the whole result as JSON.
+func printCallResult(result *mcp.CallToolResult) error {
+	return writeCallResult(os.Stdout, result, callRaw)
+}
+
+// writeCallResult writes a tool result to w as printCallResult describes;
+// raw writes the whole result as JSON.
+func writeCallResult(w io.Writer, result *mcp.CallToolResult, raw bool) error {
+	if raw {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(result); err != nil {
+			return fmt.Errorf("encoding result: %w", err)
+		}
+
+		return nil
+	}
+
+	if len(result.Content) == 0 && result.StructuredContent != nil {
+		data, err := json.MarshalIndent(result.StructuredContent, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding structured content: %w", err)
+		}
+
+		fmt.Fprintln(w, string(data))
+
+		return nil
+	}
+
+	for _, content := range result.Content {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			fmt.Fprintln(w, strings.TrimSuffix(c.Text, "\n"))
+		case mcp.ImageContent:
+			fmt.Fprintf(w, "[image %s, %d bytes base64]\n", c.MIMEType, len(c.Data))
+		case mcp.AudioContent:
+			fmt.Fprintf(w, "[audio %s, %d bytes base64]\n", c.MIMEType, len(c.Data))
+		case mcp.ResourceLink:
+			fmt.Fprintf(w, "[resource %s]\n", c.URI)
+		case mcp.EmbeddedResource:
+			if text, ok := c.Resource.(mcp.TextResourceContents); ok {
+				fmt.Fprintln(w, strings.TrimSuffix(text.Text, "\n"))
+			} else {
+				fmt.Fprintln(w, "[embedded resource]")
+			}
+		default:
+			fmt.Fprintf(w, "[%T]\n", content)
+		}
+	}
+
+	return nil
+}
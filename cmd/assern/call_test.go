@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestCallArguments(t *testing.T) {
+	t.Parallel()
+
+	properties := map[string]any{
+		"query":   map[string]any{"type": "string"},
+		"perPage": map[string]any{"type": "integer"},
+		"labels":  map[string]any{"type": "array"},
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		pairs   []string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name:  "typed by schema",
+			pairs: []string{"query=42", "perPage=5", `labels=["bug"]`},
+			want:  map[string]any{"query": "42", "perPage": float64(5), "labels": []any{"bug"}},
+		},
+		{
+			name:  "untyped values",
+			pairs: []string{"flag=true", "path=/tmp/x", "expr=a=b"},
+			want:  map[string]any{"flag": true, "path": "/tmp/x", "expr": "a=b"},
+		},
+		{
+			name:  "arg overrides json",
+			json:  `{"query": "old", "perPage": 1}`,
+			pairs: []string{"query=new"},
+			want:  map[string]any{"query": "new", "perPage": float64(1)},
+		},
+		{name: "no arguments", want: map[string]any{}},
+		{name: "invalid typed value", pairs: []string{"perPage=five"}, wantErr: `--arg perPage: "five" is not a valid integer`},
+		{name: "missing value", pairs: []string{"query"}, wantErr: "want key=value"},
+		{name: "json not an object", json: `[1]`, wantErr: "--json: want a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := callArguments(properties, tt.json, tt.pairs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("callArguments() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("callArguments() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("callArguments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallServers(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Servers = map[string]*config.ServerConfig{
+		"github":  {Command: "gh-mcp"},
+		"git":     {Command: "git-mcp"},
+		"fs":      {Command: "fs-mcp", Prefix: "files"},
+		"offline": {Command: "x", Disabled: true},
+	}
+	cfg.Settings.Aliases = map[string]string{"search": "github_search"}
+
+	tests := []struct {
+		tool string
+		want []string
+	}{
+		{tool: "github_search", want: []string{"github"}},
+		{tool: "git_log", want: []string{"git"}},
+		{tool: "files_read", want: []string{"fs"}},
+		{tool: "search", want: []string{"github"}},
+		{tool: "offline_tool", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			t.Parallel()
+
+			if got := callServers(cfg, tt.tool); !slices.Equal(got, tt.want) {
+				t.Errorf("callServers(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
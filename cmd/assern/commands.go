@@ -1,7 +1,11 @@
 package main
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/config"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,6 +23,11 @@ Configuration:
   Local:  .assern/config.yaml           (project-specific config)`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if profileFlag == "" {
+			profileFlag = os.Getenv(config.EnvProfile)
+		}
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return serveCmd.RunE(cmd, args)
 	},
@@ -31,14 +40,84 @@ var serveCmd = &cobra.Command{
 
 This is the default command - running 'assern' is equivalent to 'assern serve'.
 The server aggregates all configured MCP servers and exposes their tools
-with server-name prefixes (e.g., github_search, filesystem_read).`,
+with server-name prefixes (e.g., github_search, filesystem_read).
+
+With --http (or settings.listen) the same server is also exposed over
+Streamable HTTP at /mcp and legacy SSE at /sse, so remote clients can share
+one instance. HTTP keeps serving after the stdio client disconnects.`,
 	RunE: runServe,
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available servers and tools",
-	RunE:  runList,
+	Long: `List the configured servers and the tools they expose, asking the running
+instance when there is one and starting the servers otherwise (--fresh):
+
+  assern list --resources --prompts
+  assern list --server github --schema
+  assern list --json | jq -r '.tools[].name'
+
+--json and --yaml print the tools (with --schema their input schemas), and
+with --resources and --prompts those too, each with the server it comes from.`,
+	RunE: runList,
+}
+
+var callCmd = &cobra.Command{
+	Use:   "call <tool>",
+	Short: "Call a tool without an MCP client",
+	Long: `Call a tool by its exposed name (e.g. github_search_repositories) or an
+alias and print the result, for debugging backend servers without an LLM
+client:
+
+  assern call github_search_repositories --arg query=assern --arg perPage=5
+  assern call filesystem_read_file --json '{"path": "README.md"}'
+
+The call goes through the running instance when there is one. Otherwise, or
+with --fresh or --output-format, only the servers that can expose the tool
+are started for the call. --arg values are decoded as JSON unless the tool's
+schema types the property as a string. Exits non-zero when the tool returns
+an error.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCall,
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <tool>",
+	Short: "Show a tool's schema, server and annotations",
+	Long: `Show everything known about a tool exposed as <tool> (or an alias): its
+original name on the backend, the owning server, description, behaviour
+annotations, aliases, and the full input and output JSON Schemas.
+
+  assern inspect github_search_repositories
+  assern inspect github_search_repositories --json | jq .input_schema
+
+The tool is read from the running instance when there is one. Otherwise, or
+with --fresh, only the servers that can expose the tool are started.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Explore tools, resources and prompts interactively",
+	Long: `Start an interactive session to list and call tools, read resources and get
+prompts, for a quick feedback loop while developing backend servers:
+
+  assern> tools github
+  assern> call github_search_repositories query=assern perPage=5
+  assern> call filesystem_read_file {"path": "README.md"}
+  assern> read file:///README.md
+  assern> prompt review_code language=go
+  assern> format json
+  assern> trace on
+
+The session uses the running instance when there is one. Otherwise, or with
+--fresh, the configured servers are started for it. 'trace on' prints the
+JSON-RPC messages exchanged, 'raw <method> [params]' sends any request, and
+'help' lists the commands. Commands can also be piped in on stdin.`,
+	Args: cobra.NoArgs,
+	RunE: runRepl,
 }
 
 var reloadCmd = &cobra.Command{
@@ -54,83 +133,171 @@ This command connects to the running instance and instructs it to:
 
 In-flight requests to unchanged servers are not disrupted.
 
+With --blue-green, added and changed servers are started alongside the
+running ones first. Once all of them are up, each takes over its tools in one
+step and the servers they replace are stopped after their in-flight calls
+end, so tools never disappear. If a server fails to start, nothing changes.
+
 Alternatively, you can send SIGHUP to the assern process.`,
 	RunE: runReload,
 }
 
-var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Manage mcp.json and config.yaml files",
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of the running instance and its servers",
+	Long: `Connect to the running assern instance and report its project context,
+uptime and config fingerprint, and for each backend server its state
+(running, restarting, needs auth or failed), uptime, tool, resource and
+prompt counts, and last error.`,
+	RunE: runStatus,
 }
 
-var configInitCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Create ~/.valksor/assern/ with mcp.json and config.yaml",
-	Long: `Initialize the global configuration directory with default files.
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show tool call statistics of the running instance",
+	Long: `Connect to the running assern instance and report how many calls each
+backend server handled and how many failed, and which callers (by client name)
+still use tools marked deprecated in the configuration.`,
+	RunE: runStats,
+}
 
-Creates:
-  ~/.valksor/assern/mcp.json    - MCP server definitions (add your servers here)
-  ~/.valksor/assern/config.yaml - Projects and settings
+var healthCmd = &cobra.Command{
+	Use:   "health [server]",
+	Short: "Show server health and recent errors of the running instance",
+	Long: `Connect to the running assern instance and report the state, health and
+last error of each backend server, or of one server when named.
 
-Existing files are preserved unless --force is used.`,
-	RunE: runConfigInit,
+With --errors, print the last errors recorded for each server (up to 20, newest
+first) with when they happened and what failed: start, restart, crash,
+reconnect, health check or a call to a specific tool. Errors are kept in
+memory, so no debug logging or reproduction is needed to see why a server's
+tools stopped working.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHealth,
 }
 
-var configValidateCmd = &cobra.Command{
-	Use:   "validate",
-	Short: "Validate configuration",
-	RunE:  runConfigValidate,
+var logsCmd = &cobra.Command{
+	Use:   "logs [server]",
+	Short: "Show the log of the running instance, or what a server wrote to stderr",
+	Long: `Connect to the running assern instance and print its last log records,
+oldest first: useful when the instance was started by an MCP client and its
+stderr is nowhere to be seen. The instance keeps its last 1000 records (at
+settings.log_level) in memory. --server keeps the records about one server
+and --since those of the last duration (e.g. 5m); with --follow, keep
+printing new records until interrupted.
+
+With a server argument, print the last lines that stdio server wrote to
+stderr instead. The instance keeps the last 200 lines of each server; they
+are also logged at settings.server_logs.level (debug by default) and, with
+settings.server_logs.files, appended to ~/.valksor/assern/logs/<server>.log.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogs,
 }
 
-var mcpCmd = &cobra.Command{
-	Use:   "mcp",
-	Short: "Manage MCP server configurations",
-	Long: `Interactive commands for adding, editing, deleting, and listing MCP servers.
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the tool call audit log",
+	Long: `With settings.audit_log.path set, every tools/call is appended to the
+audit log as a JSON line: time, client session, tool, backend server,
+arguments (hashed, in full or left out), duration and outcome.
 
-Supports both global (~/.valksor/assern/mcp.json) and project-specific
-(.assern/mcp.json) configurations.
+These commands read the log of the current configuration, or the file given
+with --file.`,
+}
 
-Commands can be invoked with colon notation (e.g., mcp:add) or space notation (e.g., mcp add).`,
+var auditTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Print the latest audit log records",
+	Long: `Print the last records of the audit log, oldest first. With --follow,
+keep printing records as they are appended until interrupted.`,
+	Args: cobra.NoArgs,
+	RunE: runAuditTail,
 }
 
-var mcpAddCmd = &cobra.Command{
-	Use:   "add",
-	Short: "Add a new MCP server",
-	Long: `Interactively add a new MCP server configuration.
+var auditStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the audit log by tool and client",
+	Long: `Count the calls and errors recorded in the audit log per tool, with
+average and maximum duration, and per client. --since limits the summary to
+recent records, e.g. --since 24h.`,
+	Args: cobra.NoArgs,
+	RunE: runAuditStats,
+}
+
+var featuresCmd = &cobra.Command{
+	Use:   "features",
+	Short: "Show and flip feature flags of experimental subsystems",
+	Long: `Feature flags gate experimental subsystems such as lazy server startup,
+TOON as the default output format and the discovery/code-mode meta-tools.
+
+Flags are set under settings.features in config.yaml. 'enable' and 'disable'
+flip a flag on the running instance until it exits, without a rebuild or
+restart; flags marked "config only" are read at startup and can only be
+changed in config.`,
+}
 
-Prompts for server name, transport type, and transport-specific settings.
-Allows choosing between global and project-specific scope.`,
-	RunE: runMCPAdd,
+var featuresListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List feature flags and whether they are active",
+	Long: `List every feature flag with its state and where the state comes from
+(default, config or runtime). Queries the running instance when there is one,
+else shows the flags the current configuration would start with.`,
+	Args: cobra.NoArgs,
+	RunE: runFeaturesList,
 }
 
-var mcpEditCmd = &cobra.Command{
-	Use:   "edit [server-name]",
-	Short: "Edit an existing MCP server",
-	Long: `Interactively edit an existing MCP server configuration.
+var featuresEnableCmd = &cobra.Command{
+	Use:   "enable <feature>",
+	Short: "Turn a feature flag on in the running instance",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFeaturesEnable,
+}
 
-If server-name is provided as argument, pre-selects that server.
-Otherwise, prompts to select from available servers.`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runMCPEdit,
+var featuresDisableCmd = &cobra.Command{
+	Use:   "disable <feature>",
+	Short: "Turn a feature flag off in the running instance",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFeaturesDisable,
 }
 
-var mcpDeleteCmd = &cobra.Command{
-	Use:   "delete [server-name]",
-	Short: "Delete MCP server(s)",
-	Long: `Delete one or more MCP server configurations.
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Package assern for distribution to one OS/arch",
+	Long: `Create a ready-to-distribute directory for one platform:
 
-Prompts for server selection with multi-select support.
-Can delete from both global and project-specific configs.`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runMCPDelete,
+  bin/assern      the binary
+  config/         default mcp.json and config.yaml (as 'config init' writes them)
+  registry/       with --registry, the global mcp.json and config.yaml
+  completions/    bash, zsh, fish and PowerShell completion scripts
+  manifest.json   version, platform and SHA-256 of every file
+
+The bundle is named assern-<version>-<os>-<arch> and created under --output.
+The running binary is packaged for its own platform; for other platforms
+pass a cross-compiled binary with --binary (e.g. from 'make bundle').
+
+The registry snapshot has its secrets redacted: literal env values,
+credential headers, OAuth client secrets and tokens become <redacted>, while
+${VAR} and keyring:// references are kept for each user to resolve.`,
+	Args: cobra.NoArgs,
+	RunE: runBundle,
 }
 
-var mcpListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List MCP servers",
-	Long: `List all configured MCP servers with their configurations.
+var compatCmd = &cobra.Command{
+	Use:   "compat",
+	Short: "Check compatibility with real MCP servers",
+}
+
+var compatTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run the compatibility suite against popular MCP servers",
+	Long: `Run each server of a curated set (everything, filesystem, fetch and, with
+$GITHUB_TOKEN, github) behind its own 'assern serve --config-stdin' and
+report which features work through aggregation: tools (listing and one
+harmless call), resources, prompts and notifications (the startup summary).
 
-Shows transport type, scope (global/project), and key settings.
-More detailed than the 'assern list' command.`,
-	RunE: runMCPList,
+The servers are npx/uvx packages downloaded on first use and fetch reaches
+example.com, so the run needs --network. Targets whose launcher (npx, uvx)
+or token is missing are skipped. Exits non-zero when a check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runCompatTest,
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage mcp.json and config.yaml files",
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create ~/.valksor/assern/ with mcp.json and config.yaml",
+	Long: `Initialize the global configuration directory with default files.
+
+Creates:
+  ~/.valksor/assern/mcp.json    - MCP server definitions (add your servers here)
+  ~/.valksor/assern/config.yaml - Projects and settings
+
+Both reference their JSON Schema (see 'assern config schema'), so editors
+with JSON Schema support complete and check keys.
+
+Existing files are preserved unless --force is used.`,
+	RunE: runConfigInit,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate configuration",
+	RunE:  runConfigValidate,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open config.yaml (or mcp.json) in your editor and validate it",
+	Long: `Open the global config.yaml in $VISUAL or $EDITOR (vi when neither is set)
+and validate it when the editor exits. --mcp opens mcp.json instead, and
+--local the files in the nearest .assern directory.
+
+A running instance that started in failsafe mode because of a broken
+configuration picks up the fixed file on 'assern reload'.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show configuration",
+	Long: `Print the global configuration (servers from mcp.json, projects and
+settings from config.yaml) as YAML.
+
+With --effective, print the configuration after merging global, project and
+local (.assern/) sources for the current directory. With --trace, also show
+which source set each server field and setting, in precedence order:
+global mcp.json -> project overrides -> local mcp.json -> local config.yaml.
+
+Secrets (env values, credential headers and arguments, OAuth client secrets,
+tokens) are printed as <redacted>. --reveal prints them after asking for
+confirmation; --yes skips the question.`,
+	RunE: runConfigShow,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema [mcp|yaml|local]",
+	Short: "Print the JSON Schema of mcp.json or config.yaml",
+	Long: `Print the JSON Schema of mcp.json (mcp, the default), the global config.yaml
+(yaml) or a project's .assern/config.yaml (local). The schemas are generated
+from the configuration types, so they match the running version, and are
+published at ` + config.SchemaBaseURL + `.
+
+'config init' points the files it creates at them: "$schema" in mcp.json and
+a yaml-language-server comment in config.yaml, so editors complete and check
+keys. To do the same for an existing file:
+
+  {"$schema": "` + config.SchemaMCP.URL() + `", ...}
+  ` + config.SchemaComment(config.SchemaConfig) + `
+
+'assern config validate' checks the files against the same schemas.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{string(config.SchemaMCP), string(config.SchemaConfig), string(config.SchemaLocalConfig)},
+	RunE:      runConfigSchema,
+}
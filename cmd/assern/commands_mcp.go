@@ -0,0 +1,152 @@
+package main
+
+import "github.com/spf13/cobra"
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Manage MCP server configurations",
+	Long: `Interactive commands for adding, editing, deleting, restoring and listing MCP servers.
+
+Supports both global (~/.valksor/assern/mcp.json) and project-specific
+(.assern/mcp.json) configurations.
+
+Commands can be invoked with colon notation (e.g., mcp:add) or space notation (e.g., mcp add).`,
+}
+
+var mcpAddCmd = &cobra.Command{
+	Use:   "add [server-name] [flags] [-- args...]",
+	Short: "Add a new MCP server",
+	Long: `Add a new MCP server configuration.
+
+Without arguments, prompts for server name, transport type, and
+transport-specific settings, and for global or project-specific scope.
+
+With a name or flags, the server is configured from them instead, for
+scripts and automation. The transport follows from --command (stdio) or --url
+(http; oauth-http with --oauth-* flags) unless --transport is given. Required
+settings that are missing are prompted for when stdin is a terminal, and are
+an error otherwise. --scope project (or --project) adds the server to the
+.assern/mcp.json of the current directory, registering the project with that
+directory when config.yaml does not know it yet.`,
+	Example: `  assern mcp add github --command npx --args=-y --args @modelcontextprotocol/server-github \
+    --env 'GITHUB_TOKEN=${GITHUB_TOKEN}' --yes
+  assern mcp add fs --scope project --command npx --yes -- -y @modelcontextprotocol/server-filesystem .
+  assern mcp add api --url https://api.example.com/mcp --header 'Authorization=Bearer ${API_TOKEN}' --yes`,
+	RunE: runMCPAdd,
+}
+
+var mcpEditCmd = &cobra.Command{
+	Use:   "edit [server-name] [flags] [-- args...]",
+	Short: "Edit an existing MCP server",
+	Long: `Edit an existing MCP server configuration.
+
+If server-name is provided as argument, pre-selects that server.
+Otherwise, prompts to select from available servers.
+
+With flags (the same as 'mcp add', plus --unset-env and --unset-header) only
+the settings given change and nothing is prompted for: --env and --header set
+single entries, --args replaces the argument list. Setting --url on a stdio
+server makes it a remote one, and --command the other way around.`,
+	Example: `  assern mcp edit github --env 'GITHUB_TOKEN=${GH_PAT}' --yes
+  assern mcp edit api --url https://new.example.com/mcp --unset-header X-Old --yes`,
+	RunE: runMCPEdit,
+}
+
+var mcpDeleteCmd = &cobra.Command{
+	Use:   "delete [server-name...]",
+	Short: "Delete MCP server(s)",
+	Long: `Delete one or more MCP server configurations.
+
+Prompts for server selection with multi-select support when no names are
+given. Can delete from both global and project-specific configs. --yes skips
+the confirmation, which is required when stdin is not a terminal.
+
+Deleted definitions are kept, with the time of deletion, in mcp.archive.json
+next to the mcp.json they were deleted from; 'assern mcp restore' brings them
+back.`,
+	RunE: runMCPDelete,
+}
+
+var mcpRestoreCmd = &cobra.Command{
+	Use:   "restore [server-name]",
+	Short: "Restore a deleted MCP server",
+	Long: `Restore the most recently deleted definition of a server from the archive
+(mcp.archive.json) into the global or project mcp.json it was deleted from.
+
+If server-name is not provided, prompts to select from archived servers.
+Fails if a server with that name is configured again. The last 10 deleted
+definitions of each name are kept.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMCPRestore,
+}
+
+var mcpEnableCmd = &cobra.Command{
+	Use:   "enable [server-name...]",
+	Short: "Enable disabled MCP server(s)",
+	Long: `Enable one or more disabled MCP servers, or every server with --all.
+
+Clears "disabled" on the server in each mcp.json (global and project) that
+defines it. Prompts for the servers to enable when no names are given.
+--reload reloads the running instance so the servers start right away.`,
+	Example: `  assern mcp enable github
+  assern mcp enable --all --reload`,
+	RunE: runMCPEnable,
+}
+
+var mcpDisableCmd = &cobra.Command{
+	Use:   "disable [server-name...]",
+	Short: "Disable MCP server(s) without deleting them",
+	Long: `Disable one or more MCP servers, or every server with --all.
+
+Sets "disabled": true on the server in each mcp.json (global and project)
+that defines it, keeping its configuration; 'assern mcp enable' turns it back
+on. Prompts for the servers to disable when no names are given. --reload
+reloads the running instance so the servers stop right away.`,
+	Example: `  assern mcp disable github filesystem
+  assern mcp disable --all --reload`,
+	RunE: runMCPDisable,
+}
+
+var mcpListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List MCP servers",
+	Long: `List all configured MCP servers with their configurations.
+
+Shows transport type, scope (global/project), and key settings.
+More detailed than the 'assern list' command. Secrets in commands and URLs
+are redacted unless --reveal is given.`,
+	RunE: runMCPList,
+}
+
+var mcpImportCmd = &cobra.Command{
+	Use:   "import --from claude|cursor|vscode|windsurf [path]",
+	Short: "Import MCP servers from another client",
+	Long: `Import the MCP servers configured in Claude Desktop, Cursor, VS Code or
+Windsurf into the global or project mcp.json.
+
+Without a path, the client's usual configuration file is used (for Cursor
+and VS Code, the project file in the current directory is tried first).
+Commands, arguments, env, working directory, URLs and headers are converted;
+${env:NAME} references become ${NAME}. Disabled entries are skipped.
+
+Servers whose name is already configured are prompted for: skip, rename or
+overwrite. With --yes they are skipped, or replaced with --overwrite.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMCPImport,
+}
+
+var mcpExportCmd = &cobra.Command{
+	Use:   "export --to claude|cursor|vscode|windsurf",
+	Short: "Export a client configuration that uses assern",
+	Long: `Print an MCP configuration in the format of Claude Desktop, Cursor, VS Code
+or Windsurf.
+
+By default the snippet holds a single stdio entry that starts 'assern serve',
+ready to paste into the client's config. With --all it holds every backend from
+the global and project mcp.json instead, for moving servers to another client.
+${NAME} references become ${env:NAME} for clients that expand them.
+
+The snippet is printed to stdout; --output writes it to a file.`,
+	Args: cobra.NoArgs,
+	RunE: runMCPExport,
+}
@@ -0,0 +1,49 @@
+package main
+
+import "github.com/spf13/cobra"
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage secrets in the OS keyring",
+	Long: `Store API tokens in the OS keyring (macOS Keychain, Secret Service on
+Linux, Windows Credential Manager) instead of mcp.json or .env files, and
+reference them from a server's env as keyring://<name>:
+
+  assern secret set github_token
+  # env: {"GITHUB_TOKEN": "keyring://github_token"}
+
+References are resolved each time the server starts. op://vault/item/field
+references are read with the 1Password CLI instead and need no 'set'.`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret, read from a prompt or stdin",
+	Long: `Store a secret under <name>, replacing any previous value. The value is
+prompted for without echo, or read from stdin when it is not a terminal
+(e.g. 'pass show github | assern secret set github_token'), so it never
+appears in the shell history.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretSet,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a stored secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretGet,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the names of stored secrets",
+	Args:  cobra.NoArgs,
+	RunE:  runSecretList,
+}
+
+var secretDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Remove a stored secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretDelete,
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/compat"
+)
+
+func runCompatTest(cmd *cobra.Command, _ []string) error {
+	if !compatNetwork {
+		return errors.New("the compatibility suite downloads and runs MCP servers from npm and PyPI; pass --network to allow it")
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating the assern binary: %w", err)
+	}
+
+	// The filesystem server only gets an empty scratch directory
+	dir, err := os.MkdirTemp("", "assern-compat-")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	targets := compat.Targets(dir)
+
+	if len(compatOnly) > 0 {
+		for _, name := range compatOnly {
+			if !slices.ContainsFunc(targets, func(t compat.Target) bool { return t.Name == name }) {
+				return fmt.Errorf("unknown target %q (known: %s)", name, strings.Join(compatTargetNames(targets), ", "))
+			}
+		}
+
+		targets = slices.DeleteFunc(targets, func(t compat.Target) bool { return !slices.Contains(compatOnly, t.Name) })
+	}
+
+	report := compat.Run(cmd.Context(), compat.Options{
+		Binary:  binary,
+		Targets: targets,
+		Timeout: compatTimeout,
+		Getenv:  os.Getenv,
+	})
+
+	if compatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("encoding report: %w", err)
+		}
+	} else {
+		printCompatReport(report)
+	}
+
+	if report.Failed() {
+		return errors.New("compatibility checks failed")
+	}
+
+	return nil
+}
+
+// printCompatReport prints one row per target with the status of each
+// feature, then the details of skipped targets and failed checks.
+func printCompatReport(report *compat.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "TARGET\t"+strings.ToUpper(strings.Join(compat.Features, "\t"))+"\tTIME")
+
+	for _, tr := range report.Targets {
+		row := []string{tr.Name}
+
+		for _, feature := range compat.Features {
+			if len(tr.Results) == 0 {
+				row = append(row, "-")
+
+				continue
+			}
+
+			row = append(row, tr.Result(feature).Status)
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", strings.Join(row, "\t"), tr.Duration)
+	}
+
+	_ = w.Flush()
+
+	var notes []string
+
+	for _, tr := range report.Targets {
+		if tr.Detail != "" {
+			notes = append(notes, fmt.Sprintf("%s: %s %s", tr.Name, tr.Status, tr.Detail))
+		}
+
+		for _, r := range tr.Results {
+			if r.Status == compat.StatusFail {
+				notes = append(notes, fmt.Sprintf("%s %s: %s", tr.Name, r.Feature, r.Detail))
+			}
+		}
+	}
+
+	if len(notes) > 0 {
+		fmt.Println()
+
+		for _, note := range notes {
+			fmt.Println("  " + note)
+		}
+	}
+}
+
+// compatTargetNames returns the names of targets.
+func compatTargetNames(targets []compat.Target) []string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+
+	return names
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/log"
+	"github.com/valksor/go-assern/internal/project"
 )
 
 func runConfigInit(cmd *cobra.Command, args []string) error {
@@ -32,10 +36,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	mcpExists := config.FileExists(mcpPath)
 
 	if forceInit || !mcpExists {
-		// Create empty MCP config
-		defaultMCP := config.NewMCPConfig()
-
-		if err := defaultMCP.Save(mcpPath); err != nil {
+		if err := defaultMCPConfig().Overwrite(mcpPath); err != nil {
 			return fmt.Errorf("saving mcp.json: %w", err)
 		}
 
@@ -59,14 +60,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	cfgExists := config.FileExists(cfgPath)
 
 	if forceInit || !cfgExists {
-		// Create default Assern config (projects and settings only)
-		defaultCfg := &config.Config{
-			Servers:  map[string]*config.ServerConfig{}, // Empty - servers come from mcp.json
-			Projects: map[string]*config.ProjectConfig{},
-			Settings: config.DefaultSettings(),
-		}
-
-		if err := defaultCfg.Save(cfgPath); err != nil {
+		if err := defaultConfig().Save(cfgPath); err != nil {
 			return fmt.Errorf("saving config.yaml: %w", err)
 		}
 
@@ -93,7 +87,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 
 		fmt.Println()
 		fmt.Println("Next steps:")
-		fmt.Println("  1. Add MCP servers to mcp.json (can import from Claude Desktop)")
+		fmt.Println("  1. Add MCP servers to mcp.json (or run 'assern mcp import --from claude')")
 		fmt.Println("  2. Run 'assern config validate' to check configuration")
 		fmt.Println("  3. Run 'assern list' to see available tools")
 	} else {
@@ -103,58 +97,190 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runConfigValidate(cmd *cobra.Command, args []string) error {
-	// Validate global MCP config
-	mcpPath, err := config.GlobalMCPPath()
+// defaultMCPConfig returns the mcp.json written by 'config init': no
+// servers, and a $schema reference for editor completion.
+func defaultMCPConfig() *config.MCPConfig {
+	mcpCfg := config.NewMCPConfig()
+	mcpCfg.Schema = config.SchemaMCP.URL()
+
+	return mcpCfg
+}
+
+// defaultConfig returns the Assern config written by 'config init':
+// projects and settings only, servers come from mcp.json.
+func defaultConfig() *config.Config {
+	return &config.Config{
+		Servers:  map[string]*config.ServerConfig{},
+		Projects: map[string]*config.ProjectConfig{},
+		Settings: config.DefaultSettings(),
+	}
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	kind := config.SchemaMCP
+	if len(args) > 0 {
+		var err error
+		if kind, err = config.ParseSchemaKind(args[0]); err != nil {
+			return err
+		}
+	}
+
+	schema, err := config.Schema(kind)
 	if err != nil {
 		return err
 	}
 
-	var mcpCfg *config.MCPConfig
-	if config.FileExists(mcpPath) {
-		mcpCfg, err = config.LoadMCPConfig(mcpPath)
-		if err != nil {
-			return fmt.Errorf("invalid global mcp.json at %s: %w", mcpPath, err)
-		}
+	_, err = cmd.OutOrStdout().Write(schema)
 
-		fmt.Printf("[OK] %s (%d servers)\n", mcpPath, len(mcpCfg.MCPServers))
-	} else {
-		fmt.Printf("[--] %s (not found, optional)\n", mcpPath)
+	return err
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
 	}
 
-	// Validate global config.yaml
-	cfgPath, err := config.GlobalConfigPath()
+	// ${VAR} references resolve like they do for a server started here
+	envLoader := loadGlobalEnv(logger)
+	loadEncryptedEnv(cmd.Context(), envLoader, cwd, logger)
+	loadProjectEnv(envLoader, cwd, logger)
+
+	problems, err := config.CheckFiles(cwd, func(name string) bool {
+		_, ok := envLoader.Lookup(name)
+
+		return ok
+	}, loadOptions())
 	if err != nil {
 		return err
 	}
 
-	var cfg *config.Config
-	if config.FileExists(cfgPath) {
-		cfg, err = config.Load(cfgPath)
-		if err != nil {
-			return fmt.Errorf("invalid global config.yaml at %s: %w", cfgPath, err)
+	files, err := config.ConfigFiles(cwd)
+	if err != nil {
+		return err
+	}
+
+	errorCount, warningCount := 0, 0
+
+	for _, path := range files {
+		if !config.FileExists(path) {
+			fmt.Printf("[--] %s (not found, optional)\n", path)
+
+			continue
 		}
 
-		fmt.Printf("[OK] %s (%d projects)\n", cfgPath, len(cfg.Projects))
-	} else {
-		fmt.Printf("[--] %s (not found, optional)\n", cfgPath)
-	}
+		status := "[OK]"
+
+		var fileProblems []config.Problem
+
+		for _, p := range problems {
+			if p.File != path {
+				continue
+			}
+
+			fileProblems = append(fileProblems, p)
 
-	// Summary
-	serverCount := 0
-	if mcpCfg != nil {
-		serverCount = len(mcpCfg.MCPServers)
+			if p.Severity == config.SeverityError {
+				status = "[XX]"
+				errorCount++
+			} else {
+				warningCount++
+
+				if status == "[OK]" {
+					status = "[!!]"
+				}
+			}
+		}
+
+		fmt.Printf("%s %s\n", status, path)
+
+		for _, p := range fileProblems {
+			printProblem(p)
+		}
 	}
 
-	projectCount := 0
-	if cfg != nil {
-		projectCount = len(cfg.Projects)
+	// Overlaps need a loaded config.yaml, which errors prevent
+	if errorCount == 0 {
+		if globalCfg, err := config.LoadGlobal(loadOptions()); err == nil {
+			printProjectOverlaps(globalCfg)
+		}
 	}
 
 	fmt.Println()
+
+	if errorCount > 0 {
+		return fmt.Errorf("configuration invalid: %d error(s), %d warning(s)", errorCount, warningCount)
+	}
+
+	cfg, err := loadEffectiveConfig(cwd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
 	fmt.Println("Configuration valid!")
-	fmt.Printf("  Servers:  %d\n", serverCount)
-	fmt.Printf("  Projects: %d\n", projectCount)
+	fmt.Printf("  Servers:  %d\n", len(cfg.Servers))
+	fmt.Printf("  Projects: %d\n", len(cfg.Projects))
+
+	if cfg.Profile != "" {
+		fmt.Printf("  Profile:  %s\n", cfg.Profile)
+	}
+
+	if warningCount > 0 {
+		fmt.Printf("  Warnings: %d\n", warningCount)
+	}
 
 	return nil
 }
+
+// printProblem prints a problem of the file listed above it.
+func printProblem(p config.Problem) {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "       %s: ", p.Severity)
+
+	if p.Line > 0 {
+		fmt.Fprintf(&sb, "line %d: ", p.Line)
+	}
+
+	if p.Field != "" {
+		sb.WriteString(p.Field + ": ")
+	}
+
+	sb.WriteString(p.Message)
+
+	fmt.Println(sb.String())
+}
+
+// printProjectOverlaps warns about project directory patterns that match the
+// same paths, showing which project wins for a sample path. Overlaps are
+// warnings, not errors: detection is deterministic, but ties broken by name
+// are usually unintended and should get an explicit priority.
+func printProjectOverlaps(cfg *config.Config) {
+	registry := project.NewRegistry()
+	for name, proj := range cfg.Projects {
+		registry.Add(&project.RegistryProject{
+			Name:        name,
+			Directories: proj.Directories,
+			Remotes:     proj.Remotes,
+			Priority:    proj.Priority,
+		})
+	}
+
+	for _, o := range registry.Overlaps() {
+		fmt.Printf("[!!] overlapping project directories for %s:\n", o.Sample)
+
+		for _, m := range o.Matches {
+			fmt.Printf("       %-20s %s (priority %d)\n", m.Name, m.Pattern, registry.Priority(m.Name))
+		}
+
+		reason := "higher priority"
+		if o.Tied {
+			reason = "name order; set priority: to choose explicitly"
+		}
+
+		fmt.Printf("     %q wins (%s)\n", o.Winner(), reason)
+	}
+}
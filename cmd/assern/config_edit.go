@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := configEditPath(editMCP, editLocal)
+	if err != nil {
+		return err
+	}
+
+	if !config.FileExists(path) {
+		return fmt.Errorf("%s does not exist; run 'assern config init' first", path)
+	}
+
+	editor := editorCommand(os.Getenv)
+
+	edit, err := editorCmd(editor, path)
+	if err != nil {
+		return err
+	}
+
+	if err := edit.Run(); err != nil {
+		return fmt.Errorf("running editor %s: %w", editor[0], err)
+	}
+
+	if err := validateConfigFile(path, editMCP, editLocal); err != nil {
+		return fmt.Errorf("%s is still invalid: %w\nRun 'assern config edit' again to fix it", path, err)
+	}
+
+	fmt.Printf("[OK] %s\n", path)
+	fmt.Println("Run 'assern reload' to apply the changes to a running instance.")
+
+	return nil
+}
+
+// configEditPath returns the file 'config edit' opens: the global or, with
+// local, the .assern config.yaml, or its mcp.json with mcp.
+func configEditPath(mcp, local bool) (string, error) {
+	if !local {
+		if mcp {
+			return config.GlobalMCPPath()
+		}
+
+		return config.GlobalConfigPath()
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+
+	dir := config.FindLocalConfigDir(cwd)
+	if dir == "" {
+		return "", errors.New("no .assern directory found in this directory or its parents")
+	}
+
+	if mcp {
+		return config.LocalMCPPath(dir), nil
+	}
+
+	return config.LocalConfigPath(dir), nil
+}
+
+// editorCommand returns the editor command line from $VISUAL or $EDITOR,
+// which may include arguments such as "code --wait", falling back to vi
+// (notepad on Windows).
+func editorCommand(getenv func(string) string) []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
+
+// editorCmd returns the command opening path in editor, an editorCommand
+// result, connected to the terminal. The editor is resolved on $PATH first,
+// so only an executable file is run.
+func editorCmd(editor []string, path string) (*exec.Cmd, error) {
+	bin, err := exec.LookPath(editor[0])
+	if err != nil {
+		return nil, fmt.Errorf("finding editor %s: %w", editor[0], err)
+	}
+
+	return &exec.Cmd{
+		Path:   bin,
+		Args:   append(slices.Clone(editor), path),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}, nil
+}
+
+// validateConfigFile parses an edited file with the loader for its kind.
+func validateConfigFile(path string, mcp, local bool) error {
+	var err error
+
+	switch {
+	case mcp:
+		_, err = config.LoadMCPConfig(path, loadOptions())
+	case local:
+		_, err = config.LoadLocalProject(path, loadOptions())
+	default:
+		_, err = config.Load(path, loadOptions())
+	}
+
+	return err
+}
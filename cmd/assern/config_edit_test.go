@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Parallel()
+
+	fallback := []string{"vi"}
+	if runtime.GOOS == "windows" {
+		fallback = []string{"notepad"}
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{name: "visual wins", env: map[string]string{"VISUAL": "code --wait", "EDITOR": "nano"}, want: []string{"code", "--wait"}},
+		{name: "editor", env: map[string]string{"EDITOR": "nano"}, want: []string{"nano"}},
+		{name: "blank visual", env: map[string]string{"VISUAL": "  ", "EDITOR": "nano"}, want: []string{"nano"}},
+		{name: "fallback", want: fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := editorCommand(func(name string) string { return tt.env[name] })
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("editorCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditorCmd(t *testing.T) {
+	t.Parallel()
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edit, err := editorCmd([]string{self, "--wait"}, "config.yaml")
+	if err != nil {
+		t.Fatalf("editorCmd() error = %v", err)
+	}
+
+	if edit.Path != self || !slices.Equal(edit.Args, []string{self, "--wait", "config.yaml"}) {
+		t.Errorf("editorCmd() = %s %v, want %s with the arguments and path", edit.Path, edit.Args, self)
+	}
+
+	if _, err := editorCmd([]string{"assern-no-such-editor"}, "config.yaml"); err == nil {
+		t.Error("editorCmd() of a missing editor succeeded")
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/redact"
+)
+
+// shownConfig is the printable form of a configuration. config.Config hides
+// Servers from YAML because they come from mcp.json.
+type shownConfig struct {
+	Project  string                           `yaml:"project,omitempty"`
+	Profile  string                           `yaml:"profile,omitempty"`
+	Servers  map[string]*config.ServerConfig  `yaml:"servers,omitempty"`
+	Projects map[string]*config.ProjectConfig `yaml:"projects,omitempty"`
+	Groups   map[string][]string              `yaml:"groups,omitempty"`
+	Settings *config.Settings                 `yaml:"settings,omitempty"`
+	Auth     map[string]*config.OAuthConfig   `yaml:"auth,omitempty"`
+}
+
+func runConfigShow(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	reveal, err := confirmReveal()
+	if err != nil {
+		return err
+	}
+
+	if !showEffective && !showTrace {
+		cfg, err := loadGlobalConfig()
+		if err != nil {
+			return err
+		}
+
+		return printConfig(out, &shownConfig{
+			Servers:  cfg.Servers,
+			Projects: cfg.Projects,
+			Groups:   cfg.Groups,
+			Settings: cfg.Settings,
+			Auth:     cfg.Auth,
+		}, reveal)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+
+	cfg, trace, err := loadEffectiveTrace(cwd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if err := printConfig(out, &shownConfig{
+		Project:  trace.Project,
+		Profile:  cfg.Profile,
+		Servers:  cfg.Servers,
+		Settings: cfg.Settings,
+	}, reveal); err != nil {
+		return err
+	}
+
+	if showTrace {
+		printTrace(out, trace)
+	}
+
+	return nil
+}
+
+// loadGlobalConfig merges only the global mcp.json and config.yaml.
+func loadGlobalConfig() (*config.Config, error) {
+	mcpPath, err := config.GlobalMCPPath()
+	if err != nil {
+		return nil, err
+	}
+
+	mcpCfg, err := config.LoadMCPConfig(mcpPath, loadOptions())
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", mcpPath, err)
+	}
+
+	cfgPath, err := config.GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var globalCfg *config.Config
+	if config.FileExists(cfgPath) {
+		globalCfg, err = config.Load(cfgPath, loadOptions())
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", cfgPath, err)
+		}
+	}
+
+	return config.BuildEffectiveConfig(mcpCfg, globalCfg, nil, nil, ""), nil
+}
+
+// printConfig writes cfg as YAML, with its secrets redacted (see package
+// redact) unless reveal is set.
+func printConfig(w io.Writer, cfg *shownConfig, reveal bool) error {
+	if !reveal {
+		redacted := *cfg
+		redacted.Servers = redact.Servers(cfg.Servers)
+		redacted.Settings = redact.Settings(cfg.Settings)
+
+		if cfg.Projects != nil {
+			redacted.Projects = make(map[string]*config.ProjectConfig, len(cfg.Projects))
+			for name, p := range cfg.Projects {
+				redacted.Projects[name] = redact.Project(p)
+			}
+		}
+
+		if cfg.Auth != nil {
+			redacted.Auth = make(map[string]*config.OAuthConfig, len(cfg.Auth))
+			for name, profile := range cfg.Auth {
+				redacted.Auth[name] = redact.OAuth(profile)
+			}
+		}
+
+		cfg = &redacted
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(cfg); err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// printTrace writes the provenance of every server field and setting.
+func printTrace(w io.Writer, trace *config.MergeTrace) {
+	_, _ = fmt.Fprintln(w, "# Merge trace: source of each value (later sources override earlier ones)")
+
+	if trace.Profile != "" && trace.Profile != config.ProfileAll {
+		_, _ = fmt.Fprintf(w, "# profile %s: servers outside the group are left out\n", trace.Profile)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(trace.Servers)) {
+		_, _ = fmt.Fprintf(w, "# server %s (defined in %s)\n", name, trace.ServerOrigin(name))
+		printOrigins(w, config.ResolveTrace(trace.Servers[name]))
+	}
+
+	_, _ = fmt.Fprintln(w, "# settings")
+	printOrigins(w, config.ResolveTrace(trace.Settings))
+}
+
+// printOrigins writes one line per field as a YAML comment.
+func printOrigins(w io.Writer, origins []config.FieldOrigin) {
+	for _, o := range origins {
+		line := fmt.Sprintf("#   %-28s %s", o.Field, o.Source)
+		if len(o.Overrides) > 0 {
+			line += " (overrides " + strings.Join(o.Overrides, ", ") + ")"
+		}
+
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
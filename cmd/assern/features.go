@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func runFeaturesList(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	detector := instance.NewDetector(logger)
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return fmt.Errorf("detecting instance: %w", err)
+	}
+
+	var features []aggregator.FeatureState
+
+	if existing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+		defer cancel()
+
+		features, err = instance.QueryFeatures(ctx, existing.SocketPath)
+		if err != nil {
+			return fmt.Errorf("querying features: %w", err)
+		}
+	} else {
+		features, err = configuredFeatures()
+		if err != nil {
+			return err
+		}
+	}
+
+	if featuresJSON {
+		return printJSON(features)
+	}
+
+	if existing == nil {
+		fmt.Println("No running instance; showing the configured flags.")
+		fmt.Println()
+	}
+
+	printFeatures(features)
+
+	return nil
+}
+
+// configuredFeatures returns the feature flags the effective configuration
+// for the current directory would start an instance with.
+func configuredFeatures() ([]aggregator.FeatureState, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+
+	cfg, err := loadEffectiveConfig(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	agg, err := aggregator.New(aggregator.Options{Config: cfg, Logger: log.Logger()})
+	if err != nil {
+		return nil, err
+	}
+
+	return agg.Features(), nil
+}
+
+func printFeatures(features []aggregator.FeatureState) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "FEATURE\tSTATE\tSOURCE\tDESCRIPTION")
+
+	for _, f := range features {
+		state := "off"
+		if f.Enabled {
+			state = "on"
+		}
+
+		description := f.Description
+		if f.Restart {
+			description += " (config only)"
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Name, state, f.Source, description)
+	}
+
+	_ = w.Flush()
+}
+
+func runFeaturesEnable(cmd *cobra.Command, args []string) error {
+	return setFeature(args[0], true)
+}
+
+func runFeaturesDisable(cmd *cobra.Command, args []string) error {
+	return setFeature(args[0], false)
+}
+
+// setFeature flips a feature flag on the running instance.
+func setFeature(name string, enabled bool) error {
+	configureLogger()
+	logger := log.Logger()
+
+	if _, ok := config.LookupFeature(name); !ok {
+		return fmt.Errorf("unknown feature %q (see 'assern features list')", name)
+	}
+
+	detector := instance.NewDetector(logger)
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return fmt.Errorf("detecting instance: %w", err)
+	}
+
+	if existing == nil {
+		return errors.New("no running assern instance found (set the flag under settings.features in config.yaml instead)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+	defer cancel()
+
+	state, err := instance.SetFeature(ctx, existing.SocketPath, name, enabled)
+	if err != nil {
+		return err
+	}
+
+	verb := "disabled"
+	if state.Enabled {
+		verb = "enabled"
+	}
+
+	fmt.Printf("Feature %s %s until the instance exits\n", state.Name, verb)
+
+	return nil
+}
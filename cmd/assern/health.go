@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	var server string
+	if len(args) > 0 {
+		server = args[0]
+	}
+
+	detector := instance.NewDetector(logger)
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return fmt.Errorf("detecting instance: %w", err)
+	}
+
+	if existing == nil {
+		return errors.New("no running assern instance found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+	defer cancel()
+
+	if healthErrors {
+		recent, err := instance.QueryErrors(ctx, existing.SocketPath, server)
+		if err != nil {
+			return fmt.Errorf("querying errors: %w", err)
+		}
+
+		if healthJSON {
+			return printJSON(recent)
+		}
+
+		printRecentErrors(recent, server)
+
+		return nil
+	}
+
+	status, err := instance.QueryStatus(ctx, existing.SocketPath)
+	if err != nil {
+		return fmt.Errorf("querying status: %w", err)
+	}
+
+	servers := status.Servers
+	if server != "" {
+		servers = nil
+
+		for _, s := range status.Servers {
+			if s.Name == server {
+				servers = append(servers, s)
+			}
+		}
+
+		if len(servers) == 0 {
+			return fmt.Errorf("server %q is not configured", server)
+		}
+	}
+
+	if healthJSON {
+		return printJSON(servers)
+	}
+
+	printHealth(servers)
+
+	return nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// printHealth prints one line per server with its state, health and last
+// error.
+func printHealth(servers []aggregator.ServerStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "SERVER\tSTATE\tHEALTH\tLAST ERROR")
+
+	for _, s := range servers {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, serverState(s), s.Health, lastError(s))
+	}
+
+	_ = w.Flush()
+
+	printClockSkew(servers)
+
+	fmt.Println()
+	fmt.Println("Run 'assern health --errors' for the recent errors of each server.")
+}
+
+// printClockSkew warns about servers whose authorization server's clock
+// differs from the local one beyond their oauth.clock_skew.
+func printClockSkew(servers []aggregator.ServerStatus) {
+	for _, s := range servers {
+		if s.ClockSkewMS == 0 {
+			continue
+		}
+
+		skew := time.Duration(s.ClockSkewMS) * time.Millisecond
+
+		direction := "ahead of"
+		if skew < 0 {
+			direction, skew = "behind", -skew
+		}
+
+		fmt.Printf("\nWarning: %s: the authorization server's clock is %s %s the local clock.\n", s.Name, skew, direction)
+		fmt.Println("  Tokens may be rejected as expired; sync the system clock (NTP) or raise oauth.clock_skew.")
+	}
+}
+
+// printRecentErrors prints the recent errors of each server, newest first.
+func printRecentErrors(recent []aggregator.ServerErrors, server string) {
+	if len(recent) == 0 {
+		if server != "" {
+			fmt.Printf("No errors recorded for %s.\n", server)
+		} else {
+			fmt.Println("No errors recorded.")
+		}
+
+		return
+	}
+
+	for i, s := range recent {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%s:\n", s.Server)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		for j := len(s.Errors) - 1; j >= 0; j-- {
+			e := s.Errors[j]
+			_, _ = fmt.Fprintf(w, "  %s\t(%s ago)\t%s\t%s\n", e.Time.Local().Format(time.DateTime),
+				time.Since(e.Time).Round(time.Second), e.Operation, e.Message)
+		}
+
+		_ = w.Flush()
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func runInspect(_ *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	name := args[0]
+
+	var (
+		details *aggregator.ToolDetails
+		err     error
+	)
+
+	var existing *instance.Info
+	if !inspectFresh {
+		existing, _ = instance.NewDetector(logger).DetectRunning()
+	}
+
+	if existing != nil {
+		logger.Debug("inspecting tool on running instance", "pid", existing.PID, "socket", existing.SocketPath)
+
+		ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+		defer cancel()
+
+		details, err = instance.InspectTool(ctx, existing.SocketPath, name)
+	} else {
+		details, err = inspectOnDemand(name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if inspectJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(details); err != nil {
+			return fmt.Errorf("encoding tool: %w", err)
+		}
+
+		return nil
+	}
+
+	printToolDetails(details)
+
+	return nil
+}
+
+// inspectOnDemand starts the servers that can expose the tool and reads its
+// details from a temporary aggregator.
+func inspectOnDemand(name string) (*aggregator.ToolDetails, error) {
+	agg, stop, err := startAggregatorFor(name)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	details, err := agg.InspectTool(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w (see 'assern list')", err)
+	}
+
+	return details, nil
+}
+
+// printToolDetails prints the tool's names, server, description and
+// annotations, then its schemas as indented JSON.
+func printToolDetails(d *aggregator.ToolDetails) {
+	fmt.Printf("Tool:     %s\n", d.Name)
+	fmt.Printf("Original: %s\n", d.OriginalName)
+	fmt.Printf("Server:   %s\n", d.Server)
+
+	if d.Title != "" {
+		fmt.Printf("Title:    %s\n", d.Title)
+	}
+
+	if len(d.Aliases) > 0 {
+		fmt.Printf("Aliases:  %s\n", strings.Join(d.Aliases, ", "))
+	}
+
+	if hints := annotationHints(d.Annotations); len(hints) > 0 {
+		fmt.Printf("Hints:    %s\n", strings.Join(hints, ", "))
+	}
+
+	if d.Description != "" {
+		fmt.Println()
+		fmt.Println(strings.TrimSpace(d.Description))
+	}
+
+	fmt.Println()
+	fmt.Println("Input schema:")
+	fmt.Println(indentJSON(d.InputSchema))
+
+	if len(d.OutputSchema) > 0 {
+		fmt.Println()
+		fmt.Println("Output schema:")
+		fmt.Println(indentJSON(d.OutputSchema))
+	}
+}
+
+// annotationHints renders the behaviour hints a tool sets, e.g.
+// "read-only" or "not destructive".
+func annotationHints(a *mcp.ToolAnnotation) []string {
+	if a == nil {
+		return nil
+	}
+
+	var hints []string
+
+	add := func(hint *bool, yes, no string) {
+		switch {
+		case hint == nil:
+		case *hint:
+			hints = append(hints, yes)
+		default:
+			hints = append(hints, no)
+		}
+	}
+
+	add(a.ReadOnlyHint, "read-only", "writes")
+	add(a.DestructiveHint, "destructive", "not destructive")
+	add(a.IdempotentHint, "idempotent", "not idempotent")
+	add(a.OpenWorldHint, "open world", "closed world")
+
+	if a.Title != "" {
+		hints = append(hints, fmt.Sprintf("title %q", a.Title))
+	}
+
+	return hints
+}
+
+// indentJSON indents a JSON document, or returns it as is when invalid.
+func indentJSON(data json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAnnotationHints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		annotations *mcp.ToolAnnotation
+		want        []string
+	}{
+		{name: "none", annotations: nil, want: nil},
+		{name: "unset hints", annotations: &mcp.ToolAnnotation{}, want: nil},
+		{
+			name: "set hints",
+			annotations: &mcp.ToolAnnotation{
+				Title:           "Search",
+				ReadOnlyHint:    mcp.ToBoolPtr(true),
+				DestructiveHint: mcp.ToBoolPtr(false),
+				OpenWorldHint:   mcp.ToBoolPtr(true),
+			},
+			want: []string{"read-only", "not destructive", "open world", `title "Search"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := annotationHints(tt.annotations); !slices.Equal(got, tt.want) {
+				t.Errorf("annotationHints() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
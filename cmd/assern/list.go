@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/valksor/go-assern/internal/aggregator"
 	"github.com/valksor/go-assern/internal/config"
 	"github.com/valksor/go-assern/internal/instance"
 	"github.com/valksor/go-assern/internal/log"
@@ -26,8 +31,22 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting working directory: %w", err)
 	}
 
+	// An ephemeral configuration never matches a running instance
+	if configStdin {
+		cfg, _, err := readStdinConfig()
+		if err != nil {
+			return err
+		}
+
+		if err := checkListServer(config.GetEffectiveServers(cfg)); err != nil {
+			return err
+		}
+
+		return runListFresh(cfg, cfg, cwd, logger)
+	}
+
 	// Load effective configuration (merges global + local configs)
-	cfg, err := config.LoadEffective(cwd, projectFlag)
+	cfg, err := loadEffectiveConfig(cwd)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -35,6 +54,10 @@ func runList(cmd *cobra.Command, args []string) error {
 	// Check if any servers are configured
 	effectiveServers := config.GetEffectiveServers(cfg)
 	if len(effectiveServers) == 0 {
+		if listJSON || listYAML {
+			return writeList(&instance.ListResult{Tools: []instance.ToolInfo{}, TokensByServer: map[string]int{}})
+		}
+
 		fmt.Println("No MCP servers configured.")
 		fmt.Println()
 		fmt.Println("Add servers to:")
@@ -46,9 +69,17 @@ func runList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if err := checkListServer(effectiveServers); err != nil {
+		return err
+	}
+
 	// Try to query from running instance (unless --fresh flag is set)
 	if !freshList {
-		if result := tryListFromInstance(logger); result != nil {
+		if result, socketPath := tryListFromInstance(logger); result != nil {
+			if listJSON || listYAML {
+				return writeList(result)
+			}
+
 			// Print results from running instance
 			projectName := "(none)"
 			if projectCtx := detectProjectContext(cfg, cwd, logger); projectCtx != nil && projectCtx.Name != "" {
@@ -61,37 +92,70 @@ func runList(cmd *cobra.Command, args []string) error {
 			fmt.Println("(from running instance)")
 			fmt.Println()
 
-			fmt.Println("Tools:")
+			if verbose {
+				ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+				status, err := instance.QueryStatus(ctx, socketPath)
+				cancel()
 
-			for _, tool := range result.Tools {
-				fmt.Printf("  - %s (%s)\n", tool.Name, tool.Description)
+				if err != nil {
+					logger.Debug("failed to query status from instance", "error", err)
+				} else {
+					printServerReport(status)
+				}
 			}
 
-			printTokenSummary(result.TokensByServer, result.TotalTokens, len(result.Tools))
+			printToolRules(listedServers(effectiveServers))
+			printAliases(cfg.Settings, nil)
+			printList(result)
 
 			return nil
 		}
 	}
 
 	// Fall back to fresh discovery
-	return runListFresh(cfg, cwd, logger)
+	return runListFresh(cfg, nil, cwd, logger)
+}
+
+// checkListServer fails when --server names a server that is not among the
+// configured servers.
+func checkListServer(servers map[string]*config.ServerConfig) error {
+	if listServer == "" {
+		return nil
+	}
+
+	if _, ok := servers[listServer]; !ok {
+		return fmt.Errorf("server %q is not configured (see 'assern mcp list')", listServer)
+	}
+
+	return nil
 }
 
-// tryListFromInstance attempts to query tools from a running instance.
-// Returns nil if no instance is running or query fails.
-func tryListFromInstance(logger *slog.Logger) *instance.ListResult {
+// listedServers returns servers, or only the --server one.
+func listedServers(servers map[string]*config.ServerConfig) map[string]*config.ServerConfig {
+	if listServer == "" {
+		return servers
+	}
+
+	return map[string]*config.ServerConfig{listServer: servers[listServer]}
+}
+
+// tryListFromInstance attempts to query the tools, resources and prompts of
+// a running instance (only those of --server when set) and returns them with
+// the instance's socket path. The result is nil if no instance is running or
+// the query fails.
+func tryListFromInstance(logger *slog.Logger) (*instance.ListResult, string) {
 	detector := instance.NewDetector(logger)
 	existing, err := detector.DetectRunning()
 	if err != nil {
 		logger.Debug("instance detection failed", "error", err)
 
-		return nil
+		return nil, ""
 	}
 
 	if existing == nil {
 		logger.Debug("no running instance found")
 
-		return nil
+		return nil, ""
 	}
 
 	logger.Debug(
@@ -103,47 +167,35 @@ func tryListFromInstance(logger *slog.Logger) *instance.ListResult {
 	ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
 	defer cancel()
 
-	result, err := instance.QueryTools(ctx, existing.SocketPath)
+	result, err := instance.QueryList(ctx, existing.SocketPath, listServer)
 	if err != nil {
 		logger.Debug("failed to query tools from instance", "error", err)
 
-		return nil
+		return nil, ""
 	}
 
-	return result
+	return result, existing.SocketPath
 }
 
 func runReload(cmd *cobra.Command, args []string) error {
 	configureLogger()
-	logger := log.Logger()
 
-	// Check for running instance
-	detector := instance.NewDetector(logger)
-	existing, err := detector.DetectRunning()
+	result, err := reloadRunning(reloadBlueGreen)
 	if err != nil {
-		return fmt.Errorf("detecting instance: %w", err)
-	}
-
-	if existing == nil {
-		return errors.New("no running assern instance found")
-	}
-
-	// Send reload command
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	result, err := instance.Reload(ctx, existing.SocketPath)
-	if err != nil {
-		return fmt.Errorf("reload failed: %w", err)
+		return err
 	}
 
 	// Print results
 	fmt.Printf("Configuration reloaded successfully\n")
-	fmt.Printf("  Added:   %d servers\n", result.Added)
-	fmt.Printf("  Removed: %d servers\n", result.Removed)
+	fmt.Printf("  Added:    %d servers\n", result.Added)
+	fmt.Printf("  Removed:  %d servers\n", result.Removed)
+
+	if reloadBlueGreen {
+		fmt.Printf("  Replaced: %d servers\n", result.Replaced)
+	}
 
 	if len(result.Errors) > 0 {
-		fmt.Printf("  Errors:  %d\n", len(result.Errors))
+		fmt.Printf("  Errors:   %d\n", len(result.Errors))
 		for _, e := range result.Errors {
 			fmt.Printf("    - %s\n", e)
 		}
@@ -152,12 +204,54 @@ func runReload(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runListFresh(cfg *config.Config, cwd string, logger *slog.Logger) error {
+// errNoInstance is returned by commands that need a running instance.
+var errNoInstance = errors.New("no running assern instance found")
+
+// reloadRunning reloads the configuration of the running instance; a
+// blue-green reload also waits for the new servers to start.
+func reloadRunning(blueGreen bool) (*instance.ReloadResult, error) {
+	detector := instance.NewDetector(log.Logger())
+
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return nil, fmt.Errorf("detecting instance: %w", err)
+	}
+
+	if existing == nil {
+		return nil, errNoInstance
+	}
+
+	reload, timeout := instance.Reload, 30*time.Second
+	if blueGreen {
+		reload, timeout = instance.ReloadBlueGreen, 2*time.Minute
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := reload(ctx, existing.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("reload failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// runListFresh starts the configured servers (only --server when set) to
+// list their tools. fixed is the --config-stdin configuration, or nil to load
+// the configuration files.
+func runListFresh(cfg, fixed *config.Config, cwd string, logger *slog.Logger) error {
+	var only []string
+	if listServer != "" {
+		only = []string{listServer}
+	}
+
 	// Use helper to create aggregator
-	agg, ctx, logger, err := setupAggregator()
+	agg, ctx, logger, err := setupAggregator(false, fixed, only)
 	if err != nil {
 		return err
 	}
+	defer agg.Events().Close()
 	defer func() {
 		if cancel, ok := ctx.Value(cancelKey).(context.CancelFunc); ok {
 			cancel()
@@ -175,9 +269,20 @@ func runListFresh(cfg *config.Config, cwd string, logger *slog.Logger) error {
 		}
 	}()
 
+	result, err := instance.NewListResult(agg, listServer)
+	if err != nil {
+		return fmt.Errorf("listing tools: %w", err)
+	}
+
+	if listJSON || listYAML {
+		return writeList(result)
+	}
+
 	// Print results
 	projectName := "(none)"
-	if projectCtx := detectProjectContext(cfg, cwd, logger); projectCtx != nil && projectCtx.Name != "" {
+	if fixed != nil {
+		projectName = "(none, configuration from stdin)"
+	} else if projectCtx := detectProjectContext(cfg, cwd, logger); projectCtx != nil && projectCtx.Name != "" {
 		projectName = projectCtx.Name
 		if projectCtx.Source == project.SourceAutoDetect {
 			projectName = projectName + " (auto-detected)"
@@ -185,26 +290,122 @@ func runListFresh(cfg *config.Config, cwd string, logger *slog.Logger) error {
 	}
 	fmt.Printf("Project: %s\n\n", projectName)
 
-	fmt.Println("Servers:")
+	if verbose {
+		status := agg.Status()
+		printServerReport(&status)
+	} else {
+		fmt.Println("Servers:")
+
+		for _, name := range agg.ServerNames() {
+			fmt.Printf("  - %s\n", name)
+		}
+
+		fmt.Println()
+	}
+
+	printToolRules(listedServers(config.GetEffectiveServers(cfg)))
 
-	for _, name := range agg.ServerNames() {
-		fmt.Printf("  - %s\n", name)
+	known := make(map[string]bool, len(result.Tools))
+	for _, tool := range result.Tools {
+		known[tool.Name] = true
 	}
 
-	tools := agg.ListTools()
-	byServer, totalTokens := agg.TokenStats()
+	printAliases(cfg.Settings, known)
+	printList(result)
+
+	return nil
+}
+
+// printServerReport prints one line per server with its state, transport,
+// startup latency and endpoint (--verbose), so slow backends stand out.
+func printServerReport(status *aggregator.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "  SERVER\tSTATE\tTRANSPORT\tINITIALIZE\tTOOLS/LIST\tTOOLS\tENDPOINT")
+
+	for _, s := range status.Servers {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			s.Name, s.State, s.Transport, formatMillis(s.InitializeMS), formatMillis(s.ListToolsMS), s.Tools, s.Endpoint)
+	}
+
+	_ = w.Flush()
 
 	fmt.Println()
-	fmt.Println("Tools:")
+}
 
-	for _, tool := range tools {
-		summary := tool.Summarize()
-		fmt.Printf("  - %s (%s)\n", summary.PrefixedName, summary.Description)
+// printToolRules prints the effective allowed and denied tool patterns of
+// each server that restricts its tools. Nothing is printed when none do.
+func printToolRules(servers map[string]*config.ServerConfig) {
+	names := make([]string, 0, len(servers))
+	for name, srv := range servers {
+		if srv.ToolRules() != nil {
+			names = append(names, name)
+		}
 	}
 
-	printTokenSummary(byServer, totalTokens, len(tools))
+	if len(names) == 0 {
+		return
+	}
 
-	return nil
+	sort.Strings(names)
+
+	fmt.Println("Tool rules:")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for _, name := range names {
+		rules := servers[name].ToolRules()
+		_, _ = fmt.Fprintf(w, "  %s\tallowed: %s\tdenied: %s\n", name, formatPatterns(rules.Allowed, "*"), formatPatterns(rules.Denied, "-"))
+	}
+
+	_ = w.Flush()
+
+	fmt.Println()
+}
+
+// printAliases prints the configured tool aliases and their targets. With
+// known set, targets not in it are marked. Nothing is printed when there are
+// no aliases.
+func printAliases(settings *config.Settings, known map[string]bool) {
+	if settings == nil || len(settings.Aliases) == 0 {
+		return
+	}
+
+	fmt.Println("Aliases:")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for _, alias := range slices.Sorted(maps.Keys(settings.Aliases)) {
+		target := settings.Aliases[alias]
+		if known != nil && !known[target] {
+			target += " (not found)"
+		}
+
+		_, _ = fmt.Fprintf(w, "  %s\t-> %s\n", alias, target)
+	}
+
+	_ = w.Flush()
+
+	fmt.Println()
+}
+
+// formatPatterns joins tool patterns for display, or returns empty when
+// there are none.
+func formatPatterns(patterns []string, empty string) string {
+	if len(patterns) == 0 {
+		return empty
+	}
+
+	return strings.Join(patterns, ", ")
+}
+
+// formatMillis renders a latency in milliseconds, or "-" if not measured.
+func formatMillis(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+
+	return (time.Duration(ms) * time.Millisecond).String()
 }
 
 // formatTokens renders an estimated token count compactly (e.g. "~3.4k").
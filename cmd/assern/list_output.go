@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/valksor/go-assern/internal/instance"
+)
+
+// printList prints the tools of result, with their input schemas for
+// --schema, then its resources and prompts when asked for, and the token
+// summary.
+func printList(result *instance.ListResult) {
+	fmt.Println("Tools:")
+
+	for _, tool := range result.Tools {
+		fmt.Printf("  - %s (%s)\n", tool.Name, tool.Description)
+
+		if listSchema && len(tool.InputSchema) > 0 {
+			fmt.Println(indentLines(indentJSON(tool.InputSchema), "      "))
+		}
+	}
+
+	if listResources {
+		fmt.Println()
+		fmt.Println("Resources:")
+
+		for _, resource := range result.Resources {
+			fmt.Printf("  - %s (%s)\n", resource.URI, describeResource(resource))
+		}
+	}
+
+	if listPrompts {
+		fmt.Println()
+		fmt.Println("Prompts:")
+
+		for _, prompt := range result.Prompts {
+			fmt.Printf("  - %s%s (%s)\n", prompt.Name, formatPromptArguments(prompt.Arguments), prompt.Description)
+		}
+	}
+
+	printTokenSummary(result.TokensByServer, result.TotalTokens, len(result.Tools))
+}
+
+// writeList prints result as JSON (--json) or YAML (--yaml), leaving out
+// input schemas without --schema and resources and prompts unless asked for.
+func writeList(result *instance.ListResult) error {
+	out := *result
+
+	if !listSchema {
+		out.Tools = make([]instance.ToolInfo, len(result.Tools))
+		for i, tool := range result.Tools {
+			tool.InputSchema = nil
+			out.Tools[i] = tool
+		}
+	}
+
+	if !listResources {
+		out.Resources = nil
+	}
+
+	if !listPrompts {
+		out.Prompts = nil
+	}
+
+	if listJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("encoding list: %w", err)
+		}
+
+		return nil
+	}
+
+	// Go through JSON so the field names and the schemas come out the same
+	data, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("encoding list: %w", err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("encoding list: %w", err)
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding list: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// describeResource renders the name and MIME type of a resource.
+func describeResource(r instance.ResourceInfo) string {
+	if r.MIMEType == "" {
+		return r.Name
+	}
+
+	return r.Name + ", " + r.MIMEType
+}
+
+// formatPromptArguments renders the arguments of a prompt as "(a, b?)",
+// marking the optional ones, or returns empty when there are none.
+func formatPromptArguments(args []mcp.PromptArgument) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.Name
+		if !arg.Required {
+			names[i] += "?"
+		}
+	}
+
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// indentLines prefixes every line of s with indent.
+func indentLines(s, indent string) string {
+	return indent + strings.ReplaceAll(s, "\n", "\n"+indent)
+}
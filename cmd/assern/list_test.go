@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFormatPromptArguments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []mcp.PromptArgument
+		want string
+	}{
+		{name: "none", want: ""},
+		{name: "required", args: []mcp.PromptArgument{{Name: "pr", Required: true}}, want: "(pr)"},
+		{
+			name: "optional marked",
+			args: []mcp.PromptArgument{{Name: "pr", Required: true}, {Name: "base"}},
+			want: "(pr, base?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := formatPromptArguments(tt.args); got != tt.want {
+				t.Errorf("formatPromptArguments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndentLines(t *testing.T) {
+	t.Parallel()
+
+	if got := indentLines("{\n  \"type\": \"object\"\n}", "    "); got != "    {\n      \"type\": \"object\"\n    }" {
+		t.Errorf("indentLines() = %q", got)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	if len(args) > 0 && logsServer != "" {
+		return errors.New("give either a server argument (its stderr) or --server (the instance log about it), not both")
+	}
+
+	detector := instance.NewDetector(logger)
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return fmt.Errorf("detecting instance: %w", err)
+	}
+
+	if existing == nil {
+		return errors.New("no running assern instance found")
+	}
+
+	params := instance.LogsParams{Server: logsServer, Lines: logsLines}
+	if logsSince > 0 {
+		params.Since = time.Now().Add(-logsSince)
+	}
+
+	if len(args) > 0 {
+		params.Server = args[0]
+
+		return showStderr(cmd, existing.SocketPath, params)
+	}
+
+	if logsFollow {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := instance.FollowLogs(ctx, existing.SocketPath, params, printLogRecord); err != nil {
+			return fmt.Errorf("following logs: %w", err)
+		}
+
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), instance.ClientTimeout)
+	defer cancel()
+
+	records, err := instance.QueryLogs(ctx, existing.SocketPath, params)
+	if err != nil {
+		return fmt.Errorf("querying logs: %w", err)
+	}
+
+	if len(records) == 0 && !logsJSON {
+		fmt.Fprintln(os.Stderr, "No log records.")
+
+		return nil
+	}
+
+	for _, record := range records {
+		if err := printLogRecord(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// showStderr prints what params.Server wrote to stderr.
+func showStderr(cmd *cobra.Command, socketPath string, params instance.LogsParams) error {
+	if logsFollow {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := instance.FollowStderr(ctx, socketPath, params, printLogLine); err != nil {
+			return fmt.Errorf("following logs: %w", err)
+		}
+
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), instance.ClientTimeout)
+	defer cancel()
+
+	lines, err := instance.QueryStderr(ctx, socketPath, params)
+	if err != nil {
+		return fmt.Errorf("querying logs: %w", err)
+	}
+
+	if len(lines) == 0 && !logsJSON {
+		fmt.Fprintf(os.Stderr, "No stderr output recorded for %s.\n", params.Server)
+
+		return nil
+	}
+
+	for _, line := range lines {
+		if err := printLogLine(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printLogRecord prints one log record like the text log, or as JSON with
+// --json.
+func printLogRecord(record log.Record) error {
+	if logsJSON {
+		return json.NewEncoder(os.Stdout).Encode(record)
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s  %-5s %s", record.Time.Local().Format(time.DateTime), record.Level, record.Message)
+
+	for _, key := range slices.Sorted(maps.Keys(record.Attrs)) {
+		fmt.Fprintf(&sb, " %s=%s", key, quoteLogValue(record.Attrs[key]))
+	}
+
+	_, err := fmt.Println(sb.String())
+
+	return err
+}
+
+// quoteLogValue quotes values with spaces, as the text log does.
+func quoteLogValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		return fmt.Sprintf("%q", v)
+	}
+
+	return v
+}
+
+// printLogLine prints one stderr line with its time, or as JSON with --json.
+func printLogLine(line aggregator.LogLine) error {
+	if logsJSON {
+		return json.NewEncoder(os.Stdout).Encode(line)
+	}
+
+	_, err := fmt.Printf("%s  %s\n", line.Time.Local().Format(time.DateTime), line.Line)
+
+	return err
+}
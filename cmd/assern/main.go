@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
+	"github.com/valksor/go-assern/internal/cli"
 	"github.com/valksor/go-assern/internal/cobracli"
+	"github.com/valksor/go-assern/internal/compat"
 	"github.com/valksor/go-assern/internal/disambiguate"
 )
 
@@ -15,15 +19,123 @@ var (
 	// Global flags.
 	verbose      bool
 	quiet        bool
+	silent       bool
 	projectFlag  string
+	profileFlag  string
 	configPath   string
 	outputFormat string // "json" or "toon"
+	strictConfig bool
+
+	// serve flags.
+	serveHTTP string
+
+	// serve and list flags.
+	configStdin bool
 
 	// config init flags.
 	forceInit bool
 
+	// config edit flags.
+	editMCP   bool
+	editLocal bool
+
+	// config show flags.
+	showEffective bool
+	showTrace     bool
+
+	// config show and mcp list flags.
+	revealSecrets bool
+	revealYes     bool
+
+	// mcp add, edit and delete flags.
+	mcpFlags cli.MCPFlags
+	mcpYes   bool
+
+	// mcp enable and disable flags.
+	mcpAll    bool
+	mcpReload bool
+
 	// list flags.
-	freshList bool
+	freshList     bool
+	listResources bool
+	listPrompts   bool
+	listServer    string
+	listSchema    bool
+	listJSON      bool
+	listYAML      bool
+
+	// status flags.
+	statusJSON bool
+
+	// stats flags.
+	statsJSON bool
+
+	// health flags.
+	healthErrors bool
+	healthJSON   bool
+
+	// features flags.
+	featuresJSON bool
+
+	// logs flags.
+	logsLines  int
+	logsFollow bool
+	logsJSON   bool
+	logsServer string
+	logsSince  time.Duration
+
+	// audit flags.
+	auditFile   string
+	auditLines  int
+	auditFollow bool
+	auditSince  time.Duration
+	auditJSON   bool
+
+	// mcp import flags.
+	importFrom      string
+	importScope     string
+	importOverwrite bool
+	importYes       bool
+	importDryRun    bool
+
+	// mcp export flags.
+	exportTo      string
+	exportAll     bool
+	exportCommand string
+	exportOutput  string
+	exportForce   bool
+
+	// bundle flags.
+	bundleOS       string
+	bundleArch     string
+	bundleOutput   string
+	bundleBinary   string
+	bundleRegistry bool
+	bundleForce    bool
+
+	// call flags.
+	callArgs    []string
+	callJSON    string
+	callFresh   bool
+	callRaw     bool
+	callTimeout time.Duration
+
+	// inspect flags.
+	inspectJSON  bool
+	inspectFresh bool
+
+	// repl flags.
+	replFresh   bool
+	replTimeout time.Duration
+
+	// reload flags.
+	reloadBlueGreen bool
+
+	// compat test flags.
+	compatNetwork bool
+	compatOnly    []string
+	compatJSON    bool
+	compatTimeout time.Duration
 )
 
 // contextKey is the type used for context keys to prevent collisions.
@@ -68,7 +180,10 @@ func Execute() error {
 
 func main() {
 	if err := Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// The exit status is all a --silent run reports
+		if !silent {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 }
@@ -77,29 +192,183 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress progress and info messages")
+	rootCmd.PersistentFlags().BoolVar(&silent, "silent", false, "Write nothing to stdout or stderr but protocol traffic, not even errors (implies --quiet)")
 	rootCmd.PersistentFlags().StringVar(&projectFlag, "project", "", "Explicit project name (overrides auto-detection)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Only use the servers of this group from config.yaml, or \"all\" (default: $ASSERN_PROFILE, else the project's profile)")
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to config.yaml (default: ~/.valksor/assern/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&outputFormat, "output-format", "", "Output format for tool results: json or toon")
+	rootCmd.PersistentFlags().BoolVar(&strictConfig, "strict-config", false, "Fail on unknown keys in configuration files (like settings.strict)")
 
 	// Add commands
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(callCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(replCmd)
 	rootCmd.AddCommand(reloadCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(featuresCmd)
+	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(secretCmd)
+	rootCmd.AddCommand(compatCmd)
 	rootCmd.AddCommand(cobracli.NewVersionCommand("assern"))
 
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configSchemaCmd)
 
 	mcpCmd.AddCommand(mcpAddCmd)
 	mcpCmd.AddCommand(mcpEditCmd)
 	mcpCmd.AddCommand(mcpDeleteCmd)
+	mcpCmd.AddCommand(mcpRestoreCmd)
+	mcpCmd.AddCommand(mcpEnableCmd)
+	mcpCmd.AddCommand(mcpDisableCmd)
 	mcpCmd.AddCommand(mcpListCmd)
+	mcpCmd.AddCommand(mcpImportCmd)
+	mcpCmd.AddCommand(mcpExportCmd)
+
+	featuresCmd.AddCommand(featuresListCmd)
+	featuresCmd.AddCommand(featuresEnableCmd)
+	featuresCmd.AddCommand(featuresDisableCmd)
+
+	auditCmd.AddCommand(auditTailCmd)
+	auditCmd.AddCommand(auditStatsCmd)
+
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretDeleteCmd)
+
+	compatCmd.AddCommand(compatTestCmd)
+
+	// serve flags
+	serveCmd.Flags().StringVar(&serveHTTP, "http", "", "Also serve over Streamable HTTP and SSE on this address (e.g. :8080)")
+	serveCmd.Flags().BoolVar(&configStdin, "config-stdin", false, "Read an mcp.json document from stdin instead of configuration files")
+
+	// status flags
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the raw status as JSON")
+
+	// stats flags
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the raw statistics as JSON")
+
+	// health flags
+	healthCmd.Flags().BoolVar(&healthErrors, "errors", false, "Show the recent errors of each server")
+	healthCmd.Flags().BoolVar(&healthJSON, "json", false, "Print the raw result as JSON")
+
+	// logs flags
+	logsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "Number of lines to print first (0: all kept lines)")
+	logsCmd.Flags().StringVar(&logsServer, "server", "", "Only print the log records about this server")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Only print what was logged in this last duration (e.g. 5m)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new lines until interrupted")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "Print each line as JSON")
+
+	// features flags
+	featuresListCmd.Flags().BoolVar(&featuresJSON, "json", false, "Print the flags as JSON")
+
+	// audit flags
+	auditCmd.PersistentFlags().StringVar(&auditFile, "file", "", "Audit log to read (default: settings.audit_log.path)")
+	auditCmd.PersistentFlags().BoolVar(&auditJSON, "json", false, "Print records or the summary as JSON")
+	auditTailCmd.Flags().IntVarP(&auditLines, "lines", "n", 20, "Number of records to print")
+	auditTailCmd.Flags().BoolVarP(&auditFollow, "follow", "f", false, "Keep printing new records until interrupted")
+	auditStatsCmd.Flags().DurationVar(&auditSince, "since", 0, "Only count records newer than this, e.g. 24h")
+
+	// mcp import flags
+	mcpImportCmd.Flags().StringVar(&importFrom, "from", "", "Client to import from: claude, cursor, vscode or windsurf")
+	mcpImportCmd.Flags().StringVar(&importScope, "scope", "", "Where to write the servers: global or project (prompted when omitted)")
+	mcpImportCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "Replace existing servers of the same name in the target scope")
+	mcpImportCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Do not prompt; skip name collisions unless --overwrite is set")
+	mcpImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without writing")
+	_ = mcpImportCmd.MarkFlagRequired("from")
+
+	// mcp export flags
+	mcpExportCmd.Flags().StringVar(&exportTo, "to", "", "Client format to write: claude, cursor, vscode or windsurf")
+	mcpExportCmd.Flags().BoolVar(&exportAll, "all", false, "Export every configured backend instead of an entry for assern itself")
+	mcpExportCmd.Flags().StringVar(&exportCommand, "command", "assern", "Command the client runs to start assern")
+	mcpExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to this file instead of stdout")
+	mcpExportCmd.Flags().BoolVarP(&exportForce, "force", "f", false, "Replace an existing output file")
+	_ = mcpExportCmd.MarkFlagRequired("to")
+
+	// bundle flags
+	bundleCmd.Flags().StringVar(&bundleOS, "os", runtime.GOOS, "Target operating system")
+	bundleCmd.Flags().StringVar(&bundleArch, "arch", runtime.GOARCH, "Target architecture")
+	bundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", ".", "Directory to create the bundle in")
+	bundleCmd.Flags().StringVar(&bundleBinary, "binary", "", "assern binary to package (default: this binary; required for other platforms)")
+	bundleCmd.Flags().BoolVar(&bundleRegistry, "registry", false, "Include the global mcp.json and config.yaml with their secrets redacted")
+	bundleCmd.Flags().BoolVarP(&bundleForce, "force", "f", false, "Replace an existing bundle directory")
+
+	// call flags
+	callCmd.Flags().StringArrayVar(&callArgs, "arg", nil, "Tool argument as key=value (repeatable; JSON values for non-string properties)")
+	callCmd.Flags().StringVar(&callJSON, "json", "", "Tool arguments as a JSON object; --arg values override its keys")
+	callCmd.Flags().BoolVarP(&callFresh, "fresh", "f", false, "Start the backend even when an instance is running")
+	callCmd.Flags().BoolVar(&callRaw, "raw", false, "Print the whole result as JSON")
+	callCmd.Flags().DurationVar(&callTimeout, "timeout", 2*time.Minute, "Time limit for the call")
+
+	// inspect flags
+	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Print the tool details as JSON")
+	inspectCmd.Flags().BoolVarP(&inspectFresh, "fresh", "f", false, "Start the backend even when an instance is running")
+
+	// repl flags
+	replCmd.Flags().BoolVarP(&replFresh, "fresh", "f", false, "Start the servers even when an instance is running")
+	replCmd.Flags().DurationVar(&replTimeout, "timeout", 2*time.Minute, "Time limit for each request")
+
+	// reload flags
+	reloadCmd.Flags().BoolVar(&reloadBlueGreen, "blue-green", false, "Start changed servers alongside the running ones and swap them in once all are up")
+
+	// compat test flags
+	compatTestCmd.Flags().BoolVar(&compatNetwork, "network", false, "Allow downloading and running the servers (required)")
+	compatTestCmd.Flags().StringSliceVar(&compatOnly, "only", nil, "Only check these targets, e.g. --only filesystem,fetch")
+	compatTestCmd.Flags().BoolVar(&compatJSON, "json", false, "Print the report as JSON")
+	compatTestCmd.Flags().DurationVar(&compatTimeout, "timeout", compat.DefaultTimeout, "Time limit per target, including the download")
 
 	// config init flags
 	configInitCmd.Flags().BoolVarP(&forceInit, "force", "f", false, "Overwrite existing configuration files")
 
+	// config edit flags
+	configEditCmd.Flags().BoolVar(&editMCP, "mcp", false, "Edit mcp.json instead of config.yaml")
+	configEditCmd.Flags().BoolVar(&editLocal, "local", false, "Edit the files in the nearest .assern directory")
+
+	// config show flags
+	configShowCmd.Flags().BoolVar(&showEffective, "effective", false, "Show the merged configuration for the current directory and project")
+	configShowCmd.Flags().BoolVar(&showTrace, "trace", false, "Show which source contributed each server field and setting (implies --effective)")
+	configShowCmd.Flags().BoolVar(&revealSecrets, "reveal", false, "Print secrets (env values, credential headers, tokens) instead of <redacted>")
+	configShowCmd.Flags().BoolVarP(&revealYes, "yes", "y", false, "Reveal without asking for confirmation")
+
+	// mcp add and edit flags
+	addMCPServerFlags(mcpAddCmd)
+	addMCPServerFlags(mcpEditCmd)
+	mcpAddCmd.Flags().StringVar(&mcpFlags.Scope, "scope", "", "Where to add the server: global or project (.assern/mcp.json; default: global, or project with --project)")
+	mcpEditCmd.Flags().StringArrayVar(&mcpFlags.UnsetEnv, "unset-env", nil, "Remove an environment variable (repeatable)")
+	mcpEditCmd.Flags().StringArrayVar(&mcpFlags.UnsetHeaders, "unset-header", nil, "Remove an HTTP header (repeatable)")
+
+	// mcp delete flags
+	mcpDeleteCmd.Flags().BoolVarP(&mcpYes, "yes", "y", false, "Delete without asking for confirmation")
+
+	// mcp enable and disable flags
+	mcpEnableCmd.Flags().BoolVar(&mcpAll, "all", false, "Enable every configured server")
+	mcpEnableCmd.Flags().BoolVar(&mcpReload, "reload", false, "Reload the running instance afterwards")
+	mcpDisableCmd.Flags().BoolVar(&mcpAll, "all", false, "Disable every configured server")
+	mcpDisableCmd.Flags().BoolVar(&mcpReload, "reload", false, "Reload the running instance afterwards")
+
+	// mcp list flags
+	mcpListCmd.Flags().BoolVar(&revealSecrets, "reveal", false, "Print secrets in commands and URLs instead of <redacted>")
+	mcpListCmd.Flags().BoolVarP(&revealYes, "yes", "y", false, "Reveal without asking for confirmation")
+
 	// list flags
 	listCmd.Flags().BoolVarP(&freshList, "fresh", "f", false, "Force fresh discovery (ignore running instance)")
+	listCmd.Flags().BoolVar(&configStdin, "config-stdin", false, "Read an mcp.json document from stdin instead of configuration files (implies --fresh)")
+	listCmd.Flags().BoolVar(&listResources, "resources", false, "Also list the resources of the servers")
+	listCmd.Flags().BoolVar(&listPrompts, "prompts", false, "Also list the prompts of the servers")
+	listCmd.Flags().StringVar(&listServer, "server", "", "Only list the tools, resources and prompts of this server")
+	listCmd.Flags().BoolVar(&listSchema, "schema", false, "Include the input schema of each tool")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print the list as JSON")
+	listCmd.Flags().BoolVar(&listYAML, "yaml", false, "Print the list as YAML")
+	listCmd.MarkFlagsMutuallyExclusive("json", "yaml")
 }
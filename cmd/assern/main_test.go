@@ -2,12 +2,14 @@ package main
 
 import (
 	"bytes"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/valksor/go-assern/internal/aggregator"
 	"github.com/valksor/go-assern/internal/cobracli"
 	"github.com/valksor/go-assern/internal/config"
 	"github.com/valksor/go-assern/internal/log"
@@ -140,7 +142,7 @@ func TestCommandsRegistered(t *testing.T) {
 		commandNames[cmd.Name()] = true
 	}
 
-	expectedCommands := []string{"serve", "list", "config", "version"}
+	expectedCommands := []string{"serve", "list", "repl", "status", "stats", "health", "features", "audit", "config", "bundle", "version"}
 	for _, name := range expectedCommands {
 		if !commandNames[name] {
 			t.Errorf("Command '%s' not registered", name)
@@ -149,8 +151,8 @@ func TestCommandsRegistered(t *testing.T) {
 
 	// Check config subcommands
 	configSubcommands := configCmd.Commands()
-	if len(configSubcommands) != 2 {
-		t.Errorf("configCmd has %d subcommands, want 2", len(configSubcommands))
+	if len(configSubcommands) != 5 {
+		t.Errorf("configCmd has %d subcommands, want 5", len(configSubcommands))
 	}
 }
 
@@ -162,7 +164,7 @@ func TestGlobalFlags(t *testing.T) {
 	}
 
 	// Check that flags are defined
-	flags := []string{"verbose", "quiet", "project", "config"}
+	flags := []string{"verbose", "quiet", "silent", "project", "config"}
 	for _, flag := range flags {
 		f := rootCmd.PersistentFlags().Lookup(flag)
 		if f == nil {
@@ -207,6 +209,31 @@ func TestConfigureLogger(t *testing.T) {
 	})
 }
 
+func TestLogOutput(t *testing.T) {
+	// Not parallel - modifies global quiet/silent flags
+	defer func() { quiet, silent = false, false }()
+
+	tests := []struct {
+		name          string
+		quiet, silent bool
+		want          io.Writer
+	}{
+		{"default", false, false, os.Stderr},
+		{"quiet", true, false, io.Discard},
+		{"silent", false, true, io.Discard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiet, silent = tt.quiet, tt.silent
+
+			if got := logOutput(); got != tt.want {
+				t.Errorf("logOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDetectProjectContext(t *testing.T) {
 	// Not parallel - subtests read/write global projectFlag
 	t.Run("with empty config", func(t *testing.T) {
@@ -405,7 +432,7 @@ func TestRunConfigValidate(t *testing.T) {
 		// Test LoadEffective with a non-existent path (will use defaults)
 		tmpDir := t.TempDir()
 
-		cfg, err := config.LoadEffective(tmpDir, "")
+		cfg, err := config.LoadEffective(tmpDir, "", config.LoadOptions{})
 		if err != nil {
 			t.Fatalf("LoadEffective() error = %v", err)
 		}
@@ -452,7 +479,7 @@ func TestRunConfigValidate(t *testing.T) {
 		}
 
 		// Load the config from the temp directory
-		cfg, err := config.LoadWithMCP(mcpPath, cfgPath)
+		cfg, err := config.LoadWithMCP(mcpPath, cfgPath, config.LoadOptions{})
 		if err != nil {
 			t.Fatalf("LoadWithMCP() error = %v", err)
 		}
@@ -492,4 +519,50 @@ func TestRunList(t *testing.T) {
 			t.Errorf("runList() with empty config should succeed, got error: %v", err)
 		}
 	})
+
+	t.Run("with unknown server", func(t *testing.T) {
+		tmpHome := t.TempDir()
+		assernDir := filepath.Join(tmpHome, ".valksor", "assern")
+		if err := os.MkdirAll(assernDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		mcpPath := filepath.Join(assernDir, "mcp.json")
+		if err := os.WriteFile(mcpPath, []byte(`{"mcpServers":{"fs":{"command":"true"}}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		restore := config.SetHomeDirForTesting(tmpHome)
+		defer restore()
+
+		listServer = "github"
+		defer func() { listServer = "" }()
+
+		err := runList(listCmd, nil)
+		if err == nil || !strings.Contains(err.Error(), `server "github" is not configured`) {
+			t.Errorf("runList() with an unknown --server error = %v", err)
+		}
+	})
+}
+
+func TestServerState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status aggregator.ServerStatus
+		want   string
+	}{
+		{aggregator.ServerStatus{State: aggregator.ServerStateUp, Health: aggregator.HealthHealthy}, "running"},
+		{aggregator.ServerStatus{State: aggregator.ServerStateRestarting}, "restarting"},
+		{aggregator.ServerStatus{State: aggregator.ServerStateIdle}, "idle (lazy)"},
+		{aggregator.ServerStatus{State: aggregator.ServerStateDown, Health: aggregator.HealthNeedsAuth}, "needs auth"},
+		{aggregator.ServerStatus{State: aggregator.ServerStateDown, Health: aggregator.HealthUnhealthy}, "failed"},
+		{aggregator.ServerStatus{State: aggregator.ServerStateDown, Health: aggregator.HealthUnknown}, "failed"},
+	}
+
+	for _, tt := range tests {
+		if got := serverState(tt.status); got != tt.want {
+			t.Errorf("serverState(%+v) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
 }
@@ -1,24 +1,77 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/valksor/go-assern/internal/cli"
 )
 
-// runMCPAdd adds a new MCP server interactively.
+// runMCPAdd adds a new MCP server, from flags or interactively.
 func runMCPAdd(cmd *cobra.Command, args []string) error {
-	fmt.Println("Adding a new MCP server...")
-	fmt.Println()
-
 	// Create manager
-	mgr, err := cli.NewMCPManager()
+	mgr, err := cli.NewMCPManager(loadOptions())
 	if err != nil {
 		return fmt.Errorf("creating MCP manager: %w", err)
 	}
 
+	if len(args) == 0 && !mcpFlagsSet(cmd) {
+		return runMCPAddInteractive(mgr)
+	}
+
+	input := &cli.MCPInput{}
+	if nameArgs := argsBeforeDash(cmd, args); len(nameArgs) > 0 {
+		input.Name = nameArgs[0]
+	}
+
+	if err := applyMCPFlags(cmd, args, input); err != nil {
+		return err
+	}
+
+	if err := completeMCPInput(input); err != nil {
+		return err
+	}
+
+	if input.Project != "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+
+		created, err := cli.EnsureProject(input.Project, cwd)
+		if err != nil {
+			return err
+		}
+
+		if created {
+			fmt.Printf("Project '%s' created with directory %s\n", input.Project, cwd)
+		}
+	}
+
+	if save, err := confirmMCPSave(input); err != nil || !save {
+		return err
+	}
+
+	if err := mgr.AddServer(input); err != nil {
+		return fmt.Errorf("adding server: %w", err)
+	}
+
+	fmt.Printf("Server '%s' added to the %s config\n", input.Name, input.Scope)
+
+	return nil
+}
+
+// runMCPAddInteractive adds a server with the full interactive prompt flow.
+func runMCPAddInteractive(mgr *cli.MCPManager) error {
+	fmt.Println("Adding a new MCP server...")
+	fmt.Println()
+
 	// Run interactive prompts
 	input, err := cli.PromptForMCPServer(nil)
 	if err != nil {
@@ -41,22 +94,31 @@ func runMCPAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// runMCPEdit edits an existing MCP server.
+// runMCPEdit edits an existing MCP server, from flags or interactively.
 func runMCPEdit(cmd *cobra.Command, args []string) error {
-	fmt.Println("Editing an MCP server...")
-	fmt.Println()
-
 	// Create manager
-	mgr, err := cli.NewMCPManager()
+	mgr, err := cli.NewMCPManager(loadOptions())
 	if err != nil {
 		return fmt.Errorf("creating MCP manager: %w", err)
 	}
 
+	nameArgs := argsBeforeDash(cmd, args)
+	flagMode := mcpFlagsSet(cmd) || len(nameArgs) < len(args)
+
+	if !flagMode {
+		fmt.Println("Editing an MCP server...")
+		fmt.Println()
+	}
+
 	// Determine server name
 	var serverName string
-	if len(args) > 0 {
-		serverName = args[0]
+	if len(nameArgs) > 0 {
+		serverName = nameArgs[0]
 	} else {
+		if !cli.IsInteractive() {
+			return errors.New("server name required (stdin is not a terminal)")
+		}
+
 		// List all servers
 		allServers := mgr.ListServers()
 		if len(allServers) == 0 {
@@ -94,47 +156,162 @@ func runMCPEdit(cmd *cobra.Command, args []string) error {
 		WorkDir:   existingServer.WorkDir,
 		URL:       existingServer.URL,
 		Headers:   existingServer.Headers,
-		OAuth:     existingServer.OAuth,
+		OAuth:     existingServer.OAuth.Clone(),
 	}
 
-	// Run interactive prompts
-	updatedInput, err := cli.PromptForMCPServer(input)
-	if err != nil {
-		if err.Error() == "cancelled by user" {
-			fmt.Println("Cancelled.")
+	if flagMode {
+		if err := applyMCPFlags(cmd, args, input); err != nil {
+			return err
+		}
 
-			return nil
+		if err := completeMCPInput(input); err != nil {
+			return err
 		}
 
-		return err
+		if save, err := confirmMCPSave(input); err != nil || !save {
+			return err
+		}
+	} else {
+		// Run interactive prompts
+		input, err = cli.PromptForMCPServer(input)
+		if err != nil {
+			if err.Error() == "cancelled by user" {
+				fmt.Println("Cancelled.")
+
+				return nil
+			}
+
+			return err
+		}
 	}
 
 	// Update server
-	if err := mgr.UpdateServer(serverName, updatedInput); err != nil {
+	if err := mgr.UpdateServer(serverName, input); err != nil {
 		return fmt.Errorf("updating server: %w", err)
 	}
 
-	fmt.Printf("\nServer '%s' updated successfully!\n", serverName)
+	fmt.Printf("Server '%s' updated successfully!\n", serverName)
 
 	return nil
 }
 
+// addMCPServerFlags registers the server settings 'mcp add' and 'mcp edit'
+// take as flags.
+func addMCPServerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&mcpFlags.Transport, "transport", "", "Transport: stdio, http, sse, oauth-http, oauth-sse or websocket (default: from --command or --url)")
+	cmd.Flags().StringVar(&mcpFlags.Command, "command", "", "Command of a stdio server")
+	cmd.Flags().StringArrayVar(&mcpFlags.Args, "args", nil, "Argument of the command (repeatable; arguments after -- are added too)")
+	cmd.Flags().StringArrayVar(&mcpFlags.Env, "env", nil, "Environment variable as KEY=VALUE (repeatable)")
+	cmd.Flags().StringVar(&mcpFlags.WorkDir, "work-dir", "", "Working directory of a stdio server")
+	cmd.Flags().StringVar(&mcpFlags.URL, "url", "", "URL of a remote (http or sse) server")
+	cmd.Flags().StringArrayVar(&mcpFlags.Headers, "header", nil, "HTTP header as Name=Value (repeatable)")
+	cmd.Flags().StringVar(&mcpFlags.OAuthClientID, "oauth-client-id", "", "OAuth client ID")
+	cmd.Flags().StringVar(&mcpFlags.OAuthClientSecret, "oauth-client-secret", "", "OAuth client secret")
+	cmd.Flags().StringArrayVar(&mcpFlags.OAuthScopes, "oauth-scope", nil, "OAuth scope (repeatable)")
+	cmd.Flags().StringVar(&mcpFlags.OAuthMetadataURL, "oauth-metadata-url", "", "OAuth authorization server metadata URL")
+	cmd.Flags().StringVar(&mcpFlags.OAuthRedirectURI, "oauth-redirect-uri", "", "OAuth redirect URI")
+	cmd.Flags().BoolVar(&mcpFlags.OAuthPKCE, "oauth-pkce", false, "Use PKCE (public OAuth clients)")
+	cmd.Flags().BoolVarP(&mcpYes, "yes", "y", false, "Save without asking for confirmation")
+}
+
+// mcpFlagsSet reports whether any flag of the command itself, or
+// --project, was given: the server is then configured from flags rather
+// than by the prompt flow.
+func mcpFlagsSet(cmd *cobra.Command) bool {
+	set := cmd.Flags().Changed("project")
+
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		set = set || f.Changed
+	})
+
+	return set
+}
+
+// argsBeforeDash returns the positional arguments given before "--"; the
+// ones after it are the server command's arguments.
+func argsBeforeDash(cmd *cobra.Command, args []string) []string {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		return args[:dash]
+	}
+
+	return args
+}
+
+// applyMCPFlags applies the flags of 'mcp add' and 'mcp edit' to input.
+// Arguments after "--" are appended to --args.
+func applyMCPFlags(cmd *cobra.Command, args []string, input *cli.MCPInput) error {
+	if len(argsBeforeDash(cmd, args)) > 1 {
+		return fmt.Errorf("unexpected arguments %v (pass the command's arguments after --)", args[1:])
+	}
+
+	flags := mcpFlags
+	flags.Project = projectFlag
+
+	changed := cmd.Flags().Changed
+	if cmd.Flags().Lookup("scope") == nil {
+		// Only 'mcp add' chooses where a server goes; elsewhere --project
+		// keeps its global meaning
+		changed = func(flag string) bool { return flag != "project" && cmd.Flags().Changed(flag) }
+	}
+
+	byFlag := changed
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 && dash < len(args) {
+		flags.Args = append(slices.Clone(flags.Args), args[dash:]...)
+		changed = func(flag string) bool { return flag == "args" || byFlag(flag) }
+	}
+
+	return flags.Apply(input, changed)
+}
+
+// completeMCPInput prompts for the required settings the flags left out
+// when stdin is a terminal, and otherwise fails naming them.
+func completeMCPInput(input *cli.MCPInput) error {
+	if len(cli.MissingFields(input)) > 0 && cli.IsInteractive() {
+		if err := cli.PromptMissing(input); err != nil {
+			return err
+		}
+	}
+
+	return cli.ValidateInput(input)
+}
+
+// confirmMCPSave asks whether to save a server configured by flags, unless
+// --yes was given or there is no terminal to ask on.
+func confirmMCPSave(input *cli.MCPInput) (bool, error) {
+	if mcpYes || !cli.IsInteractive() {
+		return true, nil
+	}
+
+	save, err := cli.ConfirmSave(input)
+	if err == nil && !save {
+		fmt.Println("Cancelled.")
+	}
+
+	return save, err
+}
+
 // runMCPDelete deletes MCP server(s).
 func runMCPDelete(cmd *cobra.Command, args []string) error {
-	fmt.Println("Deleting MCP server(s)...")
-	fmt.Println()
-
 	// Create manager
-	mgr, err := cli.NewMCPManager()
+	mgr, err := cli.NewMCPManager(loadOptions())
 	if err != nil {
 		return fmt.Errorf("creating MCP manager: %w", err)
 	}
 
+	interactive := cli.IsInteractive()
+
 	// Get server names
 	var toDelete []string
 	if len(args) > 0 {
 		toDelete = args
 	} else {
+		if !interactive {
+			return errors.New("server name required (stdin is not a terminal)")
+		}
+
+		fmt.Println("Deleting MCP server(s)...")
+		fmt.Println()
+
 		// List all servers
 		allServers := mgr.ListServers()
 		if len(allServers) == 0 {
@@ -156,14 +333,20 @@ func runMCPDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Confirm deletion
-	if err := cli.ConfirmDelete(toDelete); err != nil {
-		if err.Error() == "cancelled by user" {
-			fmt.Println("Cancelled.")
+	switch {
+	case mcpYes:
+	case !interactive:
+		return errors.New("pass --yes to delete without a terminal to confirm on")
+	default:
+		if err := cli.ConfirmDelete(toDelete); err != nil {
+			if err.Error() == "cancelled by user" {
+				fmt.Println("Cancelled.")
+
+				return nil
+			}
 
-			return nil
+			return err
 		}
-
-		return err
 	}
 
 	// Delete servers
@@ -171,7 +354,56 @@ func runMCPDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("deleting servers: %w", err)
 	}
 
-	fmt.Printf("\nDeleted %d server(s)\n", len(toDelete))
+	fmt.Printf("Deleted %d server(s); 'assern mcp restore <name>' brings one back\n", len(toDelete))
+
+	return nil
+}
+
+// runMCPRestore restores a deleted MCP server from the archive.
+func runMCPRestore(cmd *cobra.Command, args []string) error {
+	mgr, err := cli.NewMCPManager(loadOptions())
+	if err != nil {
+		return fmt.Errorf("creating MCP manager: %w", err)
+	}
+
+	var serverName string
+	if len(args) > 0 {
+		serverName = args[0]
+	} else {
+		archived, err := mgr.ArchivedServers()
+		if err != nil {
+			return err
+		}
+
+		if len(archived) == 0 {
+			fmt.Println("No deleted MCP servers archived.")
+
+			return nil
+		}
+
+		// Most recently deleted first; a name deleted in both scopes is
+		// listed once, restoring the latest
+		var names []string
+		for _, info := range archived {
+			if !slices.Contains(names, info.Name) {
+				names = append(names, info.Name)
+			}
+		}
+
+		selected, err := cli.SelectServer(names, "Select server to restore:")
+		if err != nil {
+			return err
+		}
+		serverName = selected
+	}
+
+	info, err := mgr.RestoreServer(serverName)
+	if err != nil {
+		return fmt.Errorf("restoring server: %w", err)
+	}
+
+	fmt.Printf("Server '%s' restored to the %s config (deleted %s)\n",
+		info.Name, info.Scope, info.DeletedAt.Local().Format(time.DateTime))
 
 	return nil
 }
@@ -179,16 +411,21 @@ func runMCPDelete(cmd *cobra.Command, args []string) error {
 // runMCPList lists all MCP servers.
 func runMCPList(cmd *cobra.Command, args []string) error {
 	// Create manager
-	mgr, err := cli.NewMCPManager()
+	mgr, err := cli.NewMCPManager(loadOptions())
 	if err != nil {
 		return fmt.Errorf("creating MCP manager: %w", err)
 	}
 
+	reveal, err := confirmReveal()
+	if err != nil {
+		return err
+	}
+
 	// Get all servers
 	servers := mgr.ListServers()
 
 	// Format and display
-	output := cli.FormatServerList(servers, verbose)
+	output := cli.FormatServerList(servers, verbose, reveal)
 	fmt.Println(output)
 
 	return nil
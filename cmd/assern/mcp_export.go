@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/cli"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// runMCPExport prints or writes a client configuration pointing at Assern,
+// or with --all holding every configured backend.
+func runMCPExport(cmd *cobra.Command, args []string) error {
+	servers := map[string]*config.MCPServer{"assern": config.AssernClientServer(exportCommand)}
+
+	if exportAll {
+		mgr, err := cli.NewMCPManager(loadOptions())
+		if err != nil {
+			return fmt.Errorf("creating MCP manager: %w", err)
+		}
+
+		servers = make(map[string]*config.MCPServer)
+
+		// Project servers win over global ones, as in the merged config
+		for _, info := range mgr.ListServers() {
+			if _, ok := servers[info.Name]; ok && info.Scope != cli.ScopeProject {
+				continue
+			}
+
+			servers[info.Name] = info.Server
+		}
+
+		if len(servers) == 0 {
+			return errors.New("no MCP servers configured")
+		}
+	}
+
+	data, warnings, err := config.ExportClientConfig(exportTo, servers)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	if exportOutput == "" {
+		_, err := os.Stdout.Write(data)
+
+		return err
+	}
+
+	if config.FileExists(exportOutput) && !exportForce {
+		return fmt.Errorf("%s already exists (use --force to replace it, or merge the printed snippet by hand)", exportOutput)
+	}
+
+	if err := os.WriteFile(exportOutput, data, 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", exportOutput, err)
+	}
+
+	fmt.Printf("Wrote %s config to %s\n", exportTo, exportOutput)
+
+	return nil
+}
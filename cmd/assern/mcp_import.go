@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/cli"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/disambiguate"
+)
+
+// runMCPImport imports MCP servers from another client's configuration.
+func runMCPImport(cmd *cobra.Command, args []string) error {
+	path, err := importSourcePath(importFrom, args)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s config: %w", importFrom, err)
+	}
+
+	result, err := config.ParseClientConfig(importFrom, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Importing from %s\n", path)
+
+	for _, warning := range result.Warnings {
+		fmt.Printf("  warning: %s\n", warning)
+	}
+
+	if len(result.Servers) == 0 {
+		fmt.Println("No servers to import.")
+
+		return nil
+	}
+
+	interactive := !importYes && disambiguate.IsInteractive()
+
+	scope, err := importTargetScope(interactive)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := cli.NewMCPManager(loadOptions())
+	if err != nil {
+		return fmt.Errorf("creating MCP manager: %w", err)
+	}
+
+	selected, err := resolveImportNames(mgr, scope, result.Servers, interactive)
+	if err != nil {
+		return err
+	}
+
+	if len(selected) == 0 {
+		fmt.Println("\nNothing to import.")
+
+		return nil
+	}
+
+	fmt.Printf("\nServers to import into %s config:\n", scope)
+
+	for _, name := range slices.Sorted(maps.Keys(selected)) {
+		fmt.Printf("  - %s\n", name)
+	}
+
+	if importDryRun {
+		fmt.Println("\nDry run: nothing was written.")
+
+		return nil
+	}
+
+	if err := mgr.ImportServers(scope, selected); err != nil {
+		return fmt.Errorf("importing servers: %w", err)
+	}
+
+	fmt.Printf("\nImported %d server(s)\n", len(selected))
+
+	return nil
+}
+
+// importSourcePath returns the client config to read: the given path, or
+// the first of the client's usual locations that exists.
+func importSourcePath(client string, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+
+	candidates, err := config.ClientConfigPaths(client, cwd)
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidates {
+		if config.FileExists(candidate) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no %s config found (looked in %v); pass its path as an argument", client, candidates)
+}
+
+// importTargetScope returns the scope from --scope, prompting when it is not
+// set and the session is interactive. The default is global.
+func importTargetScope(interactive bool) (cli.ScopeType, error) {
+	switch cli.ScopeType(importScope) {
+	case cli.ScopeGlobal, cli.ScopeProject:
+		return cli.ScopeType(importScope), nil
+	case "":
+		if interactive {
+			return cli.PromptImportScope()
+		}
+
+		return cli.ScopeGlobal, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q (use global or project)", importScope)
+	}
+}
+
+// resolveImportNames settles name collisions and invalid names, returning
+// the servers to write keyed by their final name. Collisions are prompted
+// for when interactive; otherwise they are skipped, or replaced with
+// --overwrite when the existing server is in the target scope.
+func resolveImportNames(
+	mgr *cli.MCPManager,
+	scope cli.ScopeType,
+	servers map[string]*config.MCPServer,
+	interactive bool,
+) (map[string]*config.MCPServer, error) {
+	selected := make(map[string]*config.MCPServer, len(servers))
+
+	taken := func(name string) bool {
+		if _, ok := selected[name]; ok {
+			return true
+		}
+
+		existing, _ := mgr.ImportConflict(name, scope)
+
+		return existing != ""
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		existing, reason := mgr.ImportConflict(name, scope)
+		if reason == nil {
+			if _, ok := selected[name]; ok {
+				reason = fmt.Errorf("server '%s' is already being imported", name)
+			}
+		}
+
+		if reason == nil {
+			selected[name] = servers[name]
+
+			continue
+		}
+
+		canOverwrite := existing == scope
+
+		action := cli.ImportSkip
+		target := name
+
+		switch {
+		case interactive:
+			var err error
+
+			action, target, err = cli.PromptImportConflict(name, reason, canOverwrite, taken)
+			if err != nil {
+				return nil, err
+			}
+		case importOverwrite && canOverwrite:
+			action = cli.ImportOverwrite
+		}
+
+		if action == cli.ImportSkip {
+			fmt.Printf("  skipped %s: %v\n", name, reason)
+
+			continue
+		}
+
+		selected[target] = servers[name]
+	}
+
+	return selected, nil
+}
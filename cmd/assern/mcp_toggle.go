@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/cli"
+)
+
+// runMCPEnable enables disabled MCP servers.
+func runMCPEnable(cmd *cobra.Command, args []string) error {
+	return setMCPDisabled(args, false)
+}
+
+// runMCPDisable disables MCP servers without removing them.
+func runMCPDisable(cmd *cobra.Command, args []string) error {
+	return setMCPDisabled(args, true)
+}
+
+// setMCPDisabled sets the disabled state of the named servers, all servers
+// with --all, or the ones selected at a prompt, and reloads the running
+// instance with --reload.
+func setMCPDisabled(names []string, disabled bool) error {
+	verb := map[bool]string{false: "enable", true: "disable"}[disabled]
+
+	if mcpAll && len(names) > 0 {
+		return errors.New("pass server names or --all, not both")
+	}
+
+	mgr, err := cli.NewMCPManager(loadOptions())
+	if err != nil {
+		return fmt.Errorf("creating MCP manager: %w", err)
+	}
+
+	if len(names) == 0 && !mcpAll {
+		if !cli.IsInteractive() {
+			return errors.New("server name or --all required (stdin is not a terminal)")
+		}
+
+		// Offer the servers the command would change
+		var candidates []string
+		for _, info := range mgr.ListServers() {
+			if info.Server.Disabled != disabled && !slices.Contains(candidates, info.Name) {
+				candidates = append(candidates, info.Name)
+			}
+		}
+
+		if len(candidates) == 0 {
+			fmt.Printf("No MCP servers to %s.\n", verb)
+
+			return nil
+		}
+
+		slices.Sort(candidates)
+
+		if names, err = cli.SelectServers(candidates, fmt.Sprintf("Select server(s) to %s:", verb)); err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return nil
+		}
+	}
+
+	changed, err := mgr.SetDisabled(names, disabled)
+	if err != nil {
+		return fmt.Errorf("%s servers: %w", verb, err)
+	}
+
+	if len(changed) == 0 {
+		fmt.Printf("Nothing to do: server(s) already %sd\n", verb)
+
+		return nil
+	}
+
+	fmt.Printf("%sd %d server(s): %s\n", strings.ToUpper(verb[:1])+verb[1:], len(changed), strings.Join(changed, ", "))
+
+	if !mcpReload {
+		return nil
+	}
+
+	result, err := reloadRunning(false)
+	if errors.Is(err, errNoInstance) {
+		fmt.Println("No running assern instance; the change applies when it starts")
+
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Running instance reloaded (added %d, removed %d servers)\n", result.Added, result.Removed)
+
+	for _, e := range result.Errors {
+		fmt.Printf("  - %s\n", e)
+	}
+
+	return nil
+}
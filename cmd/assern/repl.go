@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+// replHelp lists the REPL commands.
+const replHelp = `Commands:
+  tools [filter]                  List tools, optionally only those whose name contains filter
+  call <tool> [key=value...]      Call a tool; values are JSON unless the schema types a string
+  call <tool> {"key": ...}        Call a tool with a JSON object of arguments
+  resources                       List resources
+  read <uri>                      Read a resource
+  prompts                         List prompts
+  prompt <name> [key=value...]    Get a prompt
+  raw <method> [{"params": ...}]  Send any MCP request and print its result
+  format [text|json]              Show or set how results are printed
+  trace [on|off]                  Show or set printing of the JSON-RPC traffic
+  help                            Show this help
+  quit                            Leave (or Ctrl-D)`
+
+func runRepl(cmd *cobra.Command, _ []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	var conn replConn
+
+	if !replFresh {
+		if existing, err := instance.NewDetector(logger).DetectRunning(); err == nil && existing != nil {
+			ctx, cancel := context.WithTimeout(cmd.Context(), instance.ClientTimeout)
+			defer cancel()
+
+			client := instance.NewClient(existing.SocketPath)
+			if err := client.Connect(ctx); err != nil {
+				return err
+			}
+			defer func() { _ = client.Close() }()
+
+			if err := client.Initialize(ctx); err != nil {
+				return err
+			}
+
+			conn = client
+
+			fmt.Fprintf(os.Stderr, "Connected to the running instance (pid %d).\n", existing.PID)
+		}
+	}
+
+	if conn == nil {
+		agg, stop, err := startAggregator(nil)
+		if err != nil {
+			return err
+		}
+		defer stop()
+
+		local := &localConn{server: agg.CreateMCPServer()}
+		if err := local.initialize(cmd.Context()); err != nil {
+			return err
+		}
+
+		conn = local
+
+		fmt.Fprintln(os.Stderr, "Started the servers for this session (no running instance).")
+	}
+
+	r := &repl{conn: conn, out: os.Stdout, format: replFormatText}
+
+	interactive := term.IsTerminal(int(os.Stdin.Fd()))
+	if interactive {
+		fmt.Fprintln(os.Stderr, "Type 'help' for commands.")
+	}
+
+	return r.run(cmd.Context(), os.Stdin, interactive)
+}
+
+// REPL output formats.
+const (
+	replFormatText = "text"
+	replFormatJSON = "json"
+)
+
+// repl reads commands and prints what the instance answers.
+type repl struct {
+	conn    replConn
+	out     io.Writer
+	format  string
+	tracing bool
+	schemas map[string]json.RawMessage // Input schemas of the listed tools
+}
+
+// run executes the commands read from in until quit or the end of input.
+// Commands that fail print their error and the session goes on. With
+// prompt, a prompt is printed before each command.
+func (r *repl) run(ctx context.Context, in io.Reader, prompt bool) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for {
+		if prompt {
+			fmt.Fprint(r.out, "assern> ")
+		}
+
+		if !scanner.Scan() {
+			if prompt {
+				fmt.Fprintln(r.out)
+			}
+
+			return scanner.Err()
+		}
+
+		quit, err := r.exec(ctx, scanner.Text())
+		if err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+
+		if quit {
+			return nil
+		}
+	}
+}
+
+// exec executes one command line; quit is true for quit and exit.
+func (r *repl) exec(ctx context.Context, line string) (quit bool, err error) {
+	command, rest := cutWord(line)
+	if command == "" || strings.HasPrefix(command, "#") {
+		return false, nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, replTimeout)
+	defer cancel()
+
+	switch command {
+	case "quit", "exit":
+		return true, nil
+	case "help", "?":
+		fmt.Fprintln(r.out, replHelp)
+
+		return false, nil
+	case "tools":
+		return false, r.listTools(callCtx, rest)
+	case "call":
+		return false, r.call(callCtx, rest)
+	case "resources":
+		return false, r.listResources(callCtx)
+	case "read":
+		return false, r.read(callCtx, rest)
+	case "prompts":
+		return false, r.listPrompts(callCtx)
+	case "prompt":
+		return false, r.prompt(callCtx, rest)
+	case "raw":
+		return false, r.raw(callCtx, rest)
+	case "format":
+		return false, r.setFormat(rest)
+	case "trace":
+		return false, r.setTrace(rest)
+	default:
+		return false, fmt.Errorf("unknown command %q (type 'help')", command)
+	}
+}
+
+// cutWord splits the first whitespace-separated word off line.
+func cutWord(line string) (word, rest string) {
+	line = strings.TrimSpace(line)
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		return line[:i], strings.TrimSpace(line[i:])
+	}
+
+	return line, ""
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/instance"
+)
+
+// replMaxPages bounds the pages listAll follows, should a server keep
+// returning new cursors.
+const replMaxPages = 1000
+
+// listAll requests every page of a list method and returns the items of
+// the result's key. It fails on a cursor it has seen before, which would
+// loop forever, and after replMaxPages pages.
+func (r *repl) listAll(ctx context.Context, method, key string) ([]json.RawMessage, error) {
+	var (
+		items  []json.RawMessage
+		cursor string
+		seen   = make(map[string]bool)
+	)
+
+	for range replMaxPages {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		result, err := r.conn.Request(ctx, method, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var page map[string]json.RawMessage
+		if err := json.Unmarshal(result, &page); err != nil {
+			return nil, fmt.Errorf("decoding %s result: %w", method, err)
+		}
+
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(page[key], &pageItems); err != nil && page[key] != nil {
+			return nil, fmt.Errorf("decoding %s result: %w", method, err)
+		}
+
+		items = append(items, pageItems...)
+		cursor = ""
+
+		if err := json.Unmarshal(page["nextCursor"], &cursor); err != nil || cursor == "" {
+			return items, nil
+		}
+
+		if seen[cursor] {
+			return nil, fmt.Errorf("%s: the server returned cursor %q again", method, cursor)
+		}
+
+		seen[cursor] = true
+	}
+
+	return nil, fmt.Errorf("%s: more than %d pages", method, replMaxPages)
+}
+
+// loadTools lists the tools and keeps their input schemas, which type the
+// key=value arguments of call.
+func (r *repl) loadTools(ctx context.Context) ([]json.RawMessage, []instance.ToolInfo, error) {
+	items, err := r.listAll(ctx, string(mcp.MethodToolsList), "tools")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tools := make([]instance.ToolInfo, len(items))
+	r.schemas = make(map[string]json.RawMessage, len(items))
+
+	for i, item := range items {
+		if err := json.Unmarshal(item, &tools[i]); err != nil {
+			return nil, nil, fmt.Errorf("decoding tool: %w", err)
+		}
+
+		r.schemas[tools[i].Name] = tools[i].InputSchema
+	}
+
+	return items, tools, nil
+}
+
+// call calls a tool with key=value arguments, or a JSON object.
+func (r *repl) call(ctx context.Context, line string) error {
+	name, rest := cutWord(line)
+	if name == "" {
+		return errors.New("usage: call <tool> [key=value...] or call <tool> {json}")
+	}
+
+	if r.schemas == nil {
+		// A failed listing leaves the arguments untyped
+		_, _, _ = r.loadTools(ctx)
+	}
+
+	var (
+		jsonArgs string
+		pairs    []string
+	)
+
+	if strings.HasPrefix(rest, "{") {
+		jsonArgs = rest
+	} else {
+		var err error
+		if pairs, err = shellquote.Split(rest); err != nil {
+			return fmt.Errorf("parsing arguments: %w", err)
+		}
+	}
+
+	arguments, err := callArguments(schemaProperties(r.schemas[name]), jsonArgs, pairs)
+	if err != nil {
+		return err
+	}
+
+	raw, err := r.conn.Request(ctx, string(mcp.MethodToolsCall), map[string]any{"name": name, "arguments": arguments})
+	if err != nil {
+		return err
+	}
+
+	result, err := mcp.ParseCallToolResult(&raw)
+	if err != nil {
+		return fmt.Errorf("parsing result: %w", err)
+	}
+
+	if err := writeCallResult(r.out, result, r.format == replFormatJSON); err != nil {
+		return err
+	}
+
+	if result.IsError {
+		return errToolFailed
+	}
+
+	return nil
+}
+
+func (r *repl) prompt(ctx context.Context, line string) error {
+	name, rest := cutWord(line)
+	if name == "" {
+		return errors.New("usage: prompt <name> [key=value...]")
+	}
+
+	pairs, err := shellquote.Split(rest)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	arguments := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("argument %q: want key=value", pair)
+		}
+
+		arguments[key] = value
+	}
+
+	raw, err := r.conn.Request(ctx, string(mcp.MethodPromptsGet), map[string]any{"name": name, "arguments": arguments})
+	if err != nil {
+		return err
+	}
+
+	if r.format == replFormatJSON {
+		return r.writeJSON(raw)
+	}
+
+	result, err := mcp.ParseGetPromptResult(&raw)
+	if err != nil {
+		return fmt.Errorf("parsing result: %w", err)
+	}
+
+	for _, message := range result.Messages {
+		if text, ok := message.Content.(mcp.TextContent); ok {
+			fmt.Fprintf(r.out, "%s: %s\n", message.Role, strings.TrimSuffix(text.Text, "\n"))
+		} else {
+			fmt.Fprintf(r.out, "%s: [%T]\n", message.Role, message.Content)
+		}
+	}
+
+	return nil
+}
+
+// raw sends any request; params, when given, is a JSON object.
+func (r *repl) raw(ctx context.Context, line string) error {
+	method, rest := cutWord(line)
+	if method == "" {
+		return errors.New("usage: raw <method> [{json params}]")
+	}
+
+	var params any
+	if rest != "" {
+		if err := json.Unmarshal([]byte(rest), &params); err != nil {
+			return fmt.Errorf("params: want a JSON object: %w", err)
+		}
+	}
+
+	result, err := r.conn.Request(ctx, method, params)
+	if err != nil {
+		return err
+	}
+
+	return r.writeJSON(result)
+}
+
+func (r *repl) setFormat(format string) error {
+	switch format {
+	case "":
+		fmt.Fprintf(r.out, "format: %s\n", r.format)
+	case replFormatText, replFormatJSON:
+		r.format = format
+	default:
+		return fmt.Errorf("unknown format %q: want text or json", format)
+	}
+
+	return nil
+}
+
+func (r *repl) setTrace(state string) error {
+	switch state {
+	case "":
+		fmt.Fprintf(r.out, "trace: %s\n", map[bool]string{true: "on", false: "off"}[r.tracing])
+
+		return nil
+	case "on":
+		r.tracing = true
+		r.conn.SetTrace(r.traceMessage)
+	case "off":
+		r.tracing = false
+		r.conn.SetTrace(nil)
+	default:
+		return fmt.Errorf("unknown trace state %q: want on or off", state)
+	}
+
+	return nil
+}
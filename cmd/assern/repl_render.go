@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (r *repl) listTools(ctx context.Context, filter string) error {
+	items, tools, err := r.loadTools(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+
+	for i, tool := range tools {
+		if !strings.Contains(tool.Name, filter) {
+			continue
+		}
+
+		if r.format == replFormatJSON {
+			if err := r.writeJSON(items[i]); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		description, _, _ := strings.Cut(tool.Description, "\n")
+		fmt.Fprintf(w, "%s\t%s\n", tool.Name, description)
+	}
+
+	return w.Flush()
+}
+
+func (r *repl) listResources(ctx context.Context) error {
+	items, err := r.listAll(ctx, string(mcp.MethodResourcesList), "resources")
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+
+	for _, item := range items {
+		if r.format == replFormatJSON {
+			if err := r.writeJSON(item); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var resource mcp.Resource
+		if err := json.Unmarshal(item, &resource); err != nil {
+			return fmt.Errorf("decoding resource: %w", err)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", resource.URI, resource.Name, resource.MIMEType)
+	}
+
+	return w.Flush()
+}
+
+func (r *repl) read(ctx context.Context, uri string) error {
+	if uri == "" {
+		return errors.New("usage: read <uri>")
+	}
+
+	raw, err := r.conn.Request(ctx, string(mcp.MethodResourcesRead), map[string]any{"uri": uri})
+	if err != nil {
+		return err
+	}
+
+	if r.format == replFormatJSON {
+		return r.writeJSON(raw)
+	}
+
+	result, err := mcp.ParseReadResourceResult(&raw)
+	if err != nil {
+		return fmt.Errorf("parsing result: %w", err)
+	}
+
+	for _, content := range result.Contents {
+		switch c := content.(type) {
+		case mcp.TextResourceContents:
+			fmt.Fprintln(r.out, strings.TrimSuffix(c.Text, "\n"))
+		case mcp.BlobResourceContents:
+			fmt.Fprintf(r.out, "[blob %s, %d bytes base64]\n", c.MIMEType, len(c.Blob))
+		default:
+			fmt.Fprintf(r.out, "[%T]\n", content)
+		}
+	}
+
+	return nil
+}
+
+func (r *repl) listPrompts(ctx context.Context) error {
+	items, err := r.listAll(ctx, string(mcp.MethodPromptsList), "prompts")
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+
+	for _, item := range items {
+		if r.format == replFormatJSON {
+			if err := r.writeJSON(item); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var prompt mcp.Prompt
+		if err := json.Unmarshal(item, &prompt); err != nil {
+			return fmt.Errorf("decoding prompt: %w", err)
+		}
+
+		args := make([]string, 0, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			if arg.Required {
+				args = append(args, arg.Name)
+			} else {
+				args = append(args, arg.Name+"?")
+			}
+		}
+
+		fmt.Fprintf(w, "%s(%s)\t%s\n", prompt.Name, strings.Join(args, ", "), prompt.Description)
+	}
+
+	return w.Flush()
+}
+
+// traceMessage prints a JSON-RPC message as sent (-->) or received (<--).
+func (r *repl) traceMessage(sent bool, msg []byte) {
+	arrow := "<--"
+	if sent {
+		arrow = "-->"
+	}
+
+	fmt.Fprintf(r.out, "%s %s\n", arrow, msg)
+}
+
+// writeJSON writes data indented.
+func (r *repl) writeJSON(data json.RawMessage) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("formatting JSON: %w", err)
+	}
+
+	buf.WriteByte('\n')
+
+	_, err := r.out.Write(buf.Bytes())
+
+	return err
+}
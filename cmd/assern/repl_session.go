@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// replConn sends the MCP requests of the REPL: to the running instance, or
+// to an aggregator started for the session.
+type replConn interface {
+	Request(ctx context.Context, method string, params any) (json.RawMessage, error)
+	SetTrace(fn func(sent bool, msg []byte))
+}
+
+// localConn handles requests with the MCP server of an aggregator running
+// in this process.
+type localConn struct {
+	server    *server.MCPServer
+	requestID int
+	trace     func(sent bool, msg []byte)
+}
+
+func (c *localConn) SetTrace(fn func(sent bool, msg []byte)) {
+	c.trace = fn
+}
+
+func (c *localConn) initialize(ctx context.Context) error {
+	_, err := c.Request(ctx, string(mcp.MethodInitialize), map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "assern-repl", "version": version.Version},
+	})
+
+	return err
+}
+
+func (c *localConn) Request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+
+	c.requestID++
+
+	req, err := json.Marshal(map[string]any{"jsonrpc": mcp.JSONRPC_VERSION, "id": c.requestID, "method": method, "params": params})
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", method, err)
+	}
+
+	if c.trace != nil {
+		c.trace(true, req)
+	}
+
+	data, err := json.Marshal(c.server.HandleMessage(ctx, req))
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s response: %w", method, err)
+	}
+
+	if c.trace != nil {
+		c.trace(false, data)
+	}
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", method, err)
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s error: %s", method, resp.Error.Message)
+	}
+
+	return resp.Result, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newTestRepl(t *testing.T) (*repl, *bytes.Buffer) {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0",
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("fs_read", mcp.WithDescription("Read a file\nwith details"), mcp.WithString("path"), mcp.WithNumber("limit")),
+		func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.GetArguments()
+			if _, ok := args["limit"].(float64); !ok {
+				return mcp.NewToolResultError("limit is not a number"), nil
+			}
+
+			return mcp.NewToolResultText("read " + req.GetString("path", "")), nil
+		},
+	)
+	mcpServer.AddResource(
+		mcp.NewResource("file:///notes", "notes", mcp.WithMIMEType("text/plain")),
+		func(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, Text: "remember\n"}}, nil
+		},
+	)
+	mcpServer.AddPrompt(
+		mcp.NewPrompt("greet", mcp.WithPromptDescription("Say hello"), mcp.WithArgument("name", mcp.RequiredArgument())),
+		func(_ context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("hello "+req.Params.Arguments["name"])),
+			}), nil
+		},
+	)
+
+	conn := &localConn{server: mcpServer}
+	if err := conn.initialize(t.Context()); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	var out bytes.Buffer
+
+	return &repl{conn: conn, out: &out, format: replFormatText}, &out
+}
+
+func TestReplCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string // Substrings of the output
+	}{
+		{name: "tools", lines: []string{"tools"}, want: []string{"fs_read  Read a file\n"}},
+		{name: "tools filtered out", lines: []string{"tools github"}},
+		{name: "call with pairs", lines: []string{`call fs_read path="my notes.txt" limit=3`}, want: []string{"read my notes.txt\n"}},
+		{name: "call with JSON", lines: []string{`call fs_read {"path": "a", "limit": 1}`}, want: []string{"read a\n"}},
+		{name: "invalid argument", lines: []string{`call fs_read path=a limit=x`}, want: []string{"error: --arg limit"}},
+		{name: "tool error", lines: []string{`call fs_read path=a`}, want: []string{"limit is not a number\nerror: tool returned an error"}},
+		{name: "unknown tool", lines: []string{"call missing"}, want: []string{"error: tools/call error"}},
+		{name: "resources", lines: []string{"resources", "read file:///notes"}, want: []string{"file:///notes  notes  text/plain", "remember\n"}},
+		{name: "prompts", lines: []string{"prompts", "prompt greet name=Ada"}, want: []string{"greet(name)  Say hello", "user: hello Ada\n"}},
+		{name: "json format", lines: []string{"format json", "read file:///notes"}, want: []string{`"text": "remember\n"`}},
+		{name: "trace", lines: []string{"trace on", "raw ping"}, want: []string{`--> {"id":`, `<-- {"jsonrpc":"2.0","id":2,"result":{}}`, "{}\n"}},
+		{name: "comment and unknown", lines: []string{"# note", "frobnicate"}, want: []string{`error: unknown command "frobnicate"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, out := newTestRepl(t)
+
+			input := strings.Join(append(tt.lines, "quit", "tools"), "\n")
+			if err := r.run(t.Context(), strings.NewReader(input), false); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output %q does not contain %q", out.String(), want)
+				}
+			}
+
+			if len(tt.want) == 0 && out.Len() > 0 {
+				t.Errorf("output = %q, want none", out.String())
+			}
+		})
+	}
+}
+
+// pagedConn serves list requests one item per page, the next cursor of
+// each page coming from next (given the page's number, from 1); "" ends
+// the list.
+type pagedConn struct {
+	next  func(page int) string
+	pages int
+}
+
+func (c *pagedConn) SetTrace(func(bool, []byte)) {}
+
+func (c *pagedConn) Request(context.Context, string, any) (json.RawMessage, error) {
+	c.pages++
+
+	return json.Marshal(map[string]any{
+		"tools":      []map[string]any{{"name": fmt.Sprintf("tool%d", c.pages)}},
+		"nextCursor": c.next(c.pages),
+	})
+}
+
+func TestReplListAllCursors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		next      func(page int) string
+		wantItems int
+		wantErr   string
+	}{
+		{name: "last page", next: func(page int) string {
+			if page == 3 {
+				return ""
+			}
+
+			return strconv.Itoa(page)
+		}, wantItems: 3},
+		{name: "repeated cursor", next: func(page int) string { return strconv.Itoa(min(page, 2)) }, wantErr: `cursor "2" again`},
+		{name: "endless", next: strconv.Itoa, wantErr: "more than 1000 pages"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &pagedConn{next: tt.next}
+			r := &repl{conn: conn, out: io.Discard, format: replFormatText}
+
+			items, err := r.listAll(t.Context(), "tools/list", "tools")
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("listAll() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("listAll() error = %v", err)
+			case len(items) != tt.wantItems:
+				t.Errorf("listAll() = %d items, want %d", len(items), tt.wantItems)
+			}
+		})
+	}
+}
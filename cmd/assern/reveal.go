@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/valksor/go-assern/internal/cli"
+	"github.com/valksor/go-assern/internal/disambiguate"
+)
+
+// confirmReveal reports whether secrets may be printed: --reveal must be
+// set, and confirmed at the prompt or with --yes. Without a terminal to ask
+// on, --reveal needs --yes.
+func confirmReveal() (bool, error) {
+	if !revealSecrets {
+		return false, nil
+	}
+
+	if revealYes {
+		return true, nil
+	}
+
+	if !disambiguate.IsInteractive() {
+		return false, errors.New("--reveal needs --yes when not run in a terminal")
+	}
+
+	if err := cli.ConfirmReveal(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
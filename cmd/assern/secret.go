@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/valksor/go-assern/internal/secrets"
+)
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		return errors.New("empty secret; nothing stored")
+	}
+
+	if err := (secrets.KeyringStore{}).Set(name, value); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Stored %s; reference it as keyring://%s\n", name, name)
+
+	return nil
+}
+
+// readSecretValue prompts for a secret without echo, or reads the first
+// line of stdin when it is not a terminal.
+func readSecretValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+
+		if err != nil {
+			return "", fmt.Errorf("reading secret: %w", err)
+		}
+
+		return string(value), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading secret from stdin: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	value, err := (secrets.KeyringStore{}).Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	names, err := (secrets.KeyringStore{}).List()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, "No secrets stored. Add one with 'assern secret set <name>'.")
+
+		return nil
+	}
+
+	for _, name := range names {
+		fmt.Println(name)
+	}
+
+	return nil
+}
+
+func runSecretDelete(cmd *cobra.Command, args []string) error {
+	if err := (secrets.KeyringStore{}).Delete(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Deleted %s\n", args[0])
+
+	return nil
+}
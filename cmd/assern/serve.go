@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
@@ -15,12 +17,16 @@ import (
 	"github.com/valksor/go-assern/internal/instance"
 	"github.com/valksor/go-assern/internal/log"
 	"github.com/valksor/go-assern/internal/project"
-	"github.com/valksor/go-assern/internal/transport"
 )
 
 // setupAggregator initializes and configures the aggregator with common setup.
-// Returns the aggregator, context, logger, and any error encountered.
-func setupAggregator() (*aggregator.Aggregator, context.Context, *slog.Logger, error) {
+// Returns the aggregator, context, logger, and any error encountered. With
+// failsafe set, a configuration that fails to load does not stop setup: the
+// aggregator starts in failsafe mode with default settings and no servers.
+// A non-nil fixed config (--config-stdin) is used as is, and no
+// configuration, project or .env files are read. A non-nil only keeps just
+// the servers it names ('assern call' starts only what the tool needs).
+func setupAggregator(failsafe bool, fixed *config.Config, only []string) (*aggregator.Aggregator, context.Context, *slog.Logger, error) {
 	configureLogger()
 	logger := log.Logger()
 
@@ -29,10 +35,39 @@ func setupAggregator() (*aggregator.Aggregator, context.Context, *slog.Logger, e
 		return nil, nil, nil, fmt.Errorf("getting working directory: %w", err)
 	}
 
-	// Load effective configuration (merges global + local configs)
-	cfg, err := config.LoadEffective(cwd, projectFlag)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("loading config: %w", err)
+	// Detect the project first: a project matched by directory or git
+	// remote only applies when LoadEffective is given its name
+	var projectCtx *project.Context
+
+	projectName := projectFlag
+	if fixed == nil {
+		projectCtx, projectName = resolveProject(cwd, logger)
+	}
+
+	cfg, configErr := fixed, error(nil)
+	if cfg == nil {
+		// Load effective configuration (merges global + local configs)
+		cfg, configErr = config.LoadEffective(cwd, projectName, loadOptions())
+	}
+
+	if configErr != nil {
+		if !failsafe {
+			return nil, nil, nil, fmt.Errorf("loading config: %w", configErr)
+		}
+
+		logger.Error("configuration failed to load; starting in failsafe mode without servers", "error", configErr)
+
+		cfg = config.NewConfig()
+	}
+
+	if only != nil {
+		maps.DeleteFunc(cfg.Servers, func(name string, _ *config.ServerConfig) bool { return !slices.Contains(only, name) })
+	}
+
+	logger = applyLogLevel(cfg, logger)
+
+	if id := config.InstanceID(cfg); id != "" {
+		logger = logger.With("instance_id", id)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Settings.Timeout)
@@ -41,21 +76,41 @@ func setupAggregator() (*aggregator.Aggregator, context.Context, *slog.Logger, e
 	// We attach it to the context so callers can access it if needed
 	ctx = context.WithValue(ctx, cancelKey, cancel)
 
-	envLoader := loadGlobalEnv(logger)
+	envLoader := env.NewLoader()
+
+	var loadEnv func(ctx context.Context) *env.Loader
+
+	if fixed == nil {
+		loadEnv = func(ctx context.Context) *env.Loader {
+			envLoader := loadGlobalEnv(logger)
+			loadEncryptedEnv(ctx, envLoader, cwd, logger)
+			loadProjectEnv(envLoader, cwd, logger)
+
+			return envLoader
+		}
 
-	// Detect project for context (used for logging/display)
-	projectCtx := detectProjectContext(cfg, cwd, logger)
+		envLoader = loadEnv(ctx)
+	}
 
 	// Create aggregator
 	agg, err := aggregator.New(aggregator.Options{
-		Config:       cfg,
-		Project:      projectCtx,
-		EnvLoader:    envLoader,
-		Logger:       logger,
-		Timeout:      cfg.Settings.Timeout,
-		OutputFormat: getOutputFormat(cfg, outputFormat),
-		WorkDir:      cwd,
-		ProjectName:  projectFlag,
+		Config:         cfg,
+		Project:        projectCtx,
+		EnvLoader:      envLoader,
+		Logger:         logger,
+		Timeout:        cfg.Settings.Timeout,
+		OutputFormat:   getOutputFormat(cfg, outputFormat),
+		WorkDir:        cwd,
+		ProjectName:    projectName,
+		LoadOptions:    loadOptions(),
+		Metrics:        newMetricsSink(cfg, logger),
+		Events:         newEventBus(cfg, envLoader, logger),
+		AuditLog:       openAuditLog(cfg, logger),
+		TracerProvider: newTracerProvider(cfg, envLoader, logger),
+		ConfigError:    configErr,
+		FixedConfig:    fixed != nil,
+		LoadEnv:        loadEnv,
+		Secrets:        newSecretsResolver(cfg, logger),
 	})
 	if err != nil {
 		cancel()
@@ -70,6 +125,16 @@ func runServe(cmd *cobra.Command, args []string) error {
 	configureLogger()
 	logger := log.Logger()
 
+	// An ephemeral run neither joins nor offers a shared instance
+	if configStdin {
+		cfg, rest, err := readStdinConfig()
+		if err != nil {
+			return err
+		}
+
+		return runAsPrimary(cfg, rest)
+	}
+
 	// Check for existing instance
 	detector := instance.NewDetector(logger)
 	existing, err := detector.DetectRunning()
@@ -79,6 +144,16 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	if existing != nil {
+		if serveHTTP != "" {
+			logger.Warn("--http ignored: an instance is already running; start it with --http or settings.listen instead",
+				"primary_pid", existing.PID)
+		}
+
+		if profileFlag != "" {
+			logger.Warn("--profile ignored: an instance is already running with its own servers; start it with the profile, or set "+instance.EnvNoSharing+"=1 to run separately",
+				"primary_pid", existing.PID, "profile", profileFlag)
+		}
+
 		// Run as proxy to existing instance
 		logger.Info(
 			"running in PROXY MODE - forwarding to existing instance",
@@ -90,51 +165,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	// Run as primary instance
-	return runAsPrimary(cmd, args, logger)
-}
-
-func runAsPrimary(_ *cobra.Command, _ []string, _ *slog.Logger) error {
-	agg, ctx, logger, err := setupAggregator()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if cancel, ok := ctx.Value(cancelKey).(context.CancelFunc); ok {
-			cancel()
-		}
-	}()
-
-	// Start the aggregator
-	if err := agg.Start(ctx); err != nil {
-		return fmt.Errorf("starting aggregator: %w", err)
-	}
-
-	// Create MCP server
-	mcpServer := agg.CreateMCPServer()
-
-	// Start socket server for instance sharing
-	socketPath, err := config.SocketPath()
-	if err != nil {
-		logger.Warn("failed to get socket path", "error", err)
-	} else {
-		sockServer := instance.NewServer(socketPath, mcpServer, agg, logger)
-		if err := sockServer.Start(); err != nil {
-			logger.Warn("failed to start socket server", "error", err)
-			// Continue without socket - stdio still works
-		} else {
-			defer func() { _ = sockServer.Stop() }()
-		}
-	}
-
-	// Serve stdio (existing transport code)
-	return transport.ServeStdioWithServer(ctx, agg, mcpServer, logger)
-}
-
-func runAsProxy(socketPath string, logger *slog.Logger) error {
-	proxy := instance.NewProxy(socketPath, logger)
-	defer func() { _ = proxy.Close() }()
-
-	return proxy.ServeStdio(context.Background())
+	return runAsPrimary(nil, os.Stdin)
 }
 
 // configPathResolver adapts go-assern config functions to project.PathResolver interface.
@@ -152,8 +183,52 @@ func (r *configPathResolver) FileExists(path string) bool {
 	return config.FileExists(path)
 }
 
-// detectProjectContext creates a project context for logging/display purposes.
-// The actual config merging is done by LoadEffective.
+// loadOptions returns the options --strict-config and --profile (or
+// ASSERN_PROFILE) give configuration loading.
+func loadOptions() config.LoadOptions {
+	return config.LoadOptions{Strict: strictConfig, Profile: profileFlag}
+}
+
+// resolveProject detects the project of cwd and returns it with the name to
+// pass to config.LoadEffective: --project, or the project of the global
+// registry matching cwd by directory or git remote. A project named by the
+// local .assern config is found by LoadEffective itself, so the name is
+// empty then.
+func resolveProject(cwd string, logger *slog.Logger) (*project.Context, string) {
+	globalCfg, err := config.LoadGlobal(loadOptions())
+	if err != nil {
+		// LoadEffective reports the error
+		logger.Debug("loading global config for project detection", "error", err)
+
+		globalCfg = config.NewConfig()
+	}
+
+	projectCtx := detectProjectContext(globalCfg, cwd, logger)
+	if projectCtx != nil && (projectCtx.Source == project.SourceRegistry || projectCtx.Source == project.SourceGitRemote) {
+		return projectCtx, projectCtx.Name
+	}
+
+	return projectCtx, projectFlag
+}
+
+// loadEffectiveConfig loads the effective configuration for cwd, applying
+// the project resolveProject detects.
+func loadEffectiveConfig(cwd string) (*config.Config, error) {
+	_, projectName := resolveProject(cwd, log.Logger())
+
+	return config.LoadEffective(cwd, projectName, loadOptions())
+}
+
+// loadEffectiveTrace is loadEffectiveConfig that also records the source of
+// each value, for 'config show --trace'.
+func loadEffectiveTrace(cwd string) (*config.Config, *config.MergeTrace, error) {
+	_, projectName := resolveProject(cwd, log.Logger())
+
+	return config.LoadEffectiveTrace(cwd, projectName, loadOptions())
+}
+
+// detectProjectContext detects the project of cwd from --project, the local
+// config or the projects of cfg.
 func detectProjectContext(cfg *config.Config, cwd string, logger *slog.Logger) *project.Context {
 	// Create path resolver
 	resolver := &configPathResolver{}
@@ -161,7 +236,12 @@ func detectProjectContext(cfg *config.Config, cwd string, logger *slog.Logger) *
 	// Create registry from config projects
 	registry := project.NewRegistry()
 	for name, proj := range cfg.Projects {
-		registry.Register(name, proj.Directories, nil)
+		registry.Add(&project.RegistryProject{
+			Name:        name,
+			Directories: proj.Directories,
+			Remotes:     proj.Remotes,
+			Priority:    proj.Priority,
+		})
 	}
 
 	// Create detector
@@ -169,7 +249,7 @@ func detectProjectContext(cfg *config.Config, cwd string, logger *slog.Logger) *
 
 	// Set config loader for LocalProjectConfig
 	detector.SetConfigLoader(func(path string) (any, error) {
-		return config.LoadLocalProject(path)
+		return config.LoadLocalProject(path, loadOptions())
 	})
 
 	ctx, err := detector.DetectWithExplicit(cwd, projectFlag)
@@ -182,15 +262,54 @@ func detectProjectContext(cfg *config.Config, cwd string, logger *slog.Logger) *
 	return ctx
 }
 
+// logRing keeps the last log records of this process; a primary instance
+// serves them to 'assern logs'.
+var logRing = log.NewRing(log.RingSize)
+
 func configureLogger() {
-	output := io.Discard
-	if !quiet {
-		output = os.Stderr
-	}
 	log.Configure(log.Options{
-		Output:  output,
+		Output:  logOutput(),
 		Verbose: verbose,
+		Ring:    logRing,
+	})
+
+	// Libraries logging through slog or the log package follow the same
+	// output, so --quiet and --silent silence them too
+	slog.SetDefault(log.Logger())
+}
+
+// logOutput returns where logs are written: stderr, or nowhere with --quiet
+// or --silent.
+func logOutput() io.Writer {
+	if quiet || silent {
+		return io.Discard
+	}
+
+	return os.Stderr
+}
+
+// applyLogLevel reconfigures the logger with the effective settings.log_level
+// (which a project may override) and returns it. --verbose always wins.
+func applyLogLevel(cfg *config.Config, logger *slog.Logger) *slog.Logger {
+	if verbose || cfg.Settings == nil || cfg.Settings.LogLevel == "" {
+		return logger
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Settings.LogLevel)); err != nil {
+		logger.Warn("ignoring invalid log_level", "log_level", cfg.Settings.LogLevel, "error", err)
+
+		return logger
+	}
+
+	log.Configure(log.Options{
+		Output: logOutput(),
+		Level:  level,
+		Ring:   logRing,
 	})
+	slog.SetDefault(log.Logger())
+
+	return log.Logger()
 }
 
 // getOutputFormat determines the output format from flag, env var, and config.
@@ -225,3 +344,50 @@ func loadGlobalEnv(logger *slog.Logger) *env.Loader {
 
 	return envLoader
 }
+
+// loadEncryptedEnv decrypts age/sops env files from the global directory and
+// the local .assern directory into the global and project env layers.
+// Decryption failures are logged rather than fatal so one bad key does not
+// keep unrelated servers from starting.
+func loadEncryptedEnv(ctx context.Context, envLoader *env.Loader, cwd string, logger *slog.Logger) {
+	scopes := make(map[string]string, 2)
+
+	if globalDir, err := config.GlobalDir(); err == nil {
+		scopes["global"] = globalDir
+	}
+
+	if localDir := config.FindLocalConfigDir(cwd); localDir != "" {
+		scopes["project"] = localDir
+	}
+
+	for layer, dir := range scopes {
+		loaded, err := envLoader.LoadEncryptedDir(ctx, dir, layer)
+		for _, path := range loaded {
+			logger.Debug("loaded encrypted env file", "path", path, "layer", layer)
+		}
+
+		if err != nil {
+			logger.Warn("failed to decrypt env file", "layer", layer, "error", err)
+		}
+	}
+}
+
+// loadProjectEnv loads the .env and .env.local files of the local .assern
+// directory into the project env layer, after the encrypted env files so
+// that a developer's .env.local can override committed values. Parse
+// failures are logged rather than fatal, as for encrypted files.
+func loadProjectEnv(envLoader *env.Loader, cwd string, logger *slog.Logger) {
+	localDir := config.FindLocalConfigDir(cwd)
+	if localDir == "" {
+		return
+	}
+
+	loaded, err := envLoader.LoadDotenvDir(localDir, "project")
+	for _, path := range loaded {
+		logger.Debug("loaded project env file", "path", path)
+	}
+
+	if err != nil {
+		logger.Warn("failed to load project env file", "error", err)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/proctitle"
+	"github.com/valksor/go-assern/internal/transport"
+	"github.com/valksor/go-assern/internal/webui"
+)
+
+// runAsPrimary serves the aggregator, reading stdio client messages from in.
+// With a fixed config (--config-stdin) no instance-sharing socket is opened.
+func runAsPrimary(fixed *config.Config, in io.Reader) error {
+	agg, ctx, logger, err := setupAggregator(true, fixed, nil)
+	if err != nil {
+		return err
+	}
+	defer agg.Events().Close()
+	defer func() { _ = agg.AuditLog().Close() }()
+	defer shutdownTracer(agg.TracerProvider(), logger)
+
+	if fixed != nil {
+		setProcessTitle(proctitle.RoleStdin, "", logger)
+	} else {
+		setProcessTitle(proctitle.RolePrimary, agg.ProjectName(), logger)
+	}
+	defer func() {
+		if cancel, ok := ctx.Value(cancelKey).(context.CancelFunc); ok {
+			cancel()
+		}
+	}()
+
+	// Start the aggregator
+	if err := agg.Start(ctx); err != nil {
+		return fmt.Errorf("starting aggregator: %w", err)
+	}
+
+	// Create MCP server
+	mcpServer := agg.CreateMCPServer()
+
+	// Start socket server for instance sharing
+	socketPath, err := config.SocketPath()
+	if err != nil {
+		logger.Warn("failed to get socket path", "error", err)
+	} else if fixed == nil {
+		sockServer := instance.NewServer(socketPath, mcpServer, agg, logger)
+		sockServer.SetLimits(instance.LimitsFromConfig(agg.SocketConfig()))
+		sockServer.SetAllowedUIDs(instance.AllowedUIDsFromConfig(agg.SocketConfig()))
+		sockServer.SetLogRing(logRing)
+		if err := sockServer.Start(); err != nil {
+			logger.Warn("failed to start socket server", "error", err)
+			// Continue without socket - stdio still works
+		} else {
+			defer func() { _ = sockServer.Stop() }()
+		}
+	}
+
+	// Reload on configuration file changes (settings.watch_config)
+	if agg.WatchConfigEnabled() {
+		watchCtx, stopWatch := context.WithCancel(context.Background())
+		defer stopWatch()
+
+		go func() {
+			if err := agg.WatchConfig(watchCtx); err != nil {
+				logger.Warn("not watching configuration files", "error", err)
+			}
+		}()
+	}
+
+	// Serve over HTTP as well when a listen address is set
+	listen := serveHTTP
+	if listen == "" {
+		listen = agg.ListenAddress()
+	}
+
+	var httpServer *transport.HTTPServer
+
+	if listen != "" {
+		if err := config.ValidateListen(listen); err != nil {
+			return err
+		}
+
+		httpServer = transport.NewHTTPServer(listen, mcpServer, logger)
+		httpServer.SetMaxSessions(instance.LimitsFromConfig(agg.SocketConfig()).MaxSessions)
+		httpServer.SetAuthenticator(agg)
+
+		if ids := agg.SessionIDs(); ids != nil {
+			httpServer.SetSessionIDs(ids)
+		}
+
+		if err := httpServer.Start(); err != nil {
+			return fmt.Errorf("starting HTTP server: %w", err)
+		}
+		defer func() { _ = httpServer.Stop() }()
+	}
+
+	// Serve the read-only status page (settings.web_ui)
+	if webUI := agg.WebUIConfig(); webUI.IsEnabled() {
+		if ui := startWebUI(webUI, agg, logger); ui != nil {
+			defer func() { _ = ui.Stop() }()
+		}
+	}
+
+	// Serve stdio (existing transport code)
+	if err := transport.ServeStdioFrom(ctx, agg, mcpServer, in, logger); err != nil || httpServer == nil {
+		return err
+	}
+
+	// Without a stdio client, keep serving HTTP until interrupted
+	logger.Info("stdio closed, still serving HTTP", "address", httpServer.Addr())
+
+	return httpServer.Wait()
+}
+
+// startWebUI serves the web UI configured by cfg, or logs why it cannot.
+// Without a configured token a new one is generated; the URL to open is
+// logged with it.
+func startWebUI(cfg *config.WebUIConfig, agg *aggregator.Aggregator, logger *slog.Logger) *webui.Server {
+	token := cfg.Token
+	generated := token == ""
+
+	if generated {
+		token = webui.NewToken()
+	}
+
+	ui := webui.NewServer(cfg.EffectiveListen(), token, agg, logger)
+	if err := ui.Start(); err != nil {
+		logger.Warn("failed to start web UI", "error", err)
+
+		return nil
+	}
+
+	if generated {
+		logger.Info("serving web UI", "url", ui.URL())
+	} else {
+		logger.Info("serving web UI", "address", "http://"+ui.Addr()+webui.PagePath)
+	}
+
+	return ui
+}
+
+func runAsProxy(socketPath string, logger *slog.Logger) error {
+	setProcessTitle(proctitle.RoleProxy, "", logger)
+
+	proxy := instance.NewProxy(socketPath, logger)
+	defer func() { _ = proxy.Close() }()
+
+	return proxy.ServeStdio(context.Background())
+}
+
+// setProcessTitle names the process after its role and project so it can be
+// found with ps. Failing to is not worth more than a debug line.
+func setProcessTitle(role, project string, logger *slog.Logger) {
+	applied, err := proctitle.Set(role, project)
+	if err != nil {
+		logger.Debug("failed to set process title", "error", err)
+
+		return
+	}
+
+	if applied {
+		logger.Debug("process title set", "title", proctitle.Title(role, project), "name", proctitle.Name(role, project))
+	}
+}
+
+// readStdinConfig reads the mcp.json document given on stdin with
+// --config-stdin. The returned reader yields the input that follows it.
+func readStdinConfig() (*config.Config, io.Reader, error) {
+	mcpCfg, rest, err := config.ReadMCPConfig(os.Stdin, loadOptions())
+	if err != nil {
+		return nil, nil, fmt.Errorf("--config-stdin: %w", err)
+	}
+
+	return config.NewEphemeralConfig(mcpCfg), rest, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/audit"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+	"github.com/valksor/go-assern/internal/events"
+	"github.com/valksor/go-assern/internal/metrics"
+	"github.com/valksor/go-assern/internal/redact"
+	"github.com/valksor/go-assern/internal/secrets"
+	"github.com/valksor/go-assern/internal/tracing"
+)
+
+// newMetricsSink creates the configured metrics exporter, or nil when none is
+// enabled. A nil interface (not a typed nil) is returned so the aggregator's
+// nil check disables reporting.
+func newMetricsSink(cfg *config.Config, logger *slog.Logger) aggregator.Metrics {
+	if cfg.Settings == nil || !cfg.Settings.Metrics.StatsDEnabled() {
+		return nil
+	}
+
+	sink, err := metrics.NewStatsD(cfg.Settings.Metrics.StatsD)
+	if err != nil {
+		logger.Warn("failed to start statsd exporter", "error", err)
+
+		return nil
+	}
+
+	logger.Debug("statsd exporter enabled", "address", cfg.Settings.Metrics.StatsD.EffectiveAddress())
+
+	return sink
+}
+
+// newEventBus creates the event bus for the configured sinks, or nil when
+// none are configured. Webhook URLs and headers may reference ${VAR}s from the
+// loaded environment.
+func newEventBus(cfg *config.Config, envLoader *env.Loader, logger *slog.Logger) *events.Bus {
+	if cfg.Settings == nil {
+		return nil
+	}
+
+	return events.NewBus(cfg.Settings.Events, envLoader.Expand, logger)
+}
+
+// newSecretsResolver resolves secret references in server env values from
+// the configured store. Settings were validated on load, so a bad store
+// only happens with settings built elsewhere; references then stay as is.
+func newSecretsResolver(cfg *config.Config, logger *slog.Logger) *secrets.Resolver {
+	kind := ""
+	if cfg.Settings != nil {
+		kind = cfg.Settings.SecretsStore
+	}
+
+	store, err := secrets.NewStore(kind)
+	if err != nil {
+		logger.Warn("secret references will not be resolved", "error", err)
+
+		return nil
+	}
+
+	return secrets.NewResolver(store)
+}
+
+// openAuditLog opens the configured audit log, or returns nil when none is
+// configured or it cannot be opened; serving continues without it.
+func openAuditLog(cfg *config.Config, logger *slog.Logger) *audit.Log {
+	if cfg.Settings == nil || !cfg.Settings.AuditLog.IsEnabled() {
+		return nil
+	}
+
+	auditLog, err := audit.Open(cfg.Settings.AuditLog)
+	if err != nil {
+		logger.Warn("failed to open audit log", "error", err)
+
+		return nil
+	}
+
+	logger.Debug("audit log enabled", "path", cfg.Settings.AuditLog.EffectivePath())
+
+	return auditLog
+}
+
+// newTracerProvider creates the OpenTelemetry tracer provider exporting to
+// the collector of settings.otel, or returns nil when tracing is off. A nil
+// interface (not a typed nil) is returned so the aggregator's nil check
+// disables tracing. Header values may reference ${VAR}s from the loaded
+// environment.
+func newTracerProvider(cfg *config.Config, envLoader *env.Loader, logger *slog.Logger) trace.TracerProvider {
+	if cfg.Settings == nil || !cfg.Settings.OTel.IsEnabled() {
+		return nil
+	}
+
+	otelCfg := cfg.Settings.OTel.Clone()
+	otelCfg.Headers = envLoader.ExpandMap(otelCfg.Headers)
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(otelCfg.TracesURL()),
+		otlptracehttp.WithHeaders(otelCfg.Headers),
+	)
+	if err != nil {
+		logger.Warn("failed to start opentelemetry exporter", "error", err)
+
+		return nil
+	}
+
+	// Export failures are not worth more than a debug line: tool calls go
+	// on whether or not the collector is reachable
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Debug("exporting spans failed", "url", redact.URL(otelCfg.TracesURL()), "error", err)
+	}))
+
+	logger.Debug("opentelemetry tracing enabled",
+		"endpoint", otelCfg.TracesURL(),
+		"sampling", otelCfg.EffectiveSampling(),
+	)
+
+	// Traces continued from a client keep the client's sampling decision
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(tracing.Resource(otelCfg.EffectiveServiceName())),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(otelCfg.EffectiveSampling()))),
+	)
+}
+
+// shutdownTracer exports the spans still buffered, waiting a few seconds at
+// most for the collector.
+func shutdownTracer(provider trace.TracerProvider, logger *slog.Logger) {
+	sdk, ok := provider.(*sdktrace.TracerProvider)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := sdk.Shutdown(ctx); err != nil {
+		logger.Warn("failed to export remaining spans", "error", err)
+	}
+}
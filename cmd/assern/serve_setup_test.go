@@ -0,0 +1,99 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		exported []*coltracepb.ExportTraceServiceRequest
+		apiKey   string
+	)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.URL.Path != config.OTLPTracesPath {
+			http.Error(w, "bad request", http.StatusBadRequest)
+
+			return
+		}
+
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		exported = append(exported, &req)
+		apiKey = r.Header.Get("X-Api-Key")
+	}))
+	defer collector.Close()
+
+	logger := slog.New(slog.DiscardHandler)
+
+	if tp := newTracerProvider(config.NewConfig(), env.NewLoader(), logger); tp != nil {
+		t.Errorf("newTracerProvider() without settings.otel = %v, want nil", tp)
+	}
+
+	envLoader := env.NewLoader()
+	envLoader.Set("project", "API_KEY", "secret")
+
+	cfg := config.NewConfig()
+	cfg.Settings.OTel = &config.OTelConfig{
+		Endpoint:    collector.URL,
+		Headers:     map[string]string{"X-Api-Key": "${API_KEY}"},
+		ServiceName: "gateway",
+	}
+
+	tp := newTracerProvider(cfg, envLoader, logger)
+	if tp == nil {
+		t.Fatal("newTracerProvider() = nil, want a provider")
+	}
+
+	_, span := tp.Tracer("test").Start(t.Context(), "tools/call github_search")
+	span.End()
+
+	shutdownTracer(tp, logger)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(exported) != 1 || apiKey != "secret" {
+		t.Fatalf("exported %d requests with API key %q, want 1 with the expanded key", len(exported), apiKey)
+	}
+
+	rs := exported[0].GetResourceSpans()[0]
+
+	var service string
+	for _, kv := range rs.GetResource().GetAttributes() {
+		if kv.GetKey() == "service.name" {
+			service = kv.GetValue().GetStringValue()
+		}
+	}
+
+	if service != "gateway" {
+		t.Errorf("service.name = %q, want gateway", service)
+	}
+
+	if spans := rs.GetScopeSpans()[0].GetSpans(); len(spans) != 1 || spans[0].GetName() != "tools/call github_search" {
+		t.Errorf("spans = %v, want the tools/call span", spans)
+	}
+}
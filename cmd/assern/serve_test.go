@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/project"
+)
+
+func TestLoadEffectiveConfigRemoteProject(t *testing.T) {
+	// Not parallel - modifies global homeDirFunc
+	repo := remoteProjectFixture(t)
+
+	projectCtx, projectName := resolveProject(repo, slog.New(slog.DiscardHandler))
+	if projectCtx == nil || projectCtx.Source != project.SourceGitRemote || projectName != "acme" {
+		t.Fatalf("resolveProject() = %+v, %q, want acme from git remote", projectCtx, projectName)
+	}
+
+	cfg, err := loadEffectiveConfig(repo)
+	if err != nil {
+		t.Fatalf("loadEffectiveConfig() error = %v", err)
+	}
+
+	server := cfg.Servers["github"]
+	if server == nil {
+		t.Fatal("server github missing")
+	}
+
+	if want := []string{"--org", "acme"}; !slices.Equal(server.Args, want) {
+		t.Errorf("github args = %v, want project override %v", server.Args, want)
+	}
+}
+
+func TestConfigShowTraceRemoteProject(t *testing.T) {
+	// Not parallel - modifies global homeDirFunc, the flags and the working directory
+	repo := remoteProjectFixture(t)
+	t.Chdir(repo)
+
+	oldEffective, oldTrace := showEffective, showTrace
+	showEffective, showTrace = true, true
+
+	defer func() { showEffective, showTrace = oldEffective, oldTrace }()
+
+	var out bytes.Buffer
+	configShowCmd.SetOut(&out)
+
+	defer configShowCmd.SetOut(nil)
+
+	if err := runConfigShow(configShowCmd, nil); err != nil {
+		t.Fatalf("config show --effective --trace: %v", err)
+	}
+
+	for _, want := range []string{"project: acme", "- acme", "project acme"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("config show output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+// remoteProjectFixture sets up a home whose global config has project acme
+// matched by git remote, overriding the args of server github, and returns
+// a repository whose origin is an acme remote.
+func remoteProjectFixture(t *testing.T) string {
+	t.Helper()
+
+	tmpHome := t.TempDir()
+	t.Cleanup(config.SetHomeDirForTesting(tmpHome))
+
+	globalDir := filepath.Join(tmpHome, ".valksor", "assern")
+	if err := os.MkdirAll(globalDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	globalMCP := `{"mcpServers": {"github": {"command": "gh-mcp"}}}`
+	if err := os.WriteFile(filepath.Join(globalDir, "mcp.json"), []byte(globalMCP), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	globalConfig := `projects:
+  acme:
+    remotes: [github.com/acme/*]
+    servers:
+      github:
+        args: [--org, acme]
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	gitConfig := "[remote \"origin\"]\n\turl = git@github.com:acme/api.git\n"
+	if err := os.WriteFile(filepath.Join(repo, ".git", "config"), []byte(gitConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
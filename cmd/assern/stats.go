@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func runStats(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	detector := instance.NewDetector(logger)
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return fmt.Errorf("detecting instance: %w", err)
+	}
+
+	if existing == nil {
+		return errors.New("no running assern instance found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+	defer cancel()
+
+	stats, err := instance.QueryStats(ctx, existing.SocketPath)
+	if err != nil {
+		return fmt.Errorf("querying stats: %w", err)
+	}
+
+	if statsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(stats)
+	}
+
+	printStats(stats)
+
+	return nil
+}
+
+// printStats prints per-server call counts, then each deprecated tool that
+// was called with the callers that still use it.
+func printStats(stats *aggregator.CallStats) {
+	fmt.Println("Calls:")
+
+	if len(stats.Servers) == 0 {
+		fmt.Println("  (no tool calls yet)")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		_, _ = fmt.Fprintln(w, "  SERVER\tCALLS\tFAILURES\tFAILURE RATE")
+
+		for _, s := range stats.Servers {
+			_, _ = fmt.Fprintf(w, "  %s\t%d\t%d\t%.1f%%\n", s.Name, s.Calls, s.Failures, s.FailRate)
+		}
+
+		_ = w.Flush()
+	}
+
+	fmt.Println()
+	fmt.Println("Deprecated tools:")
+
+	if len(stats.Deprecated) == 0 {
+		fmt.Println("  (none called)")
+
+		return
+	}
+
+	for _, u := range stats.Deprecated {
+		fmt.Printf("  %s: %d calls, last %s ago (%s)\n",
+			u.Tool, u.Calls, time.Since(u.LastCall).Round(time.Second), u.Note)
+
+		for _, caller := range slices.Sorted(maps.Keys(u.Callers)) {
+			fmt.Printf("    - %-30s %d\n", caller, u.Callers[caller])
+		}
+	}
+}
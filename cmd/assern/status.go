@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/instance"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	configureLogger()
+	logger := log.Logger()
+
+	detector := instance.NewDetector(logger)
+	existing, err := detector.DetectRunning()
+	if err != nil {
+		return fmt.Errorf("detecting instance: %w", err)
+	}
+
+	if existing == nil {
+		return errors.New("no running assern instance found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), instance.ClientTimeout)
+	defer cancel()
+
+	status, err := instance.QueryStatus(ctx, existing.SocketPath)
+	if err != nil {
+		return fmt.Errorf("querying status: %w", err)
+	}
+
+	if statusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(status)
+	}
+
+	printStatus(status, existing.PID)
+
+	return nil
+}
+
+// printStatus prints the instance summary followed by one line per server.
+func printStatus(status *aggregator.Status, pid int) {
+	fmt.Printf("Instance: %s (pid %d, version %s)\n", status.Instance, pid, status.Version)
+
+	if status.InstanceID != "" {
+		fmt.Printf("Instance ID: %s\n", status.InstanceID)
+	}
+
+	project := "(none)"
+	if status.Project != "" {
+		project = status.Project
+		if status.Detection != "" {
+			project += " (" + status.Detection + ")"
+		}
+
+		if status.ProjectDir != "" {
+			project += " " + status.ProjectDir
+		}
+	}
+
+	fmt.Printf("Project:  %s\n", project)
+	fmt.Printf("Config:   fingerprint %s\n", status.Fingerprint)
+
+	if status.ConfigError != "" {
+		fmt.Printf("          FAILSAFE MODE, config failed to load: %s\n", status.ConfigError)
+		fmt.Println("          Fix it ('assern config edit'), then run 'assern reload'.")
+	}
+
+	reload := "never reloaded"
+	if status.LastReload != nil {
+		reload = "last reload " + status.LastReload.Local().Format(time.DateTime)
+	}
+
+	fmt.Printf("Uptime:   %s (%s)\n", status.Uptime, reload)
+	fmt.Printf("Servers:  %d up, %d down; %d tools, %d resources, %d prompts\n",
+		status.ServersUp, status.ServersDown, status.Tools, status.Resources, status.Prompts)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "  SERVER\tSTATE\tUPTIME\tTOOLS\tRESOURCES\tPROMPTS\tLAST ERROR")
+
+	for _, s := range status.Servers {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			s.Name, serverState(s), orDash(s.Uptime), s.Tools, s.Resources, s.Prompts, lastError(s))
+	}
+
+	_ = w.Flush()
+
+	printClients(status.Clients)
+
+	for _, s := range status.Servers {
+		if s.AuthURL != "" {
+			fmt.Printf("\nAuthorize %s at:\n  %s\n", s.Name, s.AuthURL)
+		}
+	}
+}
+
+// printClients prints one line per connected client session.
+func printClients(clients []aggregator.ClientStatus) {
+	if len(clients) == 0 {
+		return
+	}
+
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "  CLIENT\tVERSION\tPROTOCOL\tCAPABILITIES\tSESSION")
+
+	for _, c := range clients {
+		caps := "-"
+		if len(c.Capabilities) > 0 {
+			caps = strings.Join(c.Capabilities, ", ")
+		}
+
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n",
+			orDash(c.Name), orDash(c.Version), orDash(c.Protocol), caps, c.Session)
+	}
+
+	_ = w.Flush()
+}
+
+// serverState names a server's state for people: running, restarting,
+// idle (lazy), needs auth, or failed.
+func serverState(s aggregator.ServerStatus) string {
+	switch {
+	case s.State == aggregator.ServerStateUp:
+		return "running"
+	case s.State == aggregator.ServerStateRestarting:
+		return "restarting"
+	case s.State == aggregator.ServerStateIdle:
+		return "idle (lazy)"
+	case s.Health == aggregator.HealthNeedsAuth:
+		return "needs auth"
+	default:
+		return "failed"
+	}
+}
+
+// lastError renders a server's last error with how long ago it happened.
+func lastError(s aggregator.ServerStatus) string {
+	if s.LastError == "" {
+		return "-"
+	}
+
+	if s.LastErrorAt.IsZero() {
+		return s.LastError
+	}
+
+	return fmt.Sprintf("%s (%s ago)", s.LastError, time.Since(s.LastErrorAt).Round(time.Second))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,222 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// identityKey is the context key of the ACL token identity of a request.
+type identityKey struct{}
+
+// WithIdentity returns a context for requests made with the settings.acl
+// token named identity. The HTTP transport sets it once it authenticated
+// the request; tool listing and calls are then limited to what the token
+// may use.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// identity returns the ACL token identity set by WithIdentity, or "".
+func identity(ctx context.Context) string {
+	name, _ := ctx.Value(identityKey{}).(string)
+
+	return name
+}
+
+// ACLEnabled reports whether settings.acl configures any token, so HTTP
+// requests must authenticate.
+func (a *Aggregator) ACLEnabled() bool {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	return a.cfg != nil && a.cfg.Settings != nil && a.cfg.Settings.ACL.IsEnabled()
+}
+
+// Authenticate returns the name of the settings.acl token matching the
+// bearer token presented by an HTTP client; false when none matches. Token
+// values are compared after expanding ${VAR} references, in constant time.
+func (a *Aggregator) Authenticate(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil || a.cfg.Settings.ACL == nil {
+		return "", false
+	}
+
+	for _, t := range a.cfg.Settings.ACL.Tokens {
+		value := t.Token
+		if a.envLoader != nil {
+			value = a.envLoader.Expand(value)
+		}
+
+		if value != "" && subtle.ConstantTimeCompare([]byte(value), []byte(token)) == 1 {
+			return t.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// aclToken returns the ACL token of the request in ctx, and false when the
+// request carries no identity and is not restricted. A token removed by a
+// reload allows nothing.
+func (a *Aggregator) aclToken(ctx context.Context) (*config.ACLToken, bool) {
+	name := identity(ctx)
+	if name == "" {
+		return nil, false
+	}
+
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return nil, true
+	}
+
+	return a.cfg.Settings.ACL.Lookup(name), true
+}
+
+// aclAllows reports whether the request in ctx may call entry.
+func (a *Aggregator) aclAllows(ctx context.Context, entry *ToolEntry) bool {
+	token, restricted := a.aclToken(ctx)
+	if !restricted {
+		return true
+	}
+
+	return token.Allows(entry.ServerName, entry.Tool.Name)
+}
+
+// aclDenied publishes policy_blocked for a call the request's token may not
+// make and returns the error to report.
+func (a *Aggregator) aclDenied(ctx context.Context, entry *ToolEntry) error {
+	a.logger.Warn("tool call denied by acl", "identity", identity(ctx), "tool", entry.PrefixedName)
+	a.publish(events.PolicyBlocked, entry.ServerName, "acl denies "+identity(ctx)+" calling "+entry.PrefixedName, map[string]any{
+		"tool":     entry.PrefixedName,
+		"identity": identity(ctx),
+	})
+
+	return fmt.Errorf("%w: %s", ErrAccessDenied, entry.PrefixedName)
+}
+
+// deniedResult is the tool result of a call refused by the ACL.
+func deniedResult(err error, server string) *mcp.CallToolResult {
+	data := map[string]any{
+		"error":   "access_denied",
+		"server":  server,
+		"message": err.Error(),
+	}
+
+	result := mcp.NewToolResultStructured(data, err.Error())
+	result.IsError = true
+
+	return result
+}
+
+// filterACLTools is a tools/list filter leaving out the tools the request's
+// ACL token may not call.
+func (a *Aggregator) filterACLTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	token, restricted := a.aclToken(ctx)
+	if !restricted {
+		return tools
+	}
+
+	filtered := make([]mcp.Tool, 0, len(tools))
+
+	for _, tool := range tools {
+		entry, ok := a.tools.Get(tool.Name)
+		if ok && !token.Allows(entry.ServerName, entry.Tool.Name) {
+			continue
+		}
+
+		filtered = append(filtered, tool)
+	}
+
+	return filtered
+}
+
+// aclAllowsServer reports whether the request in ctx may list and read the
+// resources and get the prompts of server.
+func (a *Aggregator) aclAllowsServer(ctx context.Context, server string) bool {
+	token, restricted := a.aclToken(ctx)
+
+	return !restricted || token.AllowsServer(server)
+}
+
+// aclDeniedServer publishes policy_blocked for a resource read or prompt get
+// (kind) the request's token may not make and returns the error to report.
+func (a *Aggregator) aclDeniedServer(ctx context.Context, server, kind, name string) error {
+	a.logger.Warn(kind+" denied by acl", "identity", identity(ctx), "server", server, "name", name)
+	a.publish(events.PolicyBlocked, server, "acl denies "+identity(ctx)+" the "+kind+" "+name, map[string]any{
+		kind:       name,
+		"identity": identity(ctx),
+	})
+
+	return fmt.Errorf("%w: %s", ErrAccessDenied, name)
+}
+
+// addACLHooks leaves out of resources/list and resources/templates/list the
+// resources of servers the request's ACL token may not use. They are added
+// before the pagination hooks, so pages are cut from the filtered lists.
+func (a *Aggregator) addACLHooks(hooks *server.Hooks) {
+	hooks.AddAfterListResources(func(ctx context.Context, _ any, _ *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+		if _, restricted := a.aclToken(ctx); !restricted {
+			return
+		}
+
+		result.Resources = slices.DeleteFunc(result.Resources, func(r mcp.Resource) bool {
+			entry, ok := a.resources.Get(r.URI)
+
+			return ok && !a.aclAllowsServer(ctx, entry.ServerName)
+		})
+	})
+
+	hooks.AddAfterListResourceTemplates(func(ctx context.Context, _ any, _ *mcp.ListResourceTemplatesRequest, result *mcp.ListResourceTemplatesResult) {
+		if _, restricted := a.aclToken(ctx); !restricted {
+			return
+		}
+
+		result.ResourceTemplates = slices.DeleteFunc(result.ResourceTemplates, func(t mcp.ResourceTemplate) bool {
+			if t.URITemplate == nil {
+				return false
+			}
+
+			name, ok := a.links.server(t.URITemplate.Raw())
+
+			return ok && !a.aclAllowsServer(ctx, name)
+		})
+	})
+}
+
+// filterACLPrompts is a prompts/list filter leaving out the prompts of
+// servers the request's ACL token may not use.
+func (a *Aggregator) filterACLPrompts(ctx context.Context, prompts []mcp.Prompt) []mcp.Prompt {
+	token, restricted := a.aclToken(ctx)
+	if !restricted {
+		return prompts
+	}
+
+	filtered := make([]mcp.Prompt, 0, len(prompts))
+
+	for _, prompt := range prompts {
+		entry, ok := a.prompts.Get(prompt.Name)
+		if ok && !token.AllowsServer(entry.ServerName) {
+			continue
+		}
+
+		filtered = append(filtered, prompt)
+	}
+
+	return filtered
+}
@@ -0,0 +1,199 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestACLResourcesAndPrompts(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.ACL = &config.ACLConfig{Tokens: []config.ACLToken{
+		{Name: "alice", Token: "a"},
+		{Name: "ci", Token: "c", Servers: []string{"github"}},
+	}}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, name := range []string{"github", "jira"} {
+		backend := testutil.NewMockServer(name, nil)
+		backend.Resources = []mcp.Resource{mcp.NewResource("file:///"+name+".md", name+" readme")}
+		backend.Prompts = []mcp.Prompt{mcp.NewPrompt("summary")}
+
+		if err := agg.AddServer(t.Context(), backend); err != nil {
+			t.Fatalf("AddServer(%s): %v", name, err)
+		}
+	}
+
+	mcpServer := agg.CreateMCPServer()
+	agg.registerLinkTemplate("github")
+	agg.registerLinkTemplate("jira")
+
+	sess := newFakeSession("acl-1")
+	registerSession(t, mcpServer, sess)
+
+	tests := []struct {
+		identity      string
+		wantResources []string
+		wantTemplates []string
+		wantPrompts   []string
+		wantDenied    bool // whether jira's resource and prompt are refused
+	}{
+		{
+			identity:      "",
+			wantResources: []string{"assern://github/file:///github.md", "assern://jira/file:///jira.md"},
+			wantTemplates: []string{linkTemplate("github"), linkTemplate("jira")},
+			wantPrompts:   []string{"github_summary", "jira_summary"},
+		},
+		{
+			identity:      "alice",
+			wantResources: []string{"assern://github/file:///github.md", "assern://jira/file:///jira.md"},
+			wantTemplates: []string{linkTemplate("github"), linkTemplate("jira")},
+			wantPrompts:   []string{"github_summary", "jira_summary"},
+		},
+		{
+			identity:      "ci",
+			wantResources: []string{"assern://github/file:///github.md"},
+			wantTemplates: []string{linkTemplate("github")},
+			wantPrompts:   []string{"github_summary"},
+			wantDenied:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		ctx := t.Context()
+		if tt.identity != "" {
+			ctx = WithIdentity(ctx, tt.identity)
+		}
+
+		ctx = mcpServer.WithContext(ctx, sess)
+
+		var resources struct {
+			Resources []mcp.Resource `json:"resources"`
+		}
+		aclRequest(t, ctx, mcpServer, "resources/list", nil, &resources)
+
+		if got := resourceURIs(resources.Resources); !slices.Equal(got, tt.wantResources) {
+			t.Errorf("%q: resources/list = %v, want %v", tt.identity, got, tt.wantResources)
+		}
+
+		var templates struct {
+			ResourceTemplates []struct {
+				URITemplate string `json:"uriTemplate"`
+			} `json:"resourceTemplates"`
+		}
+		aclRequest(t, ctx, mcpServer, "resources/templates/list", nil, &templates)
+
+		var gotTemplates []string
+
+		for _, tmpl := range templates.ResourceTemplates {
+			if tmpl.URITemplate != BlobURIPrefix+"{id}" {
+				gotTemplates = append(gotTemplates, tmpl.URITemplate)
+			}
+		}
+
+		slices.Sort(gotTemplates)
+
+		if !slices.Equal(gotTemplates, tt.wantTemplates) {
+			t.Errorf("%q: resources/templates/list = %v, want %v", tt.identity, gotTemplates, tt.wantTemplates)
+		}
+
+		var prompts struct {
+			Prompts []mcp.Prompt `json:"prompts"`
+		}
+		aclRequest(t, ctx, mcpServer, "prompts/list", nil, &prompts)
+
+		var gotPrompts []string
+		for _, p := range prompts.Prompts {
+			gotPrompts = append(gotPrompts, p.Name)
+		}
+
+		slices.Sort(gotPrompts)
+
+		if !slices.Equal(gotPrompts, tt.wantPrompts) {
+			t.Errorf("%q: prompts/list = %v, want %v", tt.identity, gotPrompts, tt.wantPrompts)
+		}
+
+		for _, req := range []struct {
+			method string
+			params map[string]any
+		}{
+			{"resources/read", map[string]any{"uri": "assern://jira/file:///jira.md"}},
+			{"resources/read", map[string]any{"uri": "assern://jira/file:///created.md"}},
+			{"prompts/get", map[string]any{"name": "jira_summary"}},
+		} {
+			if denied := aclRequest(t, ctx, mcpServer, req.method, req.params, nil); denied != tt.wantDenied {
+				t.Errorf("%q: %s %v denied = %v, want %v", tt.identity, req.method, req.params, denied, tt.wantDenied)
+			}
+		}
+
+		for _, req := range []struct {
+			method string
+			params map[string]any
+		}{
+			{"resources/read", map[string]any{"uri": "assern://github/file:///github.md"}},
+			{"prompts/get", map[string]any{"name": "github_summary"}},
+		} {
+			if aclRequest(t, ctx, mcpServer, req.method, req.params, nil) {
+				t.Errorf("%q: %s %v denied, want allowed", tt.identity, req.method, req.params)
+			}
+		}
+	}
+}
+
+// aclRequest sends method through srv, decoding a successful result into
+// result, and reports whether the request failed.
+func aclRequest(t *testing.T, ctx context.Context, srv *server.MCPServer, method string, params map[string]any, result any) bool {
+	t.Helper()
+
+	if params == nil {
+		params = map[string]any{}
+	}
+
+	raw, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		t.Fatalf("marshal %s: %v", method, err)
+	}
+
+	resp, ok := srv.HandleMessage(ctx, raw).(mcp.JSONRPCResponse)
+	if !ok {
+		return true
+	}
+
+	if result != nil {
+		data, err := json.Marshal(resp.Result)
+		if err != nil {
+			t.Fatalf("marshal %s result: %v", method, err)
+		}
+
+		if err := json.Unmarshal(data, result); err != nil {
+			t.Fatalf("unmarshal %s result: %v", method, err)
+		}
+	}
+
+	return false
+}
+
+func resourceURIs(resources []mcp.Resource) []string {
+	uris := make([]string, 0, len(resources))
+	for _, r := range resources {
+		uris = append(uris, r.URI)
+	}
+
+	slices.Sort(uris)
+
+	return uris
+}
@@ -9,37 +9,98 @@ import (
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/valksor/go-assern/internal/audit"
+	"github.com/valksor/go-assern/internal/clock"
 	"github.com/valksor/go-assern/internal/config"
 	"github.com/valksor/go-assern/internal/env"
+	"github.com/valksor/go-assern/internal/events"
 	"github.com/valksor/go-assern/internal/project"
+	"github.com/valksor/go-assern/internal/secrets"
 )
 
 // Aggregator combines multiple MCP servers into a single unified interface.
 type Aggregator struct {
 	cfg          *config.Config
 	projectCtx   *project.Context
-	envLoader    *env.Loader
+	envLoader    *env.Loader // Guarded by cfgMu; ReloadEnv swaps it
+	loadEnv      func(ctx context.Context) *env.Loader
+	secrets      *secrets.Resolver // Resolves secret references in env values (nil = none)
 	logger       *slog.Logger
 	outputFormat string // "json" or "toon"
+	timeout      time.Duration
 
 	// Stored for reload
 	workDir     string
 	projectName string
-
-	servers   map[string]Server
-	tools     *ToolRegistry
-	resources *ResourceRegistry
-	prompts   *PromptRegistry
-	health    *HealthTracker
-	mu        sync.RWMutex
-	reloadMu  sync.Mutex   // Prevents concurrent reloads
-	cfgMu     sync.RWMutex // Guards cfg, which Reload swaps while handlers read it
+	loadOpts    config.LoadOptions
+
+	servers        map[string]Server
+	tools          *ToolRegistry
+	resources      *ResourceRegistry
+	prompts        *PromptRegistry
+	health         *HealthTracker
+	inflight       *callCoalescer       // Shares identical in-flight calls for servers with coalesce set
+	calls          *callTracker         // Counts in-flight calls per server, for draining on reload
+	limits         *concurrencyLimits   // Call slots of servers with max_concurrency set
+	hidden         *hiddenServers       // Unhealthy servers whose tools are left out of tools/list
+	blobs          *blobStore           // Binary result content referenced as assern://blob/<id>
+	links          *linkTemplates       // Servers whose linked resources resources/read routes back
+	deviceFlows    *deviceFlows         // OAuth device authorizations in progress
+	clients        *clientProfiles      // What each connected client declared at initialize
+	sampling       *samplingCallers     // Sessions calling servers with sampling set, to forward its requests to
+	probes         *loopGroup           // Background health_check loops
+	supervisors    *loopGroup           // Crash-restart loops for stdio servers
+	watchers       *loopGroup           // tools/list_changed listeners
+	runtime        *serverRuntime       // Start times, latency and last errors, for status reports
+	deprecations   *deprecationTracker  // Calls to deprecated tools, per caller
+	lazy           *lazyStarts          // Lazy servers waiting for their first call
+	toolCache      *toolCache           // Tool lists of lazy servers (nil = disabled)
+	resourceCache  *resourceCache       // Reads of cacheable resources (nil = disabled)
+	features       *featureOverrides    // Feature flags flipped at runtime
+	metrics        Metrics              // Optional metrics exporter (nil = disabled)
+	events         *events.Bus          // Optional event bus (nil = disabled)
+	auditLog       *audit.Log           // Optional tool call audit log (nil = disabled)
+	recent         *recentCalls         // Last tool calls, for the web UI
+	logs           *serverLogs          // Last stderr lines of stdio servers, for 'assern logs'
+	tracerProvider trace.TracerProvider // Optional OpenTelemetry tracer provider (nil = disabled)
+	tracer         trace.Tracer         // Tracer of tracerProvider; records nothing when disabled
+	configErr      error                // Config load error while in failsafe mode; guarded by cfgMu
+	fixedConfig    bool                 // Config did not come from files; Reload is refused
+	instanceID     string               // settings.ids.instance, expanded; tags metrics and audit records
+	sessionIDs     SessionIDFunc        // Names socket and HTTP sessions (nil = transport defaults)
+	clock          clock.Clock          // Time of health checks, caches, backoffs and maintenance windows
+	mu             sync.RWMutex
+	reloadMu       sync.Mutex   // Prevents concurrent reloads
+	cfgMu          sync.RWMutex // Guards cfg, which Reload swaps while handlers read it
 
 	mcpServer *server.MCPServer
 
+	startedAt  time.Time // When the aggregator was created; immutable
+	lastReload time.Time // Last reload that applied changes; guarded by cfgMu
+
+	// stopMetrics cancels the health gauge reporter started by Start.
+	stopMetrics context.CancelFunc
+
 	// discovery is non-nil only when progressive tool disclosure is enabled.
 	discovery *discoveryState
+
+	// schemaRefs controls the inlining of $refs in tool input schemas; it is
+	// read once, like tool naming.
+	schemaRefs *config.SchemaRefsConfig
+
+	// toolBudget is the settings' trimming of tool definitions, which each
+	// server's own refines (see withBudget); it is read once.
+	toolBudget config.ToolBudget
+
+	// toolHandler handles tool calls: handleToolCall wrapped in
+	// Options.ToolMiddleware.
+	toolHandler ToolHandler
+
+	// inProcess are the servers of this process that Start starts next to
+	// the configured ones (see Options.InProcess).
+	inProcess map[string]*server.MCPServer
 }
 
 // Options configures the aggregator.
@@ -51,174 +112,162 @@ type Options struct {
 	Timeout      time.Duration
 	OutputFormat string // "json" or "toon"
 
-	// WorkDir and ProjectName are stored for config reload
+	// WorkDir, ProjectName and LoadOptions are stored for config reload
 	WorkDir     string
 	ProjectName string
-}
+	LoadOptions config.LoadOptions
 
-// New creates a new aggregator with the given options.
-func New(opts Options) (*Aggregator, error) {
-	if opts.Config == nil {
-		return nil, ErrConfigRequired
-	}
+	// Metrics receives per-tool latency/error metrics and health gauges.
+	Metrics Metrics
 
-	if opts.Logger == nil {
-		opts.Logger = slog.Default()
-	}
+	// Events receives server lifecycle, reload and policy events.
+	Events *events.Bus
 
-	if opts.Timeout == 0 {
-		opts.Timeout = 60 * time.Second
-	}
+	// AuditLog records every tools/call.
+	AuditLog *audit.Log
 
-	// Default output format to JSON if not specified
-	if opts.OutputFormat == "" {
-		opts.OutputFormat = "json"
-	}
+	// TracerProvider records spans of tool calls and backend connections.
+	// Nil disables tracing; the caller shuts the provider down.
+	TracerProvider trace.TracerProvider
 
-	agg := &Aggregator{
-		cfg:          opts.Config,
-		projectCtx:   opts.Project,
-		envLoader:    opts.EnvLoader,
-		logger:       opts.Logger,
-		outputFormat: opts.OutputFormat,
-		workDir:      opts.WorkDir,
-		projectName:  opts.ProjectName,
-		servers:      make(map[string]Server),
-		tools:        NewToolRegistry(),
-		resources:    NewResourceRegistry(),
-		prompts:      NewPromptRegistry(),
-		health:       NewHealthTracker(DefaultHealthThreshold),
-	}
+	// ConfigError is the error loading the configuration. When set, Config
+	// holds defaults and the aggregator runs in failsafe mode (see
+	// Aggregator.ConfigError) until a reload succeeds.
+	ConfigError error
 
-	return agg, nil
-}
+	// FixedConfig marks a Config that was not loaded from files, e.g. one
+	// read from stdin; Reload refuses to replace it.
+	FixedConfig bool
 
-// Start initializes all configured servers and discovers their tools.
-func (a *Aggregator) Start(ctx context.Context) error {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	// LoadEnv builds the environment from the .env files again. When set,
+	// WatchConfig also watches those files and calls ReloadEnv on changes.
+	LoadEnv func(ctx context.Context) *env.Loader
 
-	effectiveServers := config.GetEffectiveServers(a.cfg)
-	if len(effectiveServers) == 0 {
-		return fmt.Errorf("%w\n\nAdd servers to:\n  Global: ~/.valksor/assern/mcp.json\n  Local:  .assern/mcp.json (project-specific)\n\nRun 'assern config init' to create default config", ErrNoServers)
-	}
+	// Secrets resolves keyring:// and op:// references in server env
+	// values when a server starts. Nil leaves them as they are.
+	Secrets *secrets.Resolver
 
-	a.logger.Info("starting aggregator", "servers", len(effectiveServers))
+	// SessionIDs names new socket and HTTP client sessions. Nil uses
+	// settings.ids, or the transport defaults when that is unset.
+	SessionIDs SessionIDFunc
 
-	// Start each backend server
-	var wg sync.WaitGroup
+	// ToolMiddleware wraps every call to an aggregated tool; the first is
+	// the outermost. See LoggingMiddleware and TimingMiddleware.
+	ToolMiddleware []ToolMiddleware
 
-	errCh := make(chan error, len(effectiveServers))
+	// Clock drives health probes, restart and retry backoff, maintenance
+	// windows and cache expiry. Nil uses the system clock; tests pass a
+	// clock.Fake.
+	Clock clock.Clock
 
-	for name, srvCfg := range effectiveServers {
-		wg.Add(1)
-
-		go func(name string, cfg *config.ServerConfig) {
-			defer wg.Done()
-
-			if err := a.startServer(ctx, name, cfg); err != nil {
-				errCh <- fmt.Errorf("server %s: %w", name, err)
-			}
-		}(name, srvCfg)
-	}
-
-	wg.Wait()
-	close(errCh)
-
-	// Collect errors
-	var errs []error
-	for err := range errCh {
-		errs = append(errs, err)
-	}
-
-	if len(errs) > 0 {
-		for _, err := range errs {
-			a.logger.Error("failed to start server", "error", err)
-		}
-
-		// If ALL servers failed, return error
-		if len(a.servers) == 0 {
-			return fmt.Errorf("%w: %d servers failed", ErrAllServersFailed, len(errs))
-		}
+	// InProcess are MCP servers running in this process, by server name,
+	// started by Start next to the configured servers and called without a
+	// process or connection in between. A name must not also be configured.
+	InProcess map[string]*server.MCPServer
+}
 
-		// Partial success - log warning but continue with details
-		failedNames := make([]string, 0, len(errs))
-		for _, err := range errs {
-			failedNames = append(failedNames, err.Error())
-		}
-		a.logger.Warn(
-			fmt.Sprintf("%d of %d servers started (%d failed)",
-				len(a.servers), len(effectiveServers), len(errs)),
-			"failed", failedNames,
-		)
+// New creates a new aggregator with the given options.
+func New(opts Options) (*Aggregator, error) {
+	if opts.Config == nil {
+		return nil, ErrConfigRequired
 	}
 
-	// Load tool aliases from settings
-	if a.cfg.Settings != nil && len(a.cfg.Settings.Aliases) > 0 {
-		a.tools.SetAliases(a.cfg.Settings.Aliases)
-		a.logger.Debug("loaded tool aliases", "count", len(a.cfg.Settings.Aliases))
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
 	}
 
-	a.logger.Info(
-		"aggregator started",
-		"active_servers", len(a.servers),
-		"total_tools", a.tools.Count(),
-	)
-
-	if a.tools.Count() == 0 {
-		a.logger.Warn("no tools registered - check server configurations and 'allowed' filters")
+	if opts.Timeout == 0 {
+		opts.Timeout = 60 * time.Second
 	}
 
-	return nil
-}
-
-// startServer starts a single backend server and discovers its tools.
-func (a *Aggregator) startServer(ctx context.Context, name string, cfg *config.ServerConfig) error {
-	// Build environment for the server
-	var env []string
-	if a.envLoader != nil {
-		projectName := ""
-		if a.projectCtx != nil {
-			projectName = a.projectCtx.Name
-		}
+	clk := clock.OrReal(opts.Clock)
 
-		env = a.envLoader.BuildServerEnv(cfg.Env, projectName)
+	// Default output format to JSON if not specified
+	if opts.OutputFormat == "" {
+		opts.OutputFormat = "json"
 	}
 
-	// Create managed server
-	managed, err := NewManagedServer(name, cfg, env, a.logger)
-	if err != nil {
-		return fmt.Errorf("creating server: %w", err)
+	agg := &Aggregator{
+		cfg:            opts.Config,
+		projectCtx:     opts.Project,
+		envLoader:      opts.EnvLoader,
+		loadEnv:        opts.LoadEnv,
+		secrets:        opts.Secrets,
+		logger:         opts.Logger,
+		outputFormat:   opts.OutputFormat,
+		timeout:        opts.Timeout,
+		workDir:        opts.WorkDir,
+		projectName:    opts.ProjectName,
+		loadOpts:       opts.LoadOptions,
+		servers:        make(map[string]Server),
+		tools:          NewToolRegistry(),
+		resources:      NewResourceRegistry(),
+		prompts:        NewPromptRegistry(),
+		health:         newHealthTracker(DefaultHealthThreshold, clk),
+		inflight:       newCallCoalescer(),
+		calls:          newCallTracker(),
+		limits:         newConcurrencyLimits(),
+		hidden:         newHiddenServers(),
+		blobs:          newBlobStore(blobStoreMaxBytes),
+		links:          newLinkTemplates(),
+		deviceFlows:    newDeviceFlows(),
+		clients:        newClientProfiles(),
+		sampling:       newSamplingCallers(),
+		probes:         newLoopGroup(),
+		supervisors:    newLoopGroup(),
+		watchers:       newLoopGroup(),
+		runtime:        newServerRuntime(clk),
+		deprecations:   newDeprecationTracker(),
+		lazy:           newLazyStarts(),
+		toolCache:      newToolCache(),
+		resourceCache:  newResourceCache(clk),
+		features:       newFeatureOverrides(),
+		metrics:        opts.Metrics,
+		events:         opts.Events,
+		auditLog:       opts.AuditLog,
+		recent:         newRecentCalls(),
+		logs:           newServerLogs(),
+		tracerProvider: opts.TracerProvider,
+		tracer:         newTracer(opts.TracerProvider),
+		configErr:      opts.ConfigError,
+		fixedConfig:    opts.FixedConfig,
+		instanceID:     config.InstanceID(opts.Config),
+		sessionIDs:     opts.SessionIDs,
+		inProcess:      opts.InProcess,
+		clock:          clk,
+		startedAt:      clk.Now(),
 	}
 
-	// Start and initialize the server
-	if err := managed.Start(ctx); err != nil {
-		return fmt.Errorf("starting server: %w", err)
+	// IDs are read once, like tool naming; telemetry keys must stay stable
+	if agg.sessionIDs == nil {
+		agg.sessionIDs = sessionIDsFromConfig(opts.Config)
 	}
 
-	// Discover tools
-	tools, err := managed.DiscoverTools(ctx)
-	if err != nil {
-		if stopErr := managed.Stop(); stopErr != nil {
-			a.logger.Warn("error stopping server after discovery failure", "server", name, "error", stopErr)
-		}
-
-		return fmt.Errorf("discovering tools: %w", err)
-	}
+	// Tool naming is read once; names must stay stable while clients hold them
+	agg.tools.SetNaming(opts.Config.Settings.ToolNaming())
 
-	// Register tools with prefix
-	for _, tool := range tools {
-		a.tools.Register(name, tool, cfg.Allowed)
+	if opts.Config.Settings != nil {
+		agg.schemaRefs = opts.Config.Settings.SchemaRefs
+		agg.toolBudget = opts.Config.Settings.ToolBudget()
 	}
 
-	a.servers[name] = managed
-	a.logger.Info("server started", "name", name, "tools", len(tools))
+	agg.toolHandler = chainToolMiddleware(agg.handleToolCall, opts.ToolMiddleware)
 
-	return nil
+	return agg, nil
 }
 
 // Stop gracefully shuts down all backend servers.
 func (a *Aggregator) Stop() error {
+	// Stop background loops before taking the lock; they read a.servers.
+	a.probes.stopAll()
+	a.supervisors.stopAll()
+	a.watchers.stopAll()
+	a.deviceFlows.loops.stopAll()
+
+	if a.stopMetrics != nil {
+		a.stopMetrics()
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -232,10 +281,14 @@ func (a *Aggregator) Stop() error {
 		}
 	}
 
+	naming := a.tools.Naming()
+
 	a.servers = make(map[string]Server)
 	a.tools = NewToolRegistry()
+	a.tools.SetNaming(naming)
 	a.resources = NewResourceRegistry()
 	a.prompts = NewPromptRegistry()
+	a.lazy = newLazyStarts()
 	a.health.Clear()
 
 	if len(errs) > 0 {
@@ -297,46 +350,16 @@ func (a *Aggregator) AddServer(ctx context.Context, srv Server) error {
 		return fmt.Errorf("discovering tools from %s: %w", name, err)
 	}
 
-	// Get allowed list from config if available
-	var allowed []string
-	if srv.Config() != nil {
-		allowed = srv.Config().Allowed
-	}
+	if err := a.registerTools(name, srv.Config(), tools); err != nil {
+		a.tools.RemoveServer(name)
 
-	// Register tools with prefix
-	for _, tool := range tools {
-		a.tools.Register(name, tool, allowed)
+		return fmt.Errorf("registering tools from %s: %w", name, err)
 	}
 
-	// Try to discover resources if server supports them
-	var resourceCount int
-	if resourceSrv, ok := srv.(ResourceServer); ok {
-		resources, err := resourceSrv.DiscoverResources(ctx)
-		if err != nil {
-			a.logger.Debug("server does not provide resources", "server", name, "error", err)
-		} else {
-			for _, resource := range resources {
-				a.resources.Register(name, resource)
-			}
-			resourceCount = len(resources)
-		}
-	}
-
-	// Try to discover prompts if server supports them
-	var promptCount int
-	if promptSrv, ok := srv.(PromptServer); ok {
-		prompts, err := promptSrv.DiscoverPrompts(ctx)
-		if err != nil {
-			a.logger.Debug("server does not provide prompts", "server", name, "error", err)
-		} else {
-			for _, prompt := range prompts {
-				a.prompts.Register(name, prompt)
-			}
-			promptCount = len(prompts)
-		}
-	}
+	resourceCount, promptCount := a.registerResourcesAndPrompts(ctx, name, srv)
 
 	a.servers[name] = srv
+	a.runtime.started(name, ServerTiming{})
 	a.logger.Info("server added", "name", name, "tools", len(tools), "resources", resourceCount, "prompts", promptCount)
 
 	return nil
@@ -3,53 +3,119 @@ package aggregator
 import (
 	"context"
 	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
 )
 
 // ReloadResult contains information about a reload operation.
 type ReloadResult struct {
-	Added   int      `json:"added"`
-	Removed int      `json:"removed"`
-	Errors  []string `json:"errors,omitempty"`
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	// Replaced counts the modified servers a blue-green reload swapped
+	Replaced int      `json:"replaced,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
 }
 
 // Reload reloads the configuration from disk and updates servers.
 // Added servers are started, removed servers are stopped.
-// Modified servers are restarted (stopped then started).
+// Modified servers are restarted (stopped then started), after their
+// in-flight calls end or settings.drain_timeout passes.
 func (a *Aggregator) Reload(ctx context.Context) (*ReloadResult, error) {
+	return a.reload(ctx, false)
+}
+
+// ReloadBlueGreen is Reload without the window where a modified server's
+// tools are unavailable: added and modified servers are first started
+// alongside the running ones, then, once all of them are up, each takes
+// over its server's tools in one step, and the servers they replace are
+// stopped after the calls already sent to them end. If a server fails to
+// start, the running servers and configuration are kept and the error
+// wraps ErrBlueGreenAborted. Lazy servers are reloaded as by Reload.
+func (a *Aggregator) ReloadBlueGreen(ctx context.Context) (*ReloadResult, error) {
+	return a.reload(ctx, true)
+}
+
+// reload implements Reload and ReloadBlueGreen.
+func (a *Aggregator) reload(ctx context.Context, blueGreen bool) (*ReloadResult, error) {
 	// Prevent concurrent reloads
 	a.reloadMu.Lock()
 	defer a.reloadMu.Unlock()
 
+	if a.fixedConfig {
+		return nil, ErrConfigNotReloadable
+	}
+
 	a.logger.Info("reloading configuration")
 
 	// Load fresh config from disk
-	newCfg, err := config.LoadEffective(a.workDir, a.projectName)
+	newCfg, err := config.LoadEffective(a.workDir, a.projectName, a.loadOpts)
 	if err != nil {
+		if a.setConfigError(err) {
+			a.logger.Error("configuration still fails to load; staying in failsafe mode", "error", err)
+		}
+
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
 
+	if a.setConfigError(nil) {
+		a.leaveFailsafe()
+	}
+
 	// Compare configs
 	diff := config.DiffConfigs(a.cfg, newCfg)
 
 	if !diff.HasChanges() {
-		a.logger.Info("no configuration changes detected")
+		a.logger.Info("no server changes detected")
+
+		// Settings such as aliases may still have changed
+		a.swapConfig(newCfg)
 
 		return &ReloadResult{}, nil
 	}
 
 	a.logger.Info(
 		"configuration changes detected",
-		"added", len(diff.Added),
-		"removed", len(diff.Removed),
-		"modified", len(diff.Modified),
+		"added", slices.Sorted(slices.Values(diff.Added)),
+		"removed", slices.Sorted(slices.Values(diff.Removed)),
+		"modified", slices.Sorted(slices.Values(diff.Modified)),
 	)
 
 	result := &ReloadResult{}
+	changes := diff
+
+	if blueGreen {
+		standbys, rest, err := a.startStandbys(ctx, newCfg, diff)
+		if err != nil {
+			a.logger.Error("blue-green reload aborted", "error", err)
+
+			return nil, err
+		}
+
+		retired := make(map[string]Server)
+
+		for _, sb := range standbys {
+			if old := a.promote(sb); old != nil {
+				retired[sb.name] = old
+				result.Replaced++
+			} else {
+				result.Added++
+			}
+		}
+
+		a.retireServers(ctx, retired)
+
+		// Removed and lazy servers are handled the regular way below
+		diff = rest
+	}
 
 	// Stop removed servers
 	for _, name := range diff.Removed {
+		a.runtime.forget(name)
+
 		if err := a.stopServer(name); err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("stop %s: %v", name, err))
 			a.logger.Error("failed to stop server", "server", name, "error", err)
@@ -59,8 +125,16 @@ func (a *Aggregator) Reload(ctx context.Context) (*ReloadResult, error) {
 		}
 	}
 
-	// Stop modified servers (they will be restarted)
+	// Stop modified servers (they will be restarted) once their in-flight
+	// calls end; new calls are refused until the restart below
+	for _, name := range diff.Modified {
+		a.runtime.restarting(name)
+	}
+
+	a.drainServers(ctx, diff.Modified)
+
 	for _, name := range diff.Modified {
+
 		if err := a.stopServer(name); err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("stop %s: %v", name, err))
 			a.logger.Error("failed to stop server for restart", "server", name, "error", err)
@@ -95,19 +169,26 @@ func (a *Aggregator) Reload(ctx context.Context) (*ReloadResult, error) {
 		}
 	}
 
-	// Update config reference. Guarded because discovery/code-mode handlers
-	// read a.cfg concurrently on MCP-call goroutines.
-	a.cfgMu.Lock()
-	a.cfg = newCfg
-	a.cfgMu.Unlock()
+	a.calls.resume(diff.Modified)
+
+	a.swapConfig(newCfg)
 
 	a.logger.Info(
 		"reload completed",
 		"added", result.Added,
 		"removed", result.Removed,
+		"replaced", result.Replaced,
 		"errors", len(result.Errors),
 	)
 
+	a.publish(events.ReloadApplied, "", fmt.Sprintf("added %d, removed %d, modified %d", result.Added, result.Removed, len(changes.Modified)), map[string]any{
+		"added":      changes.Added,
+		"removed":    changes.Removed,
+		"modified":   changes.Modified,
+		"errors":     result.Errors,
+		"blue_green": blueGreen,
+	})
+
 	return result, nil
 }
 
@@ -124,6 +205,11 @@ func (a *Aggregator) stopServer(name string) error {
 		return nil
 	}
 
+	a.probes.stop(name)
+	a.supervisors.stop(name)
+	a.watchers.stop(name)
+	a.lazy.remove(name)
+
 	// Remove from registries
 	a.tools.RemoveServer(name)
 	a.resources.RemoveServer(name)
@@ -135,9 +221,8 @@ func (a *Aggregator) stopServer(name string) error {
 }
 
 // addServerToolsToMCPServer adds a server's tools to the MCP server.
-// This is called after a new server is started during reload. In discovery
-// mode the tools stay in the catalog (loaded per session on demand), so only
-// pinned tools are exposed globally.
+// This is called after a new server is started during reload or its tool
+// list changed.
 func (a *Aggregator) addServerToolsToMCPServer(serverName string) {
 	if a.mcpServer == nil {
 		return
@@ -147,6 +232,31 @@ func (a *Aggregator) addServerToolsToMCPServer(serverName string) {
 	entries := a.tools.GetByServer(serverName)
 	a.mu.RUnlock()
 
+	a.exposeTools(entries)
+}
+
+// swapConfig makes newCfg the current configuration and applies its aliases.
+// Guarded because discovery/code-mode handlers read a.cfg concurrently on
+// MCP-call goroutines.
+func (a *Aggregator) swapConfig(newCfg *config.Config) {
+	a.cfgMu.Lock()
+	a.cfg = newCfg
+	a.lastReload = a.clock.Now()
+	a.cfgMu.Unlock()
+
+	a.loadAliases(newCfg.Settings)
+}
+
+// exposeTools adds registered tools to the MCP server in one batch, so
+// clients receive a single tools/list_changed notification. In discovery
+// mode the tools stay in the catalog (loaded per session on demand), so
+// only pinned tools are exposed globally, and under tool_exposure: search
+// none are.
+func (a *Aggregator) exposeTools(entries []*ToolEntry) {
+	if a.mcpServer == nil || len(entries) == 0 || a.SearchExposure() {
+		return
+	}
+
 	discovery := a.DiscoveryEnabled()
 
 	var pinned map[string]struct{}
@@ -154,6 +264,8 @@ func (a *Aggregator) addServerToolsToMCPServer(serverName string) {
 		pinned = a.pinnedSet()
 	}
 
+	tools := make([]server.ServerTool, 0, len(entries))
+
 	for _, entry := range entries {
 		if discovery {
 			if _, ok := pinned[entry.PrefixedName]; !ok {
@@ -161,6 +273,10 @@ func (a *Aggregator) addServerToolsToMCPServer(serverName string) {
 			}
 		}
 
-		a.addToolToServer(entry)
+		tools = append(tools, server.ServerTool{Tool: entry.ExposedTool(), Handler: a.createToolHandler(entry)})
+	}
+
+	if len(tools) > 0 {
+		a.mcpServer.AddTools(tools...)
 	}
 }
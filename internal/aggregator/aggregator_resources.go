@@ -0,0 +1,108 @@
+package aggregator
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// ListResources returns all available resources.
+func (a *Aggregator) ListResources() []ResourceEntry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entries := a.resources.All()
+	result := make([]ResourceEntry, len(entries))
+
+	for i, e := range entries {
+		result[i] = *e
+	}
+
+	return result
+}
+
+// ListPrompts returns all available prompts.
+func (a *Aggregator) ListPrompts() []PromptEntry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entries := a.prompts.All()
+	result := make([]PromptEntry, len(entries))
+
+	for i, e := range entries {
+		result[i] = *e
+	}
+
+	return result
+}
+
+// registerResourcesAndPrompts discovers the resources and prompts of a
+// started server and registers those its filters allow, returning how many
+// were registered. Servers without them are not an error. The caller holds
+// a.mu.
+func (a *Aggregator) registerResourcesAndPrompts(ctx context.Context, name string, srv Server) (int, int) {
+	resources, prompts := a.discoverResourcesAndPrompts(ctx, name, srv)
+
+	return a.registerDiscovered(name, srv, resources, prompts)
+}
+
+// discoverResourcesAndPrompts lists the resources and prompts of a started
+// server; those it does not provide are nil. It asks the backend, so callers
+// should not hold a.mu unless they are starting up anyway.
+func (a *Aggregator) discoverResourcesAndPrompts(ctx context.Context, name string, srv Server) ([]mcp.Resource, []mcp.Prompt) {
+	var (
+		resources []mcp.Resource
+		prompts   []mcp.Prompt
+		err       error
+	)
+
+	if resourceSrv, ok := srv.(ResourceServer); ok {
+		if resources, err = resourceSrv.DiscoverResources(ctx); err != nil {
+			a.logger.Debug("server does not provide resources", "server", name, "error", err)
+		}
+	}
+
+	if promptSrv, ok := srv.(PromptServer); ok {
+		if prompts, err = promptSrv.DiscoverPrompts(ctx); err != nil {
+			a.logger.Debug("server does not provide prompts", "server", name, "error", err)
+		}
+	}
+
+	return resources, prompts
+}
+
+// registerDiscovered registers the resources and prompts of srv that its
+// filters allow, returning how many were registered. The caller holds a.mu.
+func (a *Aggregator) registerDiscovered(name string, srv Server, resources []mcp.Resource, prompts []mcp.Prompt) (int, int) {
+	var (
+		resourceFilter *config.ResourceFilter
+		promptFilter   *config.PromptFilter
+	)
+
+	if cfg := srv.Config(); cfg != nil {
+		resourceFilter, promptFilter = cfg.AllowedResources, cfg.Prompts
+	}
+
+	var resourceCount int
+
+	for _, resource := range resources {
+		if resourceAllowed(resource, resourceFilter) {
+			a.resources.Register(name, resource)
+			resourceCount++
+		}
+	}
+
+	if hidden := len(resources) - resourceCount; hidden > 0 {
+		a.logger.Debug("resources hidden by allowed_resources", "server", name, "hidden", hidden)
+	}
+
+	promptCount := a.registerPrompts(name, prompts, promptFilter)
+
+	if hidden := len(prompts) - promptCount; hidden > 0 {
+		a.logger.Debug("prompts hidden by prompts filter", "server", name, "hidden", hidden)
+	}
+
+	return resourceCount, promptCount
+}
@@ -2,6 +2,7 @@ package aggregator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -37,30 +38,56 @@ func (a *Aggregator) CreateMCPServer() *server.MCPServer {
 
 	opts := []server.ServerOption{
 		server.WithToolCapabilities(true),
-		server.WithResourceCapabilities(true, false), // subscribe=true, listChanged=false
-		server.WithPromptCapabilities(false),         // listChanged=false
+		server.WithResourceCapabilities(true, true), // subscribe=true, listChanged=true
+		server.WithPromptCapabilities(true),         // listChanged=true
 		server.WithLogging(),
 	}
 
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(a.announceIdentity)
+	a.addClientHooks(hooks)
+	a.addACLHooks(hooks)
+	a.addPaginationHooks(hooks)
+
 	if discovery {
-		opts = append(opts, server.WithHooks(a.discoveryHooks()))
+		a.addDiscoveryHooks(hooks)
 	}
 
-	a.mcpServer = server.NewMCPServer("Valksor Assern", version.Version, opts...)
+	opts = append(opts, server.WithHooks(hooks), server.WithToolFilter(a.hideDownTools),
+		server.WithToolFilter(a.filterACLTools), server.WithToolFilter(a.filterClientTools), server.WithToolFilter(a.orderTools),
+		server.WithToolHandlerMiddleware(a.correlateToolCalls), server.WithToolHandlerMiddleware(a.auditToolCalls),
+		server.WithPromptFilter(a.filterACLPrompts))
+
+	a.mcpServer = server.NewMCPServer(serverName, version.Version, opts...)
+	a.mcpServer.AddNotificationHandler(string(mcp.MethodNotificationInitialized), a.sendStartupSummary)
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	if discovery {
+	switch {
+	case a.SearchExposure():
+		// The aggregated tools are reached through the meta-tools only.
+		a.registerSearchExposureTools()
+	case discovery:
 		a.registerMetaTools()
 		a.exposePinnedTools()
-	} else {
+	default:
 		// Add all registered tools.
 		for _, entry := range a.tools.All() {
 			a.addToolToServer(entry)
 		}
 	}
 
+	// The status tool is always exposed, whatever the disclosure mode.
+	a.registerStatusTool()
+
+	// Referenced binary content can be read whichever server returned it
+	a.registerBlobTemplate()
+
+	if a.ConfigError() != nil {
+		a.registerConfigErrorResource()
+	}
+
 	// Code mode is independent of discovery: it adds one more meta-tool.
 	if codeMode {
 		a.registerExecuteTool()
@@ -86,68 +113,241 @@ func (a *Aggregator) addToolToServer(entry *ToolEntry) {
 	a.mcpServer.AddTool(entry.ExposedTool(), handler)
 }
 
-// createToolHandler creates a handler function for a tool that routes to the backend.
+// createToolHandler creates a handler function for a tool that routes to
+// the backend through the tool middleware (see Options.ToolMiddleware), and
+// fits the result to what the calling client supports. Each call is traced
+// when settings.otel is set.
 func (a *Aggregator) createToolHandler(entry *ToolEntry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		a.mu.RLock()
-		srv, exists := a.servers[entry.ServerName]
-		a.mu.RUnlock()
+		ctx = correlate(ctx, req)
+		ctx, span := a.startToolSpan(ctx, entry, req)
+		result, err := a.toolHandler(ctx, entry, req)
+		endToolSpan(span, result, err)
 
-		if !exists {
-			return mcp.NewToolResultError(fmt.Sprintf("%s: %v", entry.ServerName, ErrServerNotFound)), nil
+		return a.adaptResult(ctx, result), err
+	}
+}
+
+// handleToolCall routes a call to entry to its backend server. It is the
+// innermost ToolHandler.
+func (a *Aggregator) handleToolCall(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !a.aclAllows(ctx, entry) {
+		return deniedResult(a.aclDenied(ctx, entry), entry.ServerName), nil
+	}
+
+	if !a.clientAllows(ctx, entry) {
+		return deniedResult(a.clientDenied(ctx, entry), entry.ServerName), nil
+	}
+
+	// Counted before the lookup, so a reload either waits for this call
+	// or the call finds the restarted server
+	done, err := a.calls.begin(entry.ServerName)
+	if err != nil {
+		return drainingResult(err, entry.ServerName), nil
+	}
+	defer done()
+
+	a.mu.RLock()
+	srv, exists := a.servers[entry.ServerName]
+	a.mu.RUnlock()
+
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("%s: %v", entry.ServerName, ErrServerNotFound)), nil
+	}
+
+	// Route the call to the backend server with the original tool name
+	args, ok := req.Params.Arguments.(map[string]any)
+	if !ok && req.Params.Arguments != nil {
+		return mcp.NewToolResultError("invalid arguments format"), nil
+	}
+
+	cfg := srv.Config()
+	a.noteDeprecatedCall(ctx, entry, cfg)
+
+	// Before coalescing, so calls forwarding different users are not shared
+	ctx, args = withForwardedHeaders(ctx, cfg, req.Header, args)
+
+	if cfg != nil && cfg.DryRun {
+		return a.dryRunResult(entry, cfg, args), nil
+	}
+
+	// Fail fast instead of calling, or lazily starting, a backend that is
+	// down for scheduled maintenance
+	if maintErr := a.inMaintenance(entry.ServerName, cfg); maintErr != nil {
+		return a.maintenanceResult(maintErr), nil
+	}
+
+	// Fail fast instead of waiting on a backend that needs authorization
+	if authErr := a.authBlocked(entry.ServerName); authErr != nil {
+		return a.authRequiredResult(ctx, authErr), nil
+	}
+
+	if err := a.startLazy(ctx, entry.ServerName); err != nil {
+		var authErr *AuthRequiredError
+		if errors.As(err, &authErr) {
+			return a.authRequiredResult(ctx, authErr), nil
 		}
 
-		// Route the call to the backend server with the original tool name
-		args, ok := req.Params.Arguments.(map[string]any)
-		if !ok && req.Params.Arguments != nil {
-			return mcp.NewToolResultError("invalid arguments format"), nil
+		return mcp.NewToolResultError(fmt.Sprintf("starting %s: %v", entry.ServerName, err)), nil
+	}
+
+	// Get retry and coalescing config from server config
+	var (
+		retryCfg *config.RetryConfig
+		coalesce bool
+		timeout  = a.callTimeout(cfg)
+	)
+	if cfg != nil {
+		retryCfg = cfg.Retry
+		coalesce = cfg.Coalesce
+	}
+
+	// Execute with retry logic, recording health once per backend call.
+	// An idempotency key is generated per backend call, not per attempt,
+	// and a call slot is held across the attempts
+	call := func(ctx context.Context) (*mcp.CallToolResult, error) {
+		release, err := a.limits.acquire(ctx, entry.ServerName, cfg)
+		if err != nil {
+			return nil, err
 		}
+		defer release()
 
-		// Get retry config from server config
-		var retryCfg *config.RetryConfig
-		if cfg := srv.Config(); cfg != nil {
-			retryCfg = cfg.Retry
+		if cfg != nil && cfg.Sampling {
+			defer a.sampling.track(ctx, entry.ServerName)()
 		}
 
-		// Execute with retry logic
-		result, err := WithRetry(ctx, retryCfg, func(ctx context.Context, attempt int) (*mcp.CallToolResult, error) {
+		ctx, keyed := withIdempotencyKey(ctx, cfg, entry.Tool.Name, args)
+
+		start := a.clock.Now()
+		result, err := withRetry(ctx, a.clock, retryCfg, func(ctx context.Context, attempt int) (*mcp.CallToolResult, error) {
 			if attempt > 1 {
 				a.logger.Debug(
 					"retrying tool call",
 					"tool", entry.PrefixedName,
 					"server", entry.ServerName,
 					"attempt", attempt,
+					"correlation_id", CorrelationID(ctx),
 				)
 			}
 
-			return srv.CallTool(ctx, entry.Tool.Name, args)
+			return callWithTimeout(ctx, a.clock, srv, entry.Tool.Name, keyed, timeout)
 		})
-		if err != nil {
-			a.health.RecordFailure(entry.ServerName)
+		a.recordToolCall(entry, a.clock.Since(start), err)
+
+		// A call the client cancelled says nothing about the server's health
+		if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+			return nil, err
+		}
 
-			return mcp.NewToolResultError(fmt.Sprintf("tool call failed: %v", err)), nil
+		if err != nil {
+			return nil, a.recordFailure(ctx, entry.ServerName, "call "+entry.Tool.Name, err)
 		}
 
 		a.health.RecordSuccess(entry.ServerName)
 
-		// Format result as TOON if enabled
-		if a.outputFormat == "toon" {
-			toonResult, toonErr := a.formatAsTOON(result)
-			if toonErr != nil {
-				a.logger.Warn("failed to format result as TOON, using original", "error", toonErr)
+		if a.hidden.has(entry.ServerName) {
+			a.updateToolVisibility(entry.ServerName)
+		}
 
-				return result, nil // Fall back to original
-			}
+		return result, nil
+	}
 
-			return toonResult, nil
+	result, err := a.callTool(ctx, entry, args, coalesce, call)
+	if err != nil {
+		if errors.Is(err, ErrServerBusy) {
+			return busyResult(err, entry.ServerName), nil
 		}
 
-		return result, nil
+		var authErr *AuthRequiredError
+		if errors.As(err, &authErr) {
+			return a.authRequiredResult(ctx, authErr), nil
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("tool call failed: %v", err)), nil
+	}
+
+	result = a.encodeBinaryContent(result, cfg, entry.Tool.Name)
+	result = a.linkResources(result, entry.ServerName)
+
+	// Format result as TOON if enabled
+	if a.resultFormat() == "toon" {
+		toonResult, toonErr := a.formatAsTOON(result)
+		if toonErr != nil {
+			a.logger.Warn("failed to format result as TOON, using original", "error", toonErr)
+
+			return result, nil // Fall back to original
+		}
+
+		return toonResult, nil
+	}
+
+	return result, nil
+}
+
+// Tool returns the registered tool exposed as name, which may be an alias.
+func (a *Aggregator) Tool(name string) (ToolEntry, bool) {
+	entry, ok := a.tools.Get(name)
+	if !ok {
+		return ToolEntry{}, false
 	}
+
+	return *entry, true
+}
+
+// CallTool calls the tool exposed as name (or an alias of it) through the
+// same handler as MCP clients, for 'assern call'. A failing tool is not an
+// error: the result has IsError set.
+func (a *Aggregator) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	entry, ok := a.tools.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+
+	var req mcp.CallToolRequest
+	req.Params.Name = entry.PrefixedName
+	req.Params.Arguments = args
+
+	return a.createToolHandler(entry)(ctx, req)
+}
+
+// callTool runs a backend tool call, attaching it to an identical call already
+// in flight when coalescing is enabled for the server.
+func (a *Aggregator) callTool(
+	ctx context.Context,
+	entry *ToolEntry,
+	args map[string]any,
+	coalesce bool,
+	call toolCallFunc,
+) (*mcp.CallToolResult, error) {
+	if !coalesce {
+		return call(ctx)
+	}
+
+	key, ok := coalesceKey(entry.PrefixedName, args)
+	if meta := callMeta(ctx); ok && meta != nil {
+		key, ok = coalesceKey(key, meta)
+	}
+
+	if !ok {
+		return call(ctx)
+	}
+
+	result, shared, err := a.inflight.do(ctx, key, call)
+	if shared {
+		a.logger.Debug("coalesced duplicate tool call", "tool", entry.PrefixedName)
+	}
+
+	return result, err
 }
 
 // addResourceToServer adds a resource entry to the MCP server.
 func (a *Aggregator) addResourceToServer(entry *ResourceEntry) {
+	a.mcpServer.AddResources(a.serverResource(entry))
+}
+
+// serverResource is entry as the MCP server exposes it: under its prefixed
+// URI, read from the backend server.
+func (a *Aggregator) serverResource(entry *ResourceEntry) server.ServerResource {
 	// Create a copy of the resource with prefixed URI
 	prefixedResource := mcp.NewResource(
 		entry.PrefixedURI,
@@ -160,40 +360,64 @@ func (a *Aggregator) addResourceToServer(entry *ResourceEntry) {
 	}
 
 	// Create handler that routes to the backend server
-	handler := a.createResourceHandler(entry)
-
-	a.mcpServer.AddResource(prefixedResource, handler)
+	return server.ServerResource{Resource: prefixedResource, Handler: a.createResourceHandler(entry)}
 }
 
 // createResourceHandler creates a handler function for a resource that routes to the backend.
 func (a *Aggregator) createResourceHandler(entry *ResourceEntry) server.ResourceHandlerFunc {
 	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		a.mu.RLock()
-		srv, exists := a.servers[entry.ServerName]
-		a.mu.RUnlock()
+		return a.routeResourceRead(ctx, entry)
+	}
+}
 
-		if !exists {
-			return nil, fmt.Errorf("%s: %w", entry.ServerName, ErrServerNotFound)
-		}
+// routeResourceRead reads entry from its backend server with the original
+// URI, failing reads the request's ACL token may not make and reads over
+// settings.max_resource_size.
+func (a *Aggregator) routeResourceRead(ctx context.Context, entry *ResourceEntry) ([]mcp.ResourceContents, error) {
+	if !a.aclAllowsServer(ctx, entry.ServerName) {
+		return nil, a.aclDeniedServer(ctx, entry.ServerName, "resource", entry.PrefixedURI)
+	}
 
-		// Check if server supports resources
-		resourceSrv, ok := srv.(ResourceServer)
-		if !ok {
-			return nil, fmt.Errorf("server %s does not support resources", entry.ServerName)
-		}
+	a.mu.RLock()
+	srv, exists := a.servers[entry.ServerName]
+	a.mu.RUnlock()
 
-		// Route the read to the backend server with the original URI
-		result, err := resourceSrv.ReadResource(ctx, entry.OriginalURI)
-		if err != nil {
-			return nil, fmt.Errorf("reading resource: %w", err)
-		}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", entry.ServerName, ErrServerNotFound)
+	}
+
+	// Check if server supports resources
+	resourceSrv, ok := srv.(ResourceServer)
+	if !ok {
+		return nil, fmt.Errorf("server %s does not support resources", entry.ServerName)
+	}
+
+	limit := a.maxResourceSize()
+	if err := checkDeclaredSize(entry, limit); err != nil {
+		return nil, err
+	}
+
+	// Route the read to the backend server with the original URI
+	result, err := a.readResource(ctx, resourceSrv, entry)
+	if err != nil {
+		return nil, fmt.Errorf("reading resource: %w", err)
+	}
 
-		return result.Contents, nil
+	if err := checkContentsSize(entry, result.Contents, limit); err != nil {
+		return nil, err
 	}
+
+	return result.Contents, nil
 }
 
 // addPromptToServer adds a prompt entry to the MCP server.
 func (a *Aggregator) addPromptToServer(entry *PromptEntry) {
+	a.mcpServer.AddPrompts(a.serverPrompt(entry))
+}
+
+// serverPrompt is entry as the MCP server exposes it: under its prefixed
+// name, got from the backend server.
+func (a *Aggregator) serverPrompt(entry *PromptEntry) server.ServerPrompt {
 	// Create a copy of the prompt with prefixed name
 	prefixedPrompt := mcp.Prompt{
 		Name:        entry.PrefixedName,
@@ -202,14 +426,16 @@ func (a *Aggregator) addPromptToServer(entry *PromptEntry) {
 	}
 
 	// Create handler that routes to the backend server
-	handler := a.createPromptHandler(entry)
-
-	a.mcpServer.AddPrompt(prefixedPrompt, handler)
+	return server.ServerPrompt{Prompt: prefixedPrompt, Handler: a.createPromptHandler(entry)}
 }
 
 // createPromptHandler creates a handler function for a prompt that routes to the backend.
 func (a *Aggregator) createPromptHandler(entry *PromptEntry) server.PromptHandlerFunc {
 	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		if !a.aclAllowsServer(ctx, entry.ServerName) {
+			return nil, a.aclDeniedServer(ctx, entry.ServerName, "prompt", entry.PrefixedName)
+		}
+
 		a.mu.RLock()
 		srv, exists := a.servers[entry.ServerName]
 		a.mu.RUnlock()
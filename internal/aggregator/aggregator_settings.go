@@ -0,0 +1,59 @@
+package aggregator
+
+import "github.com/valksor/go-assern/internal/config"
+
+// SocketConfig returns the configured instance-sharing socket settings, or
+// nil. It reads a.cfg under cfgMu because Reload may swap a.cfg concurrently.
+func (a *Aggregator) SocketConfig() *config.SocketConfig {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return nil
+	}
+
+	return a.cfg.Settings.Socket
+}
+
+// StdioConfig returns the configured stdio transport settings, or nil.
+func (a *Aggregator) StdioConfig() *config.StdioConfig {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return nil
+	}
+
+	return a.cfg.Settings.Stdio
+}
+
+// WebUIConfig returns the web UI settings with ${VAR} references in the
+// token expanded, or nil when none are configured.
+func (a *Aggregator) WebUIConfig() *config.WebUIConfig {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil || a.cfg.Settings.WebUI == nil {
+		return nil
+	}
+
+	webUI := a.cfg.Settings.WebUI.Clone()
+	if a.envLoader != nil {
+		webUI.Token = a.envLoader.Expand(webUI.Token)
+	}
+
+	return webUI
+}
+
+// ListenAddress returns the configured HTTP listen address (settings.listen),
+// or "" when HTTP serving is off.
+func (a *Aggregator) ListenAddress() string {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return ""
+	}
+
+	return a.cfg.Settings.Listen
+}
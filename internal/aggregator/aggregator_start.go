@@ -0,0 +1,283 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// Start initializes all configured servers and discovers their tools.
+func (a *Aggregator) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	effectiveServers := config.GetEffectiveServers(a.cfg)
+	for name := range a.inProcess {
+		if _, ok := effectiveServers[name]; ok {
+			return fmt.Errorf("server %s is both configured and registered in-process", name)
+		}
+
+		effectiveServers[name] = &config.ServerConfig{Transport: string(TransportInProcess)}
+	}
+
+	if len(effectiveServers) == 0 && a.fixedConfig {
+		return fmt.Errorf("%w in the given configuration", ErrNoServers)
+	}
+
+	if len(effectiveServers) == 0 {
+		return fmt.Errorf("%w\n\nAdd servers to:\n  Global: ~/.valksor/assern/mcp.json\n  Local:  .assern/mcp.json (project-specific)\n\nRun 'assern config init' to create default config", ErrNoServers)
+	}
+
+	a.logger.Info("starting aggregator", "servers", len(effectiveServers))
+
+	// Start each backend server
+	var wg sync.WaitGroup
+
+	errCh := make(chan error, len(effectiveServers))
+
+	for name, srvCfg := range effectiveServers {
+		wg.Add(1)
+
+		go func(name string, cfg *config.ServerConfig) {
+			defer wg.Done()
+
+			if err := a.startServer(ctx, name, cfg); err != nil {
+				errCh <- fmt.Errorf("server %s: %w", name, err)
+			}
+		}(name, srvCfg)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	// Collect errors
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			a.logger.Error("failed to start server", "error", err)
+		}
+
+		// If ALL servers failed, return error
+		if len(a.servers) == 0 {
+			return fmt.Errorf("%w: %d servers failed", ErrAllServersFailed, len(errs))
+		}
+
+		// Partial success - log warning but continue with details
+		failedNames := make([]string, 0, len(errs))
+		for _, err := range errs {
+			failedNames = append(failedNames, err.Error())
+		}
+		a.logger.Warn(
+			fmt.Sprintf("%d of %d servers started (%d failed)",
+				len(a.servers), len(effectiveServers), len(errs)),
+			"failed", failedNames,
+		)
+	}
+
+	a.loadAliases(a.cfg.Settings)
+
+	a.logger.Info(
+		"aggregator started",
+		"active_servers", len(a.servers),
+		"total_tools", a.tools.Count(),
+	)
+
+	if a.tools.Count() == 0 {
+		a.logger.Warn("no tools registered - check server configurations and 'allowed' filters")
+	}
+
+	var metricsCfg *config.MetricsConfig
+	if a.cfg.Settings != nil {
+		metricsCfg = a.cfg.Settings.Metrics
+	}
+
+	a.startMetricsReporter(metricsCfg.EffectiveInterval())
+
+	return nil
+}
+
+// startServer starts a single backend server and discovers its tools,
+// publishing server_started, or server_failed/auth_expired on error.
+func (a *Aggregator) startServer(ctx context.Context, name string, cfg *config.ServerConfig) error {
+	if err := a.launchServer(ctx, name, cfg); err != nil {
+		a.runtime.failed(name, opStart, err)
+
+		if isAuthError(err) {
+			return a.markNeedsAuth(ctx, name, cfg, err)
+		}
+
+		a.publish(events.ServerFailed, name, err.Error(), nil)
+
+		return err
+	}
+
+	// A lazy server is announced when its first call starts it
+	if a.lazy.get(name) != nil {
+		return nil
+	}
+
+	a.publish(events.ServerStarted, name, "", map[string]any{"tools": len(a.tools.GetByServer(name))})
+
+	return nil
+}
+
+// launchServer does the work of startServer without publishing events.
+func (a *Aggregator) launchServer(ctx context.Context, name string, cfg *config.ServerConfig) error {
+	managed, err := a.newManagedServer(ctx, name, cfg)
+	if err != nil {
+		return err
+	}
+
+	// A lazy server with known tools is started by its first call instead;
+	// without declared or cached tools it is started now to discover them
+	if cfg.Lazy && a.FeatureEnabled(config.FeatureLazyStart) {
+		if tools, source := a.lazyTools(name, cfg); tools != nil {
+			return a.registerLazy(name, managed, tools, source)
+		}
+
+		a.logger.Info("lazy server has no declared or cached tools, starting it to discover them", "server", name)
+	}
+
+	tools, timing, err := a.startManaged(ctx, managed)
+	if err != nil {
+		return err
+	}
+
+	a.cacheTools(name, cfg, tools)
+
+	if err := a.registerTools(name, cfg, tools); err != nil {
+		a.tools.RemoveServer(name)
+
+		if stopErr := managed.Stop(); stopErr != nil {
+			a.logger.Warn("error stopping server after tool registration failure", "server", name, "error", stopErr)
+		}
+
+		return fmt.Errorf("registering tools: %w", err)
+	}
+
+	resourceCount, promptCount := a.registerResourcesAndPrompts(ctx, name, managed)
+
+	a.servers[name] = managed
+	a.runtime.started(name, timing)
+	a.logger.Info("server started", "name", name, "tools", len(tools), "resources", resourceCount, "prompts", promptCount)
+
+	a.startHealthProbe(name, cfg)
+	a.superviseServer(name, managed)
+	a.watchToolChanges(name, managed)
+
+	return nil
+}
+
+// newManagedServer creates the backend for a server with its environment,
+// secret references resolved. It is not started.
+func (a *Aggregator) newManagedServer(ctx context.Context, name string, cfg *config.ServerConfig) (*ManagedServer, error) {
+	// Build environment for the server
+	a.cfgMu.RLock()
+	env := a.serverEnv(a.envLoader, cfg)
+	conn := serverConnection(a.envLoader, cfg)
+	engine := config.ContainerEngineDocker
+	if a.cfg != nil {
+		engine = a.cfg.Settings.EffectiveContainerEngine()
+	}
+	a.cfgMu.RUnlock()
+
+	// Secret references are resolved last and override their literal
+	// values (exec keeps the last of duplicate variables)
+	resolved, err := a.secrets.ResolveEnv(ctx, cfg.Env)
+	if err != nil {
+		return nil, fmt.Errorf("resolving secrets: %w", err)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(resolved)) {
+		env = append(env, key+"="+resolved[key])
+	}
+
+	// Name hint, so a backend process (often a bare npx or node) can be
+	// traced back to its server, e.g. with ps e
+	env = append(env, ServerEnvVar+"="+name)
+
+	managed, err := NewManagedServer(name, cfg, env, a.logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating server: %w", err)
+	}
+
+	managed.conn = conn
+	managed.engine = engine
+	managed.tracer = a.tracer
+	managed.inProcess = a.inProcess[name]
+	managed.clock = a.clock
+	managed.stderr = newStderrLog(name, a.serverLogsConfig(), a.logs, managed.logger, a.clock)
+
+	if cfg.Sampling {
+		managed.sampling = &samplingHandler{agg: a, server: name}
+	}
+
+	return managed, nil
+}
+
+// startManaged starts and initializes a backend and lists its tools. The
+// backend is stopped again when listing fails.
+func (a *Aggregator) startManaged(ctx context.Context, managed *ManagedServer) ([]mcp.Tool, ServerTiming, error) {
+	initStart := a.clock.Now()
+	if err := managed.Start(ctx); err != nil {
+		return nil, ServerTiming{}, fmt.Errorf("starting server: %w", err)
+	}
+
+	timing := ServerTiming{Initialize: a.clock.Since(initStart)}
+
+	// Discover tools
+	listStart := a.clock.Now()
+	tools, err := managed.DiscoverTools(ctx)
+	timing.ListTools = a.clock.Since(listStart)
+
+	if err != nil {
+		if stopErr := managed.Stop(); stopErr != nil {
+			a.logger.Warn("error stopping server after discovery failure", "server", managed.Name(), "error", stopErr)
+		}
+
+		return nil, timing, fmt.Errorf("discovering tools: %w", err)
+	}
+
+	return tools, timing, nil
+}
+
+// StartServer starts a server that is not part of the configuration, as a
+// reload starts an added one, and exposes its tools on the MCP server.
+// Reloads leave it running.
+func (a *Aggregator) StartServer(ctx context.Context, name string, cfg *config.ServerConfig) error {
+	a.mu.Lock()
+	err := a.startAdded(ctx, name, cfg)
+	a.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	a.addServerToolsToMCPServer(name)
+
+	return nil
+}
+
+// startAdded does the work of StartServer holding a.mu, as Start does.
+func (a *Aggregator) startAdded(ctx context.Context, name string, cfg *config.ServerConfig) error {
+	if _, exists := a.servers[name]; exists {
+		return fmt.Errorf("server %s already exists", name)
+	}
+
+	if err := a.startServer(ctx, name, cfg); err != nil {
+		return fmt.Errorf("server %s: %w", name, err)
+	}
+
+	return nil
+}
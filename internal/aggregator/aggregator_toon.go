@@ -5,8 +5,21 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/toon-format/toon-go"
+
+	"github.com/valksor/go-assern/internal/config"
 )
 
+// resultFormat returns the format of tool results: the configured output
+// format, or TOON in place of the JSON default when the toon_default feature
+// is on.
+func (a *Aggregator) resultFormat() string {
+	if a.outputFormat == "json" && a.FeatureEnabled(config.FeatureTOONDefault) {
+		return "toon"
+	}
+
+	return a.outputFormat
+}
+
 // formatAsTOON converts a CallToolResult to TOON format.
 func (a *Aggregator) formatAsTOON(result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
 	if result == nil {
@@ -0,0 +1,62 @@
+package aggregator
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/audit"
+)
+
+// AuditLog returns the log tool calls are recorded to (nil if none).
+func (a *Aggregator) AuditLog() *audit.Log {
+	return a.auditLog
+}
+
+// auditToolCalls is tool handler middleware that records every tools/call,
+// including assern's own tools, to the recent calls and the audit log.
+func (a *Aggregator) auditToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := a.clock.Now()
+		result, err := next(ctx, req)
+
+		rec := audit.Record{
+			Time:        start.UTC(),
+			Instance:    a.instanceID,
+			Client:      callerName(ctx),
+			Identity:    identity(ctx),
+			Correlation: CorrelationID(ctx),
+			Tool:        req.Params.Name,
+			DurationMS:  float64(a.clock.Since(start).Microseconds()) / 1000,
+			Status:      audit.StatusOK,
+		}
+
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			rec.Session = session.SessionID()
+		}
+
+		if entry, ok := a.tools.Get(req.Params.Name); ok {
+			rec.Tool = entry.PrefixedName
+			rec.Server = entry.ServerName
+		}
+
+		switch {
+		case err != nil:
+			rec.Status = audit.StatusError
+			rec.Error = err.Error()
+		case result != nil && result.IsError:
+			rec.Status = audit.StatusError
+			rec.Error = toolResultText(result)
+		}
+
+		a.recent.add(rec)
+
+		args, _ := req.Params.Arguments.(map[string]any)
+		if werr := a.auditLog.Write(rec, args); werr != nil {
+			a.logger.Warn("could not write audit record", "tool", rec.Tool, "error", werr)
+		}
+
+		return result, err
+	}
+}
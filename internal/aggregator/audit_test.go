@@ -0,0 +1,90 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/audit"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestAuditToolCalls(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	auditLog, err := audit.Open(&config.AuditLogConfig{Path: path})
+	if err != nil {
+		t.Fatalf("audit.Open: %v", err)
+	}
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), AuditLog: auditLog})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{{Name: "search"}, {Name: "broken"}})
+	mock.ToolResults = map[string]*mcp.CallToolResult{"broken": mcp.NewToolResultError("rate limited")}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	call := func(name string, args map[string]any) {
+		t.Helper()
+
+		entry, ok := agg.tools.Get(name)
+		if !ok {
+			t.Fatalf("tool %s not registered", name)
+		}
+
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+
+		if _, err := agg.auditToolCalls(agg.createToolHandler(entry))(t.Context(), req); err != nil {
+			t.Fatalf("calling %s: %v", name, err)
+		}
+	}
+
+	call("github_search", map[string]any{"q": "assern"})
+	call("github_broken", nil)
+
+	// Handler errors are recorded too, e.g. from assern's own tools
+	failing := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "assern_status"
+
+	_, _ = agg.auditToolCalls(failing)(t.Context(), req)
+
+	records, err := audit.Tail(path, 10)
+	if err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("recorded %d calls, want 3: %+v", len(records), records)
+	}
+
+	if r := records[0]; r.Tool != "github_search" || r.Server != "github" || r.Status != audit.StatusOK ||
+		r.ArgumentsHash != audit.HashArguments(map[string]any{"q": "assern"}) || r.Client != unknownCaller {
+		t.Errorf("first record = %+v", r)
+	}
+
+	if r := records[1]; r.Status != audit.StatusError || r.Error != "rate limited" {
+		t.Errorf("tool error record = %+v, want error rate limited", r)
+	}
+
+	if r := records[2]; r.Tool != "assern_status" || r.Server != "" || r.Error != "boom" {
+		t.Errorf("handler error record = %+v, want assern_status failing with boom", r)
+	}
+}
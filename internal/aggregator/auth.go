@@ -0,0 +1,127 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// authRetryInterval is how long tool calls to a needs_auth server fail fast
+// before one call is let through to check whether the server was authorized
+// in the meantime (the token cache is shared, see tokenCacheKey).
+const authRetryInterval = 30 * time.Second
+
+// AuthRequiredError reports that a backend server rejected its credentials
+// and must be (re)authorized.
+type AuthRequiredError struct {
+	Server  string
+	AuthURL string // Where to authorize; empty if it could not be determined
+	Err     error
+}
+
+func (e *AuthRequiredError) Error() string {
+	msg := fmt.Sprintf("server %s needs authorization", e.Server)
+	if e.AuthURL != "" {
+		msg += "; authorize at " + e.AuthURL
+	}
+
+	return msg
+}
+
+func (e *AuthRequiredError) Unwrap() error { return e.Err }
+
+// markNeedsAuth moves a server to the needs_auth health state, logging the
+// authorization URL and publishing auth_expired on the transition. Servers
+// configured with oauth.device_flow start a device authorization instead and
+// report its verification URI (see startDeviceFlow).
+func (a *Aggregator) markNeedsAuth(ctx context.Context, server string, cfg *config.ServerConfig, err error) *AuthRequiredError {
+	authURL := a.startDeviceFlow(ctx, server, cfg, err)
+	if authURL == "" {
+		authURL = authorizationURL(ctx, err)
+	}
+
+	if a.health.MarkNeedsAuth(server, authURL) {
+		a.logger.Warn("server needs authorization", "server", server, "authorization_url", authURL, "error", err)
+		a.publish(events.AuthExpired, server, err.Error(), map[string]any{
+			"authorization_url": authURL,
+		})
+	}
+
+	if authURL == "" {
+		authURL = a.health.Stats(server).AuthURL
+	}
+
+	return &AuthRequiredError{Server: server, AuthURL: authURL, Err: err}
+}
+
+// authBlocked returns an *AuthRequiredError when calls to server should fail
+// fast because it is waiting for authorization (see HealthTracker.NeedsAuth).
+func (a *Aggregator) authBlocked(server string) *AuthRequiredError {
+	authURL, blocked := a.health.NeedsAuth(server, authRetryInterval)
+	if !blocked {
+		return nil
+	}
+
+	return &AuthRequiredError{Server: server, AuthURL: authURL}
+}
+
+// authorizationURL builds the URL that starts an OAuth authorization for the
+// handler carried by err. It returns "" for non-OAuth errors or when the
+// authorization server metadata cannot be fetched.
+func authorizationURL(ctx context.Context, err error) string {
+	handler := client.GetOAuthHandler(err)
+	if handler == nil {
+		return ""
+	}
+
+	state, err := transport.GenerateState()
+	if err != nil {
+		return ""
+	}
+
+	verifier, err := transport.GenerateCodeVerifier()
+	if err != nil {
+		return ""
+	}
+
+	authURL, err := handler.GetAuthorizationURL(ctx, state, transport.GenerateCodeChallenge(verifier))
+	if err != nil {
+		return ""
+	}
+
+	return authURL
+}
+
+// authRequiredResult turns authErr into a structured tool error and sends
+// the calling client an MCP logging notification with the same details, so
+// hosts that surface log messages can show the authorization URL.
+func (a *Aggregator) authRequiredResult(ctx context.Context, authErr *AuthRequiredError) *mcp.CallToolResult {
+	data := map[string]any{
+		"error":   "authorization_required",
+		"server":  authErr.Server,
+		"message": authErr.Error(),
+	}
+
+	if authErr.AuthURL != "" {
+		data["authorization_url"] = authErr.AuthURL
+	}
+
+	if a.mcpServer != nil && canLog(ctx) {
+		notification := mcp.NewLoggingMessageNotification(mcp.LoggingLevelWarning, "assern", data)
+		if err := a.mcpServer.SendLogMessageToClient(ctx, notification); err != nil {
+			a.logger.Debug("could not send authorization notice to client", "server", authErr.Server, "error", err)
+		}
+	}
+
+	result := mcp.NewToolResultStructured(data, authErr.Error())
+	result.IsError = true
+
+	return result
+}
@@ -0,0 +1,157 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// oauthRequiredError returns the error mcp-go reports for a 401 from an OAuth
+// backend whose authorization server is served by a test server.
+func oauthRequiredError(t *testing.T) error {
+	t.Helper()
+
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 "https://auth.example",
+			"authorization_endpoint": "https://auth.example/authorize",
+			"token_endpoint":         "https://auth.example/token",
+		})
+	}))
+	t.Cleanup(metadata.Close)
+
+	return &transport.OAuthAuthorizationRequiredError{
+		Handler: transport.NewOAuthHandler(transport.OAuthConfig{
+			ClientID:              "assern",
+			RedirectURI:           "http://localhost:8085/callback",
+			AuthServerMetadataURL: metadata.URL,
+			PKCEEnabled:           true,
+		}),
+	}
+}
+
+func TestToolCallNeedsAuth(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	bus := events.New(slog.New(slog.DiscardHandler), rec)
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), Events: bus})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	mock.ServerCfg = &config.ServerConfig{
+		URL:   "https://github.example/mcp",
+		Retry: &config.RetryConfig{MaxAttempts: 5},
+	}
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_search")
+	handler := agg.createToolHandler(entry)
+	mock.CallErr = oauthRequiredError(t)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{}
+
+	for range 3 {
+		result, err := handler(t.Context(), req)
+		if err != nil || !result.IsError {
+			t.Fatalf("handler = %+v, %v; want a tool error", result, err)
+		}
+
+		data, _ := result.StructuredContent.(map[string]any)
+		if data["error"] != "authorization_required" {
+			t.Fatalf("structured content = %v", result.StructuredContent)
+		}
+
+		if url, _ := data["authorization_url"].(string); !strings.HasPrefix(url, "https://auth.example/authorize?") {
+			t.Errorf("authorization_url = %q", url)
+		}
+	}
+
+	// Auth errors are not retried, and later calls fail fast
+	if len(mock.ToolCalls) != 1 {
+		t.Errorf("backend calls = %d, want 1", len(mock.ToolCalls))
+	}
+
+	if got := agg.ServerHealth("github"); got != HealthNeedsAuth {
+		t.Errorf("health = %s, want %s", got, HealthNeedsAuth)
+	}
+
+	if status := agg.Status(); status.ServersDown != 1 || status.Servers[0].AuthURL == "" {
+		t.Errorf("status = %+v, want github down with an authorization URL", status)
+	}
+
+	bus.Close()
+
+	if len(rec.events) != 1 || rec.events[0].Type != events.AuthExpired {
+		t.Errorf("events = %v, want one auth_expired", rec.events)
+	}
+}
+
+func TestHealthTrackerNeedsAuth(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	h := newHealthTracker(3, clk)
+
+	if _, blocked := h.NeedsAuth("api", authRetryInterval); blocked {
+		t.Error("unknown server blocked")
+	}
+
+	if !h.MarkNeedsAuth("api", "https://auth.example/authorize") {
+		t.Error("first MarkNeedsAuth() = false, want transition")
+	}
+
+	if h.MarkNeedsAuth("api", "") {
+		t.Error("second MarkNeedsAuth() = true, want no transition")
+	}
+
+	url, blocked := h.NeedsAuth("api", authRetryInterval)
+	if !blocked || url != "https://auth.example/authorize" {
+		t.Errorf("NeedsAuth() = %q, %v", url, blocked)
+	}
+
+	clk.Advance(authRetryInterval - time.Second)
+
+	if _, blocked := h.NeedsAuth("api", authRetryInterval); !blocked {
+		t.Error("NeedsAuth() let a call through before the retry interval")
+	}
+
+	clk.Advance(time.Second)
+
+	if _, blocked := h.NeedsAuth("api", authRetryInterval); blocked {
+		t.Error("NeedsAuth() blocked after the retry interval")
+	}
+
+	if h.IsHealthy("api") {
+		t.Error("IsHealthy() = true while needing auth")
+	}
+
+	h.RecordSuccess("api")
+
+	if stats := h.Stats("api"); stats.Status != HealthHealthy || stats.AuthURL != "" {
+		t.Errorf("after success: %+v", stats)
+	}
+}
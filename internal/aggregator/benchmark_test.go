@@ -2,13 +2,17 @@ package aggregator
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
 )
 
 func BenchmarkToolRegistry_Register(b *testing.B) {
@@ -354,3 +358,69 @@ func BenchmarkPromptRegistry_All(b *testing.B) {
 		_ = registry.All()
 	}
 }
+
+// largeResourceServer returns a fresh resource of size bytes on every read,
+// like a client decoding a backend's response.
+type largeResourceServer struct {
+	*testutil.MockServer
+
+	size int
+}
+
+func (s *largeResourceServer) ReadResource(_ context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, Text: strings.Repeat("x", s.size)}},
+	}, nil
+}
+
+// BenchmarkReadLargeResource reads a 100MB resource through resources/read
+// and encodes the response, without a limit and with max_resource_size
+// refusing it by the size its listing declares or by its contents.
+func BenchmarkReadLargeResource(b *testing.B) {
+	const size = 100 << 20
+
+	declared := int64(size)
+
+	benchmarks := []struct {
+		name  string
+		limit config.ByteSize
+		size  *int64
+	}{
+		{name: "unlimited"},
+		{name: "declared_size", limit: 10 << 20, size: &declared},
+		{name: "undeclared_size", limit: 10 << 20},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			cfg := config.NewConfig()
+			cfg.Settings.MaxResourceSize = bm.limit
+
+			agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				b.Fatalf("New: %v", err)
+			}
+
+			srv := &largeResourceServer{MockServer: testutil.NewMockServer("data", nil), size: size}
+			srv.Resources = []mcp.Resource{{URI: "file:///data/dump.csv", Name: "dump", Size: bm.size}}
+
+			if err := agg.AddServer(b.Context(), srv); err != nil {
+				b.Fatalf("AddServer: %v", err)
+			}
+
+			mcpServer := agg.CreateMCPServer()
+			request := []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"` +
+				agg.resources.GetByServer("data")[0].PrefixedURI + `"}}`)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				response := mcpServer.HandleMessage(b.Context(), request)
+				if _, err := json.Marshal(response); err != nil {
+					b.Fatalf("encoding response: %v", err)
+				}
+			}
+		})
+	}
+}
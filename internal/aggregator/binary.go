@@ -0,0 +1,272 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // Decoded for thumbnails
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// BlobURIPrefix is the URI prefix of binary content that a tool result
+// references instead of inlining it (binary_content mode "reference").
+const BlobURIPrefix = "assern://blob/"
+
+// blobStoreMaxBytes bounds the memory held by referenced blobs; the oldest
+// blobs are dropped beyond it.
+const blobStoreMaxBytes = 64 << 20
+
+// blobStore keeps referenced binary content in memory until clients read it.
+type blobStore struct {
+	mu    sync.Mutex
+	blobs map[string]blob
+	order []string // IDs, oldest first
+	size  int
+	max   int
+}
+
+type blob struct {
+	mimeType string
+	data     []byte
+}
+
+func newBlobStore(maxBytes int) *blobStore {
+	return &blobStore{blobs: make(map[string]blob), max: maxBytes}
+}
+
+// put stores data and returns its URI, dropping the oldest blobs to stay
+// within the size limit.
+func (s *blobStore) put(mimeType string, data []byte) string {
+	id := rand.Text()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[id] = blob{mimeType: mimeType, data: data}
+	s.order = append(s.order, id)
+	s.size += len(data)
+
+	for s.size > s.max && len(s.order) > 1 {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		s.size -= len(s.blobs[oldest].data)
+		delete(s.blobs, oldest)
+	}
+
+	return BlobURIPrefix + id
+}
+
+// get returns the blob with uri.
+func (s *blobStore) get(uri string) (blob, bool) {
+	id, ok := strings.CutPrefix(uri, BlobURIPrefix)
+	if !ok {
+		return blob{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.blobs[id]
+
+	return b, ok
+}
+
+// registerBlobTemplate exposes referenced blobs as the assern://blob/{id}
+// resource template.
+func (a *Aggregator) registerBlobTemplate() {
+	a.mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(
+		BlobURIPrefix+"{id}",
+		"Tool result content",
+		mcp.WithTemplateDescription("Binary content of a tool result, referenced instead of inlined (binary_content)"),
+	), a.handleBlobResource)
+}
+
+func (a *Aggregator) handleBlobResource(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	b, ok := a.blobs.get(req.Params.URI)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (blobs are kept in memory and dropped on restart or when the store is full)",
+			ErrBlobNotFound, req.Params.URI)
+	}
+
+	return []mcp.ResourceContents{mcp.BlobResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: b.mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(b.data),
+	}}, nil
+}
+
+// encodeBinaryContent applies the binary_content mode of tool to the
+// image, audio and embedded blob content of result. The result may be
+// shared with coalesced callers, so a changed copy is returned.
+func (a *Aggregator) encodeBinaryContent(result *mcp.CallToolResult, cfg *config.ServerConfig, tool string) *mcp.CallToolResult {
+	mode := cfg.BinaryMode(tool)
+	if result == nil || mode == config.BinaryKeep {
+		return result
+	}
+
+	var (
+		content []mcp.Content
+		changed bool
+	)
+
+	for _, c := range result.Content {
+		encoded, ok := a.encodeBinary(c, mode, cfg.BinaryContent)
+		if ok {
+			changed = true
+		}
+
+		content = append(content, encoded...)
+	}
+
+	if !changed {
+		return result
+	}
+
+	out := *result
+	out.Content = content
+
+	return &out
+}
+
+// encodeBinary applies mode to one content item, returning it unchanged
+// and false when it is not binary, is small enough or cannot be decoded.
+// Content is not compared to tell: embedded resources are not comparable.
+func (a *Aggregator) encodeBinary(c mcp.Content, mode string, opts *config.BinaryContentConfig) ([]mcp.Content, bool) {
+	var kind, mimeType, encoded string
+
+	switch v := c.(type) {
+	case mcp.ImageContent:
+		kind, mimeType, encoded = "image", v.MIMEType, v.Data
+	case mcp.AudioContent:
+		kind, mimeType, encoded = "audio", v.MIMEType, v.Data
+	case mcp.EmbeddedResource:
+		res, ok := v.Resource.(mcp.BlobResourceContents)
+		if !ok {
+			return []mcp.Content{c}, false
+		}
+
+		kind, mimeType, encoded = "resource", res.MIMEType, res.Blob
+	default:
+		return []mcp.Content{c}, false
+	}
+
+	if base64.StdEncoding.DecodedLen(len(encoded)) <= int(opts.MinSize) {
+		return []mcp.Content{c}, false
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return []mcp.Content{c}, false
+	}
+
+	switch mode {
+	case config.BinaryStrip:
+		return []mcp.Content{mcp.NewTextContent(fmt.Sprintf("[%s content omitted: %s, %d bytes]", kind, mimeType, len(data)))}, true
+	case config.BinaryThumbnail:
+		if kind == "image" {
+			if thumb, ok := thumbnail(data, opts); ok {
+				return []mcp.Content{thumb, a.blobLink(kind, mimeType, data)}, true
+			}
+		}
+	}
+
+	return []mcp.Content{a.blobLink(kind, mimeType, data)}, true
+}
+
+// blobLink stores data and returns a link to it.
+func (a *Aggregator) blobLink(kind, mimeType string, data []byte) mcp.ResourceLink {
+	uri := a.blobs.put(mimeType, data)
+
+	return mcp.NewResourceLink(uri, kind+" content",
+		fmt.Sprintf("%d bytes of %s content; read this resource to fetch it", len(data), kind), mimeType)
+}
+
+// thumbnail scales an image down to the configured longest side, returning
+// false when it cannot be decoded or the thumbnail exceeds the size limit.
+// JPEG stays JPEG; other formats become PNG.
+func thumbnail(data []byte, opts *config.BinaryContentConfig) (mcp.ImageContent, bool) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return mcp.ImageContent{}, false
+	}
+
+	var buf bytes.Buffer
+
+	mimeType := "image/png"
+	dst := scaleDown(src, opts.EffectiveThumbnailMaxDimension())
+
+	if format == "jpeg" {
+		mimeType = "image/jpeg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+
+	if err != nil || buf.Len() > int(opts.EffectiveThumbnailMaxSize()) {
+		return mcp.ImageContent{}, false
+	}
+
+	return mcp.NewImageContent(base64.StdEncoding.EncodeToString(buf.Bytes()), mimeType), true
+}
+
+// scaleDown shrinks src so its longest side is at most maxSide, averaging
+// the source pixels each destination pixel covers. Smaller images are
+// returned as is.
+func scaleDown(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+
+	dw, dh := maxSide, max(1, h*maxSide/w)
+	if h > w {
+		dw, dh = max(1, w*maxSide/h), maxSide
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := range dh {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+
+		for x := range dw {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var sum [4]int
+
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+x0*4 : sy*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			off := y*dst.Stride + x*4
+
+			for i := range sum {
+				dst.Pix[off+i] = uint8(sum[i] / n)
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,224 @@
+package aggregator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func pngData(t *testing.T, w, h int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestEncodeBinaryContent(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	img := mcp.NewImageContent(pngData(t, 1000, 500), "image/png")
+	audio := mcp.NewAudioContent(base64.StdEncoding.EncodeToString(make([]byte, 4096)), "audio/wav")
+	text := mcp.NewTextContent("caption")
+	embeddedText := mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "file:///notes.md", MIMEType: "text/markdown", Text: "notes"})
+	smallBlob := mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+		URI: "file:///small.bin", MIMEType: "application/octet-stream", Blob: base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	})
+
+	tests := []struct {
+		name    string
+		binary  *config.BinaryContentConfig
+		tool    string
+		content []mcp.Content
+		check   func(t *testing.T, content []mcp.Content)
+	}{
+		{
+			name:    "keep by default",
+			content: []mcp.Content{img},
+			check: func(t *testing.T, content []mcp.Content) {
+				if len(content) != 1 || content[0] != mcp.Content(img) {
+					t.Errorf("content = %v, want the image unchanged", content)
+				}
+			},
+		},
+		{
+			name:    "strip",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryStrip},
+			content: []mcp.Content{text, audio},
+			check: func(t *testing.T, content []mcp.Content) {
+				note, ok := content[1].(mcp.TextContent)
+				if content[0] != mcp.Content(text) || !ok || !strings.Contains(note.Text, "audio/wav, 4096 bytes") {
+					t.Errorf("content = %v, want the caption and a note", content)
+				}
+			},
+		},
+		{
+			name:    "reference",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryReference},
+			content: []mcp.Content{audio},
+			check: func(t *testing.T, content []mcp.Content) {
+				link, ok := content[0].(mcp.ResourceLink)
+				if !ok || !strings.HasPrefix(link.URI, BlobURIPrefix) || link.MIMEType != "audio/wav" {
+					t.Errorf("content = %v, want a blob link", content)
+				}
+			},
+		},
+		{
+			name:    "thumbnail",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryThumbnail},
+			content: []mcp.Content{img},
+			check: func(t *testing.T, content []mcp.Content) {
+				thumb, ok := content[0].(mcp.ImageContent)
+				if !ok || len(content) != 2 {
+					t.Fatalf("content = %v, want a thumbnail and a link", content)
+				}
+
+				data, _ := base64.StdEncoding.DecodeString(thumb.Data)
+
+				cfg, err := png.DecodeConfig(bytes.NewReader(data))
+				if err != nil || cfg.Width != 256 || cfg.Height != 128 {
+					t.Errorf("thumbnail = %dx%d (%v), want 256x128", cfg.Width, cfg.Height, err)
+				}
+
+				if _, ok := content[1].(mcp.ResourceLink); !ok {
+					t.Errorf("content[1] = %T, want a link to the original", content[1])
+				}
+			},
+		},
+		{
+			name:    "thumbnail of non-image is referenced",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryThumbnail},
+			content: []mcp.Content{audio},
+			check: func(t *testing.T, content []mcp.Content) {
+				if _, ok := content[0].(mcp.ResourceLink); !ok || len(content) != 1 {
+					t.Errorf("content = %v, want a blob link", content)
+				}
+			},
+		},
+		{
+			name:    "below min_size",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryStrip, MinSize: 8192},
+			content: []mcp.Content{audio},
+			check: func(t *testing.T, content []mcp.Content) {
+				if content[0] != mcp.Content(audio) {
+					t.Errorf("content = %v, want the audio unchanged", content)
+				}
+			},
+		},
+		{
+			name:    "embedded text resource",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryStrip},
+			content: []mcp.Content{embeddedText},
+			check: func(t *testing.T, content []mcp.Content) {
+				if len(content) != 1 || !reflect.DeepEqual(content[0], mcp.Content(embeddedText)) {
+					t.Errorf("content = %v, want the text resource unchanged", content)
+				}
+			},
+		},
+		{
+			name:    "embedded blob below min_size",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryReference, MinSize: 1024},
+			content: []mcp.Content{smallBlob},
+			check: func(t *testing.T, content []mcp.Content) {
+				if len(content) != 1 || !reflect.DeepEqual(content[0], mcp.Content(smallBlob)) {
+					t.Errorf("content = %v, want the blob unchanged", content)
+				}
+			},
+		},
+		{
+			name:    "tool override",
+			binary:  &config.BinaryContentConfig{Mode: config.BinaryStrip, Tools: map[string]string{"screenshot": config.BinaryKeep}},
+			tool:    "screenshot",
+			content: []mcp.Content{img},
+			check: func(t *testing.T, content []mcp.Content) {
+				if content[0] != mcp.Content(img) {
+					t.Errorf("content = %v, want the image unchanged", content)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			original := slices.Clone(tt.content)
+			result := &mcp.CallToolResult{Content: tt.content}
+
+			got := agg.encodeBinaryContent(result, &config.ServerConfig{BinaryContent: tt.binary}, tt.tool)
+			tt.check(t, got.Content)
+
+			// The result may be shared with coalesced callers
+			for i := range original {
+				if !reflect.DeepEqual(result.Content[i], original[i]) {
+					t.Fatal("the backend result was modified")
+				}
+			}
+		})
+	}
+}
+
+func TestBlobResource(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	uri := agg.blobs.put("audio/wav", []byte("RIFF"))
+
+	var req mcp.ReadResourceRequest
+	req.Params.URI = uri
+
+	contents, err := agg.handleBlobResource(t.Context(), req)
+	if err != nil {
+		t.Fatalf("handleBlobResource: %v", err)
+	}
+
+	blob, ok := contents[0].(mcp.BlobResourceContents)
+	if !ok || blob.Blob != base64.StdEncoding.EncodeToString([]byte("RIFF")) || blob.MIMEType != "audio/wav" {
+		t.Errorf("contents = %+v, want the stored blob", contents)
+	}
+
+	req.Params.URI = BlobURIPrefix + "unknown"
+	if _, err := agg.handleBlobResource(t.Context(), req); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("unknown blob error = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestBlobStoreDropsOldest(t *testing.T) {
+	t.Parallel()
+
+	store := newBlobStore(10)
+
+	first := store.put("application/octet-stream", make([]byte, 6))
+	second := store.put("application/octet-stream", make([]byte, 6))
+
+	if _, ok := store.get(first); ok {
+		t.Error("oldest blob kept beyond the size limit")
+	}
+
+	if _, ok := store.get(second); !ok {
+		t.Error("newest blob dropped")
+	}
+}
@@ -0,0 +1,214 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// standby is a backend started by a blue-green reload alongside the server
+// it replaces (or for a server the new configuration adds).
+type standby struct {
+	name   string
+	server Server
+	tools  []mcp.Tool
+	timing ServerTiming
+	// resources and prompts are discovered before the swap, so that
+	// promote does not ask the backend while holding a.mu
+	resources []mcp.Resource
+	prompts   []mcp.Prompt
+}
+
+// startStandbys starts the added and modified servers of diff that are not
+// lazy, without touching the running ones. When one fails, those already
+// started are stopped again and the error is returned. The returned diff
+// holds what is left for a regular reload: removed servers and lazy ones.
+func (a *Aggregator) startStandbys(ctx context.Context, newCfg *config.Config, diff *config.ConfigDiff) ([]*standby, *config.ConfigDiff, error) {
+	effective := config.GetEffectiveServers(newCfg)
+	rest := &config.ConfigDiff{Removed: diff.Removed}
+	lazyStart := a.FeatureEnabled(config.FeatureLazyStart)
+
+	var standbys []*standby
+
+	abort := func(name string, err error) ([]*standby, *config.ConfigDiff, error) {
+		for _, sb := range standbys {
+			if stopErr := sb.server.Stop(); stopErr != nil {
+				a.logger.Warn("error stopping standby server", "server", sb.name, "error", stopErr)
+			}
+		}
+
+		a.publish(events.ServerFailed, name, err.Error(), map[string]any{"blue_green": true})
+
+		return nil, nil, fmt.Errorf("%w: starting %s: %w", ErrBlueGreenAborted, name, err)
+	}
+
+	for _, names := range [][]string{diff.Added, diff.Modified} {
+		for _, name := range slices.Sorted(slices.Values(names)) {
+			cfg := effective[name]
+
+			// A lazy server has no process to keep warm; it is swapped
+			// the regular way and started by its first call
+			if cfg.Lazy && lazyStart {
+				if slices.Contains(diff.Added, name) {
+					rest.Added = append(rest.Added, name)
+				} else {
+					rest.Modified = append(rest.Modified, name)
+				}
+
+				continue
+			}
+
+			managed, err := a.newManagedServer(ctx, name, cfg)
+			if err != nil {
+				return abort(name, err)
+			}
+
+			tools, timing, err := a.startManaged(ctx, managed)
+			if err != nil {
+				return abort(name, err)
+			}
+
+			resources, prompts := a.discoverResourcesAndPrompts(ctx, name, managed)
+
+			a.logger.Info("standby server ready", "server", name, "tools", len(tools))
+
+			standbys = append(standbys, &standby{
+				name: name, server: managed, tools: tools, timing: timing,
+				resources: resources, prompts: prompts,
+			})
+		}
+	}
+
+	return standbys, rest, nil
+}
+
+// promote makes a standby the server behind its name, replacing the tools,
+// resources and prompts of the server it takes over from in one step, and
+// returns that server (nil for an added server). Calls already routed to
+// the replaced server continue there; see retireServers.
+func (a *Aggregator) promote(sb *standby) Server {
+	cfg := sb.server.Config()
+
+	// The replaced server's loops would act on the new one
+	a.probes.stop(sb.name)
+	a.supervisors.stop(sb.name)
+	a.watchers.stop(sb.name)
+	a.lazy.remove(sb.name)
+
+	a.cacheTools(sb.name, cfg, sb.tools)
+
+	a.mu.Lock()
+
+	old := a.servers[sb.name]
+	previous := a.tools.GetByServer(sb.name)
+	previousResources := a.resources.GetByServer(sb.name)
+	previousPrompts := a.prompts.GetByServer(sb.name)
+
+	a.tools.RemoveServer(sb.name)
+
+	if err := a.registerTools(sb.name, cfg, sb.tools); err != nil {
+		a.logger.Error("some tools were not registered", "server", sb.name, "error", err)
+	}
+
+	a.resources.RemoveServer(sb.name)
+	a.prompts.RemoveServer(sb.name)
+	a.registerDiscovered(sb.name, sb.server, sb.resources, sb.prompts)
+
+	a.servers[sb.name] = sb.server
+
+	a.mu.Unlock()
+
+	a.updateExposedTools(sb.name, previous)
+	a.updateExposedResources(sb.name, previousResources, previousPrompts)
+	a.runtime.started(sb.name, sb.timing)
+	a.health.Reset(sb.name)
+
+	a.startHealthProbe(sb.name, cfg)
+
+	if managed, ok := sb.server.(*ManagedServer); ok {
+		a.superviseServer(sb.name, managed)
+		a.watchToolChanges(sb.name, managed)
+	}
+
+	a.publish(events.ServerStarted, sb.name, "promoted by blue-green reload", map[string]any{"tools": len(sb.tools)})
+
+	return old
+}
+
+// updateExposedResources updates the MCP server to the resources and
+// prompts now registered for a server, like updateExposedTools. Each kind
+// that changes sends its list_changed notification.
+func (a *Aggregator) updateExposedResources(name string, oldResources []*ResourceEntry, oldPrompts []*PromptEntry) {
+	if a.mcpServer == nil {
+		return
+	}
+
+	a.mu.RLock()
+	resources := a.resources.GetByServer(name)
+	prompts := a.prompts.GetByServer(name)
+	a.mu.RUnlock()
+
+	var goneURIs []string
+	for _, entry := range oldResources {
+		if _, ok := a.resources.Get(entry.PrefixedURI); !ok {
+			goneURIs = append(goneURIs, entry.PrefixedURI)
+		}
+	}
+
+	if len(goneURIs) > 0 {
+		a.mcpServer.DeleteResources(goneURIs...)
+	}
+
+	if len(resources) > 0 {
+		added := make([]server.ServerResource, 0, len(resources))
+		for _, entry := range resources {
+			added = append(added, a.serverResource(entry))
+		}
+
+		a.mcpServer.AddResources(added...)
+	}
+
+	var goneNames []string
+	for _, entry := range oldPrompts {
+		if _, ok := a.prompts.Get(entry.PrefixedName); !ok {
+			goneNames = append(goneNames, entry.PrefixedName)
+		}
+	}
+
+	if len(goneNames) > 0 {
+		a.mcpServer.DeletePrompts(goneNames...)
+	}
+
+	if len(prompts) > 0 {
+		added := make([]server.ServerPrompt, 0, len(prompts))
+		for _, entry := range prompts {
+			added = append(added, a.serverPrompt(entry))
+		}
+
+		a.mcpServer.AddPrompts(added...)
+	}
+}
+
+// retireServers stops the servers a blue-green reload replaced, once the
+// calls routed to them before the swap have ended or the drain timeout has
+// passed. Calls begun since reach the new servers and are not waited for.
+func (a *Aggregator) retireServers(ctx context.Context, retired map[string]Server) {
+	names := slices.Sorted(maps.Keys(retired))
+
+	a.awaitCalls(ctx, names, a.calls.handoff(names))
+
+	for _, name := range names {
+		if err := retired[name].Stop(); err != nil {
+			a.logger.Warn("error stopping replaced server", "server", name, "error", err)
+		} else {
+			a.logger.Info("replaced server stopped", "server", name)
+		}
+	}
+}
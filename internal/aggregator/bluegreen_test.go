@@ -0,0 +1,279 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+	"github.com/valksor/go-assern/internal/events"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// writeHelperMCP writes a global mcp.json under home with one "helper"
+// server running TestStdioHelperProcess; version tells configurations apart.
+func writeHelperMCP(t *testing.T, home, command, version string) {
+	t.Helper()
+
+	dir := filepath.Join(home, ".valksor", "assern")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("creating config dir: %v", err)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"mcpServers": map[string]any{
+			"helper": map[string]any{
+				"command": command,
+				"args":    []string{"-test.run=^TestStdioHelperProcess$"},
+				"env":     map[string]string{envStdioHelper: "1", envHelperVersion: version},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("encoding mcp.json: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "mcp.json"), data, 0o644); err != nil {
+		t.Fatalf("writing mcp.json: %v", err)
+	}
+}
+
+func TestReloadBlueGreen(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeHelperMCP(t, home, os.Args[0], "1")
+
+	cfg, err := config.LoadEffective(home, "", config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadEffective: %v", err)
+	}
+
+	cfg.Settings.DrainTimeout = time.Minute
+
+	published := make(eventChannel, 16)
+
+	agg, err := New(Options{
+		Config:    cfg,
+		EnvLoader: env.NewLoader(),
+		Logger:    slog.New(slog.DiscardHandler),
+		Events:    events.New(slog.New(slog.DiscardHandler), published),
+		WorkDir:   home,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = agg.Stop() }()
+
+	mcpServer := agg.CreateMCPServer()
+
+	sess := newFakeSession("bluegreen-1")
+	registerSession(t, mcpServer, sess)
+
+	agg.mu.RLock()
+	old, _ := agg.servers["helper"].(*ManagedServer)
+	agg.mu.RUnlock()
+
+	// A call routed to the running server holds its retirement
+	done, err := agg.calls.begin("helper")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	writeHelperMCP(t, home, os.Args[0], "2")
+
+	type reloaded struct {
+		result *ReloadResult
+		err    error
+	}
+
+	ch := make(chan reloaded, 1)
+
+	go func() {
+		result, err := agg.ReloadBlueGreen(t.Context())
+		ch <- reloaded{result, err}
+	}()
+
+	// The new server takes over while the old one still has a call
+	published.wait(t, events.ServerStarted, "promoted by blue-green reload")
+
+	if !old.IsStarted() {
+		t.Error("replaced server stopped with a call in flight")
+	}
+
+	// Clients see the new server's resources and prompts, and are told
+	ctx := mcpServer.WithContext(t.Context(), sess)
+
+	var resources struct {
+		Resources []mcp.Resource `json:"resources"`
+	}
+	aclRequest(t, ctx, mcpServer, "resources/list", nil, &resources)
+
+	if got, want := resourceURIs(resources.Resources), []string{"assern://helper/file:///v2.md"}; !slices.Equal(got, want) {
+		t.Errorf("resources/list after promotion = %v, want %v", got, want)
+	}
+
+	var prompts struct {
+		Prompts []mcp.Prompt `json:"prompts"`
+	}
+	aclRequest(t, ctx, mcpServer, "prompts/list", nil, &prompts)
+
+	if len(prompts.Prompts) != 1 || prompts.Prompts[0].Name != "helper_v2" {
+		t.Errorf("prompts/list after promotion = %+v, want helper_v2", prompts.Prompts)
+	}
+
+	notes := make(map[string]int)
+	for len(sess.notes) > 0 {
+		notes[(<-sess.notes).Method]++
+	}
+
+	for _, method := range []string{mcp.MethodNotificationResourcesListChanged, mcp.MethodNotificationPromptsListChanged} {
+		if notes[method] == 0 {
+			t.Errorf("no %s notification after promotion, got %v", method, notes)
+		}
+	}
+
+	// New calls reach the new server instead of being refused
+	result, err := agg.CallTool(t.Context(), "helper_echo", nil)
+	if err != nil || result.IsError {
+		t.Fatalf("CallTool during handoff = %+v, %v", result, err)
+	}
+
+	select {
+	case r := <-ch:
+		t.Fatalf("reload returned before the earlier call ended: %+v, %v", r.result, r.err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	done()
+
+	var r reloaded
+
+	select {
+	case r = <-ch:
+	case <-time.After(10 * time.Second):
+		t.Fatal("reload did not finish after the earlier call ended")
+	}
+
+	if r.err != nil {
+		t.Fatalf("ReloadBlueGreen: %v", r.err)
+	}
+
+	if r.result.Replaced != 1 || r.result.Added != 0 || len(r.result.Errors) != 0 {
+		t.Errorf("ReloadBlueGreen() = %+v, want one replaced server", r.result)
+	}
+
+	if old.IsStarted() {
+		t.Error("replaced server still running after the reload")
+	}
+
+	if _, ok := agg.tools.Get("helper_echo"); !ok {
+		t.Error("tools of the new server not registered")
+	}
+}
+
+func TestReloadBlueGreenAborts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeHelperMCP(t, home, os.Args[0], "1")
+
+	cfg, err := config.LoadEffective(home, "", config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadEffective: %v", err)
+	}
+
+	agg, err := New(Options{
+		Config:    cfg,
+		EnvLoader: env.NewLoader(),
+		Logger:    slog.New(slog.DiscardHandler),
+		WorkDir:   home,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = agg.Stop() }()
+
+	agg.mu.RLock()
+	old, _ := agg.servers["helper"].(*ManagedServer)
+	agg.mu.RUnlock()
+
+	writeHelperMCP(t, home, filepath.Join(home, "missing-command"), "2")
+
+	if _, err := agg.ReloadBlueGreen(t.Context()); !errors.Is(err, ErrBlueGreenAborted) {
+		t.Fatalf("ReloadBlueGreen() error = %v, want ErrBlueGreenAborted", err)
+	}
+
+	agg.mu.RLock()
+	current := agg.servers["helper"]
+	agg.mu.RUnlock()
+
+	if current != Server(old) || !old.IsStarted() {
+		t.Error("running server replaced or stopped by an aborted reload")
+	}
+
+	agg.cfgMu.RLock()
+	command := agg.cfg.Servers["helper"].Command
+	agg.cfgMu.RUnlock()
+
+	if command != os.Args[0] {
+		t.Error("configuration swapped by an aborted reload")
+	}
+
+	result, err := agg.CallTool(t.Context(), "helper_echo", nil)
+	if err != nil || result.IsError {
+		t.Errorf("CallTool after an aborted reload = %+v, %v", result, err)
+	}
+}
+
+func TestPromoteRegistersDiscoveredResources(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	agg.CreateMCPServer()
+
+	// Asking the backend while promoting would fail: promote must use what
+	// the standby discovered before the swap
+	mock := testutil.NewMockServer("docs", nil)
+	mock.ResourcesErr = errors.New("backend asked under the lock")
+	mock.PromptsErr = mock.ResourcesErr
+
+	sb := &standby{
+		name:      "docs",
+		server:    mock,
+		resources: []mcp.Resource{mcp.NewResource("file:///guide.md", "guide")},
+		prompts:   []mcp.Prompt{mcp.NewPrompt("summarize")},
+	}
+
+	if old := agg.promote(sb); old != nil {
+		t.Errorf("promote() = %v, want no replaced server", old)
+	}
+
+	if resources := agg.ListResources(); len(resources) != 1 || resources[0].OriginalURI != "file:///guide.md" {
+		t.Errorf("resources = %+v, want the standby's", resources)
+	}
+
+	if prompts := agg.ListPrompts(); len(prompts) != 1 || prompts[0].PrefixedName != "docs_summarize" {
+		t.Errorf("prompts = %+v, want the standby's", prompts)
+	}
+}
@@ -0,0 +1,112 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// descriptionEllipsis ends descriptions cut by max_description_length.
+const descriptionEllipsis = "…"
+
+// withBudget returns tool with its description replaced by the server's
+// tool_descriptions entry for it and cut to max_description_length, and
+// the examples of its input schema dropped under strip_schema_examples.
+func (a *Aggregator) withBudget(tool mcp.Tool, cfg *config.ServerConfig) mcp.Tool {
+	if cfg != nil {
+		if desc, ok := cfg.ToolDescriptions[tool.Name]; ok {
+			tool.Description = desc
+		}
+	}
+
+	budget := cfg.ToolBudget(a.toolBudget)
+	tool.Description = truncateDescription(tool.Description, budget.MaxDescriptionLength)
+
+	if budget.StripSchemaExamples {
+		tool.InputSchema = stripSchemaExamples(tool.InputSchema)
+	}
+
+	return tool
+}
+
+// truncateDescription cuts desc to limit characters, the last of them an
+// ellipsis. A limit of zero keeps it whole.
+func truncateDescription(desc string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(desc) <= limit {
+		return desc
+	}
+
+	runes := []rune(desc)
+
+	return strings.TrimRightFunc(string(runes[:limit-1]), unicode.IsSpace) + descriptionEllipsis
+}
+
+// stripSchemaExamples returns a copy of schema without the "examples" and
+// "example" keywords of it and its subschemas. Properties of those names
+// are kept. A schema that cannot be copied is returned as it is.
+func stripSchemaExamples(schema mcp.ToolInputSchema) mcp.ToolInputSchema {
+	data, err := json.Marshal(schema)
+	if err != nil || !strings.Contains(string(data), `"example`) {
+		return schema
+	}
+
+	var copied map[string]any
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return schema
+	}
+
+	stripExamples(copied)
+
+	if data, err = json.Marshal(copied); err != nil {
+		return schema
+	}
+
+	var stripped mcp.ToolInputSchema
+	if err := json.Unmarshal(data, &stripped); err != nil {
+		return schema
+	}
+
+	return stripped
+}
+
+// stripExamples drops the example keywords of schema and, recursively, of
+// the subschemas it holds.
+func stripExamples(schema map[string]any) {
+	delete(schema, "examples")
+	delete(schema, "example")
+
+	for keyword, value := range schema {
+		switch keyword {
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			// Maps of names to subschemas
+			if named, ok := value.(map[string]any); ok {
+				for _, sub := range named {
+					stripSubschemas(sub)
+				}
+			}
+		case "items", "additionalProperties", "additionalItems", "prefixItems", "contains",
+			"not", "if", "then", "else", "allOf", "anyOf", "oneOf", "propertyNames":
+			stripSubschemas(value)
+		}
+	}
+}
+
+// stripSubschemas strips the examples of value, a subschema or a list of
+// them.
+func stripSubschemas(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		stripExamples(v)
+	case []any:
+		for _, item := range v {
+			if sub, ok := item.(map[string]any); ok {
+				stripExamples(sub)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestTruncateDescription(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		desc  string
+		limit int
+		want  string
+	}{
+		{name: "no limit", desc: "Search repositories", limit: 0, want: "Search repositories"},
+		{name: "within limit", desc: "Search repositories", limit: 19, want: "Search repositories"},
+		{name: "cut", desc: "Search repositories", limit: 10, want: "Search re…"},
+		{name: "trailing space dropped", desc: "Search repositories", limit: 8, want: "Search…"},
+		{name: "runes counted", desc: "Ищет репозитории", limit: 5, want: "Ищет…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := truncateDescription(tt.desc, tt.limit); got != tt.want {
+				t.Errorf("truncateDescription(%q, %d) = %q, want %q", tt.desc, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripSchemaExamples(t *testing.T) {
+	t.Parallel()
+
+	tool := mcp.NewTool("search",
+		mcp.WithString("query", mcp.Description("Search terms")),
+		mcp.WithObject("example", mcp.Properties(map[string]any{
+			"limit": map[string]any{"type": "number", "examples": []any{10, 20}},
+		})),
+		mcp.WithArray("tags", mcp.Items(map[string]any{"type": "string", "example": "go"})),
+	)
+	tool.InputSchema.Properties["query"].(map[string]any)["examples"] = []any{"mcp server"}
+
+	stripped := stripSchemaExamples(tool.InputSchema)
+
+	data, err := json.Marshal(stripped)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	if strings.Contains(string(data), `"examples"`) || strings.Contains(string(data), `"example":"go"`) {
+		t.Errorf("stripped schema still has examples: %s", data)
+	}
+
+	// A property named example is not a keyword
+	if _, ok := stripped.Properties["example"]; !ok {
+		t.Errorf("stripped schema lost the example property: %s", data)
+	}
+
+	// The backend's schema is left alone
+	if _, ok := tool.InputSchema.Properties["query"].(map[string]any)["examples"]; !ok {
+		t.Error("stripSchemaExamples() changed the schema it was given")
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	t.Parallel()
+
+	agg := &Aggregator{
+		logger:     slog.New(slog.DiscardHandler),
+		toolBudget: config.ToolBudget{MaxDescriptionLength: 12},
+	}
+
+	tool := mcp.NewTool("search_repos", mcp.WithDescription("Search GitHub repositories by name"))
+	other := mcp.NewTool("create_issue", mcp.WithDescription("Open an issue in a repository"))
+
+	tests := []struct {
+		name string
+		tool mcp.Tool
+		cfg  *config.ServerConfig
+		want string
+	}{
+		{name: "settings limit", tool: tool, want: "Search GitH…"},
+		{name: "server limit", tool: tool, cfg: &config.ServerConfig{MaxDescriptionLength: 7}, want: "Search…"},
+		{name: "limit lifted", tool: tool, cfg: &config.ServerConfig{MaxDescriptionLength: -1}, want: "Search GitHub repositories by name"},
+		{
+			name: "description replaced, then cut",
+			tool: tool,
+			cfg:  &config.ServerConfig{ToolDescriptions: map[string]string{"search_repos": "Find repositories"}},
+			want: "Find reposi…",
+		},
+		{
+			name: "other tools keep theirs",
+			tool: other,
+			cfg:  &config.ServerConfig{ToolDescriptions: map[string]string{"search_repos": "Find repos"}, MaxDescriptionLength: -1},
+			want: "Open an issue in a repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := agg.withBudget(tt.tool, tt.cfg).Description; got != tt.want {
+				t.Errorf("withBudget() description = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package aggregator
+
+import (
+	"maps"
+	"slices"
+)
+
+// CallStats summarizes tool call activity since the aggregator started.
+type CallStats struct {
+	Servers    []ServerCallStats `json:"servers"`
+	Deprecated []DeprecatedUsage `json:"deprecated"`
+}
+
+// ServerCallStats counts the backend calls made to one server.
+type ServerCallStats struct {
+	Name     string  `json:"name"`
+	Calls    int64   `json:"calls"`
+	Failures int64   `json:"failures"`
+	FailRate float64 `json:"failure_rate"` // Percent
+}
+
+// CallStats returns per-server call counts and the usage of deprecated tools.
+func (a *Aggregator) CallStats() CallStats {
+	health := a.health.AllStats()
+
+	stats := CallStats{
+		Servers:    make([]ServerCallStats, 0, len(health)),
+		Deprecated: a.DeprecatedUsage(),
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(health)) {
+		h := health[name]
+		stats.Servers = append(stats.Servers, ServerCallStats{
+			Name:     name,
+			Calls:    h.TotalCalls,
+			Failures: h.TotalFailures,
+			FailRate: h.FailureRate(),
+		})
+	}
+
+	return stats
+}
@@ -0,0 +1,123 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// progressKey is the context key of the function a tool call reports its
+// backend's progress notifications to.
+type progressKey struct{}
+
+// withProgress returns a context whose tool calls request progress
+// notifications from the backend and call notify for each of them (see
+// ManagedServer.CallTool).
+func withProgress(ctx context.Context, notify func()) context.Context {
+	return context.WithValue(ctx, progressKey{}, notify)
+}
+
+// progressNotify returns the function set by withProgress, or nil.
+func progressNotify(ctx context.Context) func() {
+	notify, _ := ctx.Value(progressKey{}).(func())
+
+	return notify
+}
+
+// callTimeout returns the call timeout of a server with cfg, or nil (see
+// config.EffectiveCallTimeout).
+func (a *Aggregator) callTimeout(cfg *config.ServerConfig) *config.CallTimeoutConfig {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	var settings *config.Settings
+	if a.cfg != nil {
+		settings = a.cfg.Settings
+	}
+
+	return config.EffectiveCallTimeout(cfg, settings).Clone()
+}
+
+// callWithTimeout calls tool on srv, failing the call with a
+// CallTimeoutError when timeout runs out. Without a timeout the call waits
+// for as long as ctx allows. A ManagedServer asks its backend for progress
+// notifications, which restart the idle timeout.
+func callWithTimeout(
+	ctx context.Context,
+	clk clock.Clock,
+	srv Server,
+	tool string,
+	args map[string]any,
+	timeout *config.CallTimeoutConfig,
+) (*mcp.CallToolResult, error) {
+	if timeout == nil {
+		return srv.CallTool(ctx, tool, args)
+	}
+
+	ctx, stop := watchCall(ctx, clk, srv.Name(), tool, timeout)
+	defer stop()
+
+	result, err := srv.CallTool(ctx, tool, args)
+	if err != nil {
+		var timeoutErr *CallTimeoutError
+		if cause := context.Cause(ctx); errors.As(cause, &timeoutErr) {
+			return nil, timeoutErr
+		}
+	}
+
+	return result, err
+}
+
+// watchCall returns a context ended with a CallTimeoutError cause once the
+// call made under it has gone timeout.Idle without a progress notification,
+// or has run for timeout.Max. stop releases it when the call is over.
+func watchCall(
+	parent context.Context,
+	clk clock.Clock,
+	server, tool string,
+	timeout *config.CallTimeoutConfig,
+) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	progress := make(chan struct{}, 1)
+	ctx = withProgress(ctx, func() {
+		select {
+		case progress <- struct{}{}:
+		default:
+		}
+	})
+
+	start := clk.Now()
+
+	go func() {
+		for {
+			wait, idle := timeout.Idle, true
+			if timeout.Max > 0 {
+				if left := timeout.Max - clk.Since(start); wait <= 0 || left <= wait {
+					wait, idle = left, false
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-progress:
+			case <-clk.After(wait):
+				after := timeout.Max
+				if idle {
+					after = timeout.Idle
+				}
+
+				cancel(&CallTimeoutError{ServerName: server, Tool: tool, After: after, Idle: idle})
+
+				return
+			}
+		}
+	}()
+
+	return ctx, func() { cancel(nil) }
+}
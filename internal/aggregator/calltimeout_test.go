@@ -0,0 +1,105 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestCallWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		timeout  *config.CallTimeoutConfig
+		args     map[string]any
+		wantIdle bool // Else, when wantErr, the maximum duration
+		wantErr  bool
+	}{
+		{
+			name:    "no timeout",
+			args:    map[string]any{"steps": 2, "interval_ms": 50},
+			timeout: nil,
+		},
+		{
+			name:    "progress keeps the call alive",
+			timeout: &config.CallTimeoutConfig{Idle: 400 * time.Millisecond, Max: 10 * time.Second},
+			args:    map[string]any{"steps": 6, "interval_ms": 150, "progress": true},
+		},
+		{
+			name:     "silent call fails fast",
+			timeout:  &config.CallTimeoutConfig{Idle: 300 * time.Millisecond, Max: 10 * time.Second},
+			args:     map[string]any{"steps": 1, "interval_ms": 5000},
+			wantErr:  true,
+			wantIdle: true,
+		},
+		{
+			name:     "steps without progress",
+			timeout:  &config.CallTimeoutConfig{Idle: 300 * time.Millisecond},
+			args:     map[string]any{"steps": 6, "interval_ms": 150},
+			wantErr:  true,
+			wantIdle: true,
+		},
+		{
+			name:    "maximum duration caps progress",
+			timeout: &config.CallTimeoutConfig{Idle: 400 * time.Millisecond, Max: 700 * time.Millisecond},
+			args:    map[string]any{"steps": 50, "interval_ms": 100, "progress": true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := newHelperServer(t, "")
+			t.Cleanup(func() { _ = srv.Stop() })
+
+			result, err := callWithTimeout(t.Context(), clock.Real, srv, "slow", tt.args, tt.timeout)
+
+			if !tt.wantErr {
+				if err != nil || result.IsError {
+					t.Fatalf("callWithTimeout() = %+v, %v; want success", result, err)
+				}
+
+				return
+			}
+
+			var timeoutErr *CallTimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("callWithTimeout() error = %v, want a CallTimeoutError", err)
+			}
+
+			if timeoutErr.Idle != tt.wantIdle || timeoutErr.ServerName != "helper" || timeoutErr.Tool != "slow" {
+				t.Errorf("callWithTimeout() error = %+v, want idle %v", timeoutErr, tt.wantIdle)
+			}
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Error("CallTimeoutError does not match context.DeadlineExceeded")
+			}
+
+			// The backend is still usable after the timed out call
+			if result, err := srv.CallTool(t.Context(), "echo", nil); err != nil || result.IsError {
+				t.Errorf("echo after timeout = %+v, %v", result, err)
+			}
+		})
+	}
+}
+
+func TestWatchCallStop(t *testing.T) {
+	t.Parallel()
+
+	ctx, stop := watchCall(t.Context(), clock.Real, "s", "t", &config.CallTimeoutConfig{Idle: time.Hour})
+	stop()
+
+	<-ctx.Done()
+
+	var timeoutErr *CallTimeoutError
+	if errors.As(context.Cause(ctx), &timeoutErr) {
+		t.Errorf("stopped call ended with %v", context.Cause(ctx))
+	}
+}
@@ -0,0 +1,151 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// cancelNotifyTimeout bounds sending notifications/cancelled to a backend.
+const cancelNotifyTimeout = 5 * time.Second
+
+// cancelTransport wraps the transport of a backend client so that a request
+// whose context ends before the backend answers is cancelled there too with
+// notifications/cancelled: mcp-go's client only stops waiting, leaving the
+// backend working on a call whose result nobody reads. A client's own
+// notifications/cancelled ends the context of its tools/call, so it reaches
+// the backend under the backend's request ID.
+type cancelTransport struct {
+	transport.Interface
+
+	logger *slog.Logger
+
+	mu sync.Mutex
+	// inflight maps the correlation IDs of calls waiting on the backend to
+	// the IDs of their requests to it
+	inflight map[string]mcp.RequestId
+}
+
+var _ transport.BidirectionalInterface = (*cancelTransport)(nil)
+
+func newCancelTransport(base transport.Interface, logger *slog.Logger) *cancelTransport {
+	return &cancelTransport{Interface: base, logger: logger, inflight: make(map[string]mcp.RequestId)}
+}
+
+// SendRequest sends request, cancelling it on the backend when ctx ends
+// first. initialize is never cancelled, as the MCP spec requires.
+func (t *cancelTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	id := CorrelationID(ctx)
+	if id != "" {
+		t.mu.Lock()
+		t.inflight[id] = request.ID
+		t.mu.Unlock()
+
+		defer func() {
+			t.mu.Lock()
+			delete(t.inflight, id)
+			t.mu.Unlock()
+		}()
+	}
+
+	resp, err := t.Interface.SendRequest(ctx, request)
+	if err != nil && ctx.Err() != nil && request.Method != string(mcp.MethodInitialize) {
+		t.cancel(ctx, request)
+	}
+
+	return resp, err
+}
+
+// cancel sends notifications/cancelled for request, whose context ended.
+func (t *cancelTransport) cancel(ctx context.Context, request transport.JSONRPCRequest) {
+	reason := "client cancelled the request"
+	if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		reason = "request timed out"
+	}
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: string(mcp.MethodNotificationCancelled),
+			Params: mcp.NotificationParams{AdditionalFields: map[string]any{
+				"requestId": request.ID,
+				"reason":    reason,
+			}},
+		},
+	}
+
+	// The request's context is over; keep its values for headers
+	sendCtx, stop := context.WithTimeout(context.WithoutCancel(ctx), cancelNotifyTimeout)
+	defer stop()
+
+	if err := t.SendNotification(sendCtx, notification); err != nil {
+		t.logger.Debug("cancellation not forwarded", "method", request.Method, "request_id", request.ID.Value(),
+			"correlation_id", CorrelationID(ctx), "error", err)
+
+		return
+	}
+
+	t.logger.Debug("cancellation forwarded", "method", request.Method, "request_id", request.ID.Value(),
+		"correlation_id", CorrelationID(ctx), "reason", reason)
+}
+
+// requestID returns the backend request ID of the call with correlation ID
+// id while it waits on the backend.
+func (t *cancelTransport) requestID(id string) (mcp.RequestId, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reqID, ok := t.inflight[id]
+
+	return reqID, ok
+}
+
+// The client finds these optional transport features by type assertion, so
+// they are passed through to the wrapped transport when it has them.
+
+func (t *cancelTransport) SetRequestHandler(handler transport.RequestHandler) {
+	if bidi, ok := t.Interface.(transport.BidirectionalInterface); ok {
+		bidi.SetRequestHandler(handler)
+	}
+}
+
+func (t *cancelTransport) SetProtocolVersion(version string) {
+	if conn, ok := t.Interface.(transport.HTTPConnection); ok {
+		conn.SetProtocolVersion(version)
+	}
+}
+
+func (t *cancelTransport) SetConnectionLostHandler(handler func(error)) {
+	type connectionLostSetter interface {
+		SetConnectionLostHandler(func(error))
+	}
+
+	if setter, ok := t.Interface.(connectionLostSetter); ok {
+		setter.SetConnectionLostHandler(handler)
+	}
+}
+
+// BackendRequestID returns the ID of the request to the backend made for
+// the tool call with the given correlation ID (see CorrelationID) while it
+// is in flight, for matching a client's call with the backend's logs.
+func (s *ManagedServer) BackendRequestID(correlationID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.requests == nil {
+		return "", false
+	}
+
+	id, ok := s.requests.requestID(correlationID)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprint(id.Value()), true
+}
@@ -0,0 +1,181 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// stubTransport answers requests at once, or blocks them until their
+// context ends, and records the notifications sent.
+type stubTransport struct {
+	transport.Interface
+
+	block         bool
+	waiting       chan struct{}
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *stubTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if !s.block {
+		return &transport.JSONRPCResponse{ID: request.ID, Result: json.RawMessage(`{}`)}, nil
+	}
+
+	s.waiting <- struct{}{}
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (s *stubTransport) SendNotification(_ context.Context, n mcp.JSONRPCNotification) error {
+	s.notifications <- n
+
+	return nil
+}
+
+func TestCancelTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		method  mcp.MCPMethod
+		block   bool
+		timeout bool
+		reason  string // "" expects no notifications/cancelled
+	}{
+		{name: "answered", method: mcp.MethodToolsCall},
+		{name: "cancelled", method: mcp.MethodToolsCall, block: true, reason: "client cancelled the request"},
+		{name: "timed out", method: mcp.MethodToolsCall, block: true, timeout: true, reason: "request timed out"},
+		{name: "initialize is never cancelled", method: mcp.MethodInitialize, block: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stub := &stubTransport{
+				block:         tt.block,
+				waiting:       make(chan struct{}),
+				notifications: make(chan mcp.JSONRPCNotification, 1),
+			}
+			ct := newCancelTransport(stub, slog.New(slog.DiscardHandler))
+
+			ctx, cancel := context.WithCancel(withCorrelationID(t.Context(), "corr-1"))
+			if tt.timeout {
+				ctx, cancel = context.WithTimeout(ctx, time.Millisecond)
+			}
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := ct.SendRequest(ctx, transport.JSONRPCRequest{ID: mcp.NewRequestId(int64(42)), Method: string(tt.method)})
+				done <- err
+			}()
+
+			if tt.block {
+				<-stub.waiting
+
+				if id, ok := ct.requestID("corr-1"); !ok || id.Value() != int64(42) {
+					t.Errorf("requestID while in flight = %v, %v; want 42", id.Value(), ok)
+				}
+
+				if !tt.timeout {
+					cancel()
+				}
+			}
+
+			if err := <-done; (err != nil) != tt.block {
+				t.Fatalf("SendRequest error = %v", err)
+			}
+
+			if _, ok := ct.requestID("corr-1"); ok {
+				t.Error("request still mapped after it ended")
+			}
+
+			select {
+			case n := <-stub.notifications:
+				fields := n.Params.AdditionalFields
+				if tt.reason == "" || n.Method != string(mcp.MethodNotificationCancelled) ||
+					fields["requestId"] != mcp.NewRequestId(int64(42)) || fields["reason"] != tt.reason {
+					t.Errorf("notification = %+v, want reason %q", n, tt.reason)
+				}
+			default:
+				if tt.reason != "" {
+					t.Error("no notifications/cancelled sent")
+				}
+			}
+		})
+	}
+}
+
+// blockingServer is a mock server whose tool calls wait for their context
+// to end, reporting that they started and stopped.
+type blockingServer struct {
+	*testutil.MockServer
+
+	started, stopped chan struct{}
+}
+
+func (b *blockingServer) CallTool(ctx context.Context, _ string, _ map[string]any) (*mcp.CallToolResult, error) {
+	close(b.started)
+	<-ctx.Done()
+	close(b.stopped)
+
+	return nil, ctx.Err()
+}
+
+func TestClientCancellationStopsBackendCall(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv := &blockingServer{
+		MockServer: testutil.NewMockServer("slow", []mcp.Tool{mcp.NewTool("crunch")}),
+		started:    make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	if err := agg.AddServer(t.Context(), srv); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	mcpServer := agg.CreateMCPServer()
+
+	answered := make(chan struct{})
+
+	go func() {
+		defer close(answered)
+
+		mcpServer.HandleMessage(t.Context(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"slow_crunch"}}`))
+	}()
+
+	<-srv.started
+
+	mcpServer.HandleMessage(t.Context(), json.RawMessage(
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user pressed stop"}}`))
+
+	select {
+	case <-srv.stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("backend call still running after the client cancelled it")
+	}
+
+	<-answered
+
+	// A cancelled call is not a failure of the server
+	if stats := agg.health.Stats("slow"); stats.ConsecutiveFailures != 0 {
+		t.Errorf("consecutive failures = %d after a cancelled call, want 0", stats.ConsecutiveFailures)
+	}
+}
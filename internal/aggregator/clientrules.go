@@ -0,0 +1,77 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// clientRules returns the settings.clients rules of the client calling in
+// ctx, by the name it sent in initialize, and that name. The rules are nil
+// when none match, or the call has no initialized session.
+func (a *Aggregator) clientRules(ctx context.Context) (*config.ClientRules, string) {
+	a.cfgMu.RLock()
+	var clients map[string]*config.ClientRules
+	if a.cfg != nil && a.cfg.Settings != nil {
+		clients = a.cfg.Settings.Clients
+	}
+	a.cfgMu.RUnlock()
+
+	if len(clients) == 0 {
+		return nil, ""
+	}
+
+	profile, ok := a.callerProfile(ctx)
+	if !ok {
+		return nil, ""
+	}
+
+	return config.LookupClient(clients, profile.name), profile.name
+}
+
+// clientAllows reports whether the client calling in ctx may use entry.
+func (a *Aggregator) clientAllows(ctx context.Context, entry *ToolEntry) bool {
+	rules, _ := a.clientRules(ctx)
+
+	return rules.Allows(entry.ServerName, entry.Tool.Name, entry.PrefixedName)
+}
+
+// clientDenied publishes policy_blocked for a call settings.clients does not
+// allow the calling client and returns the error to report.
+func (a *Aggregator) clientDenied(ctx context.Context, entry *ToolEntry) error {
+	_, client := a.clientRules(ctx)
+
+	a.logger.Warn("tool call denied by client rules", "client", client, "tool", entry.PrefixedName)
+	a.publish(events.PolicyBlocked, entry.ServerName, "client rules deny "+client+" calling "+entry.PrefixedName, map[string]any{
+		"tool":   entry.PrefixedName,
+		"client": client,
+	})
+
+	return fmt.Errorf("%w: %s", ErrAccessDenied, entry.PrefixedName)
+}
+
+// filterClientTools is a tools/list filter leaving out the tools
+// settings.clients does not allow the requesting client.
+func (a *Aggregator) filterClientTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	rules, _ := a.clientRules(ctx)
+	if rules == nil {
+		return tools
+	}
+
+	filtered := make([]mcp.Tool, 0, len(tools))
+
+	for _, tool := range tools {
+		entry, ok := a.tools.Get(tool.Name)
+		if ok && !rules.Allows(entry.ServerName, entry.Tool.Name, entry.PrefixedName) {
+			continue
+		}
+
+		filtered = append(filtered, tool)
+	}
+
+	return filtered
+}
@@ -0,0 +1,93 @@
+package aggregator
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestClientRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.Clients = map[string]*config.ClientRules{
+		"Claude Desktop": {Denied: []string{"filesystem_*"}},
+		"agent-*":        {Allowed: []string{"github:*"}},
+	}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, tool := range []struct{ server, name string }{
+		{"filesystem", "read_file"},
+		{"github", "search_code"},
+		{"jira", "search"},
+	} {
+		if _, _, err := agg.tools.Register(tool.server, mcp.NewTool(tool.name), nil); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+
+	mcpServer := agg.CreateMCPServer()
+
+	tools := []mcp.Tool{
+		mcp.NewTool("filesystem_read_file"), mcp.NewTool("github_search_code"),
+		mcp.NewTool("jira_search"), mcp.NewTool("assern_status"),
+	}
+
+	tests := []struct {
+		client string // "" = no initialized session
+		want   []string
+	}{
+		{client: "", want: []string{"filesystem_read_file", "github_search_code", "jira_search", "assern_status"}},
+		{client: "Claude Desktop", want: []string{"github_search_code", "jira_search", "assern_status"}},
+		{client: "agent-ci", want: []string{"github_search_code", "assern_status"}},
+		{client: "editor", want: []string{"filesystem_read_file", "github_search_code", "jira_search", "assern_status"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(cmp.Or(tt.client, "no session"), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			if tt.client != "" {
+				ctx = mcpServer.WithContext(ctx, server.NewInProcessSession("session-"+tt.client, nil))
+
+				req := &mcp.InitializeRequest{}
+				req.Params.ClientInfo = mcp.Implementation{Name: tt.client}
+				agg.recordClient(ctx, nil, req, &mcp.InitializeResult{})
+			}
+
+			var got []string
+			for _, tool := range agg.filterClientTools(ctx, tools) {
+				got = append(got, tool.Name)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterClientTools() = %v, want %v", got, tt.want)
+			}
+
+			entry, _ := agg.tools.Get("filesystem_read_file")
+
+			result, err := agg.handleToolCall(ctx, entry, mcp.CallToolRequest{})
+			if err != nil {
+				t.Fatalf("handleToolCall() error = %v", err)
+			}
+
+			// Allowed calls fail on the missing backend instead
+			data, _ := result.StructuredContent.(map[string]any)
+			if denied, want := data["error"] == "access_denied", !slices.Contains(tt.want, "filesystem_read_file"); denied != want {
+				t.Errorf("call of filesystem_read_file denied = %v, want %v (result %+v)", denied, want, result)
+			}
+		})
+	}
+}
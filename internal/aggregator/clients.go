@@ -0,0 +1,244 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Protocol versions that introduced content types; a client that agreed on
+// an older version is not sent them. Versions are dates, so they compare
+// as strings.
+const (
+	protocolAudio         = "2025-03-26" // audio content
+	protocolResourceLinks = "2025-06-18" // resource_link content, structuredContent
+)
+
+// clientProfile is what a client declared when it initialized its session.
+type clientProfile struct {
+	name         string
+	version      string
+	protocol     string // Protocol version agreed on at initialize
+	capabilities mcp.ClientCapabilities
+}
+
+// supports reports whether the client's protocol version is since, or
+// unknown (then the client is assumed to be current).
+func (p clientProfile) supports(since string) bool {
+	return p.protocol == "" || p.protocol >= since
+}
+
+// capabilityNames lists the capabilities the client declared, sorted, with
+// experimental ones as "experimental.<name>".
+func (p clientProfile) capabilityNames() []string {
+	caps := p.capabilities
+
+	var names []string
+
+	if caps.Roots != nil {
+		names = append(names, "roots")
+	}
+
+	if caps.Sampling != nil {
+		names = append(names, "sampling")
+	}
+
+	if caps.Elicitation != nil {
+		names = append(names, "elicitation")
+	}
+
+	if caps.Tasks != nil {
+		names = append(names, "tasks")
+	}
+
+	for name := range caps.Experimental {
+		names = append(names, "experimental."+name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// clientProfiles holds the profile of each initialized session.
+type clientProfiles struct {
+	mu       sync.RWMutex
+	sessions map[string]clientProfile
+}
+
+func newClientProfiles() *clientProfiles {
+	return &clientProfiles{sessions: make(map[string]clientProfile)}
+}
+
+func (c *clientProfiles) set(sessionID string, profile clientProfile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sessions[sessionID] = profile
+}
+
+func (c *clientProfiles) get(sessionID string) (clientProfile, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	profile, ok := c.sessions[sessionID]
+
+	return profile, ok
+}
+
+func (c *clientProfiles) forget(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sessions, sessionID)
+}
+
+// ClientStatus describes one connected client session.
+type ClientStatus struct {
+	Session      string   `json:"session"`
+	Name         string   `json:"name,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Protocol     string   `json:"protocol,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// all returns the connected clients, by session ID.
+func (c *clientProfiles) all() []ClientStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clients := make([]ClientStatus, 0, len(c.sessions))
+
+	for _, id := range slices.Sorted(maps.Keys(c.sessions)) {
+		p := c.sessions[id]
+		clients = append(clients, ClientStatus{
+			Session:      id,
+			Name:         p.name,
+			Version:      p.version,
+			Protocol:     p.protocol,
+			Capabilities: p.capabilityNames(),
+		})
+	}
+
+	return clients
+}
+
+// addClientHooks adds server hooks that record what each client declares
+// at initialize and forget it when the client disconnects.
+func (a *Aggregator) addClientHooks(hooks *server.Hooks) {
+	hooks.AddAfterInitialize(a.recordClient)
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		a.clients.forget(session.SessionID())
+	})
+}
+
+// recordClient is an after-initialize hook that keeps the client's declared
+// info, capabilities and the agreed protocol version for its session.
+func (a *Aggregator) recordClient(ctx context.Context, _ any, req *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+
+	profile := clientProfile{
+		name:         req.Params.ClientInfo.Name,
+		version:      req.Params.ClientInfo.Version,
+		protocol:     result.ProtocolVersion,
+		capabilities: req.Params.Capabilities,
+	}
+
+	a.clients.set(session.SessionID(), profile)
+
+	a.logger.Debug("client initialized",
+		"session", session.SessionID(),
+		"client", profile.name,
+		"version", profile.version,
+		"protocol", profile.protocol,
+		"capabilities", profile.capabilityNames(),
+	)
+}
+
+// callerProfile returns the profile of the client calling in ctx; false
+// for calls without an initialized session, such as 'assern call'.
+func (a *Aggregator) callerProfile(ctx context.Context) (clientProfile, bool) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return clientProfile{}, false
+	}
+
+	return a.clients.get(session.SessionID())
+}
+
+// canLog reports whether MCP logging notifications can be sent to the
+// client calling in ctx: its transport must carry them.
+func canLog(ctx context.Context) bool {
+	_, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+
+	return ok
+}
+
+// adaptResult rewrites the content of result that the calling client's
+// protocol version does not know: audio before 2025-03-26, resource links
+// and structured content before 2025-06-18. Each becomes text, so the
+// client still learns what it missed. The result may be shared with
+// coalesced callers, so a changed copy is returned.
+func (a *Aggregator) adaptResult(ctx context.Context, result *mcp.CallToolResult) *mcp.CallToolResult {
+	profile, ok := a.callerProfile(ctx)
+	if !ok || result == nil || profile.supports(protocolResourceLinks) {
+		return result
+	}
+
+	out := *result
+	out.Content = make([]mcp.Content, 0, len(result.Content))
+
+	for _, c := range result.Content {
+		out.Content = append(out.Content, adaptContent(c, profile))
+	}
+
+	// Text content is meant to repeat structured content; when a tool sent
+	// none, the structured content becomes the text
+	if out.StructuredContent != nil {
+		if len(out.Content) == 0 {
+			data, err := json.Marshal(out.StructuredContent)
+			if err == nil {
+				out.Content = append(out.Content, mcp.NewTextContent(string(data)))
+			}
+		}
+
+		out.StructuredContent = nil
+	}
+
+	return &out
+}
+
+// adaptContent returns c as text when profile's protocol version does not
+// have its type, or else unchanged.
+func adaptContent(c mcp.Content, profile clientProfile) mcp.Content {
+	switch v := c.(type) {
+	case mcp.AudioContent:
+		if profile.supports(protocolAudio) {
+			return c
+		}
+
+		return mcp.NewTextContent(fmt.Sprintf("[audio (%s) omitted: not supported by this client]", v.MIMEType))
+	case mcp.ResourceLink:
+		text := fmt.Sprintf("Resource %q: %s", v.Name, v.URI)
+		if v.MIMEType != "" {
+			text += " (" + v.MIMEType + ")"
+		}
+
+		if v.Description != "" {
+			text += " - " + v.Description
+		}
+
+		return mcp.NewTextContent(text)
+	default:
+		return c
+	}
+}
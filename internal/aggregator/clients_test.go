@@ -0,0 +1,199 @@
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// contentTypes returns the type of each content item of result.
+func contentTypes(result *mcp.CallToolResult) []string {
+	types := make([]string, 0, len(result.Content))
+
+	for _, c := range result.Content {
+		switch v := c.(type) {
+		case mcp.TextContent:
+			types = append(types, v.Type)
+		case mcp.AudioContent:
+			types = append(types, v.Type)
+		case mcp.ResourceLink:
+			types = append(types, v.Type)
+		default:
+			types = append(types, "other")
+		}
+	}
+
+	return types
+}
+
+func TestAdaptResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		protocol       string // "" = no initialized session
+		wantTypes      []string
+		wantStructured bool
+	}{
+		{
+			name:           "no session",
+			wantTypes:      []string{"text", "audio", "resource_link"},
+			wantStructured: true,
+		},
+		{
+			name:           "current client",
+			protocol:       mcp.LATEST_PROTOCOL_VERSION,
+			wantTypes:      []string{"text", "audio", "resource_link"},
+			wantStructured: true,
+		},
+		{
+			name:           "before resource links",
+			protocol:       "2025-03-26",
+			wantTypes:      []string{"text", "audio", "text"},
+			wantStructured: false,
+		},
+		{
+			name:           "before audio",
+			protocol:       "2024-11-05",
+			wantTypes:      []string{"text", "text", "text"},
+			wantStructured: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			mcpServer := agg.CreateMCPServer()
+
+			backend := &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.NewTextContent(`{"ok":true}`),
+					mcp.NewAudioContent("UklGRg==", "audio/wav"),
+					mcp.NewResourceLink("file:///report.pdf", "report", "Monthly report", "application/pdf"),
+				},
+				StructuredContent: map[string]any{"ok": true},
+			}
+
+			mock := testutil.NewMockServer("media", []mcp.Tool{mcp.NewTool("render")})
+			mock.ToolResults = map[string]*mcp.CallToolResult{"render": backend}
+
+			if err := agg.AddServer(t.Context(), mock); err != nil {
+				t.Fatalf("AddServer: %v", err)
+			}
+
+			ctx := t.Context()
+
+			if tt.protocol != "" {
+				session := server.NewInProcessSession("session-1", nil)
+				ctx = mcpServer.WithContext(ctx, session)
+
+				req := &mcp.InitializeRequest{}
+				req.Params.ClientInfo = mcp.Implementation{Name: "old-client", Version: "1.0"}
+				agg.recordClient(ctx, nil, req, &mcp.InitializeResult{ProtocolVersion: tt.protocol})
+			}
+
+			result, err := agg.CallTool(ctx, "media_render", nil)
+			if err != nil {
+				t.Fatalf("CallTool: %v", err)
+			}
+
+			if got := contentTypes(result); !slices.Equal(got, tt.wantTypes) {
+				t.Errorf("content types = %v, want %v", got, tt.wantTypes)
+			}
+
+			if got := result.StructuredContent != nil; got != tt.wantStructured {
+				t.Errorf("structured content kept = %v, want %v", got, tt.wantStructured)
+			}
+
+			// The backend's result may be shared with coalesced callers
+			if len(contentTypes(backend)) != 3 || backend.StructuredContent == nil {
+				t.Error("backend result modified")
+			}
+		})
+	}
+}
+
+func TestAdaptResultStructuredOnly(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mcpServer := agg.CreateMCPServer()
+	ctx := mcpServer.WithContext(t.Context(), server.NewInProcessSession("session-1", nil))
+
+	agg.recordClient(ctx, nil, &mcp.InitializeRequest{}, &mcp.InitializeResult{ProtocolVersion: "2025-03-26"})
+
+	result := agg.adaptResult(ctx, &mcp.CallToolResult{StructuredContent: map[string]any{"count": 2}})
+
+	if len(result.Content) != 1 || result.StructuredContent != nil {
+		t.Fatalf("adaptResult() = %+v, want the structured content as text", result)
+	}
+
+	if text, _ := result.Content[0].(mcp.TextContent); text.Text != `{"count":2}` {
+		t.Errorf("text = %q", text.Text)
+	}
+}
+
+func TestClientProfiles(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mcpServer := agg.CreateMCPServer()
+	session := server.NewInProcessSession("session-1", nil)
+	ctx := mcpServer.WithContext(t.Context(), session)
+
+	req := &mcp.InitializeRequest{}
+	req.Params.ClientInfo = mcp.Implementation{Name: "editor", Version: "2.1"}
+	req.Params.Capabilities = mcp.ClientCapabilities{
+		Sampling:     &mcp.SamplingCapability{},
+		Elicitation:  &mcp.ElicitationCapability{},
+		Experimental: map[string]any{"previews": true},
+	}
+
+	agg.recordClient(ctx, nil, req, &mcp.InitializeResult{ProtocolVersion: "2025-06-18"})
+
+	want := ClientStatus{
+		Session:      "session-1",
+		Name:         "editor",
+		Version:      "2.1",
+		Protocol:     "2025-06-18",
+		Capabilities: []string{"elicitation", "experimental.previews", "sampling"},
+	}
+
+	clients := agg.Status().Clients
+	if len(clients) != 1 || clients[0].Session != want.Session || clients[0].Name != want.Name ||
+		clients[0].Version != want.Version || clients[0].Protocol != want.Protocol ||
+		!slices.Equal(clients[0].Capabilities, want.Capabilities) {
+		t.Errorf("Status().Clients = %+v, want [%+v]", clients, want)
+	}
+
+	if _, ok := agg.callerProfile(context.Background()); ok {
+		t.Error("callerProfile() found a profile without a session")
+	}
+
+	agg.clients.forget(session.SessionID())
+
+	if clients := agg.Status().Clients; len(clients) != 0 {
+		t.Errorf("Status().Clients after disconnect = %+v, want none", clients)
+	}
+}
@@ -0,0 +1,124 @@
+package aggregator
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+)
+
+// dateResolution is the precision of the HTTP Date header: the server's
+// clock read somewhere within the second it names.
+const dateResolution = time.Second
+
+// clockSkew tracks how far an authorization server's clock is ahead of the
+// local one (negative: behind), measured from the Date header of its
+// responses, and how much of that is tolerated (oauth.clock_skew). A nil
+// *clockSkew measures nothing and tolerates no difference.
+type clockSkew struct {
+	tolerance time.Duration
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	offset   time.Duration
+	measured bool
+	warned   bool
+}
+
+func newClockSkew(tolerance time.Duration, logger *slog.Logger) *clockSkew {
+	return &clockSkew{tolerance: tolerance, logger: logger}
+}
+
+// observe records the offset shown by a response Date header to a request
+// sent at start and answered at end, warning once when it grows beyond the
+// tolerance.
+func (c *clockSkew) observe(start, end time.Time, date string) {
+	if c == nil || date == "" {
+		return
+	}
+
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	// Compare the middle of the server's second with the middle of the
+	// round trip
+	offset := serverTime.Add(dateResolution / 2).Sub(start.Add(end.Sub(start) / 2)).Round(time.Second)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.offset, c.measured = offset, true
+
+	switch beyond := c.beyond(); {
+	case beyond && !c.warned:
+		c.warned = true
+		c.logger.Warn("local clock differs from the authorization server's beyond oauth.clock_skew; tokens may be rejected as expired, check the system clock",
+			"server_clock_ahead_by", offset, "clock_skew", c.tolerance)
+	case !beyond:
+		c.warned = false
+	}
+}
+
+// beyond reports whether the measured offset exceeds the tolerance. c.mu
+// must be held.
+func (c *clockSkew) beyond() bool {
+	return c.measured && (c.offset > c.tolerance || -c.offset > c.tolerance)
+}
+
+// Skew returns the last measured offset and whether it exceeds the
+// tolerance; ok is false until a response has been measured.
+func (c *clockSkew) Skew() (offset time.Duration, beyond, ok bool) {
+	if c == nil {
+		return 0, false, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.offset, c.beyond(), c.measured
+}
+
+// lead returns how much earlier than its expiry a token should be treated
+// as expired: the tolerance, or how far the server's clock is measured
+// ahead when that is more.
+func (c *clockSkew) lead() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return max(c.tolerance, c.offset)
+}
+
+// skewTransport is an http.RoundTripper measuring clock skew from the
+// responses of an authorization server.
+type skewTransport struct {
+	base  http.RoundTripper
+	skew  *clockSkew
+	clock clock.Clock
+}
+
+func (t *skewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.clock.Now()
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.skew.observe(start, t.clock.Now(), resp.Header.Get("Date"))
+	}
+
+	return resp, err
+}
+
+// ClockSkew returns how far the clock of the server's authorization server
+// was last measured ahead of the local one, and whether that exceeds its
+// oauth.clock_skew; ok is false for servers not using OAuth or before a
+// response was measured.
+func (s *ManagedServer) ClockSkew() (offset time.Duration, beyond, ok bool) {
+	return s.skew.Skew()
+}
@@ -0,0 +1,99 @@
+package aggregator
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+
+	"github.com/valksor/go-assern/internal/clock"
+)
+
+func TestClockSkewObserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		serverTime time.Time
+		wantOffset time.Duration
+		wantBeyond bool
+		wantLead   time.Duration
+	}{
+		{name: "in sync", serverTime: now, wantLead: 30 * time.Second},
+		{name: "within tolerance", serverTime: now.Add(20 * time.Second), wantOffset: 20 * time.Second, wantLead: 30 * time.Second},
+		{name: "server ahead", serverTime: now.Add(2 * time.Minute), wantOffset: 2 * time.Minute, wantBeyond: true, wantLead: 2 * time.Minute},
+		{name: "server behind", serverTime: now.Add(-2 * time.Minute), wantOffset: -2 * time.Minute, wantBeyond: true, wantLead: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			skew := newClockSkew(30*time.Second, slog.New(slog.DiscardHandler))
+
+			if _, _, ok := skew.Skew(); ok {
+				t.Fatal("Skew() measured before any response")
+			}
+
+			// A 200ms round trip in the middle of the second the server names
+			skew.observe(now.Add(400*time.Millisecond), now.Add(600*time.Millisecond), tt.serverTime.Format(http.TimeFormat))
+
+			offset, beyond, ok := skew.Skew()
+			if !ok || offset != tt.wantOffset || beyond != tt.wantBeyond {
+				t.Errorf("Skew() = %v, %v, %v, want %v, %v, true", offset, beyond, ok, tt.wantOffset, tt.wantBeyond)
+			}
+
+			if got := skew.lead(); got != tt.wantLead {
+				t.Errorf("lead() = %v, want %v", got, tt.wantLead)
+			}
+		})
+	}
+}
+
+func TestSkewTransport(t *testing.T) {
+	t.Parallel()
+
+	// An authorization server whose clock runs five minutes ahead
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Date", time.Now().Add(5*time.Minute).UTC().Format(http.TimeFormat))
+	}))
+	defer auth.Close()
+
+	skew := newClockSkew(30*time.Second, slog.New(slog.DiscardHandler))
+	client := &http.Client{Transport: &skewTransport{base: http.DefaultTransport, skew: skew, clock: clock.Real}}
+
+	resp, err := client.Get(auth.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	offset, beyond, ok := skew.Skew()
+	if !ok || !beyond || offset < 4*time.Minute || offset > 6*time.Minute {
+		t.Fatalf("Skew() = %v, %v, %v, want about 5m beyond tolerance", offset, beyond, ok)
+	}
+
+	// A token the local clock considers valid for three more minutes has
+	// already expired on the server's
+	store := newFileTokenStore(t.TempDir(), "github")
+	store.skew = skew
+
+	token := &transport.Token{AccessToken: "x", RefreshToken: "r", ExpiresAt: time.Now().Add(3 * time.Minute)}
+	if err := store.SaveToken(t.Context(), token); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+
+	got, err := store.GetToken(t.Context())
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+
+	if !got.IsExpired() {
+		t.Error("token is not refreshed despite the server's clock lead")
+	}
+}
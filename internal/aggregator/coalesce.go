@@ -0,0 +1,117 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolCallFunc performs one backend tool call.
+type toolCallFunc func(ctx context.Context) (*mcp.CallToolResult, error)
+
+// inflightCall is a backend tool call shared by every caller that issued the
+// same request while it was running.
+type inflightCall struct {
+	done    chan struct{}
+	result  *mcp.CallToolResult
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// callCoalescer deduplicates identical tool calls that overlap in time.
+// The shared call runs detached from any single caller's context and is only
+// cancelled once every waiting caller has given up, so an impatient client
+// that cancels and retries attaches to the call it already started.
+type callCoalescer struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+
+	// changed is broadcast whenever a call gains or loses a waiter.
+	changed *sync.Cond
+}
+
+func newCallCoalescer() *callCoalescer {
+	c := &callCoalescer{calls: make(map[string]*inflightCall)}
+	c.changed = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// coalesceKey identifies a tool call by prefixed tool name and arguments.
+// json.Marshal sorts map keys, so equal argument maps produce equal keys.
+// It returns false if the arguments cannot be encoded.
+func coalesceKey(toolName string, args map[string]any) (string, bool) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+
+	return toolName + "\x00" + string(data), true
+}
+
+// do runs fn for key, or waits on the identical call already in flight.
+// shared reports whether the result came from another caller's call.
+func (c *callCoalescer) do(ctx context.Context, key string, fn toolCallFunc) (*mcp.CallToolResult, bool, error) {
+	c.mu.Lock()
+
+	call, shared := c.calls[key]
+	if shared {
+		call.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &inflightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		c.calls[key] = call
+
+		go c.run(callCtx, key, call, fn)
+	}
+
+	c.changed.Broadcast()
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result, shared, call.err
+	case <-ctx.Done():
+		c.leave(key, call)
+
+		return nil, shared, ctx.Err()
+	}
+}
+
+// run executes the shared call and publishes its outcome.
+func (c *callCoalescer) run(ctx context.Context, key string, call *inflightCall, fn toolCallFunc) {
+	defer call.cancel()
+
+	call.result, call.err = fn(ctx)
+
+	c.mu.Lock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// leave removes a waiter that stopped waiting. When the last waiter leaves,
+// the backend call is cancelled and forgotten so later calls start fresh.
+func (c *callCoalescer) leave(key string, call *inflightCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.waiters--
+	c.changed.Broadcast()
+
+	if call.waiters > 0 {
+		return
+	}
+
+	call.cancel()
+
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
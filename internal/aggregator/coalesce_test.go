@@ -0,0 +1,197 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCoalesceKey(t *testing.T) {
+	t.Parallel()
+
+	a, ok := coalesceKey("github_search", map[string]any{"q": "go", "page": 1})
+	if !ok {
+		t.Fatal("coalesceKey() failed to encode args")
+	}
+
+	b, _ := coalesceKey("github_search", map[string]any{"page": 1, "q": "go"})
+	if a != b {
+		t.Errorf("equal args produced different keys: %q vs %q", a, b)
+	}
+
+	c, _ := coalesceKey("github_search", map[string]any{"q": "rust", "page": 1})
+	if a == c {
+		t.Error("different args produced the same key")
+	}
+
+	d, _ := coalesceKey("gitlab_search", map[string]any{"q": "go", "page": 1})
+	if a == d {
+		t.Error("different tools produced the same key")
+	}
+
+	if _, ok := coalesceKey("x", map[string]any{"bad": make(chan int)}); ok {
+		t.Error("coalesceKey() should fail for unencodable args")
+	}
+}
+
+func TestCallCoalescer_SharesInflightCall(t *testing.T) {
+	t.Parallel()
+
+	c := newCallCoalescer()
+	release := make(chan struct{})
+
+	var calls atomic.Int32
+
+	fn := func(context.Context) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		<-release
+
+		return mcp.NewToolResultText("done"), nil
+	}
+
+	const callers = 5
+
+	var (
+		wg     sync.WaitGroup
+		shared atomic.Int32
+	)
+
+	for range callers {
+		wg.Go(func() {
+			result, wasShared, err := c.do(t.Context(), "key", fn)
+			if err != nil || result == nil {
+				t.Errorf("do() = %v, %v", result, err)
+			}
+
+			if wasShared {
+				shared.Add(1)
+			}
+		})
+	}
+
+	waitForWaiters(t, c, "key", callers)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("backend called %d times, want 1", got)
+	}
+
+	if got := shared.Load(); got != callers-1 {
+		t.Errorf("shared results = %d, want %d", got, callers-1)
+	}
+}
+
+func TestCallCoalescer_SequentialCallsNotShared(t *testing.T) {
+	t.Parallel()
+
+	c := newCallCoalescer()
+
+	var calls atomic.Int32
+
+	fn := func(context.Context) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+
+		return mcp.NewToolResultText("done"), nil
+	}
+
+	for range 3 {
+		if _, shared, err := c.do(t.Context(), "key", fn); err != nil || shared {
+			t.Fatalf("do() shared = %v, err = %v", shared, err)
+		}
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("backend called %d times, want 3", got)
+	}
+}
+
+func TestCallCoalescer_LeaderCancelKeepsCallAlive(t *testing.T) {
+	t.Parallel()
+
+	c := newCallCoalescer()
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (*mcp.CallToolResult, error) {
+		select {
+		case <-release:
+			return mcp.NewToolResultText("done"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(t.Context())
+	leaderErr := make(chan error, 1)
+
+	go func() {
+		_, _, err := c.do(leaderCtx, "key", fn)
+		leaderErr <- err
+	}()
+
+	waitForWaiters(t, c, "key", 1)
+
+	followerDone := make(chan error, 1)
+
+	go func() {
+		_, _, err := c.do(t.Context(), "key", fn)
+		followerDone <- err
+	}()
+
+	waitForWaiters(t, c, "key", 2)
+	cancelLeader()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+
+	if err := <-followerDone; err != nil {
+		t.Errorf("follower error = %v, want nil", err)
+	}
+}
+
+func TestCallCoalescer_AllWaitersLeaveCancelsCall(t *testing.T) {
+	t.Parallel()
+
+	c := newCallCoalescer()
+	backendCancelled := make(chan struct{})
+
+	fn := func(ctx context.Context) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		close(backendCancelled)
+
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	go func() { _, _, _ = c.do(ctx, "key", fn) }()
+
+	waitForWaiters(t, c, "key", 1)
+	cancel()
+
+	select {
+	case <-backendCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("backend call was not cancelled after the last waiter left")
+	}
+}
+
+// waitForWaiters blocks until the in-flight call for key has n waiters.
+func waitForWaiters(t *testing.T, c *callCoalescer, key string, n int) {
+	t.Helper()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for call := c.calls[key]; call == nil || call.waiters < n; call = c.calls[key] {
+		c.changed.Wait()
+	}
+}
@@ -2,6 +2,7 @@ package aggregator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/valksor/go-assern/internal/codemode"
 	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
 )
 
 // ToolExecuteName is the meta-tool that runs a sandboxed Starlark script which
@@ -31,7 +33,7 @@ func (a *Aggregator) codeModeConfig() *config.CodeModeConfig {
 
 // CodeModeEnabled reports whether the assern_execute meta-tool is active.
 func (a *Aggregator) CodeModeEnabled() bool {
-	return a.codeModeConfig().IsEnabled()
+	return a.codeModeConfig().IsEnabled() && a.FeatureEnabled(config.FeatureMetaTools)
 }
 
 // registerExecuteTool adds the assern_execute meta-tool to the MCP server.
@@ -62,7 +64,7 @@ func (a *Aggregator) handleExecute(ctx context.Context, req mcp.CallToolRequest)
 		Search:    a.searchMatches,
 		Timeout:   cfg.Timeout,
 		MaxCalls:  cfg.MaxToolCalls,
-		MaxOutput: cfg.MaxOutputBytes,
+		MaxOutput: int(cfg.MaxOutputBytes),
 	})
 
 	result, runErr := executor.Run(ctx, code)
@@ -93,9 +95,27 @@ func (a *Aggregator) callToolText(ctx context.Context, name string, args map[str
 	}
 
 	if !a.codeModeToolAllowed(entry.PrefixedName) {
+		a.publish(events.PolicyBlocked, entry.ServerName, "code mode call to "+entry.PrefixedName+" not in allowed_tools", map[string]any{
+			"tool": entry.PrefixedName,
+		})
+
 		return "", fmt.Errorf("%w: %s", ErrToolNotAllowed, entry.PrefixedName)
 	}
 
+	if !a.aclAllows(ctx, entry) {
+		return "", a.aclDenied(ctx, entry)
+	}
+
+	if !a.clientAllows(ctx, entry) {
+		return "", a.clientDenied(ctx, entry)
+	}
+
+	done, err := a.calls.begin(entry.ServerName)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
 	a.mu.RLock()
 	srv, exists := a.servers[entry.ServerName]
 	a.mu.RUnlock()
@@ -104,9 +124,42 @@ func (a *Aggregator) callToolText(ctx context.Context, name string, args map[str
 		return "", fmt.Errorf("%s: %w", entry.ServerName, ErrServerNotFound)
 	}
 
-	result, err := srv.CallTool(ctx, entry.Tool.Name, args)
+	cfg := srv.Config()
+	a.noteDeprecatedCall(ctx, entry, cfg)
+
+	if cfg != nil && cfg.DryRun {
+		return toolResultText(a.dryRunResult(entry, cfg, args)), nil
+	}
+
+	if maintErr := a.inMaintenance(entry.ServerName, cfg); maintErr != nil {
+		return "", maintErr
+	}
+
+	if authErr := a.authBlocked(entry.ServerName); authErr != nil {
+		return "", authErr
+	}
+
+	if err := a.startLazy(ctx, entry.ServerName); err != nil {
+		var authErr *AuthRequiredError
+		if errors.As(err, &authErr) {
+			return "", authErr
+		}
+
+		return "", fmt.Errorf("starting %s: %w", entry.ServerName, err)
+	}
+
+	if cfg != nil && cfg.Sampling {
+		defer a.sampling.track(ctx, entry.ServerName)()
+	}
+
+	ctx, args = withIdempotencyKey(ctx, cfg, entry.Tool.Name, args)
+
+	result, err := callWithTimeout(ctx, a.clock, srv, entry.Tool.Name, args, a.callTimeout(cfg))
 	if err != nil {
-		a.health.RecordFailure(entry.ServerName)
+		var authErr *AuthRequiredError
+		if err := a.recordFailure(ctx, entry.ServerName, "call "+entry.Tool.Name, err); errors.As(err, &authErr) {
+			return "", authErr
+		}
 
 		return "", fmt.Errorf("%s: %w", entry.ServerName, err)
 	}
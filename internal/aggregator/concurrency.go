@@ -0,0 +1,85 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// concurrencyLimits bounds the backend calls in flight per server with
+// max_concurrency set, with one semaphore per server.
+type concurrencyLimits struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newConcurrencyLimits() *concurrencyLimits {
+	return &concurrencyLimits{slots: make(map[string]chan struct{})}
+}
+
+// semaphore returns the semaphore of a server limited to limit calls. A
+// changed limit (after a reload) gets a new semaphore; calls holding a slot
+// of the old one release it there.
+func (l *concurrencyLimits) semaphore(name string, limit int) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem := l.slots[name]
+	if sem == nil || cap(sem) != limit {
+		sem = make(chan struct{}, limit)
+		l.slots[name] = sem
+	}
+
+	return sem
+}
+
+// acquire takes a call slot of the server cfg configures, returning the func
+// that frees it. Without a free slot it waits until one frees up or ctx ends
+// when cfg.Queue is set, and else fails with ErrServerBusy. Servers without
+// max_concurrency are not limited.
+func (l *concurrencyLimits) acquire(ctx context.Context, name string, cfg *config.ServerConfig) (func(), error) {
+	if cfg == nil || cfg.MaxConcurrency <= 0 {
+		return func() {}, nil
+	}
+
+	sem := l.semaphore(name, cfg.MaxConcurrency)
+	release := func() { <-sem }
+
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if !cfg.Queue {
+		return nil, fmt.Errorf("%s: %w (max_concurrency %d)", name, ErrServerBusy, cfg.MaxConcurrency)
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a free %s call slot: %w", name, ctx.Err())
+	}
+}
+
+// busyResult is the tool result of a call refused because its server is
+// at max_concurrency. It is marked retriable: a slot frees up as soon as
+// a call in flight ends.
+func busyResult(err error, server string) *mcp.CallToolResult {
+	data := map[string]any{
+		"error":     "server_busy",
+		"server":    server,
+		"message":   err.Error(),
+		"retriable": true,
+	}
+
+	result := mcp.NewToolResultStructured(data, err.Error())
+	result.IsError = true
+
+	return result
+}
@@ -0,0 +1,181 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		limits := newConcurrencyLimits()
+
+		for range 3 {
+			if _, err := limits.acquire(t.Context(), "github", &config.ServerConfig{}); err != nil {
+				t.Fatalf("acquire: %v", err)
+			}
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		t.Parallel()
+
+		limits := newConcurrencyLimits()
+		cfg := &config.ServerConfig{MaxConcurrency: 2}
+
+		var releases []func()
+
+		for range 2 {
+			release, err := limits.acquire(t.Context(), "github", cfg)
+			if err != nil {
+				t.Fatalf("acquire: %v", err)
+			}
+
+			releases = append(releases, release)
+		}
+
+		if _, err := limits.acquire(t.Context(), "github", cfg); !errors.Is(err, ErrServerBusy) {
+			t.Fatalf("acquire beyond the limit = %v, want ErrServerBusy", err)
+		}
+
+		// Other servers have their own slots
+		if _, err := limits.acquire(t.Context(), "jira", cfg); err != nil {
+			t.Errorf("acquire for another server: %v", err)
+		}
+
+		releases[0]()
+
+		if _, err := limits.acquire(t.Context(), "github", cfg); err != nil {
+			t.Errorf("acquire after a release: %v", err)
+		}
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		t.Parallel()
+
+		limits := newConcurrencyLimits()
+		cfg := &config.ServerConfig{MaxConcurrency: 1, Queue: true}
+
+		release, err := limits.acquire(t.Context(), "github", cfg)
+		if err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+
+		acquired := make(chan error, 1)
+
+		go func() {
+			_, err := limits.acquire(t.Context(), "github", cfg)
+			acquired <- err
+		}()
+
+		select {
+		case err := <-acquired:
+			t.Fatalf("queued call got a slot while the limit was reached: %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		release()
+
+		select {
+		case err := <-acquired:
+			if err != nil {
+				t.Errorf("queued acquire: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("queued call did not get the freed slot")
+		}
+	})
+
+	t.Run("queue cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		limits := newConcurrencyLimits()
+		cfg := &config.ServerConfig{MaxConcurrency: 1, Queue: true}
+
+		if _, err := limits.acquire(t.Context(), "github", cfg); err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := limits.acquire(ctx, "github", cfg); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("acquire with an expired context = %v, want DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("changed limit", func(t *testing.T) {
+		t.Parallel()
+
+		limits := newConcurrencyLimits()
+
+		release, err := limits.acquire(t.Context(), "github", &config.ServerConfig{MaxConcurrency: 1})
+		if err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+
+		// A reload raised the limit; the earlier call keeps its old slot
+		if _, err := limits.acquire(t.Context(), "github", &config.ServerConfig{MaxConcurrency: 2}); err != nil {
+			t.Errorf("acquire after raising the limit: %v", err)
+		}
+
+		release()
+	})
+}
+
+func TestCallToolServerBusy(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	mock.ServerCfg = &config.ServerConfig{MaxConcurrency: 1}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	// A call in flight holds the only slot
+	release, err := agg.limits.acquire(t.Context(), "github", mock.ServerCfg)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	result, err := agg.CallTool(t.Context(), "github_search", nil)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	data, _ := result.StructuredContent.(map[string]any)
+	if !result.IsError || data["error"] != "server_busy" || data["retriable"] != true {
+		t.Errorf("CallTool() = %+v, want a retriable server_busy error", result)
+	}
+
+	if len(mock.ToolCalls) != 0 {
+		t.Errorf("backend called %d times, want 0", len(mock.ToolCalls))
+	}
+
+	if stats := agg.health.Stats("github"); stats.Status != HealthUnknown && stats.Status != HealthHealthy {
+		t.Errorf("health = %s, a refused call is not a server failure", stats.Status)
+	}
+
+	release()
+
+	if result, err := agg.CallTool(t.Context(), "github_search", nil); err != nil || result.IsError {
+		t.Errorf("CallTool after the slot freed = %+v, %v", result, err)
+	}
+}
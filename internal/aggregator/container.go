@@ -0,0 +1,119 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// containerLabel labels the containers of container servers with the name
+// of their server, e.g. for docker ps --filter label=assern.server.
+const containerLabel = "assern.server"
+
+// containerRemoveTimeout bounds the removal of a container on Stop.
+const containerRemoveTimeout = 10 * time.Second
+
+// containerNameInvalid matches the characters container names may not hold.
+var containerNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// createContainerClient runs the server's image with the container engine
+// and speaks MCP over the container's stdio, as with a stdio server. The
+// engine gets the full environment, but only the server's own variables are
+// passed into the container (see containerArgs).
+func (s *ManagedServer) createContainerClient() (*client.Client, error) {
+	env := withLocale(s.env, s.conn.Locale)
+
+	s.container = containerName(s.name)
+
+	return client.NewStdioMCPClient(s.engine, env, containerArgs(s.container, s.name, s.conn, env)...)
+}
+
+// containerArgs returns the engine arguments running cfg's image as the
+// container name of server. Of env, only the variables of cfg.Env (secret
+// references included), ASSERN_SERVER and, with a locale, LANG and LC_ALL
+// are passed in, so host variables such as PATH never override the image's
+// and unrelated host secrets stay out. They are passed by name only, so that
+// secrets never appear on the engine's command line; the engine reads their
+// values from its own environment.
+func containerArgs(name, server string, cfg *config.ServerConfig, env []string) []string {
+	args := []string{"run", "--interactive", "--rm", "--name", name, "--label", containerLabel + "=" + server}
+
+	if cfg.WorkDir != "" {
+		args = append(args, "--workdir", cfg.WorkDir)
+	}
+
+	for _, volume := range cfg.Volumes {
+		args = append(args, "--volume", volume)
+	}
+
+	if cfg.Network != "" {
+		args = append(args, "--network", cfg.Network)
+	}
+
+	seen := make(map[string]bool, len(env))
+
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if forwardToContainer(cfg, key) && !seen[key] {
+			seen[key] = true
+			args = append(args, "--env", key)
+		}
+	}
+
+	args = append(args, cfg.Image)
+
+	return append(args, cfg.Args...)
+}
+
+// forwardToContainer reports whether the variable key is passed into the
+// container of cfg.
+func forwardToContainer(cfg *config.ServerConfig, key string) bool {
+	if _, ok := cfg.Env[key]; ok {
+		return true
+	}
+
+	switch key {
+	case ServerEnvVar:
+		return true
+	case "LANG", "LC_ALL":
+		return cfg.Locale != ""
+	}
+
+	return false
+}
+
+// containerName returns a name for a new container of server, unique so
+// that a container left over from an earlier run never blocks it.
+func containerName(server string) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return "assern-" + containerNameInvalid.ReplaceAllString(server, "-") + "-" + hex.EncodeToString(suffix)
+}
+
+// removeContainer force-removes the server's container, in case it outlived
+// the engine process; --rm already removes containers that exit. Callers
+// hold s.mu.
+func (s *ManagedServer) removeContainer() {
+	if s.container == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+	defer cancel()
+
+	// Fails with "no such container" after a normal exit
+	if out, err := exec.CommandContext(ctx, s.engine, "rm", "--force", s.container).CombinedOutput(); err != nil {
+		s.logger.Debug("container not removed", "container", s.container, "error", err, "output", strings.TrimSpace(string(out)))
+	}
+
+	s.container = ""
+}
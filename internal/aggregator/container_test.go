@@ -0,0 +1,179 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestContainerArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *config.ServerConfig
+		env  []string
+		want []string
+	}{
+		{
+			name: "image only",
+			cfg:  &config.ServerConfig{Image: "mcp/fetch"},
+			want: []string{"run", "--interactive", "--rm", "--name", "c1", "--label", "assern.server=fetch", "mcp/fetch"},
+		},
+		{
+			name: "everything",
+			cfg: &config.ServerConfig{
+				Image:   "ghcr.io/acme/mcp:1",
+				Args:    []string{"--stdio"},
+				Env:     map[string]string{"TOKEN": "keyring://acme/token"},
+				WorkDir: "/work",
+				Volumes: []string{"/src:/work:ro", "cache:/cache"},
+				Network: "none",
+			},
+			env: []string{"TOKEN=secret", "ASSERN_SERVER=fetch", "TOKEN=resolved"},
+			want: []string{
+				"run", "--interactive", "--rm", "--name", "c1", "--label", "assern.server=fetch",
+				"--workdir", "/work", "--volume", "/src:/work:ro", "--volume", "cache:/cache", "--network", "none",
+				"--env", "TOKEN", "--env", "ASSERN_SERVER", "ghcr.io/acme/mcp:1", "--stdio",
+			},
+		},
+		{
+			name: "host variables stay out",
+			cfg:  &config.ServerConfig{Image: "mcp/fetch", Env: map[string]string{"API_KEY": "${API_KEY}"}},
+			env:  []string{"PATH=/usr/bin", "HOME=/home/dev", "AWS_SECRET_ACCESS_KEY=x", "LANG=C", "API_KEY=k", "ASSERN_SERVER=fetch"},
+			want: []string{
+				"run", "--interactive", "--rm", "--name", "c1", "--label", "assern.server=fetch",
+				"--env", "API_KEY", "--env", "ASSERN_SERVER", "mcp/fetch",
+			},
+		},
+		{
+			name: "locale",
+			cfg:  &config.ServerConfig{Image: "mcp/fetch", Locale: "ja_JP.UTF-8"},
+			env:  []string{"PATH=/usr/bin", "LANG=ja_JP.UTF-8", "LC_ALL=ja_JP.UTF-8"},
+			want: []string{
+				"run", "--interactive", "--rm", "--name", "c1", "--label", "assern.server=fetch",
+				"--env", "LANG", "--env", "LC_ALL", "mcp/fetch",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := containerArgs("c1", "fetch", tt.cfg, tt.env); !slices.Equal(got, tt.want) {
+				t.Errorf("containerArgs() = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerName(t *testing.T) {
+	t.Parallel()
+
+	name := containerName("my server")
+	if !strings.HasPrefix(name, "assern-my-server-") || containerNameInvalid.MatchString(name) {
+		t.Errorf("containerName() = %q, want a valid name with the server in it", name)
+	}
+
+	if containerName("my server") == name {
+		t.Error("containerName() returned the same name twice")
+	}
+}
+
+// fakeEngine writes a container engine that logs its arguments to the
+// returned file and, for run, serves the stdio helper process in place of
+// the image.
+func fakeEngine(t *testing.T) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake engine is a shell script")
+	}
+
+	dir := t.TempDir()
+	engine := filepath.Join(dir, "engine")
+	log := filepath.Join(dir, "calls")
+
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %q\nif [ \"$1\" = run ]; then exec %q -test.run='^TestStdioHelperProcess$'; fi\n", log, os.Args[0])
+	if err := os.WriteFile(engine, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	return engine, log
+}
+
+func TestContainerServer(t *testing.T) {
+	t.Parallel()
+
+	engine, log := fakeEngine(t)
+
+	cfg := &config.ServerConfig{Image: "acme/helper", Network: "none", Env: map[string]string{envStdioHelper: "1"}}
+
+	srv, err := NewManagedServer("boxed", cfg, []string{envStdioHelper + "=1"}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	if srv.transportType != TransportContainer {
+		t.Fatalf("transport = %q, want container", srv.transportType)
+	}
+
+	srv.engine = engine
+
+	if err := srv.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	result, err := srv.CallTool(t.Context(), "echo", map[string]any{"text": "from the box"})
+	if err != nil || result.IsError {
+		t.Fatalf("CallTool() = %+v, %v", result, err)
+	}
+
+	name := srv.container
+
+	if err := srv.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(calls) != 2 || !strings.HasPrefix(calls[0], "run --interactive --rm --name "+name) ||
+		!strings.Contains(calls[0], "--network none") || !strings.Contains(calls[0], "--env "+envStdioHelper+" acme/helper") {
+		t.Fatalf("engine calls = %q, want run of the image", calls)
+	}
+
+	if calls[1] != "rm --force "+name {
+		t.Errorf("engine call on Stop = %q, want the container removed", calls[1])
+	}
+}
+
+func TestContainerServerEngineMissing(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewManagedServer("boxed", &config.ServerConfig{Image: "acme/helper"}, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	srv.engine = "assern-no-such-engine"
+
+	err = srv.Start(t.Context())
+
+	var notFound *CommandNotFoundError
+	if !errors.As(err, &notFound) || notFound.Command != "assern-no-such-engine" {
+		t.Errorf("Start() error = %v, want the engine not found", err)
+	}
+}
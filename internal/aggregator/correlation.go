@@ -0,0 +1,111 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// CorrelationMeta is the _meta field carrying the correlation ID of a
+	// tool call, from proxies to the primary instance and from it to
+	// backends.
+	CorrelationMeta = "assern/correlation_id"
+
+	// correlationHeader carries the correlation ID from HTTP clients and to
+	// HTTP and SSE backends.
+	correlationHeader = "X-Correlation-ID"
+)
+
+// correlationKey is the context key of the correlation ID of a tool call.
+type correlationKey struct{}
+
+// NewCorrelationID returns a random correlation ID for a tool call.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// withCorrelationID returns a context whose tool calls carry id.
+func withCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of the tool call in ctx, or "".
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+
+	return id
+}
+
+// correlate returns ctx carrying the correlation ID of req: the one already
+// in ctx, the one the first hop sent as _meta or X-Correlation-ID, or a new
+// one when this is the first hop.
+func correlate(ctx context.Context, req mcp.CallToolRequest) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+
+	if req.Params.Meta != nil {
+		if id, ok := req.Params.Meta.AdditionalFields[CorrelationMeta].(string); ok && id != "" {
+			return withCorrelationID(ctx, id)
+		}
+	}
+
+	if id := req.Header.Get(correlationHeader); id != "" {
+		return withCorrelationID(ctx, id)
+	}
+
+	return withCorrelationID(ctx, NewCorrelationID())
+}
+
+// correlateToolCalls is tool handler middleware giving every tools/call a
+// correlation ID before it is audited or routed, and logging it, so one grep
+// finds a call in the logs of the proxy, the primary and the backend.
+func (a *Aggregator) correlateToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = correlate(ctx, req)
+
+		a.logger.Debug("tool call", "tool", req.Params.Name, "client", callerName(ctx),
+			"correlation_id", CorrelationID(ctx))
+
+		return next(ctx, req)
+	}
+}
+
+// withCorrelationMeta returns meta plus the correlation ID in ctx, for the
+// _meta of a backend request. meta is not modified.
+func withCorrelationMeta(ctx context.Context, meta map[string]any) map[string]any {
+	id := CorrelationID(ctx)
+	if id == "" {
+		return meta
+	}
+
+	out := make(map[string]any, len(meta)+1)
+	maps.Copy(out, meta)
+	out[CorrelationMeta] = id
+
+	return out
+}
+
+// requestHeaders returns the traceparent and X-Correlation-ID headers of
+// the call in ctx for requests to HTTP and SSE backends, or nil.
+func requestHeaders(ctx context.Context) map[string]string {
+	headers := traceHeaders(ctx)
+
+	if id := CorrelationID(ctx); id != "" {
+		if headers == nil {
+			headers = make(map[string]string, 1)
+		}
+
+		headers[correlationHeader] = id
+	}
+
+	return headers
+}
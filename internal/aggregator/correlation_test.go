@@ -0,0 +1,84 @@
+package aggregator
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCorrelate(t *testing.T) {
+	t.Parallel()
+
+	withMeta := func(id string) mcp.CallToolRequest {
+		var req mcp.CallToolRequest
+		req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{CorrelationMeta: id}}
+
+		return req
+	}
+
+	withHeader := func(id string) mcp.CallToolRequest {
+		var req mcp.CallToolRequest
+		req.Header = http.Header{}
+		req.Header.Set(correlationHeader, id)
+
+		return req
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  mcp.CallToolRequest
+		want string // "" expects a new ID
+	}{
+		{"from an earlier hop in _meta", t.Context(), withMeta("proxy-id"), "proxy-id"},
+		{"from an HTTP header", t.Context(), withHeader("header-id"), "header-id"},
+		{"already in the context", withCorrelationID(t.Context(), "ctx-id"), withMeta("proxy-id"), "ctx-id"},
+		{"first hop", t.Context(), mcp.CallToolRequest{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := CorrelationID(correlate(tt.ctx, tt.req))
+
+			switch {
+			case tt.want != "" && got != tt.want:
+				t.Errorf("correlation ID = %q, want %q", got, tt.want)
+			case tt.want == "" && len(got) != 16:
+				t.Errorf("correlation ID = %q, want a new 16-character ID", got)
+			}
+		})
+	}
+}
+
+func TestWithCorrelationMeta(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]any{"progressToken": "p1"}
+
+	if got := withCorrelationMeta(t.Context(), meta); len(got) != 1 {
+		t.Errorf("withCorrelationMeta() without an ID = %v, want meta unchanged", got)
+	}
+
+	ctx := withCorrelationID(t.Context(), "abc123")
+
+	got := withCorrelationMeta(ctx, meta)
+	if got[CorrelationMeta] != "abc123" || got["progressToken"] != "p1" {
+		t.Errorf("withCorrelationMeta() = %v, want the correlation ID added", got)
+	}
+
+	if _, ok := meta[CorrelationMeta]; ok {
+		t.Error("withCorrelationMeta() modified its argument")
+	}
+
+	if headers := requestHeaders(ctx); headers[correlationHeader] != "abc123" {
+		t.Errorf("requestHeaders() = %v, want the correlation ID", headers)
+	}
+
+	if headers := requestHeaders(t.Context()); headers != nil {
+		t.Errorf("requestHeaders() without an ID = %v, want nil", headers)
+	}
+}
@@ -0,0 +1,133 @@
+package aggregator
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// unknownCaller names callers whose session carries no client info.
+const unknownCaller = "unknown"
+
+// DeprecatedUsage counts calls to one deprecated tool, per caller, so teams
+// can see which agents still need migrating.
+type DeprecatedUsage struct {
+	Tool     string           `json:"tool"`
+	Server   string           `json:"server"`
+	Note     string           `json:"note"`
+	Calls    int64            `json:"calls"`
+	Callers  map[string]int64 `json:"callers"`
+	LastCall time.Time        `json:"last_call"`
+}
+
+// deprecationNote returns the deprecation note for a backend tool: its
+// deprecated_tools entry, or else the server-wide deprecated note.
+func deprecationNote(cfg *config.ServerConfig, tool string) string {
+	if cfg == nil {
+		return ""
+	}
+
+	if note, ok := cfg.DeprecatedTools[tool]; ok && note != "" {
+		return note
+	}
+
+	return cfg.Deprecated
+}
+
+// withDeprecation returns tool with the deprecation note for it appended to
+// its description, so agents see it in tools/list and search results.
+func withDeprecation(tool mcp.Tool, cfg *config.ServerConfig) mcp.Tool {
+	note := deprecationNote(cfg, tool.Name)
+	if note == "" {
+		return tool
+	}
+
+	tool.Description = strings.TrimSpace(tool.Description + "\n\nDEPRECATED: " + note)
+
+	return tool
+}
+
+// deprecationTracker records calls to deprecated tools.
+type deprecationTracker struct {
+	mu    sync.Mutex
+	usage map[string]*DeprecatedUsage // Keyed by prefixed tool name
+}
+
+func newDeprecationTracker() *deprecationTracker {
+	return &deprecationTracker{usage: make(map[string]*DeprecatedUsage)}
+}
+
+func (t *deprecationTracker) record(entry *ToolEntry, note, caller string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u, ok := t.usage[entry.PrefixedName]
+	if !ok {
+		u = &DeprecatedUsage{Tool: entry.PrefixedName, Server: entry.ServerName, Callers: make(map[string]int64)}
+		t.usage[entry.PrefixedName] = u
+	}
+
+	u.Note = note
+	u.Calls++
+	u.Callers[caller]++
+	u.LastCall = at
+}
+
+// snapshot returns a copy of the recorded usage, sorted by tool name.
+func (t *deprecationTracker) snapshot() []DeprecatedUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]DeprecatedUsage, 0, len(t.usage))
+	for _, name := range slices.Sorted(maps.Keys(t.usage)) {
+		u := *t.usage[name]
+		u.Callers = maps.Clone(u.Callers)
+		out = append(out, u)
+	}
+
+	return out
+}
+
+// noteDeprecatedCall logs and counts a call to entry if it is deprecated.
+func (a *Aggregator) noteDeprecatedCall(ctx context.Context, entry *ToolEntry, cfg *config.ServerConfig) {
+	note := deprecationNote(cfg, entry.Tool.Name)
+	if note == "" {
+		return
+	}
+
+	caller := callerName(ctx)
+	a.deprecations.record(entry, note, caller, a.clock.Now())
+	a.logger.Warn("deprecated tool called", "tool", entry.PrefixedName, "server", entry.ServerName, "caller", caller, "note", note)
+}
+
+// DeprecatedUsage returns the calls made to deprecated tools since start.
+func (a *Aggregator) DeprecatedUsage() []DeprecatedUsage {
+	return a.deprecations.snapshot()
+}
+
+// callerName identifies the client behind ctx by the name and version it
+// sent in initialize.
+func callerName(ctx context.Context) string {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return unknownCaller
+	}
+
+	info := session.GetClientInfo()
+	switch {
+	case info.Name == "":
+		return unknownCaller
+	case info.Version == "":
+		return info.Name
+	default:
+		return info.Name + "/" + info.Version
+	}
+}
@@ -0,0 +1,121 @@
+package aggregator
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// clientInfoSession is a fakeSession that reports client info.
+type clientInfoSession struct {
+	*fakeSession
+	info mcp.Implementation
+}
+
+func (s *clientInfoSession) GetClientInfo() mcp.Implementation     { return s.info }
+func (s *clientInfoSession) SetClientInfo(info mcp.Implementation) { s.info = info }
+func (s *clientInfoSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{}
+}
+func (s *clientInfoSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+
+var _ server.SessionWithClientInfo = (*clientInfoSession)(nil)
+
+func TestDeprecationNote(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ServerConfig{
+		Deprecated:      "use github instead",
+		DeprecatedTools: map[string]string{"search": "use github_search_code"},
+	}
+
+	tests := []struct {
+		cfg  *config.ServerConfig
+		tool string
+		want string
+	}{
+		{cfg, "search", "use github_search_code"},
+		{cfg, "issues", "use github instead"},
+		{&config.ServerConfig{DeprecatedTools: map[string]string{"search": "gone"}}, "issues", ""},
+		{nil, "search", ""},
+	}
+
+	for _, tt := range tests {
+		if got := deprecationNote(tt.cfg, tt.tool); got != tt.want {
+			t.Errorf("deprecationNote(%q) = %q, want %q", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestDeprecatedToolCalls(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("legacy", []mcp.Tool{
+		mcp.NewTool("search", mcp.WithDescription("Search things")),
+		mcp.NewTool("fetch"),
+	})
+	mock.ServerCfg = &config.ServerConfig{
+		Command:         "legacy-mcp",
+		DeprecatedTools: map[string]string{"search": "use github_search_code instead"},
+	}
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	search, _ := agg.tools.Get("legacy_search")
+	if want := "Search things\n\nDEPRECATED: use github_search_code instead"; search.ExposedTool().Description != want {
+		t.Errorf("description = %q, want %q", search.ExposedTool().Description, want)
+	}
+
+	fetch, _ := agg.tools.Get("legacy_fetch")
+	if strings.Contains(fetch.ExposedTool().Description, "DEPRECATED") {
+		t.Errorf("non-deprecated tool description = %q", fetch.ExposedTool().Description)
+	}
+
+	srv := agg.CreateMCPServer()
+	session := &clientInfoSession{
+		fakeSession: newFakeSession("s1"),
+		info:        mcp.Implementation{Name: "claude-code", Version: "1.2.0"},
+	}
+	ctx := srv.WithContext(t.Context(), session)
+
+	for _, entry := range []*ToolEntry{search, search, fetch} {
+		if _, err := agg.createToolHandler(entry)(ctx, mcp.CallToolRequest{}); err != nil {
+			t.Fatalf("handler(%s): %v", entry.PrefixedName, err)
+		}
+	}
+
+	if _, err := agg.createToolHandler(search)(t.Context(), mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("handler without session: %v", err)
+	}
+
+	usage := agg.CallStats().Deprecated
+	if len(usage) != 1 {
+		t.Fatalf("deprecated usage = %+v, want one tool", usage)
+	}
+
+	u := usage[0]
+	if u.Tool != "legacy_search" || u.Calls != 3 || u.Note != "use github_search_code instead" {
+		t.Errorf("usage = %+v, want legacy_search with 3 calls", u)
+	}
+
+	if u.Callers["claude-code/1.2.0"] != 2 || u.Callers[unknownCaller] != 1 {
+		t.Errorf("callers = %v, want claude-code/1.2.0: 2, unknown: 1", u.Callers)
+	}
+}
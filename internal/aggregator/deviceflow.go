@@ -0,0 +1,409 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// deviceCodeGrantType is the grant_type of device access token requests
+// (RFC 8628, section 3.4).
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Device flow polling defaults, used when the authorization server leaves
+// them out.
+const (
+	deviceFlowInterval = 5 * time.Second
+	deviceFlowExpiry   = 15 * time.Minute
+)
+
+// deviceAuthorization is a device authorization response (RFC 8628,
+// section 3.2).
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// verification tells the user where to authorize: the URI with the code
+// filled in when the server offers one, else the URI and the code to enter.
+func (d *deviceAuthorization) verification() string {
+	if d.VerificationURIComplete != "" {
+		return d.VerificationURIComplete
+	}
+
+	return fmt.Sprintf("%s (code %s)", d.VerificationURI, d.UserCode)
+}
+
+// deviceTokenResponse is a token endpoint response, which for a pending
+// device authorization carries an error code instead of a token.
+type deviceTokenResponse struct {
+	transport.Token
+
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// deviceFlows tracks the device authorizations in progress, one per token
+// cache (see tokenCacheKey), so servers sharing an auth profile and repeated
+// failures wait on the same authorization.
+type deviceFlows struct {
+	mu      sync.Mutex
+	pending map[string]*pendingDevice
+	loops   *loopGroup
+}
+
+type pendingDevice struct {
+	verification string
+	servers      map[string]*config.ServerConfig // Servers to start or recover once authorized
+}
+
+func newDeviceFlows() *deviceFlows {
+	return &deviceFlows{pending: make(map[string]*pendingDevice), loops: newLoopGroup()}
+}
+
+// join adds a server to the pending authorization for key and returns its
+// verification text, or false when none is pending.
+func (f *deviceFlows) join(key, server string, cfg *config.ServerConfig) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.pending[key]
+	if !ok {
+		return "", false
+	}
+
+	p.servers[server] = cfg
+
+	return p.verification, true
+}
+
+// finish removes the pending authorization for key and returns the servers
+// waiting on it.
+func (f *deviceFlows) finish(key string) map[string]*config.ServerConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := f.pending[key]
+	delete(f.pending, key)
+
+	if p == nil {
+		return nil
+	}
+
+	return p.servers
+}
+
+// startDeviceFlow authorizes a server configured with oauth.device_flow: it
+// requests a device code, unless an authorization for the same token cache
+// is already pending, and polls for the token in the background. It returns
+// where the user authorizes, or "" when the server does not use the device
+// flow or it could not be started (the error is logged).
+func (a *Aggregator) startDeviceFlow(ctx context.Context, server string, cfg *config.ServerConfig, authErr error) string {
+	if cfg == nil || cfg.OAuth == nil || !cfg.OAuth.DeviceFlow {
+		return ""
+	}
+
+	key := tokenCacheKey(server, cfg)
+	if verification, ok := a.deviceFlows.join(key, server, cfg); ok {
+		return verification
+	}
+
+	store, err := oauthTokenStore(server, cfg, nil)
+	if err != nil {
+		a.logger.Warn("device authorization unavailable: no token cache", "server", server, "error", err)
+
+		return ""
+	}
+
+	deviceURL, tokenURL, err := deviceEndpoints(ctx, cfg.OAuth, client.GetOAuthHandler(authErr))
+	if err != nil {
+		a.logger.Warn("device authorization unavailable", "server", server, "error", err)
+
+		return ""
+	}
+
+	auth, err := requestDeviceCode(ctx, deviceURL, cfg.OAuth)
+	if err != nil {
+		a.logger.Warn("device authorization request failed", "server", server, "error", err)
+
+		return ""
+	}
+
+	a.deviceFlows.mu.Lock()
+	a.deviceFlows.pending[key] = &pendingDevice{
+		verification: auth.verification(),
+		servers:      map[string]*config.ServerConfig{server: cfg},
+	}
+	a.deviceFlows.mu.Unlock()
+
+	a.logger.Warn("authorize assern on any device to continue",
+		"server", server, "verification_uri", auth.VerificationURI, "user_code", auth.UserCode)
+
+	a.deviceFlows.loops.start(key, func(ctx context.Context) {
+		token, err := pollDeviceToken(ctx, a.clock, tokenURL, cfg.OAuth, auth)
+		if err == nil {
+			err = store.SaveToken(ctx, token)
+		}
+
+		servers := a.deviceFlows.finish(key)
+
+		if err != nil {
+			a.logger.Error("device authorization failed", "server", server, "error", err)
+
+			return
+		}
+
+		a.logger.Info("device authorization completed", "server", server)
+
+		for name, srvCfg := range servers {
+			a.authorized(ctx, name, srvCfg)
+		}
+	})
+
+	return auth.verification()
+}
+
+// authorized brings a server back after its token was obtained: a running
+// server leaves needs_auth, one that failed to start is started.
+func (a *Aggregator) authorized(ctx context.Context, name string, cfg *config.ServerConfig) {
+	a.mu.RLock()
+	_, running := a.servers[name]
+	a.mu.RUnlock()
+
+	if running {
+		a.health.RecordSuccess(name)
+
+		return
+	}
+
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	// The connection must outlive the flow's loop, which a later
+	// authorization for the same token cache cancels
+	if err := a.startServer(context.WithoutCancel(ctx), name, cfg); err != nil {
+		a.logger.Error("failed to start server after authorization", "server", name, "error", err)
+
+		return
+	}
+
+	a.addServerToolsToMCPServer(name)
+}
+
+// deviceEndpoints returns the device authorization and token endpoints:
+// from the config, from the metadata the OAuth handler of the failed
+// request discovered, and from the authorization server metadata document.
+func deviceEndpoints(ctx context.Context, oauth *config.OAuthConfig, handler *transport.OAuthHandler) (string, string, error) {
+	deviceURL, tokenURL, metadataURL := oauth.DeviceAuthorizationURL, "", oauth.AuthServerMetadataURL
+
+	if handler != nil {
+		if metadata, err := handler.GetServerMetadata(ctx); err == nil {
+			tokenURL = metadata.TokenEndpoint
+
+			if metadataURL == "" && metadata.Issuer != "" {
+				metadataURL = strings.TrimSuffix(metadata.Issuer, "/") + "/.well-known/oauth-authorization-server"
+			}
+		}
+	}
+
+	if (deviceURL == "" || tokenURL == "") && metadataURL != "" {
+		var metadata struct {
+			TokenEndpoint               string `json:"token_endpoint"`
+			DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+		}
+
+		if err := getJSON(ctx, metadataURL, &metadata); err != nil {
+			return "", "", fmt.Errorf("fetching authorization server metadata: %w", err)
+		}
+
+		if deviceURL == "" {
+			deviceURL = metadata.DeviceAuthorizationEndpoint
+		}
+
+		if tokenURL == "" {
+			tokenURL = metadata.TokenEndpoint
+		}
+	}
+
+	switch {
+	case deviceURL == "":
+		return "", "", errors.New("no device authorization endpoint: set oauth.device_authorization_url")
+	case tokenURL == "":
+		return "", "", errors.New("no token endpoint: set oauth.auth_server_metadata_url")
+	}
+
+	return deviceURL, tokenURL, nil
+}
+
+// requestDeviceCode starts a device authorization (RFC 8628, section 3.1).
+func requestDeviceCode(ctx context.Context, deviceURL string, oauth *config.OAuthConfig) (*deviceAuthorization, error) {
+	form := url.Values{"client_id": {oauth.ClientID}}
+	if len(oauth.Scopes) > 0 {
+		form.Set("scope", strings.Join(oauth.Scopes, " "))
+	}
+
+	if oauth.ClientSecret != "" {
+		form.Set("client_secret", oauth.ClientSecret)
+	}
+
+	status, body, err := postForm(ctx, deviceURL, form)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("device authorization endpoint returned %d: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	var auth deviceAuthorization
+	if err := json.Unmarshal(body, &auth); err != nil {
+		return nil, fmt.Errorf("decoding device authorization: %w", err)
+	}
+
+	if auth.DeviceCode == "" || auth.VerificationURI == "" {
+		return nil, errors.New("device authorization response lacks device_code or verification_uri")
+	}
+
+	return &auth, nil
+}
+
+// pollDeviceToken polls the token endpoint until the user has authorized
+// the device, the code expires or ctx is done (RFC 8628, section 3.5).
+func pollDeviceToken(ctx context.Context, clk clock.Clock, tokenURL string, oauth *config.OAuthConfig, auth *deviceAuthorization) (*transport.Token, error) {
+	interval := deviceFlowInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+
+	expiry := deviceFlowExpiry
+	if auth.ExpiresIn > 0 {
+		expiry = time.Duration(auth.ExpiresIn) * time.Second
+	}
+
+	ctx, cancel := clock.WithTimeout(ctx, clk, expiry)
+	defer cancel()
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {auth.DeviceCode},
+		"client_id":   {oauth.ClientID},
+	}
+
+	if oauth.ClientSecret != "" {
+		form.Set("client_secret", oauth.ClientSecret)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+				return nil, errors.New("device code expired before it was authorized")
+			}
+
+			return nil, ctx.Err()
+		case <-clk.After(interval):
+		}
+
+		_, body, err := postForm(ctx, tokenURL, form)
+		if err != nil {
+			return nil, err
+		}
+
+		// Errors come as JSON, with 400 or (GitHub) 200
+		var resp deviceTokenResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("decoding token response: %w", err)
+		}
+
+		switch resp.Error {
+		case "":
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+
+			continue
+		default:
+			return nil, fmt.Errorf("device authorization: %s %s", resp.Error, resp.ErrorDescription)
+		}
+
+		if resp.AccessToken == "" {
+			return nil, errors.New("token response lacks access_token")
+		}
+
+		token := resp.Token
+		if token.ExpiresIn > 0 {
+			token.ExpiresAt = clk.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		}
+
+		return &token, nil
+	}
+}
+
+// postForm posts a form to an OAuth endpoint and returns the response status
+// and body.
+func postForm(ctx context.Context, endpoint string, form url.Values) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("posting to %s: %w", endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading response from %s: %w", endpoint, err)
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// getJSON fetches a JSON document into out.
+func getJSON(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s: status %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s: %w", endpoint, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	var deviceRequests, tokenRequests atomic.Int32
+
+	// Receives a value per token request, once it is answered
+	polled := make(chan struct{}, 3)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                        srv.URL,
+			"token_endpoint":                srv.URL + "/token",
+			"device_authorization_endpoint": srv.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		deviceRequests.Add(1)
+
+		if r.FormValue("client_id") != "assern" || r.FormValue("scope") != "read write" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"device_code":      "dev-123",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": "https://auth.example/device",
+			"expires_in":       60,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != deviceCodeGrantType || r.FormValue("device_code") != "dev-123" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+
+			return
+		}
+
+		defer func() { polled <- struct{}{} }()
+
+		// The user authorizes after two polls
+		if tokenRequests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "authorization_pending"})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access",
+			"token_type":    "Bearer",
+			"refresh_token": "refresh",
+			"expires_in":    3600,
+		})
+	})
+
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), Clock: fake})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	cfg := &config.ServerConfig{
+		URL: "https://linear.example/mcp",
+		OAuth: &config.OAuthConfig{
+			ClientID:              "assern",
+			Scopes:                []string{"read", "write"},
+			AuthServerMetadataURL: srv.URL + "/.well-known/oauth-authorization-server",
+			DeviceFlow:            true,
+		},
+	}
+
+	mock := testutil.NewMockServer("linear", []mcp.Tool{mcp.NewTool("search")})
+	mock.ServerCfg = cfg
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	const want = "https://auth.example/device (code ABCD-EFGH)"
+
+	waiters := fake.Waiters()
+
+	// A second failure while the first authorization is pending joins it
+	for range 2 {
+		authErr := agg.markNeedsAuth(t.Context(), "linear", cfg, errors.New("unauthorized"))
+		if authErr.AuthURL != want {
+			t.Fatalf("AuthURL = %q, want %q", authErr.AuthURL, want)
+		}
+	}
+
+	// Each poll waits out the interval, next to the code's expiry
+	for range 3 {
+		fake.BlockUntil(waiters + 2)
+		fake.Advance(deviceFlowInterval)
+		<-polled
+	}
+
+	agg.deviceFlows.loops.wg.Wait()
+
+	if health := agg.ServerHealth("linear"); health == HealthNeedsAuth {
+		t.Fatal("server still needs authorization after the token was issued")
+	}
+
+	if n := deviceRequests.Load(); n != 1 {
+		t.Errorf("device authorization requests = %d, want 1", n)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".valksor", "assern", "tokens", "linear.json"))
+	if err != nil {
+		t.Fatalf("reading cached token: %v", err)
+	}
+
+	var token struct {
+		AccessToken  string    `json:"access_token"`
+		RefreshToken string    `json:"refresh_token"`
+		ExpiresAt    time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		t.Fatalf("decoding cached token: %v", err)
+	}
+
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" || token.ExpiresAt.IsZero() {
+		t.Errorf("cached token = %+v, want the issued token with an expiry", token)
+	}
+}
+
+func TestPollDeviceTokenDenied(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// GitHub reports device flow errors with status 200
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": "access_denied", "error_description": "denied by user"})
+	}))
+	t.Cleanup(srv.Close)
+
+	auth := &deviceAuthorization{DeviceCode: "dev", Interval: 1}
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	errc := make(chan error, 1)
+
+	go func() {
+		_, err := pollDeviceToken(t.Context(), fake, srv.URL, &config.OAuthConfig{ClientID: "assern"}, auth)
+		errc <- err
+	}()
+
+	fake.BlockUntil(2)
+	fake.Advance(time.Second)
+
+	if err := <-errc; err == nil {
+		t.Fatal("pollDeviceToken() error = nil, want access_denied")
+	}
+}
@@ -134,8 +134,9 @@ func (d *discoveryState) loadedCount(sessionID string) int {
 }
 
 // DiscoveryEnabled reports whether progressive tool disclosure is active.
+// It is not under tool_exposure: search (see SearchExposure).
 func (a *Aggregator) DiscoveryEnabled() bool {
-	return a.discoveryConfig().IsEnabled()
+	return a.discoveryConfig().IsEnabled() && a.FeatureEnabled(config.FeatureMetaTools) && !a.SearchExposure()
 }
 
 // discoveryConfig returns the configured discovery settings, or nil. It reads
@@ -151,18 +152,15 @@ func (a *Aggregator) discoveryConfig() *config.DiscoveryConfig {
 	return a.cfg.Settings.Discovery
 }
 
-// discoveryHooks returns server hooks that clean up per-session discovery state
-// when a client disconnects.
-func (a *Aggregator) discoveryHooks() *server.Hooks {
-	hooks := &server.Hooks{}
+// addDiscoveryHooks adds server hooks that clean up per-session discovery
+// state when a client disconnects.
+func (a *Aggregator) addDiscoveryHooks(hooks *server.Hooks) {
 	hooks.OnUnregisterSession = append(hooks.OnUnregisterSession,
 		func(_ context.Context, session server.ClientSession) {
 			if a.discovery != nil {
 				a.discovery.forgetSession(session.SessionID())
 			}
 		})
-
-	return hooks
 }
 
 // registerMetaTools adds the assern_* discovery meta-tools to the MCP server.
@@ -230,7 +228,19 @@ type searchMatch struct {
 }
 
 // handleSearch implements the assern_search meta-tool.
-func (a *Aggregator) handleSearch(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (a *Aggregator) handleSearch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	results := a.searchCatalog(ctx, req)
+
+	return jsonResult(map[string]any{
+		"matches": results,
+		"count":   len(results),
+		"hint":    "Call assern_load with the names you need to make them callable.",
+	}), nil
+}
+
+// searchCatalog ranks the tools matching the "query" argument of req that
+// the caller may call, up to its "limit" or discovery.max_results.
+func (a *Aggregator) searchCatalog(ctx context.Context, req mcp.CallToolRequest) []searchMatch {
 	query := req.GetString("query", "")
 
 	limit := req.GetInt("limit", 0)
@@ -238,10 +248,20 @@ func (a *Aggregator) handleSearch(_ context.Context, req mcp.CallToolRequest) (*
 		limit = a.discoveryConfig().EffectiveMaxResults()
 	}
 
-	matches := a.tools.Search(query, limit)
+	// Ranked without a limit, so tools the client may not call, which are
+	// left out, do not take the places of others
+	matches := a.tools.Search(query, 0)
 
-	results := make([]searchMatch, 0, len(matches))
+	results := make([]searchMatch, 0, min(len(matches), limit))
 	for _, e := range matches {
+		if len(results) == limit {
+			break
+		}
+
+		if !a.aclAllows(ctx, e) || !a.clientAllows(ctx, e) {
+			continue
+		}
+
 		results = append(results, searchMatch{
 			Name:            e.PrefixedName,
 			Server:          e.ServerName,
@@ -250,11 +270,7 @@ func (a *Aggregator) handleSearch(_ context.Context, req mcp.CallToolRequest) (*
 		})
 	}
 
-	return jsonResult(map[string]any{
-		"matches": results,
-		"count":   len(results),
-		"hint":    "Call assern_load with the names you need to make them callable.",
-	}), nil
+	return results
 }
 
 // handleLoad implements the assern_load meta-tool.
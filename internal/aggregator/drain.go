@@ -0,0 +1,241 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+)
+
+// callTracker counts the tool calls in flight per server, so a reload can let
+// a changed server finish its outstanding calls before stopping it. While a
+// server drains, new calls are refused with ErrServerDraining.
+type callTracker struct {
+	mu      sync.Mutex
+	servers map[string]*serverCalls
+}
+
+type serverCalls struct {
+	active   int
+	draining bool
+	idle     chan struct{} // Closed when active drops to zero while draining
+
+	// epoch is bumped by handoff; previous counts the active calls begun
+	// in an earlier epoch, and previousIdle is closed when they have ended
+	epoch        int
+	previous     int
+	previousIdle chan struct{}
+}
+
+func newCallTracker() *callTracker {
+	return &callTracker{servers: make(map[string]*serverCalls)}
+}
+
+// begin records a call to a server. The returned func ends it and must be
+// called exactly once. It fails with ErrServerDraining while the server
+// drains.
+func (t *callTracker) begin(name string) (func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sc := t.servers[name]
+	if sc == nil {
+		sc = &serverCalls{}
+		t.servers[name] = sc
+	}
+
+	if sc.draining {
+		return nil, fmt.Errorf("%s: %w", name, ErrServerDraining)
+	}
+
+	sc.active++
+	epoch := sc.epoch
+
+	var once sync.Once
+
+	return func() { once.Do(func() { t.end(name, epoch) }) }, nil
+}
+
+func (t *callTracker) end(name string, epoch int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sc := t.servers[name]
+	sc.active--
+
+	if epoch != sc.epoch {
+		sc.previous--
+
+		if sc.previous == 0 && sc.previousIdle != nil {
+			close(sc.previousIdle)
+			sc.previousIdle = nil
+		}
+	}
+
+	if sc.active > 0 {
+		return
+	}
+
+	if !sc.draining {
+		delete(t.servers, name)
+
+		return
+	}
+
+	if sc.idle != nil {
+		close(sc.idle)
+		sc.idle = nil
+	}
+}
+
+// drain refuses new calls to the servers and returns a channel per server
+// with calls in flight, closed once they have all ended.
+func (t *callTracker) drain(names []string) map[string]<-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	waits := make(map[string]<-chan struct{})
+
+	for _, name := range names {
+		sc := t.servers[name]
+		if sc == nil {
+			sc = &serverCalls{}
+			t.servers[name] = sc
+		}
+
+		sc.draining = true
+
+		if sc.active > 0 && sc.idle == nil {
+			sc.idle = make(chan struct{})
+		}
+
+		if sc.idle != nil {
+			waits[name] = sc.idle
+		}
+	}
+
+	return waits
+}
+
+// handoff marks the calls in flight to the servers as belonging to the
+// backends a blue-green reload just replaced, and returns a channel per
+// server with such calls, closed once they have all ended. Calls begun
+// afterwards reach the new backends and are not waited for.
+func (t *callTracker) handoff(names []string) map[string]<-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	waits := make(map[string]<-chan struct{})
+
+	for _, name := range names {
+		sc := t.servers[name]
+		if sc == nil || sc.active == 0 {
+			continue
+		}
+
+		sc.epoch++
+		sc.previous = sc.active
+
+		if sc.previousIdle == nil {
+			sc.previousIdle = make(chan struct{})
+		}
+
+		waits[name] = sc.previousIdle
+	}
+
+	return waits
+}
+
+// resume accepts calls to the servers again.
+func (t *callTracker) resume(names []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, name := range names {
+		sc := t.servers[name]
+		if sc == nil {
+			continue
+		}
+
+		sc.draining = false
+		sc.idle = nil
+
+		if sc.active == 0 {
+			delete(t.servers, name)
+		}
+	}
+}
+
+// active returns the number of calls in flight to a server.
+func (t *callTracker) active(name string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if sc := t.servers[name]; sc != nil {
+		return sc.active
+	}
+
+	return 0
+}
+
+// drainServers refuses new calls to the servers and waits, up to the
+// configured drain timeout in total, for their in-flight calls to end.
+// Servers still busy when the timeout passes are logged and stopped anyway.
+// The caller resumes the servers with a.calls.resume once restarted.
+func (a *Aggregator) drainServers(ctx context.Context, names []string) {
+	a.awaitCalls(ctx, names, a.calls.drain(names))
+}
+
+// awaitCalls waits, up to the configured drain timeout in total, for the
+// channels in waits (from callTracker.drain or handoff) to close.
+func (a *Aggregator) awaitCalls(ctx context.Context, names []string, waits map[string]<-chan struct{}) {
+	if len(waits) == 0 {
+		return
+	}
+
+	a.cfgMu.RLock()
+	timeout := a.cfg.Settings.EffectiveDrainTimeout()
+	a.cfgMu.RUnlock()
+
+	ctx, cancel := clock.WithTimeout(ctx, a.clock, timeout)
+	defer cancel()
+
+	for _, name := range names {
+		idle, ok := waits[name]
+		if !ok {
+			continue
+		}
+
+		a.logger.Info("waiting for in-flight calls before stopping server",
+			"server", name, "calls", a.calls.active(name), "timeout", timeout)
+
+		start := a.clock.Now()
+
+		select {
+		case <-idle:
+			a.logger.Debug("server drained", "server", name, "waited", a.clock.Since(start))
+		case <-ctx.Done():
+			a.logger.Warn("drain timeout reached, stopping server with calls in flight",
+				"server", name, "calls", a.calls.active(name))
+		}
+	}
+}
+
+// drainingResult is the tool result of a call refused while its server
+// drains. It is marked retriable: the server is back once the reload ends.
+func drainingResult(err error, server string) *mcp.CallToolResult {
+	data := map[string]any{
+		"error":     "server_restarting",
+		"server":    server,
+		"message":   err.Error(),
+		"retriable": true,
+	}
+
+	result := mcp.NewToolResultStructured(data, err.Error())
+	result.IsError = true
+
+	return result
+}
@@ -0,0 +1,209 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestCallTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newCallTracker()
+
+	done, err := tracker.begin("github")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	waits := tracker.drain([]string{"github", "idle"})
+	if _, ok := waits["idle"]; ok {
+		t.Error("drain waits on a server without calls")
+	}
+
+	idle, ok := waits["github"]
+	if !ok {
+		t.Fatal("drain does not wait on a server with a call in flight")
+	}
+
+	if _, err := tracker.begin("github"); !errors.Is(err, ErrServerDraining) {
+		t.Errorf("begin while draining = %v, want ErrServerDraining", err)
+	}
+
+	select {
+	case <-idle:
+		t.Fatal("drained before the call ended")
+	default:
+	}
+
+	done()
+	done() // Ending twice is harmless
+
+	select {
+	case <-idle:
+	case <-time.After(time.Second):
+		t.Fatal("not drained after the call ended")
+	}
+
+	tracker.resume([]string{"github", "idle"})
+
+	if _, err := tracker.begin("github"); err != nil {
+		t.Errorf("begin after resume: %v", err)
+	}
+}
+
+func TestDrainServers(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.DrainTimeout = time.Minute
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler), Clock: fake})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_search")
+	handler := agg.createToolHandler(entry)
+
+	// A call in flight holds the drain until it ends
+	done, err := agg.calls.begin("github")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	// drainServers, with the drain started before waiting in the background
+	names := []string{"github"}
+	waits := agg.calls.drain(names)
+	drained := make(chan struct{})
+
+	go func() {
+		agg.awaitCalls(t.Context(), names, waits)
+		close(drained)
+	}()
+
+	// New calls are refused with a retriable error meanwhile
+	result, err := handler(t.Context(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	if data, _ := result.StructuredContent.(map[string]any); data["error"] != "server_restarting" || !result.IsError || data["retriable"] != true {
+		t.Errorf("draining result = %+v, want a retriable server_restarting tool error", result)
+	}
+
+	// The drain timeout is waited for on the fake clock, which does not move
+	fake.BlockUntil(1)
+
+	select {
+	case <-drained:
+		t.Fatal("drained with a call in flight")
+	default:
+	}
+
+	done()
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("not drained after the call ended")
+	}
+
+	agg.calls.resume([]string{"github"})
+
+	result, err = handler(t.Context(), mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Errorf("handler after resume = %+v, %v", result, err)
+	}
+}
+
+func TestDrainServersTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.DrainTimeout = time.Minute
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler), Clock: fake})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	done, err := agg.calls.begin("github")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer done()
+
+	drained := make(chan struct{})
+
+	go func() {
+		agg.drainServers(t.Context(), []string{"github"})
+		close(drained)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drainServers still waiting after the drain timeout")
+	}
+}
+
+func TestCallTrackerHandoff(t *testing.T) {
+	t.Parallel()
+
+	tracker := newCallTracker()
+
+	if waits := tracker.handoff([]string{"github"}); len(waits) != 0 {
+		t.Errorf("handoff waits on a server without calls: %v", waits)
+	}
+
+	before, err := tracker.begin("github")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	idle, ok := tracker.handoff([]string{"github"})["github"]
+	if !ok {
+		t.Fatal("handoff does not wait on a call begun before it")
+	}
+
+	// Calls begun after the handoff are not waited for and are not refused
+	after, err := tracker.begin("github")
+	if err != nil {
+		t.Fatalf("begin after handoff: %v", err)
+	}
+	defer after()
+
+	before()
+
+	select {
+	case <-idle:
+	case <-time.After(time.Second):
+		t.Fatal("handoff not idle after the earlier call ended")
+	}
+
+	if got := tracker.active("github"); got != 1 {
+		t.Errorf("active = %d, want the later call", got)
+	}
+}
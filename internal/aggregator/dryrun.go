@@ -0,0 +1,81 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// dryRunRequest describes the call assern would have forwarded to a backend
+// for a server with dry_run set.
+type dryRunRequest struct {
+	DryRun      bool              `json:"dry_run"`
+	Server      string            `json:"server"`
+	Tool        string            `json:"tool"`
+	BackendTool string            `json:"backend_tool"`
+	Arguments   map[string]any    `json:"arguments"`
+	Transport   string            `json:"transport,omitempty"`
+	Command     string            `json:"command,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// dryRunResult returns the would-be request as the tool result instead of
+// calling the backend. Env and header values are summarized by redactValue
+// so the result can be shared without leaking credentials.
+func (a *Aggregator) dryRunResult(entry *ToolEntry, cfg *config.ServerConfig, args map[string]any) *mcp.CallToolResult {
+	a.logger.Info("dry run: tool call not forwarded", "tool", entry.PrefixedName, "server", entry.ServerName)
+
+	req := dryRunRequest{
+		DryRun:      true,
+		Server:      entry.ServerName,
+		Tool:        entry.PrefixedName,
+		BackendTool: entry.Tool.Name,
+		Arguments:   args,
+		Transport:   string(detectTransport(cfg)),
+		Command:     cfg.Command,
+		Args:        cfg.Args,
+		URL:         cfg.URL,
+		Env:         redactValues(cfg.Env),
+		Headers:     redactValues(cfg.Headers),
+	}
+
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("dry run: encoding request: %v", err))
+	}
+
+	return mcp.NewToolResultText(string(data))
+}
+
+// redactValues keeps ${VAR} references, which show where a value comes from,
+// and hides literal values.
+func redactValues(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = redactValue(v)
+	}
+
+	return out
+}
+
+func redactValue(v string) string {
+	switch {
+	case v == "":
+		return ""
+	case strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") && strings.Count(v, "${") == 1:
+		return v
+	default:
+		return "<redacted>"
+	}
+}
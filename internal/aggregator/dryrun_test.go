@@ -0,0 +1,67 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestDryRunDoesNotCallBackend(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	mock.ServerCfg = &config.ServerConfig{
+		Command: "gh-mcp",
+		DryRun:  true,
+		Env:     map[string]string{"TOKEN": "secret", "HOST": "${GH_HOST}"},
+	}
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_search")
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"q": "go"}
+
+	result, err := agg.createToolHandler(entry)(t.Context(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("handler = %+v, %v", result, err)
+	}
+
+	if len(mock.ToolCalls) != 0 {
+		t.Errorf("backend called %d times in dry-run mode", len(mock.ToolCalls))
+	}
+
+	var got dryRunRequest
+	if err := json.Unmarshal([]byte(toolResultText(result)), &got); err != nil {
+		t.Fatalf("result is not a dry-run request: %v", err)
+	}
+
+	if got.Tool != "github_search" || got.BackendTool != "search" || got.Arguments["q"] != "go" {
+		t.Errorf("request = %+v", got)
+	}
+
+	if got.Transport != string(TransportStdio) || got.Command != "gh-mcp" {
+		t.Errorf("transport = %q, command = %q", got.Transport, got.Command)
+	}
+
+	if got.Env["TOKEN"] != "<redacted>" || got.Env["HOST"] != "${GH_HOST}" {
+		t.Errorf("env = %v, want literal redacted and reference kept", got.Env)
+	}
+}
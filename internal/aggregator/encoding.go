@@ -0,0 +1,164 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+
+	"github.com/valksor/go-assern/internal/clock"
+)
+
+// transcodedStopTimeout is how long a transcoded stdio process may take to
+// exit once its stdin is closed before it is killed.
+const transcodedStopTimeout = 5 * time.Second
+
+// transcodedStdio is the stdio transport of a server whose output is not
+// UTF-8 (its encoding setting). assern runs the process itself: its stdout
+// and stderr are decoded to UTF-8 before JSON parsing, and what is written to
+// its stdin is encoded back, characters the encoding lacks becoming \u
+// escapes, which keeps the JSON valid and loses nothing.
+type transcodedStdio struct {
+	*transport.Stdio
+
+	cmd   *exec.Cmd
+	clock clock.Clock
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// startTranscodedStdio starts cmd (see stdioCommand); clk times its stop.
+func startTranscodedStdio(cmd *exec.Cmd, enc encoding.Encoding, clk clock.Clock) (*transcodedStdio, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting command: %w", err)
+	}
+
+	t := &transcodedStdio{
+		Stdio: transport.NewIO(
+			transform.NewReader(stdout, enc.NewDecoder()),
+			&transcodedWriter{pipe: stdin, enc: enc.NewEncoder()},
+			&transcodedReader{Reader: transform.NewReader(stderr, enc.NewDecoder()), pipe: stderr},
+		),
+		cmd:   cmd,
+		clock: clock.OrReal(clk),
+	}
+
+	return t, nil
+}
+
+// Close closes the process's stdin and stderr, and waits for it to exit,
+// killing it after transcodedStopTimeout. Like mcp-go's transport, it
+// reports how the process exited. Later calls return the same error.
+func (t *transcodedStdio) Close() error {
+	t.closeOnce.Do(func() {
+		closeErr := t.Stdio.Close()
+
+		waited := make(chan error, 1)
+		go func() { waited <- t.cmd.Wait() }()
+
+		var err error
+
+		select {
+		case err = <-waited:
+		case <-t.clock.After(transcodedStopTimeout):
+			_ = t.cmd.Process.Kill()
+			err = <-waited
+		}
+
+		t.closeErr = errors.Join(closeErr, err)
+	})
+
+	return t.closeErr
+}
+
+// transcodedWriter encodes the JSON messages written to a process's stdin.
+// mcp-go writes each message whole, so a write never ends mid-character.
+type transcodedWriter struct {
+	mu   sync.Mutex
+	pipe io.WriteCloser
+	enc  *encoding.Encoder
+}
+
+func (w *transcodedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.pipe.Write(encodeJSON(w.enc, p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *transcodedWriter) Close() error {
+	return w.pipe.Close()
+}
+
+// encodeJSON encodes the JSON text p with enc. Non-ASCII characters only
+// occur in JSON strings, so those enc lacks are written as \u escapes.
+func encodeJSON(enc *encoding.Encoder, p []byte) []byte {
+	if out, err := enc.Bytes(p); err == nil {
+		return out
+	}
+
+	out := make([]byte, 0, len(p))
+
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		char := p[:size]
+		p = p[size:]
+
+		if r < utf8.RuneSelf {
+			out = append(out, char...)
+
+			continue
+		}
+
+		if encoded, err := enc.Bytes(char); err == nil {
+			out = append(out, encoded...)
+
+			continue
+		}
+
+		for _, unit := range utf16.Encode([]rune{r}) {
+			out = fmt.Appendf(out, `\u%04x`, unit)
+		}
+	}
+
+	return out
+}
+
+// transcodedReader decodes what a process writes to stderr.
+type transcodedReader struct {
+	io.Reader
+
+	pipe io.Closer
+}
+
+func (r *transcodedReader) Close() error {
+	return r.pipe.Close()
+}
@@ -0,0 +1,162 @@
+package aggregator
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestTranscodedServer(t *testing.T) {
+	t.Parallel()
+
+	// Characters within windows-1252, outside Latin-1, within Shift JIS and
+	// outside both: those an encoding lacks travel as \u escapes
+	const text = "café – Grüße こんにちは ✓"
+
+	tests := []struct {
+		name     string
+		encoding string
+		line     string // Written to stderr, in the encoding
+	}{
+		{name: "utf-8", encoding: "", line: "Grüße ✓"},
+		{name: "windows-1252", encoding: "windows-1252", line: "Grüße"},
+		{name: "shift_jis", encoding: "shift_jis", line: "こんにちは"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.ServerConfig{
+				Command:  os.Args[0],
+				Args:     []string{"-test.run=^TestStdioHelperProcess$"},
+				Encoding: tt.encoding,
+			}
+
+			env := []string{envStdioHelper + "=1", envHelperEncoding + "=" + tt.encoding}
+
+			srv, err := NewManagedServer("helper", cfg, env, slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatalf("NewManagedServer: %v", err)
+			}
+
+			logs := newServerLogs()
+			srv.stderr = newStderrLog("helper", nil, logs, srv.logger, clock.Real)
+
+			if err := srv.Start(t.Context()); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+
+			t.Cleanup(func() { _ = srv.Stop() })
+
+			result, err := srv.CallTool(t.Context(), "echo", map[string]any{"text": text})
+			if err != nil {
+				t.Fatalf("CallTool(echo): %v", err)
+			}
+
+			if got := result.Content[0].(mcp.TextContent).Text; got != text {
+				t.Errorf("echo = %q, want %q", got, text)
+			}
+
+			_, lines, stop := logs.follow("helper", 0)
+			t.Cleanup(stop)
+
+			if _, err := srv.CallTool(t.Context(), "log", map[string]any{"line": tt.line}); err != nil {
+				t.Fatalf("CallTool(log): %v", err)
+			}
+
+			for {
+				select {
+				case l := <-lines:
+					if l.Line == tt.line {
+						return
+					}
+				case <-t.Context().Done():
+					t.Fatalf("stderr lines = %v, want %q", logs.tail("helper", 0), tt.line)
+				}
+			}
+		})
+	}
+}
+
+func TestTranscodedServerUnknownEncoding(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewManagedServer("helper", &config.ServerConfig{Command: os.Args[0], Encoding: "klingon"}, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	if err := srv.Start(t.Context()); err == nil || !strings.Contains(err.Error(), `unknown encoding "klingon"`) {
+		t.Errorf("Start error = %v, want unknown encoding", err)
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		env    []string
+		locale string
+		want   []string
+	}{
+		{name: "no locale", env: []string{"A=1"}, want: []string{"A=1"}},
+		{name: "added", env: []string{"A=1"}, locale: "ja_JP.UTF-8", want: []string{"A=1", "LANG=ja_JP.UTF-8", "LC_ALL=ja_JP.UTF-8"}},
+		{name: "env wins", env: []string{"LC_ALL=C"}, locale: "de_DE.UTF-8", want: []string{"LC_ALL=C", "LANG=de_DE.UTF-8"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := make([]string, len(tt.env), len(tt.env)+4)
+			copy(env, tt.env)
+
+			if got := withLocale(env, tt.locale); !slices.Equal(got, tt.want) {
+				t.Errorf("withLocale() = %v, want %v", got, tt.want)
+			}
+
+			if !slices.Equal(env[:len(tt.env)], tt.env) || len(env) != len(tt.env) {
+				t.Errorf("withLocale changed its input to %v", env)
+			}
+		})
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	t.Parallel()
+
+	enc, err := config.LookupEncoding("windows-1252")
+	if err != nil {
+		t.Fatalf("LookupEncoding: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "ascii", in: `{"a":"b"}`, want: `{"a":"b"}`},
+		{name: "encodable", in: `{"a":"café"}`, want: "{\"a\":\"caf\xe9\"}"},
+		{name: "escaped", in: `{"a":"é✓"}`, want: "{\"a\":\"\xe9\\u2713\"}"},
+		{name: "surrogates", in: `{"a":"😀"}`, want: `{"a":"\ud83d\ude00"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := string(encodeJSON(enc.NewEncoder(), []byte(tt.in))); got != tt.want {
+				t.Errorf("encodeJSON(%s) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// serverEnv returns the environment a server process is launched with, or
+// nil (inherit assern's) without a loader.
+func (a *Aggregator) serverEnv(loader *env.Loader, cfg *config.ServerConfig) []string {
+	if loader == nil {
+		return nil
+	}
+
+	projectName := ""
+	if a.projectCtx != nil {
+		projectName = a.projectCtx.Name
+	}
+
+	return loader.BuildServerEnv(cfg.Env, projectName)
+}
+
+// serverConnection returns cfg with the ${VAR} references of its args,
+// work directory, URL and headers expanded by loader, or from assern's own
+// environment without one.
+func serverConnection(loader *env.Loader, cfg *config.ServerConfig) *config.ServerConfig {
+	lookup := os.Getenv
+	if loader != nil {
+		lookup = loader.Get
+	}
+
+	return cfg.WithExpandedReferences(lookup)
+}
+
+// ReloadEnv builds the environment from the .env files again and restarts
+// the servers whose expanded env settings, args, work directory, URL or
+// headers changed, e.g. after an API token referenced as ${GITHUB_TOKEN}
+// was rotated; servers that do not reference the changed variables keep
+// running. Restarted servers are drained first, as on Reload. It returns
+// the names of the restarted servers.
+func (a *Aggregator) ReloadEnv(ctx context.Context) ([]string, error) {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	if a.loadEnv == nil {
+		return nil, ErrConfigNotReloadable
+	}
+
+	loader := a.loadEnv(ctx)
+
+	a.cfgMu.Lock()
+	previous := a.envLoader
+	a.envLoader = loader
+	a.cfgMu.Unlock()
+
+	a.mu.RLock()
+	configs := make(map[string]*config.ServerConfig, len(a.servers))
+	for name, srv := range a.servers {
+		configs[name] = srv.Config()
+	}
+	a.mu.RUnlock()
+
+	var changed []string
+
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		cfg := configs[name]
+
+		if !maps.Equal(expandedEnv(previous, cfg), expandedEnv(loader, cfg)) ||
+			!serverConnection(previous, cfg).Equal(serverConnection(loader, cfg)) {
+			changed = append(changed, name)
+		}
+	}
+
+	if len(changed) == 0 {
+		a.logger.Info("environment files changed, no server environment affected")
+
+		return nil, nil
+	}
+
+	// Values are secrets; only the affected servers are logged
+	a.logger.Info("environment changed, restarting servers", "servers", changed)
+
+	for _, name := range changed {
+		a.runtime.restarting(name)
+	}
+
+	a.drainServers(ctx, changed)
+
+	var errs []string
+
+	for _, name := range changed {
+		if err := a.stopServer(name); err != nil {
+			a.logger.Error("failed to stop server for restart", "server", name, "error", err)
+		}
+
+		if err := a.startServer(ctx, name, configs[name]); err != nil {
+			errs = append(errs, fmt.Sprintf("restart %s: %v", name, err))
+			a.logger.Error("failed to restart server", "server", name, "error", err)
+
+			continue
+		}
+
+		a.addServerToolsToMCPServer(name)
+		a.logger.Info("restarted server", "server", name)
+	}
+
+	a.calls.resume(changed)
+
+	a.publish(events.ReloadApplied, "", fmt.Sprintf("environment changed, restarted %d", len(changed)), map[string]any{
+		"modified": changed,
+		"errors":   errs,
+	})
+
+	return changed, nil
+}
+
+// expandedEnv returns the env settings of a server as expanded by loader.
+// Only they count as a server's environment for ReloadEnv: every process
+// inherits all variables, so comparing whole environments would restart
+// every server on any change.
+func expandedEnv(loader *env.Loader, cfg *config.ServerConfig) map[string]string {
+	if loader == nil {
+		return env.ExpandEnvInMap(cfg.Env)
+	}
+
+	return loader.ExpandMap(cfg.Env)
+}
+
+// envFiles returns the .env files ReloadEnv reads for workDir: the global
+// .env and encrypted env files, and the encrypted, .env and .env.local files
+// of the .assern directory workDir is in. The files need not exist.
+func envFiles(workDir string) ([]string, error) {
+	globalDir, err := config.GlobalDir()
+	if err != nil {
+		return nil, fmt.Errorf("getting global config dir: %w", err)
+	}
+
+	files := []string{filepath.Join(globalDir, config.GlobalEnvFile)}
+
+	for _, name := range env.EncryptedEnvFiles {
+		files = append(files, filepath.Join(globalDir, name))
+	}
+
+	if localDir := config.FindLocalConfigDir(workDir); localDir != "" {
+		for _, name := range slices.Concat(env.EncryptedEnvFiles, env.ProjectEnvFiles) {
+			files = append(files, filepath.Join(localDir, name))
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,90 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestReloadEnv(t *testing.T) {
+	t.Parallel()
+
+	loaderWith := func(token string) *env.Loader {
+		loader := env.NewLoader()
+		loader.Set("global", "GITHUB_TOKEN", token)
+
+		return loader
+	}
+
+	agg, err := New(Options{
+		Config:    config.NewConfig(),
+		Logger:    slog.New(slog.DiscardHandler),
+		EnvLoader: loaderWith("old"),
+		LoadEnv:   func(context.Context) *env.Loader { return loaderWith("rotated") },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	servers := map[string]*config.ServerConfig{
+		"github": {Command: "github-mcp", Env: map[string]string{"TOKEN": "${GITHUB_TOKEN}"}},
+		"fs":     {Command: "fs-mcp", Env: map[string]string{"ROOT": "/srv"}},
+		"api": {
+			URL:     "https://api.example.com/mcp",
+			Headers: map[string]string{"Authorization": "Bearer ${GITHUB_TOKEN}"},
+		},
+	}
+
+	for name, cfg := range servers {
+		mock := testutil.NewMockServer(name, []mcp.Tool{mcp.NewTool("search")})
+		mock.ServerCfg = cfg
+
+		if err := mock.Start(t.Context()); err != nil {
+			t.Fatalf("mock.Start: %v", err)
+		}
+
+		if err := agg.AddServer(t.Context(), mock); err != nil {
+			t.Fatalf("AddServer: %v", err)
+		}
+	}
+
+	restarted, err := agg.ReloadEnv(t.Context())
+	if err != nil {
+		t.Fatalf("ReloadEnv: %v", err)
+	}
+
+	// fs does not use the rotated variable and keeps running
+	if !slices.Equal(restarted, []string{"api", "github"}) {
+		t.Errorf("restarted = %v, want [api github]", restarted)
+	}
+
+	if _, ok := agg.tools.Get("fs_search"); !ok {
+		t.Error("fs tools were removed")
+	}
+
+	restarted, err = agg.ReloadEnv(t.Context())
+	if err != nil || len(restarted) != 0 {
+		t.Errorf("ReloadEnv without changes = %v, %v; want nothing restarted", restarted, err)
+	}
+}
+
+func TestReloadEnvWithoutLoadEnv(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := agg.ReloadEnv(t.Context()); !errors.Is(err, ErrConfigNotReloadable) {
+		t.Errorf("ReloadEnv() error = %v, want ErrConfigNotReloadable", err)
+	}
+}
@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -30,11 +31,15 @@ var (
 	// ErrToolNotAllowed indicates a tool is excluded by code_mode.allowed_tools.
 	ErrToolNotAllowed = errors.New("tool not allowed in code mode")
 
+	// ErrAccessDenied indicates the ACL token of an HTTP client does not
+	// allow the tool (see settings.acl).
+	ErrAccessDenied = errors.New("tool not allowed for this token")
+
 	// ErrAllServersFailed indicates every configured server failed to start.
 	ErrAllServersFailed = errors.New("all servers failed to start")
 
 	// ErrInvalidTransport indicates the server has no valid transport configuration.
-	ErrInvalidTransport = errors.New("server must have either command (stdio) or url (http/sse)")
+	ErrInvalidTransport = errors.New("server must have a command (stdio), url (http/sse) or image (container)")
 
 	// ErrOAuthRequired indicates OAuth configuration is missing for an OAuth transport.
 	ErrOAuthRequired = errors.New("OAuth configuration required")
@@ -42,8 +47,47 @@ var (
 	// ErrInvalidPrefixedName indicates a prefixed name format is invalid.
 	ErrInvalidPrefixedName = errors.New("invalid prefixed name format")
 
+	// ErrConfigNotReloadable indicates the configuration was not read from
+	// files (e.g. --config-stdin), so there is nothing to reload.
+	ErrConfigNotReloadable = errors.New("configuration was not read from files and cannot be reloaded")
+
+	// ErrServerDraining indicates a server is being restarted by a reload
+	// and refuses new calls until it is back; the call can be retried.
+	ErrServerDraining = errors.New("server is restarting after a configuration change, retry shortly")
+
+	// ErrServerBusy indicates a server already has max_concurrency calls in
+	// flight and does not queue more; the call can be retried.
+	ErrServerBusy = errors.New("server is busy with other calls, retry shortly")
+
+	// ErrServerMaintenance indicates a server is in one of its scheduled
+	// maintenance windows; the call can be retried once the window ends.
+	ErrServerMaintenance = errors.New("server is in a scheduled maintenance window")
+
+	// ErrBlueGreenAborted indicates a blue-green reload left the running
+	// servers and configuration in place because a new server failed to start.
+	ErrBlueGreenAborted = errors.New("blue-green reload aborted, running servers kept")
+
+	// ErrToolNameCollision indicates two servers expose a tool under the same
+	// name and prefix_collision is "error".
+	ErrToolNameCollision = errors.New("tool name collision")
+
+	// ErrResourceTooLarge indicates a resource exceeds
+	// settings.max_resource_size.
+	ErrResourceTooLarge = errors.New("resource exceeds max_resource_size")
+
+	// ErrBlobNotFound indicates a referenced tool result blob is unknown or
+	// was dropped from the in-memory store.
+	ErrBlobNotFound = errors.New("blob not found")
+
 	// ErrInvalidPrefixedURI indicates a prefixed URI format is invalid.
 	ErrInvalidPrefixedURI = errors.New("invalid prefixed URI format")
+
+	// ErrUnknownFeature indicates a feature flag name is not known.
+	ErrUnknownFeature = errors.New("unknown feature")
+
+	// ErrFeatureNeedsRestart indicates a feature flag cannot be flipped on a
+	// running instance.
+	ErrFeatureNeedsRestart = errors.New("feature can only be changed in config (takes effect on restart)")
 )
 
 // CommandNotFoundError is returned when a configured command cannot be found.
@@ -105,3 +149,24 @@ func (e *InitializationError) Error() string {
 }
 
 func (e *InitializationError) Unwrap() error { return e.Underlying }
+
+// CallTimeoutError is returned when a tool call exceeds its server's
+// call_timeout: it went Idle without a response or a progress notification,
+// or, reporting progress, ran past Max. It matches context.DeadlineExceeded,
+// so it is not retried.
+type CallTimeoutError struct {
+	ServerName string
+	Tool       string
+	After      time.Duration
+	Idle       bool // Else the call reached the maximum duration
+}
+
+func (e *CallTimeoutError) Error() string {
+	if e.Idle {
+		return fmt.Sprintf("server %s: tool %s timed out after %v without a response or progress", e.ServerName, e.Tool, e.After)
+	}
+
+	return fmt.Sprintf("server %s: tool %s exceeded the maximum call duration of %v", e.ServerName, e.Tool, e.After)
+}
+
+func (e *CallTimeoutError) Unwrap() error { return context.DeadlineExceeded }
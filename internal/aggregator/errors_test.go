@@ -49,7 +49,7 @@ func TestSentinelErrors(t *testing.T) {
 		{
 			name: "ErrInvalidTransport",
 			err:  ErrInvalidTransport,
-			want: "server must have either command (stdio) or url (http/sse)",
+			want: "server must have a command (stdio), url (http/sse) or image (container)",
 		},
 		{
 			name: "ErrOAuthRequired",
@@ -0,0 +1,62 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// Events returns the event bus the aggregator publishes to (nil if none).
+func (a *Aggregator) Events() *events.Bus {
+	return a.events
+}
+
+// publish sends an event to the bus, if one is configured.
+func (a *Aggregator) publish(typ events.Type, server, message string, data map[string]any) {
+	a.events.Publish(events.Event{
+		Type:    typ,
+		Server:  server,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// recordFailure records a failed backend operation (op, see
+// serverRuntime.failed) and returns the error to report. Authorization failures move the server to needs_auth and come back
+// as *AuthRequiredError (see markNeedsAuth); other failures publish
+// server_failed when they turn the server unhealthy and are returned as is.
+func (a *Aggregator) recordFailure(ctx context.Context, server, op string, err error) error {
+	a.runtime.failed(server, op, err)
+
+	if isAuthError(err) {
+		var cfg *config.ServerConfig
+
+		a.mu.RLock()
+		if srv, ok := a.servers[server]; ok {
+			cfg = srv.Config()
+		}
+		a.mu.RUnlock()
+
+		return a.markNeedsAuth(ctx, server, cfg, err)
+	}
+
+	if a.health.RecordFailure(server) {
+		stats := a.health.Stats(server)
+		a.publish(events.ServerFailed, server, fmt.Sprintf("marked unhealthy: %v", err), map[string]any{
+			"consecutive_failures": stats.ConsecutiveFailures,
+		})
+		a.updateToolVisibility(server)
+	}
+
+	return err
+}
+
+// isAuthError reports whether err means the server's credentials were
+// rejected or its OAuth token is missing or expired.
+func isAuthError(err error) bool {
+	return client.IsOAuthAuthorizationRequiredError(err) || client.IsAuthorizationRequiredError(err)
+}
@@ -0,0 +1,138 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// eventRecorder is an events.Sink that keeps every event it receives.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (r *eventRecorder) Send(_ context.Context, e events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+
+	return nil
+}
+
+// eventChannel is an events.Sink passing each event on, so tests can wait
+// for one to be published.
+type eventChannel chan events.Event
+
+func (c eventChannel) Send(ctx context.Context, e events.Event) error {
+	select {
+	case c <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait returns the first event of type typ with the given message.
+func (c eventChannel) wait(t *testing.T, typ events.Type, message string) events.Event {
+	t.Helper()
+
+	for {
+		select {
+		case e := <-c:
+			if e.Type == typ && e.Message == message {
+				return e
+			}
+		case <-t.Context().Done():
+			t.Fatalf("%s %q not published", typ, message)
+		}
+	}
+}
+
+func TestToolFailurePublishesServerFailed(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	bus := events.New(slog.New(slog.DiscardHandler), rec)
+
+	agg, err := New(Options{
+		Config: config.NewConfig(),
+		Logger: slog.New(slog.DiscardHandler),
+		Events: bus,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_search")
+	handler := agg.createToolHandler(entry)
+	mock.CallErr = errors.New("boom")
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{}
+
+	// Failures past the threshold publish only once, on the transition.
+	for range DefaultHealthThreshold + 2 {
+		if _, err := handler(t.Context(), req); err != nil {
+			t.Fatalf("handler: %v", err)
+		}
+	}
+
+	bus.Close()
+
+	var failed []events.Event
+	for _, e := range rec.events {
+		if e.Type == events.ServerFailed {
+			failed = append(failed, e)
+		}
+	}
+
+	if len(failed) != 1 {
+		t.Fatalf("server_failed events = %d, want 1", len(failed))
+	}
+
+	if failed[0].Server != "github" {
+		t.Errorf("Server = %q, want github", failed[0].Server)
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"oauth required", fmt.Errorf("call: %w", &client.OAuthAuthorizationRequiredError{}), true},
+		{"unauthorized", fmt.Errorf("call: %w", &client.AuthorizationRequiredError{}), true},
+		{"other", errors.New("connection refused"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := isAuthError(tt.err); got != tt.want {
+			t.Errorf("%s: isAuthError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Meta-tool names exposed under settings.tool_exposure: search, in place
+// of the aggregated tools.
+const (
+	ToolSearchToolsName = "assern_search_tools"
+	ToolDescribeName    = "assern_describe_tool"
+	ToolCallName        = "assern_call_tool"
+)
+
+// SearchExposure reports whether tools/list holds only the meta-tools that
+// search, describe and call the aggregated tools (settings.tool_exposure:
+// search). It takes precedence over discovery.
+func (a *Aggregator) SearchExposure() bool {
+	return a.toolExposure() == config.ToolExposureSearch && a.FeatureEnabled(config.FeatureMetaTools)
+}
+
+// toolExposure returns settings.tool_exposure. It reads a.cfg under cfgMu
+// because Reload may swap a.cfg on another goroutine.
+func (a *Aggregator) toolExposure() string {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return ""
+	}
+
+	return a.cfg.Settings.ToolExposure
+}
+
+// registerSearchExposureTools adds the meta-tools of tool_exposure: search
+// to the MCP server.
+func (a *Aggregator) registerSearchExposureTools() {
+	a.mcpServer.AddTool(mcp.NewTool(
+		ToolSearchToolsName,
+		mcp.WithDescription("Search the available tools by keyword. Returns matching tool names with descriptions. Get a tool's input schema with assern_describe_tool, then call it with assern_call_tool."),
+		mcp.WithString("query", mcp.Description("Free-text search over tool names, descriptions, and server names. An empty query lists every tool.")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of results to return.")),
+	), a.handleSearchTools)
+
+	a.mcpServer.AddTool(mcp.NewTool(
+		ToolDescribeName,
+		mcp.WithDescription("Describe a tool found with assern_search_tools: its description and the JSON Schema of the arguments assern_call_tool takes for it."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Tool name from assern_search_tools, e.g. github_search_repos.")),
+	), a.handleDescribeTool)
+
+	a.mcpServer.AddTool(mcp.NewTool(
+		ToolCallName,
+		mcp.WithDescription("Call a tool found with assern_search_tools, with arguments matching the input schema assern_describe_tool returns. Returns the tool's own result."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Tool name from assern_search_tools, e.g. github_search_repos.")),
+		mcp.WithObject("arguments", mcp.Description("Arguments of the tool.")),
+	), a.handleCallTool)
+}
+
+// handleSearchTools implements the assern_search_tools meta-tool.
+func (a *Aggregator) handleSearchTools(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	results := a.searchCatalog(ctx, req)
+
+	return jsonResult(map[string]any{
+		"matches": results,
+		"count":   len(results),
+		"hint":    "Call assern_describe_tool for a tool's arguments, then assern_call_tool to run it.",
+	}), nil
+}
+
+// handleDescribeTool implements the assern_describe_tool meta-tool. Tools
+// the caller may not call are reported as not found.
+func (a *Aggregator) handleDescribeTool(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'name' argument: %v", err)), nil
+	}
+
+	entry, ok := a.tools.Get(name)
+	if !ok || !a.aclAllows(ctx, entry) || !a.clientAllows(ctx, entry) {
+		return notFoundResult(name), nil
+	}
+
+	return jsonResult(map[string]any{
+		"server": entry.ServerName,
+		"tool":   entry.ExposedTool(),
+	}), nil
+}
+
+// handleCallTool implements the assern_call_tool meta-tool. The call takes
+// the path of a direct call to the tool, so ACL, client rules, middleware
+// and result adaptation apply as usual.
+func (a *Aggregator) handleCallTool(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'name' argument: %v", err)), nil
+	}
+
+	args, ok := req.GetArguments()["arguments"].(map[string]any)
+	if !ok && req.GetArguments()["arguments"] != nil {
+		return mcp.NewToolResultError("invalid 'arguments' argument: want an object"), nil
+	}
+
+	entry, ok := a.tools.Get(name)
+	if !ok {
+		return notFoundResult(name), nil
+	}
+
+	call := req
+	call.Params.Name = entry.PrefixedName
+	call.Params.Arguments = args
+
+	return a.createToolHandler(entry)(ctx, call)
+}
+
+// notFoundResult reports a tool name the search meta-tools do not know.
+func notFoundResult(name string) *mcp.CallToolResult {
+	return mcp.NewToolResultError(fmt.Sprintf("%v: %s (find tools with %s)", ErrToolNotFound, name, ToolSearchToolsName))
+}
@@ -0,0 +1,108 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestSearchExposure(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.ToolExposure = config.ToolExposureSearch
+	cfg.Settings.Discovery = &config.DiscoveryConfig{Enabled: true}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	backend := testutil.NewMockServer("github", []mcp.Tool{
+		mcp.NewTool("search_repos", mcp.WithDescription("Search repositories"), mcp.WithString("query", mcp.Required())),
+		mcp.NewTool("create_issue", mcp.WithDescription("Open an issue")),
+	})
+	if err := agg.AddServer(t.Context(), backend); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	if agg.DiscoveryEnabled() {
+		t.Error("DiscoveryEnabled() = true, want search exposure to take precedence")
+	}
+
+	mcpServer := agg.CreateMCPServer()
+
+	sess := newFakeSession("search-1")
+	registerSession(t, mcpServer, sess)
+
+	names := listToolNames(t, mcpServer, sess)
+	slices.Sort(names)
+
+	if want := []string{ToolCallName, ToolDescribeName, ToolSearchToolsName, ToolStatusName}; !slices.Equal(names, want) {
+		t.Errorf("tools/list = %v, want %v", names, want)
+	}
+
+	call := func(tool string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+
+		raw, err := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": tool, "arguments": args},
+		})
+		if err != nil {
+			t.Fatalf("marshal tools/call: %v", err)
+		}
+
+		resp, ok := mcpServer.HandleMessage(mcpServer.WithContext(t.Context(), sess), raw).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("tools/call %s did not succeed", tool)
+		}
+
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("tools/call %s result = %T", tool, resp.Result)
+		}
+
+		return result
+	}
+
+	if text := textContent(t, call(ToolSearchToolsName, map[string]any{"query": "repositories"})); !strings.Contains(text, `"github_search_repos"`) {
+		t.Errorf("%s result = %s, want github_search_repos", ToolSearchToolsName, text)
+	}
+
+	if text := textContent(t, call(ToolDescribeName, map[string]any{"name": "github_search_repos"})); !strings.Contains(text, `"inputSchema"`) || !strings.Contains(text, `"query"`) {
+		t.Errorf("%s result = %s, want the input schema", ToolDescribeName, text)
+	}
+
+	result := call(ToolCallName, map[string]any{"name": "github_search_repos", "arguments": map[string]any{"query": "assern"}})
+	if text := textContent(t, result); result.IsError || text != "mock result for search_repos" {
+		t.Errorf("%s result = %q (error %v), want the backend result", ToolCallName, text, result.IsError)
+	}
+
+	calls := backend.GetToolCalls()
+	if len(calls) != 1 || calls[0].Name != "search_repos" || calls[0].Args["query"] != "assern" {
+		t.Errorf("backend calls = %+v, want search_repos with query assern", calls)
+	}
+
+	for _, tt := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{ToolDescribeName, map[string]any{"name": "github_missing"}},
+		{ToolCallName, map[string]any{"name": "github_missing"}},
+		{ToolCallName, map[string]any{"name": "github_search_repos", "arguments": "query=assern"}},
+	} {
+		if result := call(tt.tool, tt.args); !result.IsError {
+			t.Errorf("%s(%v) succeeded, want an error", tt.tool, tt.args)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ConfigErrorURI is the resource describing why the configuration failed to
+// load. It is only exposed in failsafe mode.
+const ConfigErrorURI = "assern://config/error"
+
+// ConfigError returns the error that put the aggregator in failsafe mode, or
+// nil when the configuration loaded. In failsafe mode no backend servers run;
+// only the built-in tools and the ConfigErrorURI resource are exposed until a
+// reload loads the configuration.
+func (a *Aggregator) ConfigError() error {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	return a.configErr
+}
+
+// setConfigError records the latest configuration load error while in
+// failsafe mode; a nil err leaves failsafe mode. It reports whether the
+// aggregator was in failsafe mode before.
+func (a *Aggregator) setConfigError(err error) bool {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+
+	was := a.configErr != nil
+	if was || err == nil {
+		a.configErr = err
+	}
+
+	return was
+}
+
+// leaveFailsafe removes the config error resource once the configuration
+// loads.
+func (a *Aggregator) leaveFailsafe() {
+	a.logger.Info("configuration loaded; leaving failsafe mode")
+
+	if a.mcpServer != nil {
+		a.mcpServer.DeleteResources(ConfigErrorURI)
+	}
+}
+
+// registerConfigErrorResource exposes ConfigErrorURI.
+func (a *Aggregator) registerConfigErrorResource() {
+	a.mcpServer.AddResource(mcp.NewResource(
+		ConfigErrorURI,
+		"Configuration error",
+		mcp.WithResourceDescription("Why assern started without its configured servers, and how to fix it"),
+		mcp.WithMIMEType("text/plain"),
+	), a.handleConfigErrorResource)
+}
+
+func (a *Aggregator) handleConfigErrorResource(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	text := "The configuration loaded; assern is no longer in failsafe mode."
+	if err := a.ConfigError(); err != nil {
+		text = failsafeMessage(err)
+	}
+
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: "text/plain",
+		Text:     text,
+	}}, nil
+}
+
+// failsafeMessage explains failsafe mode to users and agents.
+func failsafeMessage(err error) string {
+	return fmt.Sprintf("assern is running in failsafe mode: the configuration failed to load, "+
+		"so no backend servers were started.\n\nError: %v\n\n"+
+		"Fix the file named in the error (e.g. with 'assern config edit'), then run "+
+		"'assern reload' or send SIGHUP to the assern process; the servers start "+
+		"without restarting the editor session.", err)
+}
@@ -0,0 +1,124 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestFailsafeMode(t *testing.T) {
+	home := t.TempDir()
+	globalDir := filepath.Join(home, ".valksor", "assern")
+
+	if err := os.MkdirAll(globalDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	mcpPath := filepath.Join(globalDir, "mcp.json")
+	if err := os.WriteFile(mcpPath, []byte(`{"mcpServers": {`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", home)
+
+	agg, err := New(Options{
+		Config:      config.NewConfig(),
+		Logger:      slog.New(slog.DiscardHandler),
+		WorkDir:     home,
+		ConfigError: errors.New("loading global mcp config: unexpected end of JSON input"),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	agg.CreateMCPServer()
+
+	readError := func() string {
+		t.Helper()
+
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = ConfigErrorURI
+
+		contents, err := agg.handleConfigErrorResource(t.Context(), req)
+		if err != nil || len(contents) != 1 {
+			t.Fatalf("reading %s = %v, %v", ConfigErrorURI, contents, err)
+		}
+
+		text, _ := contents[0].(mcp.TextResourceContents)
+
+		return text.Text
+	}
+
+	if text := readError(); !strings.Contains(text, "failsafe mode") || !strings.Contains(text, "unexpected end of JSON input") {
+		t.Errorf("config error resource = %q, want the failsafe notice and load error", text)
+	}
+
+	if got := agg.Status().ConfigError; got == "" {
+		t.Error("Status().ConfigError is empty in failsafe mode")
+	}
+
+	if got := agg.instructions(agg.Identity()); !strings.Contains(got, "failsafe mode") {
+		t.Errorf("instructions = %q, want the failsafe notice", got)
+	}
+
+	// A reload that still fails keeps failsafe mode with the latest error
+	if _, err := agg.Reload(t.Context()); err == nil {
+		t.Fatal("Reload with broken config succeeded")
+	}
+
+	if err := agg.ConfigError(); err == nil || !strings.Contains(err.Error(), "global mcp config") {
+		t.Errorf("ConfigError() after failed reload = %v", err)
+	}
+
+	if err := os.WriteFile(mcpPath, []byte(`{"mcpServers": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := agg.Reload(t.Context()); err != nil {
+		t.Fatalf("Reload with fixed config: %v", err)
+	}
+
+	if err := agg.ConfigError(); err != nil {
+		t.Errorf("ConfigError() after successful reload = %v, want nil", err)
+	}
+
+	if got := agg.Status().ConfigError; got != "" {
+		t.Errorf("Status().ConfigError after reload = %q, want empty", got)
+	}
+}
+
+func TestReloadFailureOutsideFailsafe(t *testing.T) {
+	home := t.TempDir()
+	globalDir := filepath.Join(home, ".valksor", "assern")
+
+	if err := os.MkdirAll(globalDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(globalDir, "mcp.json"), []byte(`{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", home)
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), WorkDir: home})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := agg.Reload(t.Context()); err == nil {
+		t.Fatal("Reload with broken config succeeded")
+	}
+
+	// A running instance keeps its servers and does not enter failsafe mode
+	if err := agg.ConfigError(); err != nil {
+		t.Errorf("ConfigError() = %v, want nil", err)
+	}
+}
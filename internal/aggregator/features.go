@@ -0,0 +1,121 @@
+package aggregator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Where the state of a feature flag comes from.
+const (
+	FeatureSourceDefault = "default"
+	FeatureSourceConfig  = "config"
+	FeatureSourceRuntime = "runtime"
+)
+
+// FeatureState is the state of one feature flag on a running instance.
+type FeatureState struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Default     bool   `json:"default"`
+	Source      string `json:"source"`
+	// Restart is set for flags that can only be changed in config.
+	Restart bool `json:"restart,omitempty"`
+}
+
+// featureOverrides holds feature flags flipped at runtime. They take
+// precedence over the config and survive reloads, but not restarts.
+type featureOverrides struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+func newFeatureOverrides() *featureOverrides {
+	return &featureOverrides{flags: make(map[string]bool)}
+}
+
+// get returns the runtime state of a flag. Safe on a nil set.
+func (f *featureOverrides) get(name string) (enabled, ok bool) {
+	if f == nil {
+		return false, false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	enabled, ok = f.flags[name]
+
+	return enabled, ok
+}
+
+func (f *featureOverrides) set(name string, enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.flags[name] = enabled
+}
+
+// FeatureEnabled reports whether a feature flag is on, taking runtime
+// overrides into account.
+func (a *Aggregator) FeatureEnabled(name string) bool {
+	return a.featureState(config.Feature{Name: name}).Enabled
+}
+
+// Features returns the state of every known feature flag.
+func (a *Aggregator) Features() []FeatureState {
+	states := make([]FeatureState, 0, len(config.Features))
+	for _, f := range config.Features {
+		states = append(states, a.featureState(f))
+	}
+
+	return states
+}
+
+// SetFeature flips a feature flag on the running instance. The change lasts
+// until the process exits.
+func (a *Aggregator) SetFeature(name string, enabled bool) (FeatureState, error) {
+	f, ok := config.LookupFeature(name)
+	if !ok {
+		return FeatureState{}, fmt.Errorf("%w: %s", ErrUnknownFeature, name)
+	}
+
+	if f.Restart {
+		return FeatureState{}, fmt.Errorf("%s: %w", name, ErrFeatureNeedsRestart)
+	}
+
+	a.features.set(name, enabled)
+	a.logger.Info("feature flag changed", "feature", name, "enabled", enabled)
+
+	return a.featureState(f), nil
+}
+
+func (a *Aggregator) featureState(f config.Feature) FeatureState {
+	if known, ok := config.LookupFeature(f.Name); ok {
+		f = known
+	}
+
+	state := FeatureState{
+		Name:        f.Name,
+		Description: f.Description,
+		Enabled:     f.Default,
+		Default:     f.Default,
+		Source:      FeatureSourceDefault,
+		Restart:     f.Restart,
+	}
+
+	a.cfgMu.RLock()
+	if a.cfg != nil && a.cfg.Settings != nil {
+		if enabled, ok := a.cfg.Settings.Features[f.Name]; ok {
+			state.Enabled, state.Source = enabled, FeatureSourceConfig
+		}
+	}
+	a.cfgMu.RUnlock()
+
+	if enabled, ok := a.features.get(f.Name); ok {
+		state.Enabled, state.Source = enabled, FeatureSourceRuntime
+	}
+
+	return state
+}
@@ -0,0 +1,98 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestFeatureState(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings = &config.Settings{Features: map[string]bool{config.FeatureLazyStart: true}}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		feature     string
+		set         *bool
+		wantEnabled bool
+		wantSource  string
+	}{
+		{"default", config.FeatureMetaTools, nil, true, FeatureSourceDefault},
+		{"config", config.FeatureLazyStart, nil, true, FeatureSourceConfig},
+		{"runtime over config", config.FeatureLazyStart, new(false), false, FeatureSourceRuntime},
+		{"runtime over default", config.FeatureTOONDefault, new(true), true, FeatureSourceRuntime},
+	}
+
+	for _, tt := range tests {
+		if tt.set != nil {
+			if _, err := agg.SetFeature(tt.feature, *tt.set); err != nil {
+				t.Fatalf("%s: SetFeature() error = %v", tt.name, err)
+			}
+		}
+
+		state := agg.featureState(config.Feature{Name: tt.feature})
+		if state.Enabled != tt.wantEnabled || state.Source != tt.wantSource {
+			t.Errorf("%s: state = %+v, want enabled=%v source=%s", tt.name, state, tt.wantEnabled, tt.wantSource)
+		}
+	}
+
+	if _, err := agg.SetFeature("warp_drive", true); !errors.Is(err, ErrUnknownFeature) {
+		t.Errorf("SetFeature(unknown) error = %v, want ErrUnknownFeature", err)
+	}
+
+	if _, err := agg.SetFeature(config.FeatureMetaTools, false); !errors.Is(err, ErrFeatureNeedsRestart) {
+		t.Errorf("SetFeature(meta_tools) error = %v, want ErrFeatureNeedsRestart", err)
+	}
+}
+
+func TestResultFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"json", "toon"} {
+		agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), OutputFormat: format})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+
+		if got := agg.resultFormat(); got != format {
+			t.Errorf("resultFormat() = %q, want %q", got, format)
+		}
+
+		if _, err := agg.SetFeature(config.FeatureTOONDefault, true); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := agg.resultFormat(); got != "toon" {
+			t.Errorf("resultFormat() with toon_default = %q, want toon", got)
+		}
+	}
+}
+
+func TestMetaToolsFeatureGatesDiscovery(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings = &config.Settings{
+		Discovery: &config.DiscoveryConfig{Enabled: true},
+		CodeMode:  &config.CodeModeConfig{Enabled: true},
+		Features:  map[string]bool{config.FeatureMetaTools: false},
+	}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if agg.DiscoveryEnabled() || agg.CodeModeEnabled() {
+		t.Error("meta-tools active with meta_tools feature off")
+	}
+}
@@ -0,0 +1,70 @@
+package aggregator
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// withForwardedHeaders returns the context and arguments of a tool call
+// with the forward_headers of cfg taken from header, the HTTP request the
+// call came in on (nil over stdio and the socket). A forwarded value
+// replaces an argument of the same name the client passed, so clients
+// cannot pose as another user; headers the request lacks are skipped.
+// args is not modified.
+func withForwardedHeaders(
+	ctx context.Context,
+	cfg *config.ServerConfig,
+	header http.Header,
+	args map[string]any,
+) (context.Context, map[string]any) {
+	if len(header) == 0 {
+		return ctx, args
+	}
+
+	var (
+		meta   map[string]any
+		cloned bool
+	)
+
+	forwarded := args
+
+	for _, fh := range cfg.ForwardedHeaders() {
+		values := header.Values(fh.Header)
+		if len(values) == 0 {
+			continue
+		}
+
+		value := strings.Join(values, ", ")
+
+		if fh.Meta {
+			if meta == nil {
+				meta = make(map[string]any)
+			}
+
+			meta[fh.Name] = value
+
+			continue
+		}
+
+		if !cloned {
+			forwarded = maps.Clone(args)
+			if forwarded == nil {
+				forwarded = make(map[string]any, 1)
+			}
+
+			cloned = true
+		}
+
+		forwarded[fh.Name] = value
+	}
+
+	if meta != nil {
+		ctx = withCallMeta(ctx, meta)
+	}
+
+	return ctx, forwarded
+}
@@ -0,0 +1,141 @@
+package aggregator
+
+import (
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestWithForwardedHeaders(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ServerConfig{ForwardHeaders: map[string]string{
+		"X-User": "_meta.user",
+		"X-Team": "team",
+	}}
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		args     map[string]any
+		wantArgs map[string]any
+		wantMeta map[string]any
+	}{
+		{
+			name:     "no HTTP request",
+			args:     map[string]any{"q": "go"},
+			wantArgs: map[string]any{"q": "go"},
+		},
+		{
+			name:     "argument and meta",
+			header:   http.Header{"X-User": {"alice"}, "X-Team": {"platform"}},
+			args:     map[string]any{"q": "go"},
+			wantArgs: map[string]any{"q": "go", "team": "platform"},
+			wantMeta: map[string]any{"user": "alice"},
+		},
+		{
+			name:     "replaces the client's argument",
+			header:   http.Header{"X-Team": {"platform"}},
+			args:     map[string]any{"team": "finance"},
+			wantArgs: map[string]any{"team": "platform"},
+		},
+		{
+			name:     "missing header",
+			header:   http.Header{"X-Other": {"x"}},
+			args:     nil,
+			wantArgs: nil,
+		},
+		{
+			name:     "repeated header",
+			header:   http.Header{"X-Team": {"a", "b"}},
+			wantArgs: map[string]any{"team": "a, b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var before int
+			if tt.args != nil {
+				before = len(tt.args)
+			}
+
+			ctx, got := withForwardedHeaders(t.Context(), cfg, tt.header, tt.args)
+
+			if !mapsMatch(got, tt.wantArgs) {
+				t.Errorf("arguments = %v, want %v", got, tt.wantArgs)
+			}
+
+			if meta := callMeta(ctx); !mapsMatch(meta, tt.wantMeta) {
+				t.Errorf("meta = %v, want %v", meta, tt.wantMeta)
+			}
+
+			if len(tt.args) != before {
+				t.Error("the caller's arguments were modified")
+			}
+		})
+	}
+}
+
+// mapsMatch compares maps of string values.
+func mapsMatch(got, want map[string]any) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestWithCallMetaMerges(t *testing.T) {
+	t.Parallel()
+
+	ctx := withCallMeta(t.Context(), map[string]any{"user": "alice"})
+	ctx = withCallMeta(ctx, map[string]any{"idempotencyKey": "k1"})
+
+	if meta := callMeta(ctx); !mapsMatch(meta, map[string]any{"user": "alice", "idempotencyKey": "k1"}) {
+		t.Errorf("meta = %v, want both fields", meta)
+	}
+}
+
+func TestToolCallForwardsHeaders(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	mock.ServerCfg = &config.ServerConfig{ForwardHeaders: map[string]string{"X-User": "user"}}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_search")
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "github_search"
+	req.Params.Arguments = map[string]any{"q": "go"}
+	req.Header = http.Header{"X-User": {"alice"}}
+
+	if _, err := agg.createToolHandler(entry)(t.Context(), req); err != nil {
+		t.Fatalf("tool call: %v", err)
+	}
+
+	if len(mock.ToolCalls) != 1 || mock.ToolCalls[0].Args["user"] != "alice" || mock.ToolCalls[0].Args["q"] != "go" {
+		t.Errorf("backend calls = %+v, want user forwarded", mock.ToolCalls)
+	}
+}
@@ -3,6 +3,8 @@ package aggregator
 import (
 	"sync"
 	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
 )
 
 // HealthStatus represents the health state of a server.
@@ -13,16 +15,28 @@ const (
 	HealthHealthy HealthStatus = "healthy"
 	// HealthUnhealthy indicates the server has failed multiple consecutive requests.
 	HealthUnhealthy HealthStatus = "unhealthy"
+	// HealthDegraded indicates recent requests failed, but fewer in a row
+	// than the threshold for unhealthy.
+	HealthDegraded HealthStatus = "degraded"
 	// HealthUnknown indicates the server has not been tested yet.
 	HealthUnknown HealthStatus = "unknown"
+	// HealthNeedsAuth indicates the server rejected its OAuth credentials and
+	// must be (re)authorized before calls can succeed.
+	HealthNeedsAuth HealthStatus = "needs_auth"
 )
 
+// down reports whether the status means calls are expected to fail.
+func (s HealthStatus) down() bool {
+	return s == HealthUnhealthy || s == HealthNeedsAuth
+}
+
 // DefaultHealthThreshold is the number of consecutive failures before marking unhealthy.
 const DefaultHealthThreshold = 3
 
 // HealthTracker monitors server health based on call success/failure patterns.
 type HealthTracker struct {
 	threshold int
+	clock     clock.Clock
 	servers   map[string]*serverHealth
 	mu        sync.RWMutex
 }
@@ -35,17 +49,24 @@ type serverHealth struct {
 	lastSuccess         time.Time
 	totalCalls          int64
 	totalFailures       int64
+	authURL             string // Authorization URL while status is needs_auth
 }
 
 // NewHealthTracker creates a new health tracker with the specified failure threshold.
 // If threshold is <= 0, DefaultHealthThreshold is used.
 func NewHealthTracker(threshold int) *HealthTracker {
+	return newHealthTracker(threshold, clock.Real)
+}
+
+// newHealthTracker creates a health tracker timing failures with clk.
+func newHealthTracker(threshold int, clk clock.Clock) *HealthTracker {
 	if threshold <= 0 {
 		threshold = DefaultHealthThreshold
 	}
 
 	return &HealthTracker{
 		threshold: threshold,
+		clock:     clk,
 		servers:   make(map[string]*serverHealth),
 	}
 }
@@ -58,26 +79,81 @@ func (h *HealthTracker) RecordSuccess(serverName string) {
 
 	sh := h.getOrCreate(serverName)
 	sh.consecutiveFailures = 0
-	sh.lastSuccess = time.Now()
+	sh.lastSuccess = h.clock.Now()
 	sh.totalCalls++
 	sh.status = HealthHealthy
+	sh.authURL = ""
 }
 
 // RecordFailure records a failed call to a server.
-// If consecutive failures exceed the threshold, the server is marked unhealthy.
-func (h *HealthTracker) RecordFailure(serverName string) {
+// The server is marked degraded, and unhealthy once consecutive failures
+// reach the threshold. It returns true when this failure caused that
+// transition to unhealthy.
+func (h *HealthTracker) RecordFailure(serverName string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sh := h.getOrCreate(serverName)
+	sh.consecutiveFailures++
+	sh.lastFailure = h.clock.Now()
+	sh.totalCalls++
+	sh.totalFailures++
+
+	if sh.status.down() {
+		return false
+	}
+
+	if sh.consecutiveFailures < h.threshold {
+		sh.status = HealthDegraded
+
+		return false
+	}
+
+	sh.status = HealthUnhealthy
+
+	return true
+}
+
+// MarkNeedsAuth records a call rejected for missing or expired authorization
+// and puts the server in the needs_auth state, remembering the URL where the
+// user can authorize (may be empty). It returns true on the transition.
+func (h *HealthTracker) MarkNeedsAuth(serverName, authURL string) bool {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	sh := h.getOrCreate(serverName)
 	sh.consecutiveFailures++
-	sh.lastFailure = time.Now()
+	sh.lastFailure = h.clock.Now()
 	sh.totalCalls++
 	sh.totalFailures++
 
-	if sh.consecutiveFailures >= h.threshold {
-		sh.status = HealthUnhealthy
+	if authURL != "" {
+		sh.authURL = authURL
 	}
+
+	if sh.status == HealthNeedsAuth {
+		return false
+	}
+
+	sh.status = HealthNeedsAuth
+
+	return true
+}
+
+// NeedsAuth reports whether calls to a server should fail fast because it
+// needs authorization and was last tried less than retryAfter ago, along with
+// the authorization URL. Once retryAfter has passed one call goes through, so
+// a server authorized elsewhere recovers without a restart.
+func (h *HealthTracker) NeedsAuth(serverName string, retryAfter time.Duration) (string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sh, ok := h.servers[serverName]
+	if !ok || sh.status != HealthNeedsAuth {
+		return "", false
+	}
+
+	return sh.authURL, h.clock.Since(sh.lastFailure) < retryAfter
 }
 
 // Status returns the health status of a server.
@@ -93,11 +169,10 @@ func (h *HealthTracker) Status(serverName string) HealthStatus {
 	return sh.status
 }
 
-// IsHealthy returns true if the server is healthy or unknown (not yet unhealthy).
+// IsHealthy returns true if the server is healthy or unknown (not yet
+// unhealthy and not waiting for authorization).
 func (h *HealthTracker) IsHealthy(serverName string) bool {
-	status := h.Status(serverName)
-
-	return status != HealthUnhealthy
+	return !h.Status(serverName).down()
 }
 
 // Stats returns health statistics for a server.
@@ -117,6 +192,7 @@ func (h *HealthTracker) Stats(serverName string) HealthStats {
 		LastSuccess:         sh.lastSuccess,
 		TotalCalls:          sh.totalCalls,
 		TotalFailures:       sh.totalFailures,
+		AuthURL:             sh.authURL,
 	}
 }
 
@@ -134,6 +210,7 @@ func (h *HealthTracker) AllStats() map[string]HealthStats {
 			LastSuccess:         sh.lastSuccess,
 			TotalCalls:          sh.totalCalls,
 			TotalFailures:       sh.totalFailures,
+			AuthURL:             sh.authURL,
 		}
 	}
 
@@ -149,6 +226,7 @@ func (h *HealthTracker) MarkHealthy(serverName string) {
 	sh := h.getOrCreate(serverName)
 	sh.status = HealthHealthy
 	sh.consecutiveFailures = 0
+	sh.authURL = ""
 }
 
 // Reset removes all tracking data for a server.
@@ -187,6 +265,7 @@ type HealthStats struct {
 	LastSuccess         time.Time
 	TotalCalls          int64
 	TotalFailures       int64
+	AuthURL             string // Set while Status is HealthNeedsAuth
 }
 
 // FailureRate returns the failure rate as a percentage (0-100).
@@ -79,20 +79,22 @@ func TestHealthTracker_RecordFailure(t *testing.T) {
 
 	ht := NewHealthTracker(3)
 
-	// First failure - still unknown/healthy
+	// First failure - degraded
 	ht.RecordFailure("server1")
-	if ht.Status("server1") != HealthUnknown {
-		t.Errorf("Status() = %q, want %q after 1 failure", ht.Status("server1"), HealthUnknown)
+	if ht.Status("server1") != HealthDegraded {
+		t.Errorf("Status() = %q, want %q after 1 failure", ht.Status("server1"), HealthDegraded)
 	}
 
-	// Second failure - still unknown/healthy
+	// Second failure - still degraded
 	ht.RecordFailure("server1")
-	if ht.Status("server1") != HealthUnknown {
-		t.Errorf("Status() = %q, want %q after 2 failures", ht.Status("server1"), HealthUnknown)
+	if ht.Status("server1") != HealthDegraded {
+		t.Errorf("Status() = %q, want %q after 2 failures", ht.Status("server1"), HealthDegraded)
 	}
 
 	// Third failure - should be unhealthy
-	ht.RecordFailure("server1")
+	if !ht.RecordFailure("server1") {
+		t.Error("RecordFailure() = false, want true on transition to unhealthy")
+	}
 	if ht.Status("server1") != HealthUnhealthy {
 		t.Errorf("Status() = %q, want %q after 3 failures", ht.Status("server1"), HealthUnhealthy)
 	}
@@ -132,8 +134,8 @@ func TestHealthTracker_SuccessResetsFailures(t *testing.T) {
 	// Two more failures won't trigger unhealthy
 	ht.RecordFailure("server1")
 	ht.RecordFailure("server1")
-	if ht.Status("server1") != HealthHealthy {
-		t.Error("should still be healthy after 2 failures (threshold is 3)")
+	if ht.Status("server1") != HealthDegraded {
+		t.Error("should be degraded, not unhealthy, after 2 failures (threshold is 3)")
 	}
 }
 
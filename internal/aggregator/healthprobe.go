@@ -0,0 +1,248 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// errProbeToolError is returned when a probe tool call reports IsError.
+var errProbeToolError = errors.New("probe tool returned an error result")
+
+// healthCheck returns the health check of a server with cfg, or nil (see
+// config.EffectiveHealthCheck).
+func (a *Aggregator) healthCheck(cfg *config.ServerConfig) *config.HealthCheckConfig {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	var settings *config.Settings
+	if a.cfg != nil {
+		settings = a.cfg.Settings
+	}
+
+	return config.EffectiveHealthCheck(cfg, settings).Clone()
+}
+
+// startHealthProbe begins probing a server if its config, or the default
+// for URL-based servers, declares a health check.
+func (a *Aggregator) startHealthProbe(name string, cfg *config.ServerConfig) {
+	// A restarted server starts out visible
+	a.hidden.set(name, false)
+
+	hc := a.healthCheck(cfg)
+	if hc == nil {
+		return
+	}
+
+	a.probes.start(name, func(ctx context.Context) {
+		ticker := a.clock.NewTicker(hc.EffectiveInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				a.probeServer(ctx, name, hc)
+			}
+		}
+	})
+
+	a.logger.Debug("health probe scheduled", "server", name, "interval", hc.EffectiveInterval())
+}
+
+// probeServer runs one probe and records the outcome. With reconnect enabled,
+// a server that the probe tips into the unhealthy state is restarted; with
+// hide_tools, its tools leave tools/list while it is unhealthy.
+func (a *Aggregator) probeServer(ctx context.Context, name string, hc *config.HealthCheckConfig) {
+	a.mu.RLock()
+	srv, exists := a.servers[name]
+	a.mu.RUnlock()
+
+	// A server down for scheduled maintenance is expected to fail
+	if !exists || a.inMaintenance(name, srv.Config()) != nil {
+		return
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, hc.EffectiveTimeout())
+	err := runProbe(probeCtx, srv, hc)
+	cancel()
+
+	if err == nil {
+		a.health.MarkHealthy(name)
+		a.updateToolVisibility(name)
+
+		return
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	_ = a.recordFailure(ctx, name, opHealthCheck, err)
+	a.logger.Warn("health probe failed", "server", name, "error", err)
+
+	if hc.Reconnect && !a.health.IsHealthy(name) {
+		a.reconnectServer(ctx, name, srv)
+		a.updateToolVisibility(name)
+	}
+}
+
+// runProbe calls the configured probe tool, or pings or lists tools when
+// none is set.
+func runProbe(ctx context.Context, srv Server, hc *config.HealthCheckConfig) error {
+	if hc.Tool == "" {
+		if pinger, ok := srv.(PingServer); ok && hc.Ping {
+			return pinger.Ping(ctx)
+		}
+
+		_, err := srv.DiscoverTools(ctx)
+
+		return err
+	}
+
+	result, err := srv.CallTool(ctx, hc.Tool, hc.Arguments)
+	if err != nil {
+		return err
+	}
+
+	if result != nil && result.IsError {
+		return fmt.Errorf("%s: %w", hc.Tool, errProbeToolError)
+	}
+
+	return nil
+}
+
+// reconnectServer restarts an unhealthy server's connection in place. Its
+// registered tools are kept; health tracking starts over on success. The
+// restart gets the same timeout as the initial server start.
+func (a *Aggregator) reconnectServer(ctx context.Context, name string, srv Server) {
+	a.logger.Info("reconnecting unhealthy server", "server", name)
+	a.runtime.restarting(name)
+
+	if err := srv.Stop(); err != nil {
+		a.logger.Warn("error stopping server for reconnect", "server", name, "error", err)
+	}
+
+	startCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	start := a.clock.Now()
+	if err := srv.Start(startCtx); err != nil {
+		a.runtime.failed(name, opReconnect, err)
+		a.logger.Error("reconnect failed", "server", name, "error", err)
+
+		return
+	}
+
+	a.runtime.started(name, ServerTiming{Initialize: a.clock.Since(start)})
+	a.health.Reset(name)
+	a.logger.Info("server reconnected", "server", name)
+}
+
+// hiddenServers holds the servers whose tools are left out of tools/list
+// because they are unhealthy and their health check sets hide_tools.
+type hiddenServers struct {
+	mu    sync.RWMutex
+	names map[string]bool
+}
+
+func newHiddenServers() *hiddenServers {
+	return &hiddenServers{names: make(map[string]bool)}
+}
+
+// set hides or shows a server's tools, reporting whether that changed.
+// The methods accept a nil receiver, which hides nothing.
+func (h *hiddenServers) set(name string, hidden bool) bool {
+	if h == nil {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.names[name] == hidden {
+		return false
+	}
+
+	if hidden {
+		h.names[name] = true
+	} else {
+		delete(h.names, name)
+	}
+
+	return true
+}
+
+func (h *hiddenServers) has(name string) bool {
+	if h == nil {
+		return false
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.names[name]
+}
+
+func (h *hiddenServers) empty() bool {
+	if h == nil {
+		return true
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.names) == 0
+}
+
+// updateToolVisibility hides the tools of a server with hide_tools while it
+// is unhealthy and shows them again once it is not, notifying clients with
+// tools/list_changed on each change.
+func (a *Aggregator) updateToolVisibility(name string) {
+	a.mu.RLock()
+	srv, exists := a.servers[name]
+	a.mu.RUnlock()
+
+	hide := false
+
+	if exists {
+		if hc := a.healthCheck(srv.Config()); hc != nil && hc.HideTools {
+			hide = a.health.Status(name) == HealthUnhealthy
+		}
+	}
+
+	if !a.hidden.set(name, hide) {
+		return
+	}
+
+	if hide {
+		a.logger.Warn("hiding tools of unhealthy server", "server", name)
+	} else {
+		a.logger.Info("showing tools of recovered server", "server", name)
+	}
+
+	if a.mcpServer != nil {
+		a.mcpServer.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+	}
+}
+
+// hideDownTools is a tools/list filter that leaves out the tools of hidden
+// servers (see updateToolVisibility).
+func (a *Aggregator) hideDownTools(_ context.Context, tools []mcp.Tool) []mcp.Tool {
+	if a.hidden.empty() {
+		return tools
+	}
+
+	return slices.DeleteFunc(slices.Clone(tools), func(tool mcp.Tool) bool {
+		entry, ok := a.tools.Get(tool.Name)
+
+		return ok && a.hidden.has(entry.ServerName)
+	})
+}
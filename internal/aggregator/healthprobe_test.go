@@ -0,0 +1,281 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func newProbeAggregator(t *testing.T, mock *testutil.MockServer) *Aggregator {
+	t.Helper()
+
+	agg, err := New(Options{
+		Config: config.NewConfig(),
+		Logger: slog.New(slog.DiscardHandler),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	return agg
+}
+
+func TestProbeServer_ToolProbe(t *testing.T) {
+	t.Parallel()
+
+	mock := testutil.NewMockServer("db", []mcp.Tool{mcp.NewTool("ping")})
+	agg := newProbeAggregator(t, mock)
+
+	hc := &config.HealthCheckConfig{Tool: "ping", Arguments: map[string]any{"deep": true}}
+	agg.probeServer(t.Context(), "db", hc)
+
+	calls := mock.GetToolCalls()
+	if len(calls) != 1 || calls[0].Name != "ping" || calls[0].Args["deep"] != true {
+		t.Fatalf("probe tool calls = %+v, want one ping with args", calls)
+	}
+
+	if got := agg.health.Status("db"); got != HealthHealthy {
+		t.Errorf("status after successful probe = %s, want healthy", got)
+	}
+}
+
+func TestProbeServer_ErrorResultCountsAsFailure(t *testing.T) {
+	t.Parallel()
+
+	mock := testutil.NewMockServer("db", []mcp.Tool{mcp.NewTool("ping")})
+	mock.SetToolResult("ping", mcp.NewToolResultError("database unreachable"))
+	agg := newProbeAggregator(t, mock)
+
+	agg.probeServer(t.Context(), "db", &config.HealthCheckConfig{Tool: "ping"})
+
+	if got := agg.health.Stats("db").ConsecutiveFailures; got != 1 {
+		t.Errorf("consecutive failures = %d, want 1", got)
+	}
+}
+
+func TestProbeServer_ReconnectsWhenUnhealthy(t *testing.T) {
+	t.Parallel()
+
+	mock := testutil.NewMockServer("db", []mcp.Tool{mcp.NewTool("ping")})
+	mock.CallErr = errors.New("broken pipe")
+	agg := newProbeAggregator(t, mock)
+	agg.timeout = time.Second
+
+	hc := &config.HealthCheckConfig{Tool: "ping", Reconnect: true}
+
+	for range DefaultHealthThreshold - 1 {
+		agg.probeServer(t.Context(), "db", hc)
+	}
+
+	if got := agg.health.Status("db"); got == HealthUnhealthy {
+		t.Fatal("server marked unhealthy before reaching the threshold")
+	}
+
+	agg.probeServer(t.Context(), "db", hc)
+
+	// The reconnect resets health tracking and leaves the server started.
+	if got := agg.health.Status("db"); got != HealthUnknown {
+		t.Errorf("status after reconnect = %s, want unknown", got)
+	}
+
+	if !mock.IsStarted() {
+		t.Error("server should be started again after reconnect")
+	}
+}
+
+func TestProbeServer_ListToolsWhenNoTool(t *testing.T) {
+	t.Parallel()
+
+	mock := testutil.NewMockServer("fs", []mcp.Tool{mcp.NewTool("read")})
+	agg := newProbeAggregator(t, mock)
+
+	agg.probeServer(t.Context(), "fs", &config.HealthCheckConfig{})
+
+	if len(mock.GetToolCalls()) != 0 {
+		t.Error("list-tools probe should not call any tool")
+	}
+
+	if got := agg.health.Status("fs"); got != HealthHealthy {
+		t.Errorf("status = %s, want healthy", got)
+	}
+}
+
+// probedServer is a mock server reporting each tool call on calls.
+type probedServer struct {
+	*testutil.MockServer
+
+	calls chan string
+}
+
+func (p *probedServer) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	result, err := p.MockServer.CallTool(ctx, name, args)
+	p.calls <- name
+
+	return result, err
+}
+
+func TestStartHealthProbe_RunsOnInterval(t *testing.T) {
+	t.Parallel()
+
+	srv := &probedServer{MockServer: testutil.NewMockServer("db", []mcp.Tool{mcp.NewTool("ping")}), calls: make(chan string)}
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), Clock: clk})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), srv); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	agg.startHealthProbe("db", &config.ServerConfig{
+		Health: &config.HealthCheckConfig{Interval: time.Minute, Tool: "ping"},
+	})
+
+	clk.BlockUntil(1)
+
+	for i := range 2 {
+		clk.Advance(time.Minute)
+
+		select {
+		case <-srv.calls:
+		case <-time.After(time.Second):
+			t.Fatalf("probe %d did not run after its interval", i+1)
+		}
+	}
+
+	agg.probes.stopAll()
+	clk.Advance(time.Hour)
+
+	if n := clk.Waiters(); n != 0 {
+		t.Errorf("%d timers left after the probe stopped, want 0", n)
+	}
+
+	if got := agg.health.Status("db"); got != HealthHealthy {
+		t.Errorf("status = %s, want healthy", got)
+	}
+}
+
+func TestHealthProber_StopAllReusable(t *testing.T) {
+	t.Parallel()
+
+	p := newLoopGroup()
+	ran := make(chan struct{}, 2)
+
+	loop := func(ctx context.Context) {
+		ran <- struct{}{}
+		<-ctx.Done()
+	}
+
+	p.start("a", loop)
+	<-ran
+	p.stopAll()
+
+	p.start("a", loop)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("prober did not start a loop after stopAll")
+	}
+
+	p.stopAll()
+}
+
+// pingServer is a mock server that answers MCP pings.
+type pingServer struct {
+	*testutil.MockServer
+
+	pings   int
+	pingErr error
+}
+
+func (p *pingServer) Ping(context.Context) error {
+	p.pings++
+
+	return p.pingErr
+}
+
+func TestProbeServer_Ping(t *testing.T) {
+	t.Parallel()
+
+	srv := &pingServer{MockServer: testutil.NewMockServer("api", []mcp.Tool{mcp.NewTool("search")})}
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), srv); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	hc := &config.HealthCheckConfig{Ping: true}
+	agg.probeServer(t.Context(), "api", hc)
+
+	if srv.pings != 1 || len(srv.GetToolCalls()) != 0 {
+		t.Fatalf("pings = %d, tool calls = %d; want one ping", srv.pings, len(srv.GetToolCalls()))
+	}
+
+	if got := agg.health.Status("api"); got != HealthHealthy {
+		t.Errorf("status after answered ping = %s, want healthy", got)
+	}
+
+	srv.pingErr = errors.New("connection refused")
+	agg.probeServer(t.Context(), "api", hc)
+
+	if got := agg.health.Status("api"); got != HealthDegraded {
+		t.Errorf("status after failed ping = %s, want degraded", got)
+	}
+}
+
+func TestProbeServer_HidesToolsWhileUnhealthy(t *testing.T) {
+	t.Parallel()
+
+	mock := testutil.NewMockServer("db", []mcp.Tool{mcp.NewTool("ping")})
+	mock.ServerCfg = &config.ServerConfig{Health: &config.HealthCheckConfig{Tool: "ping", HideTools: true}}
+	mock.CallErr = errors.New("connection refused")
+	agg := newProbeAggregator(t, mock)
+	agg.CreateMCPServer()
+
+	listed := []mcp.Tool{mcp.NewTool("assern_status"), mcp.NewTool("db_ping")}
+
+	for range DefaultHealthThreshold {
+		if got := agg.hideDownTools(t.Context(), listed); len(got) != 2 {
+			t.Fatalf("tools listed before the server is unhealthy = %d, want 2", len(got))
+		}
+
+		agg.probeServer(t.Context(), "db", mock.ServerCfg.Health)
+	}
+
+	got := agg.hideDownTools(t.Context(), listed)
+	if len(got) != 1 || got[0].Name != "assern_status" {
+		t.Errorf("tools listed while unhealthy = %+v, want only assern_status", got)
+	}
+
+	mock.CallErr = nil
+	agg.probeServer(t.Context(), "db", mock.ServerCfg.Health)
+
+	if got := agg.hideDownTools(t.Context(), listed); len(got) != 2 {
+		t.Errorf("tools listed after recovery = %d, want 2", len(got))
+	}
+}
@@ -0,0 +1,69 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/rand"
+	"maps"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// callMetaKey is the context key of the _meta fields sent with a tool call.
+type callMetaKey struct{}
+
+// withCallMeta returns a context whose tool calls send meta as the request
+// _meta (see ManagedServer.CallTool), added to the fields ctx already sends.
+func withCallMeta(ctx context.Context, meta map[string]any) context.Context {
+	if prev := callMeta(ctx); prev != nil {
+		merged := maps.Clone(prev)
+		maps.Copy(merged, meta)
+		meta = merged
+	}
+
+	return context.WithValue(ctx, callMetaKey{}, meta)
+}
+
+// callMeta returns the _meta fields set by withCallMeta, or nil.
+func callMeta(ctx context.Context) map[string]any {
+	meta, _ := ctx.Value(callMetaKey{}).(map[string]any)
+
+	return meta
+}
+
+// newIdempotencyKey returns a random key for one tool call.
+func newIdempotencyKey() string {
+	return rand.Text()
+}
+
+// withIdempotencyKey returns the context and arguments of a call to a tool
+// configured under idempotency_keys, so every attempt of the call, retries
+// included, carries the same key. args is not modified, and a key the
+// client passed as the argument is kept.
+func withIdempotencyKey(
+	ctx context.Context,
+	cfg *config.ServerConfig,
+	tool string,
+	args map[string]any,
+) (context.Context, map[string]any) {
+	target, ok := cfg.IdempotencyKey(tool)
+	if !ok {
+		return ctx, args
+	}
+
+	if target.Meta {
+		return withCallMeta(ctx, map[string]any{target.Name: newIdempotencyKey()}), args
+	}
+
+	if _, set := args[target.Name]; set {
+		return ctx, args
+	}
+
+	keyed := maps.Clone(args)
+	if keyed == nil {
+		keyed = make(map[string]any, 1)
+	}
+
+	keyed[target.Name] = newIdempotencyKey()
+
+	return ctx, keyed
+}
@@ -0,0 +1,132 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestWithIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ServerConfig{IdempotencyKeys: map[string]string{
+		"create_issue": "idempotency_key",
+		"send":         "_meta.idempotencyKey",
+	}}
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Parallel()
+
+		args := map[string]any{"q": "x"}
+
+		ctx, got := withIdempotencyKey(t.Context(), cfg, "search", args)
+		if len(got) != 1 || callMeta(ctx) != nil {
+			t.Errorf("withIdempotencyKey() = %v, meta %v; want the call unchanged", got, callMeta(ctx))
+		}
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		t.Parallel()
+
+		args := map[string]any{"title": "bug"}
+
+		_, got := withIdempotencyKey(t.Context(), cfg, "create_issue", args)
+		if key, _ := got["idempotency_key"].(string); key == "" {
+			t.Errorf("arguments = %v, want a generated idempotency_key", got)
+		}
+
+		if _, ok := args["idempotency_key"]; ok {
+			t.Error("the caller's arguments were modified")
+		}
+	})
+
+	t.Run("argument passed by the client", func(t *testing.T) {
+		t.Parallel()
+
+		args := map[string]any{"idempotency_key": "client-key"}
+
+		_, got := withIdempotencyKey(t.Context(), cfg, "create_issue", args)
+		if got["idempotency_key"] != "client-key" {
+			t.Errorf("idempotency_key = %v, want the client's key", got["idempotency_key"])
+		}
+	})
+
+	t.Run("meta", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, got := withIdempotencyKey(t.Context(), cfg, "send", nil)
+		if got != nil {
+			t.Errorf("arguments = %v, want nil", got)
+		}
+
+		if key, _ := callMeta(ctx)["idempotencyKey"].(string); key == "" {
+			t.Errorf("meta = %v, want a generated idempotencyKey", callMeta(ctx))
+		}
+	})
+}
+
+func TestToolCallIdempotencyKeyOnRetries(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("create_issue")})
+	mock.ServerCfg = &config.ServerConfig{
+		Command:         "github-mcp",
+		Retry:           &config.RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, BackoffFactor: 1},
+		IdempotencyKeys: map[string]string{"create_issue": "idempotency_key"},
+	}
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_create_issue")
+	handler := agg.createToolHandler(entry)
+	mock.CallErr = errors.New("connection reset")
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"title": "bug"}
+
+	for range 2 {
+		if _, err := handler(t.Context(), req); err != nil {
+			t.Fatalf("handler: %v", err)
+		}
+	}
+
+	calls := mock.GetToolCalls()
+	if len(calls) != 6 {
+		t.Fatalf("backend calls = %d, want 3 attempts for each of 2 calls", len(calls))
+	}
+
+	first, _ := calls[0].Args["idempotency_key"].(string)
+	second, _ := calls[3].Args["idempotency_key"].(string)
+
+	if first == "" || first == second {
+		t.Errorf("keys = %q and %q, want a distinct key per call", first, second)
+	}
+
+	for i, call := range calls {
+		want := first
+		if i >= 3 {
+			want = second
+		}
+
+		if call.Args["idempotency_key"] != want {
+			t.Errorf("attempt %d key = %v, want %q repeated on retries", i, call.Args["idempotency_key"], want)
+		}
+	}
+}
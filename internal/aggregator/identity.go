@@ -0,0 +1,70 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// serverName is the MCP implementation name assern reports to clients.
+const serverName = "Valksor Assern"
+
+// Identity tells clients and users which assern instance, and which project
+// context, they are connected to when several instances run side by side.
+type Identity struct {
+	Instance    string `json:"instance"`
+	Project     string `json:"project,omitempty"`
+	Fingerprint string `json:"config_fingerprint"`
+}
+
+// Identity returns the instance identity for the current configuration. The
+// fingerprint changes when a reload applies a different configuration.
+func (a *Aggregator) Identity() Identity {
+	a.cfgMu.RLock()
+	cfg := a.cfg
+	a.cfgMu.RUnlock()
+
+	return Identity{
+		Instance:    config.InstanceName(cfg),
+		Project:     a.ProjectName(),
+		Fingerprint: config.Fingerprint(cfg),
+	}
+}
+
+// Title is a human-readable label, e.g. "Valksor Assern (laptop, project acme)".
+func (id Identity) Title() string {
+	if id.Project == "" {
+		return fmt.Sprintf("%s (%s)", serverName, id.Instance)
+	}
+
+	return fmt.Sprintf("%s (%s, project %s)", serverName, id.Instance, id.Project)
+}
+
+// Instructions describes the identity for the initialize instructions.
+func (id Identity) Instructions() string {
+	project := id.Project
+	if project == "" {
+		project = "none"
+	}
+
+	return fmt.Sprintf(
+		"Connected to assern instance %q (project: %s, config fingerprint: %s). "+
+			"assern aggregates tools from several MCP servers; tool names are prefixed with their server name. "+
+			"Call %s to see which servers are up.",
+		id.Instance, project, id.Fingerprint, ToolStatusName,
+	)
+}
+
+// announceIdentity is an after-initialize hook that adds the identity and
+// instructions to the initialize result. It is computed per session rather
+// than fixed when the MCP server is created, so sessions opened after a
+// reload see the new fingerprint and instructions.
+func (a *Aggregator) announceIdentity(_ context.Context, _ any, _ *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	id := a.Identity()
+
+	result.ServerInfo.Title = id.Title()
+	result.Instructions = a.instructions(id)
+}
@@ -0,0 +1,114 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/project"
+)
+
+func TestInitializeAnnouncesIdentity(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.InstanceName = "work-laptop"
+
+	agg, err := New(Options{
+		Config:  cfg,
+		Project: &project.Context{Name: "acme"},
+		Logger:  slog.New(slog.DiscardHandler),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv := agg.CreateMCPServer()
+
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0"},
+			"capabilities":    map[string]any{},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal initialize: %v", err)
+	}
+
+	data, err := json.Marshal(srv.HandleMessage(context.Background(), raw))
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
+	}
+
+	var parsed struct {
+		Result struct {
+			ServerInfo struct {
+				Name  string `json:"name"`
+				Title string `json:"title"`
+			} `json:"serverInfo"`
+			Instructions string `json:"instructions"`
+		} `json:"result"`
+	}
+
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("unmarshal initialize response: %v", err)
+	}
+
+	info := parsed.Result.ServerInfo
+	if info.Name != serverName {
+		t.Errorf("serverInfo.name = %q, want %q", info.Name, serverName)
+	}
+
+	if want := "Valksor Assern (work-laptop, project acme)"; info.Title != want {
+		t.Errorf("serverInfo.title = %q, want %q", info.Title, want)
+	}
+
+	fingerprint := config.Fingerprint(cfg)
+	for _, want := range []string{`"work-laptop"`, "project: acme", "config fingerprint: " + fingerprint} {
+		if !strings.Contains(parsed.Result.Instructions, want) {
+			t.Errorf("instructions %q missing %q", parsed.Result.Instructions, want)
+		}
+	}
+
+	// A reload that changes the configuration changes the fingerprint.
+	changed := cfg.Clone()
+	changed.Settings.OutputFormat = "toon"
+
+	agg.cfgMu.Lock()
+	agg.cfg = changed
+	agg.cfgMu.Unlock()
+
+	if got := agg.Identity().Fingerprint; got == fingerprint {
+		t.Errorf("fingerprint after config change = %q, want it to differ", got)
+	}
+}
+
+func TestIdentityTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		id   Identity
+		want string
+	}{
+		{name: "with project", id: Identity{Instance: "ci", Project: "acme"}, want: "Valksor Assern (ci, project acme)"},
+		{name: "without project", id: Identity{Instance: "ci"}, want: "Valksor Assern (ci)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.id.Title(); got != tt.want {
+				t.Errorf("Title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
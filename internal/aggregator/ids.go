@@ -0,0 +1,79 @@
+package aggregator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Client transports passed to a SessionIDFunc.
+const (
+	SessionSocket = "socket"
+	SessionHTTP   = "http"
+	SessionSSE    = "sse"
+)
+
+// SessionIDFunc returns the ID of a new client session on a transport. IDs
+// must be unique within the process; they appear in logs and audit records.
+type SessionIDFunc func(transport string) string
+
+// NewSessionIDFunc returns a SessionIDFunc that builds IDs from prefix, the
+// transport and a suffix in format (see config.SessionFormatUUID), e.g.
+// "devbox/socket-3f2a9c1e" for prefix "devbox/" and the short format.
+func NewSessionIDFunc(prefix, format string) SessionIDFunc {
+	var counter atomic.Uint64
+
+	return func(transport string) string {
+		var suffix string
+
+		switch format {
+		case config.SessionFormatShort:
+			b := make([]byte, 4)
+			_, _ = rand.Read(b)
+			suffix = hex.EncodeToString(b)
+		case config.SessionFormatSequential:
+			suffix = strconv.FormatUint(counter.Add(1), 10)
+		default:
+			suffix = uuid.NewString()
+		}
+
+		return prefix + transport + "-" + suffix
+	}
+}
+
+// sessionIDsFromConfig returns the SessionIDFunc for settings.ids, or nil
+// when session IDs are not configured.
+func sessionIDsFromConfig(cfg *config.Config) SessionIDFunc {
+	if cfg.Settings == nil || !cfg.Settings.IDs.CustomSessions() {
+		return nil
+	}
+
+	return NewSessionIDFunc(config.SessionPrefix(cfg), cfg.Settings.IDs.EffectiveSessionFormat())
+}
+
+// InstanceID returns the configured instance ID (settings.ids.instance), or
+// "" when unset. It is fixed when the aggregator is created.
+func (a *Aggregator) InstanceID() string {
+	return a.instanceID
+}
+
+// SessionIDs returns the function naming new client sessions, or nil when
+// transports keep their own session IDs.
+func (a *Aggregator) SessionIDs() SessionIDFunc {
+	return a.sessionIDs
+}
+
+// NewSessionID returns the ID for a new client session on transport, or ""
+// when transports keep their own session IDs.
+func (a *Aggregator) NewSessionID(transport string) string {
+	if a.sessionIDs == nil {
+		return ""
+	}
+
+	return a.sessionIDs(transport)
+}
@@ -0,0 +1,136 @@
+package aggregator
+
+import (
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/audit"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestNewSessionIDFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		want   *regexp.Regexp
+	}{
+		{format: config.SessionFormatUUID, want: regexp.MustCompile(`^dev/socket-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)},
+		{format: "", want: regexp.MustCompile(`^dev/socket-[0-9a-f-]{36}$`)},
+		{format: config.SessionFormatShort, want: regexp.MustCompile(`^dev/socket-[0-9a-f]{8}$`)},
+		{format: config.SessionFormatSequential, want: regexp.MustCompile(`^dev/socket-1$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			ids := NewSessionIDFunc("dev/", tt.format)
+
+			first := ids(SessionSocket)
+			if !tt.want.MatchString(first) {
+				t.Errorf("session id = %q, want match for %s", first, tt.want)
+			}
+
+			if second := ids(SessionSocket); second == first {
+				t.Errorf("second session id = %q, want a different id", second)
+			}
+		})
+	}
+}
+
+func TestAggregatorIDs(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.IDs = &config.IDsConfig{
+		Instance:      "devbox-{pid}",
+		SessionPrefix: "{instance}/",
+		SessionFormat: config.SessionFormatSequential,
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	auditLog, err := audit.Open(&config.AuditLogConfig{Path: path})
+	if err != nil {
+		t.Fatalf("audit.Open: %v", err)
+	}
+
+	sink := &fakeMetrics{}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler), Metrics: sink, AuditLog: auditLog})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	id := config.ExpandIDTemplate("devbox-{pid}", cfg, "")
+	if got := agg.InstanceID(); got != id {
+		t.Fatalf("InstanceID() = %q, want %q", got, id)
+	}
+
+	if got := agg.NewSessionID(SessionSocket); got != id+"/socket-1" {
+		t.Errorf("NewSessionID() = %q, want %q", got, id+"/socket-1")
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("github_search")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "github_search"
+
+	if _, err := agg.auditToolCalls(agg.createToolHandler(entry))(t.Context(), req); err != nil {
+		t.Fatalf("calling github_search: %v", err)
+	}
+
+	agg.reportHealthMetrics()
+
+	for _, name := range []string{MetricToolCallCount, MetricServersActive, MetricServerHealthy} {
+		if _, found := sink.find(name, map[string]string{"instance_id": id}); !found {
+			t.Errorf("%s has no instance_id tag", name)
+		}
+	}
+
+	records, err := audit.Tail(path, 1)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Tail() = %v, %v", records, err)
+	}
+
+	if got := records[0].Instance; got != id {
+		t.Errorf("audit record instance_id = %q, want %q", got, id)
+	}
+}
+
+func TestAggregatorSessionIDsDefault(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if got := agg.NewSessionID(SessionHTTP); got != "" {
+		t.Errorf("NewSessionID() = %q, want empty so transports keep their ids", got)
+	}
+
+	agg, err = New(Options{
+		Config:     config.NewConfig(),
+		Logger:     slog.New(slog.DiscardHandler),
+		SessionIDs: func(transport string) string { return "custom-" + transport },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if got := agg.NewSessionID(SessionHTTP); got != "custom-http" {
+		t.Errorf("NewSessionID() = %q, want custom-http from Options.SessionIDs", got)
+	}
+}
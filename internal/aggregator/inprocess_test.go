@@ -0,0 +1,78 @@
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// newEchoServer returns an in-process MCP server with an echo tool.
+func newEchoServer() *server.MCPServer {
+	srv := server.NewMCPServer("echo", "1.0.0", server.WithToolCapabilities(false))
+	srv.AddTool(mcp.NewTool("echo", mcp.WithString("text")), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("echo: " + req.GetString("text", "")), nil
+	})
+
+	return srv
+}
+
+func TestInProcessServer(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{
+		Config:    config.NewConfig(),
+		Logger:    slog.New(slog.DiscardHandler),
+		InProcess: map[string]*server.MCPServer{"local": newEchoServer()},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	if tools := agg.ListTools(); len(tools) != 1 || tools[0].PrefixedName != "local_echo" {
+		t.Fatalf("ListTools() = %+v, want local_echo", tools)
+	}
+
+	result, err := agg.CallTool(t.Context(), "local_echo", map[string]any{"text": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "echo: hi" {
+		t.Errorf("CallTool() = %+v, want the in-process result", result.Content)
+	}
+
+	if got := agg.Status().Servers; len(got) != 1 || got[0].State != ServerStateUp {
+		t.Errorf("Status().Servers = %+v, want local up", got)
+	}
+}
+
+func TestInProcessServerNameConflict(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Servers["local"] = &config.ServerConfig{Command: "local-mcp"}
+
+	agg, err := New(Options{
+		Config:    cfg,
+		Logger:    slog.New(slog.DiscardHandler),
+		InProcess: map[string]*server.MCPServer{"local": newEchoServer()},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.Start(t.Context()); err == nil || !strings.Contains(err.Error(), "both configured and registered in-process") {
+		t.Errorf("Start() error = %v, want a name conflict", err)
+	}
+}
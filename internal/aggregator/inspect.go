@@ -0,0 +1,73 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolDetails describes one exposed tool, for 'assern inspect'.
+type ToolDetails struct {
+	// Name is the exposed name; OriginalName is the name on the backend.
+	Name         string `json:"name"`
+	OriginalName string `json:"original_name"`
+	Server       string `json:"server"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	// InputSchema and OutputSchema are the JSON Schemas the backend sent.
+	InputSchema  json.RawMessage     `json:"input_schema"`
+	OutputSchema json.RawMessage     `json:"output_schema,omitempty"`
+	Annotations  *mcp.ToolAnnotation `json:"annotations,omitempty"`
+	// Aliases are the aliases that resolve to the tool.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// InspectTool returns the details of the tool exposed as name, or of the
+// target of the alias name.
+func (a *Aggregator) InspectTool(name string) (*ToolDetails, error) {
+	entry, ok := a.tools.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+
+	// Marshalling applies RawInputSchema and RawOutputSchema when set
+	data, err := json.Marshal(entry.Tool)
+	if err != nil {
+		return nil, fmt.Errorf("encoding tool %s: %w", entry.PrefixedName, err)
+	}
+
+	var schemas struct {
+		InputSchema  json.RawMessage `json:"inputSchema"`
+		OutputSchema json.RawMessage `json:"outputSchema"`
+	}
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("decoding tool %s: %w", entry.PrefixedName, err)
+	}
+
+	details := &ToolDetails{
+		Name:         entry.PrefixedName,
+		OriginalName: entry.Tool.Name,
+		Server:       entry.ServerName,
+		Title:        entry.Tool.Title,
+		Description:  entry.Tool.Description,
+		InputSchema:  schemas.InputSchema,
+		OutputSchema: schemas.OutputSchema,
+	}
+
+	if entry.Tool.Annotations != (mcp.ToolAnnotation{}) {
+		annotations := entry.Tool.Annotations
+		details.Annotations = &annotations
+	}
+
+	for alias, target := range a.tools.Aliases() {
+		if target == entry.PrefixedName {
+			details.Aliases = append(details.Aliases, alias)
+		}
+	}
+
+	slices.Sort(details.Aliases)
+
+	return details, nil
+}
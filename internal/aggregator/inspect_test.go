@@ -0,0 +1,75 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestInspectTool(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	schema := `{"type":"object","properties":{"query":{"type":"string"}},"required":["query"]}`
+
+	search := mcp.NewToolWithRawSchema("search", "Search repositories", json.RawMessage(schema))
+	search.Annotations.ReadOnlyHint = mcp.ToBoolPtr(true)
+	plain := mcp.Tool{Name: "plain", InputSchema: mcp.ToolInputSchema{Type: "object"}}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{search, plain})
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	agg.loadAliases(&config.Settings{Aliases: map[string]string{"find": "github_search", "lookup": "github_search"}})
+
+	details, err := agg.InspectTool("find")
+	if err != nil {
+		t.Fatalf("InspectTool() error = %v", err)
+	}
+
+	if details.Name != "github_search" || details.OriginalName != "search" || details.Server != "github" ||
+		details.Description != "Search repositories" {
+		t.Errorf("InspectTool() = %+v", details)
+	}
+
+	if string(details.InputSchema) != schema {
+		t.Errorf("InputSchema = %s, want the raw schema %s", details.InputSchema, schema)
+	}
+
+	if details.Annotations == nil || details.Annotations.ReadOnlyHint == nil || !*details.Annotations.ReadOnlyHint {
+		t.Errorf("Annotations = %+v, want the read-only hint", details.Annotations)
+	}
+
+	if !slices.Equal(details.Aliases, []string{"find", "lookup"}) {
+		t.Errorf("Aliases = %v, want [find lookup]", details.Aliases)
+	}
+
+	details, err = agg.InspectTool("github_plain")
+	if err != nil {
+		t.Fatalf("InspectTool() error = %v", err)
+	}
+
+	if details.Annotations != nil || details.OutputSchema != nil || details.Aliases != nil {
+		t.Errorf("InspectTool() = %+v, want no annotations, output schema or aliases", details)
+	}
+
+	if _, err := agg.InspectTool("github_missing"); !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("InspectTool() for a missing tool error = %v, want ErrToolNotFound", err)
+	}
+}
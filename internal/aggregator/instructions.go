@@ -0,0 +1,104 @@
+package aggregator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// instructions builds the instructions returned at initialize: the instance
+// identity, the failsafe notice if the configuration failed to load, then
+// settings.instructions (which a project may replace), then,
+// with settings.instructions_summary, a generated summary of the servers and
+// policies in effect.
+func (a *Aggregator) instructions(id Identity) string {
+	a.cfgMu.RLock()
+	var configured string
+	var summary bool
+	if a.cfg != nil && a.cfg.Settings != nil {
+		configured = strings.TrimSpace(a.cfg.Settings.Instructions)
+		summary = a.cfg.Settings.InstructionsSummary
+	}
+	a.cfgMu.RUnlock()
+
+	parts := []string{id.Instructions()}
+	if err := a.ConfigError(); err != nil {
+		parts = append(parts, failsafeMessage(err))
+	}
+
+	if configured != "" {
+		parts = append(parts, configured)
+	}
+
+	if summary {
+		parts = append(parts, a.instructionsSummary())
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// instructionsSummary describes the backend servers, aliases and the
+// discovery and code mode policies, one line each.
+func (a *Aggregator) instructionsSummary() string {
+	lines := []string{"Servers:"}
+
+	a.mu.RLock()
+	for _, name := range slices.Sorted(maps.Keys(a.servers)) {
+		count := len(a.tools.GetByServer(name))
+
+		line := fmt.Sprintf("- %s: %d tools", name, count)
+		if count == 1 {
+			line = fmt.Sprintf("- %s: 1 tool", name)
+		}
+
+		if cfg := a.servers[name].Config(); cfg != nil && cfg.DryRun {
+			line += " (dry run: calls return the request instead of running it)"
+		}
+
+		if a.health.Status(name).down() {
+			line += " (currently unavailable)"
+		}
+
+		lines = append(lines, line)
+	}
+	a.mu.RUnlock()
+
+	if len(lines) == 1 {
+		lines = append(lines, "- none running")
+	}
+
+	if aliases := a.tools.Aliases(); len(aliases) > 0 {
+		pairs := make([]string, 0, len(aliases))
+		for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+			pairs = append(pairs, alias+" -> "+aliases[alias])
+		}
+
+		lines = append(lines, "Aliases: "+strings.Join(pairs, ", "))
+	}
+
+	if a.SearchExposure() {
+		lines = append(lines, fmt.Sprintf(
+			"Tools are not listed: find them with %s, get their arguments with %s and call them with %s.",
+			ToolSearchToolsName, ToolDescribeName, ToolCallName,
+		))
+	}
+
+	if a.DiscoveryEnabled() {
+		lines = append(lines, fmt.Sprintf(
+			"Tool discovery is on: most tools are not listed up front. Find them with %s and call %s before using them.",
+			ToolSearchName, ToolLoadName,
+		))
+	}
+
+	if cfg := a.codeModeConfig(); cfg.IsEnabled() {
+		line := fmt.Sprintf("Code mode is on: %s runs a script that can call several tools in one request.", ToolExecuteName)
+		if len(cfg.AllowedTools) > 0 {
+			line += " Scripts may only call: " + strings.Join(cfg.AllowedTools, ", ") + "."
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,97 @@
+package aggregator
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestInstructions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings func(*config.Settings)
+		want     []string
+		wantNot  []string
+	}{
+		{
+			name:     "identity only",
+			settings: func(*config.Settings) {},
+			want:     []string{`assern instance "test"`},
+			wantNot:  []string{"Servers:"},
+		},
+		{
+			name: "configured instructions",
+			settings: func(s *config.Settings) {
+				s.Instructions = "  Prefer read-only tools.\n"
+			},
+			want:    []string{"\n\nPrefer read-only tools."},
+			wantNot: []string{"Servers:"},
+		},
+		{
+			name: "generated summary",
+			settings: func(s *config.Settings) {
+				s.Instructions = "Prefer read-only tools."
+				s.InstructionsSummary = true
+				s.Aliases = map[string]string{"gh": "github_search"}
+				s.Discovery = &config.DiscoveryConfig{Enabled: true}
+				s.CodeMode = &config.CodeModeConfig{Enabled: true, AllowedTools: []string{"github_search"}}
+			},
+			want: []string{
+				"Prefer read-only tools.\n\nServers:",
+				"- github: 1 tool (dry run",
+				"Aliases: gh -> github_search",
+				ToolSearchName,
+				"Scripts may only call: github_search.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.NewConfig()
+			cfg.Settings.InstanceName = "test"
+			tt.settings(cfg.Settings)
+
+			agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			agg.tools.SetAliases(cfg.Settings.Aliases)
+
+			mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+			mock.ServerCfg = &config.ServerConfig{Command: "gh-mcp", DryRun: true}
+
+			if err := mock.Start(t.Context()); err != nil {
+				t.Fatalf("mock.Start: %v", err)
+			}
+
+			if err := agg.AddServer(t.Context(), mock); err != nil {
+				t.Fatalf("AddServer: %v", err)
+			}
+
+			got := agg.instructions(agg.Identity())
+
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("instructions missing %q:\n%s", want, got)
+				}
+			}
+
+			for _, unwanted := range tt.wantNot {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("instructions contain %q:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package aggregator_test
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
@@ -744,3 +745,44 @@ func TestAggregator_ResourceAndPromptPrefixing(t *testing.T) {
 		}
 	})
 }
+
+func TestAggregator_CallTool(t *testing.T) {
+	t.Parallel()
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	mock.ToolResults["search"] = mcp.NewToolResultText("found")
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+
+	if entry, ok := agg.Tool("github_search"); !ok || entry.Tool.Name != "search" {
+		t.Errorf("Tool() = %+v, %v; want the search tool", entry, ok)
+	}
+
+	result, err := agg.CallTool(t.Context(), "github_search", map[string]any{"q": "go"})
+	if err != nil || result.IsError {
+		t.Fatalf("CallTool() = %+v, %v", result, err)
+	}
+
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "found" {
+		t.Errorf("CallTool() content = %+v, want found", result.Content)
+	}
+
+	if len(mock.ToolCalls) != 1 || mock.ToolCalls[0].Args["q"] != "go" {
+		t.Errorf("backend calls = %+v", mock.ToolCalls)
+	}
+
+	if _, err := agg.CallTool(t.Context(), "github_missing", nil); !errors.Is(err, aggregator.ErrToolNotFound) {
+		t.Errorf("CallTool(missing) error = %v, want ErrToolNotFound", err)
+	}
+}
@@ -2,6 +2,7 @@ package aggregator
 
 import (
 	"context"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
@@ -56,6 +57,26 @@ type PromptServer interface {
 	GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error)
 }
 
+// PingServer is an optional interface for servers that answer MCP ping
+// requests, used by health checks with ping set.
+type PingServer interface {
+	Server
+
+	// Ping sends an MCP ping and waits for the reply.
+	Ping(ctx context.Context) error
+}
+
+// ClockSkewServer is an optional interface for servers that measure the
+// clock of their OAuth authorization server, reported by Status.
+type ClockSkewServer interface {
+	Server
+
+	// ClockSkew returns how far the authorization server's clock was last
+	// measured ahead of the local one and whether that exceeds the
+	// tolerance; ok is false until it was measured.
+	ClockSkew() (offset time.Duration, beyond, ok bool)
+}
+
 // FullServer combines all MCP capabilities - tools, resources, and prompts.
 type FullServer interface {
 	Server
@@ -65,3 +86,9 @@ type FullServer interface {
 
 // Ensure ManagedServer implements Server interface.
 var _ Server = (*ManagedServer)(nil)
+
+// Ensure ManagedServer answers health check pings.
+var _ PingServer = (*ManagedServer)(nil)
+
+// Ensure ManagedServer reports clock skew.
+var _ ClockSkewServer = (*ManagedServer)(nil)
@@ -0,0 +1,281 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// Where the tools of a lazy server came from before it was started.
+const (
+	lazySourceConfig = "config"
+	lazySourceCache  = "cache"
+)
+
+// lazyStarts tracks lazy servers that are registered but not started yet.
+// Each pending server has its own lock so concurrent first calls wait for a
+// single start instead of racing to spawn the process.
+type lazyStarts struct {
+	mu      sync.Mutex
+	pending map[string]*lazyStart
+}
+
+type lazyStart struct {
+	mu      sync.Mutex
+	started bool
+}
+
+func newLazyStarts() *lazyStarts {
+	return &lazyStarts{pending: make(map[string]*lazyStart)}
+}
+
+func (l *lazyStarts) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending[name] = &lazyStart{}
+}
+
+// get returns the pending start of a server, or nil if it is not waiting for
+// its first call.
+func (l *lazyStarts) get(name string) *lazyStart {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.pending[name]
+}
+
+func (l *lazyStarts) remove(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.pending, name)
+}
+
+// lazyTools returns the tools to advertise for a lazy server without starting
+// it: the tools declared in its config, else the cached result of its last
+// tools/list. It returns nil when neither is available.
+func (a *Aggregator) lazyTools(name string, cfg *config.ServerConfig) ([]mcp.Tool, string) {
+	if len(cfg.Tools) > 0 {
+		tools := make([]mcp.Tool, 0, len(cfg.Tools))
+		for _, d := range cfg.Tools {
+			tools = append(tools, declaredTool(d))
+		}
+
+		return tools, lazySourceConfig
+	}
+
+	if tools := a.toolCache.load(name, cfg); tools != nil {
+		return tools, lazySourceCache
+	}
+
+	return nil, ""
+}
+
+// registerLazy registers a lazy server with the tools advertised for it,
+// leaving the process to be started by the first call (see startLazy).
+func (a *Aggregator) registerLazy(name string, managed *ManagedServer, tools []mcp.Tool, source string) error {
+	if err := a.registerTools(name, managed.Config(), tools); err != nil {
+		a.tools.RemoveServer(name)
+
+		return fmt.Errorf("registering tools: %w", err)
+	}
+
+	a.servers[name] = managed
+	a.lazy.add(name)
+	a.logger.Info("server registered lazily", "name", name, "tools", len(tools), "tools_from", source)
+
+	a.superviseServer(name, managed)
+	a.watchToolChanges(name, managed)
+
+	return nil
+}
+
+// startLazy starts a lazy server on the first call routed to it, refreshing
+// its tools from the live tools/list. It is a no-op for servers that are not
+// waiting for their first call.
+func (a *Aggregator) startLazy(ctx context.Context, name string) error {
+	pending := a.lazy.get(name)
+	if pending == nil {
+		return nil
+	}
+
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	if pending.started {
+		return nil
+	}
+
+	a.mu.RLock()
+	managed, ok := a.servers[name].(*ManagedServer)
+	a.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	a.logger.Info("starting lazy server on first call", "server", name)
+
+	if err := a.relaunch(ctx, name, managed); err != nil {
+		a.runtime.failed(name, opStart, err)
+
+		if isAuthError(err) {
+			return a.markNeedsAuth(ctx, name, managed.Config(), err)
+		}
+
+		a.publish(events.ServerFailed, name, err.Error(), nil)
+
+		return err
+	}
+
+	pending.started = true
+	a.lazy.remove(name)
+
+	a.startHealthProbe(name, managed.Config())
+	a.publish(events.ServerStarted, name, "started on first call", map[string]any{
+		"tools": len(a.tools.GetByServer(name)),
+	})
+
+	return nil
+}
+
+// declaredTool converts a tool declared in the config to an MCP tool. An
+// invalid schema falls back to an empty object schema; the real schema
+// replaces it once the server is started.
+func declaredTool(d config.ToolDeclaration) mcp.Tool {
+	tool := mcp.NewTool(d.Name, mcp.WithDescription(d.Description))
+
+	if len(d.InputSchema) == 0 {
+		return tool
+	}
+
+	data, err := json.Marshal(d.InputSchema)
+	if err != nil {
+		return tool
+	}
+
+	var schema mcp.ToolInputSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return tool
+	}
+
+	tool.InputSchema = schema
+
+	return tool
+}
+
+// toolCache persists the tools/list result of lazy servers so later runs can
+// advertise them without starting the server. An entry is only used while
+// the server's launch settings are unchanged (see launchFingerprint).
+type toolCache struct {
+	dir string
+}
+
+// cachedTools is the on-disk form of one toolCache entry.
+type cachedTools struct {
+	Fingerprint string     `json:"fingerprint"`
+	Tools       []mcp.Tool `json:"tools"`
+}
+
+// newToolCache returns a cache in config.ToolCacheDir, or nil (caching
+// disabled) when the directory cannot be determined.
+func newToolCache() *toolCache {
+	dir, err := config.ToolCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return &toolCache{dir: dir}
+}
+
+func (c *toolCache) path(name string) string {
+	return filepath.Join(c.dir, tokenKeySanitizer.ReplaceAllString(name, "_")+".json")
+}
+
+// load returns the cached tools of a server, or nil if there are none or
+// they were cached for different launch settings.
+func (c *toolCache) load(name string, cfg *config.ServerConfig) []mcp.Tool {
+	if c == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path(name))
+	if err != nil {
+		return nil
+	}
+
+	var entry cachedTools
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Fingerprint != launchFingerprint(cfg) {
+		return nil
+	}
+
+	return entry.Tools
+}
+
+// save stores the tools of a server. Safe on a nil cache.
+func (c *toolCache) save(name string, cfg *config.ServerConfig, tools []mcp.Tool) error {
+	if c == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("creating tool cache dir: %w", err)
+	}
+
+	data, err := json.Marshal(cachedTools{Fingerprint: launchFingerprint(cfg), Tools: tools})
+	if err != nil {
+		return fmt.Errorf("encoding tools: %w", err)
+	}
+
+	if err := os.WriteFile(c.path(name), data, 0o600); err != nil {
+		return fmt.Errorf("writing tool cache: %w", err)
+	}
+
+	return nil
+}
+
+// cacheTools records the tools of a lazy server for the next run.
+func (a *Aggregator) cacheTools(name string, cfg *config.ServerConfig, tools []mcp.Tool) {
+	if !cfg.Lazy {
+		return
+	}
+
+	if err := a.toolCache.save(name, cfg, tools); err != nil {
+		a.logger.Debug("could not cache tools of lazy server", "server", name, "error", err)
+	}
+}
+
+// launchFingerprint hashes the settings that decide which backend a server
+// talks to, so cached tools are dropped when the command, image, URL or
+// environment change.
+func launchFingerprint(cfg *config.ServerConfig) string {
+	data, _ := json.Marshal(struct {
+		Command   string
+		Args      []string
+		Env       map[string]string
+		WorkDir   string
+		URL       string
+		Headers   map[string]string
+		Transport string
+
+		// Left out when unset, keeping the fingerprints of other servers
+		Image   string   `json:",omitempty"`
+		Volumes []string `json:",omitempty"`
+		Network string   `json:",omitempty"`
+	}{cfg.Command, cfg.Args, cfg.Env, cfg.WorkDir, cfg.URL, cfg.Headers, cfg.Transport, cfg.Image, cfg.Volumes, cfg.Network})
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
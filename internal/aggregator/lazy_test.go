@@ -0,0 +1,150 @@
+package aggregator
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestLazyServerStartsOnFirstCall(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	agg.toolCache = &toolCache{dir: t.TempDir()}
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	cfg := &config.ServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestStdioHelperProcess$"},
+		Lazy:    true,
+		Tools:   []config.ToolDeclaration{{Name: "echo", Description: "declared"}},
+	}
+
+	managed, err := NewManagedServer("helper", cfg, []string{envStdioHelper + "=1"}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	tools, source := agg.lazyTools("helper", cfg)
+	if source != lazySourceConfig {
+		t.Fatalf("lazyTools() source = %q, want %q", source, lazySourceConfig)
+	}
+
+	agg.mu.Lock()
+	agg.registerLazy("helper", managed, tools, source)
+	agg.mu.Unlock()
+
+	if managed.IsStarted() {
+		t.Fatal("lazy server started at registration")
+	}
+
+	if state := agg.Status().Servers[0].State; state != ServerStateIdle {
+		t.Errorf("state before first call = %q, want %q", state, ServerStateIdle)
+	}
+
+	entry, ok := agg.tools.Get("helper_echo")
+	if !ok {
+		t.Fatal("declared tool not registered")
+	}
+
+	result, err := agg.createToolHandler(entry)(t.Context(), mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Fatalf("first call = %+v, %v", result, err)
+	}
+
+	if !managed.IsStarted() {
+		t.Fatal("lazy server not started by first call")
+	}
+
+	if state := agg.Status().Servers[0].State; state != ServerStateUp {
+		t.Errorf("state after first call = %q, want %q", state, ServerStateUp)
+	}
+
+	// The live tool list replaces the declared one and is cached
+	if _, ok := agg.tools.Get("helper_crash"); !ok {
+		t.Error("live tools not registered after start")
+	}
+
+	cached := agg.toolCache.load("helper", cfg)
+	if len(cached) != 5 {
+		t.Errorf("cached tools = %d, want 5", len(cached))
+	}
+}
+
+func TestToolCache(t *testing.T) {
+	t.Parallel()
+
+	cache := &toolCache{dir: t.TempDir()}
+	cfg := &config.ServerConfig{Command: "jira-mcp", Lazy: true}
+	tools := []mcp.Tool{mcp.NewTool("search", mcp.WithDescription("Search issues"))}
+
+	if got := cache.load("jira", cfg); got != nil {
+		t.Errorf("load() before save = %v, want nil", got)
+	}
+
+	if err := cache.save("jira", cfg, tools); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  *config.ServerConfig
+		want int
+	}{
+		{"same launch settings", &config.ServerConfig{Command: "jira-mcp", Lazy: true}, 1},
+		{"unrelated setting changed", &config.ServerConfig{Command: "jira-mcp", Lazy: true, DryRun: true}, 1},
+		{"command changed", &config.ServerConfig{Command: "jira-mcp-v2", Lazy: true}, 0},
+		{"env changed", &config.ServerConfig{Command: "jira-mcp", Env: map[string]string{"A": "1"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cache.load("jira", tt.cfg); len(got) != tt.want {
+				t.Errorf("load() = %d tools, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	var disabled *toolCache
+	if err := disabled.save("jira", cfg, tools); err != nil || disabled.load("jira", cfg) != nil {
+		t.Error("nil cache is not a no-op")
+	}
+}
+
+func TestDeclaredTool(t *testing.T) {
+	t.Parallel()
+
+	tool := declaredTool(config.ToolDeclaration{
+		Name:        "search",
+		Description: "Search issues",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"jql": map[string]any{"type": "string"}},
+			"required":   []any{"jql"},
+		},
+	})
+
+	if tool.Name != "search" || tool.Description != "Search issues" {
+		t.Errorf("tool = %+v", tool)
+	}
+
+	if !slices.Equal(tool.InputSchema.Required, []string{"jql"}) || tool.InputSchema.Properties["jql"] == nil {
+		t.Errorf("InputSchema = %+v", tool.InputSchema)
+	}
+
+	invalid := declaredTool(config.ToolDeclaration{Name: "bad", InputSchema: map[string]any{"required": "jql"}})
+	if invalid.InputSchema.Type != "object" || len(invalid.InputSchema.Required) != 0 {
+		t.Errorf("invalid schema not replaced by empty object: %+v", invalid.InputSchema)
+	}
+}
@@ -0,0 +1,50 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+)
+
+// watchToolChanges re-discovers a server's tools whenever the backend sends
+// notifications/tools/list_changed. Updating the MCP server's tool set makes
+// it notify connected clients in turn, so they pick up the change without
+// reconnecting.
+func (a *Aggregator) watchToolChanges(name string, srv *ManagedServer) {
+	a.watchers.start(name, func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-srv.ToolsChanged():
+				if err := a.refreshTools(ctx, name, srv); err != nil {
+					a.logger.Warn("could not refresh tools after list change", "server", name, "error", err)
+				}
+			}
+		}
+	})
+}
+
+// refreshTools replaces a running server's registered tools with a fresh
+// tools/list result.
+func (a *Aggregator) refreshTools(ctx context.Context, name string, srv *ManagedServer) error {
+	if !srv.IsStarted() {
+		return nil
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	tools, err := srv.DiscoverTools(listCtx)
+	if err != nil {
+		return fmt.Errorf("discovering tools: %w", err)
+	}
+
+	before := len(a.tools.GetByServer(name))
+
+	a.cacheTools(name, srv.Config(), tools)
+	a.replaceServerTools(name, srv.Config(), tools)
+
+	a.logger.Info("server tool list changed", "server", name, "before", before, "after", len(a.tools.GetByServer(name)))
+
+	return nil
+}
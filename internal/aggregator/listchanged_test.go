@@ -0,0 +1,84 @@
+package aggregator
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestToolListChangeRefreshesTools(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv := newHelperServer(t, "")
+	if err := agg.AddServer(t.Context(), srv); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	mcpServer := agg.CreateMCPServer()
+	agg.watchToolChanges("helper", srv)
+
+	if _, ok := agg.tools.Get("helper_extra"); ok {
+		t.Fatal("extra tool registered before the backend added it")
+	}
+
+	if _, err := srv.CallTool(t.Context(), "grow", nil); err != nil {
+		t.Fatalf("grow: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, ok := agg.tools.Get("helper_extra"); ok {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("tools not refreshed after list_changed")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if mcpServer.GetTool("helper_extra") == nil {
+		t.Error("refreshed tool not exposed to clients")
+	}
+}
+
+func TestHandleNotificationCoalesces(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewManagedServer("test", &config.ServerConfig{Command: "echo"}, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	note := func(method string) {
+		srv.handleNotification(mcp.JSONRPCNotification{Notification: mcp.Notification{Method: method}})
+	}
+
+	note("notifications/message")
+	note(mcp.MethodNotificationToolsListChanged)
+	note(mcp.MethodNotificationToolsListChanged)
+
+	select {
+	case <-srv.ToolsChanged():
+	default:
+		t.Fatal("list_changed not signalled")
+	}
+
+	select {
+	case <-srv.ToolsChanged():
+		t.Error("repeated list_changed signalled twice")
+	default:
+	}
+}
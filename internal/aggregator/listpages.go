@@ -0,0 +1,185 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cursorMetaKey carries the cursor of a list request in its _meta, from
+// the before hook that hides it from mcp-go to the after hook that pages
+// the result. mcp-go's own cursors assume lists sorted by name, which
+// tools/list is not once orderTools has run.
+const cursorMetaKey = "assern/cursor"
+
+// listCursor is the position a nextCursor resumes at: after the item named
+// Name, the last of the previous page, which ended at Offset.
+type listCursor struct {
+	Name   string `json:"n"`
+	Offset int    `json:"o"`
+}
+
+// encode returns the opaque cursor clients send back.
+func (c listCursor) encode() mcp.Cursor {
+	data, _ := json.Marshal(c)
+
+	return mcp.Cursor(base64.RawURLEncoding.EncodeToString(data))
+}
+
+// decodeCursor parses a cursor made by listCursor.encode.
+func decodeCursor(cursor mcp.Cursor) (listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(string(cursor))
+	if err != nil {
+		return listCursor{}, errors.New("not a cursor from this server")
+	}
+
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return listCursor{}, errors.New("not a cursor from this server")
+	}
+
+	return c, nil
+}
+
+// pageSize returns settings.page_size; zero lists everything in one page.
+func (a *Aggregator) pageSize() int {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return 0
+	}
+
+	return a.cfg.Settings.PageSize
+}
+
+// addPaginationHooks pages tools/list, resources/list,
+// resources/templates/list and prompts/list by settings.page_size, after
+// filtering and ordering.
+func (a *Aggregator) addPaginationHooks(hooks *server.Hooks) {
+	hooks.AddOnRequestInitialization(checkListCursor)
+
+	hooks.AddBeforeListTools(func(_ context.Context, _ any, req *mcp.ListToolsRequest) {
+		stashCursor(&req.Params)
+	})
+	hooks.AddAfterListTools(func(_ context.Context, _ any, req *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+		result.Tools, result.NextCursor = listPage(result.Tools, req.Params, a.pageSize())
+	})
+
+	hooks.AddBeforeListResources(func(_ context.Context, _ any, req *mcp.ListResourcesRequest) {
+		stashCursor(&req.Params)
+	})
+	hooks.AddAfterListResources(func(_ context.Context, _ any, req *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+		result.Resources, result.NextCursor = listPage(result.Resources, req.Params, a.pageSize())
+	})
+
+	hooks.AddBeforeListResourceTemplates(func(_ context.Context, _ any, req *mcp.ListResourceTemplatesRequest) {
+		stashCursor(&req.Params)
+	})
+	hooks.AddAfterListResourceTemplates(func(_ context.Context, _ any, req *mcp.ListResourceTemplatesRequest, result *mcp.ListResourceTemplatesResult) {
+		result.ResourceTemplates, result.NextCursor = listPage(result.ResourceTemplates, req.Params, a.pageSize())
+	})
+
+	hooks.AddBeforeListPrompts(func(_ context.Context, _ any, req *mcp.ListPromptsRequest) {
+		stashCursor(&req.Params)
+	})
+	hooks.AddAfterListPrompts(func(_ context.Context, _ any, req *mcp.ListPromptsRequest, result *mcp.ListPromptsResult) {
+		result.Prompts, result.NextCursor = listPage(result.Prompts, req.Params, a.pageSize())
+	})
+}
+
+// checkListCursor rejects list requests whose cursor this server did not
+// make, before they reach mcp-go.
+func checkListCursor(_ context.Context, _ any, message any) error {
+	raw, ok := message.(json.RawMessage)
+	if !ok || !bytes.Contains(raw, []byte(`"cursor"`)) {
+		return nil
+	}
+
+	var req struct {
+		Method mcp.MCPMethod       `json:"method"`
+		Params mcp.PaginatedParams `json:"params"`
+	}
+	if err := json.Unmarshal(raw, &req); err != nil || req.Params.Cursor == "" {
+		return nil
+	}
+
+	switch req.Method {
+	case mcp.MethodToolsList, mcp.MethodResourcesList, mcp.MethodResourcesTemplatesList, mcp.MethodPromptsList:
+	default:
+		return nil
+	}
+
+	if _, err := decodeCursor(req.Params.Cursor); err != nil {
+		return fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return nil
+}
+
+// stashCursor moves the cursor of a list request into its _meta, so mcp-go
+// returns the whole list for listPage to page.
+func stashCursor(params *mcp.PaginatedParams) {
+	if params.Cursor == "" {
+		return
+	}
+
+	if params.Meta == nil {
+		params.Meta = &mcp.Meta{}
+	}
+
+	if params.Meta.AdditionalFields == nil {
+		params.Meta.AdditionalFields = make(map[string]any)
+	}
+
+	params.Meta.AdditionalFields[cursorMetaKey] = string(params.Cursor)
+	params.Cursor = ""
+}
+
+// listPage returns the page of items that starts at the cursor stashCursor
+// kept in params, and the cursor of the next page, empty on the last. A
+// size of zero returns all the remaining items.
+func listPage[T mcp.Named](items []T, params mcp.PaginatedParams, size int) ([]T, mcp.Cursor) {
+	start := 0
+
+	if params.Meta != nil {
+		if raw, ok := params.Meta.AdditionalFields[cursorMetaKey].(string); ok {
+			// Checked by checkListCursor already
+			if c, err := decodeCursor(mcp.Cursor(raw)); err == nil {
+				start = resumeAt(items, c)
+			}
+		}
+	}
+
+	if size <= 0 || len(items)-start <= size {
+		return items[start:], ""
+	}
+
+	end := start + size
+
+	return items[start:end], listCursor{Name: items[end-1].GetName(), Offset: end}.encode()
+}
+
+// resumeAt returns the index after the item c names: c.Offset when it is
+// still there, else after its first occurrence. When it has gone, the
+// page resumes where it was, so items added or removed since the previous
+// page shift the next one as little as possible.
+func resumeAt[T mcp.Named](items []T, c listCursor) int {
+	if c.Offset > 0 && c.Offset <= len(items) && items[c.Offset-1].GetName() == c.Name {
+		return c.Offset
+	}
+
+	for i, item := range items {
+		if item.GetName() == c.Name {
+			return i + 1
+		}
+	}
+
+	return min(max(c.Offset-1, 0), len(items))
+}
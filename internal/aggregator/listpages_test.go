@@ -0,0 +1,167 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestListPagination(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.PageSize = 2
+	cfg.Servers["linear"] = &config.ServerConfig{Command: "linear", ToolPriority: map[string]int{"search": 5}}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, tool := range []struct{ server, name string }{
+		{"github", "create_issue"},
+		{"github", "search_repos"},
+		{"linear", "search"},
+	} {
+		if _, _, err := agg.tools.Register(tool.server, mcp.NewTool(tool.name), nil); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+
+	mcpServer := agg.CreateMCPServer()
+
+	list := func(cursor mcp.Cursor) (*mcp.ListToolsResult, *mcp.JSONRPCError) {
+		t.Helper()
+
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		raw, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list", "params": params})
+		if err != nil {
+			t.Fatalf("marshal tools/list: %v", err)
+		}
+
+		switch resp := mcpServer.HandleMessage(t.Context(), raw).(type) {
+		case mcp.JSONRPCResponse:
+			result, ok := resp.Result.(mcp.ListToolsResult)
+			if !ok {
+				t.Fatalf("tools/list result = %T", resp.Result)
+			}
+
+			return &result, nil
+		case mcp.JSONRPCError:
+			return nil, &resp
+		default:
+			t.Fatalf("tools/list response = %T", resp)
+		}
+
+		return nil, nil
+	}
+
+	var (
+		names  []string
+		cursor mcp.Cursor
+		pages  int
+	)
+
+	for {
+		result, rpcErr := list(cursor)
+		if rpcErr != nil {
+			t.Fatalf("tools/list page %d: %v", pages+1, rpcErr.Error)
+		}
+
+		pages++
+
+		if len(result.Tools) > cfg.Settings.PageSize {
+			t.Errorf("page %d has %d tools, want at most %d", pages, len(result.Tools), cfg.Settings.PageSize)
+		}
+
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+
+		if cursor = result.NextCursor; cursor == "" {
+			break
+		}
+	}
+
+	// Priority order survives paging: linear_search first, then by name
+	want := []string{"linear_search", ToolStatusName, "github_create_issue", "github_search_repos"}
+	slices.Sort(want[1:])
+
+	if !slices.Equal(names, want) || pages != 2 {
+		t.Errorf("paged tools/list = %v in %d pages, want %v in 2", names, pages, want)
+	}
+
+	if _, rpcErr := list("bm90LWEtY3Vyc29y"); rpcErr == nil {
+		t.Error("tools/list with a foreign cursor succeeded, want an error")
+	}
+}
+
+func TestListPage(t *testing.T) {
+	t.Parallel()
+
+	tools := []mcp.Tool{mcp.NewTool("a"), mcp.NewTool("b"), mcp.NewTool("c"), mcp.NewTool("d")}
+
+	withCursor := func(c listCursor) mcp.PaginatedParams {
+		var params mcp.PaginatedParams
+		params.Cursor = c.encode()
+		stashCursor(&params)
+
+		return params
+	}
+
+	names := func(tools []mcp.Tool) []string {
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		tools    []mcp.Tool
+		params   mcp.PaginatedParams
+		size     int
+		want     []string
+		wantNext bool
+	}{
+		{name: "unpaged", tools: tools, want: []string{"a", "b", "c", "d"}},
+		{name: "first page", tools: tools, size: 3, want: []string{"a", "b", "c"}, wantNext: true},
+		{name: "last page", tools: tools, params: withCursor(listCursor{Name: "c", Offset: 3}), size: 3, want: []string{"d"}},
+		{
+			name:   "item added before the cursor",
+			tools:  append([]mcp.Tool{mcp.NewTool("0")}, tools...),
+			params: withCursor(listCursor{Name: "b", Offset: 2}),
+			size:   2,
+			want:   []string{"c", "d"},
+		},
+		{
+			name:   "cursor item removed",
+			tools:  []mcp.Tool{tools[0], tools[2], tools[3]},
+			params: withCursor(listCursor{Name: "b", Offset: 2}),
+			size:   2,
+			want:   []string{"c", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page, next := listPage(tt.tools, tt.params, tt.size)
+			if got := names(page); !slices.Equal(got, tt.want) || (next != "") != tt.wantNext {
+				t.Errorf("listPage() = %v, next %q; want %v, next %v", got, next, tt.want, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package aggregator
+
+import (
+	"context"
+	"sync"
+)
+
+// loopGroup owns per-server background loops: health_check probes, crash
+// supervision and tool list change listeners. Loops outlive the startup context, so they hang off their own
+// root context that Stop cancels.
+type loopGroup struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	loops  map[string]context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newLoopGroup() *loopGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &loopGroup{
+		ctx:    ctx,
+		cancel: cancel,
+		loops:  make(map[string]context.CancelFunc),
+	}
+}
+
+// start launches run for name, replacing any loop already running for it.
+func (p *loopGroup) start(name string, run func(ctx context.Context)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cancel, ok := p.loops[name]; ok {
+		cancel()
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.loops[name] = cancel
+
+	p.wg.Go(func() { run(ctx) })
+}
+
+// stop cancels the loop for name, if any.
+func (p *loopGroup) stop(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cancel, ok := p.loops[name]; ok {
+		cancel()
+		delete(p.loops, name)
+	}
+}
+
+// stopAll cancels every loop and waits for them to exit. The group can be
+// reused afterwards.
+func (p *loopGroup) stopAll() {
+	p.mu.Lock()
+	p.cancel()
+	p.loops = make(map[string]context.CancelFunc)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+
+	p.mu.Lock()
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.mu.Unlock()
+}
@@ -0,0 +1,76 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// MaintenanceError is returned for calls to a server in one of its
+// maintenance windows (see config.MaintenanceWindow).
+type MaintenanceError struct {
+	Server  string
+	Message string
+	Until   time.Time // When the window ends
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("%s: %s (until %s)", e.Server, e.Message, e.Until.Format(time.RFC3339))
+}
+
+func (e *MaintenanceError) Unwrap() error { return ErrServerMaintenance }
+
+// inMaintenance returns the error for calls to server while cfg puts it in
+// a maintenance window, or nil.
+func (a *Aggregator) inMaintenance(server string, cfg *config.ServerConfig) *MaintenanceError {
+	window, until, ok := cfg.InMaintenance(a.clock.Now())
+	if !ok {
+		return nil
+	}
+
+	return &MaintenanceError{Server: server, Message: window.EffectiveMessage(), Until: until}
+}
+
+// maintenanceResult is the error result of a call refused during a
+// maintenance window, telling the client when to retry.
+func (a *Aggregator) maintenanceResult(err *MaintenanceError) *mcp.CallToolResult {
+	data := map[string]any{
+		"error":       "server_maintenance",
+		"server":      err.Server,
+		"message":     err.Message,
+		"until":       err.Until.Format(time.RFC3339),
+		"retry_after": int(a.clock.Until(err.Until).Round(time.Second).Seconds()),
+		"retriable":   true,
+	}
+
+	result := mcp.NewToolResultStructured(data, err.Error())
+	result.IsError = true
+
+	return result
+}
+
+// waitOutMaintenance blocks while cfg puts the server in a maintenance
+// window, or until ctx ends, and reports whether it waited at all.
+func (a *Aggregator) waitOutMaintenance(ctx context.Context, name string, cfg *config.ServerConfig) bool {
+	waited := false
+
+	for {
+		_, until, ok := cfg.InMaintenance(a.clock.Now())
+		if !ok {
+			return waited
+		}
+
+		waited = true
+		a.logger.Info("server in maintenance window, restart deferred", "server", name, "until", until)
+
+		select {
+		case <-ctx.Done():
+			return waited
+		case <-a.clock.After(a.clock.Until(until)):
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestMaintenanceWindowRefusesCalls(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		window config.MaintenanceWindow
+		open   bool
+	}{
+		// Every minute starts a window, so it is always open
+		{name: "open", window: config.MaintenanceWindow{Schedule: "* * * * *", Duration: time.Hour, Message: "redeploying"}, open: true},
+		// February 31st never comes
+		{name: "closed", window: config.MaintenanceWindow{Schedule: "0 0 31 2 *", Duration: time.Hour}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			mock := testutil.NewMockServer("wiki", []mcp.Tool{mcp.NewTool("search")})
+			mock.ServerCfg = &config.ServerConfig{Command: "wiki-mcp", Maintenance: []config.MaintenanceWindow{tt.window}}
+
+			if err := agg.AddServer(t.Context(), mock); err != nil {
+				t.Fatalf("AddServer: %v", err)
+			}
+
+			entry, _ := agg.tools.Get("wiki_search")
+
+			result, err := agg.createToolHandler(entry)(t.Context(), mcp.CallToolRequest{})
+			if err != nil {
+				t.Fatalf("handler: %v", err)
+			}
+
+			if result.IsError != tt.open || (len(mock.ToolCalls) == 0) != tt.open {
+				t.Fatalf("IsError = %v with %d backend calls, want the call refused = %v", result.IsError, len(mock.ToolCalls), tt.open)
+			}
+
+			status := agg.Status()
+			if state := status.Servers[0].State; (state == ServerStateMaintenance) != tt.open {
+				t.Errorf("state = %q", state)
+			}
+
+			if !tt.open {
+				return
+			}
+
+			data, _ := result.StructuredContent.(map[string]any)
+			if data["error"] != "server_maintenance" || data["message"] != "redeploying" || data["retriable"] != true {
+				t.Errorf("structured content = %v", data)
+			}
+
+			if status.Servers[0].MaintenanceUntil.IsZero() || status.ServersDown != 1 {
+				t.Errorf("status = %+v, want the server down until the window ends", status.Servers[0])
+			}
+		})
+	}
+}
+
+func TestWaitOutMaintenance(t *testing.T) {
+	t.Parallel()
+
+	// 03:10, ten minutes into the nightly window
+	clk := clock.NewFake(time.Date(2026, 3, 1, 3, 10, 0, 0, time.UTC))
+	agg := &Aggregator{logger: slog.New(slog.DiscardHandler), clock: clk}
+
+	closed := &config.ServerConfig{Maintenance: []config.MaintenanceWindow{{Schedule: "0 0 31 2 *", Duration: time.Hour}}}
+	if agg.waitOutMaintenance(t.Context(), "wiki", closed) {
+		t.Error("waited outside a maintenance window")
+	}
+
+	open := &config.ServerConfig{Maintenance: []config.MaintenanceWindow{{Schedule: "0 3 * * *", Duration: time.Hour, Timezone: "UTC"}}}
+
+	done := make(chan bool)
+	go func() { done <- agg.waitOutMaintenance(t.Context(), "wiki", open) }()
+
+	clk.BlockUntil(1)
+	clk.Advance(49 * time.Minute)
+
+	select {
+	case <-done:
+		t.Fatal("waitOutMaintenance returned before the window ended")
+	default:
+	}
+
+	clk.Advance(time.Minute)
+
+	if !<-done {
+		t.Error("waitOutMaintenance did not report waiting")
+	}
+
+	result := agg.maintenanceResult(&MaintenanceError{Until: clk.Now().Add(90 * time.Second)})
+	if data, _ := result.StructuredContent.(map[string]any); data["retry_after"] != 90 {
+		t.Errorf("retry_after = %v, want 90", data["retry_after"])
+	}
+}
+
+func TestWaitOutMaintenance_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	agg := &Aggregator{logger: slog.New(slog.DiscardHandler), clock: clock.NewFake(time.Now())}
+	open := &config.ServerConfig{Maintenance: []config.MaintenanceWindow{{Schedule: "* * * * *", Duration: time.Hour}}}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	if !agg.waitOutMaintenance(ctx, "wiki", open) {
+		t.Error("waitOutMaintenance did not report waiting")
+	}
+}
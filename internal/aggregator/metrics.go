@@ -0,0 +1,103 @@
+package aggregator
+
+import (
+	"context"
+	"time"
+)
+
+// Metric names emitted by the aggregator.
+const (
+	MetricToolCallDuration = "tool.call.duration"
+	MetricToolCallCount    = "tool.call.count"
+	MetricServerHealthy    = "server.healthy"
+	MetricServerFailures   = "server.consecutive_failures"
+	MetricServersActive    = "servers.active"
+)
+
+// Metrics receives aggregator measurements. internal/metrics provides a
+// statsd/DogStatsD implementation; a nil Metrics disables reporting.
+type Metrics interface {
+	Timing(name string, d time.Duration, tags map[string]string)
+	Count(name string, n int64, tags map[string]string)
+	Gauge(name string, v float64, tags map[string]string)
+}
+
+// recordToolCall reports the latency and outcome of one backend tool call.
+func (a *Aggregator) recordToolCall(entry *ToolEntry, d time.Duration, err error) {
+	if a.metrics == nil {
+		return
+	}
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	tags := a.metricTags(map[string]string{
+		"server": entry.ServerName,
+		"tool":   entry.Tool.Name,
+		"status": status,
+	})
+
+	a.metrics.Timing(MetricToolCallDuration, d, tags)
+	a.metrics.Count(MetricToolCallCount, 1, tags)
+}
+
+// startMetricsReporter periodically reports server health gauges until Stop.
+func (a *Aggregator) startMetricsReporter(interval time.Duration) {
+	if a.metrics == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopMetrics = cancel
+
+	go func() {
+		ticker := a.clock.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				a.reportHealthMetrics()
+			}
+		}
+	}()
+}
+
+// reportHealthMetrics emits one round of server health gauges.
+func (a *Aggregator) reportHealthMetrics() {
+	names := a.ServerNames()
+
+	a.metrics.Gauge(MetricServersActive, float64(len(names)), a.metricTags(nil))
+
+	for _, name := range names {
+		stats := a.health.Stats(name)
+		tags := a.metricTags(map[string]string{"server": name})
+
+		healthy := 1.0
+		if stats.Status.down() {
+			healthy = 0
+		}
+
+		a.metrics.Gauge(MetricServerHealthy, healthy, tags)
+		a.metrics.Gauge(MetricServerFailures, float64(stats.ConsecutiveFailures), tags)
+	}
+}
+
+// metricTags adds the instance_id tag to tags when an instance ID is set.
+func (a *Aggregator) metricTags(tags map[string]string) map[string]string {
+	if a.instanceID == "" {
+		return tags
+	}
+
+	if tags == nil {
+		tags = make(map[string]string, 1)
+	}
+
+	tags["instance_id"] = a.instanceID
+
+	return tags
+}
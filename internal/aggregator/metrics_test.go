@@ -0,0 +1,160 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+type recordedMetric struct {
+	kind  string
+	name  string
+	value float64
+	tags  map[string]string
+}
+
+// fakeMetrics records every measurement for assertions.
+type fakeMetrics struct {
+	mu      sync.Mutex
+	metrics []recordedMetric
+}
+
+func (f *fakeMetrics) add(m recordedMetric) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.metrics = append(f.metrics, m)
+}
+
+func (f *fakeMetrics) Timing(name string, d time.Duration, tags map[string]string) {
+	f.add(recordedMetric{kind: "timing", name: name, value: float64(d), tags: tags})
+}
+
+func (f *fakeMetrics) Count(name string, n int64, tags map[string]string) {
+	f.add(recordedMetric{kind: "count", name: name, value: float64(n), tags: tags})
+}
+
+func (f *fakeMetrics) Gauge(name string, v float64, tags map[string]string) {
+	f.add(recordedMetric{kind: "gauge", name: name, value: v, tags: tags})
+}
+
+func (f *fakeMetrics) find(name string, tags map[string]string) (recordedMetric, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, m := range f.metrics {
+		if m.name != name {
+			continue
+		}
+
+		match := true
+		for k, v := range tags {
+			if m.tags[k] != v {
+				match = false
+			}
+		}
+
+		if match {
+			return m, true
+		}
+	}
+
+	return recordedMetric{}, false
+}
+
+func newMetricsAggregator(t *testing.T, sink Metrics, mock *testutil.MockServer) *Aggregator {
+	t.Helper()
+
+	agg, err := New(Options{
+		Config:  config.NewConfig(),
+		Logger:  slog.New(slog.DiscardHandler),
+		Metrics: sink,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	return agg
+}
+
+func TestToolCallMetrics(t *testing.T) {
+	t.Parallel()
+
+	sink := &fakeMetrics{}
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	agg := newMetricsAggregator(t, sink, mock)
+
+	entry, ok := agg.tools.Get("github_search")
+	if !ok {
+		t.Fatal("tool not registered")
+	}
+
+	handler := agg.createToolHandler(entry)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{}
+
+	if _, err := handler(t.Context(), req); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	okTags := map[string]string{"server": "github", "tool": "search", "status": "ok"}
+	if _, found := sink.find(MetricToolCallDuration, okTags); !found {
+		t.Error("missing tool call duration metric")
+	}
+
+	if m, found := sink.find(MetricToolCallCount, okTags); !found || m.value != 1 {
+		t.Errorf("tool call count = %+v, found %v", m, found)
+	}
+
+	mock.CallErr = errors.New("boom")
+
+	if _, err := handler(t.Context(), req); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	if _, found := sink.find(MetricToolCallCount, map[string]string{"status": "error"}); !found {
+		t.Error("missing error count metric")
+	}
+}
+
+func TestReportHealthMetrics(t *testing.T) {
+	t.Parallel()
+
+	sink := &fakeMetrics{}
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search")})
+	agg := newMetricsAggregator(t, sink, mock)
+
+	for range DefaultHealthThreshold {
+		agg.health.RecordFailure("github")
+	}
+
+	agg.reportHealthMetrics()
+
+	if m, found := sink.find(MetricServersActive, nil); !found || m.value != 1 {
+		t.Errorf("servers.active = %+v, found %v", m, found)
+	}
+
+	if m, found := sink.find(MetricServerHealthy, map[string]string{"server": "github"}); !found || m.value != 0 {
+		t.Errorf("server.healthy = %+v, found %v; want 0", m, found)
+	}
+
+	if m, found := sink.find(MetricServerFailures, map[string]string{"server": "github"}); !found || m.value != DefaultHealthThreshold {
+		t.Errorf("server.consecutive_failures = %+v, found %v", m, found)
+	}
+}
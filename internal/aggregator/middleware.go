@@ -0,0 +1,82 @@
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+)
+
+// ToolHandler handles a call to one aggregated tool. A failing tool is
+// reported in the result (IsError); an error means the call could not be
+// handled at all.
+type ToolHandler func(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// ToolMiddleware wraps the handling of tool calls, e.g. to log, time, limit
+// or redact them. It sees every call to an aggregated tool from MCP clients
+// and 'assern call', but not calls to the assern_* meta-tools nor the
+// backend calls of code-mode scripts.
+type ToolMiddleware func(next ToolHandler) ToolHandler
+
+// chainToolMiddleware wraps handler in middleware; the first middleware is
+// the outermost, so it sees the call first and the result last.
+func chainToolMiddleware(handler ToolHandler, middleware []ToolMiddleware) ToolHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			handler = middleware[i](handler)
+		}
+	}
+
+	return handler
+}
+
+// LoggingMiddleware logs every tool call at debug level with its server,
+// duration (timed by clk; nil is the system clock) and outcome.
+func LoggingMiddleware(logger *slog.Logger, clk clock.Clock) ToolMiddleware {
+	clk = clock.OrReal(clk)
+
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := clk.Now()
+			result, err := next(ctx, entry, req)
+
+			attrs := []any{
+				"tool", entry.PrefixedName,
+				"server", entry.ServerName,
+				"duration", clk.Since(start),
+			}
+
+			switch {
+			case err != nil:
+				attrs = append(attrs, "error", err)
+			case result != nil && result.IsError:
+				attrs = append(attrs, "is_error", true)
+			}
+
+			logger.DebugContext(ctx, "tool call", attrs...)
+
+			return result, err
+		}
+	}
+}
+
+// TimingMiddleware reports how long each tool call took, as timed by clk
+// (nil is the system clock), to observe, with whether it failed (an error or
+// an IsError result).
+func TimingMiddleware(clk clock.Clock, observe func(entry *ToolEntry, elapsed time.Duration, failed bool)) ToolMiddleware {
+	clk = clock.OrReal(clk)
+
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := clk.Now()
+			result, err := next(ctx, entry, req)
+
+			observe(entry, clk.Since(start), err != nil || (result != nil && result.IsError))
+
+			return result, err
+		}
+	}
+}
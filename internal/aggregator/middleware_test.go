@@ -0,0 +1,146 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// tracingMiddleware appends name to trace before and after each call.
+func tracingMiddleware(name string, trace *[]string) ToolMiddleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			*trace = append(*trace, name+">")
+			result, err := next(ctx, entry, req)
+			*trace = append(*trace, "<"+name)
+
+			return result, err
+		}
+	}
+}
+
+func TestToolMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		trace   []string
+		timings []string
+	)
+
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	timing := TimingMiddleware(fake, func(entry *ToolEntry, elapsed time.Duration, failed bool) {
+		outcome := "ok"
+		if failed {
+			outcome = "failed"
+		}
+
+		timings = append(timings, fmt.Sprintf("%s %s %v", entry.PrefixedName, outcome, elapsed))
+	})
+
+	// Takes a second, and short-circuits calls to github_delete without
+	// reaching the backend
+	deny := func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			fake.Advance(time.Second)
+
+			if entry.Tool.Name == "delete" {
+				return mcp.NewToolResultError("denied"), nil
+			}
+
+			return next(ctx, entry, req)
+		}
+	}
+
+	agg, err := New(Options{
+		Config:         config.NewConfig(),
+		Logger:         slog.New(slog.DiscardHandler),
+		ToolMiddleware: []ToolMiddleware{tracingMiddleware("outer", &trace), nil, timing, tracingMiddleware("inner", &trace), deny},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search"), mcp.NewTool("delete")})
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	if _, err := agg.CallTool(t.Context(), "github_search", nil); err != nil {
+		t.Fatalf("CallTool(github_search): %v", err)
+	}
+
+	if want := []string{"outer>", "inner>", "<inner", "<outer"}; !slices.Equal(trace, want) {
+		t.Errorf("trace = %v, want %v", trace, want)
+	}
+
+	result, err := agg.CallTool(t.Context(), "github_delete", nil)
+	if err != nil || !result.IsError {
+		t.Fatalf("CallTool(github_delete) = %+v, %v, want the middleware's error result", result, err)
+	}
+
+	if len(mock.ToolCalls) != 1 {
+		t.Errorf("backend calls = %+v, want only search", mock.ToolCalls)
+	}
+
+	if want := []string{"github_search ok 1s", "github_delete failed 1s"}; !slices.Equal(timings, want) {
+		t.Errorf("timings = %v, want %v", timings, want)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	entry := &ToolEntry{PrefixedName: "github_search", ServerName: "github", Tool: mcp.NewTool("search")}
+
+	handlers := []ToolHandler{
+		func(context.Context, *ToolEntry, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		},
+		func(context.Context, *ToolEntry, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("no such repository"), nil
+		},
+		func(context.Context, *ToolEntry, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+
+	for _, handler := range handlers {
+		_, _ = LoggingMiddleware(logger, clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))(handler)(t.Context(), entry, mcp.CallToolRequest{})
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("logged %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	for i, want := range []string{"", "is_error=true", `error="connection reset"`} {
+		line := lines[i]
+		if !strings.Contains(line, "msg=\"tool call\" tool=github_search server=github duration=0s") {
+			t.Errorf("line %d = %s", i, line)
+		}
+
+		if want != "" && !strings.Contains(line, want) {
+			t.Errorf("line %d = %s, want %s", i, line, want)
+		}
+
+		if want == "" && strings.Contains(line, "error") {
+			t.Errorf("line %d = %s, want no error", i, line)
+		}
+	}
+}
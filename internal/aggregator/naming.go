@@ -0,0 +1,89 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// registerTools registers the tools of a server that pass its tool rules,
+// named by the registry's naming and the server's prefix. A tool of another
+// server moved aside by a name collision is exposed again under its new
+// name. Tools refused under prefix_collision "error" are reported together;
+// the server's other tools are registered regardless.
+func (a *Aggregator) registerTools(serverName string, cfg *config.ServerConfig, tools []mcp.Tool) error {
+	var prefix string
+	if cfg != nil {
+		if err := config.ValidatePrefix(cfg.Prefix); err != nil {
+			return fmt.Errorf("prefix %q: %w", cfg.Prefix, err)
+		}
+
+		prefix = cfg.Prefix
+	}
+
+	naming := a.tools.Naming()
+	rules := cfg.ToolRules()
+
+	var (
+		errs  []error
+		moved []*ToolEntry
+	)
+
+	for _, tool := range tools {
+		if !rules.Allows(serverName, tool.Name) {
+			continue
+		}
+
+		name := naming.ToolName(serverName, prefix, tool.Name)
+
+		entry, m, err := a.tools.RegisterAs(serverName, a.withInlinedRefs(serverName, withDeprecation(a.withBudget(tool, cfg), cfg)), name)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if entry.PrefixedName != name {
+			a.logger.Warn("tool name taken by another server, suffixed",
+				"server", serverName, "tool", tool.Name, "name", entry.PrefixedName)
+		}
+
+		if m != nil {
+			a.logger.Warn("tool name taken by a server that sorts first, suffixed",
+				"server", m.ServerName, "tool", m.Tool.Name, "name", m.PrefixedName)
+
+			moved = append(moved, m)
+		}
+	}
+
+	a.exposeTools(moved)
+
+	return errors.Join(errs...)
+}
+
+// loadAliases replaces the tool aliases with those of the effective settings
+// (global, project and local config.yaml). An alias whose target is not
+// registered is kept, since its server may still come up, but logged.
+func (a *Aggregator) loadAliases(settings *config.Settings) {
+	var aliases map[string]string
+	if settings != nil {
+		aliases = settings.Aliases
+	}
+
+	a.tools.SetAliases(aliases)
+
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		if _, ok := a.tools.Get(alias); !ok {
+			a.logger.Warn("alias target not found", "alias", alias, "tool", aliases[alias])
+		}
+	}
+
+	if len(aliases) > 0 {
+		a.logger.Debug("loaded tool aliases", "count", len(aliases))
+	}
+}
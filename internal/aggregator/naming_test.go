@@ -0,0 +1,124 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestAddServerNamesTools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		settings  config.Settings
+		prefixes  map[string]string
+		want      map[string]string // exposed name -> server
+		wantError string            // server whose AddServer fails
+	}{
+		{
+			name:     "server prefix",
+			settings: config.Settings{},
+			want:     map[string]string{"github_search": "github", "gitlab_search": "gitlab", "gitlab_merge": "gitlab"},
+		},
+		{
+			name:     "none suffixes collisions",
+			settings: config.Settings{PrefixStrategy: config.PrefixStrategyNone},
+			want:     map[string]string{"search": "github", "search_gitlab": "gitlab", "merge": "gitlab"},
+		},
+		{
+			name:     "per-server prefix under none",
+			settings: config.Settings{PrefixStrategy: config.PrefixStrategyNone},
+			prefixes: map[string]string{"gitlab": "gl"},
+			want:     map[string]string{"search": "github", "gl_search": "gitlab", "gl_merge": "gitlab"},
+		},
+		{
+			name:     "template",
+			settings: config.Settings{PrefixStrategy: "{tool}.{server}"},
+			want:     map[string]string{"search.github": "github", "search.gitlab": "gitlab", "merge.gitlab": "gitlab"},
+		},
+		{
+			name:      "collision error",
+			settings:  config.Settings{PrefixStrategy: config.PrefixStrategyNone, PrefixCollision: config.PrefixCollisionError},
+			want:      map[string]string{"search": "github"},
+			wantError: "gitlab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.NewConfig()
+			cfg.Settings = &tt.settings
+
+			agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			servers := map[string][]mcp.Tool{
+				"github": {{Name: "search"}},
+				"gitlab": {{Name: "search"}, {Name: "merge"}},
+			}
+
+			for _, name := range []string{"github", "gitlab"} {
+				mock := testutil.NewMockServer(name, servers[name])
+				mock.ServerCfg.Prefix = tt.prefixes[name]
+
+				err := agg.AddServer(t.Context(), mock)
+				if name == tt.wantError {
+					if !errors.Is(err, ErrToolNameCollision) {
+						t.Fatalf("AddServer(%s) error = %v, want ErrToolNameCollision", name, err)
+					}
+
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("AddServer(%s): %v", name, err)
+				}
+			}
+
+			got := make(map[string]string)
+			for _, entry := range agg.tools.All() {
+				got[entry.PrefixedName] = entry.ServerName
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("tools = %v, want %v", got, tt.want)
+			}
+
+			for name, server := range tt.want {
+				if got[name] != server {
+					t.Errorf("tool %s belongs to %q, want %q", name, got[name], server)
+				}
+			}
+		})
+	}
+}
+
+func TestAddServerRejectsInvalidPrefix(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{{Name: "search"}})
+	mock.ServerCfg.Prefix = "git hub"
+
+	if err := agg.AddServer(t.Context(), mock); err == nil {
+		t.Fatal("AddServer() expected error for invalid prefix")
+	}
+
+	if agg.tools.Count() != 0 {
+		t.Errorf("Count() = %d, want 0", agg.tools.Count())
+	}
+}
@@ -0,0 +1,73 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxDiscoveryPages bounds how many pages of tools, resources or prompts
+// are fetched from one backend, so a server that keeps returning cursors
+// cannot stall discovery.
+const maxDiscoveryPages = 100
+
+// fetchPage fetches the page of a list starting at cursor, returning its
+// items and the cursor of the next page (empty on the last page).
+type fetchPage[T any] func(ctx context.Context, cursor mcp.Cursor) ([]T, mcp.Cursor, error)
+
+// collectPages follows nextCursor until the last page and returns all items.
+// It stops early, keeping what it has, when a cursor repeats or after
+// maxDiscoveryPages pages, and logs a warning naming what was left out.
+func collectPages[T any](ctx context.Context, logger *slog.Logger, kind string, fetch fetchPage[T]) ([]T, error) {
+	var (
+		items  []T
+		cursor mcp.Cursor
+		seen   = make(map[mcp.Cursor]bool)
+	)
+
+	for page := 1; ; page++ {
+		batch, next, err := fetch(ctx, cursor)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		items = append(items, batch...)
+
+		if next == "" {
+			if page > 1 {
+				logger.Debug("discovered "+kind+" across pages", "pages", page, "count", len(items))
+			}
+
+			return items, nil
+		}
+
+		if seen[next] {
+			logger.Warn("backend repeated a "+kind+" cursor; keeping the pages fetched so far",
+				"pages", page, "count", len(items))
+
+			return items, nil
+		}
+
+		if page >= maxDiscoveryPages {
+			logger.Warn("backend has more "+kind+" than the page limit; the rest are not aggregated",
+				"pages", page, "count", len(items))
+
+			return items, nil
+		}
+
+		logger.Debug("fetching next page of "+kind, "page", page+1, "count", len(items))
+
+		seen[next] = true
+		cursor = next
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
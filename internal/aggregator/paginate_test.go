@@ -0,0 +1,115 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCollectPages(t *testing.T) {
+	t.Parallel()
+
+	errBackend := errors.New("backend failed")
+
+	// pages returns a fetcher serving n pages of one item each, whose cursors
+	// are produced by next.
+	pages := func(n int, next func(page int) mcp.Cursor) fetchPage[int] {
+		return func(_ context.Context, cursor mcp.Cursor) ([]int, mcp.Cursor, error) {
+			page := 0
+			if cursor != "" {
+				_, _ = fmt.Sscanf(string(cursor), "page-%d", &page)
+			}
+
+			if page+1 >= n {
+				return []int{page}, "", nil
+			}
+
+			return []int{page}, next(page), nil
+		}
+	}
+	sequential := func(page int) mcp.Cursor { return mcp.Cursor(fmt.Sprintf("page-%d", page+1)) }
+
+	tests := []struct {
+		name    string
+		fetch   fetchPage[int]
+		want    int
+		wantErr error
+	}{
+		{
+			name:  "single page",
+			fetch: pages(1, sequential),
+			want:  1,
+		},
+		{
+			name:  "follows cursors",
+			fetch: pages(5, sequential),
+			want:  5,
+		},
+		{
+			name:  "stops on repeated cursor",
+			fetch: pages(10, func(int) mcp.Cursor { return "page-1" }),
+			want:  2,
+		},
+		{
+			name:  "stops at page limit",
+			fetch: pages(maxDiscoveryPages+10, sequential),
+			want:  maxDiscoveryPages,
+		},
+		{
+			name: "error on later page",
+			fetch: func(_ context.Context, cursor mcp.Cursor) ([]int, mcp.Cursor, error) {
+				if cursor != "" {
+					return nil, "", errBackend
+				}
+
+				return []int{0}, "page-1", nil
+			},
+			wantErr: errBackend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := collectPages(t.Context(), slog.New(slog.DiscardHandler), "items", tt.fetch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("collectPages() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("collectPages() returned %d items, want %d", len(got), tt.want)
+			}
+
+			if !slices.IsSorted(got) {
+				t.Errorf("collectPages() items out of page order: %v", got)
+			}
+		})
+	}
+}
+
+func TestDiscoverToolsFollowsPages(t *testing.T) {
+	t.Parallel()
+
+	srv := newHelperServer(t, "")
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	tools, err := srv.DiscoverTools(t.Context())
+	if err != nil {
+		t.Fatalf("DiscoverTools: %v", err)
+	}
+
+	// The helper serves two tools per page
+	if len(tools) != 5 {
+		t.Errorf("DiscoverTools returned %d tools, want 5", len(tools))
+	}
+}
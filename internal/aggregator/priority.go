@@ -0,0 +1,95 @@
+package aggregator
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// toolPriority returns the priority of a backend tool: its tool_priority
+// entry, or else the server-wide priority.
+func toolPriority(cfg *config.ServerConfig, tool string) int {
+	if cfg == nil {
+		return 0
+	}
+
+	if p, ok := cfg.ToolPriority[tool]; ok {
+		return p
+	}
+
+	return cfg.Priority
+}
+
+// orderTools is the tools/list filter that moves tools with a higher
+// priority first. mcp-go hands it the tools sorted by name, and the sort is
+// stable, so tools of equal priority stay alphabetical. Tools with a
+// positive priority get settings.priority_marker prefixed to their title.
+//
+// mcp-go's list cursors assume alphabetical order, so assern pages the
+// reordered list itself (see addPaginationHooks).
+func (a *Aggregator) orderTools(_ context.Context, tools []mcp.Tool) []mcp.Tool {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil {
+		return tools
+	}
+
+	var marker string
+	if a.cfg.Settings != nil {
+		marker = a.cfg.Settings.PriorityMarker
+	}
+
+	priorities := make(map[string]int, len(tools))
+	backend := make(map[string]mcp.Tool, len(tools))
+
+	for _, tool := range tools {
+		entry, ok := a.tools.Get(tool.Name)
+		if !ok {
+			continue // Meta-tool
+		}
+
+		if p := toolPriority(a.cfg.Servers[entry.ServerName], entry.Tool.Name); p != 0 {
+			priorities[tool.Name] = p
+			backend[tool.Name] = entry.Tool
+		}
+	}
+
+	if len(priorities) == 0 {
+		return tools
+	}
+
+	ordered := slices.Clone(tools)
+	slices.SortStableFunc(ordered, func(x, y mcp.Tool) int {
+		return cmp.Compare(priorities[y.Name], priorities[x.Name])
+	})
+
+	if marker == "" {
+		return ordered
+	}
+
+	for i, tool := range ordered {
+		if priorities[tool.Name] > 0 {
+			ordered[i].Title = marker + toolTitle(backend[tool.Name], tool.Name)
+		}
+	}
+
+	return ordered
+}
+
+// toolTitle returns the display name of a backend tool as clients pick it:
+// its title, else its annotations' title, else name (the exposed name).
+func toolTitle(tool mcp.Tool, name string) string {
+	switch {
+	case tool.Title != "":
+		return tool.Title
+	case tool.Annotations.Title != "":
+		return tool.Annotations.Title
+	default:
+		return name
+	}
+}
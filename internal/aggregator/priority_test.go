@@ -0,0 +1,102 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestToolPriority(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ServerConfig{
+		Priority:     5,
+		ToolPriority: map[string]int{"search": 10, "delete": 0},
+	}
+
+	tests := []struct {
+		cfg  *config.ServerConfig
+		tool string
+		want int
+	}{
+		{cfg, "search", 10},
+		{cfg, "delete", 0},
+		{cfg, "issues", 5},
+		{&config.ServerConfig{ToolPriority: map[string]int{"search": -1}}, "search", -1},
+		{nil, "search", 0},
+	}
+
+	for _, tt := range tests {
+		if got := toolPriority(tt.cfg, tt.tool); got != tt.want {
+			t.Errorf("toolPriority(%q) = %d, want %d", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestOrderTools(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.PriorityMarker = "★ "
+	cfg.Servers["github"] = &config.ServerConfig{
+		Command:      "github-mcp",
+		ToolPriority: map[string]int{"search": 10, "delete": -1},
+	}
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "jira-mcp", Priority: 5}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	titled := mcp.NewTool("issues", mcp.WithTitleAnnotation("Jira issues"))
+
+	for _, mock := range []*testutil.MockServer{
+		testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("delete"), mcp.NewTool("fetch"), mcp.NewTool("search")}),
+		testutil.NewMockServer("jira", []mcp.Tool{titled, mcp.NewTool("comment")}),
+	} {
+		mock.ServerCfg = cfg.Servers[mock.Name()]
+
+		if err := agg.AddServer(t.Context(), mock); err != nil {
+			t.Fatalf("AddServer: %v", err)
+		}
+	}
+
+	srv := agg.CreateMCPServer()
+
+	resp := srv.HandleMessage(t.Context(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("encoding response: %v", err)
+	}
+
+	var list struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+
+	var names, titles []string
+	for _, tool := range list.Result.Tools {
+		names = append(names, tool.Name)
+		titles = append(titles, tool.Title)
+	}
+
+	wantNames := []string{"github_search", "jira_comment", "jira_issues", "assern_status", "github_fetch", "github_delete"}
+	if !slices.Equal(names, wantNames) {
+		t.Errorf("tools/list order = %v, want %v", names, wantNames)
+	}
+
+	wantTitles := []string{"★ github_search", "★ jira_comment", "★ Jira issues", "", "", ""}
+	if !slices.Equal(titles, wantTitles) {
+		t.Errorf("tools/list titles = %q, want %q", titles, wantTitles)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
 )
 
 // PromptEntry represents a prompt from a backend server.
@@ -13,7 +15,8 @@ type PromptEntry struct {
 	ServerName string
 	// Prompt is the original prompt definition.
 	Prompt mcp.Prompt
-	// PrefixedName is the prompt name with server prefix.
+	// PrefixedName is the prompt name with server prefix. It is built from
+	// the configured rename when there is one; Prompt keeps the backend's name.
 	PrefixedName string
 }
 
@@ -32,7 +35,13 @@ func NewPromptRegistry() *PromptRegistry {
 
 // Register adds a prompt from a server to the registry.
 func (r *PromptRegistry) Register(serverName string, prompt mcp.Prompt) {
-	prefixedName := PrefixPromptName(serverName, prompt.Name)
+	r.RegisterAs(serverName, prompt, prompt.Name)
+}
+
+// RegisterAs adds a prompt from a server under another name. Gets of the
+// prefixed name are still routed to the backend's prompt name.
+func (r *PromptRegistry) RegisterAs(serverName string, prompt mcp.Prompt, name string) {
+	prefixedName := PrefixPromptName(serverName, name)
 
 	entry := &PromptEntry{
 		ServerName:   serverName,
@@ -82,6 +91,35 @@ func (r *PromptRegistry) RemoveServer(serverName string) {
 	})
 }
 
+// registerPrompts registers the prompts of a server that pass its prompt
+// filter, under their configured names, and returns how many were
+// registered. A prompt whose name is already taken by another prompt of
+// the server is skipped.
+func (a *Aggregator) registerPrompts(serverName string, prompts []mcp.Prompt, filter *config.PromptFilter) int {
+	registered := make(map[string]string, len(prompts))
+
+	for _, prompt := range prompts {
+		if !filter.Exposes(prompt.Name) {
+			continue
+		}
+
+		name := filter.ExposedName(prompt.Name)
+		prefixed := PrefixPromptName(serverName, name)
+
+		if original, taken := registered[prefixed]; taken {
+			a.logger.Warn("prompt name collides with another prompt, skipped",
+				"server", serverName, "prompt", prompt.Name, "name", name, "other", original)
+
+			continue
+		}
+
+		registered[prefixed] = prompt.Name
+		a.prompts.RegisterAs(serverName, prompt, name)
+	}
+
+	return len(registered)
+}
+
 // PrefixPromptName creates a prefixed prompt name from server and prompt names.
 // Example: ("github", "create-issue") -> "github_create_issue".
 func PrefixPromptName(serverName, promptName string) string {
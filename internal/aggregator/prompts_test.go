@@ -2,10 +2,14 @@ package aggregator
 
 import (
 	"errors"
+	"log/slog"
 	"sync"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
 )
 
 func TestPromptRegistry_Register(t *testing.T) {
@@ -256,3 +260,53 @@ func TestPromptRegistry_ConcurrentAccess(t *testing.T) {
 
 	// No race conditions should occur
 }
+
+func TestAddServerFiltersAndRenamesPrompts(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("docs", nil)
+	mock.ServerCfg.Prompts = &config.PromptFilter{
+		Blocked: []string{"debug"},
+		Rename:  map[string]string{"code-review": "review", "summary": "review"},
+	}
+	mock.Prompts = []mcp.Prompt{
+		{Name: "code-review"},
+		{Name: "debug"},
+		{Name: "summary"},
+		{Name: "explain"},
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, entry := range agg.prompts.GetByServer("docs") {
+		got[entry.PrefixedName] = entry.Prompt.Name
+	}
+
+	want := map[string]string{"docs_review": "code-review", "docs_explain": "explain"}
+	if len(got) != len(want) {
+		t.Fatalf("registered prompts = %v, want %v", got, want)
+	}
+
+	for name, original := range want {
+		if got[name] != original {
+			t.Errorf("prompt %s routes to %q, want %q", name, got[name], original)
+		}
+	}
+
+	entry, _ := agg.prompts.Get("docs_review")
+	if _, err := agg.createPromptHandler(entry)(t.Context(), mcp.GetPromptRequest{}); err != nil {
+		t.Fatalf("getting renamed prompt: %v", err)
+	}
+
+	if len(mock.PromptGets) != 1 || mock.PromptGets[0].Name != "code-review" {
+		t.Errorf("backend gets = %+v, want one get of code-review", mock.PromptGets)
+	}
+}
@@ -0,0 +1,63 @@
+package aggregator
+
+import (
+	"sync"
+
+	"github.com/valksor/go-assern/internal/audit"
+)
+
+// recentCallsSize is how many tool calls RecentCalls remembers.
+const recentCallsSize = 50
+
+// recentCalls keeps the last recentCallsSize tool calls in memory, without
+// arguments, for the web UI.
+type recentCalls struct {
+	mu      sync.Mutex
+	records []audit.Record
+	next    int // Index the next record overwrites once records is full
+}
+
+func newRecentCalls() *recentCalls {
+	return &recentCalls{records: make([]audit.Record, 0, recentCallsSize)}
+}
+
+func (r *recentCalls) add(rec audit.Record) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.records) < recentCallsSize {
+		r.records = append(r.records, rec)
+
+		return
+	}
+
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % recentCallsSize
+}
+
+// list returns the remembered calls, newest first.
+func (r *recentCalls) list() []audit.Record {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]audit.Record, 0, len(r.records))
+	for i := range r.records {
+		out = append(out, r.records[(r.next+len(r.records)-1-i)%len(r.records)])
+	}
+
+	return out
+}
+
+// RecentCalls returns the most recent tools/call requests, newest first,
+// whether or not the audit log is enabled. Arguments are never kept.
+func (a *Aggregator) RecentCalls() []audit.Record {
+	return a.recent.list()
+}
@@ -0,0 +1,56 @@
+package aggregator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/audit"
+)
+
+func TestRecentCalls(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		added     int
+		wantLen   int
+		wantFirst string // Newest
+		wantLast  string // Oldest kept
+	}{
+		{name: "empty"},
+		{name: "partly filled", added: 3, wantLen: 3, wantFirst: "tool2", wantLast: "tool0"},
+		{name: "exactly full", added: recentCallsSize, wantLen: recentCallsSize, wantFirst: fmt.Sprintf("tool%d", recentCallsSize-1), wantLast: "tool0"},
+		{name: "wrapped", added: recentCallsSize + 7, wantLen: recentCallsSize, wantFirst: fmt.Sprintf("tool%d", recentCallsSize+6), wantLast: "tool7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newRecentCalls()
+			for i := range tt.added {
+				r.add(audit.Record{Tool: fmt.Sprintf("tool%d", i)})
+			}
+
+			got := r.list()
+			if len(got) != tt.wantLen {
+				t.Fatalf("len = %d, want %d", len(got), tt.wantLen)
+			}
+
+			if tt.wantLen > 0 && (got[0].Tool != tt.wantFirst || got[len(got)-1].Tool != tt.wantLast) {
+				t.Errorf("newest %q, oldest %q; want %q, %q", got[0].Tool, got[len(got)-1].Tool, tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestRecentCalls_Nil(t *testing.T) {
+	t.Parallel()
+
+	var r *recentCalls
+	r.add(audit.Record{Tool: "x"})
+
+	if got := r.list(); got != nil {
+		t.Errorf("nil list = %v", got)
+	}
+}
@@ -39,6 +39,24 @@ func (r *registry[E, K]) register(serverName string, entry E, prefixFunc func(st
 	r.cacheValid = false // invalidate cache
 }
 
+// rekey moves a server's entry from oldKey to newKey, storing entry (a
+// copy carrying the new key) in place of the old one.
+func (r *registry[E, K]) rekey(serverName string, oldKey, newKey K, entry E, keyFunc func(E) K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, oldKey)
+	r.entries[newKey] = entry
+
+	for i, e := range r.byServer[serverName] {
+		if keyFunc(e) == oldKey {
+			r.byServer[serverName][i] = entry
+		}
+	}
+
+	r.cacheValid = false // invalidate cache
+}
+
 // get retrieves an entry by its prefixed key.
 func (r *registry[E, K]) get(key K) (E, bool) {
 	r.mu.RLock()
@@ -2,6 +2,7 @@ package aggregator_test
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
@@ -198,3 +199,20 @@ func TestReloadResult_Fields(t *testing.T) {
 		t.Errorf("expected 2 errors, got %d", len(result.Errors))
 	}
 }
+
+func TestAggregator_Reload_FixedConfig(t *testing.T) {
+	t.Parallel()
+
+	agg, err := aggregator.New(aggregator.Options{
+		Config:      &config.Config{Servers: map[string]*config.ServerConfig{}, Settings: config.DefaultSettings()},
+		Logger:      slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		FixedConfig: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	if _, err := agg.Reload(context.Background()); !errors.Is(err, aggregator.ErrConfigNotReloadable) {
+		t.Errorf("Reload() error = %v, want ErrConfigNotReloadable", err)
+	}
+}
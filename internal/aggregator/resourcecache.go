@@ -0,0 +1,240 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// resourceCache stores resource reads of servers with resource_cache set,
+// one file per server and URI. Entries are tied to the server's launch
+// settings like the tool cache (see launchFingerprint).
+type resourceCache struct {
+	dir   string
+	clock clock.Clock
+}
+
+// cachedResource is the on-disk form of one resourceCache entry.
+type cachedResource struct {
+	Fingerprint string `json:"fingerprint"`
+	URI         string `json:"uri"`
+	// Validator is the resource's size and lastModified from resources/list
+	// when it was read; empty if the backend reports neither.
+	Validator string          `json:"validator,omitempty"`
+	Stored    time.Time       `json:"stored"`
+	Result    json.RawMessage `json:"result"`
+}
+
+// newResourceCache returns a cache in config.ResourceCacheDir timing
+// entries with clk, or nil (caching disabled) when the directory cannot be
+// determined.
+func newResourceCache(clk clock.Clock) *resourceCache {
+	dir, err := config.ResourceCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return &resourceCache{dir: dir, clock: clk}
+}
+
+func (c *resourceCache) path(server, uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+
+	return filepath.Join(c.dir, tokenKeySanitizer.ReplaceAllString(server, "_"), hex.EncodeToString(sum[:16])+".json")
+}
+
+// load returns the cached read of uri, or nil if there is none or it was
+// stored for different launch settings.
+func (c *resourceCache) load(server string, cfg *config.ServerConfig, uri string) *cachedResource {
+	if c == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path(server, uri))
+	if err != nil {
+		return nil
+	}
+
+	var entry cachedResource
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URI != uri || entry.Fingerprint != launchFingerprint(cfg) {
+		return nil
+	}
+
+	return &entry
+}
+
+// save stores a read. Reads above the configured size are not cached.
+func (c *resourceCache) save(server string, cfg *config.ServerConfig, uri, validator string, result *mcp.ReadResourceResult) error {
+	if c == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding resource: %w", err)
+	}
+
+	if len(raw) > int(cfg.ResourceCache.EffectiveMaxSize()) {
+		return nil
+	}
+
+	return c.write(server, &cachedResource{
+		Fingerprint: launchFingerprint(cfg),
+		URI:         uri,
+		Validator:   validator,
+		Stored:      c.clock.Now(),
+		Result:      raw,
+	})
+}
+
+// renew marks a revalidated entry as fresh again.
+func (c *resourceCache) renew(server string, entry *cachedResource) error {
+	entry.Stored = c.clock.Now()
+
+	return c.write(server, entry)
+}
+
+func (c *resourceCache) write(server string, entry *cachedResource) error {
+	path := c.path(server, entry.URI)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating resource cache dir: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding resource cache entry: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing resource cache: %w", err)
+	}
+
+	return nil
+}
+
+// fresh reports whether an entry is within its TTL.
+func (c *resourceCache) fresh(entry *cachedResource, cfg *config.ResourceCacheConfig) bool {
+	return c.clock.Now().Sub(entry.Stored) < cfg.EffectiveTTL()
+}
+
+// resourceCacheable reports whether reads of uri are cached for a server.
+func resourceCacheable(cfg *config.ServerConfig, uri string) bool {
+	if cfg == nil || cfg.ResourceCache == nil {
+		return false
+	}
+
+	patterns := cfg.ResourceCache.URIs
+
+	return len(patterns) == 0 || slices.ContainsFunc(patterns, func(p string) bool {
+		return matchResourceURI(uri, p)
+	})
+}
+
+// resourceValidator identifies a version of a resource from its listing:
+// the size and lastModified annotation. Empty when the backend reports
+// neither, in which case an expired entry is always downloaded again.
+func resourceValidator(resource mcp.Resource) string {
+	var modified, size string
+
+	if resource.Annotations != nil {
+		modified = resource.Annotations.LastModified
+	}
+
+	if resource.Size != nil {
+		size = strconv.FormatInt(*resource.Size, 10)
+	}
+
+	if modified == "" && size == "" {
+		return ""
+	}
+
+	return size + "@" + modified
+}
+
+// currentValidator lists the backend's resources to find the validator of
+// uri as it is now.
+func currentValidator(ctx context.Context, srv ResourceServer, uri string) (string, error) {
+	resources, err := srv.DiscoverResources(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	i := slices.IndexFunc(resources, func(r mcp.Resource) bool { return r.URI == uri })
+	if i < 0 {
+		return "", nil
+	}
+
+	return resourceValidator(resources[i]), nil
+}
+
+// readResource reads a resource from its backend, going through the disk
+// cache when the server has resource_cache set. A fresh entry is served
+// as is; an expired one is renewed without downloading when the backend
+// still lists the same validator for it.
+func (a *Aggregator) readResource(ctx context.Context, srv ResourceServer, entry *ResourceEntry) (*mcp.ReadResourceResult, error) {
+	cfg := srv.Config()
+	if a.resourceCache == nil || !resourceCacheable(cfg, entry.OriginalURI) {
+		return srv.ReadResource(ctx, entry.OriginalURI)
+	}
+
+	logger := a.logger.With("server", entry.ServerName, "uri", entry.OriginalURI)
+
+	validator := resourceValidator(entry.Resource)
+
+	if cached := a.resourceCache.load(entry.ServerName, cfg, entry.OriginalURI); cached != nil {
+		if a.resourceCache.fresh(cached, cfg.ResourceCache) {
+			if result, err := cachedResult(cached); err == nil {
+				logger.Debug("resource served from cache")
+
+				return result, nil
+			}
+		} else if cached.Validator != "" {
+			current, err := currentValidator(ctx, srv, entry.OriginalURI)
+			if err != nil {
+				logger.Debug("could not revalidate cached resource", "error", err)
+			}
+
+			validator = current
+
+			if current == cached.Validator {
+				if result, err := cachedResult(cached); err == nil {
+					if err := a.resourceCache.renew(entry.ServerName, cached); err != nil {
+						logger.Debug("could not renew cached resource", "error", err)
+					}
+
+					logger.Debug("cached resource revalidated")
+
+					return result, nil
+				}
+			}
+		}
+	}
+
+	result, err := srv.ReadResource(ctx, entry.OriginalURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.resourceCache.save(entry.ServerName, cfg, entry.OriginalURI, validator, result); err != nil {
+		logger.Debug("could not cache resource", "error", err)
+	}
+
+	return result, nil
+}
+
+func cachedResult(entry *cachedResource) (*mcp.ReadResourceResult, error) {
+	return mcp.ParseReadResourceResult(&entry.Result)
+}
@@ -0,0 +1,128 @@
+package aggregator
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestReadResourceCache(t *testing.T) {
+	t.Parallel()
+
+	const uri = "file:///docs/guide.md"
+
+	annotated := func(modified string) mcp.Resource {
+		r := mcp.Resource{URI: uri, Name: "guide", MIMEType: "text/markdown"}
+		if modified != "" {
+			r.Annotations = &mcp.Annotations{LastModified: modified}
+		}
+
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		cache     *config.ResourceCacheConfig
+		listed    mcp.Resource
+		relisted  mcp.Resource // what resources/list reports at the second read
+		elapsed   time.Duration
+		wantReads int
+	}{
+		{
+			name:      "fresh entry served from disk",
+			cache:     &config.ResourceCacheConfig{TTL: time.Hour},
+			listed:    annotated("2026-01-01T00:00:00Z"),
+			relisted:  annotated("2026-01-01T00:00:00Z"),
+			elapsed:   time.Minute,
+			wantReads: 1,
+		},
+		{
+			name:      "expired entry revalidated",
+			cache:     &config.ResourceCacheConfig{TTL: time.Hour},
+			listed:    annotated("2026-01-01T00:00:00Z"),
+			relisted:  annotated("2026-01-01T00:00:00Z"),
+			elapsed:   2 * time.Hour,
+			wantReads: 1,
+		},
+		{
+			name:      "expired entry changed upstream",
+			cache:     &config.ResourceCacheConfig{TTL: time.Hour},
+			listed:    annotated("2026-01-01T00:00:00Z"),
+			relisted:  annotated("2026-02-01T00:00:00Z"),
+			elapsed:   2 * time.Hour,
+			wantReads: 2,
+		},
+		{
+			name:      "expired entry without validator",
+			cache:     &config.ResourceCacheConfig{TTL: time.Hour},
+			listed:    annotated(""),
+			relisted:  annotated(""),
+			elapsed:   2 * time.Hour,
+			wantReads: 2,
+		},
+		{
+			name:      "uri not cacheable",
+			cache:     &config.ResourceCacheConfig{URIs: []string{"file:///other/**"}},
+			listed:    annotated("2026-01-01T00:00:00Z"),
+			relisted:  annotated("2026-01-01T00:00:00Z"),
+			wantReads: 2,
+		},
+		{
+			name:      "cache not configured",
+			listed:    annotated("2026-01-01T00:00:00Z"),
+			relisted:  annotated("2026-01-01T00:00:00Z"),
+			wantReads: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+			agg.resourceCache = &resourceCache{dir: t.TempDir(), clock: clk}
+
+			mock := testutil.NewMockServer("docs", nil)
+			mock.ServerCfg.ResourceCache = tt.cache
+			mock.Resources = []mcp.Resource{tt.listed}
+
+			if err := agg.AddServer(t.Context(), mock); err != nil {
+				t.Fatalf("AddServer: %v", err)
+			}
+
+			entry := agg.resources.GetByServer("docs")[0]
+
+			for i := range 2 {
+				if i == 1 {
+					clk.Advance(tt.elapsed)
+					mock.Resources = []mcp.Resource{tt.relisted}
+				}
+
+				result, err := agg.readResource(t.Context(), mock, entry)
+				if err != nil {
+					t.Fatalf("readResource: %v", err)
+				}
+
+				text, ok := result.Contents[0].(mcp.TextResourceContents)
+				if !ok || text.Text != "mock content for "+uri {
+					t.Fatalf("read %d returned %+v", i+1, result.Contents)
+				}
+			}
+
+			if got := len(mock.ResourceReads); got != tt.wantReads {
+				t.Errorf("backend reads = %d, want %d", got, tt.wantReads)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package aggregator
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// linkTemplates records the servers with an assern://<server>/{+uri}
+// resource template registered, so resources/read of a resource_link
+// rewritten from one of their tool results routes back to them.
+type linkTemplates struct {
+	mu      sync.Mutex
+	servers map[string]bool
+}
+
+func newLinkTemplates() *linkTemplates {
+	return &linkTemplates{servers: make(map[string]bool)}
+}
+
+// add reports whether name was not yet recorded, recording it.
+func (l *linkTemplates) add(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.servers[name] {
+		return false
+	}
+
+	l.servers[name] = true
+
+	return true
+}
+
+// server returns the server whose linked resource template is template.
+func (l *linkTemplates) server(template string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for name := range l.servers {
+		if template == linkTemplate(name) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// linkTemplate is the URI template of the resources linked from the tool
+// results of serverName.
+func linkTemplate(serverName string) string {
+	return PrefixResourceURI(serverName, "") + "{+uri}"
+}
+
+// linkResources returns result with the URI of each resource_link content
+// item prefixed like the resources of serverName (see PrefixResourceURI),
+// since the client can only read resources through assern. The result may
+// be shared with coalesced callers, so a changed copy is returned.
+func (a *Aggregator) linkResources(result *mcp.CallToolResult, serverName string) *mcp.CallToolResult {
+	if result == nil {
+		return nil
+	}
+
+	var content []mcp.Content
+
+	for i, c := range result.Content {
+		link, ok := c.(mcp.ResourceLink)
+		if !ok {
+			continue
+		}
+
+		if content == nil {
+			content = make([]mcp.Content, len(result.Content))
+			copy(content, result.Content)
+		}
+
+		link.URI = PrefixResourceURI(serverName, link.URI)
+		content[i] = link
+	}
+
+	if content == nil {
+		return result
+	}
+
+	a.registerLinkTemplate(serverName)
+
+	linked := *result
+	linked.Content = content
+
+	return &linked
+}
+
+// registerLinkTemplate exposes the resources linked from the tool results
+// of serverName as a resource template, once. Linked resources are often
+// created by the call (a generated report, a new issue) and so missing from
+// the resources listed at startup.
+func (a *Aggregator) registerLinkTemplate(serverName string) {
+	if a.mcpServer == nil || !a.links.add(serverName) {
+		return
+	}
+
+	a.mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(
+		linkTemplate(serverName),
+		serverName+" linked resources",
+		mcp.WithTemplateDescription("Resources linked from the tool results of "+serverName),
+	), a.handleLinkedResource(serverName))
+}
+
+// handleLinkedResource reads a resource linked from a tool result of
+// serverName from the backend, with the URI it linked.
+func (a *Aggregator) handleLinkedResource(serverName string) server.ResourceTemplateHandlerFunc {
+	prefix := PrefixResourceURI(serverName, "")
+
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		uri := strings.TrimPrefix(req.Params.URI, prefix)
+
+		return a.routeResourceRead(ctx, &ResourceEntry{
+			ServerName:  serverName,
+			Resource:    mcp.Resource{URI: uri},
+			PrefixedURI: req.Params.URI,
+			OriginalURI: uri,
+		})
+	}
+}
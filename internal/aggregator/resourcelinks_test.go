@@ -0,0 +1,87 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestResourceLinks(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Servers["github"] = &config.ServerConfig{Command: "github-mcp"}
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	backendResult := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent("report written"),
+		mcp.NewResourceLink("file:///reports/weekly.md", "weekly.md", "Weekly report", "text/markdown"),
+	}}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("report")})
+	mock.ServerCfg = cfg.Servers["github"]
+	mock.SetToolResult("report", backendResult)
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	srv := agg.CreateMCPServer()
+
+	call := func(msg string, v any) {
+		t.Helper()
+
+		data, err := json.Marshal(srv.HandleMessage(t.Context(), json.RawMessage(msg)))
+		if err != nil {
+			t.Fatalf("encoding response: %v", err)
+		}
+
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatalf("decoding %s: %v", data, err)
+		}
+	}
+
+	var called struct {
+		Result struct {
+			Content []map[string]any `json:"content"`
+		} `json:"result"`
+	}
+	call(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"github_report"}}`, &called)
+
+	const linked = "assern://github/file:///reports/weekly.md"
+
+	if len(called.Result.Content) != 2 || called.Result.Content[1]["uri"] != linked {
+		t.Fatalf("tool result content = %v, want the resource link URI rewritten to %s", called.Result.Content, linked)
+	}
+
+	if backendResult.Content[1].(mcp.ResourceLink).URI != "file:///reports/weekly.md" {
+		t.Error("linkResources() modified the backend's result")
+	}
+
+	var read struct {
+		Result struct {
+			Contents []map[string]any `json:"contents"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	call(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"`+linked+`"}}`, &read)
+
+	if read.Error != nil || len(read.Result.Contents) != 1 {
+		t.Fatalf("resources/read of the link = %+v", read)
+	}
+
+	if reads := mock.ResourceReads; len(reads) != 1 || reads[0].URI != "file:///reports/weekly.md" {
+		t.Errorf("backend reads = %v, want the original URI", reads)
+	}
+}
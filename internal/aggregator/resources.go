@@ -2,9 +2,14 @@ package aggregator
 
 import (
 	"fmt"
+	"mime"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
 )
 
 // ResourceEntry represents a resource from a backend server.
@@ -120,3 +125,59 @@ func ParsePrefixedURI(prefixedURI string) (string, string, error) {
 
 	return server, uri, nil
 }
+
+// resourceAllowed reports whether a resource passes a server's resource
+// filter. A nil filter allows every resource.
+func resourceAllowed(resource mcp.Resource, filter *config.ResourceFilter) bool {
+	if filter == nil {
+		return true
+	}
+
+	if len(filter.MIMETypes) > 0 && !slices.ContainsFunc(filter.MIMETypes, func(p string) bool {
+		return matchMIMEType(resource.MIMEType, p)
+	}) {
+		return false
+	}
+
+	if len(filter.URIs) > 0 && !slices.ContainsFunc(filter.URIs, func(p string) bool {
+		return matchResourceURI(resource.URI, p)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// matchMIMEType matches a MIME type against "type/subtype", "type/*" or "*",
+// ignoring case and parameters. A resource without a MIME type only matches
+// "*".
+func matchMIMEType(mimeType, pattern string) bool {
+	if pattern == "*" || pattern == "*/*" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+
+	pattern = strings.ToLower(pattern)
+
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+
+	return mediaType == pattern
+}
+
+// matchResourceURI matches a resource URI against a glob where "*" matches
+// within one path segment and a trailing "/**" matches any depth.
+func matchResourceURI(uri, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return uri == prefix || strings.HasPrefix(uri, prefix+"/")
+	}
+
+	matched, err := path.Match(pattern, uri)
+
+	return err == nil && matched
+}
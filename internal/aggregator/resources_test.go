@@ -2,10 +2,14 @@ package aggregator
 
 import (
 	"errors"
+	"log/slog"
 	"sync"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
 )
 
 func TestResourceRegistry_Register(t *testing.T) {
@@ -222,3 +226,66 @@ func TestResourceRegistry_ConcurrentAccess(t *testing.T) {
 
 	// No race conditions should occur
 }
+
+func TestResourceAllowed(t *testing.T) {
+	t.Parallel()
+
+	readme := mcp.Resource{URI: "file:///repo/docs/README.md", MIMEType: "text/markdown; charset=utf-8"}
+	nested := mcp.Resource{URI: "file:///repo/docs/api/v1.md", MIMEType: "text/markdown"}
+	image := mcp.Resource{URI: "file:///repo/logo.png", MIMEType: "image/png"}
+	untyped := mcp.Resource{URI: "file:///repo/Makefile"}
+
+	tests := []struct {
+		name   string
+		filter *config.ResourceFilter
+		want   []bool // readme, nested, image, untyped
+	}{
+		{"no filter", nil, []bool{true, true, true, true}},
+		{"exact mime type", &config.ResourceFilter{MIMETypes: []string{"text/markdown"}}, []bool{true, true, false, false}},
+		{"mime wildcard", &config.ResourceFilter{MIMETypes: []string{"Image/*"}}, []bool{false, false, true, false}},
+		{"any mime type", &config.ResourceFilter{MIMETypes: []string{"*"}}, []bool{true, true, true, true}},
+		{"uri glob one segment", &config.ResourceFilter{URIs: []string{"file:///repo/docs/*.md"}}, []bool{true, false, false, false}},
+		{"uri glob any depth", &config.ResourceFilter{URIs: []string{"file:///repo/docs/**"}}, []bool{true, true, false, false}},
+		{
+			"mime and uri",
+			&config.ResourceFilter{MIMETypes: []string{"text/*"}, URIs: []string{"file:///repo/*"}},
+			[]bool{false, false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i, r := range []mcp.Resource{readme, nested, image, untyped} {
+				if got := resourceAllowed(r, tt.filter); got != tt.want[i] {
+					t.Errorf("resourceAllowed(%s) = %v, want %v", r.URI, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAddServerFiltersResources(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("docs", nil)
+	mock.ServerCfg.AllowedResources = &config.ResourceFilter{MIMETypes: []string{"text/markdown"}}
+	mock.Resources = []mcp.Resource{
+		{URI: "file:///a.md", Name: "a", MIMEType: "text/markdown"},
+		{URI: "file:///b.png", Name: "b", MIMEType: "image/png"},
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	if got := agg.resources.GetByServer("docs"); len(got) != 1 || got[0].OriginalURI != "file:///a.md" {
+		t.Errorf("registered resources = %+v, want only file:///a.md", got)
+	}
+}
@@ -0,0 +1,67 @@
+package aggregator
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxResourceSize returns settings.max_resource_size, 0 when resource reads
+// are not limited.
+func (a *Aggregator) maxResourceSize() int64 {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return 0
+	}
+
+	return int64(a.cfg.Settings.MaxResourceSize)
+}
+
+// checkDeclaredSize fails the read of entry before it reaches the backend
+// when the size its listing declares is over limit, so the resource is
+// never buffered.
+func checkDeclaredSize(entry *ResourceEntry, limit int64) error {
+	if limit <= 0 || entry.Resource.Size == nil || *entry.Resource.Size <= limit {
+		return nil
+	}
+
+	return resourceTooLarge(entry, *entry.Resource.Size, limit)
+}
+
+// checkContentsSize fails a read whose contents are over limit, for
+// backends that do not declare sizes or declare them wrong.
+func checkContentsSize(entry *ResourceEntry, contents []mcp.ResourceContents, limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	if size := contentsSize(contents); size > limit {
+		return resourceTooLarge(entry, size, limit)
+	}
+
+	return nil
+}
+
+// contentsSize returns the size of contents in bytes: the length of the
+// texts plus the decoded length of the blobs.
+func contentsSize(contents []mcp.ResourceContents) int64 {
+	var size int64
+
+	for _, content := range contents {
+		switch c := content.(type) {
+		case mcp.TextResourceContents:
+			size += int64(len(c.Text))
+		case mcp.BlobResourceContents:
+			size += int64(base64.StdEncoding.DecodedLen(len(c.Blob)))
+		}
+	}
+
+	return size
+}
+
+func resourceTooLarge(entry *ResourceEntry, size, limit int64) error {
+	return fmt.Errorf("%w: %s is %d bytes, over the limit of %d", ErrResourceTooLarge, entry.PrefixedURI, size, limit)
+}
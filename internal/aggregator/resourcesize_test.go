@@ -0,0 +1,74 @@
+package aggregator
+
+import (
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func TestMaxResourceSize(t *testing.T) {
+	t.Parallel()
+
+	const uri = "file:///data/dump.csv"
+
+	declared := func(size int64) *int64 { return &size }
+	text := []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, Text: strings.Repeat("x", 2000)}}
+	blob := []mcp.ResourceContents{mcp.BlobResourceContents{URI: uri, Blob: base64.StdEncoding.EncodeToString(make([]byte, 1500))}}
+
+	tests := []struct {
+		name      string
+		limit     config.ByteSize
+		size      *int64
+		contents  []mcp.ResourceContents
+		wantErr   bool
+		wantReads int
+	}{
+		{name: "no limit", contents: text, wantReads: 1},
+		{name: "within the limit", limit: 4000, size: declared(2000), contents: text, wantReads: 1},
+		{name: "declared over the limit", limit: 1000, size: declared(2000), contents: text, wantErr: true},
+		{name: "text over the limit", limit: 1000, contents: text, wantErr: true, wantReads: 1},
+		{name: "blob over the limit", limit: 1000, contents: blob, wantErr: true, wantReads: 1},
+		{name: "declared size understated", limit: 1000, size: declared(10), contents: text, wantErr: true, wantReads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.NewConfig()
+			cfg.Settings.MaxResourceSize = tt.limit
+
+			agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			mock := testutil.NewMockServer("data", nil)
+			mock.Resources = []mcp.Resource{{URI: uri, Name: "dump", Size: tt.size}}
+			mock.ResourceContents = map[string][]mcp.ResourceContents{uri: tt.contents}
+
+			if err := agg.AddServer(t.Context(), mock); err != nil {
+				t.Fatalf("AddServer: %v", err)
+			}
+
+			contents, err := agg.routeResourceRead(t.Context(), agg.resources.GetByServer("data")[0])
+			switch {
+			case tt.wantErr && !errors.Is(err, ErrResourceTooLarge):
+				t.Errorf("routeResourceRead() error = %v, want ErrResourceTooLarge", err)
+			case !tt.wantErr && (err != nil || len(contents) != 1):
+				t.Errorf("routeResourceRead() = %v, %v; want the contents", contents, err)
+			}
+
+			if got := len(mock.ResourceReads); got != tt.wantReads {
+				t.Errorf("backend reads = %d, want %d", got, tt.wantReads)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/valksor/go-assern/internal/clock"
 	"github.com/valksor/go-assern/internal/config"
 )
 
@@ -47,6 +48,11 @@ func IsRetryable(err error) bool {
 		return false
 	}
 
+	// Retrying cannot fix missing or expired authorization
+	if isAuthError(err) {
+		return false
+	}
+
 	// By default, assume transient failures are retryable
 	return true
 }
@@ -57,6 +63,11 @@ type RetryFunc[T any] func(ctx context.Context, attempt int) (T, error)
 // WithRetry executes the given function with retry logic based on config.
 // If retryCfg is nil, the function is executed once without retries.
 func WithRetry[T any](ctx context.Context, retryCfg *config.RetryConfig, fn RetryFunc[T]) (T, error) {
+	return withRetry(ctx, clock.Real, retryCfg, fn)
+}
+
+// withRetry is WithRetry waiting out the backoff delays on clk.
+func withRetry[T any](ctx context.Context, clk clock.Clock, retryCfg *config.RetryConfig, fn RetryFunc[T]) (T, error) {
 	var zero T
 
 	// No retry config means single attempt
@@ -94,7 +105,7 @@ func WithRetry[T any](ctx context.Context, retryCfg *config.RetryConfig, fn Retr
 		select {
 		case <-ctx.Done():
 			return zero, ctx.Err()
-		case <-time.After(delay):
+		case <-clk.After(delay):
 		}
 
 		// Calculate next delay with backoff
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/valksor/go-assern/internal/clock"
 	"github.com/valksor/go-assern/internal/config"
 )
 
@@ -183,25 +184,43 @@ func TestWithRetry_MaxDelayRespected(t *testing.T) {
 		BackoffFactor: 2.0,
 	}
 
-	start := time.Now()
-	calls := 0
-	_, _ = WithRetry[string](context.Background(), cfg, func(ctx context.Context, attempt int) (string, error) {
-		calls++
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	attempts := make(chan struct{}, cfg.MaxAttempts)
+	done := make(chan int)
 
-		return "", errors.New("error")
-	})
-	elapsed := time.Since(start)
+	go func() {
+		calls := 0
+		_, _ = withRetry[string](t.Context(), clk, cfg, func(ctx context.Context, attempt int) (string, error) {
+			calls++
+			attempts <- struct{}{}
 
-	if calls != 4 {
-		t.Errorf("calls = %d, want 4", calls)
+			return "", errors.New("error")
+		})
+		done <- calls
+	}()
+
+	<-attempts
+
+	// maxDelay caps 100ms and 200ms to 60ms
+	for _, delay := range []time.Duration{50 * time.Millisecond, 60 * time.Millisecond, 60 * time.Millisecond} {
+		clk.BlockUntil(1)
+		clk.Advance(delay - time.Millisecond)
+
+		if clk.Waiters() != 1 {
+			t.Fatalf("retried before the %v delay passed", delay)
+		}
+
+		clk.Advance(time.Millisecond)
+
+		select {
+		case <-attempts:
+		case <-time.After(time.Second):
+			t.Fatalf("no retry after the %v delay", delay)
+		}
 	}
 
-	// Expected: 50ms + 60ms + 60ms = 170ms (maxDelay caps 100ms and 200ms to 60ms)
-	// Allow some tolerance
-	expectedMin := 150 * time.Millisecond
-	expectedMax := 250 * time.Millisecond
-	if elapsed < expectedMin || elapsed > expectedMax {
-		t.Errorf("elapsed = %v, want between %v and %v", elapsed, expectedMin, expectedMax)
+	if calls := <-done; calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
 	}
 }
 
@@ -0,0 +1,139 @@
+package aggregator
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/redact"
+)
+
+// maxRecentErrors is how many errors are kept per server for triage.
+const maxRecentErrors = 20
+
+// Operations that recent errors are recorded for. Failed tool calls use
+// "call <tool>" instead.
+const (
+	opStart       = "start"
+	opRestart     = "restart"
+	opReconnect   = "reconnect"
+	opHealthCheck = "health check"
+	opCrash       = "crash"
+)
+
+// ServerError is one recorded failure of a server.
+type ServerError struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Message   string    `json:"message"`
+}
+
+// ServerTiming is how long a server took to start.
+type ServerTiming struct {
+	Initialize time.Duration // Connect (or spawn) and MCP initialize
+	ListTools  time.Duration // tools/list during startup
+}
+
+// serverRuntime is what the aggregator tracks about each server besides its
+// health: when it last started, how long that took, its last error and
+// whether it is being restarted. It has its own lock because servers start
+// concurrently.
+type serverRuntime struct {
+	clock clock.Clock
+
+	mu sync.Mutex
+	m  map[string]runtimeRecord
+}
+
+type runtimeRecord struct {
+	timing      ServerTiming
+	startedAt   time.Time
+	lastError   string
+	lastErrorAt time.Time
+	restarting  bool
+	errors      []ServerError // Most recent last, at most maxRecentErrors
+}
+
+func newServerRuntime(clk clock.Clock) *serverRuntime {
+	return &serverRuntime{clock: clk, m: make(map[string]runtimeRecord)}
+}
+
+// started records a successful (re)start. The last error is kept, so status
+// still shows why a server that recovered went down.
+func (r *serverRuntime) started(name string, timing ServerTiming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.m[name]
+	rec.timing = timing
+	rec.startedAt = r.clock.Now()
+	rec.restarting = false
+	r.m[name] = rec
+}
+
+// failed records the error of a failed operation (start, call, ...) and adds
+// it to the server's recent errors, dropping the oldest beyond
+// maxRecentErrors.
+func (r *serverRuntime) failed(name, op string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+
+	// Errors may quote the URL of the request that failed
+	message := redact.Text(err.Error())
+
+	rec := r.m[name]
+	rec.lastError = message
+	rec.lastErrorAt = now
+	rec.restarting = false
+
+	if len(rec.errors) >= maxRecentErrors {
+		rec.errors = rec.errors[len(rec.errors)-maxRecentErrors+1:]
+	}
+
+	rec.errors = append(rec.errors, ServerError{Time: now, Operation: op, Message: message})
+	r.m[name] = rec
+}
+
+// recentErrors returns a copy of the recent errors of every server that has
+// any, by server name.
+func (r *serverRuntime) recentErrors() map[string][]ServerError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[string][]ServerError)
+	for name, rec := range r.m {
+		if len(rec.errors) > 0 {
+			out[name] = slices.Clone(rec.errors)
+		}
+	}
+
+	return out
+}
+
+// restarting marks a server as being restarted, until started or failed.
+func (r *serverRuntime) restarting(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.m[name]
+	rec.restarting = true
+	r.m[name] = rec
+}
+
+// forget drops the record of a server that was removed from the config.
+func (r *serverRuntime) forget(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.m, name)
+}
+
+func (r *serverRuntime) get(name string) runtimeRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.m[name]
+}
@@ -0,0 +1,145 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var (
+	// errNoSamplingCaller is returned to a backend that requests sampling
+	// while none of its tool calls is in flight.
+	errNoSamplingCaller = errors.New("no client tool call in flight to forward the sampling request to")
+
+	// errAmbiguousSampling is returned when calls from several clients are
+	// in flight, so the request cannot be told apart.
+	errAmbiguousSampling = errors.New("tool calls from several clients are in flight; cannot tell which one the sampling request belongs to")
+
+	// errClientNoSampling is returned when the calling client did not
+	// declare the sampling capability.
+	errClientNoSampling = errors.New("client does not support sampling")
+)
+
+// samplingCallers tracks the client sessions with tool calls in flight to
+// each server with sampling set, so a backend's sampling request can be
+// forwarded to the client whose call caused it.
+type samplingCallers struct {
+	mu      sync.Mutex
+	servers map[string]map[*samplingCall]struct{}
+}
+
+type samplingCall struct {
+	session server.ClientSession
+}
+
+func newSamplingCallers() *samplingCallers {
+	return &samplingCallers{servers: make(map[string]map[*samplingCall]struct{})}
+}
+
+// track records a call to name from the session in ctx until the returned
+// function is called. Calls without a session are not tracked.
+func (s *samplingCallers) track(ctx context.Context, name string) func() {
+	session := server.ClientSessionFromContext(ctx)
+	if s == nil || session == nil {
+		return func() {}
+	}
+
+	call := &samplingCall{session: session}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.servers[name] == nil {
+		s.servers[name] = make(map[*samplingCall]struct{})
+	}
+
+	s.servers[name][call] = struct{}{}
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.servers[name], call)
+
+		if len(s.servers[name]) == 0 {
+			delete(s.servers, name)
+		}
+	}
+}
+
+// caller returns the one session with calls in flight to name.
+func (s *samplingCallers) caller(name string) (server.ClientSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var session server.ClientSession
+
+	for call := range s.servers[name] {
+		switch {
+		case session == nil:
+			session = call.session
+		case session.SessionID() != call.session.SessionID():
+			return nil, errAmbiguousSampling
+		}
+	}
+
+	if session == nil {
+		return nil, errNoSamplingCaller
+	}
+
+	return session, nil
+}
+
+// samplingHandler forwards the sampling requests of one backend to the
+// client session with a tool call in flight to it.
+type samplingHandler struct {
+	agg    *Aggregator
+	server string
+}
+
+var _ client.SamplingHandler = (*samplingHandler)(nil)
+
+// CreateMessage forwards request to the calling client and returns its
+// result to the backend.
+func (h *samplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	result, err := h.agg.forwardSampling(ctx, h.server, request)
+	if err != nil {
+		h.agg.logger.Warn("sampling request not forwarded", "server", h.server, "error", err)
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// forwardSampling sends a backend's sampling request to the client session
+// with a tool call in flight to server, if it declared sampling.
+func (a *Aggregator) forwardSampling(ctx context.Context, name string, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	if a.mcpServer == nil {
+		return nil, errNoSamplingCaller
+	}
+
+	session, err := a.sampling.caller(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile, ok := a.clients.get(session.SessionID()); ok && profile.capabilities.Sampling == nil {
+		return nil, fmt.Errorf("%s: %w", profile.name, errClientNoSampling)
+	}
+
+	a.logger.Debug("forwarding sampling request", "server", name, "session", session.SessionID(),
+		"messages", len(request.Messages))
+
+	result, err := a.mcpServer.RequestSampling(a.mcpServer.WithContext(ctx, session), request)
+	if err != nil {
+		return nil, fmt.Errorf("client sampling: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// samplingSession is a client session answering sampling requests.
+type samplingSession struct {
+	*fakeSession
+
+	requests []mcp.CreateMessageRequest
+}
+
+func (s *samplingSession) RequestSampling(_ context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	s.requests = append(s.requests, request)
+
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("Riga")},
+		Model:           "client-model",
+		StopReason:      "endTurn",
+	}, nil
+}
+
+var _ server.SessionWithSampling = (*samplingSession)(nil)
+
+func TestSamplingForwardedToCaller(t *testing.T) {
+	t.Parallel()
+
+	// A backend whose "ask" tool has the client's LLM answer
+	backend := server.NewMCPServer("llm", "1.0.0")
+	backend.AddTool(mcp.NewTool("ask"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := backend.RequestSampling(ctx, mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+			Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("Capital of Latvia?")}},
+			MaxTokens: 10,
+		}})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		text, _ := result.Content.(mcp.TextContent)
+
+		return mcp.NewToolResultText(text.Text + " (" + result.Model + ")"), nil
+	})
+
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(backend))
+	t.Cleanup(ts.Close)
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	t.Cleanup(func() { _ = agg.Stop() })
+	agg.CreateMCPServer()
+
+	managed, err := agg.newManagedServer(t.Context(), "llm", &config.ServerConfig{URL: ts.URL, Sampling: true})
+	if err != nil {
+		t.Fatalf("newManagedServer: %v", err)
+	}
+
+	if err := managed.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), managed); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	entry, _ := agg.tools.Get("llm_ask")
+	session := &samplingSession{fakeSession: newFakeSession("s1")}
+	ctx := agg.mcpServer.WithContext(t.Context(), session)
+
+	result, err := agg.createToolHandler(entry)(ctx, mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Fatalf("call = %+v, %v", result, err)
+	}
+
+	if got := toolResultText(result); got != "Riga (client-model)" {
+		t.Errorf("result = %q, want the client's answer", got)
+	}
+
+	if len(session.requests) != 1 || len(session.requests[0].Messages) != 1 {
+		t.Errorf("client got sampling requests %+v, want the backend's one", session.requests)
+	}
+}
+
+func TestSamplingCallers(t *testing.T) {
+	t.Parallel()
+
+	callers := newSamplingCallers()
+	s1 := server.NewMCPServer("test", "1").WithContext(t.Context(), newFakeSession("s1"))
+	s2 := server.NewMCPServer("test", "1").WithContext(t.Context(), newFakeSession("s2"))
+
+	if _, err := callers.caller("llm"); !errors.Is(err, errNoSamplingCaller) {
+		t.Errorf("caller() without calls = %v, want errNoSamplingCaller", err)
+	}
+
+	// Two calls of one session route to it
+	release1 := callers.track(s1, "llm")
+	release2 := callers.track(s1, "llm")
+
+	if session, err := callers.caller("llm"); err != nil || session.SessionID() != "s1" {
+		t.Errorf("caller() = %v, %v, want s1", session, err)
+	}
+
+	// A call from another session makes the caller ambiguous
+	release3 := callers.track(s2, "llm")
+	if _, err := callers.caller("llm"); !errors.Is(err, errAmbiguousSampling) {
+		t.Errorf("caller() with two sessions = %v, want errAmbiguousSampling", err)
+	}
+
+	release1()
+	release2()
+
+	if session, err := callers.caller("llm"); err != nil || session.SessionID() != "s2" {
+		t.Errorf("caller() = %v, %v, want s2", session, err)
+	}
+
+	release3()
+
+	if len(callers.servers) != 0 {
+		t.Errorf("servers = %v, want none after all calls ended", callers.servers)
+	}
+}
@@ -0,0 +1,271 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// withInlinedRefs returns tool with the local $refs of its input schema
+// replaced by their targets when settings.schema_refs.inline is on. A tool
+// whose inlined schema would exceed the size cap keeps its references.
+func (a *Aggregator) withInlinedRefs(serverName string, tool mcp.Tool) mcp.Tool {
+	if !a.schemaRefs.InlinesRefs() {
+		return tool
+	}
+
+	data, err := json.Marshal(tool.InputSchema)
+	if err != nil || !strings.Contains(string(data), `"$ref"`) {
+		return tool
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return tool
+	}
+
+	inlined, ok := inlineRefs(schema, a.schemaRefs.EffectiveMaxBytes())
+	if !ok {
+		a.logger.Warn("tool input schema too large to inline $refs, keeping them",
+			"server", serverName, "tool", tool.Name, "max_bytes", a.schemaRefs.EffectiveMaxBytes())
+
+		return tool
+	}
+
+	data, err = json.Marshal(inlined)
+	if err != nil {
+		return tool
+	}
+
+	var inputSchema mcp.ToolInputSchema
+	if err := json.Unmarshal(data, &inputSchema); err != nil {
+		return tool
+	}
+
+	tool.InputSchema = inputSchema
+
+	return tool
+}
+
+// inlineRefs returns schema with its local $refs ("#/$defs/Name",
+// "#/definitions/Name" or another JSON pointer into schema) replaced by
+// copies of their targets. Keywords next to a $ref override those of the
+// target. A $ref met again while its target is being inlined (a cycle) is
+// kept, and so are the definitions; otherwise they are dropped. Remote and
+// unresolvable $refs are left as they are. ok is false when the inlined
+// schema would be larger than maxBytes.
+func inlineRefs(schema map[string]any, maxBytes int) (map[string]any, bool) {
+	// mcp-go renames "definitions" to "$defs", so point references there
+	if _, ok := schema["definitions"]; !ok {
+		if _, ok := schema["$defs"]; ok {
+			schema, _ = rewriteRefs(schema, "#/definitions/", "#/$defs/").(map[string]any)
+		}
+	}
+
+	r := &refInliner{root: schema, budget: maxBytes, stack: []string{"#"}}
+
+	body := make(map[string]any, len(schema))
+
+	for k, v := range schema {
+		if k == "$defs" || k == "definitions" {
+			continue
+		}
+
+		expanded, ok := r.expand(v)
+		if !ok {
+			return nil, false
+		}
+
+		body[k] = expanded
+	}
+
+	if r.kept {
+		for _, k := range []string{"$defs", "definitions"} {
+			if defs, ok := schema[k]; ok {
+				body[k] = defs
+			}
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil || len(data) > maxBytes {
+		return nil, false
+	}
+
+	return body, true
+}
+
+// refInliner expands $refs against root. budget is the number of bytes of
+// targets that may still be copied, which bounds the work on schemas whose
+// references fan out exponentially.
+type refInliner struct {
+	root   map[string]any
+	budget int
+	// stack holds the references being expanded, to detect cycles
+	stack []string
+	// kept is set when a cyclic reference was left in place
+	kept bool
+}
+
+// expand returns a copy of v with its local references inlined.
+func (r *refInliner) expand(v any) (any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			return r.expandRef(v, ref)
+		}
+
+		out := make(map[string]any, len(v))
+
+		for k, child := range v {
+			expanded, ok := r.expand(child)
+			if !ok {
+				return nil, false
+			}
+
+			out[k] = expanded
+		}
+
+		return out, true
+	case []any:
+		out := make([]any, len(v))
+
+		for i, child := range v {
+			expanded, ok := r.expand(child)
+			if !ok {
+				return nil, false
+			}
+
+			out[i] = expanded
+		}
+
+		return out, true
+	default:
+		return v, true
+	}
+}
+
+// expandRef inlines the target of ref, merging the other keywords of node
+// over it.
+func (r *refInliner) expandRef(node map[string]any, ref string) (any, bool) {
+	if slices.Contains(r.stack, ref) {
+		r.kept = true
+
+		return node, true
+	}
+
+	target, ok := resolvePointer(r.root, ref)
+	if !ok {
+		return node, true
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return node, true
+	}
+
+	if r.budget -= len(data); r.budget < 0 {
+		return nil, false
+	}
+
+	r.stack = append(r.stack, ref)
+	expanded, ok := r.expand(target)
+	r.stack = r.stack[:len(r.stack)-1]
+
+	if !ok {
+		return nil, false
+	}
+
+	targetMap, isMap := expanded.(map[string]any)
+	if !isMap || len(node) == 1 {
+		return expanded, true
+	}
+
+	for k, v := range node {
+		if k == "$ref" {
+			continue
+		}
+
+		sibling, ok := r.expand(v)
+		if !ok {
+			return nil, false
+		}
+
+		targetMap[k] = sibling
+	}
+
+	return targetMap, true
+}
+
+// resolvePointer returns the value the local reference ref ("#/a/b/0")
+// points to in root.
+func resolvePointer(root map[string]any, ref string) (any, bool) {
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return root, true
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false // anchors such as "#node" are not supported
+	}
+
+	var current any = root
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch c := current.(type) {
+		case map[string]any:
+			next, ok := c[token]
+			if !ok {
+				return nil, false
+			}
+
+			current = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+
+			current = c[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// rewriteRefs returns a copy of v with the prefix from of $ref values
+// replaced by to.
+func rewriteRefs(v any, from, to string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" && strings.HasPrefix(ref, from) {
+				out[k] = to + strings.TrimPrefix(ref, from)
+
+				continue
+			}
+
+			out[k] = rewriteRefs(child, from, to)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = rewriteRefs(child, from, to)
+		}
+
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,150 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+func decodeSchema(t *testing.T, s string) map[string]any {
+	t.Helper()
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("decoding %s: %v", s, err)
+	}
+
+	return m
+}
+
+func TestInlineRefs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schema   string
+		maxBytes int
+		want     string
+		wantOK   bool
+	}{
+		{
+			name: "defs inlined and dropped",
+			schema: `{"type":"object","properties":{"owner":{"$ref":"#/$defs/user"},"tags":{"type":"array","items":{"$ref":"#/$defs/tag"}}},
+				"$defs":{"user":{"type":"object","properties":{"login":{"type":"string"}}},"tag":{"type":"string"}}}`,
+			want: `{"type":"object","properties":{"owner":{"type":"object","properties":{"login":{"type":"string"}}},
+				"tags":{"type":"array","items":{"type":"string"}}}}`,
+			wantOK: true,
+		},
+		{
+			name:   "draft-07 definitions renamed by mcp-go",
+			schema: `{"type":"object","properties":{"id":{"$ref":"#/definitions/id"}},"$defs":{"id":{"type":"integer"}}}`,
+			want:   `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+			wantOK: true,
+		},
+		{
+			name:   "sibling keywords override the target",
+			schema: `{"properties":{"id":{"$ref":"#/$defs/id","description":"Issue number"}},"$defs":{"id":{"type":"integer","description":"An id"}}}`,
+			want:   `{"properties":{"id":{"type":"integer","description":"Issue number"}}}`,
+			wantOK: true,
+		},
+		{
+			name:   "pointer into properties",
+			schema: `{"properties":{"a":{"type":"string"},"b":{"$ref":"#/properties/a"}}}`,
+			want:   `{"properties":{"a":{"type":"string"},"b":{"type":"string"}}}`,
+			wantOK: true,
+		},
+		{
+			name: "cycle keeps its reference and the definitions",
+			schema: `{"properties":{"root":{"$ref":"#/$defs/node"}},
+				"$defs":{"node":{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/$defs/node"}}}}}}`,
+			want: `{"properties":{"root":{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/$defs/node"}}}}},
+				"$defs":{"node":{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/$defs/node"}}}}}}`,
+			wantOK: true,
+		},
+		{
+			name:   "remote and missing references left alone",
+			schema: `{"properties":{"a":{"$ref":"https://example.com/a.json"},"b":{"$ref":"#/$defs/missing"}}}`,
+			want:   `{"properties":{"a":{"$ref":"https://example.com/a.json"},"b":{"$ref":"#/$defs/missing"}}}`,
+			wantOK: true,
+		},
+		{
+			name: "over the size cap",
+			schema: `{"properties":{"a":{"$ref":"#/$defs/big"},"b":{"$ref":"#/$defs/big"},"c":{"$ref":"#/$defs/big"}},
+				"$defs":{"big":{"type":"string","description":"` + strings.Repeat("x", 100) + `"}}}`,
+			maxBytes: 300,
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			maxBytes := tt.maxBytes
+			if maxBytes == 0 {
+				maxBytes = int(config.DefaultSchemaRefsMaxBytes)
+			}
+
+			got, ok := inlineRefs(decodeSchema(t, tt.schema), maxBytes)
+			if ok != tt.wantOK {
+				t.Fatalf("inlineRefs() ok = %v, want %v (%v)", ok, tt.wantOK, got)
+			}
+
+			if !tt.wantOK {
+				return
+			}
+
+			if want := decodeSchema(t, tt.want); !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("inlineRefs() = %s\nwant %s", gotJSON, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterToolsInlinesRefs(t *testing.T) {
+	t.Parallel()
+
+	schema := `{"type":"object","properties":{"owner":{"$ref":"#/$defs/user"}},"required":["owner"],"$defs":{"user":{"type":"string"}}}`
+
+	var tool mcp.Tool
+	if err := json.Unmarshal([]byte(`{"name":"search","inputSchema":`+schema+`}`), &tool); err != nil {
+		t.Fatalf("decoding tool: %v", err)
+	}
+
+	for _, inline := range []bool{false, true} {
+		cfg := config.NewConfig()
+		cfg.Settings.SchemaRefs = &config.SchemaRefsConfig{Inline: inline}
+
+		agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+
+		mock := testutil.NewMockServer("github", []mcp.Tool{tool})
+		if err := agg.AddServer(t.Context(), mock); err != nil {
+			t.Fatalf("AddServer: %v", err)
+		}
+
+		entry, ok := agg.tools.Get("github_search")
+		if !ok {
+			t.Fatal("tool not registered")
+		}
+
+		exposed, _ := json.Marshal(entry.ExposedTool().InputSchema)
+		if hasRef := strings.Contains(string(exposed), "$ref"); hasRef == inline {
+			t.Errorf("inline=%v: exposed schema %s", inline, exposed)
+		}
+
+		if got := entry.Tool.InputSchema.Required; len(got) != 1 || got[0] != "owner" {
+			t.Errorf("inline=%v: required = %v, want [owner]", inline, got)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package aggregator
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -13,8 +12,14 @@ import (
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/valksor/go-assern/internal/clock"
 	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/redact"
+	"github.com/valksor/go-assern/internal/tracing"
 )
 
 // TransportType represents the type of MCP transport.
@@ -27,18 +32,66 @@ const (
 	TransportOAuthSSE  TransportType = "oauth-sse"
 	TransportOAuthHTTP TransportType = "oauth-http"
 	TransportInProcess TransportType = "in-process"
+	TransportWebSocket TransportType = "websocket"
+	TransportContainer TransportType = "container"
 )
 
 // ManagedServer represents a backend MCP server that Assern manages.
 type ManagedServer struct {
 	name          string
 	cfg           *config.ServerConfig
+	conn          *config.ServerConfig // cfg with its references expanded, to connect with
 	env           []string
 	logger        *slog.Logger
 	transportType TransportType
 
 	client *client.Client
 
+	// requests wraps the client's transport, forwarding cancellations and
+	// tracking the backend request IDs of calls in flight
+	requests *cancelTransport
+
+	// tracer records spans of connections and tool calls; the aggregator
+	// sets its own
+	tracer trace.Tracer
+
+	// clock times initialization and clock skew measurements; the
+	// aggregator sets its own
+	clock clock.Clock
+
+	// skew measures the authorization server's clock (nil without OAuth)
+	skew *clockSkew
+
+	// stderr receives what a stdio process writes to stderr
+	stderr *stderrLog
+
+	// sampling handles the backend's sampling requests; when set, the
+	// sampling capability is declared at initialize
+	sampling client.SamplingHandler
+
+	// inProcess is the server an in-process transport calls
+	inProcess *server.MCPServer
+
+	// engine runs the image of a container server (settings.container_engine),
+	// and container is the name of its running container
+	engine    string
+	container string
+
+	// crashed receives a value when a started stdio process exits, or a
+	// WebSocket connection drops, without Stop being called; it is buffered
+	// so the watcher never blocks.
+	crashed chan struct{}
+
+	// toolsChanged receives a value when the backend sends
+	// notifications/tools/list_changed; buffered, so bursts coalesce.
+	toolsChanged chan struct{}
+
+	// progress maps the progress tokens of the calls in flight that asked
+	// for progress notifications to the functions they report them to
+	progressMu  sync.Mutex
+	progress    map[string]func()
+	progressSeq uint64
+
 	mu      sync.RWMutex
 	started bool
 }
@@ -51,13 +104,29 @@ func NewManagedServer(name string, cfg *config.ServerConfig, env []string, logge
 		return nil, fmt.Errorf("server %s: %w", name, ErrInvalidTransport)
 	}
 
-	return &ManagedServer{
+	s := &ManagedServer{
 		name:          name,
 		cfg:           cfg,
+		conn:          cfg,
 		env:           env,
 		logger:        logger.With("server", name),
 		transportType: transportType,
-	}, nil
+		engine:        config.ContainerEngineDocker,
+		crashed:       make(chan struct{}, 1),
+		toolsChanged:  make(chan struct{}, 1),
+		progress:      make(map[string]func()),
+		clock:         clock.Real,
+		tracer:        newTracer(nil),
+	}
+
+	// Without the aggregator's settings stderr is only logged at debug
+	s.stderr = newStderrLog(name, nil, nil, s.logger, clock.Real)
+
+	if cfg.OAuth != nil {
+		s.skew = newClockSkew(cfg.OAuth.EffectiveClockSkew(), s.logger)
+	}
+
+	return s, nil
 }
 
 // validateCommand checks if the command exists and is executable.
@@ -99,8 +168,41 @@ func (s *ManagedServer) validateCommand() error {
 	return nil
 }
 
-// Start initializes the backend server connection.
+// validateEngine checks that the container engine of a container server is
+// installed.
+func (s *ManagedServer) validateEngine() error {
+	path, err := exec.LookPath(s.engine)
+	if err != nil {
+		return &CommandNotFoundError{
+			ServerName: s.name,
+			Command:    s.engine,
+			Err:        err,
+			Type:       "command_not_in_path",
+			Suggestion: fmt.Sprintf("Install %s or set settings.container_engine to another engine", s.engine),
+		}
+	}
+
+	s.logger.Debug("container engine found in PATH", "engine", s.engine, "path", path)
+
+	return nil
+}
+
+// Start initializes the backend server connection, traced as a span.
 func (s *ManagedServer) Start(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "connect "+s.name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(tracing.Connection(s.name, string(s.transportType))...))
+	defer span.End()
+
+	err := s.start(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// start creates the client for the server's transport and initializes it.
+func (s *ManagedServer) start(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -112,7 +214,7 @@ func (s *ManagedServer) Start(ctx context.Context) error {
 		"starting server",
 		"transport", s.transportType,
 		"command", s.cfg.Command,
-		"url", s.cfg.URL,
+		"url", redact.URL(s.cfg.URL),
 	)
 
 	// Pre-flight validation for stdio commands
@@ -122,6 +224,12 @@ func (s *ManagedServer) Start(ctx context.Context) error {
 		}
 	}
 
+	if s.transportType == TransportContainer {
+		if err := s.validateEngine(); err != nil {
+			return err
+		}
+	}
+
 	// Create client based on transport type
 	var err error
 
@@ -137,7 +245,11 @@ func (s *ManagedServer) Start(ctx context.Context) error {
 	case TransportOAuthHTTP:
 		s.client, err = s.createOAuthHTTPClient()
 	case TransportInProcess:
-		return errors.New("in-process transport requires explicit server reference")
+		s.client, err = s.createInProcessClient()
+	case TransportWebSocket:
+		s.client, err = s.createWebSocketClient()
+	case TransportContainer:
+		s.client, err = s.createContainerClient()
 	default:
 		return fmt.Errorf("unsupported transport type: %s", s.transportType)
 	}
@@ -146,6 +258,26 @@ func (s *ManagedServer) Start(ctx context.Context) error {
 		return fmt.Errorf("creating %s client: %w", s.transportType, err)
 	}
 
+	stderr, hasStderr := client.GetStderr(s.client)
+
+	s.requests = newCancelTransport(s.client.GetTransport(), s.logger)
+	s.client = client.NewClient(s.requests)
+
+	if hasStderr {
+		go s.watchProcess(s.client, stderr)
+	}
+
+	if s.transportType == TransportWebSocket {
+		c := s.client
+		c.OnConnectionLost(func(err error) { s.connectionLost(c, err) })
+	}
+
+	s.client.OnNotification(s.handleNotification)
+
+	if s.sampling != nil {
+		client.WithSamplingHandler(s.sampling)(s.client)
+	}
+
 	// Start the client (required before Initialize)
 	if err := s.client.Start(ctx); err != nil {
 		return fmt.Errorf("starting %s client: %w", s.transportType, err)
@@ -161,16 +293,16 @@ func (s *ManagedServer) Start(ctx context.Context) error {
 	initReq.Params.Capabilities = mcp.ClientCapabilities{}
 
 	// Add diagnostic logging
-	initStart := time.Now()
+	initStart := s.clock.Now()
 	s.logger.Debug(
 		"beginning initialization",
 		"server", s.name,
 		"command", s.cfg.Command,
-		"args", s.cfg.Args,
+		"args", redact.Args(s.cfg.Args),
 	)
 
 	_, err = s.client.Initialize(ctx, initReq)
-	duration := time.Since(initStart)
+	duration := s.clock.Since(initStart)
 
 	if err != nil {
 		s.logger.Error(
@@ -188,6 +320,8 @@ func (s *ManagedServer) Start(ctx context.Context) error {
 				"error", closeErr)
 		}
 
+		s.removeContainer()
+
 		// Enhance error with context
 		return &InitializationError{
 			ServerName: s.name,
@@ -228,54 +362,14 @@ func (s *ManagedServer) Stop() error {
 		}
 	}
 
+	s.removeContainer()
+
 	s.started = false
 	s.logger.Info("server stopped")
 
 	return nil
 }
 
-// DiscoverTools queries the backend server for available tools.
-func (s *ManagedServer) DiscoverTools(ctx context.Context) ([]mcp.Tool, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if !s.started {
-		return nil, ErrServerNotStarted
-	}
-
-	result, err := s.client.ListTools(ctx, mcp.ListToolsRequest{})
-	if err != nil {
-		return nil, fmt.Errorf("listing tools: %w", err)
-	}
-
-	s.logger.Debug("discovered tools", "count", len(result.Tools))
-
-	return result.Tools, nil
-}
-
-// CallTool executes a tool on the backend server.
-func (s *ManagedServer) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if !s.started {
-		return nil, ErrServerNotStarted
-	}
-
-	req := mcp.CallToolRequest{}
-	req.Params.Name = name
-	req.Params.Arguments = args
-
-	s.logger.Debug("calling tool", "name", name)
-
-	result, err := s.client.CallTool(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("calling tool %s: %w", name, err)
-	}
-
-	return result, nil
-}
-
 // Name returns the server name.
 func (s *ManagedServer) Name() string {
 	return s.name
@@ -293,86 +387,3 @@ func (s *ManagedServer) IsStarted() bool {
 func (s *ManagedServer) Config() *config.ServerConfig {
 	return s.cfg
 }
-
-// DiscoverResources queries the backend server for available resources.
-func (s *ManagedServer) DiscoverResources(ctx context.Context) ([]mcp.Resource, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if !s.started {
-		return nil, ErrServerNotStarted
-	}
-
-	result, err := s.client.ListResources(ctx, mcp.ListResourcesRequest{})
-	if err != nil {
-		return nil, fmt.Errorf("listing resources: %w", err)
-	}
-
-	s.logger.Debug("discovered resources", "count", len(result.Resources))
-
-	return result.Resources, nil
-}
-
-// ReadResource reads a resource from the backend server.
-func (s *ManagedServer) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if !s.started {
-		return nil, ErrServerNotStarted
-	}
-
-	req := mcp.ReadResourceRequest{}
-	req.Params.URI = uri
-
-	s.logger.Debug("reading resource", "uri", uri)
-
-	result, err := s.client.ReadResource(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("reading resource %s: %w", uri, err)
-	}
-
-	return result, nil
-}
-
-// DiscoverPrompts queries the backend server for available prompts.
-func (s *ManagedServer) DiscoverPrompts(ctx context.Context) ([]mcp.Prompt, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if !s.started {
-		return nil, ErrServerNotStarted
-	}
-
-	result, err := s.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
-	if err != nil {
-		return nil, fmt.Errorf("listing prompts: %w", err)
-	}
-
-	s.logger.Debug("discovered prompts", "count", len(result.Prompts))
-
-	return result.Prompts, nil
-}
-
-// GetPrompt retrieves a prompt from the backend server.
-func (s *ManagedServer) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if !s.started {
-		return nil, ErrServerNotStarted
-	}
-
-	req := mcp.GetPromptRequest{}
-	req.Params.Name = name
-	req.Params.Arguments = args
-
-	s.logger.Debug("getting prompt", "name", name)
-
-	result, err := s.client.GetPrompt(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("getting prompt %s: %w", name, err)
-	}
-
-	return result, nil
-}
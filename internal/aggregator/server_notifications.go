@@ -0,0 +1,124 @@
+package aggregator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// handleNotification signals ToolsChanged on a tools/list_changed
+// notification, and passes progress notifications to the call they are
+// about. It runs on the transport's read loop, so it must not block or
+// issue requests itself.
+func (s *ManagedServer) handleNotification(n mcp.JSONRPCNotification) {
+	switch n.Method {
+	case string(mcp.MethodNotificationProgress):
+		token, _ := n.Params.AdditionalFields["progressToken"].(string)
+
+		s.progressMu.Lock()
+		notify := s.progress[token]
+		s.progressMu.Unlock()
+
+		if notify != nil {
+			notify()
+		}
+	case mcp.MethodNotificationToolsListChanged:
+		s.logger.Debug("backend tool list changed")
+
+		select {
+		case s.toolsChanged <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// watchProgress returns a new progress token whose notifications are passed
+// to notify until unwatchProgress is called with it.
+func (s *ManagedServer) watchProgress(notify func()) string {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	s.progressSeq++
+	token := fmt.Sprintf("assern-%d", s.progressSeq)
+	s.progress[token] = notify
+
+	return token
+}
+
+func (s *ManagedServer) unwatchProgress(token string) {
+	s.progressMu.Lock()
+	delete(s.progress, token)
+	s.progressMu.Unlock()
+}
+
+// ToolsChanged returns a channel that receives a value when the backend
+// reports that its tool list changed.
+func (s *ManagedServer) ToolsChanged() <-chan struct{} {
+	return s.toolsChanged
+}
+
+// Crashed returns a channel that receives a value each time the server's
+// stdio process exits or its WebSocket connection drops on its own. It
+// never fires for other transports.
+func (s *ManagedServer) Crashed() <-chan struct{} {
+	return s.crashed
+}
+
+// watchProcess drains the stdio process's stderr into s.stderr until the
+// process exits. If c is still the active client at that point the
+// exit was not caused by Stop, so the server is marked stopped and Crashed
+// is signalled.
+func (s *ManagedServer) watchProcess(c *client.Client, stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		s.stderr.write(scanner.Text())
+	}
+
+	// An over-long line stops the scanner early; keep draining until EOF
+	if scanner.Err() != nil {
+		_, _ = io.Copy(io.Discard, stderr)
+	}
+
+	s.stderr.close()
+
+	if s.crash(c) {
+		s.logger.Warn("server process exited unexpectedly")
+	}
+}
+
+// connectionLost handles the end of a WebSocket connection, reported by
+// c's transport, as watchProcess handles the exit of a stdio process.
+func (s *ManagedServer) connectionLost(c *client.Client, err error) {
+	if s.crash(c) {
+		s.logger.Warn("server connection lost", "error", err)
+	}
+}
+
+// crash marks the server stopped and signals Crashed if c is still the
+// active client, i.e. the backend went away without Stop being called.
+// It reports whether it did.
+func (s *ManagedServer) crash(c *client.Client) bool {
+	s.mu.Lock()
+	crashed := s.started && s.client == c
+	if crashed {
+		s.started = false
+		if err := c.Close(); err != nil {
+			s.logger.Debug("error closing client of crashed server", "error", err)
+		}
+
+		s.removeContainer()
+	}
+	s.mu.Unlock()
+
+	if crashed {
+		select {
+		case s.crashed <- struct{}{}:
+		default:
+		}
+	}
+
+	return crashed
+}
@@ -0,0 +1,212 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valksor/go-assern/internal/tracing"
+)
+
+// Ping sends an MCP ping to the backend server.
+func (s *ManagedServer) Ping(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return ErrServerNotStarted
+	}
+
+	if err := s.client.Ping(ctx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+
+	return nil
+}
+
+// DiscoverTools queries the backend server for available tools.
+func (s *ManagedServer) DiscoverTools(ctx context.Context) ([]mcp.Tool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return nil, ErrServerNotStarted
+	}
+
+	tools, err := collectPages(ctx, s.logger, "tools", func(ctx context.Context, cursor mcp.Cursor) ([]mcp.Tool, mcp.Cursor, error) {
+		req := mcp.ListToolsRequest{}
+		req.Params.Cursor = cursor
+
+		result, err := s.client.ListToolsByPage(ctx, req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return result.Tools, result.NextCursor, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing tools: %w", err)
+	}
+
+	s.logger.Debug("discovered tools", "count", len(tools))
+
+	return tools, nil
+}
+
+// CallTool executes a tool on the backend server.
+func (s *ManagedServer) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return nil, ErrServerNotStarted
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+
+	ctx, span := s.tracer.Start(ctx, "call "+s.name+"."+name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(tracing.ToolCall(name, s.name)...),
+		trace.WithAttributes(tracing.Transport.String(string(s.transportType))))
+	defer span.End()
+
+	// The backend can continue the trace from _meta.traceparent, and log
+	// the call's correlation ID
+	if meta := withCorrelationMeta(ctx, withTraceMeta(ctx, callMeta(ctx))); meta != nil {
+		req.Params.Meta = &mcp.Meta{AdditionalFields: meta}
+	}
+
+	// Progress notifications keep a call with a call timeout alive
+	if notify := progressNotify(ctx); notify != nil {
+		token := s.watchProgress(notify)
+		defer s.unwatchProgress(token)
+
+		if req.Params.Meta == nil {
+			req.Params.Meta = &mcp.Meta{}
+		}
+
+		req.Params.Meta.ProgressToken = token
+	}
+
+	s.logger.Debug("calling tool", "name", name, "correlation_id", CorrelationID(ctx))
+
+	result, err := s.client.CallTool(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, fmt.Errorf("calling tool %s: %w", name, err)
+	}
+
+	if result.IsError {
+		span.SetStatus(codes.Error, toolResultText(result))
+	}
+
+	return result, nil
+}
+
+// DiscoverResources queries the backend server for available resources.
+func (s *ManagedServer) DiscoverResources(ctx context.Context) ([]mcp.Resource, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return nil, ErrServerNotStarted
+	}
+
+	resources, err := collectPages(ctx, s.logger, "resources", func(ctx context.Context, cursor mcp.Cursor) ([]mcp.Resource, mcp.Cursor, error) {
+		req := mcp.ListResourcesRequest{}
+		req.Params.Cursor = cursor
+
+		result, err := s.client.ListResourcesByPage(ctx, req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return result.Resources, result.NextCursor, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing resources: %w", err)
+	}
+
+	s.logger.Debug("discovered resources", "count", len(resources))
+
+	return resources, nil
+}
+
+// ReadResource reads a resource from the backend server.
+func (s *ManagedServer) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return nil, ErrServerNotStarted
+	}
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+
+	s.logger.Debug("reading resource", "uri", uri)
+
+	result, err := s.client.ReadResource(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("reading resource %s: %w", uri, err)
+	}
+
+	return result, nil
+}
+
+// DiscoverPrompts queries the backend server for available prompts.
+func (s *ManagedServer) DiscoverPrompts(ctx context.Context) ([]mcp.Prompt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return nil, ErrServerNotStarted
+	}
+
+	prompts, err := collectPages(ctx, s.logger, "prompts", func(ctx context.Context, cursor mcp.Cursor) ([]mcp.Prompt, mcp.Cursor, error) {
+		req := mcp.ListPromptsRequest{}
+		req.Params.Cursor = cursor
+
+		result, err := s.client.ListPromptsByPage(ctx, req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return result.Prompts, result.NextCursor, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing prompts: %w", err)
+	}
+
+	s.logger.Debug("discovered prompts", "count", len(prompts))
+
+	return prompts, nil
+}
+
+// GetPrompt retrieves a prompt from the backend server.
+func (s *ManagedServer) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.started {
+		return nil, ErrServerNotStarted
+	}
+
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+
+	s.logger.Debug("getting prompt", "name", name)
+
+	result, err := s.client.GetPrompt(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("getting prompt %s: %w", name, err)
+	}
+
+	return result, nil
+}
@@ -1,10 +1,13 @@
 package aggregator
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -28,6 +31,10 @@ var sharedHTTPTransport = &http.Transport{
 	TLSHandshakeTimeout: 10 * time.Second,
 }
 
+// oauthHTTPTimeout bounds requests to authorization servers, as mcp-go's
+// default OAuth client does.
+const oauthHTTPTimeout = 30 * time.Second
+
 // sharedHTTPClient is the default HTTP client with connection pooling.
 var sharedHTTPClient = &http.Client{
 	Transport: sharedHTTPTransport,
@@ -41,12 +48,21 @@ func detectTransport(cfg *config.ServerConfig) TransportType {
 		return TransportType(cfg.Transport)
 	}
 
+	// An image runs the server in a container
+	if cfg.Image != "" {
+		return TransportContainer
+	}
+
 	// Auto-detect OAuth transports when OAuth config is present
 	if cfg.OAuth != nil && cfg.URL != "" {
 		return TransportOAuthHTTP // Default OAuth to HTTP (modern MCP standard)
 	}
 
 	// Auto-detect based on which fields are set
+	if isWebSocketURL(cfg.URL) {
+		return TransportWebSocket
+	}
+
 	if cfg.URL != "" {
 		return TransportHTTP // Default URL-based to Streamable HTTP (modern MCP standard)
 	}
@@ -58,6 +74,17 @@ func detectTransport(cfg *config.ServerConfig) TransportType {
 	return ""
 }
 
+// isWebSocketURL reports whether rawURL has a ws or wss scheme.
+func isWebSocketURL(rawURL string) bool {
+	scheme, _, ok := strings.Cut(rawURL, "://")
+
+	return ok && (strings.EqualFold(scheme, "ws") || strings.EqualFold(scheme, "wss"))
+}
+
+// ServerEnvVar is set to the server name in the environment of every stdio
+// backend, next to ASSERN_PROJECT.
+const ServerEnvVar = "ASSERN_SERVER"
+
 // createStdioClient creates a stdio transport client.
 func (s *ManagedServer) createStdioClient() (*client.Client, error) {
 	// Ensure PATH is preserved even if mcp-go changes behavior
@@ -70,7 +97,61 @@ func (s *ManagedServer) createStdioClient() (*client.Client, error) {
 		}
 	}
 
-	return client.NewStdioMCPClient(s.cfg.Command, env, s.cfg.Args...)
+	env = withLocale(env, s.conn.Locale)
+
+	enc, err := config.LookupEncoding(s.conn.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc != nil {
+		t, err := startTranscodedStdio(stdioCommand(context.Background(), s.conn.Command, env, s.conn.Args, s.conn.WorkDir), enc, s.clock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start stdio transport: %w", err)
+		}
+
+		s.logger.Debug("transcoding server output", "encoding", s.conn.Encoding)
+
+		return client.NewClient(t), nil
+	}
+
+	if dir := s.conn.WorkDir; dir != "" {
+		return client.NewStdioMCPClientWithOptions(s.conn.Command, env, s.conn.Args,
+			transport.WithCommandFunc(func(ctx context.Context, command string, env, args []string) (*exec.Cmd, error) {
+				return stdioCommand(ctx, command, env, args, dir), nil
+			}))
+	}
+
+	return client.NewStdioMCPClient(s.conn.Command, env, s.conn.Args...)
+}
+
+// stdioCommand returns the command of a stdio server run in dir ("" for
+// assern's own), with env added to assern's environment as mcp-go does.
+func stdioCommand(ctx context.Context, command string, env, args []string, dir string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Dir = dir
+
+	return cmd
+}
+
+// withLocale adds LANG and LC_ALL set to locale to env, except those env
+// already sets. An empty locale leaves env alone.
+func withLocale(env []string, locale string) []string {
+	if locale == "" {
+		return env
+	}
+
+	// Never append into the backing array of the server's own env
+	env = slices.Clip(env)
+
+	for _, key := range []string{"LANG", "LC_ALL"} {
+		if !envContains(env, key) {
+			env = append(env, key+"="+locale)
+		}
+	}
+
+	return env
 }
 
 // envContains checks if a specific environment variable exists in the env slice.
@@ -89,28 +170,42 @@ func envContains(env []string, key string) bool {
 func (s *ManagedServer) createSSEClient() (*client.Client, error) {
 	opts := []transport.ClientOption{
 		transport.WithHTTPClient(sharedHTTPClient), // Use connection-pooled client
+		transport.WithHeaderFunc(requestHeaders),
 	}
 
 	// Add custom headers if configured
-	if len(s.cfg.Headers) > 0 {
-		opts = append(opts, transport.WithHeaders(s.cfg.Headers))
+	if len(s.conn.Headers) > 0 {
+		opts = append(opts, transport.WithHeaders(s.conn.Headers))
 	}
 
-	return client.NewSSEMCPClient(s.cfg.URL, opts...)
+	return client.NewSSEMCPClient(s.conn.URL, opts...)
 }
 
 // createHTTPClient creates a Streamable HTTP transport client with optional headers.
 func (s *ManagedServer) createHTTPClient() (*client.Client, error) {
 	opts := []transport.StreamableHTTPCOption{
 		transport.WithHTTPBasicClient(sharedHTTPClient), // Use connection-pooled client
+		transport.WithHTTPHeaderFunc(requestHeaders),
 	}
 
 	// Add custom headers if configured
-	if len(s.cfg.Headers) > 0 {
-		opts = append(opts, transport.WithHTTPHeaders(s.cfg.Headers))
+	if len(s.conn.Headers) > 0 {
+		opts = append(opts, transport.WithHTTPHeaders(s.conn.Headers))
+	}
+
+	opts = append(opts, s.listenOptions()...)
+
+	return client.NewStreamableHttpClient(s.conn.URL, opts...)
+}
+
+// listenOptions keeps a GET stream open to Streamable HTTP backends with
+// sampling set, on which they send their sampling requests.
+func (s *ManagedServer) listenOptions() []transport.StreamableHTTPCOption {
+	if s.sampling == nil {
+		return nil
 	}
 
-	return client.NewStreamableHttpClient(s.cfg.URL, opts...)
+	return []transport.StreamableHTTPCOption{transport.WithContinuousListening()}
 }
 
 // buildOAuthConfig constructs the mcp-go OAuth config from the server's
@@ -118,38 +213,33 @@ func (s *ManagedServer) createHTTPClient() (*client.Client, error) {
 // runs (and are shared by servers referencing the same auth profile).
 func (s *ManagedServer) buildOAuthConfig() transport.OAuthConfig {
 	oauthCfg := transport.OAuthConfig{
-		ClientID:              s.cfg.OAuth.ClientID,
-		ClientSecret:          s.cfg.OAuth.ClientSecret,
-		RedirectURI:           s.cfg.OAuth.RedirectURI,
-		Scopes:                s.cfg.OAuth.Scopes,
-		AuthServerMetadataURL: s.cfg.OAuth.AuthServerMetadataURL,
-		PKCEEnabled:           s.cfg.OAuth.PKCEEnabled,
+		ClientID:              s.conn.OAuth.ClientID,
+		ClientSecret:          s.conn.OAuth.ClientSecret,
+		RedirectURI:           s.conn.OAuth.RedirectURI,
+		Scopes:                s.conn.OAuth.Scopes,
+		AuthServerMetadataURL: s.conn.OAuth.AuthServerMetadataURL,
+		PKCEEnabled:           s.conn.OAuth.PKCEEnabled,
+		// Measures clock skew from the authorization server's responses
+		HTTPClient: &http.Client{
+			Transport: &skewTransport{base: sharedHTTPTransport, skew: s.skew, clock: s.clock},
+			Timeout:   oauthHTTPTimeout,
+		},
 	}
 
-	if dir, err := config.TokensDir(); err != nil {
+	if store, err := oauthTokenStore(s.name, s.cfg, s.skew); err != nil {
 		s.logger.Warn("oauth token cache unavailable; tokens will not persist", "error", err)
 	} else {
-		oauthCfg.TokenStore = newFileTokenStore(dir, s.tokenCacheKey())
+		oauthCfg.TokenStore = store
 	}
 
 	return oauthCfg
 }
 
-// tokenCacheKey identifies the cached-token bucket: the shared OAuth profile
-// reference when set, otherwise the server's own name.
-func (s *ManagedServer) tokenCacheKey() string {
-	if s.cfg.OAuthRef != "" {
-		return s.cfg.OAuthRef
-	}
-
-	return s.name
-}
-
 // missingOAuthErr returns a descriptive error when OAuth config is absent,
 // distinguishing an unresolved profile reference from a missing inline config.
 func (s *ManagedServer) missingOAuthErr(transportName string) error {
-	if s.cfg.OAuthRef != "" {
-		return fmt.Errorf("%s transport: oauth_ref %q not found in auth profiles: %w", transportName, s.cfg.OAuthRef, ErrOAuthRequired)
+	if s.conn.OAuthRef != "" {
+		return fmt.Errorf("%s transport: oauth_ref %q not found in auth profiles: %w", transportName, s.conn.OAuthRef, ErrOAuthRequired)
 	}
 
 	return fmt.Errorf("%s transport: %w", transportName, ErrOAuthRequired)
@@ -157,36 +247,54 @@ func (s *ManagedServer) missingOAuthErr(transportName string) error {
 
 // createOAuthSSEClient creates an SSE client with OAuth authentication.
 func (s *ManagedServer) createOAuthSSEClient() (*client.Client, error) {
-	if s.cfg.OAuth == nil {
+	if s.conn.OAuth == nil {
 		return nil, s.missingOAuthErr("oauth-sse")
 	}
 
 	oauthCfg := s.buildOAuthConfig()
 
-	opts := []transport.ClientOption{}
+	opts := []transport.ClientOption{transport.WithHeaderFunc(requestHeaders)}
 
 	// Add additional headers if configured
-	if len(s.cfg.Headers) > 0 {
-		opts = append(opts, transport.WithHeaders(s.cfg.Headers))
+	if len(s.conn.Headers) > 0 {
+		opts = append(opts, transport.WithHeaders(s.conn.Headers))
 	}
 
-	return client.NewOAuthSSEClient(s.cfg.URL, oauthCfg, opts...)
+	return client.NewOAuthSSEClient(s.conn.URL, oauthCfg, opts...)
 }
 
 // createOAuthHTTPClient creates a Streamable HTTP client with OAuth authentication.
 func (s *ManagedServer) createOAuthHTTPClient() (*client.Client, error) {
-	if s.cfg.OAuth == nil {
+	if s.conn.OAuth == nil {
 		return nil, s.missingOAuthErr("oauth-http")
 	}
 
 	oauthCfg := s.buildOAuthConfig()
 
-	opts := []transport.StreamableHTTPCOption{}
+	opts := []transport.StreamableHTTPCOption{transport.WithHTTPHeaderFunc(requestHeaders)}
 
 	// Add additional headers if configured
-	if len(s.cfg.Headers) > 0 {
-		opts = append(opts, transport.WithHTTPHeaders(s.cfg.Headers))
+	if len(s.conn.Headers) > 0 {
+		opts = append(opts, transport.WithHTTPHeaders(s.conn.Headers))
+	}
+
+	opts = append(opts, s.listenOptions()...)
+
+	return client.NewOAuthStreamableHttpClient(s.conn.URL, oauthCfg, opts...)
+}
+
+// createInProcessClient creates a client calling the in-process server
+// registered under the server's name (see Options.InProcess) directly,
+// without a process or connection in between.
+func (s *ManagedServer) createInProcessClient() (*client.Client, error) {
+	if s.inProcess == nil {
+		return nil, fmt.Errorf("no in-process server registered as %s", s.name)
+	}
+
+	var opts []transport.InProcessOption
+	if s.sampling != nil {
+		opts = append(opts, transport.WithSamplingHandler(s.sampling))
 	}
 
-	return client.NewOAuthStreamableHttpClient(s.cfg.URL, oauthCfg, opts...)
+	return client.NewClient(transport.NewInProcessTransportWithOptions(s.inProcess, opts...)), nil
 }
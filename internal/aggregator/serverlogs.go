@@ -0,0 +1,244 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+const (
+	// serverLogSize is how many stderr lines are kept per server for
+	// 'assern logs'.
+	serverLogSize = 200
+
+	// logFollowerBuffer is how many lines a follower may fall behind
+	// before lines are dropped for it; the process is never held up.
+	logFollowerBuffer = 256
+)
+
+// LogLine is a line a stdio server wrote to stderr.
+type LogLine struct {
+	Time   time.Time `json:"time"`
+	Server string    `json:"server"`
+	Line   string    `json:"line"`
+}
+
+// serverLogs keeps the last stderr lines of each server and hands new ones
+// to followers.
+type serverLogs struct {
+	mu        sync.Mutex
+	lines     map[string][]LogLine // Oldest first, at most serverLogSize
+	followers map[*logFollower]struct{}
+}
+
+// logFollower receives the new lines of one server.
+type logFollower struct {
+	server string
+	ch     chan LogLine
+}
+
+func newServerLogs() *serverLogs {
+	return &serverLogs{
+		lines:     make(map[string][]LogLine),
+		followers: make(map[*logFollower]struct{}),
+	}
+}
+
+func (l *serverLogs) add(line LogLine) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lines := l.lines[line.Server]
+	if len(lines) >= serverLogSize {
+		lines = lines[len(lines)-serverLogSize+1:]
+	}
+
+	l.lines[line.Server] = append(lines, line)
+
+	for f := range l.followers {
+		if f.server != line.Server {
+			continue
+		}
+
+		select {
+		case f.ch <- line:
+		default:
+		}
+	}
+}
+
+// tail returns the last n lines of server, oldest first; n <= 0 returns
+// all kept lines.
+func (l *serverLogs) tail(server string, n int) []LogLine {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.tailLocked(server, n)
+}
+
+func (l *serverLogs) tailLocked(server string, n int) []LogLine {
+	lines := l.lines[server]
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return append([]LogLine(nil), lines...)
+}
+
+// follow returns the last n lines of server like tail, and a channel
+// receiving the lines after them until stop is called.
+func (l *serverLogs) follow(server string, n int) ([]LogLine, <-chan LogLine, func()) {
+	f := &logFollower{server: server, ch: make(chan LogLine, logFollowerBuffer)}
+
+	l.mu.Lock()
+	l.followers[f] = struct{}{}
+	lines := l.tailLocked(server, n)
+	l.mu.Unlock()
+
+	return lines, f.ch, func() {
+		l.mu.Lock()
+		delete(l.followers, f)
+		l.mu.Unlock()
+	}
+}
+
+// stderrLog receives the stderr lines of one server's process: it logs
+// them, keeps them in serverLogs and appends them to the server's file.
+type stderrLog struct {
+	server string
+	logger *slog.Logger
+	level  slog.Level
+	logged bool
+	logs   *serverLogs // nil = not kept
+	clock  clock.Clock
+
+	mu   sync.Mutex
+	path string   // "" = no file
+	file *os.File // Opened at the first line after each close
+}
+
+// newStderrLog returns the sink of server's stderr for the settings.
+func newStderrLog(server string, settings *config.ServerLogsConfig, logs *serverLogs, logger *slog.Logger, clk clock.Clock) *stderrLog {
+	level, logged := settings.EffectiveLevel()
+
+	l := &stderrLog{server: server, logger: logger, level: level, logged: logged, logs: logs, clock: clk}
+
+	dir, err := settings.FilesDir()
+	if err != nil {
+		logger.Warn("server stderr is not written to a file", "error", err)
+	} else if dir != "" {
+		l.path = filepath.Join(dir, tokenKeySanitizer.ReplaceAllString(server, "_")+".log")
+	}
+
+	return l
+}
+
+func (l *stderrLog) write(line string) {
+	if l.logged {
+		l.logger.Log(context.Background(), l.level, "server stderr", "line", line)
+	}
+
+	now := l.clock.Now()
+
+	if l.logs != nil {
+		l.logs.add(LogLine{Time: now, Server: l.server, Line: line})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.path == "" {
+		return
+	}
+
+	if l.file == nil {
+		file, err := openServerLogFile(l.path)
+		if err != nil {
+			// Give up on the file rather than failing on every line
+			l.logger.Warn("server stderr is not written to a file", "path", l.path, "error", err)
+			l.path = ""
+
+			return
+		}
+
+		l.file = file
+	}
+
+	if _, err := fmt.Fprintf(l.file, "%s %s\n", now.Format(time.RFC3339), line); err != nil {
+		l.logger.Debug("writing server stderr to file", "path", l.path, "error", err)
+	}
+}
+
+// close closes the file once the process's stderr ended.
+func (l *stderrLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+}
+
+// openServerLogFile opens a server's log file for appending, creating it
+// and its directory readable by the user only.
+func openServerLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+}
+
+// ServerLogs returns the last n stderr lines of a configured server, oldest
+// first (all kept lines, up to serverLogSize, when n <= 0).
+func (a *Aggregator) ServerLogs(server string, n int) ([]LogLine, error) {
+	if !a.isConfigured(server) {
+		return nil, fmt.Errorf("%w: %s", ErrServerNotFound, server)
+	}
+
+	return a.logs.tail(server, n), nil
+}
+
+// FollowServerLogs returns the last n stderr lines of a configured server
+// like ServerLogs, and a channel receiving its new lines until stop is
+// called. Lines arriving while the receiver falls behind are dropped.
+func (a *Aggregator) FollowServerLogs(server string, n int) ([]LogLine, <-chan LogLine, func(), error) {
+	if !a.isConfigured(server) {
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrServerNotFound, server)
+	}
+
+	lines, next, stop := a.logs.follow(server, n)
+
+	return lines, next, stop, nil
+}
+
+// isConfigured reports whether server is in the current configuration.
+func (a *Aggregator) isConfigured(server string) bool {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	_, ok := a.cfg.Servers[server]
+
+	return ok
+}
+
+// serverLogsConfig returns settings.server_logs of the current
+// configuration.
+func (a *Aggregator) serverLogsConfig() *config.ServerLogsConfig {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.cfg == nil || a.cfg.Settings == nil {
+		return nil
+	}
+
+	return a.cfg.Settings.ServerLogs
+}
@@ -0,0 +1,195 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestServerLogs_Tail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		added     int
+		n         int
+		wantLen   int
+		wantFirst string
+	}{
+		{name: "empty", n: 10},
+		{name: "fewer than asked", added: 3, n: 10, wantLen: 3, wantFirst: "line0"},
+		{name: "last n", added: 30, n: 10, wantLen: 10, wantFirst: "line20"},
+		{name: "all kept", added: 30, wantLen: 30, wantFirst: "line0"},
+		{name: "oldest dropped", added: serverLogSize + 5, wantLen: serverLogSize, wantFirst: "line5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logs := newServerLogs()
+			for i := range tt.added {
+				logs.add(LogLine{Server: "github", Line: fmt.Sprintf("line%d", i)})
+			}
+
+			logs.add(LogLine{Server: "other", Line: "not github"})
+
+			got := logs.tail("github", tt.n)
+			if len(got) != tt.wantLen {
+				t.Fatalf("len = %d, want %d", len(got), tt.wantLen)
+			}
+
+			if tt.wantLen > 0 && got[0].Line != tt.wantFirst {
+				t.Errorf("first line = %q, want %q", got[0].Line, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestServerLogs_Follow(t *testing.T) {
+	t.Parallel()
+
+	logs := newServerLogs()
+	logs.add(LogLine{Server: "github", Line: "before"})
+
+	backlog, next, stop := logs.follow("github", 0)
+	if len(backlog) != 1 || backlog[0].Line != "before" {
+		t.Errorf("backlog = %v", backlog)
+	}
+
+	logs.add(LogLine{Server: "other", Line: "elsewhere"})
+	logs.add(LogLine{Server: "github", Line: "after"})
+
+	if got := <-next; got.Line != "after" {
+		t.Errorf("followed line = %q, want after", got.Line)
+	}
+
+	stop()
+	logs.add(LogLine{Server: "github", Line: "stopped"})
+
+	select {
+	case got := <-next:
+		t.Errorf("line %q after stop", got.Line)
+	default:
+	}
+}
+
+func TestStderrLog_File(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC)
+	dir := t.TempDir()
+	logs := newServerLogs()
+
+	l := newStderrLog("my/server", &config.ServerLogsConfig{Level: "off", Files: true, Dir: dir}, logs,
+		slog.New(slog.DiscardHandler), clock.NewFake(now))
+	l.write("starting")
+	l.write("ready")
+	l.close()
+
+	// A restarted process appends to the same file
+	l.write("again")
+	l.close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "my_server.log"))
+	if err != nil {
+		t.Fatalf("reading log file: %v", err)
+	}
+
+	want := "2026-01-01T09:30:00Z starting\n2026-01-01T09:30:00Z ready\n2026-01-01T09:30:00Z again\n"
+	if string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+
+	if got := logs.tail("my/server", 0); len(got) != 3 || !got[0].Time.Equal(now) {
+		t.Errorf("kept lines = %v", got)
+	}
+}
+
+func TestServerStderrCaptured(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestStdioHelperProcess$"},
+	}
+
+	aggCfg := config.NewConfig()
+	aggCfg.Servers["helper"] = cfg
+
+	agg, err := New(Options{Config: aggCfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := agg.ServerLogs("missing", 0); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("ServerLogs(missing) error = %v, want ErrServerNotFound", err)
+	}
+
+	srv, err := NewManagedServer("helper", cfg, []string{envStdioHelper + "=1"}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	dir := t.TempDir()
+	srv.stderr = newStderrLog("helper", &config.ServerLogsConfig{Files: true, Dir: dir}, agg.logs, srv.logger, agg.clock)
+
+	_, next, stop, err := agg.FollowServerLogs("helper", 0)
+	if err != nil {
+		t.Fatalf("FollowServerLogs: %v", err)
+	}
+	defer stop()
+
+	if err := srv.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	if _, err := srv.CallTool(t.Context(), "log", map[string]any{"line": "token refreshed"}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	for {
+		select {
+		case line := <-next:
+			if line.Line != "token refreshed" {
+				continue
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("stderr line not captured")
+		}
+
+		break
+	}
+
+	lines, err := agg.ServerLogs("helper", 1)
+	if err != nil || len(lines) != 1 || lines[0].Server != "helper" || lines[0].Line != "token refreshed" {
+		t.Errorf("ServerLogs = %v, %v", lines, err)
+	}
+
+	_ = srv.Stop()
+
+	// The file is written after the line is kept; poll until it is there
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		data, _ := os.ReadFile(filepath.Join(dir, "helper.log"))
+		if strings.Contains(string(data), " token refreshed\n") {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("log file = %q, want the stderr line", data)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
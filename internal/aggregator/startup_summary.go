@@ -0,0 +1,101 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// startupSummaryLogger names the logger of the startup summary notification.
+const startupSummaryLogger = "assern"
+
+// sendStartupSummary sends the client that finished initializing one MCP
+// logging notification saying what it got: servers up and down, tool count,
+// project and output format. Clients that surface server logs show it
+// without the user checking the terminal.
+//
+// It handles notifications/initialized, the first point at which the spec
+// allows server notifications, so the client cannot have chosen a log level
+// yet; the summary is sent directly rather than through
+// SendLogMessageToClient, whose default level (error) would drop it.
+func (a *Aggregator) sendStartupSummary(ctx context.Context, _ mcp.JSONRPCNotification) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || a.mcpServer == nil || !canLog(ctx) {
+		return
+	}
+
+	level, data := a.startupSummary()
+
+	err := a.mcpServer.SendNotificationToSpecificClient(session.SessionID(), string(mcp.MethodNotificationMessage), map[string]any{
+		"level":  level,
+		"logger": startupSummaryLogger,
+		"data":   data,
+	})
+	if err != nil {
+		a.logger.Debug("could not send startup summary to client", "session", session.SessionID(), "error", err)
+	}
+}
+
+// startupSummary returns the level and data of the startup summary: info,
+// or warning when a server is down or the configuration failed to load.
+func (a *Aggregator) startupSummary() (mcp.LoggingLevel, map[string]any) {
+	status := a.Status()
+
+	up := make([]string, 0, status.ServersUp)
+	down := make([]string, 0, status.ServersDown)
+
+	for _, s := range status.Servers {
+		if s.State == ServerStateUp || s.State == ServerStateIdle {
+			up = append(up, s.Name)
+		} else {
+			down = append(down, s.Name)
+		}
+	}
+
+	format := a.resultFormat()
+
+	parts := []string{fmt.Sprintf("%d of %d servers up", len(up), len(up)+len(down))}
+	if len(down) > 0 {
+		parts[0] += " (down: " + strings.Join(down, ", ") + ")"
+	}
+
+	if status.Tools == 1 {
+		parts = append(parts, "1 tool")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d tools", status.Tools))
+	}
+
+	if status.Project != "" {
+		parts = append(parts, "project "+status.Project)
+	}
+
+	parts = append(parts, "output "+format)
+
+	data := map[string]any{
+		"message":       "assern: " + strings.Join(parts, ", "),
+		"servers_up":    up,
+		"servers_down":  down,
+		"tools":         status.Tools,
+		"output_format": format,
+	}
+
+	if status.Project != "" {
+		data["project"] = status.Project
+	}
+
+	level := mcp.LoggingLevelInfo
+	if len(down) > 0 {
+		level = mcp.LoggingLevelWarning
+	}
+
+	if status.ConfigError != "" {
+		data["message"] = "assern: configuration failed to load, running without servers (see assern_status): " + status.ConfigError
+		data["config_error"] = status.ConfigError
+		level = mcp.LoggingLevelWarning
+	}
+
+	return level, data
+}
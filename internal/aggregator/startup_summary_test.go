@@ -0,0 +1,75 @@
+package aggregator
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestStartupSummary(t *testing.T) {
+	t.Parallel()
+
+	withServer := config.NewConfig()
+	withServer.Servers["github"] = &config.ServerConfig{Command: "github-mcp"}
+
+	tests := []struct {
+		name        string
+		opts        Options
+		wantLevel   mcp.LoggingLevel
+		wantMessage string
+		wantDown    []string
+	}{
+		{
+			name:        "nothing configured",
+			opts:        Options{Config: config.NewConfig(), OutputFormat: "toon"},
+			wantLevel:   mcp.LoggingLevelInfo,
+			wantMessage: "assern: 0 of 0 servers up, 0 tools, output toon",
+			wantDown:    []string{},
+		},
+		{
+			name:        "server not running",
+			opts:        Options{Config: withServer},
+			wantLevel:   mcp.LoggingLevelWarning,
+			wantMessage: "assern: 0 of 1 servers up (down: github), 0 tools, output json",
+			wantDown:    []string{"github"},
+		},
+		{
+			name:        "failsafe mode",
+			opts:        Options{Config: config.NewConfig(), ConfigError: errors.New("bad mcp.json")},
+			wantLevel:   mcp.LoggingLevelWarning,
+			wantMessage: "assern: configuration failed to load, running without servers (see assern_status): bad mcp.json",
+			wantDown:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Logger = slog.New(slog.DiscardHandler)
+
+			agg, err := New(tt.opts)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			level, data := agg.startupSummary()
+			if level != tt.wantLevel {
+				t.Errorf("level = %s, want %s", level, tt.wantLevel)
+			}
+
+			if data["message"] != tt.wantMessage {
+				t.Errorf("message = %q, want %q", data["message"], tt.wantMessage)
+			}
+
+			if down, _ := data["servers_down"].([]string); !slices.Equal(down, tt.wantDown) {
+				t.Errorf("servers_down = %v, want %v", down, tt.wantDown)
+			}
+		})
+	}
+}
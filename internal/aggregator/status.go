@@ -0,0 +1,269 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/redact"
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// ToolStatusName is the built-in tool reporting aggregator status. It is
+// always exposed, in discovery mode too, so agents can check which backends
+// are available before planning multi-step work.
+const ToolStatusName = "assern_status"
+
+// Server states reported by Status. A restarting server, or one in a
+// scheduled maintenance window, counts as down; an idle one (lazy, not
+// started before its first call) counts as up.
+const (
+	ServerStateUp          = "up"
+	ServerStateDown        = "down"
+	ServerStateRestarting  = "restarting"
+	ServerStateIdle        = "idle"
+	ServerStateMaintenance = "maintenance"
+)
+
+// Status is a point-in-time summary of the aggregator.
+type Status struct {
+	Version     string         `json:"version"`
+	Instance    string         `json:"instance"`
+	InstanceID  string         `json:"instance_id,omitempty"`
+	Project     string         `json:"project,omitempty"`
+	ProjectDir  string         `json:"project_dir,omitempty"`
+	Detection   string         `json:"project_source,omitempty"` // How the project was detected
+	Fingerprint string         `json:"config_fingerprint"`
+	StartedAt   time.Time      `json:"started_at"`
+	Uptime      string         `json:"uptime"`
+	LastReload  *time.Time     `json:"last_reload,omitempty"`
+	ServersUp   int            `json:"servers_up"`
+	ServersDown int            `json:"servers_down"`
+	Tools       int            `json:"tools"`
+	Resources   int            `json:"resources"`
+	Prompts     int            `json:"prompts"`
+	Servers     []ServerStatus `json:"servers"`
+	// Clients are the connected MCP client sessions
+	Clients []ClientStatus `json:"clients,omitempty"`
+	// ConfigError is set in failsafe mode (see Aggregator.ConfigError)
+	ConfigError string `json:"config_error,omitempty"`
+}
+
+// ServerStatus describes one configured or running backend server.
+type ServerStatus struct {
+	Name      string       `json:"name"`
+	State     string       `json:"state"`
+	Health    HealthStatus `json:"health"`
+	Tools     int          `json:"tools"`
+	Resources int          `json:"resources"`
+	Prompts   int          `json:"prompts"`
+	Transport string       `json:"transport,omitempty"`
+	Endpoint  string       `json:"endpoint,omitempty"` // URL or command line
+	// StartedAt and Uptime refer to the last successful (re)start
+	StartedAt time.Time `json:"started_at,omitzero"`
+	Uptime    string    `json:"uptime,omitempty"`
+	// Startup latency of the last (re)start, in milliseconds
+	InitializeMS int64 `json:"initialize_ms,omitempty"`
+	ListToolsMS  int64 `json:"list_tools_ms,omitempty"`
+	// LastError is the most recent start or call failure, kept after recovery
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+	// AuthURL is where the user can authorize a needs_auth server
+	AuthURL string `json:"authorization_url,omitempty"`
+	// MaintenanceUntil is when the current maintenance window ends
+	MaintenanceUntil time.Time `json:"maintenance_until,omitzero"`
+	// ClockSkewMS is how far the clock of the server's OAuth authorization
+	// server was measured ahead of the local one (negative: behind), set
+	// when it exceeds oauth.clock_skew
+	ClockSkewMS int64 `json:"clock_skew_ms,omitempty"`
+}
+
+// serverEndpoint describes where a server is reached: its URL without query
+// string (which may carry credentials) or password, or its command line with
+// secrets redacted.
+func serverEndpoint(cfg *config.ServerConfig) string {
+	if cfg == nil {
+		return ""
+	}
+
+	if cfg.URL != "" {
+		endpoint, _, _ := strings.Cut(cfg.URL, "?")
+
+		return redact.URL(endpoint)
+	}
+
+	return redact.CommandLine(cfg.Command, cfg.Args)
+}
+
+// Status returns the current aggregator status. A server is down when it is
+// configured but not running, or running but unhealthy or awaiting
+// authorization.
+func (a *Aggregator) Status() Status {
+	a.cfgMu.RLock()
+	cfg := a.cfg
+	lastReload := a.lastReload
+	configErr := a.configErr
+	a.cfgMu.RUnlock()
+
+	var configured map[string]*config.ServerConfig
+	if cfg != nil {
+		configured = config.GetEffectiveServers(cfg)
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	names := make(map[string]struct{}, len(configured)+len(a.servers))
+	for name := range configured {
+		names[name] = struct{}{}
+	}
+
+	for name := range a.servers {
+		names[name] = struct{}{}
+	}
+
+	status := Status{
+		Version:     version.Version,
+		Instance:    config.InstanceName(cfg),
+		InstanceID:  a.instanceID,
+		Project:     a.ProjectName(),
+		Fingerprint: config.Fingerprint(cfg),
+		StartedAt:   a.startedAt,
+		Uptime:      a.clock.Since(a.startedAt).Round(time.Second).String(),
+		Tools:       a.tools.Count(),
+		Resources:   a.resources.Count(),
+		Prompts:     a.prompts.Count(),
+		Servers:     make([]ServerStatus, 0, len(names)),
+		Clients:     a.clients.all(),
+	}
+
+	if a.projectCtx != nil {
+		status.ProjectDir = a.projectCtx.Directory
+		status.Detection = string(a.projectCtx.Source)
+	}
+
+	if !lastReload.IsZero() {
+		status.LastReload = &lastReload
+	}
+
+	if configErr != nil {
+		status.ConfigError = configErr.Error()
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		srv, running := a.servers[name]
+
+		cfg := configured[name]
+		if running && srv.Config() != nil {
+			cfg = srv.Config()
+		}
+
+		stats := a.health.Stats(name)
+		rec := a.runtime.get(name)
+		s := ServerStatus{
+			Name:         name,
+			State:        ServerStateDown,
+			Health:       stats.Status,
+			Tools:        len(a.tools.GetByServer(name)),
+			Resources:    len(a.resources.GetByServer(name)),
+			Prompts:      len(a.prompts.GetByServer(name)),
+			Endpoint:     serverEndpoint(cfg),
+			InitializeMS: rec.timing.Initialize.Milliseconds(),
+			ListToolsMS:  rec.timing.ListTools.Milliseconds(),
+			LastError:    rec.lastError,
+			LastErrorAt:  rec.lastErrorAt,
+			AuthURL:      stats.AuthURL,
+		}
+
+		if cfg != nil {
+			s.Transport = string(detectTransport(cfg))
+		}
+
+		if skewed, ok := srv.(ClockSkewServer); ok && running {
+			if offset, beyond, measured := skewed.ClockSkew(); measured && beyond {
+				s.ClockSkewMS = offset.Milliseconds()
+			}
+		}
+
+		if running && !rec.startedAt.IsZero() {
+			s.StartedAt = rec.startedAt
+			s.Uptime = a.clock.Since(rec.startedAt).Round(time.Second).String()
+		}
+
+		maintErr := a.inMaintenance(name, cfg)
+
+		switch {
+		case maintErr != nil:
+			s.State = ServerStateMaintenance
+			s.MaintenanceUntil = maintErr.Until
+			status.ServersDown++
+		case running && a.lazy.get(name) != nil:
+			s.State = ServerStateIdle
+			status.ServersUp++
+		case rec.restarting:
+			s.State = ServerStateRestarting
+			status.ServersDown++
+		case running && !s.Health.down():
+			s.State = ServerStateUp
+			status.ServersUp++
+		default:
+			status.ServersDown++
+		}
+
+		status.Servers = append(status.Servers, s)
+	}
+
+	return status
+}
+
+// ServerErrors lists the recent errors of one server, oldest first.
+type ServerErrors struct {
+	Server string        `json:"server"`
+	Errors []ServerError `json:"errors"`
+}
+
+// RecentErrors returns the last errors recorded for each server (at most
+// maxRecentErrors per server), sorted by server name. A non-empty server
+// limits the result to that server. Servers without errors are left out.
+func (a *Aggregator) RecentErrors(server string) []ServerErrors {
+	recent := a.runtime.recentErrors()
+
+	out := make([]ServerErrors, 0, len(recent))
+	for _, name := range slices.Sorted(maps.Keys(recent)) {
+		if server == "" || name == server {
+			out = append(out, ServerErrors{Server: name, Errors: recent[name]})
+		}
+	}
+
+	return out
+}
+
+// registerStatusTool adds the assern_status built-in tool to the MCP server.
+func (a *Aggregator) registerStatusTool() {
+	a.mcpServer.AddTool(mcp.NewTool(
+		ToolStatusName,
+		mcp.WithDescription(
+			"Report assern status: uptime, last config reload, and for each backend server "+
+				"whether it is up or down, its health and how many tools it provides. "+
+				"Check this before planning work that depends on specific servers.",
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), a.handleStatus)
+}
+
+// handleStatus implements the assern_status built-in tool.
+func (a *Aggregator) handleStatus(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(a.Status(), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("encoding status: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(data)), nil
+}
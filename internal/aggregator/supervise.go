@@ -0,0 +1,173 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// errProcessExited is recorded as the last error of a crashed server.
+var errProcessExited = errors.New("server process exited unexpectedly")
+
+// errConnectionLost is recorded as the last error of a WebSocket server
+// whose connection dropped.
+var errConnectionLost = errors.New("server connection lost")
+
+// restartBackoff spaces out restart attempts of a crashed stdio server: the
+// first attempt is immediate, then 1s, 2s, 4s, ... up to a minute.
+var restartBackoff = &config.RetryConfig{
+	InitialDelay:  time.Second,
+	MaxDelay:      time.Minute,
+	BackoffFactor: 2,
+}
+
+// superviseServer restarts a stdio or container server whenever its process
+// exits on its own, and reconnects a WebSocket server whenever its
+// connection drops, unless its restart_policy is "never".
+func (a *Aggregator) superviseServer(name string, srv *ManagedServer) {
+	supervised := srv.transportType == TransportStdio || srv.transportType == TransportContainer ||
+		srv.transportType == TransportWebSocket
+	if !supervised || !srv.Config().RestartsOnCrash() {
+		return
+	}
+
+	a.supervisors.start(name, func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-srv.Crashed():
+				a.restartCrashed(ctx, name, srv)
+			}
+		}
+	})
+}
+
+// restartCrashed restarts a crashed server with exponential backoff until it
+// is running again or the supervisor is stopped. During a maintenance window
+// the server is left down, without failures or restart attempts, until the
+// window ends.
+func (a *Aggregator) restartCrashed(ctx context.Context, name string, srv *ManagedServer) {
+	crashErr := errProcessExited
+	if srv.transportType == TransportWebSocket {
+		crashErr = errConnectionLost
+	}
+
+	if a.inMaintenance(name, srv.Config()) == nil {
+		a.runtime.failed(name, opCrash, crashErr)
+		a.publish(events.ServerFailed, name, crashErr.Error(), nil)
+	}
+
+	for attempt := 1; ; attempt++ {
+		a.runtime.restarting(name)
+
+		// Backoff starts over after a maintenance window
+		if a.waitOutMaintenance(ctx, name, srv.Config()) {
+			attempt = 1
+		}
+
+		if delay := CalculateBackoffDelay(restartBackoff, attempt); delay > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.clock.After(delay):
+			}
+		}
+
+		a.logger.Info("restarting crashed server", "server", name, "attempt", attempt)
+
+		err := a.relaunch(ctx, name, srv)
+		if err == nil {
+			a.logger.Info("crashed server restarted", "server", name, "attempt", attempt)
+			a.publish(events.ServerStarted, name, "restarted after crash", map[string]any{
+				"tools":   len(a.tools.GetByServer(name)),
+				"attempt": attempt,
+			})
+
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		a.runtime.failed(name, opRestart, err)
+		a.logger.Warn("restart of crashed server failed", "server", name, "attempt", attempt,
+			"retry_in", CalculateBackoffDelay(restartBackoff, attempt+1), "error", err)
+	}
+}
+
+// relaunch starts a stopped server again and re-registers its tools, which
+// may have changed across the restart.
+func (a *Aggregator) relaunch(ctx context.Context, name string, srv *ManagedServer) error {
+	startCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	initStart := a.clock.Now()
+	if err := srv.Start(startCtx); err != nil {
+		return fmt.Errorf("starting server: %w", err)
+	}
+
+	timing := ServerTiming{Initialize: a.clock.Since(initStart)}
+
+	listStart := a.clock.Now()
+	tools, err := srv.DiscoverTools(startCtx)
+	timing.ListTools = a.clock.Since(listStart)
+
+	if err != nil {
+		if stopErr := srv.Stop(); stopErr != nil {
+			a.logger.Warn("error stopping server after discovery failure", "server", name, "error", stopErr)
+		}
+
+		return fmt.Errorf("discovering tools: %w", err)
+	}
+
+	a.cacheTools(name, srv.Config(), tools)
+	a.replaceServerTools(name, srv.Config(), tools)
+	a.runtime.started(name, timing)
+	a.health.Reset(name)
+
+	return nil
+}
+
+// replaceServerTools swaps a server's registered tools for a fresh tools/list
+// result and updates the MCP server to match.
+func (a *Aggregator) replaceServerTools(name string, cfg *config.ServerConfig, tools []mcp.Tool) {
+	old := a.tools.GetByServer(name)
+
+	a.tools.RemoveServer(name)
+
+	if err := a.registerTools(name, cfg, tools); err != nil {
+		a.logger.Error("some tools were not registered", "server", name, "error", err)
+	}
+
+	a.updateExposedTools(name, old)
+}
+
+// updateExposedTools updates the MCP server to the tools now registered for
+// a server: tools of old that are no longer registered are deleted, the
+// others are added or replaced.
+func (a *Aggregator) updateExposedTools(name string, old []*ToolEntry) {
+	if a.mcpServer == nil {
+		return
+	}
+
+	var gone []string
+	for _, entry := range old {
+		if _, ok := a.tools.Get(entry.PrefixedName); !ok {
+			gone = append(gone, entry.PrefixedName)
+		}
+	}
+
+	if len(gone) > 0 {
+		a.mcpServer.DeleteTools(gone...)
+	}
+
+	a.addServerToolsToMCPServer(name)
+}
@@ -0,0 +1,215 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/text/transform"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// envStdioHelper makes the test binary act as a stdio MCP server, see
+// TestStdioHelperProcess.
+const envStdioHelper = "ASSERN_STDIO_HELPER"
+
+// envHelperEncoding makes the helper read and write in another encoding
+// than UTF-8, as some Windows-hosted servers do.
+const envHelperEncoding = "ASSERN_HELPER_ENCODING"
+
+// envHelperVersion makes the helper serve a resource file:///v<version>.md
+// and a prompt v<version>, so tests can tell its configurations apart.
+const envHelperVersion = "HELPER_VERSION"
+
+// TestStdioHelperProcess is not a real test: started by the tests below with
+// envStdioHelper set, it serves an "echo" tool returning its "text" argument
+// ("ok" without one), a "crash" tool that makes
+// the process exit, as a crashing backend would, a "grow" tool that adds
+// an "extra" tool, sending notifications/tools/list_changed, and a "log"
+// tool that writes its "line" argument to stderr, and a "slow" tool taking
+// "steps" steps of "interval_ms", after each of which it reports progress
+// when "progress" is set and the call asked for it. Lists are served two
+// items per page, so discovery has to follow cursors. With envHelperEncoding
+// set, everything it reads and writes is in that encoding. See also
+// envHelperVersion.
+func TestStdioHelperProcess(t *testing.T) {
+	if os.Getenv(envStdioHelper) != "1" {
+		t.Skip("helper process for stdio supervision tests")
+	}
+
+	var (
+		stdin          io.Reader = os.Stdin
+		stdout, stderr io.Writer = os.Stdout, os.Stderr
+	)
+
+	if enc, _ := config.LookupEncoding(os.Getenv(envHelperEncoding)); enc != nil {
+		stdin = transform.NewReader(os.Stdin, enc.NewDecoder())
+		stdout = &transcodedWriter{pipe: os.Stdout, enc: enc.NewEncoder()}
+		stderr = transform.NewWriter(os.Stderr, enc.NewEncoder())
+	}
+
+	srv := server.NewMCPServer("helper", "1.0.0", server.WithPaginationLimit(2))
+	srv.AddTool(mcp.NewTool("echo"), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(req.GetString("text", "ok")), nil
+	})
+	srv.AddTool(mcp.NewTool("crash"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		os.Exit(3)
+
+		return nil, nil
+	})
+	srv.AddTool(mcp.NewTool("grow"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		srv.AddTool(mcp.NewTool("extra"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("extra"), nil
+		})
+
+		return mcp.NewToolResultText("grown"), nil
+	})
+
+	srv.AddTool(mcp.NewTool("log"), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fmt.Fprintln(stderr, req.GetString("line", ""))
+
+		return mcp.NewToolResultText("logged"), nil
+	})
+
+	srv.AddTool(mcp.NewTool("slow"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var token mcp.ProgressToken
+		if req.Params.Meta != nil && req.GetBool("progress", false) {
+			token = req.Params.Meta.ProgressToken
+		}
+
+		interval := time.Duration(req.GetInt("interval_ms", 0)) * time.Millisecond
+
+		for step := range req.GetInt("steps", 0) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+
+			if token != nil {
+				_ = srv.SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+					"progressToken": token,
+					"progress":      step + 1,
+				})
+			}
+		}
+
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	if version := os.Getenv(envHelperVersion); version != "" {
+		srv.AddResource(mcp.NewResource("file:///v"+version+".md", "v"+version), func(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, Text: version}}, nil
+		})
+		srv.AddPrompt(mcp.NewPrompt("v"+version), func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("v"+version, nil), nil
+		})
+	}
+
+	_ = server.NewStdioServer(srv).Listen(context.Background(), stdin, stdout)
+
+	os.Exit(0)
+}
+
+func newHelperServer(t *testing.T, policy string) *ManagedServer {
+	t.Helper()
+
+	cfg := &config.ServerConfig{
+		Command:       os.Args[0],
+		Args:          []string{"-test.run=^TestStdioHelperProcess$"},
+		RestartPolicy: policy,
+	}
+
+	srv, err := NewManagedServer("helper", cfg, []string{envStdioHelper + "=1"}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	if err := srv.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	return srv
+}
+
+func TestSuperviseRestartsCrashedServer(t *testing.T) {
+	t.Parallel()
+
+	logs := newMessageHandler()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(logs)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv := newHelperServer(t, "")
+	if err := agg.AddServer(t.Context(), srv); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	agg.superviseServer("helper", srv)
+
+	if _, err := srv.CallTool(t.Context(), "crash", nil); err == nil {
+		t.Fatal("crash tool returned without error")
+	}
+
+	logs.wait(t, "crashed server restarted")
+
+	if rec := agg.runtime.get("helper"); rec.lastError != errProcessExited.Error() {
+		t.Errorf("last error = %q, want %q", rec.lastError, errProcessExited)
+	}
+
+	result, err := srv.CallTool(t.Context(), "echo", nil)
+	if err != nil || result.IsError {
+		t.Fatalf("echo after restart = %+v, %v", result, err)
+	}
+
+	if _, ok := agg.tools.Get("helper_echo"); !ok {
+		t.Error("tools not re-registered after restart")
+	}
+}
+
+func TestSuperviseRestartPolicyNever(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv := newHelperServer(t, config.RestartNever)
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	agg.superviseServer("helper", srv)
+
+	if _, err := srv.CallTool(t.Context(), "crash", nil); err == nil {
+		t.Fatal("crash tool returned without error")
+	}
+
+	select {
+	case <-srv.Crashed():
+	case <-time.After(10 * time.Second):
+		t.Fatal("crash not detected")
+	}
+
+	if srv.IsStarted() {
+		t.Error("crashed server still reported as started")
+	}
+
+	agg.supervisors.mu.Lock()
+	_, supervised := agg.supervisors.loops["helper"]
+	agg.supervisors.mu.Unlock()
+
+	if supervised {
+		t.Error("server with restart_policy never is supervised")
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client/transport"
+
+	"github.com/valksor/go-assern/internal/config"
 )
 
 // tokenKeySanitizer replaces any character that is unsafe in a filename so the
@@ -23,6 +26,15 @@ var tokenKeySanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
 type fileTokenStore struct {
 	path string
 	mu   sync.Mutex
+
+	// refreshBefore makes GetToken report a token that can be refreshed as
+	// expired this long before it does, so mcp-go refreshes it before
+	// requests start failing
+	refreshBefore time.Duration
+
+	// skew moves the refresh earlier still by the tolerated clock
+	// difference with the authorization server, or its measured lead
+	skew *clockSkew
 }
 
 // newFileTokenStore returns a token store backed by dir/<sanitized key>.json.
@@ -32,8 +44,34 @@ func newFileTokenStore(dir, key string) *fileTokenStore {
 	return &fileTokenStore{path: filepath.Join(dir, safe+".json")}
 }
 
+// oauthTokenStore returns the token cache of a server, refreshing tokens as
+// its oauth.refresh_before says, allowing for clock skew. skew may be nil.
+func oauthTokenStore(name string, cfg *config.ServerConfig, skew *clockSkew) (*fileTokenStore, error) {
+	dir, err := config.TokensDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store := newFileTokenStore(dir, tokenCacheKey(name, cfg))
+	store.refreshBefore = cfg.OAuth.EffectiveRefreshBefore()
+	store.skew = skew
+
+	return store, nil
+}
+
+// tokenCacheKey identifies the cached-token bucket: the shared OAuth profile
+// reference when set, otherwise the server's own name.
+func tokenCacheKey(name string, cfg *config.ServerConfig) string {
+	if cfg.OAuthRef != "" {
+		return cfg.OAuthRef
+	}
+
+	return name
+}
+
 // GetToken loads the cached token, returning transport.ErrNoToken when none has
-// been stored yet.
+// been stored yet. A token due for refresh (see refreshBefore and skew) is
+// returned as expired.
 func (s *fileTokenStore) GetToken(ctx context.Context) (*transport.Token, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -56,6 +94,12 @@ func (s *fileTokenStore) GetToken(ctx context.Context) (*transport.Token, error)
 		return nil, fmt.Errorf("decoding token: %w", err)
 	}
 
+	// Only the returned copy expires early; the file keeps the real expiry
+	early := s.refreshBefore + s.skew.lead()
+	if early > 0 && token.RefreshToken != "" && !token.ExpiresAt.IsZero() {
+		token.ExpiresAt = token.ExpiresAt.Add(-early)
+	}
+
 	return &token, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client/transport"
 )
@@ -88,3 +89,41 @@ func TestFileTokenStoreSanitizesKey(t *testing.T) {
 		t.Errorf("sanitized filename = %q, want .._.._etc_passwd.json", got)
 	}
 }
+
+func TestFileTokenStoreRefreshBefore(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Now().Add(30 * time.Second)
+
+	tests := []struct {
+		name        string
+		token       transport.Token
+		wantExpired bool
+	}{
+		{name: "refreshable token due", token: transport.Token{AccessToken: "x", RefreshToken: "r", ExpiresAt: expiresAt}, wantExpired: true},
+		{name: "no refresh token", token: transport.Token{AccessToken: "x", ExpiresAt: expiresAt}},
+		{name: "no expiry", token: transport.Token{AccessToken: "x", RefreshToken: "r"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newFileTokenStore(t.TempDir(), "github")
+			store.refreshBefore = time.Minute
+
+			if err := store.SaveToken(t.Context(), &tt.token); err != nil {
+				t.Fatalf("SaveToken: %v", err)
+			}
+
+			got, err := store.GetToken(t.Context())
+			if err != nil {
+				t.Fatalf("GetToken: %v", err)
+			}
+
+			if got.IsExpired() != tt.wantExpired {
+				t.Errorf("IsExpired() = %v, want %v", got.IsExpired(), tt.wantExpired)
+			}
+		})
+	}
+}
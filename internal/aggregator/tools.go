@@ -3,10 +3,12 @@ package aggregator
 import (
 	"fmt"
 	"maps"
-	"slices"
 	"strings"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
 )
 
 // ToolEntry represents a tool from a backend server.
@@ -15,7 +17,8 @@ type ToolEntry struct {
 	ServerName string
 	// Tool is the original tool definition.
 	Tool mcp.Tool
-	// PrefixedName is the tool name with server prefix (e.g., "github_search").
+	// PrefixedName is the exposed tool name, by default with the server
+	// prefix (e.g., "github_search"; see config.ToolNaming).
 	PrefixedName string
 
 	// Precomputed lowercased fields for the search ranker, populated once in
@@ -31,38 +34,112 @@ type ToolEntry struct {
 type ToolRegistry struct {
 	// Use the generic registry with entry pointer and string key
 	r *registry[*ToolEntry, string]
-	// aliases maps alias names to prefixed tool names
+	// aliases maps alias names to prefixed tool names; replaced on reload,
+	// so guarded by aliasMu
 	aliases map[string]string
+	aliasMu sync.RWMutex
+	// naming builds exposed names and settles collisions between servers
+	naming config.ToolNaming
+	// mu makes the collision check and registration in RegisterAs atomic
+	mu sync.Mutex
 }
 
-// NewToolRegistry creates a new tool registry.
+// NewToolRegistry creates a new tool registry with the default naming
+// (server_tool, collisions suffixed).
 func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
 		r:       newRegistry[*ToolEntry, string](),
 		aliases: make(map[string]string),
+		naming:  config.DefaultToolNaming(),
+	}
+}
+
+// SetNaming sets how tools registered afterwards are named.
+func (r *ToolRegistry) SetNaming(naming config.ToolNaming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.naming = naming
+}
+
+// Naming returns the registry's tool naming.
+func (r *ToolRegistry) Naming() config.ToolNaming {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.naming
+}
+
+// Register adds a tool from a server to the registry under the name the
+// registry's naming gives it, unless rules hide it. Nil rules register every
+// tool. See RegisterAs for collisions.
+func (r *ToolRegistry) Register(serverName string, tool mcp.Tool, rules *config.ToolRules) (entry, moved *ToolEntry, err error) {
+	if !rules.Allows(serverName, tool.Name) {
+		return nil, nil, nil
 	}
+
+	return r.RegisterAs(serverName, tool, r.Naming().ToolName(serverName, "", tool.Name))
 }
 
-// Register adds tools from a server to the registry.
-// If allowed is non-empty, only tools in the allowed list are registered.
-func (r *ToolRegistry) Register(serverName string, tool mcp.Tool, allowed []string) {
-	// Check if tool is in allowed list (if specified)
-	if len(allowed) > 0 && !isAllowed(tool.Name, allowed) {
-		return
+// RegisterAs adds a tool from a server under the given exposed name.
+//
+// When another server already holds the name, collisions are settled by
+// server name rather than start order, so the outcome does not depend on
+// which backend answered first: with the suffix strategy the server that
+// sorts first keeps the name and the other's tool gets "_<server>"
+// appended, which may move the tool already registered. The new entry is
+// returned along with the moved one, if any, so the caller can expose it
+// under its new name. With the error strategy the tool is not registered
+// and ErrToolNameCollision returned.
+func (r *ToolRegistry) RegisterAs(serverName string, tool mcp.Tool, name string) (entry, moved *ToolEntry, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if holder, taken := r.r.get(name); taken && holder.ServerName != serverName {
+		if r.naming.Collision == config.PrefixCollisionError {
+			return nil, nil, fmt.Errorf("%w: %q of server %s is already exposed by server %s",
+				ErrToolNameCollision, name, serverName, holder.ServerName)
+		}
+
+		loser := serverName
+		if serverName < holder.ServerName {
+			loser = holder.ServerName
+		}
+
+		suffixed := name + "_" + sanitizeName(loser)
+		if other, taken := r.r.get(suffixed); taken && other.ServerName != loser {
+			return nil, nil, fmt.Errorf("%w: %q of server %s and its suffixed name are taken",
+				ErrToolNameCollision, name, loser)
+		}
+
+		if loser == serverName {
+			name = suffixed
+		} else {
+			moved = newToolEntry(holder.ServerName, holder.Tool, suffixed)
+			r.r.rekey(holder.ServerName, holder.PrefixedName, suffixed, moved, func(e *ToolEntry) string {
+				return e.PrefixedName
+			})
+		}
 	}
 
-	prefixedName := PrefixToolName(serverName, tool.Name)
+	entry = newToolEntry(serverName, tool, name)
+
+	r.r.register(serverName, entry, func(_ string, e *ToolEntry) string {
+		return e.PrefixedName
+	})
+
+	return entry, moved, nil
+}
 
+func newToolEntry(serverName string, tool mcp.Tool, name string) *ToolEntry {
 	entry := &ToolEntry{
 		ServerName:   serverName,
 		Tool:         tool,
-		PrefixedName: prefixedName,
+		PrefixedName: name,
 	}
 	entry.indexForSearch()
 
-	r.r.register(serverName, entry, func(_ string, e *ToolEntry) string {
-		return e.PrefixedName
-	})
+	return entry
 }
 
 // indexForSearch precomputes the lowercased fields the search ranker reads.
@@ -77,34 +154,41 @@ func (e *ToolEntry) indexForSearch() {
 // Get retrieves a tool entry by its prefixed name or alias.
 // Aliases are resolved first, then the actual tool is looked up.
 func (r *ToolRegistry) Get(name string) (*ToolEntry, bool) {
-	// Check if name is an alias
-	if target, isAlias := r.aliases[name]; isAlias {
-		return r.r.get(target)
-	}
-
-	return r.r.get(name)
+	return r.r.get(r.ResolveAlias(name))
 }
 
 // SetAliases sets the tool aliases.
 // Each alias maps to a prefixed tool name.
 func (r *ToolRegistry) SetAliases(aliases map[string]string) {
+	r.aliasMu.Lock()
+	defer r.aliasMu.Unlock()
+
 	r.aliases = make(map[string]string, len(aliases))
 	maps.Copy(r.aliases, aliases)
 }
 
 // AddAlias adds a single alias mapping.
 func (r *ToolRegistry) AddAlias(alias, prefixedName string) {
+	r.aliasMu.Lock()
+	defer r.aliasMu.Unlock()
+
 	r.aliases[alias] = prefixedName
 }
 
 // RemoveAlias removes an alias.
 func (r *ToolRegistry) RemoveAlias(alias string) {
+	r.aliasMu.Lock()
+	defer r.aliasMu.Unlock()
+
 	delete(r.aliases, alias)
 }
 
 // ResolveAlias resolves an alias to its prefixed tool name.
 // Returns the original name if not an alias.
 func (r *ToolRegistry) ResolveAlias(name string) string {
+	r.aliasMu.RLock()
+	defer r.aliasMu.RUnlock()
+
 	if target, isAlias := r.aliases[name]; isAlias {
 		return target
 	}
@@ -114,6 +198,9 @@ func (r *ToolRegistry) ResolveAlias(name string) string {
 
 // Aliases returns a copy of all defined aliases.
 func (r *ToolRegistry) Aliases() map[string]string {
+	r.aliasMu.RLock()
+	defer r.aliasMu.RUnlock()
+
 	result := make(map[string]string, len(r.aliases))
 	maps.Copy(result, r.aliases)
 
@@ -122,6 +209,9 @@ func (r *ToolRegistry) Aliases() map[string]string {
 
 // IsAlias checks if a name is a defined alias.
 func (r *ToolRegistry) IsAlias(name string) bool {
+	r.aliasMu.RLock()
+	defer r.aliasMu.RUnlock()
+
 	_, ok := r.aliases[name]
 
 	return ok
@@ -150,11 +240,15 @@ func (r *ToolRegistry) ServerCount() int {
 // Clear removes all entries and aliases from the registry.
 func (r *ToolRegistry) Clear() {
 	r.r.clear()
-	r.aliases = make(map[string]string)
+	r.SetAliases(nil)
 }
 
-// RemoveServer removes all tools for a specific server.
+// RemoveServer removes all tools for a specific server. Tools of other
+// servers moved aside by its tools keep their suffixed names.
 func (r *ToolRegistry) RemoveServer(serverName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.r.removeServer(serverName, func(e *ToolEntry) string {
 		return e.PrefixedName
 	})
@@ -196,11 +290,6 @@ func sanitizeName(name string) string {
 	return strings.ReplaceAll(name, "-", "_")
 }
 
-// isAllowed checks if a tool name is in the allowed list.
-func isAllowed(toolName string, allowed []string) bool {
-	return slices.Contains(allowed, toolName)
-}
-
 // ToolSummary provides a summary of a tool for display.
 type ToolSummary struct {
 	PrefixedName string
@@ -7,6 +7,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
 )
 
 func TestPrefixToolName(t *testing.T) {
@@ -103,7 +104,7 @@ func TestToolRegistry_RegisterWithAllowed(t *testing.T) {
 
 	registry := aggregator.NewToolRegistry()
 
-	allowed := []string{"search", "create"}
+	rules := &config.ToolRules{Allowed: []string{"search", "create"}}
 
 	tools := []mcp.Tool{
 		{Name: "search"},
@@ -112,7 +113,7 @@ func TestToolRegistry_RegisterWithAllowed(t *testing.T) {
 	}
 
 	for _, tool := range tools {
-		registry.Register("github", tool, allowed)
+		registry.Register("github", tool, rules)
 	}
 
 	if registry.Count() != 2 {
@@ -124,6 +125,29 @@ func TestToolRegistry_RegisterWithAllowed(t *testing.T) {
 	}
 }
 
+func TestToolRegistry_RegisterWithDenied(t *testing.T) {
+	t.Parallel()
+
+	registry := aggregator.NewToolRegistry()
+
+	rules := &config.ToolRules{Allowed: []string{"*issue*", "search"}, Denied: []string{"delete_*"}}
+
+	for _, name := range []string{"search", "create_issue", "delete_issue", "list_repos"} {
+		registry.Register("github", mcp.Tool{Name: name}, rules)
+	}
+
+	for name, want := range map[string]bool{
+		"github_search":       true,
+		"github_create_issue": true,
+		"github_delete_issue": false, // Denied wins over allowed
+		"github_list_repos":   false,
+	} {
+		if _, ok := registry.Get(name); ok != want {
+			t.Errorf("%s registered = %v, want %v", name, ok, want)
+		}
+	}
+}
+
 func TestToolRegistry_GetByServer(t *testing.T) {
 	t.Parallel()
 
@@ -576,3 +600,60 @@ func TestToolRegistry_AliasPointsToNonexistent(t *testing.T) {
 		t.Error("Get(alias to nonexistent) should return false")
 	}
 }
+
+func TestToolRegistry_CollisionSuffix(t *testing.T) {
+	t.Parallel()
+
+	// The outcome does not depend on which server registers first
+	for _, order := range [][]string{{"alpha", "beta"}, {"beta", "alpha"}} {
+		registry := aggregator.NewToolRegistry()
+		registry.SetNaming(config.ToolNaming{Strategy: config.PrefixStrategyNone, Collision: config.PrefixCollisionSuffix})
+
+		var moved []*aggregator.ToolEntry
+
+		for _, server := range order {
+			_, m, err := registry.Register(server, mcp.Tool{Name: "search"}, nil)
+			if err != nil {
+				t.Fatalf("Register(%s) error = %v", server, err)
+			}
+
+			if m != nil {
+				moved = append(moved, m)
+			}
+		}
+
+		for name, server := range map[string]string{"search": "alpha", "search_beta": "beta"} {
+			entry, ok := registry.Get(name)
+			if !ok || entry.ServerName != server {
+				t.Errorf("order %v: %s = %+v, want server %s", order, name, entry, server)
+			}
+		}
+
+		if wantMoved := order[0] == "beta"; (len(moved) == 1) != wantMoved {
+			t.Errorf("order %v: moved = %v, want moved %v", order, moved, wantMoved)
+		}
+
+		if registry.Count() != 2 || len(registry.GetByServer("beta")) != 1 {
+			t.Errorf("order %v: Count() = %d, beta tools = %d", order, registry.Count(), len(registry.GetByServer("beta")))
+		}
+	}
+}
+
+func TestToolRegistry_CollisionError(t *testing.T) {
+	t.Parallel()
+
+	registry := aggregator.NewToolRegistry()
+	registry.SetNaming(config.ToolNaming{Strategy: config.PrefixStrategyNone, Collision: config.PrefixCollisionError})
+
+	if _, _, err := registry.Register("alpha", mcp.Tool{Name: "search"}, nil); err != nil {
+		t.Fatalf("Register(alpha) error = %v", err)
+	}
+
+	if _, _, err := registry.Register("beta", mcp.Tool{Name: "search"}, nil); !errors.Is(err, aggregator.ErrToolNameCollision) {
+		t.Fatalf("Register(beta) error = %v, want ErrToolNameCollision", err)
+	}
+
+	if entry, _ := registry.Get("search"); entry.ServerName != "alpha" {
+		t.Errorf("search belongs to %s, want alpha", entry.ServerName)
+	}
+}
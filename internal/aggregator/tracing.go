@@ -0,0 +1,125 @@
+package aggregator
+
+import (
+	"context"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/valksor/go-assern/internal/tracing"
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// traceparentMeta is the _meta field carrying W3C trace context in MCP
+// requests, both from clients and to backends.
+const traceparentMeta = "traceparent"
+
+// traceContext reads and writes the W3C traceparent.
+var traceContext propagation.TraceContext
+
+// newTracer returns the tracer of assern's spans from provider, or one
+// recording nothing when provider is nil.
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	return provider.Tracer(tracing.ScopeName, trace.WithInstrumentationVersion(version.Version))
+}
+
+// TracerProvider returns the tracer provider of tool call spans (nil if
+// tracing is off).
+func (a *Aggregator) TracerProvider() trace.TracerProvider {
+	return a.tracerProvider
+}
+
+// startToolSpan starts the span of a tools/call from a client, continuing
+// the trace the client sent as _meta.traceparent, if any.
+func (a *Aggregator) startToolSpan(ctx context.Context, entry *ToolEntry, req mcp.CallToolRequest) (context.Context, trace.Span) {
+	if a.tracerProvider == nil {
+		return ctx, noop.Span{}
+	}
+
+	if req.Params.Meta != nil {
+		if tp, ok := req.Params.Meta.AdditionalFields[traceparentMeta].(string); ok {
+			ctx = withTraceparent(ctx, tp)
+		}
+	}
+
+	attrs := tracing.ToolCall(entry.PrefixedName, entry.ServerName)
+	attrs = append(attrs, tracing.Client.String(callerName(ctx)))
+
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		attrs = append(attrs, tracing.SessionID.String(session.SessionID()))
+	}
+
+	if name := identity(ctx); name != "" {
+		attrs = append(attrs, tracing.Identity.String(name))
+	}
+
+	if id := CorrelationID(ctx); id != "" {
+		attrs = append(attrs, tracing.CorrelationID.String(id))
+	}
+
+	return a.tracer.Start(ctx, "tools/call "+entry.PrefixedName,
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// endToolSpan records the outcome of a tool call on its span and ends it.
+func endToolSpan(span trace.Span, result *mcp.CallToolResult, err error) {
+	switch {
+	case err != nil:
+		span.SetStatus(codes.Error, err.Error())
+	case result != nil && result.IsError:
+		span.SetStatus(codes.Error, toolResultText(result))
+	}
+
+	span.End()
+}
+
+// withTraceparent returns a context continuing the trace of a W3C
+// traceparent received from a client; spans started from it become its
+// children and keep its sampling decision. An invalid value is ignored.
+func withTraceparent(ctx context.Context, traceparent string) context.Context {
+	return traceContext.Extract(ctx, propagation.MapCarrier{traceparentMeta: traceparent})
+}
+
+// traceparent returns the W3C traceparent of the span in ctx, to pass the
+// trace on to a backend, or "" when there is none.
+func traceparent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+
+	return carrier.Get(traceparentMeta)
+}
+
+// withTraceMeta returns meta plus the traceparent of the span in ctx, for
+// the _meta of a backend request. meta is not modified.
+func withTraceMeta(ctx context.Context, meta map[string]any) map[string]any {
+	tp := traceparent(ctx)
+	if tp == "" {
+		return meta
+	}
+
+	out := make(map[string]any, len(meta)+1)
+	maps.Copy(out, meta)
+	out[traceparentMeta] = tp
+
+	return out
+}
+
+// traceHeaders returns the traceparent HTTP header of the span in ctx for
+// requests to HTTP and SSE backends, or nil.
+func traceHeaders(ctx context.Context) map[string]string {
+	tp := traceparent(ctx)
+	if tp == "" {
+		return nil
+	}
+
+	return map[string]string{traceparentMeta: tp}
+}
@@ -0,0 +1,124 @@
+package aggregator
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+	"github.com/valksor/go-assern/internal/tracing"
+)
+
+func TestToolCallSpans(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler), TracerProvider: provider})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search"), mcp.NewTool("fail")})
+	mock.ToolResults = map[string]*mcp.CallToolResult{"fail": mcp.NewToolResultError("rate limited")}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	for _, name := range []string{"github_search", "github_fail"} {
+		entry, _ := agg.tools.Get(name)
+
+		var req mcp.CallToolRequest
+		req.Params.Name = name
+		req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		}}
+
+		if _, err := agg.createToolHandler(entry)(t.Context(), req); err != nil {
+			t.Fatalf("calling %s: %v", name, err)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2: %+v", len(spans), spans)
+	}
+
+	for _, s := range spans {
+		if !strings.HasPrefix(s.Name(), "tools/call github_") {
+			t.Errorf("span name = %q", s.Name())
+		}
+
+		if s.SpanKind() != trace.SpanKindServer {
+			t.Errorf("span %s kind = %v, want server", s.Name(), s.SpanKind())
+		}
+
+		if s.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || s.Parent().SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("span %s does not continue the client's trace: %v, parent %v", s.Name(), s.SpanContext(), s.Parent())
+		}
+
+		if failed := s.Status().Code == codes.Error; failed != (s.Name() == "tools/call github_fail") {
+			t.Errorf("span %s status = %+v", s.Name(), s.Status())
+		}
+
+		if !slices.Contains(s.Attributes(), tracing.Server.String("github")) {
+			t.Errorf("span %s attributes = %v, want the server", s.Name(), s.Attributes())
+		}
+	}
+}
+
+func TestToolCallSpansDisabled(t *testing.T) {
+	t.Parallel()
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var req mcp.CallToolRequest
+	req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}}
+
+	ctx, span := agg.startToolSpan(t.Context(), &ToolEntry{PrefixedName: "github_search", ServerName: "github"}, req)
+	defer span.End()
+
+	if tp := traceparent(ctx); tp != "" {
+		t.Errorf("traceparent() with tracing off = %q, want none", tp)
+	}
+}
+
+func TestWithTraceMeta(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]any{"progressToken": "p1"}
+
+	if got := withTraceMeta(t.Context(), meta); len(got) != 1 {
+		t.Errorf("withTraceMeta() without a span = %v, want meta unchanged", got)
+	}
+
+	ctx := withTraceparent(t.Context(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	got := withTraceMeta(ctx, meta)
+	if got["traceparent"] != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" || got["progressToken"] != "p1" {
+		t.Errorf("withTraceMeta() = %v", got)
+	}
+
+	if _, ok := meta["traceparent"]; ok {
+		t.Error("withTraceMeta() modified the caller's meta")
+	}
+
+	if headers := traceHeaders(ctx); headers["traceparent"] == "" {
+		t.Errorf("traceHeaders() = %v, want the traceparent", headers)
+	}
+}
@@ -0,0 +1,142 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// configWatchDebounce is how long WatchConfig waits after the last change
+// before reloading, so one save (editors often write, then rename a temporary
+// file over the original) triggers one reload.
+const configWatchDebounce = 500 * time.Millisecond
+
+// WatchConfigEnabled reports whether settings.watch_config is on and the
+// configuration came from files. It reads a.cfg under cfgMu because Reload
+// may swap a.cfg concurrently.
+func (a *Aggregator) WatchConfigEnabled() bool {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+
+	if a.fixedConfig || a.cfg == nil || a.cfg.Settings == nil {
+		return false
+	}
+
+	return a.cfg.Settings.WatchConfig
+}
+
+// WatchConfig reloads the configuration, as SIGHUP and `assern reload` do,
+// whenever one of its files changes, until ctx is done. With Options.LoadEnv
+// set, changes to the .env files call ReloadEnv instead. The directories of
+// the files are watched rather than the files, so files replaced by a rename
+// or created later are noticed; a .assern directory created after startup
+// is not.
+func (a *Aggregator) WatchConfig(ctx context.Context) error {
+	files, err := config.ConfigFiles(a.workDir)
+	if err != nil {
+		return err
+	}
+
+	// Whether a watched file is an env file rather than a config file
+	watched := make(map[string]bool, len(files))
+
+	for _, file := range files {
+		watched[filepath.Clean(file)] = false
+	}
+
+	if a.loadEnv != nil {
+		envs, err := envFiles(a.workDir)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range envs {
+			watched[filepath.Clean(file)] = true
+		}
+
+		files = append(files, envs...)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating config watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	for _, file := range files {
+
+		dir := filepath.Dir(file)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() || slices.Contains(watcher.WatchList(), dir) {
+			continue
+		}
+
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("watching %s: %w", dir, err)
+		}
+	}
+
+	a.logger.Info("watching configuration files", "files", files)
+
+	var (
+		changed  = make(map[string]bool)
+		debounce <-chan time.Time
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			isEnv, ok := watched[filepath.Clean(event.Name)]
+			if !ok || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			changed[event.Name] = isEnv
+			debounce = a.clock.After(configWatchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			a.logger.Warn("config watcher error", "error", err)
+		case <-debounce:
+			debounce = nil
+
+			a.logger.Info("configuration files changed, reloading", "files", slices.Sorted(maps.Keys(changed)))
+
+			var envChanged, configChanged bool
+			for _, isEnv := range changed {
+				envChanged = envChanged || isEnv
+				configChanged = configChanged || !isEnv
+			}
+
+			clear(changed)
+
+			// The environment first, so servers a config reload starts get it
+			if envChanged {
+				if _, err := a.ReloadEnv(ctx); err != nil {
+					a.logger.Error("automatic environment reload failed", "error", err)
+				}
+			}
+
+			if configChanged {
+				if _, err := a.Reload(ctx); err != nil {
+					a.logger.Error("automatic configuration reload failed", "error", err)
+				}
+			}
+		}
+	}
+}
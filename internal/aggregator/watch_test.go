@@ -0,0 +1,185 @@
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+)
+
+func TestWatchConfigReloadsOnChange(t *testing.T) {
+	home := t.TempDir()
+	globalDir := filepath.Join(home, ".valksor", "assern")
+
+	if err := os.MkdirAll(globalDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(globalDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("settings:\n  watch_config: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", home)
+
+	cfg, err := config.LoadEffective(home, "", config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadEffective: %v", err)
+	}
+
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	logs := newMessageHandler()
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(logs), WorkDir: home, Clock: fake})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if !agg.WatchConfigEnabled() {
+		t.Fatal("WatchConfigEnabled() = false with settings.watch_config")
+	}
+
+	done := make(chan error, 1)
+
+	go func() { done <- agg.WatchConfig(t.Context()) }()
+
+	logs.wait(t, "watching configuration files")
+	replaceFile(t, configPath, "settings:\n  watch_config: true\n  aliases:\n    search: github_search_code\n")
+
+	// The reload waits out the debounce after the change
+	fake.BlockUntil(1)
+	fake.Advance(configWatchDebounce)
+	logs.wait(t, "reloading configuration")
+
+	// Reloads hold reloadMu, so the reload has ended once it is free
+	agg.reloadMu.Lock()
+	got := agg.tools.ResolveAlias("search")
+	agg.reloadMu.Unlock()
+
+	if got != "github_search_code" {
+		t.Errorf("alias search = %q, want github_search_code from the edited config.yaml", got)
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("WatchConfig returned early: %v", err)
+	default:
+	}
+}
+
+func TestWatchConfigReloadsEnvOnChange(t *testing.T) {
+	home := t.TempDir()
+	globalDir := filepath.Join(home, ".valksor", "assern")
+
+	if err := os.MkdirAll(globalDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", home)
+
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	logs := newMessageHandler()
+	loaded := make(chan struct{}, 1)
+
+	agg, err := New(Options{
+		Config:  config.NewConfig(),
+		Logger:  slog.New(logs),
+		WorkDir: home,
+		Clock:   fake,
+		LoadEnv: func(context.Context) *env.Loader {
+			loaded <- struct{}{}
+
+			return env.NewLoader()
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	go func() { _ = agg.WatchConfig(t.Context()) }()
+
+	logs.wait(t, "watching configuration files")
+	replaceFile(t, filepath.Join(globalDir, ".env"), "GITHUB_TOKEN=rotated\n")
+
+	fake.BlockUntil(1)
+	fake.Advance(configWatchDebounce)
+
+	select {
+	case <-loaded:
+	case <-t.Context().Done():
+		t.Fatal("environment was not reloaded after .env changed")
+	}
+}
+
+func TestWatchConfigEnabledFixedConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewConfig()
+	cfg.Settings.WatchConfig = true
+
+	agg, err := New(Options{Config: cfg, Logger: slog.New(slog.DiscardHandler), FixedConfig: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if agg.WatchConfigEnabled() {
+		t.Error("WatchConfigEnabled() = true for a configuration read from stdin")
+	}
+}
+
+// replaceFile writes data to path the way editors save: to a temporary file
+// renamed over path, which the watcher sees as a single event.
+func replaceFile(t *testing.T, path, data string) {
+	t.Helper()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// messageHandler is a slog.Handler passing the message of each record on,
+// so tests can wait for what the code under test has logged.
+type messageHandler struct {
+	messages chan string
+}
+
+func newMessageHandler() *messageHandler {
+	return &messageHandler{messages: make(chan string, 100)}
+}
+
+func (h *messageHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *messageHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *messageHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *messageHandler) Handle(_ context.Context, r slog.Record) error {
+	h.messages <- r.Message
+
+	return nil
+}
+
+// wait returns once message has been logged.
+func (h *messageHandler) wait(t *testing.T, message string) {
+	t.Helper()
+
+	for {
+		select {
+		case got := <-h.messages:
+			if got == message {
+				return
+			}
+		case <-t.Context().Done():
+			t.Fatalf("%q not logged", message)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// wsSubprotocol is the WebSocket subprotocol MCP servers speak.
+const wsSubprotocol = "mcp"
+
+// wsMaxMessageSize is the largest message read from a WebSocket backend.
+const wsMaxMessageSize = 64 << 20
+
+// errWebSocketClosed is returned by requests on a connection Close closed.
+var errWebSocketClosed = errors.New("websocket connection closed")
+
+// createWebSocketClient creates a WebSocket transport client with optional
+// headers, sent with the handshake.
+func (s *ManagedServer) createWebSocketClient() (*client.Client, error) {
+	header := make(http.Header, len(s.conn.Headers))
+	for key, value := range s.conn.Headers {
+		header.Set(key, value)
+	}
+
+	return client.NewClient(newWSTransport(s.conn.URL, header, s.logger)), nil
+}
+
+// wsTransport carries MCP's JSON-RPC messages over a WebSocket connection,
+// one message per frame, in both directions: responses are matched to
+// requests by ID, and the backend's own requests (e.g. sampling) are
+// answered on the same connection.
+type wsTransport struct {
+	url    string
+	header http.Header
+	logger *slog.Logger
+
+	conn *websocket.Conn
+
+	// ctx is cancelled when the connection ends, stopping the handlers of
+	// the backend's requests
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu             sync.Mutex
+	pending        map[string]chan *transport.JSONRPCResponse
+	onNotification func(mcp.JSONRPCNotification)
+	onRequest      transport.RequestHandler
+	onLost         func(error)
+	err            error // why the connection ended; nil while it is open
+	closed         bool  // Close was called
+}
+
+// newWSTransport returns a transport for url; Start connects it.
+func newWSTransport(url string, header http.Header, logger *slog.Logger) *wsTransport {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &wsTransport{
+		url:     url,
+		header:  header,
+		logger:  logger,
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(map[string]chan *transport.JSONRPCResponse),
+	}
+}
+
+// Start connects to the backend, asking for the MCP subprotocol, and starts
+// reading its messages. ctx bounds the handshake only.
+func (t *wsTransport) Start(ctx context.Context) error {
+	conn, resp, err := websocket.Dial(ctx, t.url, &websocket.DialOptions{
+		HTTPHeader:   t.header,
+		Subprotocols: []string{wsSubprotocol},
+	})
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", t.url, err)
+	}
+
+	conn.SetReadLimit(wsMaxMessageSize)
+	t.conn = conn
+
+	go t.readLoop()
+
+	return nil
+}
+
+// SendRequest sends request and waits for its response.
+func (t *wsTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	id := request.ID.String()
+	ch := make(chan *transport.JSONRPCResponse, 1)
+
+	t.mu.Lock()
+	if t.err != nil {
+		t.mu.Unlock()
+
+		return nil, t.err
+	}
+
+	t.pending[id] = ch
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(data); err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			return nil, t.err
+		}
+
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// SendNotification sends notification.
+func (t *wsTransport) SendNotification(_ context.Context, notification mcp.JSONRPCNotification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshaling notification: %w", err)
+	}
+
+	return t.write(data)
+}
+
+// write sends data as one text message. It is bound to the connection's
+// context rather than the caller's: the library closes the connection when
+// a write's context ends.
+func (t *wsTransport) write(data []byte) error {
+	return t.conn.Write(t.ctx, websocket.MessageText, data)
+}
+
+// SetNotificationHandler sets the handler of the backend's notifications.
+func (t *wsTransport) SetNotificationHandler(handler func(mcp.JSONRPCNotification)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onNotification = handler
+}
+
+// SetRequestHandler sets the handler of the backend's requests.
+func (t *wsTransport) SetRequestHandler(handler transport.RequestHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onRequest = handler
+}
+
+// SetConnectionLostHandler sets the function called when the connection
+// ends without Close being called.
+func (t *wsTransport) SetConnectionLostHandler(handler func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onLost = handler
+}
+
+// Close closes the connection.
+func (t *wsTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	if t.conn == nil {
+		t.cancel()
+
+		return nil
+	}
+
+	return t.conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// GetSessionId returns "": WebSocket connections have no MCP session ID.
+func (t *wsTransport) GetSessionId() string {
+	return ""
+}
+
+// wsMessage holds the fields telling JSON-RPC messages apart.
+type wsMessage struct {
+	ID     *mcp.RequestId `json:"id"`
+	Method string         `json:"method"`
+}
+
+// readLoop dispatches the backend's messages until the connection ends.
+func (t *wsTransport) readLoop() {
+	for {
+		_, data, err := t.conn.Read(t.ctx)
+		if err != nil {
+			t.end(err)
+
+			return
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.logger.Debug("ignoring malformed websocket message", "error", err)
+
+			continue
+		}
+
+		switch {
+		case msg.Method != "" && msg.ID != nil:
+			t.handleRequest(data)
+		case msg.Method != "":
+			t.handleNotification(data)
+		case msg.ID != nil:
+			t.handleResponse(msg.ID.String(), data)
+		}
+	}
+}
+
+// handleResponse delivers a response to the request waiting for it.
+func (t *wsTransport) handleResponse(id string, data []byte) {
+	var resp transport.JSONRPCResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.logger.Debug("ignoring malformed websocket response", "error", err)
+
+		return
+	}
+
+	t.mu.Lock()
+	ch, ok := t.pending[id]
+	delete(t.pending, id)
+	t.mu.Unlock()
+
+	if ok {
+		ch <- &resp
+	}
+}
+
+// handleNotification passes a notification to the notification handler.
+func (t *wsTransport) handleNotification(data []byte) {
+	var notification mcp.JSONRPCNotification
+	if err := json.Unmarshal(data, &notification); err != nil {
+		t.logger.Debug("ignoring malformed websocket notification", "error", err)
+
+		return
+	}
+
+	t.mu.Lock()
+	handler := t.onNotification
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler(notification)
+	}
+}
+
+// handleRequest answers a request of the backend with the request handler,
+// in its own goroutine so that the read loop keeps delivering responses.
+func (t *wsTransport) handleRequest(data []byte) {
+	var request transport.JSONRPCRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.logger.Debug("ignoring malformed websocket request", "error", err)
+
+		return
+	}
+
+	t.mu.Lock()
+	handler := t.onRequest
+	t.mu.Unlock()
+
+	go func() {
+		var resp *transport.JSONRPCResponse
+
+		if handler == nil {
+			resp = transport.NewJSONRPCErrorResponse(request.ID, mcp.METHOD_NOT_FOUND, "No request handler configured", nil)
+		} else {
+			var err error
+
+			resp, err = handler(t.ctx, request)
+			if err != nil {
+				resp = transport.NewJSONRPCErrorResponse(request.ID, mcp.INTERNAL_ERROR, err.Error(), nil)
+			}
+		}
+
+		if resp == nil {
+			return
+		}
+
+		out, err := json.Marshal(resp)
+		if err != nil {
+			t.logger.Debug("error marshaling websocket response", "error", err)
+
+			return
+		}
+
+		if err := t.write(out); err != nil {
+			t.logger.Debug("error sending websocket response", "error", err)
+		}
+	}()
+}
+
+// end fails the requests in flight after the connection ended with err,
+// and reports the loss unless Close ended it.
+func (t *wsTransport) end(err error) {
+	t.cancel()
+
+	t.mu.Lock()
+	closed := t.closed
+	if closed {
+		t.err = errWebSocketClosed
+	} else {
+		t.err = fmt.Errorf("%w: %w", errConnectionLost, err)
+	}
+
+	for id, ch := range t.pending {
+		close(ch)
+		delete(t.pending, id)
+	}
+
+	lost, lostErr := t.onLost, t.err
+	t.mu.Unlock()
+
+	if !closed && lost != nil {
+		lost(lostErr)
+	}
+}
@@ -0,0 +1,211 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// wsBackend serves an MCP server over WebSocket.
+type wsBackend struct {
+	url string
+
+	mu      sync.Mutex
+	conns   []*websocket.Conn
+	headers []http.Header
+}
+
+// newWSBackend serves mcpServer over WebSocket until the test ends.
+func newWSBackend(t *testing.T, mcpServer *server.MCPServer) *wsBackend {
+	t.Helper()
+
+	b := &wsBackend{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{wsSubprotocol}})
+		if err != nil {
+			return
+		}
+
+		b.mu.Lock()
+		b.conns = append(b.conns, conn)
+		b.headers = append(b.headers, r.Header.Clone())
+		b.mu.Unlock()
+
+		defer conn.CloseNow()
+
+		for {
+			_, msg, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+
+			resp := mcpServer.HandleMessage(r.Context(), msg)
+			if resp == nil {
+				continue
+			}
+
+			data, _ := json.Marshal(resp)
+			if err := conn.Write(r.Context(), websocket.MessageText, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(func() {
+		b.drop()
+		srv.Close()
+	})
+
+	b.url = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	return b
+}
+
+// drop closes the backend's connections, as a server going away would.
+func (b *wsBackend) drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, conn := range b.conns {
+		_ = conn.CloseNow()
+	}
+
+	b.conns = nil
+}
+
+// connections returns the number of handshakes the backend accepted.
+func (b *wsBackend) connections() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.headers)
+}
+
+func TestDetectTransportWebSocket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *config.ServerConfig
+		want TransportType
+	}{
+		{name: "ws URL", cfg: &config.ServerConfig{URL: "ws://localhost:8080/mcp"}, want: TransportWebSocket},
+		{name: "wss URL", cfg: &config.ServerConfig{URL: "wss://example.com/mcp"}, want: TransportWebSocket},
+		{name: "https URL", cfg: &config.ServerConfig{URL: "https://example.com/mcp"}, want: TransportHTTP},
+		{name: "explicit", cfg: &config.ServerConfig{URL: "https://example.com/ws", Transport: "websocket"}, want: TransportWebSocket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := detectTransport(tt.cfg); got != tt.want {
+				t.Errorf("detectTransport() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketServer(t *testing.T) {
+	t.Parallel()
+
+	backend := newWSBackend(t, newEchoServer())
+
+	srv, err := NewManagedServer("live", &config.ServerConfig{
+		URL:     backend.url,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	if err := srv.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	if got := backend.headers[0].Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization header = %q, want the configured one", got)
+	}
+
+	if got := backend.headers[0].Get("Sec-WebSocket-Protocol"); got != wsSubprotocol {
+		t.Errorf("Sec-WebSocket-Protocol = %q, want %q", got, wsSubprotocol)
+	}
+
+	tools, err := srv.DiscoverTools(t.Context())
+	if err != nil || len(tools) != 1 || tools[0].Name != "echo" {
+		t.Fatalf("DiscoverTools() = %+v, %v; want echo", tools, err)
+	}
+
+	result, err := srv.CallTool(t.Context(), "echo", map[string]any{"text": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "echo: hi" {
+		t.Errorf("CallTool() = %+v, want the backend's result", result.Content)
+	}
+}
+
+func TestSuperviseReconnectsWebSocketServer(t *testing.T) {
+	t.Parallel()
+
+	backend := newWSBackend(t, newEchoServer())
+
+	agg, err := New(Options{Config: config.NewConfig(), Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv, err := NewManagedServer("live", &config.ServerConfig{URL: backend.url}, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewManagedServer: %v", err)
+	}
+
+	if err := srv.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), srv); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	agg.superviseServer("live", srv)
+	backend.drop()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		rec := agg.runtime.get("live")
+		if srv.IsStarted() && backend.connections() == 2 {
+			if rec.lastError != errConnectionLost.Error() {
+				t.Errorf("last error = %q, want %q", rec.lastError, errConnectionLost)
+			}
+
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("server not reconnected: started=%v, connections=%d", srv.IsStarted(), backend.connections())
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	result, err := srv.CallTool(t.Context(), "echo", map[string]any{"text": "again"})
+	if err != nil || result.IsError {
+		t.Fatalf("echo after reconnect = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,203 @@
+// Package audit records tool calls as JSON lines for later inspection.
+//
+// Each tools/call handled by the aggregator becomes one Record appended to
+// the file named by settings.audit_log.path:
+//
+//	log, err := audit.Open(cfg.Settings.AuditLog)
+//	defer log.Close()
+//	log.Write(audit.Record{Tool: "github_search", Server: "github"}, args)
+//
+// Arguments are stored as a hash, in full (with configured keys redacted),
+// or not at all. A nil *Log is valid and discards every record.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Call outcomes.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// redacted replaces the values of redacted arguments.
+const redacted = "<redacted>"
+
+// Record is one tool call.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Instance is the instance ID (settings.ids.instance), so records of
+	// several instances can share one store.
+	Instance string `json:"instance_id,omitempty"`
+	// Session is the MCP session ID; Client is the name and version the
+	// client sent in initialize.
+	Session string `json:"session,omitempty"`
+	Client  string `json:"client,omitempty"`
+	// Identity names the settings.acl token an HTTP client authenticated
+	// with.
+	Identity string `json:"identity,omitempty"`
+	// Correlation is the correlation ID of the call, shared by the logs of
+	// the proxy, the primary instance and the backend.
+	Correlation string `json:"correlation_id,omitempty"`
+	// Tool is the prefixed tool name; Server is the backend it belongs to,
+	// empty for assern's own tools.
+	Tool   string `json:"tool"`
+	Server string `json:"server,omitempty"`
+	// ArgumentsHash or Arguments is set depending on the arguments mode.
+	ArgumentsHash string         `json:"arguments_hash,omitempty"`
+	Arguments     map[string]any `json:"arguments,omitempty"`
+	DurationMS    float64        `json:"duration_ms"`
+	Status        string         `json:"status"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// Duration returns the call duration.
+func (r Record) Duration() time.Duration {
+	return time.Duration(r.DurationMS * float64(time.Millisecond))
+}
+
+// Log appends records to an audit log file.
+type Log struct {
+	mu        sync.Mutex
+	file      *os.File
+	arguments string
+	redact    []string
+}
+
+// Open opens the audit log configured by cfg for appending, creating it if
+// needed. It returns nil when cfg does not enable the log.
+func Open(cfg *config.AuditLogConfig) (*Log, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil //nolint:nilnil // A nil *Log is the disabled log
+	}
+
+	path := cfg.EffectivePath()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating audit log dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+
+	return &Log{
+		file:      file,
+		arguments: cfg.EffectiveArguments(),
+		redact:    cfg.Redact,
+	}, nil
+}
+
+// Write appends rec, filling in its arguments from args according to the
+// arguments mode.
+func (l *Log) Write(rec Record, args map[string]any) error {
+	if l == nil {
+		return nil
+	}
+
+	switch l.arguments {
+	case config.AuditArgumentsFull:
+		rec.Arguments = redactArguments(args, l.redact)
+	case config.AuditArgumentsHash:
+		rec.ArgumentsHash = HashArguments(args)
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding audit record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// HashArguments returns "sha256:<hex>" of the arguments' JSON encoding, or
+// "" when there are none. Map keys are encoded in sorted order, so equal
+// arguments always hash alike.
+func HashArguments(args map[string]any) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// redactArguments returns a copy of args with the values of keys named in
+// redact (case-insensitively, at any depth) replaced.
+func redactArguments(args map[string]any, redact []string) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+
+	out, _ := redactValue(args, redact).(map[string]any)
+
+	return out
+}
+
+func redactValue(v any, redact []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+
+		for k, val := range v {
+			if redactedKey(k, redact) {
+				out[k] = redacted
+			} else {
+				out[k] = redactValue(val, redact)
+			}
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = redactValue(val, redact)
+		}
+
+		return out
+	default:
+		return v
+	}
+}
+
+func redactedKey(key string, redact []string) bool {
+	return slices.ContainsFunc(redact, func(r string) bool { return strings.EqualFold(key, r) })
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestLogWrite(t *testing.T) {
+	t.Parallel()
+
+	args := map[string]any{
+		"query": "status",
+		"auth":  map[string]any{"Token": "secret", "user": "ann"},
+		"items": []any{map[string]any{"token": "secret"}},
+	}
+
+	tests := []struct {
+		name      string
+		arguments string
+		check     func(t *testing.T, rec Record)
+	}{
+		{
+			name: "hash by default",
+			check: func(t *testing.T, rec Record) {
+				t.Helper()
+
+				if rec.ArgumentsHash != HashArguments(args) || rec.Arguments != nil {
+					t.Errorf("record = %+v, want only the arguments hash", rec)
+				}
+			},
+		},
+		{
+			name:      "full with redaction",
+			arguments: config.AuditArgumentsFull,
+			check: func(t *testing.T, rec Record) {
+				t.Helper()
+
+				auth, _ := rec.Arguments["auth"].(map[string]any)
+				if auth["Token"] != redacted || auth["user"] != "ann" {
+					t.Errorf("auth = %v, want Token redacted and user kept", auth)
+				}
+
+				items, _ := rec.Arguments["items"].([]any)
+				if item, _ := items[0].(map[string]any); item["token"] != redacted {
+					t.Errorf("items = %v, want nested token redacted", items)
+				}
+
+				if rec.Arguments["query"] != "status" || rec.ArgumentsHash != "" {
+					t.Errorf("record = %+v, want full arguments only", rec)
+				}
+			},
+		},
+		{
+			name:      "none",
+			arguments: config.AuditArgumentsNone,
+			check: func(t *testing.T, rec Record) {
+				t.Helper()
+
+				if rec.Arguments != nil || rec.ArgumentsHash != "" {
+					t.Errorf("record = %+v, want no arguments", rec)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+
+			log, err := Open(&config.AuditLogConfig{Path: path, Arguments: tt.arguments, Redact: []string{"token"}})
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+
+			if err := log.Write(Record{Tool: "github_search", Server: "github", Status: StatusOK}, args); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			if err := log.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			records, err := Tail(path, 10)
+			if err != nil || len(records) != 1 {
+				t.Fatalf("Tail = %v, %v; want one record", records, err)
+			}
+
+			tt.check(t, records[0])
+
+			if args["auth"].(map[string]any)["Token"] != "secret" {
+				t.Error("redaction modified the caller's arguments")
+			}
+		})
+	}
+}
+
+func TestOpenDisabled(t *testing.T) {
+	t.Parallel()
+
+	log, err := Open(&config.AuditLogConfig{Arguments: config.AuditArgumentsFull})
+	if err != nil || log != nil {
+		t.Fatalf("Open without path = %v, %v; want nil, nil", log, err)
+	}
+
+	if err := log.Write(Record{Tool: "x"}, nil); err != nil {
+		t.Errorf("nil Log Write: %v", err)
+	}
+}
+
+func TestOpenFileMode(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := Open(&config.AuditLogConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	defer func() { _ = log.Close() }()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("audit log mode = %o, want 600", perm)
+	}
+}
+
+func TestHashArguments(t *testing.T) {
+	t.Parallel()
+
+	a := HashArguments(map[string]any{"a": 1, "b": "x"})
+	b := HashArguments(map[string]any{"b": "x", "a": 1})
+
+	if a == "" || a != b {
+		t.Errorf("hashes of equal arguments = %q, %q; want equal and non-empty", a, b)
+	}
+
+	if a == HashArguments(map[string]any{"a": 2, "b": "x"}) {
+		t.Error("different arguments hash alike")
+	}
+
+	if got := HashArguments(nil); got != "" {
+		t.Errorf("HashArguments(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// maxRecordSize bounds one line of the log; full arguments can be large.
+const maxRecordSize = 16 << 20
+
+// followInterval is how often Follow checks the file for new records.
+const followInterval = 500 * time.Millisecond
+
+// Decode calls fn for each record read from r. Lines that are not valid
+// records, such as one cut short by a crash, are skipped.
+func Decode(r io.Reader, fn func(Record) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxRecordSize)
+
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Tool == "" {
+			continue
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+
+	return nil
+}
+
+// ReadFile calls fn for each record in the log at path.
+func ReadFile(path string, fn func(Record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return Decode(file, fn)
+}
+
+// Tail returns the last n records of the log at path, oldest first.
+func Tail(path string, n int) ([]Record, error) {
+	var records []Record
+
+	err := ReadFile(path, func(rec Record) error {
+		records = append(records, rec)
+		if len(records) > n {
+			records = records[1:]
+		}
+
+		return nil
+	})
+
+	return records, err
+}
+
+// Follow calls fn for each record appended to the log at path after it is
+// called, until ctx is done. A log that is truncated or replaced, e.g. by
+// rotation, is read again from the start.
+func Follow(ctx context.Context, path string, fn func(Record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("seeking audit log: %w", err)
+	}
+
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	var partial []byte
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if current, _ := file.Stat(); info.Size() < offset || !os.SameFile(info, current) {
+			reopened, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+
+			_ = file.Close()
+			file, offset, partial = reopened, 0, nil
+		}
+
+		data, err := io.ReadAll(io.NewSectionReader(file, offset, info.Size()-offset))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading audit log: %w", err)
+		}
+
+		offset += int64(len(data))
+
+		// Hold back a trailing line that is still being written
+		data = append(partial, data...)
+		end := bytes.LastIndexByte(data, '\n') + 1
+		partial = bytes.Clone(data[end:])
+
+		if err := Decode(bytes.NewReader(data[:end]), fn); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeSkipsMalformedLines(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		`{"tool":"a_x","status":"ok"}`,
+		`not json`,
+		`{"status":"ok"}`,
+		`{"tool":"b_y","status":"error","error":"boom"}`,
+		`{"tool":"c_z","sta`,
+	}, "\n")
+
+	var tools []string
+
+	err := Decode(strings.NewReader(input), func(rec Record) error {
+		tools = append(tools, rec.Tool)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if strings.Join(tools, ",") != "a_x,b_y" {
+		t.Errorf("decoded tools = %v, want [a_x b_y]", tools)
+	}
+}
+
+func TestTail(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	var lines []string
+	for _, tool := range []string{"a", "b", "c", "d"} {
+		lines = append(lines, `{"tool":"`+tool+`","status":"ok"}`)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Tail(path, 2)
+	if err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+
+	if len(records) != 2 || records[0].Tool != "c" || records[1].Tool != "d" {
+		t.Errorf("Tail(2) = %+v, want c and d", records)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte(`{"tool":"old","status":"ok"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	got := make(chan string, 10)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- Follow(ctx, path, func(rec Record) error {
+			got <- rec.Tool
+
+			return nil
+		})
+	}()
+
+	// Let Follow reach the end of the existing log first
+	time.Sleep(100 * time.Millisecond)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	// A record written in two parts is only reported once complete
+	_, _ = file.WriteString(`{"tool":"new",`)
+	time.Sleep(2 * followInterval)
+	_, _ = file.WriteString(`"status":"ok"}` + "\n")
+
+	select {
+	case tool := <-got:
+		if tool != "new" {
+			t.Errorf("followed record = %q, want new", tool)
+		}
+	case <-ctx.Done():
+		t.Fatal("appended record was not reported")
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Follow: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Summary aggregates the records of an audit log.
+type Summary struct {
+	From    time.Time     `json:"from"`
+	To      time.Time     `json:"to"`
+	Calls   int64         `json:"calls"`
+	Errors  int64         `json:"errors"`
+	Tools   []ToolStats   `json:"tools"`
+	Clients []ClientStats `json:"clients"`
+}
+
+// ToolStats counts the calls of one tool.
+type ToolStats struct {
+	Tool   string  `json:"tool"`
+	Server string  `json:"server,omitempty"`
+	Calls  int64   `json:"calls"`
+	Errors int64   `json:"errors"`
+	AvgMS  float64 `json:"avg_ms"`
+	MaxMS  float64 `json:"max_ms"`
+}
+
+// ClientStats counts the calls made by one client.
+type ClientStats struct {
+	Client string `json:"client"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+}
+
+// Summarizer builds a Summary from records added one at a time.
+type Summarizer struct {
+	summary Summary
+	tools   map[string]*ToolStats
+	clients map[string]*ClientStats
+	totalMS map[string]float64
+}
+
+// NewSummarizer returns an empty summarizer.
+func NewSummarizer() *Summarizer {
+	return &Summarizer{
+		tools:   make(map[string]*ToolStats),
+		clients: make(map[string]*ClientStats),
+		totalMS: make(map[string]float64),
+	}
+}
+
+// Add counts one record.
+func (s *Summarizer) Add(rec Record) {
+	failed := rec.Status == StatusError
+
+	s.summary.Calls++
+	if failed {
+		s.summary.Errors++
+	}
+
+	if s.summary.From.IsZero() || rec.Time.Before(s.summary.From) {
+		s.summary.From = rec.Time
+	}
+
+	if rec.Time.After(s.summary.To) {
+		s.summary.To = rec.Time
+	}
+
+	tool, ok := s.tools[rec.Tool]
+	if !ok {
+		tool = &ToolStats{Tool: rec.Tool, Server: rec.Server}
+		s.tools[rec.Tool] = tool
+	}
+
+	tool.Calls++
+	tool.MaxMS = max(tool.MaxMS, rec.DurationMS)
+	s.totalMS[rec.Tool] += rec.DurationMS
+
+	client := rec.Client
+	if client == "" {
+		client = "unknown"
+	}
+
+	c, ok := s.clients[client]
+	if !ok {
+		c = &ClientStats{Client: client}
+		s.clients[client] = c
+	}
+
+	c.Calls++
+
+	if failed {
+		tool.Errors++
+		c.Errors++
+	}
+}
+
+// Summary returns the totals so far, tools and clients ordered by most
+// calls first.
+func (s *Summarizer) Summary() Summary {
+	out := s.summary
+
+	out.Tools = make([]ToolStats, 0, len(s.tools))
+	for _, name := range slices.Sorted(maps.Keys(s.tools)) {
+		tool := *s.tools[name]
+		tool.AvgMS = s.totalMS[name] / float64(tool.Calls)
+		out.Tools = append(out.Tools, tool)
+	}
+
+	out.Clients = make([]ClientStats, 0, len(s.clients))
+	for _, name := range slices.Sorted(maps.Keys(s.clients)) {
+		out.Clients = append(out.Clients, *s.clients[name])
+	}
+
+	slices.SortStableFunc(out.Tools, func(a, b ToolStats) int { return cmp.Compare(b.Calls, a.Calls) })
+	slices.SortStableFunc(out.Clients, func(a, b ClientStats) int { return cmp.Compare(b.Calls, a.Calls) })
+
+	return out
+}
@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizer(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+
+	s := NewSummarizer()
+	s.Add(Record{Time: start.Add(time.Minute), Tool: "github_search", Server: "github", Client: "cursor/1.0", DurationMS: 100, Status: StatusOK})
+	s.Add(Record{Time: start, Tool: "github_search", Server: "github", Client: "cursor/1.0", DurationMS: 300, Status: StatusError})
+	s.Add(Record{Time: start.Add(2 * time.Minute), Tool: "fs_read", Server: "fs", DurationMS: 5, Status: StatusOK})
+
+	got := s.Summary()
+
+	if got.Calls != 3 || got.Errors != 1 {
+		t.Errorf("totals = %d calls, %d errors; want 3, 1", got.Calls, got.Errors)
+	}
+
+	if !got.From.Equal(start) || !got.To.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("period = %v to %v, want %v to %v", got.From, got.To, start, start.Add(2*time.Minute))
+	}
+
+	if len(got.Tools) != 2 || got.Tools[0].Tool != "github_search" {
+		t.Fatalf("tools = %+v, want github_search first", got.Tools)
+	}
+
+	if tool := got.Tools[0]; tool.Calls != 2 || tool.Errors != 1 || tool.AvgMS != 200 || tool.MaxMS != 300 {
+		t.Errorf("github_search = %+v, want 2 calls, 1 error, avg 200, max 300", tool)
+	}
+
+	if len(got.Clients) != 2 || got.Clients[0].Client != "cursor/1.0" || got.Clients[1].Client != "unknown" {
+		t.Errorf("clients = %+v, want cursor/1.0 then unknown", got.Clients)
+	}
+}
@@ -0,0 +1,344 @@
+// Package cli provides interactive CLI components for assern.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/term"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// MCPFlags holds the flags of 'mcp add' and 'mcp edit', which configure a
+// server without prompts.
+type MCPFlags struct {
+	Transport string
+	Command   string
+	Args      []string
+	Env       []string // KEY=VALUE
+	UnsetEnv  []string
+	WorkDir   string
+	URL       string
+	Headers   []string // Name=Value
+	// UnsetHeaders names headers to remove
+	UnsetHeaders []string
+	Scope        string
+	Project      string
+
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthScopes       []string
+	OAuthMetadataURL  string
+	OAuthRedirectURI  string
+	OAuthPKCE         bool
+}
+
+// Apply sets the fields of input that the flags reported by changed were
+// given for, leaving the others as they are, so editing a server only
+// touches what was passed. Setting a command on a remote server turns it
+// into a stdio server and a url on a stdio server the other way around.
+func (f *MCPFlags) Apply(input *MCPInput, changed func(flag string) bool) error {
+	if changed("scope") {
+		switch ScopeType(f.Scope) {
+		case ScopeGlobal, ScopeProject:
+			input.Scope = ScopeType(f.Scope)
+		default:
+			return fmt.Errorf("invalid --scope %q (must be global or project)", f.Scope)
+		}
+	}
+
+	if changed("project") {
+		if input.Scope == ScopeGlobal && changed("scope") {
+			return errors.New("--project needs --scope project")
+		}
+
+		input.Scope = ScopeProject
+		input.Project = f.Project
+	}
+
+	if input.Scope == "" {
+		input.Scope = ScopeGlobal
+	}
+
+	if changed("command") {
+		input.Command = f.Command
+		if f.Command != "" && !changed("url") {
+			input.URL, input.Headers, input.OAuth = "", nil, nil
+		}
+	}
+
+	if changed("url") {
+		input.URL = f.URL
+		if f.URL != "" && !changed("command") {
+			input.Command, input.Args, input.WorkDir = "", nil, ""
+		}
+	}
+
+	if changed("args") {
+		input.Args = slices.Clone(f.Args)
+	}
+
+	if changed("work-dir") {
+		input.WorkDir = f.WorkDir
+	}
+
+	var err error
+	if input.Env, err = applyPairs(input.Env, f.Env, f.UnsetEnv, "--env", ValidateEnvVarKey); err != nil {
+		return err
+	}
+
+	if input.Headers, err = applyPairs(input.Headers, f.Headers, f.UnsetHeaders, "--header", nil); err != nil {
+		return err
+	}
+
+	f.applyOAuth(input, changed)
+
+	switch {
+	case changed("transport"):
+		input.Transport = f.Transport
+	case changed("command") || changed("url") || input.Transport == "":
+		input.Transport = inferTransport(input)
+	}
+
+	return nil
+}
+
+// applyOAuth sets the OAuth settings given by flags, creating the OAuth
+// configuration when the first one is set.
+func (f *MCPFlags) applyOAuth(input *MCPInput, changed func(flag string) bool) {
+	set := func(flag string, apply func(o *config.OAuthConfig)) {
+		if !changed(flag) {
+			return
+		}
+
+		if input.OAuth == nil {
+			input.OAuth = &config.OAuthConfig{}
+		}
+
+		apply(input.OAuth)
+	}
+
+	set("oauth-client-id", func(o *config.OAuthConfig) { o.ClientID = f.OAuthClientID })
+	set("oauth-client-secret", func(o *config.OAuthConfig) { o.ClientSecret = f.OAuthClientSecret })
+	set("oauth-scope", func(o *config.OAuthConfig) { o.Scopes = slices.Clone(f.OAuthScopes) })
+	set("oauth-metadata-url", func(o *config.OAuthConfig) { o.AuthServerMetadataURL = f.OAuthMetadataURL })
+	set("oauth-redirect-uri", func(o *config.OAuthConfig) { o.RedirectURI = f.OAuthRedirectURI })
+	set("oauth-pkce", func(o *config.OAuthConfig) { o.PKCEEnabled = f.OAuthPKCE })
+}
+
+// applyPairs sets the KEY=VALUE pairs of set in m and removes the keys of
+// unset, returning nil rather than an empty map.
+func applyPairs(m map[string]string, set, unset []string, flag string, validateKey func(string) error) (map[string]string, error) {
+	if len(set) == 0 && len(unset) == 0 {
+		return m, nil
+	}
+
+	m = maps.Clone(m)
+	if m == nil {
+		m = make(map[string]string)
+	}
+
+	for _, pair := range set {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s %q: want KEY=VALUE", flag, pair)
+		}
+
+		if validateKey != nil {
+			if err := validateKey(key); err != nil {
+				return nil, fmt.Errorf("%s %q: %w", flag, pair, err)
+			}
+		}
+
+		m[key] = value
+	}
+
+	for _, key := range unset {
+		delete(m, key)
+	}
+
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	return m, nil
+}
+
+// inferTransport picks the transport a server's settings call for.
+func inferTransport(input *MCPInput) string {
+	switch {
+	case input.Command != "":
+		return transportStdio
+	case input.URL != "" && input.OAuth != nil:
+		return transportOAuthHTTP
+	case isWebSocketURL(input.URL):
+		return transportWebSocket
+	case input.URL != "":
+		return transportHTTP
+	}
+
+	return ""
+}
+
+// MissingFields returns the flags input still needs before it can be
+// saved, such as "--command" for a stdio server without one.
+func MissingFields(input *MCPInput) []string {
+	var missing []string
+
+	if input.Name == "" {
+		missing = append(missing, "name")
+	}
+
+	switch transportConfigKind(input.Transport) {
+	case "stdio":
+		if input.Command == "" {
+			missing = append(missing, "--command")
+		}
+	case "http":
+		if input.URL == "" {
+			missing = append(missing, "--url")
+		}
+
+		if transportNeedsOAuth(input.Transport) && (input.OAuth == nil || input.OAuth.ClientID == "") {
+			missing = append(missing, "--oauth-client-id")
+		}
+	default:
+		if input.Transport == "" {
+			missing = append(missing, "--command or --url")
+		}
+	}
+
+	return missing
+}
+
+// ValidateInput checks a server configured by flags: its name, transport
+// and the settings the transport needs.
+func ValidateInput(input *MCPInput) error {
+	if missing := MissingFields(input); len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	if err := ValidateServerName(input.Name); err != nil {
+		return err
+	}
+
+	if err := ValidateTransport(input.Transport); err != nil {
+		return err
+	}
+
+	if input.Command != "" && input.URL != "" {
+		return errors.New("--command and --url are mutually exclusive")
+	}
+
+	switch {
+	case transportNeedsOAuth(input.Transport):
+		return ValidateHTTPSURL(input.URL)
+	case input.Transport == transportWebSocket || input.Transport == "" && isWebSocketURL(input.URL):
+		return ValidateWebSocketURL(input.URL)
+	case input.URL != "":
+		return ValidateURL(input.URL)
+	}
+
+	return nil
+}
+
+// PromptMissing asks for the settings input still needs (see
+// MissingFields) and nothing else.
+func PromptMissing(input *MCPInput) error {
+	if input.Name == "" {
+		if err := promptName(input); err != nil {
+			return err
+		}
+	}
+
+	if input.Transport == "" {
+		if err := promptTransport(input); err != nil {
+			return err
+		}
+	}
+
+	switch transportConfigKind(input.Transport) {
+	case "stdio":
+		if input.Command == "" {
+			if err := survey.AskOne(&survey.Input{
+				Message: "Command:",
+				Help:    "Executable to run (e.g., npx, node, python)",
+			}, &input.Command, survey.WithValidator(survey.Required)); err != nil {
+				return err
+			}
+		}
+	case "http":
+		if input.URL == "" {
+			if err := survey.AskOne(&survey.Input{
+				Message: "Server URL:",
+				Help:    "e.g., https://api.example.com/mcp",
+			}, &input.URL, survey.WithValidator(survey.Required)); err != nil {
+				return err
+			}
+		}
+
+		if transportNeedsOAuth(input.Transport) && (input.OAuth == nil || input.OAuth.ClientID == "") {
+			return promptOAuthConfig(input)
+		}
+	}
+
+	return nil
+}
+
+// ConfirmSave shows the summary of input and asks whether to save it.
+func ConfirmSave(input *MCPInput) (bool, error) {
+	fmt.Println("Configuration Summary:")
+	for _, line := range buildSummaryLines(input) {
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	var save bool
+	if err := survey.AskOne(&survey.Confirm{Message: "Save configuration?", Default: true}, &save); err != nil {
+		return false, err
+	}
+
+	return save, nil
+}
+
+// EnsureProject registers project in config.yaml, with dir as its
+// directory, unless it already exists. It reports whether it was created.
+func EnsureProject(project, dir string) (bool, error) {
+	cfg, err := config.LoadGlobal(config.LoadOptions{})
+	if err != nil {
+		return false, fmt.Errorf("loading projects: %w", err)
+	}
+
+	if _, ok := cfg.Projects[project]; ok {
+		return false, nil
+	}
+
+	if cfg.Projects == nil {
+		cfg.Projects = make(map[string]*config.ProjectConfig)
+	}
+
+	cfg.Projects[project] = &config.ProjectConfig{Directories: []string{dir}}
+
+	configPath, err := config.GlobalConfigPath()
+	if err != nil {
+		return false, err
+	}
+
+	if err := cfg.Save(configPath); err != nil {
+		return false, fmt.Errorf("saving project config: %w", err)
+	}
+
+	return true, nil
+}
+
+// IsInteractive reports whether stdin is a terminal, so prompts can be
+// answered.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
@@ -0,0 +1,240 @@
+// Package cli provides interactive CLI components for assern.
+package cli
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestMCPFlagsApply(t *testing.T) {
+	t.Parallel()
+
+	stdio := func() *MCPInput {
+		return &MCPInput{
+			Name: "github", Scope: ScopeGlobal, Transport: transportStdio, Command: "npx",
+			Args: []string{"-y", "gh"}, Env: map[string]string{"TOKEN": "${GH}", "DEBUG": "1"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		input   *MCPInput
+		flags   MCPFlags
+		changed []string
+		want    *MCPInput
+		wantErr string
+	}{
+		{
+			name:    "new stdio server",
+			input:   &MCPInput{Name: "fs"},
+			flags:   MCPFlags{Command: "npx", Args: []string{"-y", "fs"}, Env: []string{"ROOT=/tmp=x"}},
+			changed: []string{"command", "args", "env"},
+			want: &MCPInput{
+				Name: "fs", Scope: ScopeGlobal, Transport: transportStdio, Command: "npx",
+				Args: []string{"-y", "fs"}, Env: map[string]string{"ROOT": "/tmp=x"},
+			},
+		},
+		{
+			name:    "new OAuth server in a project",
+			input:   &MCPInput{Name: "api"},
+			flags:   MCPFlags{URL: "https://api.example.com/mcp", Project: "work", OAuthClientID: "id", OAuthScopes: []string{"read"}},
+			changed: []string{"url", "project", "oauth-client-id", "oauth-scope"},
+			want: &MCPInput{
+				Name: "api", Scope: ScopeProject, Project: "work", Transport: transportOAuthHTTP,
+				URL: "https://api.example.com/mcp", OAuth: &config.OAuthConfig{ClientID: "id", Scopes: []string{"read"}},
+			},
+		},
+		{
+			name:    "new websocket server",
+			input:   &MCPInput{Name: "live"},
+			flags:   MCPFlags{URL: "wss://live.example.com/mcp", Headers: []string{"Authorization=Bearer ${TOKEN}"}},
+			changed: []string{"url", "header"},
+			want: &MCPInput{
+				Name: "live", Scope: ScopeGlobal, Transport: transportWebSocket, URL: "wss://live.example.com/mcp",
+				Headers: map[string]string{"Authorization": "Bearer ${TOKEN}"},
+			},
+		},
+		{
+			name:    "partial edit",
+			input:   stdio(),
+			flags:   MCPFlags{Env: []string{"TOKEN=${GITHUB_TOKEN}"}, UnsetEnv: []string{"DEBUG"}, Command: "ignored"},
+			changed: []string{"env", "unset-env"},
+			want: &MCPInput{
+				Name: "github", Scope: ScopeGlobal, Transport: transportStdio, Command: "npx",
+				Args: []string{"-y", "gh"}, Env: map[string]string{"TOKEN": "${GITHUB_TOKEN}"},
+			},
+		},
+		{
+			name:    "stdio to remote",
+			input:   stdio(),
+			flags:   MCPFlags{URL: "https://example.com/sse", Transport: transportSSE, Headers: []string{"X-Key=k"}},
+			changed: []string{"url", "transport", "header"},
+			want: &MCPInput{
+				Name: "github", Scope: ScopeGlobal, Transport: transportSSE, URL: "https://example.com/sse",
+				Env: map[string]string{"TOKEN": "${GH}", "DEBUG": "1"}, Headers: map[string]string{"X-Key": "k"},
+			},
+		},
+		{
+			name:    "invalid env pair",
+			input:   &MCPInput{Name: "fs"},
+			flags:   MCPFlags{Env: []string{"NOVALUE"}},
+			changed: []string{"env"},
+			wantErr: `--env "NOVALUE": want KEY=VALUE`,
+		},
+		{
+			name:    "invalid env key",
+			input:   &MCPInput{Name: "fs"},
+			flags:   MCPFlags{Env: []string{"BAD-KEY=1"}},
+			changed: []string{"env"},
+			wantErr: "letters, numbers, and underscores",
+		},
+		{
+			name:    "invalid scope",
+			input:   &MCPInput{Name: "fs"},
+			flags:   MCPFlags{Scope: "team"},
+			changed: []string{"scope"},
+			wantErr: `invalid --scope "team"`,
+		},
+		{
+			name:    "project with global scope",
+			input:   &MCPInput{Name: "fs"},
+			flags:   MCPFlags{Scope: "global", Project: "work"},
+			changed: []string{"scope", "project"},
+			wantErr: "--project needs --scope project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.flags.Apply(tt.input, func(flag string) bool { return slices.Contains(tt.changed, flag) })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Apply() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.input, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", tt.input, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPFlagsApplyKeepsOriginal(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"A": "1"}
+	input := &MCPInput{Name: "fs", Command: "x", Env: env}
+
+	flags := MCPFlags{Env: []string{"B=2"}}
+	if err := flags.Apply(input, func(flag string) bool { return flag == "env" }); err != nil {
+		t.Fatal(err)
+	}
+
+	// The loaded server's map is not modified, only replaced
+	if !maps.Equal(env, map[string]string{"A": "1"}) {
+		t.Errorf("original env = %v, want it unchanged", env)
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       MCPInput
+		wantMissing []string
+		wantErr     string
+	}{
+		{name: "valid stdio", input: MCPInput{Name: "fs", Transport: transportStdio, Command: "npx"}},
+		{name: "valid http", input: MCPInput{Name: "api", Transport: transportHTTP, URL: "http://localhost:8080/mcp"}},
+		{
+			name:        "nothing given",
+			input:       MCPInput{},
+			wantMissing: []string{"name", "--command or --url"},
+			wantErr:     "missing name, --command or --url",
+		},
+		{
+			name:        "stdio without command",
+			input:       MCPInput{Name: "fs", Transport: transportStdio},
+			wantMissing: []string{"--command"},
+			wantErr:     "missing --command",
+		},
+		{
+			name:        "oauth without client",
+			input:       MCPInput{Name: "api", Transport: transportOAuthSSE, URL: "https://x.example.com"},
+			wantMissing: []string{"--oauth-client-id"},
+			wantErr:     "missing --oauth-client-id",
+		},
+		{
+			name:    "oauth over http",
+			input:   MCPInput{Name: "api", Transport: transportOAuthHTTP, URL: "http://x.example.com", OAuth: &config.OAuthConfig{ClientID: "id"}},
+			wantErr: "OAuth requires HTTPS URL",
+		},
+		{
+			name:    "command for a remote transport",
+			input:   MCPInput{Name: "api", Transport: transportHTTP, URL: "https://x.example.com", Command: "srv"},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "url for stdio",
+			input:   MCPInput{Name: "api", Transport: transportStdio, Command: "srv", URL: "https://x.example.com"},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:  "valid websocket",
+			input: MCPInput{Name: "api", Transport: transportWebSocket, URL: "wss://x.example.com/mcp"},
+		},
+		{
+			name:    "websocket over https",
+			input:   MCPInput{Name: "api", Transport: transportWebSocket, URL: "https://x.example.com/mcp"},
+			wantErr: "must be ws or wss",
+		},
+		{
+			name:    "unknown transport",
+			input:   MCPInput{Name: "api", Transport: "grpc", URL: "https://x.example.com"},
+			wantErr: "invalid transport type",
+		},
+		{
+			name:    "reserved name",
+			input:   MCPInput{Name: "all", Transport: transportStdio, Command: "x"},
+			wantErr: "reserved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := MissingFields(&tt.input); !slices.Equal(got, tt.wantMissing) {
+				t.Errorf("MissingFields() = %v, want %v", got, tt.wantMissing)
+			}
+
+			err := ValidateInput(&tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateInput() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateInput() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
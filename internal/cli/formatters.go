@@ -4,10 +4,13 @@ package cli
 import (
 	"fmt"
 	"strings"
+
+	"github.com/valksor/go-assern/internal/redact"
 )
 
-// FormatServerList formats a list of servers for display.
-func FormatServerList(servers []ServerInfo, verbose bool) string {
+// FormatServerList formats a list of servers for display. Secrets in
+// commands and URLs are redacted unless reveal is set.
+func FormatServerList(servers []ServerInfo, verbose, reveal bool) string {
 	if len(servers) == 0 {
 		return "No MCP servers configured."
 	}
@@ -35,7 +38,7 @@ func FormatServerList(servers []ServerInfo, verbose bool) string {
 		globalPath, _ := getGlobalPath()
 		fmt.Fprintf(&sb, "Global Servers (%s):\n", globalPath)
 		for _, srv := range globalServers {
-			formatServer(&sb, srv, verbose)
+			formatServer(&sb, srv, verbose, reveal)
 		}
 		sb.WriteString("\n")
 	}
@@ -48,7 +51,7 @@ func FormatServerList(servers []ServerInfo, verbose bool) string {
 		}
 		fmt.Fprintf(&sb, "\n")
 		for _, srv := range srvs {
-			formatServer(&sb, srv, verbose)
+			formatServer(&sb, srv, verbose, reveal)
 		}
 		sb.WriteString("\n")
 	}
@@ -56,10 +59,13 @@ func FormatServerList(servers []ServerInfo, verbose bool) string {
 	return sb.String()
 }
 
-// FormatServerDetail formats a single server's detailed configuration.
+// FormatServerDetail formats a single server's detailed configuration with
+// its secrets redacted.
 func FormatServerDetail(srv *ServerInfo) string {
 	var sb strings.Builder
 
+	server := redact.MCPServer(srv.Server)
+
 	fmt.Fprintf(&sb, "Name: %s\n", srv.Name)
 	fmt.Fprintf(&sb, "Scope: %s", srv.Scope)
 	if srv.Scope == ScopeProject && srv.Project != "" {
@@ -67,55 +73,69 @@ func FormatServerDetail(srv *ServerInfo) string {
 	}
 	fmt.Fprintf(&sb, "\n")
 	fmt.Fprintf(&sb, "Transport: %s\n", srv.Transport)
+	if server.Disabled {
+		fmt.Fprintf(&sb, "Status: disabled\n")
+	}
 
 	// Transport-specific details
 	switch srv.Transport {
 	case transportStdio:
-		if srv.Server.Command != "" {
-			fmt.Fprintf(&sb, "  Command: %s\n", srv.Server.Command)
+		if server.Command != "" {
+			fmt.Fprintf(&sb, "  Command: %s\n", server.Command)
+		}
+		if len(server.Args) > 0 {
+			fmt.Fprintf(&sb, "  Args: %s\n", strings.Join(server.Args, " "))
+		}
+		if server.WorkDir != "" {
+			fmt.Fprintf(&sb, "  Working Directory: %s\n", server.WorkDir)
 		}
-		if len(srv.Server.Args) > 0 {
-			fmt.Fprintf(&sb, "  Args: %s\n", strings.Join(srv.Server.Args, " "))
+	case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE, transportWebSocket:
+		if server.URL != "" {
+			fmt.Fprintf(&sb, "  URL: %s\n", server.URL)
 		}
-		if srv.Server.WorkDir != "" {
-			fmt.Fprintf(&sb, "  Working Directory: %s\n", srv.Server.WorkDir)
+	case transportContainer:
+		fmt.Fprintf(&sb, "  Image: %s\n", server.Image)
+		if len(server.Args) > 0 {
+			fmt.Fprintf(&sb, "  Args: %s\n", strings.Join(server.Args, " "))
 		}
-	case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE:
-		if srv.Server.URL != "" {
-			fmt.Fprintf(&sb, "  URL: %s\n", srv.Server.URL)
+		for _, volume := range server.Volumes {
+			fmt.Fprintf(&sb, "  Volume: %s\n", volume)
+		}
+		if server.Network != "" {
+			fmt.Fprintf(&sb, "  Network: %s\n", server.Network)
 		}
 	}
 
 	// OAuth details
-	if srv.Server.OAuth != nil {
+	if server.OAuth != nil {
 		fmt.Fprintf(&sb, "  OAuth:\n")
-		fmt.Fprintf(&sb, "    Client ID: %s\n", srv.Server.OAuth.ClientID)
-		if srv.Server.OAuth.ClientSecret != "" {
-			fmt.Fprintf(&sb, "    Client Secret: ***\n")
+		fmt.Fprintf(&sb, "    Client ID: %s\n", server.OAuth.ClientID)
+		if server.OAuth.ClientSecret != "" {
+			fmt.Fprintf(&sb, "    Client Secret: %s\n", server.OAuth.ClientSecret)
 		}
-		if len(srv.Server.OAuth.Scopes) > 0 {
-			fmt.Fprintf(&sb, "    Scopes: %s\n", strings.Join(srv.Server.OAuth.Scopes, ", "))
+		if len(server.OAuth.Scopes) > 0 {
+			fmt.Fprintf(&sb, "    Scopes: %s\n", strings.Join(server.OAuth.Scopes, ", "))
 		}
-		if srv.Server.OAuth.AuthServerMetadataURL != "" {
-			fmt.Fprintf(&sb, "    Auth Server: %s\n", srv.Server.OAuth.AuthServerMetadataURL)
+		if server.OAuth.AuthServerMetadataURL != "" {
+			fmt.Fprintf(&sb, "    Auth Server: %s\n", server.OAuth.AuthServerMetadataURL)
 		}
-		if srv.Server.OAuth.PKCEEnabled {
+		if server.OAuth.PKCEEnabled {
 			fmt.Fprintf(&sb, "    PKCE: enabled\n")
 		}
 	}
 
-	// Headers (mask values for credential-bearing header names)
-	if len(srv.Server.Headers) > 0 {
+	// Headers
+	if len(server.Headers) > 0 {
 		fmt.Fprintf(&sb, "  Headers:\n")
-		for k, v := range srv.Server.Headers {
-			fmt.Fprintf(&sb, "    %s: %s\n", k, maskHeaderValue(k, v))
+		for k, v := range server.Headers {
+			fmt.Fprintf(&sb, "    %s: %s\n", k, v)
 		}
 	}
 
 	// Environment variables
-	if len(srv.Server.Env) > 0 {
+	if len(server.Env) > 0 {
 		fmt.Fprintf(&sb, "  Environment:\n")
-		for k, v := range srv.Server.Env {
+		for k, v := range server.Env {
 			fmt.Fprintf(&sb, "    %s: %s\n", k, v)
 		}
 	}
@@ -123,40 +143,36 @@ func FormatServerDetail(srv *ServerInfo) string {
 	return sb.String()
 }
 
-// maskHeaderValue masks the value of credential-bearing headers (Authorization,
-// API keys, tokens, cookies) so secrets are not printed by `assern list`.
-func maskHeaderValue(key, value string) string {
-	if value == "" {
-		return value
-	}
-
-	lower := strings.ToLower(key)
-	for _, secret := range []string{"authorization", "api-key", "apikey", "token", "secret", "cookie", "password"} {
-		if strings.Contains(lower, secret) {
-			return "***"
-		}
-	}
-
-	return value
-}
-
 // formatServer formats a single server for list display.
-func formatServer(sb *strings.Builder, srv ServerInfo, verbose bool) {
+func formatServer(sb *strings.Builder, srv ServerInfo, verbose, reveal bool) {
 	status := "enabled"
-	_ = srv.Server.OAuth // Reserved for future use
+	if srv.Server.Disabled {
+		status = "disabled"
+	}
 
 	fmt.Fprintf(sb, "  %-20s %-10s %s", srv.Name, srv.Transport, status)
 
 	if verbose {
+		server := srv.Server
+		if !reveal {
+			server = redact.MCPServer(server)
+		}
+
 		switch srv.Transport {
 		case transportStdio:
-			fmt.Fprintf(sb, " (%s", srv.Server.Command)
-			if len(srv.Server.Args) > 0 {
-				fmt.Fprintf(sb, " %s", strings.Join(srv.Server.Args, " "))
+			fmt.Fprintf(sb, " (%s", server.Command)
+			if len(server.Args) > 0 {
+				fmt.Fprintf(sb, " %s", strings.Join(server.Args, " "))
+			}
+			fmt.Fprintf(sb, ")")
+		case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE, transportWebSocket:
+			fmt.Fprintf(sb, " (%s)", server.URL)
+		case transportContainer:
+			fmt.Fprintf(sb, " (%s", server.Image)
+			if len(server.Args) > 0 {
+				fmt.Fprintf(sb, " %s", strings.Join(server.Args, " "))
 			}
 			fmt.Fprintf(sb, ")")
-		case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE:
-			fmt.Fprintf(sb, " (%s)", srv.Server.URL)
 		}
 	}
 
@@ -39,8 +39,9 @@ func TestFormatServerList(t *testing.T) {
 					Scope:     ScopeGlobal,
 					Transport: "stdio",
 					Server: &config.MCPServer{
-						Command: "npx",
-						Args:    []string{"-y", "@modelcontextprotocol/server-filesystem"},
+						Command:  "npx",
+						Args:     []string{"-y", "@modelcontextprotocol/server-filesystem"},
+						Disabled: true,
 					},
 				},
 			},
@@ -51,6 +52,7 @@ func TestFormatServerList(t *testing.T) {
 				"filesystem",
 				"stdio",
 				"enabled",
+				"disabled",
 			},
 		},
 		{
@@ -102,7 +104,7 @@ func TestFormatServerList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := FormatServerList(tt.servers, tt.verbose)
+			result := FormatServerList(tt.servers, tt.verbose, false)
 
 			for _, expected := range tt.contains {
 				if !containsString(result, expected) {
@@ -153,6 +155,8 @@ func TestFormatServerDetail(t *testing.T) {
 					URL: "https://api.example.com/mcp",
 					Headers: map[string]string{
 						"Authorization": "Bearer ${API_TOKEN}",
+						"X-Api-Key":     "sk-live-123",
+						"Accept":        "application/json",
 					},
 				},
 			},
@@ -161,7 +165,9 @@ func TestFormatServerDetail(t *testing.T) {
 				"Transport: http",
 				"URL: https://api.example.com/mcp",
 				"Headers:",
-				"Authorization: ***",
+				"Authorization: Bearer ${API_TOKEN}",
+				"X-Api-Key: <redacted>",
+				"Accept: application/json",
 			},
 		},
 		{
@@ -190,7 +196,7 @@ func TestFormatServerDetail(t *testing.T) {
 				"URL: https://enterprise.com/mcp",
 				"OAuth:",
 				"Client ID: client-123",
-				"Client Secret: ***",
+				"Client Secret: <redacted>",
 				"Scopes: read, write",
 				"PKCE: enabled",
 			},
@@ -259,7 +265,7 @@ func TestFormatServer(t *testing.T) {
 		},
 	}
 
-	result := FormatServerList(servers, false)
+	result := FormatServerList(servers, false, false)
 
 	if !containsString(result, "test") {
 		t.Error("formatServer() output does not contain server name")
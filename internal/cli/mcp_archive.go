@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// archiveServers appends the servers in deleted to the archive of the
+// mcp.json at mcpPath.
+func archiveServers(mcpPath string, deleted *config.MCPArchive) error {
+	if len(deleted.Servers) == 0 {
+		return nil
+	}
+
+	path := config.MCPArchivePath(mcpPath)
+
+	archive, err := config.LoadMCPArchive(path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range deleted.Servers {
+		archive.Add(entry.Name, entry.Server, entry.DeletedAt)
+	}
+
+	if err := archive.Save(path); err != nil {
+		return fmt.Errorf("archiving deleted servers: %w", err)
+	}
+
+	return nil
+}
+
+// ArchivedInfo describes the latest deleted definition of a server.
+type ArchivedInfo struct {
+	Name      string
+	Scope     ScopeType
+	Transport string
+	DeletedAt time.Time
+}
+
+// ArchivedServers returns the latest deleted definition of each server
+// name in the global and project archives, most recently deleted first.
+func (m *MCPManager) ArchivedServers() ([]ArchivedInfo, error) {
+	var infos []ArchivedInfo
+
+	for _, scope := range []ScopeType{ScopeGlobal, ScopeProject} {
+		archive, _, err := m.loadArchive(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]bool)
+		for _, entry := range slices.Backward(archive.Servers) {
+			if seen[entry.Name] {
+				continue
+			}
+
+			seen[entry.Name] = true
+			infos = append(infos, ArchivedInfo{
+				Name:      entry.Name,
+				Scope:     scope,
+				Transport: detectTransport(entry.Server),
+				DeletedAt: entry.DeletedAt,
+			})
+		}
+	}
+
+	slices.SortStableFunc(infos, func(a, b ArchivedInfo) int {
+		return b.DeletedAt.Compare(a.DeletedAt)
+	})
+
+	return infos, nil
+}
+
+// RestoreServer moves the most recently deleted definition of name back
+// into the mcp.json it was deleted from, and returns where it went. It
+// fails if a server with that name exists.
+func (m *MCPManager) RestoreServer(name string) (ArchivedInfo, error) {
+	if err := m.checkDuplicate(name, ""); err != nil {
+		return ArchivedInfo{}, err
+	}
+
+	var (
+		found   config.ArchivedServer
+		scope   ScopeType
+		archive *config.MCPArchive
+		path    string
+	)
+
+	for _, s := range []ScopeType{ScopeGlobal, ScopeProject} {
+		a, p, err := m.loadArchive(s)
+		if err != nil {
+			return ArchivedInfo{}, err
+		}
+
+		if entry, ok := a.Latest(name); ok && (archive == nil || entry.DeletedAt.After(found.DeletedAt)) {
+			found, scope, archive, path = entry, s, a, p
+		}
+	}
+
+	if archive == nil {
+		return ArchivedInfo{}, fmt.Errorf("server %s not found in the archive", name)
+	}
+
+	if scope == ScopeGlobal {
+		if m.globalMCP.MCPServers == nil {
+			m.globalMCP.MCPServers = make(map[string]*config.MCPServer)
+		}
+		m.globalMCP.MCPServers[name] = found.Server
+
+		if err := m.globalMCP.Save(m.globalPath); err != nil {
+			return ArchivedInfo{}, fmt.Errorf("saving global config: %w", err)
+		}
+	} else {
+		if err := m.ensureLocal(); err != nil {
+			return ArchivedInfo{}, err
+		}
+
+		m.localMCP.MCPServers[name] = found.Server
+
+		if err := m.localMCP.Save(m.localPath); err != nil {
+			return ArchivedInfo{}, fmt.Errorf("saving local config: %w", err)
+		}
+	}
+
+	archive.RemoveLatest(name)
+	if err := archive.Save(path); err != nil {
+		return ArchivedInfo{}, fmt.Errorf("updating archive: %w", err)
+	}
+
+	return ArchivedInfo{
+		Name:      name,
+		Scope:     scope,
+		Transport: detectTransport(found.Server),
+		DeletedAt: found.DeletedAt,
+	}, nil
+}
+
+// loadArchive returns the archive of scope's mcp.json and its path. A
+// project without a .assern directory has an empty archive.
+func (m *MCPManager) loadArchive(scope ScopeType) (*config.MCPArchive, string, error) {
+	mcpPath := m.globalPath
+	if scope == ScopeProject {
+		mcpPath = m.localPath
+	}
+
+	if mcpPath == "" {
+		return &config.MCPArchive{}, "", nil
+	}
+
+	path := config.MCPArchivePath(mcpPath)
+
+	archive, err := config.LoadMCPArchive(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return archive, path, nil
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SetDisabled disables or enables servers, in every mcp.json that defines
+// them, and returns the names whose state changed. An empty names selects
+// all servers. Nothing is saved if a name is not found.
+func (m *MCPManager) SetDisabled(names []string, disabled bool) ([]string, error) {
+	if len(names) == 0 {
+		global, local := m.ServerNames()
+		names = append(global, local...)
+		slices.Sort(names)
+		names = slices.Compact(names)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, _, err := m.GetServer(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("server not found: %s", strings.Join(missing, ", "))
+	}
+
+	var changed []string
+	globalModified, localModified := false, false
+
+	for _, name := range names {
+		found := false
+
+		if srv, ok := m.globalMCP.MCPServers[name]; ok && srv.Disabled != disabled {
+			srv.Disabled = disabled
+			globalModified, found = true, true
+		}
+
+		if m.localMCP != nil {
+			if srv, ok := m.localMCP.MCPServers[name]; ok && srv.Disabled != disabled {
+				srv.Disabled = disabled
+				localModified, found = true, true
+			}
+		}
+
+		if found && !slices.Contains(changed, name) {
+			changed = append(changed, name)
+		}
+	}
+
+	if globalModified {
+		if err := m.globalMCP.Save(m.globalPath); err != nil {
+			return nil, fmt.Errorf("saving global config: %w", err)
+		}
+	}
+
+	if localModified {
+		if err := m.localMCP.Save(m.localPath); err != nil {
+			return nil, fmt.Errorf("saving local config: %w", err)
+		}
+	}
+
+	return changed, nil
+}
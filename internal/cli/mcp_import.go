@@ -0,0 +1,126 @@
+// Package cli provides interactive CLI components for assern.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// ImportAction is what to do with an imported server whose name is taken.
+type ImportAction string
+
+const (
+	ImportSkip      ImportAction = "skip"
+	ImportOverwrite ImportAction = "overwrite"
+	ImportRename    ImportAction = "rename"
+)
+
+// ImportServers writes imported servers to the global or project mcp.json
+// with a single save. Servers of the same name in that scope are replaced;
+// callers resolve collisions beforehand with ImportConflict.
+func (m *MCPManager) ImportServers(scope ScopeType, servers map[string]*config.MCPServer) error {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	if scope == ScopeGlobal {
+		if m.globalMCP.MCPServers == nil {
+			m.globalMCP.MCPServers = make(map[string]*config.MCPServer)
+		}
+		maps.Copy(m.globalMCP.MCPServers, servers)
+
+		return m.globalMCP.Save(m.globalPath)
+	}
+
+	if err := m.ensureLocal(); err != nil {
+		return err
+	}
+
+	maps.Copy(m.localMCP.MCPServers, servers)
+
+	return m.localMCP.Save(m.localPath)
+}
+
+// ImportConflict reports why name cannot be imported into scope as is: it
+// is not a valid server name, or a server of that name already exists.
+// The returned scope is where the existing server lives, empty if the name
+// is invalid. A nil error means the name is free.
+func (m *MCPManager) ImportConflict(name string, scope ScopeType) (ScopeType, error) {
+	if err := ValidateServerName(name); err != nil {
+		return "", err
+	}
+
+	if _, existing, err := m.GetServer(name); err == nil {
+		return existing, fmt.Errorf("server '%s' already exists in %s config", name, existing)
+	}
+
+	return "", nil
+}
+
+// PromptImportScope asks whether imported servers go to the global or the
+// project mcp.json.
+func PromptImportScope() (ScopeType, error) {
+	var scope string
+	if err := survey.AskOne(&survey.Select{
+		Message: "Import servers into:",
+		Options: []string{string(ScopeGlobal), string(ScopeProject)},
+		Default: string(ScopeGlobal),
+		Help:    "Global: ~/.valksor/assern/mcp.json\nProject: .assern/mcp.json in the current directory",
+	}, &scope, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+
+	return ScopeType(scope), nil
+}
+
+// PromptImportConflict asks what to do with an imported server whose name
+// cannot be used: skip it, overwrite the existing server (only offered when
+// canOverwrite) or import it under a new name. taken reports names that are
+// already used, so the new name does not collide again.
+func PromptImportConflict(name string, reason error, canOverwrite bool, taken func(string) bool) (ImportAction, string, error) {
+	fmt.Printf("\n%v\n", reason)
+
+	options := []string{string(ImportSkip), string(ImportRename)}
+	if canOverwrite {
+		options = append(options, string(ImportOverwrite))
+	}
+
+	var action string
+	if err := survey.AskOne(&survey.Select{
+		Message: fmt.Sprintf("What to do with '%s'?", name),
+		Options: options,
+		Default: string(ImportSkip),
+	}, &action, survey.WithValidator(survey.Required)); err != nil {
+		return "", "", err
+	}
+
+	if ImportAction(action) != ImportRename {
+		return ImportAction(action), name, nil
+	}
+
+	var newName string
+	if err := survey.AskOne(&survey.Input{
+		Message: "New name:",
+	}, &newName, survey.WithValidator(func(ans any) error {
+		val, ok := ans.(string)
+		if !ok {
+			return errors.New("expected string value")
+		}
+		if err := ValidateServerName(val); err != nil {
+			return err
+		}
+		if taken(val) {
+			return fmt.Errorf("server '%s' already exists", val)
+		}
+
+		return nil
+	})); err != nil {
+		return "", "", err
+	}
+
+	return ImportRename, newName, nil
+}
@@ -0,0 +1,104 @@
+// Package cli provides interactive CLI components for assern.
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestMCPManagerImportServers(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope ScopeType
+	}{
+		{name: "global", scope: ScopeGlobal},
+		{name: "project", scope: ScopeProject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, restore := setupTestConfig(t)
+			defer restore()
+
+			workDir := filepath.Join(tmpDir, "work")
+			if err := os.MkdirAll(workDir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			mgr, err := NewMCPManagerWithPath(workDir, config.LoadOptions{})
+			if err != nil {
+				t.Fatalf("NewMCPManagerWithPath() error = %v", err)
+			}
+
+			err = mgr.ImportServers(tt.scope, map[string]*config.MCPServer{
+				"github": {Command: "npx", Env: map[string]string{"TOKEN": "${GITHUB_TOKEN}"}},
+				"remote": {URL: "https://example.com/mcp", Headers: map[string]string{"X-Key": "k"}},
+			})
+			if err != nil {
+				t.Fatalf("ImportServers() error = %v", err)
+			}
+
+			// Reload from disk to check what was written
+			mgr, err = NewMCPManagerWithPath(workDir, config.LoadOptions{})
+			if err != nil {
+				t.Fatalf("NewMCPManagerWithPath() error = %v", err)
+			}
+
+			for _, name := range []string{"github", "remote"} {
+				_, scope, err := mgr.GetServer(name)
+				if err != nil {
+					t.Fatalf("GetServer(%s) error = %v", name, err)
+				}
+				if scope != tt.scope {
+					t.Errorf("GetServer(%s) scope = %s, want %s", name, scope, tt.scope)
+				}
+			}
+
+			srv, _, _ := mgr.GetServer("remote")
+			if srv.Headers["X-Key"] != "k" {
+				t.Errorf("remote headers = %v, want X-Key", srv.Headers)
+			}
+
+			if _, _, err := mgr.GetServer("test-server"); err != nil {
+				t.Errorf("existing server lost: %v", err)
+			}
+		})
+	}
+}
+
+func TestMCPManagerImportConflict(t *testing.T) {
+	tmpDir, restore := setupTestConfig(t)
+	defer restore()
+
+	mgr, err := NewMCPManagerWithPath(tmpDir, config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("NewMCPManagerWithPath() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		server       string
+		wantExisting ScopeType
+		wantErr      bool
+	}{
+		{name: "free", server: "github"},
+		{name: "exists", server: "test-server", wantExisting: ScopeGlobal, wantErr: true},
+		{name: "invalid", server: "my.server", wantErr: true},
+		{name: "reserved", server: "all", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := mgr.ImportConflict(tt.server, ScopeProject)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImportConflict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if existing != tt.wantExisting {
+				t.Errorf("ImportConflict() existing = %q, want %q", existing, tt.wantExisting)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/valksor/go-assern/internal/config"
 )
@@ -53,8 +54,9 @@ type MCPManager struct {
 	cwd        string
 }
 
-// NewMCPManager creates a manager for MCP operations.
-func NewMCPManager() (*MCPManager, error) {
+// NewMCPManager creates a manager for MCP operations, loading the mcp.json
+// files with opts.
+func NewMCPManager(opts config.LoadOptions) (*MCPManager, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("getting working directory: %w", err)
@@ -68,7 +70,7 @@ func NewMCPManager() (*MCPManager, error) {
 		return nil, fmt.Errorf("getting global MCP path: %w", err)
 	}
 	mgr.globalPath = globalPath
-	mgr.globalMCP, err = config.LoadMCPConfig(globalPath)
+	mgr.globalMCP, err = config.LoadMCPConfig(globalPath, opts)
 	if err != nil {
 		return nil, fmt.Errorf("loading global MCP config: %w", err)
 	}
@@ -78,7 +80,7 @@ func NewMCPManager() (*MCPManager, error) {
 	if localDir != "" {
 		mgr.localPath = config.LocalMCPPath(localDir)
 		if config.FileExists(mgr.localPath) {
-			mgr.localMCP, err = config.LoadMCPConfig(mgr.localPath)
+			mgr.localMCP, err = config.LoadMCPConfig(mgr.localPath, opts)
 			if err != nil {
 				return nil, fmt.Errorf("loading local MCP config: %w", err)
 			}
@@ -89,7 +91,7 @@ func NewMCPManager() (*MCPManager, error) {
 }
 
 // NewMCPManagerWithPath creates a manager with a specific working directory.
-func NewMCPManagerWithPath(cwd string) (*MCPManager, error) {
+func NewMCPManagerWithPath(cwd string, opts config.LoadOptions) (*MCPManager, error) {
 	mgr := &MCPManager{cwd: cwd}
 
 	// Load global config
@@ -98,7 +100,7 @@ func NewMCPManagerWithPath(cwd string) (*MCPManager, error) {
 		return nil, fmt.Errorf("getting global MCP path: %w", err)
 	}
 	mgr.globalPath = globalPath
-	mgr.globalMCP, err = config.LoadMCPConfig(globalPath)
+	mgr.globalMCP, err = config.LoadMCPConfig(globalPath, opts)
 	if err != nil {
 		return nil, fmt.Errorf("loading global MCP config: %w", err)
 	}
@@ -108,7 +110,7 @@ func NewMCPManagerWithPath(cwd string) (*MCPManager, error) {
 	if localDir != "" {
 		mgr.localPath = config.LocalMCPPath(localDir)
 		if config.FileExists(mgr.localPath) {
-			mgr.localMCP, err = config.LoadMCPConfig(mgr.localPath)
+			mgr.localMCP, err = config.LoadMCPConfig(mgr.localPath, opts)
 			if err != nil {
 				return nil, fmt.Errorf("loading local MCP config: %w", err)
 			}
@@ -147,6 +149,18 @@ func (m *MCPManager) AddServer(input *MCPInput) error {
 	}
 
 	// Add to local
+	if err := m.ensureLocal(); err != nil {
+		return err
+	}
+
+	m.localMCP.MCPServers[input.Name] = server
+
+	return m.localMCP.Save(m.localPath)
+}
+
+// ensureLocal prepares the project mcp.json for writing, creating the
+// .assern directory if needed.
+func (m *MCPManager) ensureLocal() error {
 	if m.localMCP == nil {
 		// Ensure local directory exists
 		localDir := config.FindLocalConfigDir(m.cwd)
@@ -164,9 +178,8 @@ func (m *MCPManager) AddServer(input *MCPInput) error {
 	if m.localMCP.MCPServers == nil {
 		m.localMCP.MCPServers = make(map[string]*config.MCPServer)
 	}
-	m.localMCP.MCPServers[input.Name] = server
 
-	return m.localMCP.Save(m.localPath)
+	return nil
 }
 
 // UpdateServer updates an existing server.
@@ -181,7 +194,9 @@ func (m *MCPManager) UpdateServer(name string, input *MCPInput) error {
 	server := m.inputToMCPServer(input)
 
 	// Find which config contains the server
-	if _, ok := m.globalMCP.MCPServers[name]; ok {
+	if old, ok := m.globalMCP.MCPServers[name]; ok {
+		keepUnprompted(server, old)
+
 		// Delete old name if renaming
 		if input.Name != name {
 			delete(m.globalMCP.MCPServers, name)
@@ -192,7 +207,9 @@ func (m *MCPManager) UpdateServer(name string, input *MCPInput) error {
 	}
 
 	if m.localMCP != nil {
-		if _, ok := m.localMCP.MCPServers[name]; ok {
+		if old, ok := m.localMCP.MCPServers[name]; ok {
+			keepUnprompted(server, old)
+
 			// Delete old name if renaming
 			if input.Name != name {
 				delete(m.localMCP.MCPServers, name)
@@ -206,20 +223,26 @@ func (m *MCPManager) UpdateServer(name string, input *MCPInput) error {
 	return fmt.Errorf("server %s not found", name)
 }
 
-// DeleteServer removes servers.
+// DeleteServer removes servers, moving their definitions to the archive
+// next to the mcp.json they were deleted from (see RestoreServer).
 func (m *MCPManager) DeleteServer(names []string) error {
 	modified := false
 	var deletedNames []string
 
+	now := time.Now()
+	globalArchive, localArchive := &config.MCPArchive{}, &config.MCPArchive{}
+
 	for _, name := range names {
-		if _, ok := m.globalMCP.MCPServers[name]; ok {
+		if srv, ok := m.globalMCP.MCPServers[name]; ok {
+			globalArchive.Add(name, srv, now)
 			delete(m.globalMCP.MCPServers, name)
 			deletedNames = append(deletedNames, name)
 			modified = true
 		}
 
 		if m.localMCP != nil {
-			if _, ok := m.localMCP.MCPServers[name]; ok {
+			if srv, ok := m.localMCP.MCPServers[name]; ok {
+				localArchive.Add(name, srv, now)
 				delete(m.localMCP.MCPServers, name)
 				// Only add to deletedNames if not already added from global
 				found := slices.Contains(deletedNames, name)
@@ -235,6 +258,15 @@ func (m *MCPManager) DeleteServer(names []string) error {
 		return errors.New("none of the specified servers were found")
 	}
 
+	// Archive first, so a failed save never loses a definition
+	if err := archiveServers(m.globalPath, globalArchive); err != nil {
+		return err
+	}
+
+	if err := archiveServers(m.localPath, localArchive); err != nil {
+		return err
+	}
+
 	// Save modified configs
 	if err := m.globalMCP.Save(m.globalPath); err != nil {
 		return fmt.Errorf("saving global config: %w", err)
@@ -324,6 +356,14 @@ func (m *MCPManager) inputToMCPServer(input *MCPInput) *config.MCPServer {
 	return server
 }
 
+// keepUnprompted carries over the settings the interactive prompts do not
+// cover, so editing a server does not drop them.
+func keepUnprompted(server, old *config.MCPServer) {
+	server.Lazy = old.Lazy
+	server.Tools = old.Tools
+	server.Disabled = old.Disabled
+}
+
 // checkDuplicate checks if a server name already exists (excluding the given skipName).
 func (m *MCPManager) checkDuplicate(name, skipName string) error {
 	if name == skipName {
@@ -349,6 +389,10 @@ func detectTransport(srv *config.MCPServer) string {
 		return srv.Transport
 	}
 
+	if srv.Image != "" {
+		return transportContainer
+	}
+
 	if srv.Command != "" {
 		return transportStdio
 	}
@@ -357,6 +401,10 @@ func detectTransport(srv *config.MCPServer) string {
 		return transportOAuthHTTP
 	}
 
+	if isWebSocketURL(srv.URL) {
+		return transportWebSocket
+	}
+
 	if srv.URL != "" {
 		return transportHTTP
 	}
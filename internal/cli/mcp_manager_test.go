@@ -45,7 +45,7 @@ func TestNewMCPManager(t *testing.T) {
 	// Create manager with test directory
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -69,7 +69,7 @@ func TestMCPManagerListServers(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -105,7 +105,7 @@ func TestMCPManagerGetServer(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -134,7 +134,7 @@ func TestMCPManagerGetServerNotFound(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -151,7 +151,7 @@ func TestMCPManagerAddServer(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -191,7 +191,7 @@ func TestMCPManagerAddServerDuplicate(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -215,7 +215,7 @@ func TestMCPManagerUpdateServer(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -246,7 +246,7 @@ func TestMCPManagerDeleteServer(t *testing.T) {
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
@@ -263,13 +263,106 @@ func TestMCPManagerDeleteServer(t *testing.T) {
 	}
 }
 
+func TestMCPManagerRestoreServer(t *testing.T) {
+	tmpDir, restore := setupTestConfig(t)
+	defer restore()
+
+	t.Chdir(tmpDir)
+
+	mgr, err := NewMCPManager(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("NewMCPManager() error = %v", err)
+	}
+
+	if err := mgr.DeleteServer([]string{"test-server"}); err != nil {
+		t.Fatalf("DeleteServer() error = %v", err)
+	}
+
+	archived, err := mgr.ArchivedServers()
+	if err != nil || len(archived) != 1 || archived[0].Name != "test-server" || archived[0].Scope != ScopeGlobal {
+		t.Fatalf("ArchivedServers() = %+v, %v; want test-server in the global archive", archived, err)
+	}
+
+	// A fresh manager sees the archive written by the deletion
+	mgr, err = NewMCPManager(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("NewMCPManager() error = %v", err)
+	}
+
+	info, err := mgr.RestoreServer("test-server")
+	if err != nil {
+		t.Fatalf("RestoreServer() error = %v", err)
+	}
+
+	if info.Scope != ScopeGlobal || info.Transport != transportStdio {
+		t.Errorf("RestoreServer() = %+v, want a global stdio server", info)
+	}
+
+	srv, _, err := mgr.GetServer("test-server")
+	if err != nil || srv.Command != "node" || len(srv.Args) != 1 {
+		t.Errorf("restored server = %+v, %v; want the deleted definition", srv, err)
+	}
+
+	if archived, _ := mgr.ArchivedServers(); len(archived) != 0 {
+		t.Errorf("ArchivedServers() after restore = %+v, want empty", archived)
+	}
+
+	if _, err := mgr.RestoreServer("test-server"); err == nil {
+		t.Error("RestoreServer() over an existing server succeeded, want error")
+	}
+}
+
+func TestMCPManagerSetDisabled(t *testing.T) {
+	tmpDir, restore := setupTestConfig(t)
+	defer restore()
+
+	t.Chdir(tmpDir)
+
+	mgr, err := NewMCPManager(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("NewMCPManager() error = %v", err)
+	}
+
+	if _, err := mgr.SetDisabled([]string{"test-server", "missing"}, true); err == nil {
+		t.Fatal("SetDisabled() with an unknown server succeeded, want error")
+	}
+
+	if srv, _, _ := mgr.GetServer("test-server"); srv.Disabled {
+		t.Fatal("SetDisabled() changed a server despite failing")
+	}
+
+	changed, err := mgr.SetDisabled([]string{"test-server"}, true)
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("SetDisabled() = %v, %v; want [test-server]", changed, err)
+	}
+
+	// Disabling again changes nothing
+	if changed, _ := mgr.SetDisabled(nil, true); len(changed) != 0 {
+		t.Errorf("SetDisabled() of a disabled server = %v, want none", changed)
+	}
+
+	// The state is saved
+	mgr, err = NewMCPManager(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("NewMCPManager() error = %v", err)
+	}
+
+	if srv, _, _ := mgr.GetServer("test-server"); !srv.Disabled {
+		t.Error("disabled state was not saved")
+	}
+
+	if changed, err := mgr.SetDisabled(nil, false); err != nil || len(changed) != 1 {
+		t.Errorf("SetDisabled(all, false) = %v, %v; want [test-server]", changed, err)
+	}
+}
+
 func TestMCPManagerServerNames(t *testing.T) {
 	tmpDir, restore := setupTestConfig(t)
 	defer restore()
 
 	t.Chdir(tmpDir)
 
-	mgr, err := NewMCPManager()
+	mgr, err := NewMCPManager(config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("NewMCPManager() error = %v", err)
 	}
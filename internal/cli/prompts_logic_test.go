@@ -73,7 +73,7 @@ func TestTransportNeedsOAuth(t *testing.T) {
 		{name: "sse", transport: transportSSE, want: false},
 		{name: "oauth-http", transport: transportOAuthHTTP, want: true},
 		{name: "oauth-sse", transport: transportOAuthSSE, want: true},
-		{name: "unknown", transport: "websocket", want: false},
+		{name: "unknown", transport: "grpc", want: false},
 		{name: "empty", transport: "", want: false},
 	}
 
@@ -101,7 +101,8 @@ func TestTransportConfigKind(t *testing.T) {
 		{name: "sse", transport: transportSSE, want: "http"},
 		{name: "oauth-http", transport: transportOAuthHTTP, want: "http"},
 		{name: "oauth-sse", transport: transportOAuthSSE, want: "http"},
-		{name: "unknown", transport: "websocket", want: ""},
+		{name: "websocket", transport: transportWebSocket, want: "http"},
+		{name: "unknown", transport: "grpc", want: ""},
 		{name: "empty", transport: "", want: ""},
 	}
 
@@ -4,6 +4,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -45,6 +46,24 @@ func SelectServers(serverNames []string, promptMsg string) ([]string, error) {
 	return selected, nil
 }
 
+// ConfirmReveal asks for confirmation before printing secrets. The prompt
+// goes to stderr so that the revealed output can still be redirected.
+func ConfirmReveal() error {
+	var confirm bool
+	if err := survey.AskOne(&survey.Confirm{
+		Message: "Print secrets (env values, tokens, credential headers) in clear text?",
+		Default: false,
+	}, &confirm, survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)); err != nil {
+		return err
+	}
+
+	if !confirm {
+		return errors.New("cancelled by user")
+	}
+
+	return nil
+}
+
 // ConfirmDelete asks for confirmation before deleting.
 func ConfirmDelete(names []string) error {
 	fmt.Printf("\nAbout to delete %d server(s):\n", len(names))
@@ -70,7 +89,7 @@ func ConfirmDelete(names []string) error {
 
 // detectProject attempts to detect the current project.
 func detectProject(cwd string) string {
-	cfg, err := config.LoadGlobal()
+	cfg, err := config.LoadGlobal(config.LoadOptions{})
 	if err != nil {
 		return ""
 	}
@@ -79,12 +98,17 @@ func detectProject(cwd string) string {
 	resolver := &configPathResolver{}
 	registry := project.NewRegistry()
 	for name, proj := range cfg.Projects {
-		registry.Register(name, proj.Directories, nil)
+		registry.Add(&project.RegistryProject{
+			Name:        name,
+			Directories: proj.Directories,
+			Remotes:     proj.Remotes,
+			Priority:    proj.Priority,
+		})
 	}
 
 	detector := project.NewDetector(resolver, ".assern", registry)
 	detector.SetConfigLoader(func(path string) (any, error) {
-		return config.LoadLocalProject(path)
+		return config.LoadLocalProject(path, config.LoadOptions{})
 	})
 
 	ctx, err := detector.Detect(cwd)
@@ -111,7 +111,7 @@ func promptScope(input *MCPInput) error {
 // promptProjectSelection prompts for project selection.
 func promptProjectSelection(input *MCPInput, detected string) error {
 	// Load existing projects
-	cfg, err := config.LoadGlobal()
+	cfg, err := config.LoadGlobal(config.LoadOptions{})
 	if err != nil {
 		return fmt.Errorf("loading projects: %w", err)
 	}
@@ -192,7 +192,7 @@ func promptNewProject(input *MCPInput) error {
 			return err
 		}
 		// Create the project in global config
-		cfg, _ := config.LoadGlobal()
+		cfg, _ := config.LoadGlobal(config.LoadOptions{})
 		if cfg.Projects == nil {
 			cfg.Projects = make(map[string]*config.ProjectConfig)
 		}
@@ -307,7 +307,7 @@ func buildSummaryLines(input *MCPInput) []string {
 		if input.WorkDir != "" {
 			lines = append(lines, "    Working Dir: "+input.WorkDir)
 		}
-	case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE:
+	case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE, transportWebSocket:
 		lines = append(lines, "    URL: "+input.URL)
 		if input.OAuth != nil {
 			lines = append(lines, fmt.Sprintf("    OAuth: ClientID=%s, Scopes=%v", input.OAuth.ClientID, input.OAuth.Scopes))
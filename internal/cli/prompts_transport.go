@@ -16,7 +16,7 @@ func transportConfigKind(transport string) string {
 	switch transport {
 	case transportStdio:
 		return "stdio"
-	case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE:
+	case transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE, transportWebSocket:
 		return "http"
 	default:
 		return ""
@@ -40,14 +40,14 @@ func promptTransport(input *MCPInput) error {
 		return nil
 	}
 
-	options := []string{transportStdio, transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE}
+	options := []string{transportStdio, transportHTTP, transportSSE, transportOAuthHTTP, transportOAuthSSE, transportWebSocket}
 
 	var transport string
 	if err := survey.AskOne(&survey.Select{
 		Message: "Transport type:",
 		Options: options,
 		Default: transportStdio,
-		Help:    "stdio: local subprocess\nhttp/sse: remote server\noauth-*: authenticated remote server",
+		Help:    "stdio: local subprocess\nhttp/sse: remote server\noauth-*: authenticated remote server\nwebsocket: remote server over ws:// or wss://",
 	}, &transport, survey.WithValidator(survey.Required)); err != nil {
 		return err
 	}
@@ -202,9 +202,14 @@ func promptEnvVars(input *MCPInput) error {
 func promptHTTPConfig(input *MCPInput, useOAuth bool) error {
 	// URL
 	if input.URL == "" {
+		help := "e.g., https://api.example.com/mcp"
+		if input.Transport == transportWebSocket {
+			help = "e.g., wss://api.example.com/mcp"
+		}
+
 		if err := survey.AskOne(&survey.Input{
 			Message: "Server URL:",
-			Help:    "e.g., https://api.example.com/mcp",
+			Help:    help,
 		}, &input.URL, survey.WithValidator(func(ans any) error {
 			val, ok := ans.(string)
 			if !ok {
@@ -213,6 +218,9 @@ func promptHTTPConfig(input *MCPInput, useOAuth bool) error {
 			if useOAuth {
 				return ValidateHTTPSURL(val)
 			}
+			if input.Transport == transportWebSocket {
+				return ValidateWebSocketURL(val)
+			}
 
 			return ValidateURL(val)
 		})); err != nil {
@@ -16,6 +16,8 @@ const (
 	transportSSE       = "sse"
 	transportOAuthHTTP = "oauth-http"
 	transportOAuthSSE  = "oauth-sse"
+	transportWebSocket = "websocket"
+	transportContainer = "container"
 )
 
 // reservedNames are server names that cannot be used.
@@ -75,6 +77,33 @@ func ValidateURL(u string) error {
 	return nil
 }
 
+// ValidateWebSocketURL checks if a string is a valid WebSocket URL.
+func ValidateWebSocketURL(u string) error {
+	if u == "" {
+		return errors.New("URL cannot be empty")
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsed.Scheme != "ws" && parsed.Scheme != "wss" {
+		return errors.New("WebSocket URL scheme must be ws or wss")
+	}
+
+	if parsed.Host == "" {
+		return errors.New("URL must include a host")
+	}
+
+	return nil
+}
+
+// isWebSocketURL reports whether u has a ws or wss scheme.
+func isWebSocketURL(u string) bool {
+	return strings.HasPrefix(u, "ws://") || strings.HasPrefix(u, "wss://")
+}
+
 // ValidateHTTPSURL checks if a string is a valid HTTPS URL.
 func ValidateHTTPSURL(u string) error {
 	if err := ValidateURL(u); err != nil {
@@ -121,11 +150,12 @@ func ValidateTransport(transport string) error {
 		transportSSE:       true,
 		transportOAuthHTTP: true,
 		transportOAuthSSE:  true,
+		transportWebSocket: true,
 		"":                 true, // Auto-detect
 	}
 
 	if !validTransports[transport] {
-		return fmt.Errorf("invalid transport type: %s (must be stdio, http, sse, oauth-http, or oauth-sse)", transport)
+		return fmt.Errorf("invalid transport type: %s (must be stdio, http, sse, oauth-http, oauth-sse, or websocket)", transport)
 	}
 
 	return nil
@@ -221,6 +221,65 @@ func TestValidateHTTPSURL(t *testing.T) {
 	}
 }
 
+func TestValidateWebSocketURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "valid ws URL",
+			input:   "ws://localhost:8080/mcp",
+			wantErr: false,
+		},
+		{
+			name:    "valid wss URL",
+			input:   "wss://example.com/mcp",
+			wantErr: false,
+		},
+		{
+			name:        "HTTP URL not allowed",
+			input:       "https://example.com/mcp",
+			wantErr:     true,
+			errContains: "must be ws or wss",
+		},
+		{
+			name:        "missing host",
+			input:       "wss:///mcp",
+			wantErr:     true,
+			errContains: "must include a host",
+		},
+		{
+			name:        "empty URL",
+			input:       "",
+			wantErr:     true,
+			errContains: "cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebSocketURL(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWebSocketURL() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil {
+					t.Errorf("ValidateWebSocketURL() expected error containing %q, got nil", tt.errContains)
+
+					return
+				}
+				if !containsString(err.Error(), tt.errContains) {
+					t.Errorf("ValidateWebSocketURL() error = %q, want error containing %q", err.Error(), tt.errContains)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateEnvVarKey(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -317,6 +376,11 @@ func TestValidateTransport(t *testing.T) {
 			input:   "oauth-sse",
 			wantErr: false,
 		},
+		{
+			name:    "websocket",
+			input:   "websocket",
+			wantErr: false,
+		},
 		{
 			name:    "empty (auto-detect)",
 			input:   "",
@@ -324,7 +388,7 @@ func TestValidateTransport(t *testing.T) {
 		},
 		{
 			name:        "invalid transport",
-			input:       "websocket",
+			input:       "grpc",
 			wantErr:     true,
 			errContains: "invalid transport type",
 		},
@@ -0,0 +1,79 @@
+// Package clock abstracts reading the time and waiting for it, so that
+// timeouts, caches, backoffs and health checks can be driven by a Fake in
+// tests instead of sleeping.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and schedules wake-ups.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+	// Until returns the duration until t.
+	Until(t time.Time) time.Duration
+	// After returns a channel receiving the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a ticker sending the time every d. d must be
+	// positive.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	// C returns the channel the ticks are delivered on.
+	C() <-chan time.Time
+	// Stop turns the ticker off; no more ticks are sent.
+	Stop()
+}
+
+// Real is the system clock.
+var Real Clock = realClock{}
+
+// OrReal returns c, or Real when c is nil.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+
+	return c
+}
+
+// WithTimeout is context.WithTimeout on c: the returned context is
+// cancelled, with context.DeadlineExceeded as its cause, once d has elapsed
+// on c.
+func WithTimeout(parent context.Context, c Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.After(d):
+			cancel(context.DeadlineExceeded)
+		}
+	}()
+
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration        { return time.Until(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
@@ -0,0 +1,176 @@
+package clock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock for tests: its time only moves when Advance or Set is
+// called, firing the After channels and tickers that have come due. Code
+// waiting in another goroutine can be synchronized with BlockUntil before
+// advancing.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending After channel or an active ticker.
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration // Zero for After
+	ch     chan time.Time
+}
+
+var _ Clock = (*Fake)(nil)
+
+// NewFake returns a Fake clock reading now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Since returns the fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Until returns the fake duration until t.
+func (f *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// After returns a channel receiving the fake time once it has been
+// advanced by d. It fires at once when d is not positive.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+
+		return ch
+	}
+
+	f.add(&fakeWaiter{at: f.now.Add(d), ch: ch})
+
+	return ch
+}
+
+// NewTicker returns a ticker firing each time the fake time passes another
+// multiple of d. Like time.Ticker, ticks a slow receiver misses are
+// dropped.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{at: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.add(w)
+
+	return &fakeTicker{clock: f, w: w}
+}
+
+// add registers w and wakes BlockUntil. f.mu must be held.
+func (f *Fake) add(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+}
+
+// Advance moves the fake time forward by d, firing what came due in order.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the fake time to t, firing what came due in order. Moving it
+// backwards fires nothing.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		i := f.nextDue(t)
+		if i < 0 {
+			break
+		}
+
+		w := f.waiters[i]
+		f.now = w.at
+
+		select {
+		case w.ch <- w.at:
+		default:
+		}
+
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			f.waiters = slices.Delete(f.waiters, i, i+1)
+		}
+	}
+
+	f.now = t
+}
+
+// nextDue returns the index of the earliest waiter due at or before t, or
+// -1. f.mu must be held.
+func (f *Fake) nextDue(t time.Time) int {
+	next := -1
+
+	for i, w := range f.waiters {
+		if !w.at.After(t) && (next < 0 || w.at.Before(f.waiters[next].at)) {
+			next = i
+		}
+	}
+
+	return next
+}
+
+// Waiters returns the number of pending After channels and active tickers.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n After channels and tickers are
+// pending, i.e. until the code under test is waiting on the clock.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+type fakeTicker struct {
+	clock *Fake
+	w     *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	t.clock.waiters = slices.DeleteFunc(t.clock.waiters, func(w *fakeWaiter) bool { return w == t.w })
+}
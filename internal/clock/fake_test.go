@@ -0,0 +1,157 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/clock"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFake_After(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		wait    time.Duration
+		advance time.Duration
+		fired   bool
+	}{
+		{"before deadline", time.Minute, 59 * time.Second, false},
+		{"at deadline", time.Minute, time.Minute, true},
+		{"past deadline", time.Minute, time.Hour, true},
+		{"non-positive wait", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := clock.NewFake(epoch)
+			ch := f.After(tt.wait)
+			f.Advance(tt.advance)
+
+			select {
+			case got := <-ch:
+				if !tt.fired {
+					t.Fatalf("fired at %v, want pending", got)
+				}
+
+				if want := epoch.Add(tt.wait); !got.Equal(want) {
+					t.Errorf("fired with %v, want %v", got, want)
+				}
+			default:
+				if tt.fired {
+					t.Fatal("not fired")
+				}
+			}
+
+			if got, want := f.Now(), epoch.Add(tt.advance); !got.Equal(want) {
+				t.Errorf("Now() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFake_Ticker(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	ticker := f.NewTicker(10 * time.Second)
+
+	f.Advance(10 * time.Second)
+
+	if got := <-ticker.C(); !got.Equal(epoch.Add(10 * time.Second)) {
+		t.Errorf("first tick = %v", got)
+	}
+
+	// Ticks the receiver misses are dropped, as with time.Ticker
+	f.Advance(35 * time.Second)
+
+	if got := <-ticker.C(); !got.Equal(epoch.Add(20 * time.Second)) {
+		t.Errorf("tick after missed ones = %v, want the first missed", got)
+	}
+
+	ticker.Stop()
+	f.Advance(time.Minute)
+
+	select {
+	case got := <-ticker.C():
+		t.Errorf("tick %v after Stop", got)
+	default:
+	}
+
+	if n := f.Waiters(); n != 0 {
+		t.Errorf("Waiters() = %d after Stop, want 0", n)
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	done := make(chan time.Time)
+
+	go func() { done <- <-f.After(time.Hour) }()
+
+	f.BlockUntil(1)
+	f.Advance(time.Hour)
+
+	if got := <-done; !got.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("woke at %v", got)
+	}
+}
+
+func TestFake_SinceUntil(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	f.Advance(90 * time.Second)
+
+	if got := f.Since(epoch); got != 90*time.Second {
+		t.Errorf("Since = %v, want 1m30s", got)
+	}
+
+	if got := f.Until(epoch.Add(2 * time.Minute)); got != 30*time.Second {
+		t.Errorf("Until = %v, want 30s", got)
+	}
+}
+
+func TestOrReal(t *testing.T) {
+	t.Parallel()
+
+	if clock.OrReal(nil) != clock.Real {
+		t.Error("OrReal(nil) is not Real")
+	}
+
+	f := clock.NewFake(epoch)
+	if clock.OrReal(f) != f {
+		t.Error("OrReal(f) is not f")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+
+	ctx, cancel := clock.WithTimeout(t.Context(), f, time.Minute)
+	defer cancel()
+
+	f.BlockUntil(1)
+	f.Advance(59 * time.Second)
+
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("ctx.Err() before the timeout = %v", err)
+	}
+
+	f.Advance(time.Second)
+	<-ctx.Done()
+
+	if cause := context.Cause(ctx); !errors.Is(cause, context.DeadlineExceeded) {
+		t.Errorf("context.Cause() = %v, want DeadlineExceeded", cause)
+	}
+}
@@ -0,0 +1,299 @@
+package compat
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/env"
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// Features checked for every target, in report order.
+const (
+	FeatureTools         = "tools"
+	FeatureResources     = "resources"
+	FeaturePrompts       = "prompts"
+	FeatureNotifications = "notifications"
+)
+
+// Features lists the checked features in report order.
+var Features = []string{FeatureTools, FeatureResources, FeaturePrompts, FeatureNotifications}
+
+// notificationWait is how long to wait for the startup summary.
+const notificationWait = 5 * time.Second
+
+// baseline is what the backend offers when connected to directly, to tell
+// "the server has no resources" from "aggregation lost them". Counts are -1
+// when unknown.
+type baseline struct {
+	resources int
+	prompts   int
+}
+
+// unknownBaseline is used when the backend cannot be queried directly.
+var unknownBaseline = baseline{resources: -1, prompts: -1}
+
+// backendBaseline connects to a stdio target directly and counts its
+// resources and prompts.
+func backendBaseline(ctx context.Context, target Target) baseline {
+	base := unknownBaseline
+	if target.Server == nil || target.Server.Command == "" {
+		return base
+	}
+
+	var environ []string
+	for key, value := range env.ExpandEnvInMap(target.Server.Env) {
+		environ = append(environ, key+"="+value)
+	}
+
+	c, err := client.NewStdioMCPClient(target.Server.Command, environ, target.Server.Args...)
+	if err != nil {
+		return base
+	}
+	defer func() { _ = c.Close() }()
+
+	var req mcp.InitializeRequest
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "assern-compat", Version: version.Version}
+
+	init, err := c.Initialize(ctx, req)
+	if err != nil {
+		return base
+	}
+
+	base = baseline{}
+
+	if init.Capabilities.Resources != nil {
+		if list, err := c.ListResources(ctx, mcp.ListResourcesRequest{}); err == nil {
+			base.resources = len(list.Resources)
+		} else {
+			base.resources = -1
+		}
+	}
+
+	if init.Capabilities.Prompts != nil {
+		if list, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{}); err == nil {
+			base.prompts = len(list.Prompts)
+		} else {
+			base.prompts = -1
+		}
+	}
+
+	return base
+}
+
+// check runs the feature checks against an initialized client.
+func check(ctx context.Context, c *client.Client, target Target, notes *notifications, base baseline) []Result {
+	return []Result{
+		checkTools(ctx, c, target),
+		checkResources(ctx, c, target, base.resources),
+		checkPrompts(ctx, c, target, base.prompts),
+		checkNotifications(ctx, notes, target),
+	}
+}
+
+// missing is the result of a feature the backend offers (want items when
+// listed directly) but the aggregated server does not.
+func missing(result Result, want int) Result {
+	if want <= 0 {
+		result.Status = StatusNone
+
+		return result
+	}
+
+	result.Status, result.Detail = StatusFail, fmt.Sprintf("the server lists %d directly, none through assern", want)
+
+	return result
+}
+
+// checkTools lists the target's aggregated tools and makes the probe call.
+func checkTools(ctx context.Context, c *client.Client, target Target) Result {
+	result := Result{Feature: FeatureTools}
+
+	list, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		result.Status, result.Detail = StatusFail, "tools/list: "+err.Error()
+
+		return result
+	}
+
+	var names []string
+
+	for _, tool := range list.Tools {
+		if strings.HasPrefix(tool.Name, aggregator.PrefixToolName(target.Name, "")) {
+			names = append(names, tool.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		result.Status, result.Detail = StatusFail, "no tools of the server were listed"
+
+		return result
+	}
+
+	result.Status, result.Detail = StatusOK, fmt.Sprintf("%d listed", len(names))
+
+	if target.Tool == "" {
+		return result
+	}
+
+	name := aggregator.PrefixToolName(target.Name, target.Tool)
+	if !slices.Contains(names, name) {
+		result.Status, result.Detail = StatusFail, name+" not listed"
+
+		return result
+	}
+
+	var req mcp.CallToolRequest
+	req.Params.Name = name
+	req.Params.Arguments = target.Arguments
+
+	res, err := c.CallTool(ctx, req)
+	switch {
+	case err != nil:
+		result.Status, result.Detail = StatusFail, name+": "+err.Error()
+	case res.IsError:
+		result.Status, result.Detail = StatusFail, name+" returned an error: "+textOf(res.Content)
+	default:
+		result.Detail += ", " + name + " called"
+	}
+
+	return result
+}
+
+// checkResources lists the target's resources and reads the first one. want
+// is the number the backend lists directly, or -1 when unknown.
+func checkResources(ctx context.Context, c *client.Client, target Target, want int) Result {
+	result := Result{Feature: FeatureResources}
+
+	list, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		result.Status, result.Detail = StatusFail, "resources/list: "+err.Error()
+
+		return result
+	}
+
+	prefix := aggregator.PrefixResourceURI(target.Name, "")
+
+	var uris []string
+
+	for _, res := range list.Resources {
+		if strings.HasPrefix(res.URI, prefix) {
+			uris = append(uris, res.URI)
+		}
+	}
+
+	if len(uris) == 0 {
+		return missing(result, want)
+	}
+
+	var req mcp.ReadResourceRequest
+	req.Params.URI = uris[0]
+
+	if _, err := c.ReadResource(ctx, req); err != nil {
+		result.Status, result.Detail = StatusFail, "reading "+uris[0]+": "+err.Error()
+
+		return result
+	}
+
+	result.Status, result.Detail = StatusOK, fmt.Sprintf("%d listed, first read", len(uris))
+
+	return result
+}
+
+// checkPrompts lists the target's prompts and gets the first one. want is
+// the number the backend lists directly, or -1 when unknown.
+func checkPrompts(ctx context.Context, c *client.Client, target Target, want int) Result {
+	result := Result{Feature: FeaturePrompts}
+
+	list, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		result.Status, result.Detail = StatusFail, "prompts/list: "+err.Error()
+
+		return result
+	}
+
+	var names []string
+
+	for _, prompt := range list.Prompts {
+		if strings.HasPrefix(prompt.Name, aggregator.PrefixPromptName(target.Name, "")) {
+			names = append(names, prompt.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return missing(result, want)
+	}
+
+	var req mcp.GetPromptRequest
+	req.Params.Name = names[0]
+	req.Params.Arguments = target.PromptArguments
+
+	if _, err := c.GetPrompt(ctx, req); err != nil {
+		result.Status, result.Detail = StatusFail, "getting "+names[0]+": "+err.Error()
+
+		return result
+	}
+
+	result.Status, result.Detail = StatusOK, fmt.Sprintf("%d listed, %s fetched", len(names), names[0])
+
+	return result
+}
+
+// checkNotifications waits for the startup summary that assern sends after
+// initialization and checks that it reports the target up.
+func checkNotifications(ctx context.Context, notes *notifications, target Target) Result {
+	result := Result{Feature: FeatureNotifications}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationWait)
+	defer cancel()
+
+	summary, ok := notes.wait(ctx, func(n mcp.JSONRPCNotification) bool {
+		return n.Method == string(mcp.MethodNotificationMessage) && n.Params.AdditionalFields["logger"] == "assern"
+	})
+	if !ok {
+		result.Status, result.Detail = StatusFail, "no startup summary notification received"
+
+		return result
+	}
+
+	data, _ := summary.Params.AdditionalFields["data"].(map[string]any)
+	up, _ := data["servers_up"].([]any)
+
+	if !slices.Contains(up, any(target.Name)) {
+		result.Status, result.Detail = StatusFail, fmt.Sprintf("startup summary does not report %s up: %v", target.Name, data["message"])
+
+		return result
+	}
+
+	result.Status, result.Detail = StatusOK, "startup summary received"
+
+	return result
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// textOf joins the text content of a result, for error details.
+func textOf(content []mcp.Content) string {
+	var parts []string
+
+	for _, c := range content {
+		if text, ok := c.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,260 @@
+// Package compat checks which MCP features of real backend servers work
+// through aggregation. It runs each Target behind its own assern process,
+// started as 'assern serve --config-stdin' with the target as the only
+// server, and exercises tools, resources, prompts and notifications as a
+// client would:
+//
+//	report := compat.Run(ctx, compat.Options{Binary: exe, Targets: compat.Targets(dir)})
+//
+// Targets run npx/uvx packages that are downloaded on first use and may
+// call out to the internet, so the harness is opt-in ('assern compat test
+// --network').
+package compat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// DefaultTimeout bounds one target, including the package download on
+// first use.
+const DefaultTimeout = 2 * time.Minute
+
+// Target is a backend server to check.
+type Target struct {
+	Name        string
+	Description string
+	Server      *config.MCPServer
+
+	// Requires lists commands and environment variables ("$NAME") that must
+	// be available, else the target is skipped.
+	Requires []string
+
+	// Tool and Arguments make a harmless call checking that tool calls get
+	// through; empty only lists the tools.
+	Tool      string
+	Arguments map[string]any
+
+	// PromptArguments are passed when getting the first prompt.
+	PromptArguments map[string]string
+}
+
+// Options configures a run.
+type Options struct {
+	// Binary is the assern executable to test.
+	Binary  string
+	Targets []Target
+	// Timeout bounds each target; zero uses DefaultTimeout.
+	Timeout time.Duration
+	// LookPath and Getenv check Target.Requires; nil uses exec.LookPath and
+	// no environment.
+	LookPath func(string) (string, error)
+	Getenv   func(string) string
+}
+
+// Run checks every target in turn and returns the report.
+func Run(ctx context.Context, opts Options) *Report {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	if opts.LookPath == nil {
+		opts.LookPath = exec.LookPath
+	}
+
+	if opts.Getenv == nil {
+		opts.Getenv = func(string) string { return "" }
+	}
+
+	report := &Report{Version: version.Version}
+
+	for _, target := range opts.Targets {
+		report.Targets = append(report.Targets, runTarget(ctx, opts, target))
+	}
+
+	return report
+}
+
+// runTarget checks one target behind its own assern process.
+func runTarget(ctx context.Context, opts Options, target Target) *TargetReport {
+	tr := &TargetReport{Name: target.Name}
+
+	if missing := missingRequirements(target, opts.LookPath, opts.Getenv); len(missing) > 0 {
+		tr.Status, tr.Detail = StatusSkip, "missing "+strings.Join(missing, ", ")
+
+		return tr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { tr.Duration = time.Since(start).Round(time.Millisecond) }()
+
+	c, notes, stop, err := startAssern(ctx, opts.Binary, target)
+	if err != nil {
+		tr.Status, tr.Detail = StatusFail, err.Error()
+
+		return tr
+	}
+	defer stop()
+
+	base := backendBaseline(ctx, target)
+
+	tr.Results = check(ctx, c, target, notes, base)
+	tr.Status = worst(tr.Results)
+
+	return tr
+}
+
+// missingRequirements returns the commands and "$NAME" variables of the
+// target that are not available.
+func missingRequirements(target Target, lookPath func(string) (string, error), getenv func(string) string) []string {
+	var missing []string
+
+	for _, req := range target.Requires {
+		if name, ok := strings.CutPrefix(req, "$"); ok {
+			if getenv(name) == "" {
+				missing = append(missing, req)
+			}
+
+			continue
+		}
+
+		if _, err := lookPath(req); err != nil {
+			missing = append(missing, req)
+		}
+	}
+
+	return missing
+}
+
+// startAssern starts 'assern serve --config-stdin' with the target as its
+// only server and returns an initialized client and the notifications it
+// receives. stop ends the process.
+func startAssern(ctx context.Context, binary string, target Target) (*client.Client, *notifications, func(), error) {
+	doc, err := json.Marshal(config.MCPConfig{MCPServers: map[string]*config.MCPServer{target.Name: target.Server}})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, binary, "serve", "--config-stdin")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("creating stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("creating stdout pipe: %w", err)
+	}
+
+	// Kept to explain a failed start; read only after Wait
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, nil, fmt.Errorf("starting %s: %w", binary, err)
+	}
+
+	stop := func() {
+		_ = stdin.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+
+	fail := func(err error) (*client.Client, *notifications, func(), error) {
+		stop()
+
+		if line := lastLine(stderr.String()); line != "" {
+			err = fmt.Errorf("%w (assern: %s)", err, line)
+		}
+
+		return nil, nil, nil, err
+	}
+
+	if _, err := stdin.Write(append(doc, '\n')); err != nil {
+		return fail(fmt.Errorf("sending config: %w", err))
+	}
+
+	c := client.NewClient(transport.NewIO(stdout, stdin, nil))
+
+	notes := &notifications{}
+	c.OnNotification(notes.add)
+
+	if err := c.Start(ctx); err != nil {
+		return fail(fmt.Errorf("starting client: %w", err))
+	}
+
+	var req mcp.InitializeRequest
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "assern-compat", Version: version.Version}
+
+	if _, err := c.Initialize(ctx, req); err != nil {
+		return fail(fmt.Errorf("initializing: %w", err))
+	}
+
+	return c, notes, func() { _ = c.Close(); stop() }, nil
+}
+
+// notifications records the notifications a client receives.
+type notifications struct {
+	mu      sync.Mutex
+	seen    []mcp.JSONRPCNotification
+	changed chan struct{}
+}
+
+func (n *notifications) add(notification mcp.JSONRPCNotification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.seen = append(n.seen, notification)
+
+	if n.changed != nil {
+		close(n.changed)
+		n.changed = nil
+	}
+}
+
+// wait returns the first notification matching match, waiting for it until
+// ctx is done.
+func (n *notifications) wait(ctx context.Context, match func(mcp.JSONRPCNotification) bool) (mcp.JSONRPCNotification, bool) {
+	for {
+		n.mu.Lock()
+
+		for _, notification := range n.seen {
+			if match(notification) {
+				n.mu.Unlock()
+
+				return notification, true
+			}
+		}
+
+		if n.changed == nil {
+			n.changed = make(chan struct{})
+		}
+
+		changed := n.changed
+		n.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return mcp.JSONRPCNotification{}, false
+		}
+	}
+}
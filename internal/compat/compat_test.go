@@ -0,0 +1,312 @@
+package compat
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// aggregatedServer stands in for assern serving one backend "demo": its tools,
+// resources and prompts are already prefixed.
+func aggregatedServer(t *testing.T, failTool bool) *client.Client {
+	t.Helper()
+
+	s := server.NewMCPServer("assern", "test", server.WithResourceCapabilities(false, false), server.WithPromptCapabilities(false))
+	s.AddTool(mcp.NewTool("demo_echo"), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if failTool {
+			return mcp.NewToolResultError("boom"), nil
+		}
+
+		return mcp.NewToolResultText(req.GetString("message", "")), nil
+	})
+	s.AddTool(mcp.NewTool("other_echo"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("other"), nil
+	})
+	s.AddResource(mcp.NewResource("assern://demo/file:///readme", "readme"), func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: "assern://demo/file:///readme", Text: "hi"}}, nil
+	})
+	s.AddPrompt(mcp.NewPrompt("other_greet"), func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("greet", nil), nil
+	})
+
+	c, err := client.NewInProcessClient(s)
+	if err != nil {
+		t.Fatalf("NewInProcessClient: %v", err)
+	}
+
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	var req mcp.InitializeRequest
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+
+	if _, err := c.Initialize(t.Context(), req); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	return c
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	target := Target{Name: "demo", Tool: "echo", Arguments: map[string]any{"message": "hi"}}
+
+	tests := []struct {
+		name     string
+		failTool bool
+		target   Target
+		// backendResources is the number of resources listed directly
+		backendResources int
+		want             map[string]string
+	}{
+		{
+			name:   "all supported",
+			target: target,
+			want:   map[string]string{FeatureTools: StatusOK, FeatureResources: StatusOK, FeaturePrompts: StatusNone},
+		},
+		{
+			name:     "tool call fails",
+			failTool: true,
+			target:   target,
+			want:     map[string]string{FeatureTools: StatusFail, FeatureResources: StatusOK, FeaturePrompts: StatusNone},
+		},
+		{
+			name:   "probe tool not listed",
+			target: Target{Name: "demo", Tool: "missing"},
+			want:   map[string]string{FeatureTools: StatusFail},
+		},
+		{
+			name:   "no tools of the server",
+			target: Target{Name: "absent"},
+			want:   map[string]string{FeatureTools: StatusFail, FeatureResources: StatusNone, FeaturePrompts: StatusNone},
+		},
+		{
+			name:             "resources lost in aggregation",
+			target:           Target{Name: "absent"},
+			backendResources: 2,
+			want:             map[string]string{FeatureResources: StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := aggregatedServer(t, tt.failTool)
+
+			results := []Result{
+				checkTools(t.Context(), c, tt.target),
+				checkResources(t.Context(), c, tt.target, tt.backendResources),
+				checkPrompts(t.Context(), c, tt.target, -1),
+			}
+
+			report := &TargetReport{Results: results}
+			for feature, want := range tt.want {
+				if got := report.Result(feature); got.Status != want {
+					t.Errorf("%s = %s (%s), want %s", feature, got.Status, got.Detail, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckNotifications(t *testing.T) {
+	t.Parallel()
+
+	summary := func(up ...any) mcp.JSONRPCNotification {
+		var n mcp.JSONRPCNotification
+		n.Method = string(mcp.MethodNotificationMessage)
+		n.Params.AdditionalFields = map[string]any{
+			"logger": "assern",
+			"data":   map[string]any{"servers_up": up, "message": "assern: summary"},
+		}
+
+		return n
+	}
+
+	tests := []struct {
+		name  string
+		notes []mcp.JSONRPCNotification
+		want  string
+	}{
+		{name: "target up", notes: []mcp.JSONRPCNotification{summary("demo")}, want: StatusOK},
+		{name: "target down", notes: []mcp.JSONRPCNotification{summary()}, want: StatusFail},
+		{name: "no summary", want: StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			notes := &notifications{}
+
+			go func() {
+				for _, n := range tt.notes {
+					time.Sleep(10 * time.Millisecond)
+					notes.add(n)
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+			defer cancel()
+
+			if got := checkNotifications(ctx, notes, Target{Name: "demo"}); got.Status != tt.want {
+				t.Errorf("checkNotifications() = %s (%s), want %s", got.Status, got.Detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSkipsMissingRequirements(t *testing.T) {
+	t.Parallel()
+
+	lookPath := func(name string) (string, error) {
+		if name == "npx" {
+			return "/usr/bin/npx", nil
+		}
+
+		return "", errors.New("not found")
+	}
+
+	report := Run(t.Context(), Options{
+		Binary:   "/nonexistent/assern",
+		Targets:  Targets(t.TempDir()),
+		LookPath: lookPath,
+		Getenv:   func(string) string { return "" },
+	})
+
+	got := make(map[string]string)
+	for _, tr := range report.Targets {
+		got[tr.Name] = tr.Status + " " + tr.Detail
+	}
+
+	// npx targets run (and fail on the missing binary); the others are skipped
+	want := map[string]string{
+		"fetch":  StatusSkip + " missing uvx",
+		"github": StatusSkip + " missing $GITHUB_TOKEN",
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s = %q, want %q", name, got[name], w)
+		}
+	}
+
+	if got := report.Targets[0]; got.Status != StatusFail {
+		t.Errorf("%s = %s, want fail for the missing binary", got.Name, got.Status)
+	}
+
+	if !report.Failed() {
+		t.Error("Failed() = false, want true")
+	}
+}
+
+// envHelper makes the test binary serve as a backend for TestRun.
+const envHelper = "ASSERN_COMPAT_HELPER"
+
+// TestHelperProcess is not a real test: started by TestRun with envHelper
+// set, it serves an echo tool, a resource and a prompt over stdio.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(envHelper) != "1" {
+		t.Skip("helper process for TestRun")
+	}
+
+	s := server.NewMCPServer("helper", "1.0.0", server.WithResourceCapabilities(false, false), server.WithPromptCapabilities(false))
+	s.AddTool(mcp.NewTool("echo"), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(req.GetString("message", "")), nil
+	})
+	s.AddResource(mcp.NewResource("file:///readme", "readme"), func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: "file:///readme", Text: "hi"}}, nil
+	})
+	s.AddPrompt(mcp.NewPrompt("greet"), func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("greet", []mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("hello"))}), nil
+	})
+
+	_ = server.ServeStdio(s)
+
+	os.Exit(0)
+}
+
+// buildAssern builds the assern binary into a temporary directory.
+func buildAssern(t *testing.T) string {
+	t.Helper()
+
+	binary := filepath.Join(t.TempDir(), "assern")
+
+	build := exec.CommandContext(t.Context(), "go", "build", "-o", binary, "github.com/valksor/go-assern/cmd/assern")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building assern: %v\n%s", err, out)
+	}
+
+	return binary
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds assern")
+	}
+
+	target := Target{
+		Name: "helper",
+		Server: &config.MCPServer{
+			Command: os.Args[0],
+			Args:    []string{"-test.run=^TestHelperProcess$"},
+			Env:     map[string]string{envHelper: "1"},
+		},
+		Tool:      "echo",
+		Arguments: map[string]any{"message": "hi"},
+	}
+
+	report := Run(t.Context(), Options{Binary: buildAssern(t), Targets: []Target{target}, Timeout: time.Minute})
+
+	tr := report.Targets[0]
+	if tr.Status != StatusOK {
+		t.Fatalf("status = %s (%s), results %+v", tr.Status, tr.Detail, tr.Results)
+	}
+
+	for _, feature := range Features {
+		if got := tr.Result(feature); got.Status != StatusOK {
+			t.Errorf("%s = %s (%s), want ok", feature, got.Status, got.Detail)
+		}
+	}
+}
+
+// TestRunNetwork runs the real matrix. It downloads the servers, so it only
+// runs with ASSERN_COMPAT_NETWORK=1.
+func TestRunNetwork(t *testing.T) {
+	if os.Getenv("ASSERN_COMPAT_NETWORK") == "" {
+		t.Skip("set ASSERN_COMPAT_NETWORK=1 to run against real MCP servers")
+	}
+
+	report := Run(t.Context(), Options{Binary: buildAssern(t), Targets: Targets(t.TempDir()), Getenv: os.Getenv})
+
+	for _, tr := range report.Targets {
+		t.Logf("%s: %s %s", tr.Name, tr.Status, tr.Detail)
+
+		for _, r := range tr.Results {
+			t.Logf("  %s: %s %s", r.Feature, r.Status, r.Detail)
+		}
+	}
+
+	if report.Failed() {
+		t.Error("compatibility checks failed")
+	}
+
+	if !slices.ContainsFunc(report.Targets, func(tr *TargetReport) bool { return tr.Status == StatusOK }) {
+		t.Error("no target ran")
+	}
+}
@@ -0,0 +1,62 @@
+package compat
+
+import (
+	"slices"
+	"time"
+)
+
+// Check outcomes.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+	StatusNone = "none" // The server offers nothing of this kind
+	StatusSkip = "skip" // The target or check could not run
+)
+
+// Result is the outcome of one feature check.
+type Result struct {
+	Feature string `json:"feature"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// TargetReport holds the results of one target.
+type TargetReport struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"` // StatusSkip when the target did not run, else the worst result
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Results  []Result      `json:"results,omitempty"`
+}
+
+// Result returns the result of feature, or a skipped result when it was not
+// checked.
+func (r *TargetReport) Result(feature string) Result {
+	for _, result := range r.Results {
+		if result.Feature == feature {
+			return result
+		}
+	}
+
+	return Result{Feature: feature, Status: StatusSkip}
+}
+
+// Report is the outcome of a compatibility run.
+type Report struct {
+	Version string          `json:"version"`
+	Targets []*TargetReport `json:"targets"`
+}
+
+// Failed reports whether any check failed.
+func (r *Report) Failed() bool {
+	return slices.ContainsFunc(r.Targets, func(t *TargetReport) bool { return t.Status == StatusFail })
+}
+
+// worst returns the most severe status of results: fail, then ok.
+func worst(results []Result) string {
+	if slices.ContainsFunc(results, func(r Result) bool { return r.Status == StatusFail }) {
+		return StatusFail
+	}
+
+	return StatusOK
+}
@@ -0,0 +1,49 @@
+package compat
+
+import (
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Targets returns the curated set of popular MCP servers. dir is the
+// directory the filesystem server is allowed to read.
+func Targets(dir string) []Target {
+	return []Target{
+		{
+			Name:        "everything",
+			Description: "MCP reference server exercising every protocol feature",
+			Server:      &config.MCPServer{Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-everything"}},
+			Requires:    []string{"npx"},
+			Tool:        "echo",
+			Arguments:   map[string]any{"message": "assern compat"},
+		},
+		{
+			Name:        "filesystem",
+			Description: "Reference filesystem server, restricted to one directory",
+			Server:      &config.MCPServer{Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-filesystem", dir}},
+			Requires:    []string{"npx"},
+			Tool:        "list_directory",
+			Arguments:   map[string]any{"path": dir},
+		},
+		{
+			Name:            "fetch",
+			Description:     "Reference fetch server (Python), retrieving example.com",
+			Server:          &config.MCPServer{Command: "uvx", Args: []string{"mcp-server-fetch"}},
+			Requires:        []string{"uvx"},
+			Tool:            "fetch",
+			Arguments:       map[string]any{"url": "https://example.com", "max_length": 1000},
+			PromptArguments: map[string]string{"url": "https://example.com"},
+		},
+		{
+			Name:        "github",
+			Description: "GitHub server, authenticated with $GITHUB_TOKEN",
+			Server: &config.MCPServer{
+				Command: "npx",
+				Args:    []string{"-y", "@modelcontextprotocol/server-github"},
+				Env:     map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_TOKEN}"},
+			},
+			Requires:  []string{"npx", "$GITHUB_TOKEN"},
+			Tool:      "search_repositories",
+			Arguments: map[string]any{"query": "repo:modelcontextprotocol/servers", "perPage": 1},
+		},
+	}
+}
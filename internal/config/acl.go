@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// ACLConfig restricts HTTP clients to what their bearer token may call, for
+// a shared instance serving a team (see Settings.Listen). It is set as
+// settings.acl in config.yaml or in a separate acl.yaml next to it. With
+// tokens configured, HTTP requests without a known token are rejected;
+// stdio and socket clients are local and not restricted.
+type ACLConfig struct {
+	Tokens []ACLToken `yaml:"tokens,omitempty"`
+}
+
+// ACLToken is one bearer token and what it may call.
+type ACLToken struct {
+	// Name identifies the token's holder in logs and audit records.
+	Name string `yaml:"name"`
+	// Token is the bearer token; ${VAR} references are expanded from the
+	// environment assern loaded (.env files included).
+	Token string `yaml:"token"`
+	// Servers lists the backend servers the token may use (their tools,
+	// resources and prompts), as globs over server names. Empty allows
+	// every server.
+	Servers []string `yaml:"servers,omitempty"`
+	// Tools lists the tools the token may call, as entries of the allowed
+	// list of a server (see ToolRules): "server:" qualifiers and "!"
+	// exclusions included. Empty allows every tool of the allowed servers.
+	Tools []string `yaml:"tools,omitempty"`
+}
+
+// IsEnabled reports whether any token is configured.
+func (a *ACLConfig) IsEnabled() bool {
+	return a != nil && len(a.Tokens) > 0
+}
+
+// Lookup returns the token named name, or nil.
+func (a *ACLConfig) Lookup(name string) *ACLToken {
+	if a == nil {
+		return nil
+	}
+
+	for i := range a.Tokens {
+		if a.Tokens[i].Name == name {
+			return &a.Tokens[i]
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether the token may call tool (unprefixed) of server.
+// Assern's own tools have no server and are always allowed.
+func (t *ACLToken) Allows(server, tool string) bool {
+	if t == nil {
+		return false
+	}
+
+	if server == "" {
+		return true
+	}
+
+	if !t.AllowsServer(server) {
+		return false
+	}
+
+	return len(t.Tools) == 0 || matchToolList(t.Tools, server, tool, true)
+}
+
+// AllowsServer reports whether the token may use server at all: list and
+// read its resources and get its prompts. Assern's own resources have no
+// server and are always allowed.
+func (t *ACLToken) AllowsServer(server string) bool {
+	if t == nil {
+		return false
+	}
+
+	return server == "" || len(t.Servers) == 0 || slices.ContainsFunc(t.Servers, func(pattern string) bool {
+		return MatchGlob(pattern, server)
+	})
+}
+
+// Clone creates a deep copy of the ACL config.
+func (a *ACLConfig) Clone() *ACLConfig {
+	if a == nil {
+		return nil
+	}
+
+	clone := &ACLConfig{Tokens: make([]ACLToken, 0, len(a.Tokens))}
+
+	for _, t := range a.Tokens {
+		clone.Tokens = append(clone.Tokens, ACLToken{
+			Name:    t.Name,
+			Token:   t.Token,
+			Servers: slices.Clone(t.Servers),
+			Tools:   slices.Clone(t.Tools),
+		})
+	}
+
+	return clone
+}
+
+// ValidateACL checks that every token has a unique name and a value.
+func ValidateACL(a *ACLConfig) error {
+	if a == nil {
+		return nil
+	}
+
+	names := make(map[string]bool, len(a.Tokens))
+
+	for i, t := range a.Tokens {
+		switch {
+		case t.Name == "":
+			return fmt.Errorf("tokens[%d]: name is required", i)
+		case t.Token == "":
+			return fmt.Errorf("tokens[%d] (%s): token is required", i, t.Name)
+		case names[t.Name]:
+			return fmt.Errorf("tokens[%d]: duplicate name %q", i, t.Name)
+		}
+
+		names[t.Name] = true
+	}
+
+	return nil
+}
+
+// LoadACL reads an acl.yaml file: an ACLConfig at the top level.
+func LoadACL(path string, opts LoadOptions) (*ACLConfig, error) {
+	return loadACL(path, opts.Strict)
+}
+
+// loadACL is LoadACL, rejecting unknown keys if strict.
+func loadACL(path string, strict bool) (*ACLConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading acl file: %w", err)
+	}
+
+	var acl ACLConfig
+
+	if err := decodeYAML(data, &acl); err != nil {
+		return nil, fmt.Errorf("parsing acl file: %w", err)
+	}
+
+	if strict {
+		if err := checkKnownFields(data, &acl, "yaml"); err != nil {
+			return nil, fmt.Errorf("parsing acl file: %w", err)
+		}
+	}
+
+	if err := ValidateACL(&acl); err != nil {
+		return nil, fmt.Errorf("acl file: %w", err)
+	}
+
+	return &acl, nil
+}
+
+// errACLTwice reports an ACL configured both in config.yaml and acl.yaml.
+var errACLTwice = errors.New("settings.acl is set in config.yaml and acl.yaml exists; configure the ACL in one place")
+
+// loadGlobalACL adds the global acl.yaml, when it exists, to globalConfig as
+// its settings.acl, creating the config if there is none.
+func loadGlobalACL(globalConfig *Config, strict bool) (*Config, error) {
+	path, err := GlobalACLPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global acl path: %w", err)
+	}
+
+	if !FileExists(path) {
+		return globalConfig, nil
+	}
+
+	acl, err := loadACL(path, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	if globalConfig == nil {
+		globalConfig = NewConfig()
+	}
+
+	if globalConfig.Settings.ACL != nil {
+		return nil, errACLTwice
+	}
+
+	globalConfig.Settings.ACL = acl
+
+	return globalConfig, nil
+}
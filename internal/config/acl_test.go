@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestACLTokenAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		token  *ACLToken
+		server string
+		tool   string
+		want   bool
+	}{
+		{name: "nil token", token: nil, server: "github", tool: "search", want: false},
+		{name: "no restrictions", token: &ACLToken{}, server: "github", tool: "delete_repo", want: true},
+		{name: "own tools", token: &ACLToken{Servers: []string{"jira"}}, server: "", tool: "assern_status", want: true},
+		{name: "server allowed", token: &ACLToken{Servers: []string{"git*"}}, server: "github", tool: "search", want: true},
+		{name: "server not allowed", token: &ACLToken{Servers: []string{"jira"}}, server: "github", tool: "search", want: false},
+		{name: "tool allowed", token: &ACLToken{Tools: []string{"search_*"}}, server: "github", tool: "search_code", want: true},
+		{name: "tool not allowed", token: &ACLToken{Tools: []string{"search_*"}}, server: "github", tool: "delete_repo", want: false},
+		{name: "tool excluded", token: &ACLToken{Tools: []string{"!delete_*"}}, server: "github", tool: "delete_repo", want: false},
+		{name: "qualified tool", token: &ACLToken{Tools: []string{"jira:*"}}, server: "github", tool: "search", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.token.Allows(tt.server, tt.tool); got != tt.want {
+				t.Errorf("Allows(%q, %q) = %v, want %v", tt.server, tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestACLTokenAllowsServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		token  *ACLToken
+		server string
+		want   bool
+	}{
+		{name: "nil token", token: nil, server: "github", want: false},
+		{name: "no restrictions", token: &ACLToken{}, server: "github", want: true},
+		{name: "own resources", token: &ACLToken{Servers: []string{"jira"}}, server: "", want: true},
+		{name: "server allowed", token: &ACLToken{Servers: []string{"git*"}}, server: "github", want: true},
+		{name: "server not allowed", token: &ACLToken{Servers: []string{"jira"}}, server: "github", want: false},
+		{name: "tools do not restrict", token: &ACLToken{Tools: []string{"search_*"}}, server: "github", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.token.AllowsServer(tt.server); got != tt.want {
+				t.Errorf("AllowsServer(%q) = %v, want %v", tt.server, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateACL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		acl     *ACLConfig
+		wantErr bool
+	}{
+		{name: "nil", acl: nil},
+		{name: "valid", acl: &ACLConfig{Tokens: []ACLToken{{Name: "alice", Token: "a"}, {Name: "bob", Token: "b"}}}},
+		{name: "no name", acl: &ACLConfig{Tokens: []ACLToken{{Token: "a"}}}, wantErr: true},
+		{name: "no token", acl: &ACLConfig{Tokens: []ACLToken{{Name: "alice"}}}, wantErr: true},
+		{name: "duplicate", acl: &ACLConfig{Tokens: []ACLToken{{Name: "alice", Token: "a"}, {Name: "alice", Token: "b"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ValidateACL(tt.acl); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateACL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadEffectiveACLFile(t *testing.T) {
+	home := mockHomeDir(t)
+
+	dir := filepath.Join(home, ".valksor", "assern")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	acl := "tokens:\n  - name: alice\n    token: ${ALICE_TOKEN}\n    servers: [github]\n"
+	if err := os.WriteFile(filepath.Join(dir, "acl.yaml"), []byte(acl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadEffective(home, "", LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadEffective() error = %v", err)
+	}
+
+	token := cfg.Settings.ACL.Lookup("alice")
+	if token == nil || token.Token != "${ALICE_TOKEN}" || len(token.Servers) != 1 {
+		t.Fatalf("settings.acl = %+v, want the token of acl.yaml", cfg.Settings.ACL)
+	}
+
+	// The ACL is configured in one place only
+	config := "settings:\n  acl:\n    tokens:\n      - name: bob\n        token: b\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadEffective(home, "", LoadOptions{}); !errors.Is(err, errACLTwice) {
+		t.Errorf("LoadEffective() error = %v, want errACLTwice", err)
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// How an audit log records tool call arguments.
+const (
+	// AuditArgumentsHash records a SHA-256 of the arguments (default), so
+	// identical calls can be matched without storing their content.
+	AuditArgumentsHash = "hash"
+	// AuditArgumentsFull records the arguments, minus Redact keys.
+	AuditArgumentsFull = "full"
+	// AuditArgumentsNone records no arguments.
+	AuditArgumentsNone = "none"
+)
+
+// AuditLogConfig enables the tool call audit log: one JSON line per
+// tools/call with the time, client session, tool, backend server,
+// arguments, duration and outcome.
+type AuditLogConfig struct {
+	// Path is the file records are appended to; "~" is expanded. Logging
+	// is off while it is empty.
+	Path string `yaml:"path,omitempty"`
+	// Arguments is AuditArgumentsHash (default), AuditArgumentsFull or
+	// AuditArgumentsNone.
+	Arguments string `yaml:"arguments,omitempty"`
+	// Redact lists argument names whose values are replaced by
+	// "<redacted>" in full mode, matched case-insensitively at any depth.
+	Redact []string `yaml:"redact,omitempty"`
+}
+
+// IsEnabled reports whether an audit log path is configured.
+func (a *AuditLogConfig) IsEnabled() bool {
+	return a != nil && a.Path != ""
+}
+
+// EffectivePath returns Path with "~" expanded.
+func (a *AuditLogConfig) EffectivePath() string {
+	if a == nil {
+		return ""
+	}
+
+	return ExpandPath(a.Path)
+}
+
+// EffectiveArguments returns the argument mode, applying the default.
+func (a *AuditLogConfig) EffectiveArguments() string {
+	if a == nil || a.Arguments == "" {
+		return AuditArgumentsHash
+	}
+
+	return a.Arguments
+}
+
+// Clone creates a deep copy of the audit log config.
+func (a *AuditLogConfig) Clone() *AuditLogConfig {
+	if a == nil {
+		return nil
+	}
+
+	return &AuditLogConfig{
+		Path:      a.Path,
+		Arguments: a.Arguments,
+		Redact:    slices.Clone(a.Redact),
+	}
+}
+
+// ValidateAuditLog checks the argument mode of an audit log config.
+func ValidateAuditLog(a *AuditLogConfig) error {
+	if a == nil {
+		return nil
+	}
+
+	switch a.Arguments {
+	case "", AuditArgumentsHash, AuditArgumentsFull, AuditArgumentsNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid arguments mode %q (use %s, %s or %s)",
+			a.Arguments, AuditArgumentsHash, AuditArgumentsFull, AuditArgumentsNone)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAuditLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		wantMode string
+		wantErr  bool
+	}{
+		{name: "default mode", yaml: "path: /tmp/audit.jsonl", wantMode: AuditArgumentsHash},
+		{name: "full", yaml: "path: /tmp/audit.jsonl\n    arguments: full", wantMode: AuditArgumentsFull},
+		{name: "none", yaml: "path: /tmp/audit.jsonl\n    arguments: none", wantMode: AuditArgumentsNone},
+		{name: "invalid mode", yaml: "path: /tmp/audit.jsonl\n    arguments: partial", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse([]byte("settings:\n  audit_log:\n    "+tt.yaml+"\n"), LoadOptions{})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "settings.audit_log") {
+					t.Fatalf("Parse error = %v, want settings.audit_log error", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			if !cfg.Settings.AuditLog.IsEnabled() {
+				t.Error("audit log not enabled")
+			}
+
+			if got := cfg.Settings.AuditLog.EffectiveArguments(); got != tt.wantMode {
+				t.Errorf("EffectiveArguments() = %q, want %q", got, tt.wantMode)
+			}
+		})
+	}
+}
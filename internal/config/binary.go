@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Binary content modes (see BinaryContentConfig).
+const (
+	// BinaryKeep passes binary content through unchanged (default).
+	BinaryKeep = "keep"
+	// BinaryStrip replaces binary content with a short text note.
+	BinaryStrip = "strip"
+	// BinaryThumbnail shrinks images to a thumbnail linked to the original;
+	// other binary content is handled as BinaryReference.
+	BinaryThumbnail = "thumbnail"
+	// BinaryReference replaces binary content with a link to an
+	// assern://blob/<id> resource that clients read on demand.
+	BinaryReference = "reference"
+)
+
+const (
+	// DefaultThumbnailMaxDimension is the longest side of a thumbnail, in
+	// pixels.
+	DefaultThumbnailMaxDimension = 256
+	// DefaultThumbnailMaxSize is the largest thumbnail inlined in a result;
+	// larger ones are replaced by a reference.
+	DefaultThumbnailMaxSize ByteSize = 64 << 10
+)
+
+// BinaryContentConfig controls how the image, audio and embedded blob
+// content of a server's tool results reaches clients, so large base64
+// payloads do not fill the agent's context.
+type BinaryContentConfig struct {
+	// Mode applies to every tool of the server: "keep" (default), "strip",
+	// "thumbnail" or "reference".
+	Mode string `yaml:"mode,omitempty"`
+	// Tools overrides Mode for single tools, keyed by unprefixed name.
+	Tools map[string]string `yaml:"tools,omitempty"`
+	// MinSize leaves content up to this decoded size unchanged.
+	MinSize ByteSize `yaml:"min_size,omitempty"`
+	// ThumbnailMaxDimension is the longest side of thumbnails in pixels.
+	// Zero uses DefaultThumbnailMaxDimension.
+	ThumbnailMaxDimension int `yaml:"thumbnail_max_dimension,omitempty"`
+	// ThumbnailMaxSize replaces thumbnails larger than this by a
+	// reference. Zero uses DefaultThumbnailMaxSize.
+	ThumbnailMaxSize ByteSize `yaml:"thumbnail_max_size,omitempty"`
+}
+
+// BinaryMode returns the binary content mode of calls to tool (its
+// unprefixed name): its entry in binary_content.tools, else the server's
+// binary_content.mode, else BinaryKeep. s may be nil.
+func (s *ServerConfig) BinaryMode(tool string) string {
+	if s == nil || s.BinaryContent == nil {
+		return BinaryKeep
+	}
+
+	if mode := s.BinaryContent.Tools[tool]; mode != "" {
+		return mode
+	}
+
+	if s.BinaryContent.Mode != "" {
+		return s.BinaryContent.Mode
+	}
+
+	return BinaryKeep
+}
+
+// EffectiveThumbnailMaxDimension returns the longest side of thumbnails,
+// applying the default.
+func (c *BinaryContentConfig) EffectiveThumbnailMaxDimension() int {
+	if c == nil || c.ThumbnailMaxDimension <= 0 {
+		return DefaultThumbnailMaxDimension
+	}
+
+	return c.ThumbnailMaxDimension
+}
+
+// EffectiveThumbnailMaxSize returns the largest inlined thumbnail, applying
+// the default.
+func (c *BinaryContentConfig) EffectiveThumbnailMaxSize() ByteSize {
+	if c == nil || c.ThumbnailMaxSize <= 0 {
+		return DefaultThumbnailMaxSize
+	}
+
+	return c.ThumbnailMaxSize
+}
+
+// Clone creates a deep copy of the binary content config.
+func (c *BinaryContentConfig) Clone() *BinaryContentConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+	clone.Tools = maps.Clone(c.Tools)
+
+	return &clone
+}
+
+// Equal compares two binary content configs for equality.
+func (c *BinaryContentConfig) Equal(other *BinaryContentConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+
+	return c.Mode == other.Mode &&
+		c.MinSize == other.MinSize &&
+		c.ThumbnailMaxDimension == other.ThumbnailMaxDimension &&
+		c.ThumbnailMaxSize == other.ThumbnailMaxSize &&
+		maps.Equal(c.Tools, other.Tools)
+}
+
+// validateBinaryContent checks the binary_content modes of servers defined
+// under path.
+func validateBinaryContent(path string, servers map[string]*ServerConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil || srv.BinaryContent == nil {
+			continue
+		}
+
+		if err := validBinaryMode(srv.BinaryContent.Mode); err != nil {
+			return fmt.Errorf("%s.%s.binary_content.mode: %w", path, name, err)
+		}
+
+		for _, tool := range slices.Sorted(maps.Keys(srv.BinaryContent.Tools)) {
+			if err := validBinaryMode(srv.BinaryContent.Tools[tool]); err != nil {
+				return fmt.Errorf("%s.%s.binary_content.tools.%s: %w", path, name, tool, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validBinaryMode(mode string) error {
+	switch mode {
+	case "", BinaryKeep, BinaryStrip, BinaryThumbnail, BinaryReference:
+		return nil
+	default:
+		return fmt.Errorf("unknown mode %q (want %s, %s, %s or %s)", mode, BinaryKeep, BinaryStrip, BinaryThumbnail, BinaryReference)
+	}
+}
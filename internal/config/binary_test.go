@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServerConfigBinaryMode(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ServerConfig{BinaryContent: &BinaryContentConfig{
+		Mode:  BinaryReference,
+		Tools: map[string]string{"screenshot": BinaryThumbnail},
+	}}
+
+	tests := []struct {
+		name string
+		cfg  *ServerConfig
+		tool string
+		want string
+	}{
+		{name: "nil config", cfg: nil, tool: "screenshot", want: BinaryKeep},
+		{name: "not configured", cfg: &ServerConfig{}, tool: "screenshot", want: BinaryKeep},
+		{name: "no server mode", cfg: &ServerConfig{BinaryContent: &BinaryContentConfig{}}, tool: "x", want: BinaryKeep},
+		{name: "server mode", cfg: cfg, tool: "download", want: BinaryReference},
+		{name: "tool override", cfg: cfg, tool: "screenshot", want: BinaryThumbnail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.cfg.BinaryMode(tt.tool); got != tt.want {
+				t.Errorf("BinaryMode(%q) = %q, want %q", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBinaryContent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: "projects:\n  work:\n    servers:\n      browser:\n        binary_content:\n          mode: reference\n          min_size: 16KB\n          tools:\n            screenshot: thumbnail\n",
+		},
+		{
+			name:    "unknown mode",
+			data:    "projects:\n  work:\n    servers:\n      browser:\n        binary_content:\n          mode: shrink\n",
+			wantErr: `projects.work.servers.browser.binary_content.mode: unknown mode "shrink"`,
+		},
+		{
+			name:    "unknown tool mode",
+			data:    "projects:\n  work:\n    servers:\n      browser:\n        binary_content:\n          tools:\n            screenshot: tiny\n",
+			wantErr: `binary_content.tools.screenshot: unknown mode "tiny"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
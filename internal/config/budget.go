@@ -0,0 +1,53 @@
+package config
+
+import "fmt"
+
+// ToolBudget trims the tool definitions of a server, so more servers fit
+// in a client's context.
+type ToolBudget struct {
+	// MaxDescriptionLength cuts longer descriptions to that many
+	// characters, ending them with "…" (0 = no limit)
+	MaxDescriptionLength int
+	// StripSchemaExamples drops the "examples" and "example" keywords from
+	// input schemas
+	StripSchemaExamples bool
+}
+
+// ToolBudget returns the budget settings.max_description_length and
+// settings.strip_schema_examples give every server.
+func (s *Settings) ToolBudget() ToolBudget {
+	if s == nil {
+		return ToolBudget{}
+	}
+
+	return ToolBudget{MaxDescriptionLength: s.MaxDescriptionLength, StripSchemaExamples: s.StripSchemaExamples}
+}
+
+// ToolBudget returns the budget of the server's tools: its own
+// max_description_length replaces that of base (negative lifts it), and its
+// strip_schema_examples adds to that of base.
+func (s *ServerConfig) ToolBudget(base ToolBudget) ToolBudget {
+	if s == nil {
+		return base
+	}
+
+	switch {
+	case s.MaxDescriptionLength < 0:
+		base.MaxDescriptionLength = 0
+	case s.MaxDescriptionLength > 0:
+		base.MaxDescriptionLength = s.MaxDescriptionLength
+	}
+
+	base.StripSchemaExamples = base.StripSchemaExamples || s.StripSchemaExamples
+
+	return base
+}
+
+// ValidateMaxDescriptionLength checks settings.max_description_length.
+func ValidateMaxDescriptionLength(length int) error {
+	if length < 0 {
+		return fmt.Errorf("%d is negative (0 means no limit)", length)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServerToolBudget(t *testing.T) {
+	t.Parallel()
+
+	settings := &Settings{MaxDescriptionLength: 200}
+
+	tests := []struct {
+		name   string
+		server *ServerConfig
+		want   ToolBudget
+	}{
+		{name: "no server", want: ToolBudget{MaxDescriptionLength: 200}},
+		{name: "settings apply", server: &ServerConfig{}, want: ToolBudget{MaxDescriptionLength: 200}},
+		{name: "server limit", server: &ServerConfig{MaxDescriptionLength: 80}, want: ToolBudget{MaxDescriptionLength: 80}},
+		{name: "limit lifted", server: &ServerConfig{MaxDescriptionLength: -1}, want: ToolBudget{}},
+		{
+			name:   "examples stripped",
+			server: &ServerConfig{StripSchemaExamples: true},
+			want:   ToolBudget{MaxDescriptionLength: 200, StripSchemaExamples: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.server.ToolBudget(settings.ToolBudget()); got != tt.want {
+				t.Errorf("ToolBudget() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMaxDescriptionLength(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Parse([]byte("settings:\n  max_description_length: 120\n  strip_schema_examples: true\n"+
+		"projects:\n  work:\n    servers:\n      github:\n        max_description_length: -1\n"+
+		"        tool_descriptions:\n          search_repos: Find repositories\n"), LoadOptions{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := cfg.Settings.ToolBudget(); got != (ToolBudget{MaxDescriptionLength: 120, StripSchemaExamples: true}) {
+		t.Errorf("settings ToolBudget() = %+v", got)
+	}
+
+	if got := cfg.Projects["work"].Servers["github"]; got.MaxDescriptionLength != -1 || got.ToolDescriptions["search_repos"] != "Find repositories" {
+		t.Errorf("github = %+v", got)
+	}
+
+	_, err = Parse([]byte("settings:\n  max_description_length: -5\n"), LoadOptions{})
+	if err == nil || !strings.Contains(err.Error(), "settings.max_description_length") {
+		t.Errorf("Parse() error = %v, want a settings.max_description_length error", err)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// CallTimeoutConfig bounds how long a tool call may wait on its backend,
+// telling a silent hang from a long operation: progress notifications the
+// backend sends for the call count as signs of life, so a call that keeps
+// reporting progress runs on, up to Max, while one that goes quiet fails
+// after Idle.
+type CallTimeoutConfig struct {
+	// Idle is how long a call may go without a response or a progress
+	// notification; each notification starts it again. Zero disables it.
+	Idle time.Duration `yaml:"idle,omitempty"`
+	// Max caps a call however much progress it reports. Zero means no cap.
+	Max time.Duration `yaml:"max,omitempty"`
+}
+
+// EffectiveCallTimeout returns the call timeout of a server: its own, or the
+// settings.call_timeout default. It returns nil when calls to the server are
+// not timed out. s and settings may be nil.
+func EffectiveCallTimeout(s *ServerConfig, settings *Settings) *CallTimeoutConfig {
+	switch {
+	case s != nil && s.CallTimeout != nil:
+		return s.CallTimeout.enabled()
+	case settings != nil:
+		return settings.CallTimeout.enabled()
+	default:
+		return nil
+	}
+}
+
+// enabled returns c, or nil when it sets no limit.
+func (c *CallTimeoutConfig) enabled() *CallTimeoutConfig {
+	if c == nil || (c.Idle <= 0 && c.Max <= 0) {
+		return nil
+	}
+
+	return c
+}
+
+// Clone creates a copy of the call timeout config.
+func (c *CallTimeoutConfig) Clone() *CallTimeoutConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	return &clone
+}
+
+// Equal compares two CallTimeoutConfig for equality.
+func (c *CallTimeoutConfig) Equal(other *CallTimeoutConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+
+	return *c == *other
+}
+
+// ValidateCallTimeout checks that Max leaves room for Idle; negative
+// durations are already rejected when decoding.
+func ValidateCallTimeout(c *CallTimeoutConfig) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Idle > 0 && c.Max > 0 && c.Max < c.Idle {
+		return fmt.Errorf("max (%v) must not be shorter than idle (%v)", c.Max, c.Idle)
+	}
+
+	return nil
+}
+
+// validateCallTimeouts checks the call_timeout of servers defined under path.
+func validateCallTimeouts(path string, servers map[string]*ServerConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil {
+			continue
+		}
+
+		if err := ValidateCallTimeout(srv.CallTimeout); err != nil {
+			return fmt.Errorf("%s.%s.call_timeout: %w", path, name, err)
+		}
+	}
+
+	return nil
+}
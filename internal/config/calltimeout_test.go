@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEffectiveCallTimeout(t *testing.T) {
+	t.Parallel()
+
+	def := &CallTimeoutConfig{Idle: time.Minute}
+	own := &CallTimeoutConfig{Idle: 10 * time.Second, Max: time.Hour}
+
+	tests := []struct {
+		name     string
+		server   *ServerConfig
+		settings *Settings
+		want     *CallTimeoutConfig
+	}{
+		{name: "nothing set", server: &ServerConfig{}, settings: &Settings{}},
+		{name: "nil server and settings"},
+		{name: "default", server: &ServerConfig{}, settings: &Settings{CallTimeout: def}, want: def},
+		{name: "own wins", server: &ServerConfig{CallTimeout: own}, settings: &Settings{CallTimeout: def}, want: own},
+		{name: "own zero disables the default", server: &ServerConfig{CallTimeout: &CallTimeoutConfig{}}, settings: &Settings{CallTimeout: def}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := EffectiveCallTimeout(tt.server, tt.settings); got != tt.want {
+				t.Errorf("EffectiveCallTimeout() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCallTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: "settings:\n  call_timeout:\n    idle: 30s\n    max: 10m\nprojects:\n  work:\n    servers:\n      build:\n        call_timeout:\n          idle: 2m\n",
+		},
+		{
+			name:    "max shorter than idle",
+			data:    "settings:\n  call_timeout:\n    idle: 30s\n    max: 10s\n",
+			wantErr: "settings.call_timeout: max (10s) must not be shorter than idle (30s)",
+		},
+		{
+			name:    "server max shorter than idle",
+			data:    "projects:\n  work:\n    servers:\n      build:\n        call_timeout:\n          idle: 1m\n          max: 1s\n",
+			wantErr: "projects.work.servers.build.call_timeout: max (1s) must not be shorter than idle (1m0s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
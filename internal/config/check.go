@@ -0,0 +1,346 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity tells whether a Problem keeps a configuration from working.
+type Severity string
+
+const (
+	// SeverityError is a problem that fails loading or keeps a server from
+	// starting.
+	SeverityError Severity = "error"
+	// SeverityWarning is a setting that loads but is most likely a mistake,
+	// such as a key assern ignores.
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is an issue CheckFiles found in a configuration file.
+type Problem struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"` // 0 when unknown
+	Field    string   `json:"field,omitempty"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+}
+
+// String formats the problem as "file:line: field: message".
+func (p Problem) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location += ":" + strconv.Itoa(p.Line)
+	}
+
+	if p.Field == "" {
+		return location + ": " + p.Message
+	}
+
+	return location + ": " + p.Field + ": " + p.Message
+}
+
+// yamlLinePattern finds the line in the errors of the YAML decoder.
+var yamlLinePattern = regexp.MustCompile(`\bline (\d+):`)
+
+// CheckFiles checks the configuration files LoadEffective reads for workDir
+// and reports every problem found, rather than stopping at the first as
+// loading does: parse and validation errors, unknown keys (errors in strict
+// mode, warnings otherwise), servers with both or neither of command and
+// url, values the JSON Schema of the file rejects (such as a transport or
+// merge_mode that does not exist), project overrides of
+// servers no mcp.json defines, group members no mcp.json defines, a local
+// profile no group matches, servers defined in both the global and the
+// local mcp.json, and ${VAR} references to variables lookup reports unset.
+// The error is for files that cannot be read.
+func CheckFiles(workDir string, lookup func(name string) bool, opts LoadOptions) ([]Problem, error) {
+	globalConfigPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global config path: %w", err)
+	}
+
+	globalMCPPath, err := GlobalMCPPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global mcp path: %w", err)
+	}
+
+	c := &checker{lookup: lookup}
+
+	globalConfigFile, err := readCheckedFile(globalConfigPath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	c.strict = opts.Strict || globalConfigFile.strictSetting()
+
+	globalMCPFile, err := readCheckedFile(globalMCPPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	globalACLPath, err := GlobalACLPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global acl path: %w", err)
+	}
+
+	globalACLFile, err := readCheckedFile(globalACLPath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var localMCPFile, localConfigFile *checkedFile
+
+	if localDir := FindLocalConfigDir(workDir); localDir != "" {
+		if localMCPFile, err = readCheckedFile(LocalMCPPath(localDir), true); err != nil {
+			return nil, err
+		}
+
+		if localConfigFile, err = readCheckedFile(LocalConfigPath(localDir), false); err != nil {
+			return nil, err
+		}
+	}
+
+	globalMCP := c.checkMCPFile(globalMCPFile)
+	localMCP := c.checkMCPFile(localMCPFile)
+
+	globalServers := slices.Collect(maps.Keys(globalMCP.MCPServers))
+	localServers := slices.Collect(maps.Keys(localMCP.MCPServers))
+
+	groups := c.checkConfigFile(globalConfigFile, globalServers, localServers)
+	c.checkACLFile(globalACLFile, globalConfigFile)
+	c.checkLocalConfigFile(localConfigFile, globalConfigFile.path, groups, append(globalServers, localServers...))
+	c.checkDuplicateServers(localMCPFile, globalMCPFile, localServers, globalServers)
+
+	return c.problems, nil
+}
+
+// checker collects the problems of CheckFiles.
+type checker struct {
+	lookup   func(name string) bool
+	strict   bool
+	problems []Problem
+}
+
+// checkedFile is a configuration file with the line of each key.
+type checkedFile struct {
+	path  string
+	data  []byte         // nil when the file does not exist
+	json  bool           // mcp.json rather than YAML
+	lines map[string]int // Line of each key, by field path
+}
+
+// readCheckedFile reads path; a missing file is not an error.
+func readCheckedFile(path string, isJSON bool) (*checkedFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &checkedFile{path: path, json: isJSON}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	f := &checkedFile{path: path, data: data, json: isJSON, lines: make(map[string]int)}
+	if isJSON {
+		data = standardizeJSON(data)
+	}
+
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) == nil {
+		keyLines(&doc, "", f.lines)
+	}
+
+	return f, nil
+}
+
+func (f *checkedFile) exists() bool {
+	return f != nil && f.data != nil
+}
+
+// strictSetting reports whether the file sets settings.strict.
+func (f *checkedFile) strictSetting() bool {
+	var cfg struct {
+		Settings struct {
+			Strict bool `yaml:"strict"`
+		} `yaml:"settings"`
+	}
+
+	return f.exists() && yaml.Unmarshal(f.data, &cfg) == nil && cfg.Settings.Strict
+}
+
+// line returns the line of field, or of its closest enclosing key; 0 when
+// none is known.
+func (f *checkedFile) line(field string) int {
+	for field != "" {
+		if line, ok := f.lines[field]; ok {
+			return line
+		}
+
+		i := strings.LastIndex(field, ".")
+		if i < 0 {
+			break
+		}
+
+		field = field[:i]
+	}
+
+	return 0
+}
+
+// keyLines records the line of every mapping key under node.
+func keyLines(node *yaml.Node, path string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			keyLines(child, path, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := joinPath(path, node.Content[i].Value)
+			lines[key] = node.Content[i].Line
+			keyLines(node.Content[i+1], key, lines)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			key := fmt.Sprintf("%s[%d]", path, i)
+			lines[key] = child.Line
+			keyLines(child, key, lines)
+		}
+	}
+}
+
+func (c *checker) add(f *checkedFile, field string, severity Severity, message string) {
+	c.problems = append(c.problems, Problem{
+		File:     f.path,
+		Line:     f.line(field),
+		Field:    field,
+		Message:  message,
+		Severity: severity,
+	})
+}
+
+// loadError reports the error loading f returned, located when it names a
+// field: each field of a joined error is reported on its own.
+func (c *checker) loadError(f *checkedFile, err error) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if joined, ok := e.(interface{ Unwrap() []error }); ok {
+			for _, err := range joined.Unwrap() {
+				c.loadError(f, err)
+			}
+
+			return
+		}
+	}
+
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		if errors.Is(err, ErrUnknownField) {
+			return // Reported by unknownKeys
+		}
+
+		if !c.reported(f, fieldErr.Line) {
+			c.problems = append(c.problems, Problem{
+				File: f.path, Line: fieldErr.Line, Field: fieldErr.Field, Message: fieldErr.Err.Error(), Severity: SeverityError,
+			})
+		}
+
+		return
+	}
+
+	message := err.Error()
+	for _, prefix := range []string{"parsing config: ", "parsing mcp config: ", "parsing local project config: ", "parsing acl file: ", "acl file: "} {
+		message = strings.TrimPrefix(message, prefix)
+	}
+
+	// Validation errors start with the field they are about
+	if field, rest, ok := strings.Cut(message, ": "); ok && f.line(field) > 0 {
+		if !c.reported(f, f.line(field)) {
+			c.add(f, field, SeverityError, rest)
+		}
+
+		return
+	}
+
+	problem := Problem{File: f.path, Message: message, Severity: SeverityError}
+
+	if match := yamlLinePattern.FindStringSubmatch(message); match != nil {
+		problem.Line, _ = strconv.Atoi(match[1])
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		problem.Line = 1 + strings.Count(string(f.data[:min(int(syntaxErr.Offset), len(f.data))]), "\n")
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		problem.Line = 1 + strings.Count(string(f.data[:min(int(typeErr.Offset), len(f.data))]), "\n")
+	}
+
+	if !c.reported(f, problem.Line) {
+		c.problems = append(c.problems, problem)
+	}
+}
+
+// unknownKeys reports the keys of f that out does not declare.
+func (c *checker) unknownKeys(f *checkedFile, out any) {
+	data, tag := f.data, "yaml"
+	if f.json {
+		data, tag = standardizeJSON(data), "json"
+	}
+
+	severity := SeverityWarning
+	if c.strict {
+		severity = SeverityError
+	}
+
+	err := checkKnownFields(data, out, tag)
+
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	for _, err := range errs {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			c.problems = append(c.problems, Problem{
+				File: f.path, Line: fieldErr.Line, Field: fieldErr.Field, Message: fieldErr.Err.Error(), Severity: severity,
+			})
+		}
+	}
+}
+
+// schemaErrors reports the values of f that do not match the schema of its
+// kind; unknown keys are left to unknownKeys, which suggests fixes.
+func (c *checker) schemaErrors(f *checkedFile, kind SchemaKind) {
+	violations, err := ValidateSchema(kind, f.data)
+	if err != nil {
+		return // A syntax error, reported when the file is loaded
+	}
+
+	for _, v := range violations {
+		if !v.Unknown {
+			c.problems = append(c.problems, Problem{
+				File: f.path, Line: v.Line, Field: v.Field, Message: v.Message, Severity: SeverityError,
+			})
+		}
+	}
+}
+
+// reported reports whether an error on line of f is already known, so the
+// same mistake found by the schema and by loading is reported once.
+func (c *checker) reported(f *checkedFile, line int) bool {
+	return line > 0 && slices.ContainsFunc(c.problems, func(p Problem) bool {
+		return p.File == f.path && p.Line == line && p.Severity == SeverityError
+	})
+}
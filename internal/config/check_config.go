@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// checkConfigFile checks the global config.yaml f, whose projects override
+// the servers of the global mcp.json, and returns its groups. Group members
+// may be servers of either mcp.json.
+func (c *checker) checkConfigFile(f *checkedFile, globalServers, localServers []string) map[string][]string {
+	if !f.exists() {
+		return nil
+	}
+
+	c.unknownKeys(f, &Config{})
+	c.schemaErrors(f, SchemaConfig)
+
+	cfg, err := Parse(f.data, LoadOptions{Strict: c.strict})
+	if err != nil {
+		c.loadError(f, err)
+
+		return nil
+	}
+
+	c.checkGroups(f, cfg.Groups, append(globalServers, localServers...))
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Projects)) {
+		proj := cfg.Projects[name]
+		path := "projects." + name
+
+		c.checkEnv(f, path+".env", proj.Env)
+		c.checkOverrides(f, path+".servers", proj.Servers, globalServers,
+			"the global mcp.json does not define it (project overrides apply before the local mcp.json)")
+	}
+
+	return cfg.Groups
+}
+
+// checkACLFile checks the global acl.yaml f, which replaces settings.acl
+// of the global config.yaml configFile.
+func (c *checker) checkACLFile(f, configFile *checkedFile) {
+	if !f.exists() {
+		return
+	}
+
+	c.unknownKeys(f, &ACLConfig{})
+
+	if _, err := loadACL(f.path, false); err != nil {
+		c.loadError(f, err)
+	} else if configFile.lines["settings.acl"] > 0 {
+		c.add(configFile, "settings.acl", SeverityError, errACLTwice.Error())
+	}
+}
+
+// checkLocalConfigFile checks the .assern config.yaml f: its profile must
+// be one of groups, defined in globalConfigPath, and its overrides must be
+// of servers an mcp.json defines.
+func (c *checker) checkLocalConfigFile(f *checkedFile, globalConfigPath string, groups map[string][]string, defined []string) {
+	if !f.exists() {
+		return
+	}
+
+	c.unknownKeys(f, &LocalProjectConfig{})
+	c.schemaErrors(f, SchemaLocalConfig)
+
+	cfg, err := loadLocalProject(f.path, false)
+	if err != nil {
+		c.loadError(f, err)
+
+		return
+	}
+
+	c.checkEnv(f, "env", cfg.Env)
+
+	if _, ok := groups[cfg.Profile]; !ok && cfg.Profile != "" && cfg.Profile != ProfileAll {
+		c.add(f, "profile", SeverityError, fmt.Sprintf("unknown group %q: groups are defined in %s", cfg.Profile, globalConfigPath))
+	}
+
+	c.checkOverrides(f, "servers", cfg.Servers, defined, "no mcp.json defines it")
+}
+
+// checkOverrides checks the servers of a config.yaml, which override the
+// servers of the mcp.json files named defined; overrides of other servers
+// are ignored, for the reason given.
+func (c *checker) checkOverrides(f *checkedFile, path string, servers map[string]*ServerConfig, defined []string, reason string) {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil {
+			continue
+		}
+
+		field := path + "." + name
+
+		c.checkServer(f, field, srv)
+
+		if !slices.Contains(defined, name) {
+			c.add(f, field, SeverityWarning, "overrides a server that is never defined, so it is ignored: "+reason)
+		}
+	}
+}
+
+// checkGroups warns about group members that no mcp.json defines.
+func (c *checker) checkGroups(f *checkedFile, groups map[string][]string, defined []string) {
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		for i, member := range groups[name] {
+			if !slices.Contains(defined, member) {
+				c.add(f, fmt.Sprintf("groups.%s[%d]", name, i), SeverityWarning,
+					fmt.Sprintf("no mcp.json defines server %q, so the group leaves it out", member))
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+)
+
+// validTransports are the values of a server's transport setting.
+var validTransports = []string{"stdio", "sse", "http", "oauth-sse", "oauth-http", "websocket", "container"}
+
+// checkMCPFile checks the servers f defines and returns them; an empty
+// configuration when f is missing or invalid.
+func (c *checker) checkMCPFile(f *checkedFile) *MCPConfig {
+	if !f.exists() {
+		return NewMCPConfig()
+	}
+
+	c.unknownKeys(f, &MCPConfig{})
+	c.schemaErrors(f, SchemaMCP)
+
+	cfg, err := parseMCPConfig(f.data, false)
+	if err != nil {
+		c.loadError(f, err)
+
+		return NewMCPConfig()
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.MCPServers)) {
+		srv := cfg.MCPServers[name]
+		if srv == nil {
+			continue
+		}
+
+		path := "mcpServers." + name
+
+		c.checkServer(f, path, mcpServerToConfig(srv))
+
+		switch {
+		case srv.Command == "" && srv.URL == "" && srv.Image == "":
+			c.add(f, path, SeverityError, "needs a command (stdio), a url (http, sse) or an image (container)")
+		case srv.Transport == "stdio" && srv.Command == "":
+			c.add(f, path+".transport", SeverityError, "the stdio transport needs a command")
+		case srv.Transport == "container" && srv.Image == "":
+			c.add(f, path+".transport", SeverityError, "the container transport needs an image")
+		case srv.Transport != "" && srv.Transport != "stdio" && srv.Transport != "container" && srv.URL == "":
+			c.add(f, path+".transport", SeverityError, fmt.Sprintf("the %s transport needs a url", srv.Transport))
+		}
+	}
+
+	return cfg
+}
+
+// checkServer checks the settings a server definition and an override
+// share.
+func (c *checker) checkServer(f *checkedFile, path string, srv *ServerConfig) {
+	if srv.Command != "" && srv.URL != "" {
+		c.add(f, path+".url", SeverityError, "command and url are mutually exclusive: set command for a stdio server, url for a remote one")
+	}
+
+	if srv.Image != "" && (srv.Command != "" || srv.URL != "") {
+		c.add(f, path+".image", SeverityError, "image runs the server in a container and excludes command and url")
+	}
+
+	c.checkEnv(f, path+".env", srv.Env)
+
+	for i, arg := range srv.Args {
+		c.checkReferences(f, fmt.Sprintf("%s.args[%d]", path, i), referenceNames(arg))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(srv.Headers)) {
+		c.checkReferences(f, path+".headers."+name, referenceNames(srv.Headers[name]))
+	}
+
+	workDir := "work_dir"
+	if f.json {
+		workDir = "workDir"
+	}
+
+	c.checkReferences(f, path+"."+workDir, referenceNames(srv.WorkDir))
+	c.checkReferences(f, path+".url", referenceNames(srv.URL))
+	c.checkReferences(f, path+".image", referenceNames(srv.Image))
+
+	for i, volume := range srv.Volumes {
+		c.checkReferences(f, fmt.Sprintf("%s.volumes[%d]", path, i), referenceNames(volume))
+	}
+}
+
+// checkEnv checks the references of env values, which may use $VAR as
+// well as ${VAR}.
+func (c *checker) checkEnv(f *checkedFile, path string, env map[string]string) {
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		var refs []string
+
+		os.Expand(env[name], func(ref string) string {
+			refs = append(refs, ref)
+
+			return ""
+		})
+
+		c.checkReferences(f, path+"."+name, refs)
+	}
+}
+
+// checkReferences warns about the variables of refs that are not set.
+func (c *checker) checkReferences(f *checkedFile, field string, refs []string) {
+	if c.lookup == nil {
+		return
+	}
+
+	slices.Sort(refs)
+
+	for _, ref := range slices.Compact(refs) {
+		if !c.lookup(ref) {
+			c.add(f, field, SeverityWarning, fmt.Sprintf("${%s} is not set in the environment or a .env file, so it expands to an empty string", ref))
+		}
+	}
+}
+
+// referenceNames returns the variables of the ${VAR} references in value.
+func referenceNames(value string) []string {
+	var names []string
+
+	for _, match := range referencePattern.FindAllStringSubmatch(value, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
+
+// checkDuplicateServers warns about the servers of the local mcp.json
+// localFile that the global mcp.json globalFile defines too.
+func (c *checker) checkDuplicateServers(localFile, globalFile *checkedFile, localServers, globalServers []string) {
+	for _, name := range slices.Sorted(slices.Values(localServers)) {
+		if slices.Contains(globalServers, name) {
+			field := "mcpServers." + name
+			c.add(localFile, field, SeverityWarning, fmt.Sprintf(
+				"also defined in %s (line %d); this definition is merged over it", globalFile.path, globalFile.line(field)))
+		}
+	}
+}
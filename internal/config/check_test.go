@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckFiles(t *testing.T) {
+	home := t.TempDir()
+	restore := SetHomeDirForTesting(home)
+	defer restore()
+
+	globalDir := filepath.Join(home, ".valksor", "assern")
+	workDir := filepath.Join(home, "work")
+	localDir := filepath.Join(workDir, ".assern")
+
+	files := map[string]string{
+		filepath.Join(globalDir, "mcp.json"): `{
+  // Comments are fine
+  "mcpServers": {
+    "github": {
+      "command": "github-mcp",
+      "env": {"TOKEN": "${GITHUB_TOKEN}", "HOME_DIR": "$HOME"}
+    },
+    "both": {"command": "x", "url": "https://example.com/mcp"},
+    "neither": {"args": ["--flag"]},
+    "badtransport": {"url": "https://example.com/mcp", "transport": "websockets"},
+    "api": {
+      "url": "https://example.com/mcp",
+      "headers": {"Authorization": "Bearer ${API_TOKEN}"},
+      "argz": []
+    }
+  }
+}`,
+		filepath.Join(globalDir, "config.yaml"): `projects:
+  work:
+    directories: ["~/work"]
+    servers:
+      github:
+        merge_mode: merge
+      jira:
+        env:
+          JIRA_TOKEN: "${JIRA_TOKEN}"
+settings:
+  log_levle: debug
+groups:
+  web-dev: [github, browser]
+`,
+		filepath.Join(localDir, "mcp.json"): `{"mcpServers": {"github": {"command": "other-mcp"}, "local": {"command": "local-mcp"}}}`,
+		filepath.Join(localDir, "config.yaml"): `servers:
+  local:
+    transport: stdio
+  missing:
+    env:
+      A: b
+profile: mobile
+`,
+	}
+
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	set := []string{"GITHUB_TOKEN", "HOME"}
+
+	problems, err := CheckFiles(workDir, func(name string) bool { return slices.Contains(set, name) }, LoadOptions{})
+	if err != nil {
+		t.Fatalf("CheckFiles() error = %v", err)
+	}
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, string(p.Severity)+" "+strings.TrimPrefix(p.String(), home+"/"))
+	}
+
+	want := []string{
+		"warning .valksor/assern/mcp.json:14: mcpServers.api.argz: unknown field",
+		`error .valksor/assern/mcp.json:10: mcpServers.badtransport.transport: invalid value "websockets": value must be one of 'stdio', 'sse'`,
+		`warning .valksor/assern/mcp.json:13: mcpServers.api.headers.Authorization: ${API_TOKEN} is not set`,
+		"error .valksor/assern/mcp.json:8: mcpServers.both.url: command and url are mutually exclusive",
+		"error .valksor/assern/mcp.json:9: mcpServers.neither: needs a command (stdio), a url (http, sse) or an image (container)",
+		"warning .valksor/assern/config.yaml:11: settings.log_levle: unknown field (did you mean \"log_level\"?)",
+		"error .valksor/assern/config.yaml:6: projects.work.servers.github.merge_mode: invalid value \"merge\": value must be one of 'overlay', 'replace'",
+		`warning .valksor/assern/config.yaml:13: groups.web-dev[1]: no mcp.json defines server "browser"`,
+		"warning .valksor/assern/config.yaml:9: projects.work.servers.jira.env.JIRA_TOKEN: ${JIRA_TOKEN} is not set",
+		"warning .valksor/assern/config.yaml:7: projects.work.servers.jira: overrides a server that is never defined",
+		`error work/.assern/config.yaml:7: profile: unknown group "mobile"`,
+		"warning work/.assern/config.yaml:4: servers.missing: overrides a server that is never defined",
+		"warning work/.assern/mcp.json:1: mcpServers.github: also defined in " + filepath.Join(globalDir, "mcp.json") + " (line 4)",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("CheckFiles() = %d problems, want %d:\n%s", len(got), len(want), strings.Join(got, "\n"))
+	}
+
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("problem %d = %q, want it to start with %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCheckFilesLoadErrors(t *testing.T) {
+	home := t.TempDir()
+	restore := SetHomeDirForTesting(home)
+	defer restore()
+
+	globalDir := filepath.Join(home, ".valksor", "assern")
+	if err := os.MkdirAll(globalDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"mcp.json":    "{\n  \"mcpServers\": {\n    \"a\": {\"command\": 1}\n  }\n}",
+		"config.yaml": "settings:\n  strict: true\n  listen: nonsense\n  lazy: true\n",
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(globalDir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems, err := CheckFiles(home, nil, LoadOptions{})
+	if err != nil {
+		t.Fatalf("CheckFiles() error = %v", err)
+	}
+
+	if len(problems) != 2 {
+		t.Fatalf("CheckFiles() = %v, want 2 problems", problems)
+	}
+
+	// The mcp.json type error, found by the schema and not again by loading
+	if p := problems[0]; p.Severity != SeverityError || p.Line != 3 || p.Message != "got number, want string" {
+		t.Errorf("problems[0] = %+v, want the type error on line 3", p)
+	}
+
+	// In strict mode unknown keys are errors, and hide later validation
+	if p := problems[1]; p.Severity != SeverityError || p.Field != "settings.lazy" || p.Line != 4 {
+		t.Errorf("problems[1] = %+v, want the unknown key as an error on line 4", p)
+	}
+}
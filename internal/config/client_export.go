@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// assernVarRef is Assern's ${NAME} environment reference.
+var assernVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// AssernClientServer returns the stdio entry that points a client at
+// Assern itself: command serve.
+func AssernClientServer(command string) *MCPServer {
+	return &MCPServer{Command: command, Args: []string{"serve"}}
+}
+
+// ExportClientConfig renders servers in a client's MCP configuration schema,
+// the reverse of ParseClientConfig. Cursor, VS Code and Windsurf get ${NAME}
+// references as ${env:NAME}. The returned warnings name servers that were
+// left out or may need editing in the client.
+func ExportClientConfig(client string, servers map[string]*MCPServer) ([]byte, []string, error) {
+	if !slices.Contains(ClientFormats, client) {
+		return nil, nil, fmt.Errorf("unknown client %q (use %s)", client, joinOr(ClientFormats))
+	}
+
+	entries := make(map[string]*clientExportServer, len(servers))
+
+	var warnings []string
+
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+
+		if srv.OAuth != nil || srv.OAuthRef != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: OAuth servers cannot be exported, skipped", name))
+
+			continue
+		}
+
+		entry, refs := toClientServer(client, srv)
+		if client == ClientClaude && refs {
+			warnings = append(warnings, fmt.Sprintf("%s: Claude Desktop does not expand ${VAR} references, replace them with values", name))
+		}
+
+		entries[name] = entry
+	}
+
+	var doc any = map[string]any{"mcpServers": entries}
+	if client == ClientVSCode {
+		doc = map[string]any{"servers": entries}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding %s config: %w", client, err)
+	}
+
+	return append(data, '\n'), warnings, nil
+}
+
+// clientExportServer is a server entry as the clients write it.
+type clientExportServer struct {
+	Type      string            `json:"type,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Cwd       string            `json:"cwd,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	ServerURL string            `json:"serverUrl,omitempty"` // Windsurf
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// toClientServer converts one server, reporting whether it contains ${NAME}
+// references.
+func toClientServer(client string, srv *MCPServer) (*clientExportServer, bool) {
+	refs := false
+
+	translate := func(s string) string {
+		if !assernVarRef.MatchString(s) {
+			return s
+		}
+
+		refs = true
+
+		if client == ClientClaude {
+			return s
+		}
+
+		return assernVarRef.ReplaceAllString(s, "$${env:$1}")
+	}
+
+	translateMap := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+
+		out := make(map[string]string, len(m))
+		for k, v := range m {
+			out[k] = translate(v)
+		}
+
+		return out
+	}
+
+	entry := &clientExportServer{
+		Command: translate(srv.Command),
+		Env:     translateMap(srv.Env),
+		Cwd:     translate(srv.WorkDir),
+		Headers: translateMap(srv.Headers),
+	}
+
+	for _, arg := range srv.Args {
+		entry.Args = append(entry.Args, translate(arg))
+	}
+
+	url := translate(srv.URL)
+	if client == ClientWindsurf {
+		entry.ServerURL = url
+	} else {
+		entry.URL = url
+	}
+
+	switch {
+	case srv.Command != "":
+		entry.Type = "stdio"
+	case srv.Transport == "sse":
+		entry.Type = "sse"
+	default:
+		entry.Type = "http"
+	}
+
+	// Claude Desktop and Windsurf infer the transport; only the editors
+	// take a type
+	if client == ClientClaude || client == ClientWindsurf {
+		if entry.Type != "sse" {
+			entry.Type = ""
+		}
+	}
+
+	return entry, refs
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExportClientConfig(t *testing.T) {
+	servers := map[string]*MCPServer{
+		"github": {Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"GITHUB_TOKEN": "${GITHUB_TOKEN}"}},
+		"remote": {URL: "https://example.com/sse", Transport: "sse", Headers: map[string]string{"X-Key": "k"}},
+		"secure": {URL: "https://example.com/mcp", OAuth: &OAuthConfig{ClientID: "id"}},
+	}
+
+	tests := []struct {
+		name      string
+		client    string
+		wantKey   string
+		wantEntry map[string]any
+		wantWarns int
+		wantErr   bool
+	}{
+		{
+			name:    "claude keeps references and warns",
+			client:  ClientClaude,
+			wantKey: "mcpServers",
+			wantEntry: map[string]any{
+				"command": "npx", "args": []any{"-y", "server-github"},
+				"env": map[string]any{"GITHUB_TOKEN": "${GITHUB_TOKEN}"},
+			},
+			wantWarns: 2,
+		},
+		{
+			name:    "cursor translates references",
+			client:  ClientCursor,
+			wantKey: "mcpServers",
+			wantEntry: map[string]any{
+				"type": "stdio", "command": "npx", "args": []any{"-y", "server-github"},
+				"env": map[string]any{"GITHUB_TOKEN": "${env:GITHUB_TOKEN}"},
+			},
+			wantWarns: 1,
+		},
+		{
+			name:    "vscode uses servers",
+			client:  ClientVSCode,
+			wantKey: "servers",
+			wantEntry: map[string]any{
+				"type": "stdio", "command": "npx", "args": []any{"-y", "server-github"},
+				"env": map[string]any{"GITHUB_TOKEN": "${env:GITHUB_TOKEN}"},
+			},
+			wantWarns: 1,
+		},
+		{
+			name:    "unknown client",
+			client:  "zed",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, warnings, err := ExportClientConfig(tt.client, servers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportClientConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var doc map[string]map[string]map[string]any
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, data)
+			}
+
+			entries := doc[tt.wantKey]
+			if _, ok := entries["secure"]; ok {
+				t.Error("OAuth server was exported")
+			}
+			if !reflect.DeepEqual(entries["github"], tt.wantEntry) {
+				t.Errorf("github entry = %v, want %v", entries["github"], tt.wantEntry)
+			}
+			if len(warnings) != tt.wantWarns {
+				t.Errorf("warnings = %v, want %d", warnings, tt.wantWarns)
+			}
+		})
+	}
+}
+
+func TestExportClientConfigRoundTrip(t *testing.T) {
+	servers := map[string]*MCPServer{
+		"fs":     {Command: "mcp-fs", Args: []string{"/srv"}, WorkDir: "/srv", Env: map[string]string{"TOKEN": "${TOKEN}"}},
+		"remote": {URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer ${API_TOKEN}"}},
+		"events": {URL: "https://example.com/sse", Transport: "sse"},
+	}
+
+	for _, client := range []string{ClientCursor, ClientVSCode, ClientWindsurf} {
+		t.Run(client, func(t *testing.T) {
+			data, _, err := ExportClientConfig(client, servers)
+			if err != nil {
+				t.Fatalf("ExportClientConfig() error = %v", err)
+			}
+
+			got, err := ParseClientConfig(client, data)
+			if err != nil {
+				t.Fatalf("ParseClientConfig() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got.Servers, servers) {
+				t.Errorf("round trip = %+v, want %+v", got.Servers, servers)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/valksor/go-assern/internal/paths"
+)
+
+// MCP clients whose configuration can be imported and exported.
+const (
+	ClientClaude   = "claude"
+	ClientCursor   = "cursor"
+	ClientVSCode   = "vscode"
+	ClientWindsurf = "windsurf"
+)
+
+// ClientFormats lists the clients accepted by ParseClientConfig and
+// ExportClientConfig.
+var ClientFormats = []string{ClientClaude, ClientCursor, ClientVSCode, ClientWindsurf}
+
+// ClientConfigPaths returns the usual locations of a client's MCP
+// configuration, most specific first: the project file under cwd (Cursor and
+// VS Code), then the user-level file.
+func ClientConfigPaths(client, cwd string) ([]string, error) {
+	switch client {
+	case ClientClaude:
+		return []string{filepath.Join(userConfigDir(), "Claude", "claude_desktop_config.json")}, nil
+	case ClientCursor:
+		return []string{
+			filepath.Join(cwd, ".cursor", "mcp.json"),
+			paths.ExpandPath("~/.cursor/mcp.json"),
+		}, nil
+	case ClientVSCode:
+		return []string{
+			filepath.Join(cwd, ".vscode", "mcp.json"),
+			filepath.Join(userConfigDir(), "Code", "User", "mcp.json"),
+			filepath.Join(userConfigDir(), "Code", "User", "settings.json"),
+		}, nil
+	case ClientWindsurf:
+		return []string{paths.ExpandPath("~/.codeium/windsurf/mcp_config.json")}, nil
+	default:
+		return nil, fmt.Errorf("unknown client %q (use %s)", client, joinOr(ClientFormats))
+	}
+}
+
+// userConfigDir returns the per-user application config directory the
+// clients use: ~/Library/Application Support on macOS, %APPDATA% on
+// Windows and ~/.config elsewhere.
+func userConfigDir() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return paths.ExpandPath("~/Library/Application Support")
+	case "windows":
+		if dir := os.Getenv("APPDATA"); dir != "" {
+			return dir
+		}
+	}
+
+	return paths.ExpandPath("~/.config")
+}
+
+// clientServer is a server entry in any of the supported client formats.
+type clientServer struct {
+	Type      string            `json:"type"`
+	Command   string            `json:"command"`
+	Args      []string          `json:"args"`
+	Env       map[string]string `json:"env"`
+	Cwd       string            `json:"cwd"`
+	URL       string            `json:"url"`
+	ServerURL string            `json:"serverUrl"` // Windsurf
+	Headers   map[string]string `json:"headers"`
+	Disabled  bool              `json:"disabled"`
+}
+
+// clientConfig holds the places the clients keep their servers: mcpServers
+// (Claude, Cursor, Windsurf), servers (VS Code mcp.json) and mcp.servers
+// (VS Code settings.json).
+type clientConfig struct {
+	MCPServers map[string]*clientServer `json:"mcpServers"`
+	Servers    map[string]*clientServer `json:"servers"`
+	MCP        struct {
+		Servers map[string]*clientServer `json:"servers"`
+	} `json:"mcp"`
+}
+
+// ImportResult is the outcome of converting a client configuration.
+type ImportResult struct {
+	// Servers are the converted servers, in Assern's mcp.json schema.
+	Servers map[string]*MCPServer
+	// Warnings describe entries that were skipped or may need editing.
+	Warnings []string
+}
+
+var (
+	// envVarRef is VS Code and Cursor's ${env:NAME}, which Assern writes as
+	// ${NAME}.
+	envVarRef = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+	// clientVarRef matches client variables Assern cannot resolve, such as
+	// ${input:token} or ${workspaceFolder}.
+	clientVarRef = regexp.MustCompile(`\$\{(input:[^}]*|workspaceFolder[^}]*|userHome|cwd)\}`)
+)
+
+// ParseClientConfig converts a client's MCP configuration (JSON with
+// comments) to Assern servers, including env, working directory and
+// headers. Disabled entries are skipped with a warning.
+func ParseClientConfig(client string, data []byte) (*ImportResult, error) {
+	if !slices.Contains(ClientFormats, client) {
+		return nil, fmt.Errorf("unknown client %q (use %s)", client, joinOr(ClientFormats))
+	}
+
+	var raw clientConfig
+	if err := json.Unmarshal(standardizeJSON(data), &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s config: %w", client, err)
+	}
+
+	entries := make(map[string]*clientServer)
+	maps.Copy(entries, raw.MCP.Servers)
+	maps.Copy(entries, raw.Servers)
+	maps.Copy(entries, raw.MCPServers)
+
+	result := &ImportResult{Servers: make(map[string]*MCPServer, len(entries))}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		entry := entries[name]
+		if entry == nil {
+			continue
+		}
+
+		if entry.Disabled {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: disabled in %s, skipped", name, client))
+
+			continue
+		}
+
+		srv := &MCPServer{
+			Command: entry.Command,
+			Args:    entry.Args,
+			Env:     entry.Env,
+			WorkDir: entry.Cwd,
+			URL:     entry.URL,
+			Headers: entry.Headers,
+		}
+
+		if srv.URL == "" {
+			srv.URL = entry.ServerURL
+		}
+
+		if srv.Command == "" && srv.URL == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: no command or url, skipped", name))
+
+			continue
+		}
+
+		// Only an explicit SSE type needs a hint; stdio and HTTP are detected
+		if entry.Type == "sse" && srv.URL != "" {
+			srv.Transport = "sse"
+		}
+
+		if unresolved := translateClientVars(srv); len(unresolved) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s variables %v are not supported, edit them after import",
+				name, client, unresolved))
+		}
+
+		result.Servers[name] = srv
+	}
+
+	return result, nil
+}
+
+// translateClientVars rewrites ${env:NAME} references to ${NAME} and returns
+// the client variables left that Assern cannot resolve.
+func translateClientVars(srv *MCPServer) []string {
+	var unresolved []string
+
+	translate := func(s string) string {
+		s = envVarRef.ReplaceAllString(s, "$${$1}")
+		unresolved = append(unresolved, clientVarRef.FindAllString(s, -1)...)
+
+		return s
+	}
+
+	srv.Command = translate(srv.Command)
+	srv.WorkDir = translate(srv.WorkDir)
+	srv.URL = translate(srv.URL)
+
+	for i, arg := range srv.Args {
+		srv.Args[i] = translate(arg)
+	}
+
+	for k, v := range srv.Env {
+		srv.Env[k] = translate(v)
+	}
+
+	for k, v := range srv.Headers {
+		srv.Headers[k] = translate(v)
+	}
+
+	slices.Sort(unresolved)
+
+	return slices.Compact(unresolved)
+}
+
+// joinOr joins names as "a, b or c".
+func joinOr(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseClientConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		client    string
+		data      string
+		want      map[string]*MCPServer
+		wantWarns []string
+		wantErr   bool
+	}{
+		{
+			name:   "claude desktop",
+			client: ClientClaude,
+			data: `{"mcpServers": {
+				"github": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-github"],
+					"env": {"GITHUB_TOKEN": "ghp_x"}}
+			}}`,
+			want: map[string]*MCPServer{
+				"github": {Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-github"},
+					Env: map[string]string{"GITHUB_TOKEN": "ghp_x"}},
+			},
+		},
+		{
+			name:   "cursor remote with headers",
+			client: ClientCursor,
+			data: `{"mcpServers": {
+				"remote": {"url": "https://example.com/sse", "type": "sse",
+					"headers": {"Authorization": "Bearer ${env:API_TOKEN}"}}
+			}}`,
+			want: map[string]*MCPServer{
+				"remote": {URL: "https://example.com/sse", Transport: "sse",
+					Headers: map[string]string{"Authorization": "Bearer ${API_TOKEN}"}},
+			},
+		},
+		{
+			name:   "vscode mcp.json with comments",
+			client: ClientVSCode,
+			data: `{
+				// Workspace servers
+				"servers": {
+					"fs": {"type": "stdio", "command": "mcp-fs", "cwd": "/srv"},
+					"api": {"type": "http", "url": "https://api.example.com/mcp",
+						"headers": {"X-Key": "${input:apiKey}"}}
+				},
+				"inputs": []
+			}`,
+			want: map[string]*MCPServer{
+				"fs":  {Command: "mcp-fs", WorkDir: "/srv"},
+				"api": {URL: "https://api.example.com/mcp", Headers: map[string]string{"X-Key": "${input:apiKey}"}},
+			},
+			wantWarns: []string{"api: vscode variables"},
+		},
+		{
+			name:   "vscode settings.json",
+			client: ClientVSCode,
+			data:   `{"editor.tabSize": 2, "mcp": {"servers": {"fs": {"command": "mcp-fs"}}}}`,
+			want:   map[string]*MCPServer{"fs": {Command: "mcp-fs"}},
+		},
+		{
+			name:   "windsurf serverUrl and disabled entry",
+			client: ClientWindsurf,
+			data: `{"mcpServers": {
+				"remote": {"serverUrl": "https://example.com/mcp"},
+				"old": {"command": "old-server", "disabled": true},
+				"broken": {}
+			}}`,
+			want:      map[string]*MCPServer{"remote": {URL: "https://example.com/mcp"}},
+			wantWarns: []string{"broken: no command or url", "old: disabled in windsurf"},
+		},
+		{
+			name:    "unknown client",
+			client:  "zed",
+			data:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			client:  ClientClaude,
+			data:    `{"mcpServers": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClientConfig(tt.client, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClientConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(got.Servers) != len(tt.want) {
+				t.Fatalf("ParseClientConfig() servers = %d, want %d", len(got.Servers), len(tt.want))
+			}
+
+			for name, want := range tt.want {
+				srv, ok := got.Servers[name]
+				if !ok {
+					t.Fatalf("server %s missing", name)
+				}
+				if !reflect.DeepEqual(srv, want) {
+					t.Errorf("server %s = %+v, want %+v", name, srv, want)
+				}
+			}
+
+			if len(got.Warnings) != len(tt.wantWarns) {
+				t.Fatalf("warnings = %v, want %v", got.Warnings, tt.wantWarns)
+			}
+			for i, prefix := range tt.wantWarns {
+				if !strings.HasPrefix(got.Warnings[i], prefix) {
+					t.Errorf("warning %d = %q, want prefix %q", i, got.Warnings[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestClientConfigPaths(t *testing.T) {
+	home := t.TempDir()
+	restore := SetHomeDirForTesting(home)
+	defer restore()
+
+	cwd := filepath.Join(home, "project")
+
+	paths, err := ClientConfigPaths(ClientCursor, cwd)
+	if err != nil {
+		t.Fatalf("ClientConfigPaths() error = %v", err)
+	}
+
+	want := []string{filepath.Join(cwd, ".cursor", "mcp.json"), filepath.Join(home, ".cursor", "mcp.json")}
+	if !slices.Equal(paths, want) {
+		t.Errorf("ClientConfigPaths() = %v, want %v", paths, want)
+	}
+
+	if _, err := ClientConfigPaths("zed", cwd); err == nil {
+		t.Error("ClientConfigPaths() expected error for unknown client")
+	}
+}
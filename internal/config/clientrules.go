@@ -0,0 +1,87 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ClientRules limits the tools offered to an MCP client, which is picked by
+// the name it sends in initialize (see Settings.Clients).
+//
+// Entries are globs over exposed tool names, e.g. "filesystem_*", or, with
+// a "server:" qualifier, over a server's own tool names as in ToolRules
+// ("github:delete_*"). "!" entries exclude what they match from the rest
+// of their list. Denied wins over allowed.
+type ClientRules struct {
+	Allowed []string `yaml:"allowed,omitempty"`
+	Denied  []string `yaml:"denied,omitempty"`
+}
+
+// Allows reports whether the client may list and call tool of server,
+// exposed as exposed. Nil rules allow every tool.
+func (r *ClientRules) Allows(server, tool, exposed string) bool {
+	if r == nil || server == "" {
+		return true
+	}
+
+	match := func(pattern string) bool {
+		if strings.Contains(pattern, ":") {
+			return matchToolPattern(pattern, server, tool)
+		}
+
+		return MatchGlob(pattern, exposed)
+	}
+
+	if len(r.Allowed) > 0 && !matchList(r.Allowed, true, match) {
+		return false
+	}
+
+	return !matchList(r.Denied, false, match)
+}
+
+// Clone creates a deep copy of the rules.
+func (r *ClientRules) Clone() *ClientRules {
+	if r == nil {
+		return nil
+	}
+
+	return &ClientRules{Allowed: slices.Clone(r.Allowed), Denied: slices.Clone(r.Denied)}
+}
+
+// LookupClient returns the rules of the client named name: the entry of
+// that name, else the longest glob entry matching it (the first in name
+// order on a tie), so "Claude*" covers the Claude clients and "*" every
+// other. Nil when none matches.
+func LookupClient(clients map[string]*ClientRules, name string) *ClientRules {
+	if rules, ok := clients[name]; ok {
+		return rules
+	}
+
+	best := ""
+	for _, pattern := range slices.Sorted(maps.Keys(clients)) {
+		if strings.ContainsAny(pattern, "*?") && len(pattern) > len(best) && MatchGlob(pattern, name) {
+			best = pattern
+		}
+	}
+
+	if best == "" {
+		return nil
+	}
+
+	return clients[best]
+}
+
+// cloneClients deep-copies the rules of every client.
+func cloneClients(clients map[string]*ClientRules) map[string]*ClientRules {
+	if clients == nil {
+		return nil
+	}
+
+	clone := make(map[string]*ClientRules, len(clients))
+	for name, rules := range clients {
+		clone[name] = rules.Clone()
+	}
+
+	return clone
+}
@@ -0,0 +1,65 @@
+package config
+
+import "testing"
+
+func TestClientRulesAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rules   *ClientRules
+		server  string
+		tool    string
+		exposed string
+		want    bool
+	}{
+		{name: "nil rules", server: "fs", tool: "read", exposed: "fs_read", want: true},
+		{name: "denied exposed glob", rules: &ClientRules{Denied: []string{"filesystem_*"}}, server: "filesystem", tool: "read_file", exposed: "filesystem_read_file"},
+		{name: "other server", rules: &ClientRules{Denied: []string{"filesystem_*"}}, server: "github", tool: "search", exposed: "github_search", want: true},
+		{name: "qualified own name", rules: &ClientRules{Denied: []string{"github:delete_*"}}, server: "github", tool: "delete_repo", exposed: "gh_delete_repo"},
+		{name: "not allowed", rules: &ClientRules{Allowed: []string{"github_*"}}, server: "jira", tool: "search", exposed: "jira_search"},
+		{name: "allowed with exclusion", rules: &ClientRules{Allowed: []string{"github_*", "!github_delete_*"}}, server: "github", tool: "delete_repo", exposed: "github_delete_repo"},
+		{name: "meta-tool", rules: &ClientRules{Allowed: []string{"github_*"}}, tool: "assern_status", exposed: "assern_status", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.rules.Allows(tt.server, tt.tool, tt.exposed); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupClient(t *testing.T) {
+	t.Parallel()
+
+	desktop := &ClientRules{Denied: []string{"a"}}
+	claude := &ClientRules{Denied: []string{"b"}}
+	star := &ClientRules{Denied: []string{"c"}}
+
+	clients := map[string]*ClientRules{"Claude Desktop": desktop, "Claude*": claude, "*": star}
+
+	tests := []struct {
+		name string
+		want *ClientRules
+	}{
+		{name: "Claude Desktop", want: desktop},
+		{name: "Claude Code", want: claude},
+		{name: "editor", want: star},
+	}
+
+	for _, tt := range tests {
+		if got := LookupClient(clients, tt.name); got != tt.want {
+			t.Errorf("LookupClient(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	delete(clients, "*")
+
+	if got := LookupClient(clients, "editor"); got != nil {
+		t.Errorf("LookupClient(editor) = %+v, want nil", got)
+	}
+}
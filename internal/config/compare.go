@@ -1,6 +1,10 @@
 package config
 
-import "slices"
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
 
 // ConfigDiff represents the difference between two configurations.
 type ConfigDiff struct {
@@ -59,10 +63,26 @@ func (s *ServerConfig) Equal(other *ServerConfig) bool {
 	// Compare basic string fields
 	if s.Command != other.Command ||
 		s.WorkDir != other.WorkDir ||
+		s.Encoding != other.Encoding ||
+		s.Locale != other.Locale ||
+		s.Image != other.Image ||
+		s.Network != other.Network ||
 		s.URL != other.URL ||
 		s.Transport != other.Transport ||
 		s.OAuthRef != other.OAuthRef ||
 		s.Disabled != other.Disabled ||
+		s.Coalesce != other.Coalesce ||
+		s.MaxConcurrency != other.MaxConcurrency ||
+		s.Queue != other.Queue ||
+		s.Sampling != other.Sampling ||
+		s.DryRun != other.DryRun ||
+		s.Deprecated != other.Deprecated ||
+		s.Priority != other.Priority ||
+		s.MaxDescriptionLength != other.MaxDescriptionLength ||
+		s.StripSchemaExamples != other.StripSchemaExamples ||
+		s.RestartPolicy != other.RestartPolicy ||
+		s.Lazy != other.Lazy ||
+		s.Prefix != other.Prefix ||
 		s.MergeMode != other.MergeMode {
 		return false
 	}
@@ -71,10 +91,17 @@ func (s *ServerConfig) Equal(other *ServerConfig) bool {
 	if !slices.Equal(s.Args, other.Args) {
 		return false
 	}
+	if !slices.Equal(s.Volumes, other.Volumes) {
+		return false
+	}
 	if !slices.Equal(s.Allowed, other.Allowed) {
 		return false
 	}
 
+	if !slices.Equal(s.Denied, other.Denied) {
+		return false
+	}
+
 	// Compare maps
 	if !mapsEqual(s.Env, other.Env) {
 		return false
@@ -82,6 +109,29 @@ func (s *ServerConfig) Equal(other *ServerConfig) bool {
 	if !mapsEqual(s.Headers, other.Headers) {
 		return false
 	}
+	if !mapsEqual(s.DeprecatedTools, other.DeprecatedTools) {
+		return false
+	}
+	if !mapsEqual(s.IdempotencyKeys, other.IdempotencyKeys) {
+		return false
+	}
+	if !mapsEqual(s.ForwardHeaders, other.ForwardHeaders) {
+		return false
+	}
+	if !maps.Equal(s.ToolPriority, other.ToolPriority) {
+		return false
+	}
+	if !mapsEqual(s.ToolDescriptions, other.ToolDescriptions) {
+		return false
+	}
+
+	if !toolDeclarationsEqual(s.Tools, other.Tools) {
+		return false
+	}
+
+	if !slices.Equal(s.Maintenance, other.Maintenance) {
+		return false
+	}
 
 	// Compare OAuth configs
 	if !s.OAuth.Equal(other.OAuth) {
@@ -93,6 +143,31 @@ func (s *ServerConfig) Equal(other *ServerConfig) bool {
 		return false
 	}
 
+	// Compare health check configs
+	if !s.Health.Equal(other.Health) {
+		return false
+	}
+
+	if !s.CallTimeout.Equal(other.CallTimeout) {
+		return false
+	}
+
+	if !s.ResourceCache.Equal(other.ResourceCache) {
+		return false
+	}
+
+	if !s.BinaryContent.Equal(other.BinaryContent) {
+		return false
+	}
+
+	if !s.Prompts.Equal(other.Prompts) {
+		return false
+	}
+
+	if !s.AllowedResources.Equal(other.AllowedResources) {
+		return false
+	}
+
 	return true
 }
 
@@ -110,6 +185,10 @@ func (o *OAuthConfig) Equal(other *OAuthConfig) bool {
 		o.RedirectURI == other.RedirectURI &&
 		o.AuthServerMetadataURL == other.AuthServerMetadataURL &&
 		o.PKCEEnabled == other.PKCEEnabled &&
+		o.DeviceFlow == other.DeviceFlow &&
+		o.DeviceAuthorizationURL == other.DeviceAuthorizationURL &&
+		o.RefreshBefore == other.RefreshBefore &&
+		o.ClockSkew == other.ClockSkew &&
 		slices.Equal(o.Scopes, other.Scopes)
 }
 
@@ -141,3 +220,21 @@ func mapsEqual(a, b map[string]string) bool {
 
 	return true
 }
+
+// Equal compares two HealthCheckConfig for equality.
+func (h *HealthCheckConfig) Equal(other *HealthCheckConfig) bool {
+	if h == nil && other == nil {
+		return true
+	}
+	if h == nil || other == nil {
+		return false
+	}
+
+	return h.Interval == other.Interval &&
+		h.Timeout == other.Timeout &&
+		h.Tool == other.Tool &&
+		h.Ping == other.Ping &&
+		h.Reconnect == other.Reconnect &&
+		h.HideTools == other.HideTools &&
+		reflect.DeepEqual(h.Arguments, other.Arguments)
+}
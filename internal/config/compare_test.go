@@ -92,6 +92,17 @@ func TestServerConfigEqual(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "different denied list",
+			a: &ServerConfig{
+				Command: "node",
+				Denied:  []string{"delete_*"},
+			},
+			b: &ServerConfig{
+				Command: "node",
+			},
+			expected: false,
+		},
 		{
 			name: "url vs command",
 			a: &ServerConfig{
@@ -102,6 +113,90 @@ func TestServerConfigEqual(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name:     "different health check",
+			a:        &ServerConfig{Command: "node", Health: &HealthCheckConfig{Tool: "ping"}},
+			b:        &ServerConfig{Command: "node", Health: &HealthCheckConfig{Tool: "ping", Arguments: map[string]any{"x": 1}}},
+			expected: false,
+		},
+		{
+			name:     "same health check",
+			a:        &ServerConfig{Command: "node", Health: &HealthCheckConfig{Tool: "ping", Interval: time.Minute}},
+			b:        &ServerConfig{Command: "node", Health: &HealthCheckConfig{Tool: "ping", Interval: time.Minute}},
+			expected: true,
+		},
+		{
+			name:     "different coalesce",
+			a:        &ServerConfig{Command: "node"},
+			b:        &ServerConfig{Command: "node", Coalesce: true},
+			expected: false,
+		},
+		{
+			name:     "different deprecation note",
+			a:        &ServerConfig{Command: "node", Deprecated: "use new"},
+			b:        &ServerConfig{Command: "node"},
+			expected: false,
+		},
+		{
+			name:     "different deprecated tools",
+			a:        &ServerConfig{Command: "node", DeprecatedTools: map[string]string{"search": "use find"}},
+			b:        &ServerConfig{Command: "node", DeprecatedTools: map[string]string{"search": "use query"}},
+			expected: false,
+		},
+		{
+			name:     "different tool priority",
+			a:        &ServerConfig{Command: "node", ToolPriority: map[string]int{"search": 10}},
+			b:        &ServerConfig{Command: "node", ToolPriority: map[string]int{"search": 5}},
+			expected: false,
+		},
+		{
+			name:     "different max concurrency",
+			a:        &ServerConfig{Command: "node", MaxConcurrency: 1},
+			b:        &ServerConfig{Command: "node", MaxConcurrency: 2, Queue: true},
+			expected: false,
+		},
+		{
+			name:     "different resource filter",
+			a:        &ServerConfig{Command: "node", AllowedResources: &ResourceFilter{MIMETypes: []string{"text/*"}}},
+			b:        &ServerConfig{Command: "node", AllowedResources: &ResourceFilter{MIMETypes: []string{"text/markdown"}}},
+			expected: false,
+		},
+		{
+			name:     "different resource cache",
+			a:        &ServerConfig{Command: "node", ResourceCache: &ResourceCacheConfig{TTL: time.Hour}},
+			b:        &ServerConfig{Command: "node", ResourceCache: &ResourceCacheConfig{TTL: 2 * time.Hour}},
+			expected: false,
+		},
+		{
+			name:     "different prompt renames",
+			a:        &ServerConfig{Command: "node", Prompts: &PromptFilter{Rename: map[string]string{"a": "b"}}},
+			b:        &ServerConfig{Command: "node", Prompts: &PromptFilter{Rename: map[string]string{"a": "c"}}},
+			expected: false,
+		},
+		{
+			name:     "different lazy flag",
+			a:        &ServerConfig{Command: "node", Lazy: true},
+			b:        &ServerConfig{Command: "node"},
+			expected: false,
+		},
+		{
+			name: "same declared tools",
+			a: &ServerConfig{Command: "node", Tools: []ToolDeclaration{
+				{Name: "search", InputSchema: map[string]any{"type": "object"}},
+			}},
+			b: &ServerConfig{Command: "node", Tools: []ToolDeclaration{
+				{Name: "search", InputSchema: map[string]any{"type": "object"}},
+			}},
+			expected: true,
+		},
+		{
+			name: "different declared tool schema",
+			a: &ServerConfig{Command: "node", Tools: []ToolDeclaration{
+				{Name: "search", InputSchema: map[string]any{"type": "object"}},
+			}},
+			b:        &ServerConfig{Command: "node", Tools: []ToolDeclaration{{Name: "search"}}},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -20,6 +20,14 @@ const (
 	MergeModeReplace MergeMode = "replace"
 )
 
+// Restart policies for stdio servers whose process exits unexpectedly.
+const (
+	// RestartOnFailure restarts the process with exponential backoff (default).
+	RestartOnFailure = "on-failure"
+	// RestartNever leaves a crashed server down until the next reload.
+	RestartNever = "never"
+)
+
 // RetryConfig defines retry behavior for server operations.
 type RetryConfig struct {
 	MaxAttempts   int           `yaml:"max_attempts,omitempty" json:"maxAttempts,omitempty"`
@@ -47,6 +55,59 @@ type OAuthConfig struct {
 	Scopes                []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
 	AuthServerMetadataURL string   `yaml:"auth_server_metadata_url,omitempty" json:"authServerMetadataUrl,omitempty"`
 	PKCEEnabled           bool     `yaml:"pkce_enabled,omitempty" json:"pkceEnabled,omitempty"`
+
+	// DeviceFlow authorizes with the device authorization grant (RFC 8628)
+	// instead of a browser redirect: assern shows a verification URL and
+	// code to enter on any device and waits for the token, for headless
+	// machines. DeviceAuthorizationURL overrides the endpoint advertised in
+	// the authorization server metadata.
+	DeviceFlow             bool   `yaml:"device_flow,omitempty" json:"deviceFlow,omitempty"`
+	DeviceAuthorizationURL string `yaml:"device_authorization_url,omitempty" json:"deviceAuthorizationUrl,omitempty"`
+
+	// RefreshBefore refreshes a cached token this long before it expires
+	// (see EffectiveRefreshBefore)
+	RefreshBefore time.Duration `yaml:"refresh_before,omitempty" json:"refreshBefore,omitempty"`
+
+	// ClockSkew is how far the local clock may differ from the authorization
+	// server's: cached tokens are refreshed this much earlier, or more when
+	// its responses show a larger lead (see EffectiveClockSkew)
+	ClockSkew time.Duration `yaml:"clock_skew,omitempty" json:"clockSkew,omitempty"`
+}
+
+// DefaultOAuthRefreshBefore is how long before expiry a cached OAuth token
+// with a refresh token is refreshed when refresh_before is unset.
+const DefaultOAuthRefreshBefore = time.Minute
+
+// DefaultOAuthClockSkew is the clock difference tolerated between assern
+// and an authorization server when clock_skew is unset.
+const DefaultOAuthClockSkew = 30 * time.Second
+
+// EffectiveRefreshBefore returns how long before expiry to refresh a token.
+// Zero uses DefaultOAuthRefreshBefore; a negative value returns zero,
+// refreshing only once the token has expired. o may be nil.
+func (o *OAuthConfig) EffectiveRefreshBefore() time.Duration {
+	switch {
+	case o == nil || o.RefreshBefore == 0:
+		return DefaultOAuthRefreshBefore
+	case o.RefreshBefore < 0:
+		return 0
+	default:
+		return o.RefreshBefore
+	}
+}
+
+// EffectiveClockSkew returns the tolerated clock difference. Zero uses
+// DefaultOAuthClockSkew; a negative value returns zero, trusting the local
+// clock. o may be nil.
+func (o *OAuthConfig) EffectiveClockSkew() time.Duration {
+	switch {
+	case o == nil || o.ClockSkew == 0:
+		return DefaultOAuthClockSkew
+	case o.ClockSkew < 0:
+		return 0
+	default:
+		return o.ClockSkew
+	}
 }
 
 // Config represents the complete Assern configuration (internal merged representation).
@@ -58,6 +119,12 @@ type Config struct {
 	// Auth holds named OAuth profiles that servers can reference by oauth_ref,
 	// so several servers can share one set of OAuth credentials.
 	Auth map[string]*OAuthConfig `yaml:"auth,omitempty"`
+	// Groups maps profile names to the servers they start, e.g.
+	// "web-dev": [github, filesystem] (see LoadOptions.Profile)
+	Groups map[string][]string `yaml:"groups,omitempty"`
+	// Profile is the group the effective configuration's servers were
+	// limited to, if any
+	Profile string `yaml:"-" json:"-"`
 }
 
 // ServerConfig defines an MCP server configuration.
@@ -68,6 +135,22 @@ type ServerConfig struct {
 	Env     map[string]string `yaml:"env,omitempty"`
 	WorkDir string            `yaml:"work_dir,omitempty"` // Working directory for stdio servers
 
+	// Encoding is the character encoding a stdio server reads and writes,
+	// e.g. "windows-1252" or "shift_jis" (see LookupEncoding); its output
+	// is transcoded to UTF-8 and its input from it. Empty means UTF-8
+	Encoding string `yaml:"encoding,omitempty"`
+	// Locale sets LANG and LC_ALL of a stdio server, e.g. "en_US.UTF-8",
+	// unless Env sets them
+	Locale string `yaml:"locale,omitempty"`
+
+	// Container transport fields: Image is run with settings.container_engine
+	// and spoken to over stdio, with Args as the container's arguments, Env
+	// passed in, WorkDir as its working directory, Volumes mounted
+	// ("host:container[:ro]") and Network joined
+	Image   string   `yaml:"image,omitempty"`
+	Volumes []string `yaml:"volumes,omitempty"`
+	Network string   `yaml:"network,omitempty"`
+
 	// HTTP/SSE transport fields
 	URL     string            `yaml:"url,omitempty"`
 	Headers map[string]string `yaml:"headers,omitempty"` // Custom HTTP headers (API keys, Bearer tokens)
@@ -79,123 +162,141 @@ type ServerConfig struct {
 	// Used when OAuth is not set inline; inline OAuth takes precedence.
 	OAuthRef string `yaml:"oauth_ref,omitempty"`
 
-	// Transport type hint: "stdio", "sse", "http", "oauth-sse", "oauth-http" (auto-detected if not specified)
+	// Transport type hint: "stdio", "sse", "http", "oauth-sse", "oauth-http", "websocket", "container" (auto-detected if not specified)
 	Transport string `yaml:"transport,omitempty"`
 
 	// Retry configuration for transient failures
 	Retry *RetryConfig `yaml:"retry,omitempty" json:"retry,omitempty"`
 
-	// Common fields
+	// IdempotencyKeys marks mutating tools, keyed by their unprefixed name,
+	// whose calls carry a key generated once per call and repeated on every
+	// retry, so the backend can drop duplicates. The value is the argument
+	// to set, or "_meta.<key>" to send it in the request _meta instead
+	IdempotencyKeys map[string]string `yaml:"idempotency_keys,omitempty"`
+
+	// ForwardHeaders copies headers of the HTTP request a tool call came in
+	// on into the backend call, keyed by header name. The value is the
+	// argument to set, or "_meta.<key>" to send it in the request _meta
+	ForwardHeaders map[string]string `yaml:"forward_headers,omitempty"`
+
+	// Coalesce attaches an identical tool call (same tool, same arguments) to
+	// the one already in flight instead of issuing a duplicate backend call
+	Coalesce bool `yaml:"coalesce,omitempty"`
+
+	// MaxConcurrency caps the server's tool calls in flight (0 = no limit).
+	// Beyond it calls wait for a free slot when Queue is set, or else fail
+	// at once with a retriable error
+	MaxConcurrency int  `yaml:"max_concurrency,omitempty"`
+	Queue          bool `yaml:"queue,omitempty"`
+
+	// Sampling declares the sampling capability to the backend and forwards
+	// its sampling/createMessage requests to the client whose tool call is
+	// in flight, so backends can have the client's LLM generate text
+	Sampling bool `yaml:"sampling,omitempty"`
+
+	// DryRun answers tool calls with the request that would have been
+	// forwarded instead of calling the backend (debugging aid)
+	DryRun bool `yaml:"dry_run,omitempty"`
+
+	// Deprecated marks every tool of the server as deprecated with a note
+	// such as "use github instead"; DeprecatedTools does the same for single
+	// tools, keyed by their unprefixed name, and wins over Deprecated
+	Deprecated      string            `yaml:"deprecated,omitempty"`
+	DeprecatedTools map[string]string `yaml:"deprecated_tools,omitempty"`
+
+	// Priority moves every tool of the server up tools/list (higher first,
+	// negative values sink); ToolPriority does the same for single tools,
+	// keyed by their unprefixed name, and wins over Priority
+	Priority     int            `yaml:"priority,omitempty"`
+	ToolPriority map[string]int `yaml:"tool_priority,omitempty"`
+
+	// ToolDescriptions replaces the descriptions of single tools, keyed by
+	// their unprefixed name, e.g. with shorter ones
+	ToolDescriptions map[string]string `yaml:"tool_descriptions,omitempty"`
+	// MaxDescriptionLength and StripSchemaExamples trim the server's tool
+	// definitions like the settings of the same name; a negative
+	// MaxDescriptionLength lifts the limit (see ToolBudget)
+	MaxDescriptionLength int  `yaml:"max_description_length,omitempty"`
+	StripSchemaExamples  bool `yaml:"strip_schema_examples,omitempty"`
+
+	// RestartPolicy is "on-failure" (default) or "never"; see RestartsOnCrash
+	RestartPolicy string `yaml:"restart_policy,omitempty"`
+
+	// Lazy defers starting the server until the first call to one of its
+	// tools, which are advertised from Tools or from the tool cache meanwhile
+	Lazy  bool              `yaml:"lazy,omitempty"`
+	Tools []ToolDeclaration `yaml:"tools,omitempty"`
+
+	// Health declares a periodic probe that feeds the health tracker
+	Health *HealthCheckConfig `yaml:"health_check,omitempty"`
+
+	// CallTimeout fails tool calls that hang, while letting those that
+	// report progress run longer; it replaces settings.call_timeout
+	CallTimeout *CallTimeoutConfig `yaml:"call_timeout,omitempty"`
+
+	// Maintenance declares recurring windows during which the server is
+	// known to be down: calls fail at once with a friendly error, and the
+	// server is neither probed nor restarted until the window ends
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty"`
+
+	// AllowedResources filters the server's resources by MIME type or URI
+	AllowedResources *ResourceFilter `yaml:"allowed_resources,omitempty"`
+
+	// ResourceCache caches the server's resource reads on disk
+	ResourceCache *ResourceCacheConfig `yaml:"resource_cache,omitempty"`
+
+	// BinaryContent strips, thumbnails or references the binary content of
+	// tool results (see BinaryMode)
+	BinaryContent *BinaryContentConfig `yaml:"binary_content,omitempty"`
+
+	// Prompts filters and renames the server's prompts
+	Prompts *PromptFilter `yaml:"prompts,omitempty"`
+
+	// Prefix replaces the server name in its exposed tool names, and
+	// prefixes them even under prefix_strategy "none"
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// Common fields. Allowed and Denied filter tools by name or glob; see
+	// ToolRules
 	Allowed   []string  `yaml:"allowed,omitempty"`
+	Denied    []string  `yaml:"denied,omitempty"`
 	Disabled  bool      `yaml:"disabled,omitempty"`
 	MergeMode MergeMode `yaml:"merge_mode,omitempty"`
 }
 
+// RestartsOnCrash reports whether a stdio server is restarted when its process
+// exits unexpectedly, and a WebSocket server reconnected when its connection
+// drops. Only RestartNever opts out.
+func (s *ServerConfig) RestartsOnCrash() bool {
+	return s != nil && s.RestartPolicy != RestartNever
+}
+
 // ProjectConfig defines a project's configuration in the global registry.
 type ProjectConfig struct {
 	Directories []string                 `yaml:"directories,omitempty"`
 	Env         map[string]string        `yaml:"env,omitempty"`
 	Servers     map[string]*ServerConfig `yaml:"servers,omitempty"`
+	// Remotes match git remote URLs (e.g. "github.com/acme/*"), so clones
+	// outside the listed directories are still detected.
+	Remotes []string `yaml:"remotes,omitempty"`
+	// Priority decides which project wins when directory patterns of several
+	// projects match the same path. Higher wins; ties go to the first name
+	// in alphabetical order.
+	Priority int `yaml:"priority,omitempty"`
+	// Settings overrides global settings while this project is active.
+	Settings *SettingsOverride `yaml:"settings,omitempty"`
+	// Profile is the group of servers started in this project unless
+	// --profile or ASSERN_PROFILE selects another.
+	Profile string `yaml:"profile,omitempty"`
 }
 
 // LocalProjectConfig represents the .assern/config.yaml in a project directory.
 type LocalProjectConfig struct {
-	Project string                   `yaml:"project,omitempty"`
-	Servers map[string]*ServerConfig `yaml:"servers,omitempty"`
-	Env     map[string]string        `yaml:"env,omitempty"`
-}
-
-// Settings contains global Assern settings.
-type Settings struct {
-	LogLevel     string            `yaml:"log_level,omitempty"`
-	LogFile      string            `yaml:"log_file,omitempty"`
-	Timeout      time.Duration     `yaml:"timeout,omitempty"`
-	OutputFormat string            `yaml:"output_format,omitempty"` // "json" or "toon"
-	Aliases      map[string]string `yaml:"aliases,omitempty"`       // Tool aliases (alias -> prefixed_tool_name)
-	Discovery    *DiscoveryConfig  `yaml:"discovery,omitempty"`     // Runtime tool discovery (progressive disclosure)
-	CodeMode     *CodeModeConfig   `yaml:"code_mode,omitempty"`     // Sandboxed tool-composition via assern_execute
-}
-
-// CodeModeConfig controls the assern_execute meta-tool, which runs a sandboxed
-// Starlark script that can orchestrate several aggregated tools in one call.
-// Disabled by default; it adds a code-execution surface, so enable deliberately.
-type CodeModeConfig struct {
-	// Enabled exposes the assern_execute tool. Off by default.
-	Enabled bool `yaml:"enabled,omitempty"`
-	// Timeout bounds a single script's wall-clock execution time.
-	Timeout time.Duration `yaml:"timeout,omitempty"`
-	// MaxToolCalls caps how many tool calls one script may make.
-	MaxToolCalls int `yaml:"max_tool_calls,omitempty"`
-	// MaxOutputBytes caps the size of a script's captured output.
-	MaxOutputBytes int `yaml:"max_output_bytes,omitempty"`
-	// AllowedTools restricts which prefixed tool names a script may call.
-	// Empty means any aggregated tool may be called.
-	AllowedTools []string `yaml:"allowed_tools,omitempty"`
-}
-
-// IsEnabled reports whether code mode is configured and turned on.
-func (c *CodeModeConfig) IsEnabled() bool {
-	return c != nil && c.Enabled
-}
-
-// Default values for tool discovery. They only take effect when discovery is
-// enabled; the feature is opt-in and off by default.
-const (
-	// DefaultDiscoveryMaxResults caps how many tools assern_search returns.
-	DefaultDiscoveryMaxResults = 10
-	// DefaultDiscoveryMaxLoaded caps how many tools a single session may have
-	// loaded at once. Zero means unlimited.
-	DefaultDiscoveryMaxLoaded = 30
-)
-
-// DiscoveryConfig controls runtime tool discovery (progressive disclosure).
-// When disabled (the default), every aggregated tool is exposed to the client
-// at startup, preserving the original behaviour. When enabled, only the
-// assern_* meta-tools (plus any Pinned tools) are exposed up front, and clients
-// pull in the tools they need at runtime via assern_search / assern_load.
-type DiscoveryConfig struct {
-	// Enabled turns progressive disclosure on. Off by default.
-	Enabled bool `yaml:"enabled,omitempty"`
-	// Pinned lists prefixed tool names (e.g. "github_search") that are always
-	// exposed even in discovery mode, without needing a search.
-	Pinned []string `yaml:"pinned,omitempty"`
-	// MaxResults is the default number of matches assern_search returns.
-	MaxResults int `yaml:"max_results,omitempty"`
-	// MaxLoaded caps the number of tools a session may have loaded at once.
-	// When the cap is reached, the least-recently loaded tool is evicted.
-	// Zero uses DefaultDiscoveryMaxLoaded; a negative value means unlimited.
-	MaxLoaded int `yaml:"max_loaded,omitempty"`
-}
-
-// IsEnabled reports whether discovery is configured and turned on.
-func (d *DiscoveryConfig) IsEnabled() bool {
-	return d != nil && d.Enabled
-}
-
-// EffectiveMaxResults returns the configured search limit or the default.
-func (d *DiscoveryConfig) EffectiveMaxResults() int {
-	if d == nil || d.MaxResults <= 0 {
-		return DefaultDiscoveryMaxResults
-	}
-
-	return d.MaxResults
-}
-
-// EffectiveMaxLoaded returns the per-session load ceiling. A return of zero
-// means unlimited (no eviction).
-func (d *DiscoveryConfig) EffectiveMaxLoaded() int {
-	if d == nil {
-		return DefaultDiscoveryMaxLoaded
-	}
-
-	switch {
-	case d.MaxLoaded < 0:
-		return 0 // unlimited
-	case d.MaxLoaded == 0:
-		return DefaultDiscoveryMaxLoaded
-	default:
-		return d.MaxLoaded
-	}
+	Project  string                   `yaml:"project,omitempty"`
+	Servers  map[string]*ServerConfig `yaml:"servers,omitempty"`
+	Env      map[string]string        `yaml:"env,omitempty"`
+	Settings *SettingsOverride        `yaml:"settings,omitempty"` // Overrides global and project settings
+	Profile  string                   `yaml:"profile,omitempty"`  // Overrides the project's default profile
 }
 
 // NewConfig creates a new empty Config with initialized maps.
@@ -207,171 +308,8 @@ func NewConfig() *Config {
 	}
 }
 
-// DefaultSettings returns the default settings.
-func DefaultSettings() *Settings {
-	return &Settings{
-		LogLevel:     "info",
-		Timeout:      60 * time.Second,
-		OutputFormat: "json", // Default to JSON for backward compatibility
-	}
-}
-
-// Load reads a configuration file from the given path.
-func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
-	}
-
-	return Parse(data)
-}
-
-// Parse parses YAML configuration data (config.yaml).
-// Note: This only parses Projects and Settings. Servers come from mcp.json.
-func Parse(data []byte) (*Config, error) {
-	cfg := NewConfig()
-
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parsing config: %w", err)
-	}
-
-	// Apply defaults
-	if cfg.Settings == nil {
-		cfg.Settings = DefaultSettings()
-	}
-
-	// Set default merge mode for servers defined in project overrides
-	for _, proj := range cfg.Projects {
-		for _, srv := range proj.Servers {
-			if srv.MergeMode == "" {
-				srv.MergeMode = MergeModeOverlay
-			}
-		}
-	}
-
-	return cfg, nil
-}
-
-// LoadWithMCP loads both mcp.json and config.yaml from a directory and merges them.
-func LoadWithMCP(mcpPath, configPath string) (*Config, error) {
-	// Load MCP servers from mcp.json
-	mcpCfg, err := LoadMCPConfig(mcpPath)
-	if err != nil {
-		return nil, fmt.Errorf("loading mcp config: %w", err)
-	}
-
-	// Load Assern config from config.yaml
-	cfg, err := Load(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// config.yaml is optional, create empty config
-			cfg = NewConfig()
-		} else {
-			return nil, fmt.Errorf("loading config: %w", err)
-		}
-	}
-
-	// Populate servers from MCP config
-	cfg.Servers = mcpCfg.ToServerConfigs()
-
-	return cfg, nil
-}
-
-// LoadGlobal loads the global configuration from ~/.valksor/assern/.
-func LoadGlobal() (*Config, error) {
-	mcpPath, err := GlobalMCPPath()
-	if err != nil {
-		return nil, err
-	}
-
-	configPath, err := GlobalConfigPath()
-	if err != nil {
-		return nil, err
-	}
-
-	return LoadWithMCP(mcpPath, configPath)
-}
-
-// LoadEffective loads all configuration sources and builds the effective config.
-// It loads global mcp.json, global config.yaml, and optionally local .assern/ configs.
-// The projectName is used to apply project-specific overrides from global config.
-func LoadEffective(workDir, projectName string) (*Config, error) {
-	// Load global MCP config
-	globalMCPPath, err := GlobalMCPPath()
-	if err != nil {
-		return nil, fmt.Errorf("getting global mcp path: %w", err)
-	}
-
-	globalMCP, err := LoadMCPConfig(globalMCPPath)
-	if err != nil {
-		return nil, fmt.Errorf("loading global mcp config: %w", err)
-	}
-
-	// Load global Assern config
-	globalConfigPath, err := GlobalConfigPath()
-	if err != nil {
-		return nil, fmt.Errorf("getting global config path: %w", err)
-	}
-
-	var globalConfig *Config
-	if FileExists(globalConfigPath) {
-		globalConfig, err = Load(globalConfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("loading global config: %w", err)
-		}
-	}
-
-	// Try to find local .assern directory
-	var localMCP *MCPConfig
-	var localConfig *LocalProjectConfig
-
-	localDir := FindLocalConfigDir(workDir)
-	if localDir != "" {
-		// Load local MCP config if exists
-		localMCPPath := LocalMCPPath(localDir)
-		if FileExists(localMCPPath) {
-			localMCP, err = LoadMCPConfig(localMCPPath)
-			if err != nil {
-				return nil, fmt.Errorf("loading local mcp config: %w", err)
-			}
-		}
-
-		// Load local config if exists
-		localConfigPath := LocalConfigPath(localDir)
-		if FileExists(localConfigPath) {
-			localConfig, err = LoadLocalProject(localConfigPath)
-			if err != nil {
-				return nil, fmt.Errorf("loading local config: %w", err)
-			}
-
-			// Use project name from local config if not specified
-			if projectName == "" && localConfig.Project != "" {
-				projectName = localConfig.Project
-			}
-		}
-	}
-
-	// Build effective config using all sources
-	return BuildEffectiveConfig(globalMCP, globalConfig, localMCP, localConfig, projectName), nil
-}
-
-// LoadLocalProject reads a project-local .assern/config.yaml file.
-func LoadLocalProject(path string) (*LocalProjectConfig, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("reading local project config: %w", err)
-	}
-
-	var cfg LocalProjectConfig
-
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parsing local project config: %w", err)
-	}
-
-	return &cfg, nil
-}
-
-// Save writes the configuration to the given path.
+// Save writes the configuration to the given path, with a comment that
+// points YAML editors at its schema.
 func (c *Config) Save(path string) error {
 	// Ensure directory exists. Owner-only: config may hold OAuth secrets.
 	dir := filepath.Dir(path)
@@ -384,6 +322,9 @@ func (c *Config) Save(path string) error {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
 
+	// Point editors at the schema, for completion and validation
+	data = append([]byte(SchemaComment(SchemaConfig)+"\n"), data...)
+
 	// 0600: config can contain client secrets and credential headers.
 	if err := os.WriteFile(path, data, 0o600); err != nil {
 		return fmt.Errorf("writing config file: %w", err)
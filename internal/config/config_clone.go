@@ -26,12 +26,16 @@ func (o *OAuthConfig) Clone() *OAuthConfig {
 	}
 
 	clone := &OAuthConfig{
-		ClientID:              o.ClientID,
-		ClientSecret:          o.ClientSecret,
-		RedirectURI:           o.RedirectURI,
-		Scopes:                make([]string, len(o.Scopes)),
-		AuthServerMetadataURL: o.AuthServerMetadataURL,
-		PKCEEnabled:           o.PKCEEnabled,
+		ClientID:               o.ClientID,
+		ClientSecret:           o.ClientSecret,
+		RedirectURI:            o.RedirectURI,
+		Scopes:                 make([]string, len(o.Scopes)),
+		AuthServerMetadataURL:  o.AuthServerMetadataURL,
+		PKCEEnabled:            o.PKCEEnabled,
+		DeviceFlow:             o.DeviceFlow,
+		DeviceAuthorizationURL: o.DeviceAuthorizationURL,
+		RefreshBefore:          o.RefreshBefore,
+		ClockSkew:              o.ClockSkew,
 	}
 
 	copy(clone.Scopes, o.Scopes)
@@ -68,6 +72,86 @@ func (d *DiscoveryConfig) Clone() *DiscoveryConfig {
 	}
 }
 
+// Clone creates a deep copy of the socket configuration.
+func (s *SocketConfig) Clone() *SocketConfig {
+	if s == nil {
+		return nil
+	}
+
+	return &SocketConfig{
+		MaxSessions:          s.MaxSessions,
+		MaxConnectionsPerUID: s.MaxConnectionsPerUID,
+		AllowedUIDs:          slices.Clone(s.AllowedUIDs),
+	}
+}
+
+// Clone creates a deep copy of the metrics configuration.
+func (m *MetricsConfig) Clone() *MetricsConfig {
+	if m == nil {
+		return nil
+	}
+
+	clone := &MetricsConfig{Interval: m.Interval}
+
+	if m.StatsD != nil {
+		clone.StatsD = &StatsDConfig{
+			Enabled:   m.StatsD.Enabled,
+			Address:   m.StatsD.Address,
+			Prefix:    m.StatsD.Prefix,
+			DogStatsD: m.StatsD.DogStatsD,
+			Tags:      slices.Clone(m.StatsD.Tags),
+		}
+	}
+
+	return clone
+}
+
+// Clone creates a deep copy of the events configuration.
+func (e *EventsConfig) Clone() *EventsConfig {
+	if e == nil {
+		return nil
+	}
+
+	clone := &EventsConfig{Sinks: make([]*EventSinkConfig, 0, len(e.Sinks))}
+
+	for _, sink := range e.Sinks {
+		if sink == nil {
+			continue
+		}
+
+		clone.Sinks = append(clone.Sinks, &EventSinkConfig{
+			Type:    sink.Type,
+			Events:  slices.Clone(sink.Events),
+			Timeout: sink.Timeout,
+			URL:     sink.URL,
+			Format:  sink.Format,
+			Headers: maps.Clone(sink.Headers),
+			Command: sink.Command,
+			Args:    slices.Clone(sink.Args),
+		})
+	}
+
+	return clone
+}
+
+// Clone creates a copy of the health check configuration. Argument values
+// are copied shallowly; they are treated as read-only.
+func (h *HealthCheckConfig) Clone() *HealthCheckConfig {
+	if h == nil {
+		return nil
+	}
+
+	return &HealthCheckConfig{
+		Interval:  h.Interval,
+		Timeout:   h.Timeout,
+		Tool:      h.Tool,
+		Arguments: maps.Clone(h.Arguments),
+		Ping:      h.Ping,
+		Reconnect: h.Reconnect,
+		HideTools: h.HideTools,
+	}
+}
+
 // Clone creates a deep copy of the configuration.
 func (c *Config) Clone() *Config {
 	if c == nil {
@@ -94,16 +178,51 @@ func (c *Config) Clone() *Config {
 		}
 	}
 
+	clone.Groups = cloneGroups(c.Groups)
+	clone.Profile = c.Profile
+
 	// Clone settings
 	if c.Settings != nil {
 		clone.Settings = &Settings{
-			LogLevel:     c.Settings.LogLevel,
-			LogFile:      c.Settings.LogFile,
-			Timeout:      c.Settings.Timeout,
-			OutputFormat: c.Settings.OutputFormat,
-			Aliases:      make(map[string]string, len(c.Settings.Aliases)),
-			Discovery:    c.Settings.Discovery.Clone(),
-			CodeMode:     c.Settings.CodeMode.Clone(),
+			InstanceName:         c.Settings.InstanceName,
+			LogLevel:             c.Settings.LogLevel,
+			LogFile:              c.Settings.LogFile,
+			Timeout:              c.Settings.Timeout,
+			OutputFormat:         c.Settings.OutputFormat,
+			Instructions:         c.Settings.Instructions,
+			InstructionsSummary:  c.Settings.InstructionsSummary,
+			Aliases:              make(map[string]string, len(c.Settings.Aliases)),
+			Discovery:            c.Settings.Discovery.Clone(),
+			CodeMode:             c.Settings.CodeMode.Clone(),
+			Socket:               c.Settings.Socket.Clone(),
+			Metrics:              c.Settings.Metrics.Clone(),
+			Events:               c.Settings.Events.Clone(),
+			Features:             maps.Clone(c.Settings.Features),
+			Listen:               c.Settings.Listen,
+			AuditLog:             c.Settings.AuditLog.Clone(),
+			PrefixStrategy:       c.Settings.PrefixStrategy,
+			PrefixCollision:      c.Settings.PrefixCollision,
+			WatchConfig:          c.Settings.WatchConfig,
+			DrainTimeout:         c.Settings.DrainTimeout,
+			SecretsStore:         c.Settings.SecretsStore,
+			IDs:                  c.Settings.IDs.Clone(),
+			SchemaRefs:           c.Settings.SchemaRefs.Clone(),
+			PriorityMarker:       c.Settings.PriorityMarker,
+			HealthCheck:          c.Settings.HealthCheck.Clone(),
+			CallTimeout:          c.Settings.CallTimeout.Clone(),
+			ACL:                  c.Settings.ACL.Clone(),
+			Clients:              cloneClients(c.Settings.Clients),
+			OTel:                 c.Settings.OTel.Clone(),
+			Stdio:                c.Settings.Stdio.Clone(),
+			WebUI:                c.Settings.WebUI.Clone(),
+			ServerLogs:           c.Settings.ServerLogs.Clone(),
+			ToolExposure:         c.Settings.ToolExposure,
+			MaxDescriptionLength: c.Settings.MaxDescriptionLength,
+			StripSchemaExamples:  c.Settings.StripSchemaExamples,
+			PageSize:             c.Settings.PageSize,
+			MaxResourceSize:      c.Settings.MaxResourceSize,
+			ContainerEngine:      c.Settings.ContainerEngine,
+			Strict:               c.Settings.Strict,
 		}
 		maps.Copy(clone.Settings.Aliases, c.Settings.Aliases)
 	}
@@ -118,19 +237,50 @@ func (s *ServerConfig) Clone() *ServerConfig {
 	}
 
 	clone := &ServerConfig{
-		Command:   s.Command,
-		Args:      make([]string, len(s.Args)),
-		Env:       make(map[string]string, len(s.Env)),
-		WorkDir:   s.WorkDir,
-		URL:       s.URL,
-		Headers:   make(map[string]string, len(s.Headers)),
-		OAuth:     s.OAuth.Clone(),
-		OAuthRef:  s.OAuthRef,
-		Transport: s.Transport,
-		Retry:     s.Retry.Clone(),
-		Allowed:   make([]string, len(s.Allowed)),
-		Disabled:  s.Disabled,
-		MergeMode: s.MergeMode,
+		Command:              s.Command,
+		Args:                 make([]string, len(s.Args)),
+		Env:                  make(map[string]string, len(s.Env)),
+		WorkDir:              s.WorkDir,
+		Encoding:             s.Encoding,
+		Locale:               s.Locale,
+		Image:                s.Image,
+		Volumes:              slices.Clone(s.Volumes),
+		Network:              s.Network,
+		URL:                  s.URL,
+		Headers:              make(map[string]string, len(s.Headers)),
+		OAuth:                s.OAuth.Clone(),
+		OAuthRef:             s.OAuthRef,
+		Transport:            s.Transport,
+		Retry:                s.Retry.Clone(),
+		IdempotencyKeys:      maps.Clone(s.IdempotencyKeys),
+		ForwardHeaders:       maps.Clone(s.ForwardHeaders),
+		Coalesce:             s.Coalesce,
+		MaxConcurrency:       s.MaxConcurrency,
+		Queue:                s.Queue,
+		Sampling:             s.Sampling,
+		DryRun:               s.DryRun,
+		RestartPolicy:        s.RestartPolicy,
+		Lazy:                 s.Lazy,
+		Tools:                cloneToolDeclarations(s.Tools),
+		Deprecated:           s.Deprecated,
+		DeprecatedTools:      maps.Clone(s.DeprecatedTools),
+		Priority:             s.Priority,
+		ToolPriority:         maps.Clone(s.ToolPriority),
+		ToolDescriptions:     maps.Clone(s.ToolDescriptions),
+		MaxDescriptionLength: s.MaxDescriptionLength,
+		StripSchemaExamples:  s.StripSchemaExamples,
+		Health:               s.Health.Clone(),
+		CallTimeout:          s.CallTimeout.Clone(),
+		Maintenance:          slices.Clone(s.Maintenance),
+		AllowedResources:     s.AllowedResources.Clone(),
+		ResourceCache:        s.ResourceCache.Clone(),
+		BinaryContent:        s.BinaryContent.Clone(),
+		Prompts:              s.Prompts.Clone(),
+		Prefix:               s.Prefix,
+		Allowed:              make([]string, len(s.Allowed)),
+		Denied:               slices.Clone(s.Denied),
+		Disabled:             s.Disabled,
+		MergeMode:            s.MergeMode,
 	}
 
 	copy(clone.Args, s.Args)
@@ -153,6 +303,10 @@ func (p *ProjectConfig) Clone() *ProjectConfig {
 		Directories: make([]string, len(p.Directories)),
 		Env:         make(map[string]string, len(p.Env)),
 		Servers:     make(map[string]*ServerConfig, len(p.Servers)),
+		Remotes:     slices.Clone(p.Remotes),
+		Priority:    p.Priority,
+		Settings:    p.Settings.Clone(),
+		Profile:     p.Profile,
 	}
 
 	copy(clone.Directories, p.Directories)
@@ -165,3 +319,16 @@ func (p *ProjectConfig) Clone() *ProjectConfig {
 
 	return clone
 }
+
+// Clone creates a copy of the settings override.
+func (o *SettingsOverride) Clone() *SettingsOverride {
+	if o == nil {
+		return nil
+	}
+
+	clone := *o
+	clone.Features = maps.Clone(o.Features)
+	clone.Aliases = maps.Clone(o.Aliases)
+
+	return &clone
+}
@@ -1,8 +1,10 @@
 package config_test
 
 import (
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,7 +34,7 @@ settings:
   timeout: 120s
 `
 
-	cfg, err := config.Parse([]byte(yaml))
+	cfg, err := config.Parse([]byte(yaml), config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("Parse failed: %v", err)
 	}
@@ -157,7 +159,7 @@ func TestLoadAndSave(t *testing.T) {
 	}
 
 	// Load
-	loaded, err := config.Load(cfgPath)
+	loaded, err := config.Load(cfgPath, config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("Load failed: %v", err)
 	}
@@ -215,7 +217,7 @@ func TestLoadWithMCP(t *testing.T) {
 	}
 
 	// Load with MCP
-	loaded, err := config.LoadWithMCP(mcpPath, cfgPath)
+	loaded, err := config.LoadWithMCP(mcpPath, cfgPath, config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("LoadWithMCP failed: %v", err)
 	}
@@ -301,6 +303,81 @@ func TestSettingsClone(t *testing.T) {
 	}
 }
 
+func TestSettingsEffectiveDrainTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings *config.Settings
+		want     time.Duration
+	}{
+		{name: "nil uses default", settings: nil, want: config.DefaultDrainTimeout},
+		{name: "zero uses default", settings: &config.Settings{}, want: config.DefaultDrainTimeout},
+		{name: "negative means no wait", settings: &config.Settings{DrainTimeout: -time.Second}, want: 0},
+		{name: "explicit", settings: &config.Settings{DrainTimeout: 45 * time.Second}, want: 45 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.settings.EffectiveDrainTimeout(); got != tt.want {
+				t.Errorf("EffectiveDrainTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthConfigEffectiveRefreshBefore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		oauth *config.OAuthConfig
+		want  time.Duration
+	}{
+		{name: "nil uses default", oauth: nil, want: config.DefaultOAuthRefreshBefore},
+		{name: "zero uses default", oauth: &config.OAuthConfig{}, want: config.DefaultOAuthRefreshBefore},
+		{name: "negative refreshes on expiry", oauth: &config.OAuthConfig{RefreshBefore: -time.Second}, want: 0},
+		{name: "explicit", oauth: &config.OAuthConfig{RefreshBefore: 5 * time.Minute}, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.oauth.EffectiveRefreshBefore(); got != tt.want {
+				t.Errorf("EffectiveRefreshBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthConfigEffectiveClockSkew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		oauth *config.OAuthConfig
+		want  time.Duration
+	}{
+		{name: "nil uses default", oauth: nil, want: config.DefaultOAuthClockSkew},
+		{name: "zero uses default", oauth: &config.OAuthConfig{}, want: config.DefaultOAuthClockSkew},
+		{name: "negative trusts the local clock", oauth: &config.OAuthConfig{ClockSkew: -time.Second}, want: 0},
+		{name: "explicit", oauth: &config.OAuthConfig{ClockSkew: 2 * time.Minute}, want: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.oauth.EffectiveClockSkew(); got != tt.want {
+				t.Errorf("EffectiveClockSkew() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseOutputFormat(t *testing.T) {
 	t.Parallel()
 
@@ -339,7 +416,7 @@ settings:
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			cfg, err := config.Parse([]byte(tt.yaml))
+			cfg, err := config.Parse([]byte(tt.yaml), config.LoadOptions{})
 			if err != nil {
 				t.Fatalf("Parse failed: %v", err)
 			}
@@ -357,6 +434,71 @@ settings:
 	}
 }
 
+func TestParseToolExposure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr bool
+	}{
+		{name: "unset", yaml: "settings:\n  log_level: debug\n"},
+		{name: "all", yaml: "settings:\n  tool_exposure: all\n", want: config.ToolExposureAll},
+		{name: "search", yaml: "settings:\n  tool_exposure: search\n", want: config.ToolExposureSearch},
+		{name: "unknown", yaml: "settings:\n  tool_exposure: lazy\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.Parse([]byte(tt.yaml), config.LoadOptions{})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "settings.tool_exposure") {
+					t.Fatalf("Parse() error = %v, want a settings.tool_exposure error", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if cfg.Settings.ToolExposure != tt.want {
+				t.Errorf("ToolExposure = %q, want %q", cfg.Settings.ToolExposure, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePageSize(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.Parse([]byte("settings:\n  page_size: 50\n"), config.LoadOptions{})
+	if err != nil || cfg.Settings.PageSize != 50 {
+		t.Fatalf("Parse() = %v, %v; want page_size 50", cfg, err)
+	}
+
+	if _, err := config.Parse([]byte("settings:\n  page_size: -1\n"), config.LoadOptions{}); err == nil || !strings.Contains(err.Error(), "settings.page_size") {
+		t.Errorf("Parse() error = %v, want a settings.page_size error", err)
+	}
+}
+
+func TestParseMaxResourceSize(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.Parse([]byte("settings:\n  max_resource_size: 50MiB\n"), config.LoadOptions{})
+	if err != nil || cfg.Settings.MaxResourceSize != 50<<20 {
+		t.Fatalf("Parse() = %v, %v; want max_resource_size 50MiB", cfg, err)
+	}
+
+	if _, err := config.Parse([]byte("settings:\n  max_resource_size: -1\n"), config.LoadOptions{}); err == nil || !strings.Contains(err.Error(), "max_resource_size") {
+		t.Errorf("Parse() error = %v, want a max_resource_size error", err)
+	}
+}
+
 func TestMCPConfig_URLBased(t *testing.T) {
 	t.Parallel()
 
@@ -388,7 +530,7 @@ func TestMCPConfig_URLBased(t *testing.T) {
 	}
 
 	// Load MCP config
-	mcpCfg, err := config.LoadMCPConfig(mcpPath)
+	mcpCfg, err := config.LoadMCPConfig(mcpPath, config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("LoadMCPConfig failed: %v", err)
 	}
@@ -493,7 +635,7 @@ func TestMCPConfig_Headers(t *testing.T) {
   }
 }`
 
-	cfg, err := config.ParseMCPConfig([]byte(mcpJSON))
+	cfg, err := config.ParseMCPConfig([]byte(mcpJSON), config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("ParseMCPConfig failed: %v", err)
 	}
@@ -516,6 +658,61 @@ func TestMCPConfig_Headers(t *testing.T) {
 	}
 }
 
+func TestReadMCPConfig(t *testing.T) {
+	t.Parallel()
+
+	in := strings.NewReader(`{"mcpServers": {"fs": {"command": "npx", "args": ["fs"]}}}
+{"jsonrpc":"2.0","id":1,"method":"ping"}
+`)
+
+	mcpCfg, rest, err := config.ReadMCPConfig(in, config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("ReadMCPConfig() error = %v", err)
+	}
+
+	if server := mcpCfg.MCPServers["fs"]; server == nil || server.Command != "npx" {
+		t.Fatalf("MCPServers[fs] = %+v, want command npx", server)
+	}
+
+	// The stream after the document starts at the next message
+	remaining, err := io.ReadAll(rest)
+	if err != nil {
+		t.Fatalf("reading remainder: %v", err)
+	}
+
+	if want := `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"; string(remaining) != want {
+		t.Errorf("remainder = %q, want %q", remaining, want)
+	}
+
+	cfg := config.NewEphemeralConfig(mcpCfg)
+	if cfg.Servers["fs"] == nil || cfg.Settings == nil {
+		t.Errorf("NewEphemeralConfig() = %+v, want fs server and default settings", cfg)
+	}
+}
+
+func TestReadMCPConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "truncated", input: `{"mcpServers": {`},
+		{name: "comments", input: "// servers\n{\"mcpServers\": {}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, _, err := config.ReadMCPConfig(strings.NewReader(tt.input), config.LoadOptions{}); err == nil {
+				t.Error("ReadMCPConfig() error = nil, want error")
+			}
+		})
+	}
+}
+
 func TestMCPConfig_OAuth(t *testing.T) {
 	t.Parallel()
 
@@ -536,7 +733,7 @@ func TestMCPConfig_OAuth(t *testing.T) {
   }
 }`
 
-	cfg, err := config.ParseMCPConfig([]byte(mcpJSON))
+	cfg, err := config.ParseMCPConfig([]byte(mcpJSON), config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("ParseMCPConfig failed: %v", err)
 	}
@@ -0,0 +1,31 @@
+package config
+
+import "fmt"
+
+// Container engines, for settings.container_engine.
+const (
+	// ContainerEngineDocker runs container servers with docker (default).
+	ContainerEngineDocker = "docker"
+	// ContainerEnginePodman runs container servers with podman.
+	ContainerEnginePodman = "podman"
+)
+
+// ValidateContainerEngine checks settings.container_engine.
+func ValidateContainerEngine(engine string) error {
+	switch engine {
+	case "", ContainerEngineDocker, ContainerEnginePodman:
+		return nil
+	}
+
+	return fmt.Errorf("%q is not %q or %q", engine, ContainerEngineDocker, ContainerEnginePodman)
+}
+
+// EffectiveContainerEngine returns the engine that runs container servers:
+// settings.container_engine, or docker when it is not set.
+func (s *Settings) EffectiveContainerEngine() string {
+	if s == nil || s.ContainerEngine == "" {
+		return ContainerEngineDocker
+	}
+
+	return s.ContainerEngine
+}
@@ -0,0 +1,77 @@
+package config_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestParseContainerEngine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr bool
+	}{
+		{name: "unset", yaml: "settings:\n  log_level: debug\n", want: config.ContainerEngineDocker},
+		{name: "docker", yaml: "settings:\n  container_engine: docker\n", want: config.ContainerEngineDocker},
+		{name: "podman", yaml: "settings:\n  container_engine: podman\n", want: config.ContainerEnginePodman},
+		{name: "unknown", yaml: "settings:\n  container_engine: lxc\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.Parse([]byte(tt.yaml), config.LoadOptions{})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "settings.container_engine") {
+					t.Fatalf("Parse() error = %v, want a settings.container_engine error", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := cfg.Settings.EffectiveContainerEngine(); got != tt.want {
+				t.Errorf("EffectiveContainerEngine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerServerConfig(t *testing.T) {
+	t.Parallel()
+
+	mcp := &config.MCPConfig{MCPServers: map[string]*config.MCPServer{
+		"fetch": {Image: "mcp/fetch", Volumes: []string{"${HOME}/data:/data:ro"}, Network: "none"},
+	}}
+
+	srv := mcp.ToServerConfigs()["fetch"]
+	if srv.Image != "mcp/fetch" || srv.Network != "none" || !slices.Equal(srv.Volumes, []string{"${HOME}/data:/data:ro"}) {
+		t.Fatalf("ToServerConfigs() = %+v, want the container fields", srv)
+	}
+
+	clone := srv.Clone()
+	clone.Volumes[0] = "/tmp:/data"
+
+	if srv.Volumes[0] != "${HOME}/data:/data:ro" {
+		t.Error("Clone() shares the volumes of the original")
+	}
+
+	if srv.Equal(clone) {
+		t.Error("Equal() ignores volumes")
+	}
+
+	expanded := srv.WithExpandedReferences(func(string) string { return "/home/dev" })
+	if expanded.Volumes[0] != "/home/dev/data:/data:ro" {
+		t.Errorf("expanded volume = %q, want the reference expanded", expanded.Volumes[0])
+	}
+}
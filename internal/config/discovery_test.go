@@ -122,7 +122,7 @@ settings:
     max_loaded: 25
 `
 
-	cfg, err := config.Parse([]byte(yaml))
+	cfg, err := config.Parse([]byte(yaml), config.LoadOptions{})
 	if err != nil {
 		t.Fatalf("Parse() error = %v", err)
 	}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// LookupEncoding returns the character encoding named by a stdio server's
+// encoding setting, e.g. "windows-1252", "shift_jis", "gbk" or "utf-16le",
+// with the aliases of the WHATWG Encoding Standard ("latin1", "cp1251",
+// "sjis"...). It returns nil for "" and UTF-8, which need no transcoding.
+func LookupEncoding(name string) (encoding.Encoding, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown encoding %q", name)
+	}
+
+	if enc == unicode.UTF8 {
+		return nil, nil
+	}
+
+	return enc, nil
+}
+
+// validateEncodings checks the encoding settings of servers defined under
+// path.
+func validateEncodings(path string, servers map[string]*ServerConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil {
+			continue
+		}
+
+		if _, err := LookupEncoding(srv.Encoding); err != nil {
+			return fmt.Errorf("%s.%s.encoding: %w", path, name, err)
+		}
+	}
+
+	return nil
+}
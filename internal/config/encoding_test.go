@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		wantNil  bool
+		wantErr  bool
+		wantName string // Of the encoding, when not nil
+	}{
+		{name: "", wantNil: true},
+		{name: "utf-8", wantNil: true},
+		{name: "UTF8", wantNil: true},
+		{name: "windows-1252", wantName: "Windows 1252"},
+		{name: "latin1", wantName: "Windows 1252"},
+		{name: " Shift_JIS ", wantName: "Shift JIS"},
+		{name: "utf-16le", wantName: "UTF-16LE (Ignore BOM)"},
+		{name: "klingon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			enc, err := LookupEncoding(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupEncoding(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if tt.wantNil {
+				if enc != nil {
+					t.Errorf("LookupEncoding(%q) = %v, want nil", tt.name, enc)
+				}
+
+				return
+			}
+
+			if enc == nil {
+				t.Fatalf("LookupEncoding(%q) = nil", tt.name)
+			}
+
+			if got, ok := enc.(interface{ String() string }); !ok || got.String() != tt.wantName {
+				t.Errorf("LookupEncoding(%q) = %v, want %s", tt.name, enc, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: "servers:\n  legacy:\n    command: legacy-mcp\n    encoding: cp1252\n    locale: de_DE.CP1252\n",
+		},
+		{
+			name:    "unknown",
+			data:    "projects:\n  work:\n    servers:\n      legacy:\n        encoding: klingon\n",
+			wantErr: `projects.work.servers.legacy.encoding: unknown encoding "klingon"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
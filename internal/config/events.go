@@ -0,0 +1,67 @@
+package config
+
+import (
+	"slices"
+	"time"
+)
+
+// Event sink types.
+const (
+	// EventSinkWebhook POSTs each event to a URL.
+	EventSinkWebhook = "webhook"
+	// EventSinkExec runs a local command with the event on stdin.
+	EventSinkExec = "exec"
+	// EventSinkDesktop shows a native desktop notification.
+	EventSinkDesktop = "desktop"
+)
+
+// Webhook payload formats.
+const (
+	// WebhookFormatJSON posts the raw event object.
+	WebhookFormatJSON = "json"
+	// WebhookFormatSlack posts a Slack incoming-webhook {"text": ...} body.
+	WebhookFormatSlack = "slack"
+)
+
+// DefaultEventSinkTimeout bounds a single event delivery.
+const DefaultEventSinkTimeout = 10 * time.Second
+
+// EventsConfig configures where aggregator events (server_started,
+// server_failed, auth_expired, reload_applied, policy_blocked,
+// quota_exceeded) are sent.
+type EventsConfig struct {
+	Sinks []*EventSinkConfig `yaml:"sinks,omitempty"`
+}
+
+// EventSinkConfig describes one event destination.
+type EventSinkConfig struct {
+	// Type is "webhook", "exec" or "desktop".
+	Type string `yaml:"type"`
+	// Events limits the sink to these event types. Empty means all events.
+	Events []string `yaml:"events,omitempty"`
+	// Timeout bounds a single delivery. Zero uses DefaultEventSinkTimeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Webhook fields. URL and header values support ${VAR} expansion.
+	URL     string            `yaml:"url,omitempty"`
+	Format  string            `yaml:"format,omitempty"` // "json" (default) or "slack"
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Exec fields. The event is written to stdin as JSON.
+	Command string   `yaml:"command,omitempty"`
+	Args    []string `yaml:"args,omitempty"`
+}
+
+// Wants reports whether the sink subscribes to the given event type.
+func (s *EventSinkConfig) Wants(eventType string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, eventType)
+}
+
+// EffectiveTimeout returns the delivery timeout, applying the default.
+func (s *EventSinkConfig) EffectiveTimeout() time.Duration {
+	if s == nil || s.Timeout <= 0 {
+		return DefaultEventSinkTimeout
+	}
+
+	return s.Timeout
+}
@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestParseEventsSettings(t *testing.T) {
+	t.Parallel()
+
+	yaml := `
+settings:
+  events:
+    sinks:
+      - type: webhook
+        url: "${HOOK_URL}"
+        format: slack
+        events: [server_failed]
+        headers:
+          Authorization: "Bearer ${TOKEN}"
+      - type: exec
+        command: notify
+        timeout: 2s
+`
+
+	cfg, err := config.Parse([]byte(yaml), config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	sinks := cfg.Settings.Events.Sinks
+	if len(sinks) != 2 {
+		t.Fatalf("sinks = %d, want 2", len(sinks))
+	}
+
+	hook, exec := sinks[0], sinks[1]
+
+	if !hook.Wants("server_failed") || hook.Wants("server_started") {
+		t.Error("webhook event filter not applied")
+	}
+
+	if !exec.Wants("reload_applied") {
+		t.Error("sink without events filter should want every event")
+	}
+
+	if got := hook.EffectiveTimeout(); got != config.DefaultEventSinkTimeout {
+		t.Errorf("webhook timeout = %v, want default", got)
+	}
+
+	if got := exec.EffectiveTimeout(); got != 2*time.Second {
+		t.Errorf("exec timeout = %v, want 2s", got)
+	}
+
+	clone := cfg.Clone().Settings.Events
+	clone.Sinks[0].Headers["Authorization"] = "changed"
+
+	if hook.Headers["Authorization"] != "Bearer ${TOKEN}" {
+		t.Error("Clone() did not deep-copy sink headers")
+	}
+}
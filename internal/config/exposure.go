@@ -0,0 +1,23 @@
+package config
+
+import "fmt"
+
+// Tool exposure modes, for settings.tool_exposure.
+const (
+	// ToolExposureAll lists every aggregated tool in tools/list (default).
+	ToolExposureAll = "all"
+	// ToolExposureSearch lists only the assern_search_tools,
+	// assern_describe_tool and assern_call_tool meta-tools, through which
+	// the client finds and calls the aggregated tools.
+	ToolExposureSearch = "search"
+)
+
+// ValidateToolExposure checks settings.tool_exposure.
+func ValidateToolExposure(exposure string) error {
+	switch exposure {
+	case "", ToolExposureAll, ToolExposureSearch:
+		return nil
+	}
+
+	return fmt.Errorf("%q is not %q or %q", exposure, ToolExposureAll, ToolExposureSearch)
+}
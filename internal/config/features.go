@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Feature flag names, set under settings.features in config.yaml.
+const (
+	// FeatureLazyStart honours lazy: true on servers (see ServerConfig.Lazy).
+	// When off, lazy servers are started at startup like any other.
+	FeatureLazyStart = "lazy_start"
+	// FeatureTOONDefault formats tool results as TOON when the output format
+	// would otherwise be the JSON default.
+	FeatureTOONDefault = "toon_default"
+	// FeatureMetaTools exposes the assern_* discovery, code-mode and
+	// tool_exposure: search meta-tools when those subsystems are enabled.
+	FeatureMetaTools = "meta_tools"
+)
+
+// Feature describes a flag gating an experimental subsystem.
+type Feature struct {
+	Name        string
+	Description string
+	// Default is the state of the flag when the config does not set it.
+	Default bool
+	// Restart is set for flags that are read once at startup. They cannot
+	// be flipped on a running instance; change them in config and restart.
+	Restart bool
+}
+
+// Features lists the known feature flags.
+var Features = []Feature{
+	{
+		Name:        FeatureLazyStart,
+		Description: "Start servers marked lazy on their first tool call",
+	},
+	{
+		Name:        FeatureTOONDefault,
+		Description: "Format tool results as TOON instead of the JSON default",
+	},
+	{
+		Name:        FeatureMetaTools,
+		Description: "Expose the discovery, code-mode and search meta-tools",
+		Default:     true,
+		Restart:     true,
+	},
+}
+
+// LookupFeature returns the feature flag with the given name.
+func LookupFeature(name string) (Feature, bool) {
+	i := slices.IndexFunc(Features, func(f Feature) bool { return f.Name == name })
+	if i < 0 {
+		return Feature{}, false
+	}
+
+	return Features[i], true
+}
+
+// FeatureEnabled reports whether a feature flag is on: the configured value
+// if set, else the flag's default. Safe on nil settings.
+func (s *Settings) FeatureEnabled(name string) bool {
+	if s != nil {
+		if enabled, ok := s.Features[name]; ok {
+			return enabled
+		}
+	}
+
+	f, _ := LookupFeature(name)
+
+	return f.Default
+}
+
+// validateFeatures rejects unknown names in the features section at path.
+func validateFeatures(path string, features map[string]bool) error {
+	for _, name := range slices.Sorted(maps.Keys(features)) {
+		if _, ok := LookupFeature(name); !ok {
+			return fmt.Errorf("%s: unknown feature %q (known: %s)", path, name, featureNames())
+		}
+	}
+
+	return nil
+}
+
+func featureNames() string {
+	names := make([]string, len(Features))
+	for i, f := range Features {
+		names[i] = f.Name
+	}
+
+	return strings.Join(names, ", ")
+}
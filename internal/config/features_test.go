@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFeatures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "known flags",
+			yaml: "settings:\n  features:\n    lazy_start: true\n    toon_default: false\n",
+		},
+		{
+			name:    "unknown flag",
+			yaml:    "settings:\n  features:\n    warp_drive: true\n",
+			wantErr: `settings.features: unknown feature "warp_drive"`,
+		},
+		{
+			name:    "unknown flag in project",
+			yaml:    "projects:\n  web:\n    settings:\n      features:\n        warp_drive: true\n",
+			wantErr: `projects.web.settings.features: unknown feature "warp_drive"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.yaml), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeatureEnabled(t *testing.T) {
+	t.Parallel()
+
+	var unset *Settings
+	if unset.FeatureEnabled(FeatureLazyStart) || !unset.FeatureEnabled(FeatureMetaTools) {
+		t.Error("nil settings do not report flag defaults")
+	}
+
+	s := &Settings{Features: map[string]bool{FeatureMetaTools: false}}
+	if s.FeatureEnabled(FeatureMetaTools) {
+		t.Error("configured value does not override default")
+	}
+
+	override := &SettingsOverride{Features: map[string]bool{FeatureLazyStart: true}}
+	override.applyTo(s)
+
+	if !s.FeatureEnabled(FeatureLazyStart) || s.FeatureEnabled(FeatureMetaTools) {
+		t.Errorf("after project override, features = %v", s.Features)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+// ForwardedHeader maps an incoming HTTP request header onto the tool calls
+// of a server (see ServerConfig.ForwardHeaders).
+type ForwardedHeader struct {
+	// Header is the canonical header name, e.g. "X-User".
+	Header string
+	// Name is the argument or _meta key that carries the header value.
+	Name string
+	// Meta sends the value in the request _meta instead of the arguments.
+	Meta bool
+}
+
+// ForwardedHeaders returns the headers to forward to the server's tool
+// calls, sorted by header name. s may be nil.
+func (s *ServerConfig) ForwardedHeaders() []ForwardedHeader {
+	if s == nil || len(s.ForwardHeaders) == 0 {
+		return nil
+	}
+
+	headers := make([]ForwardedHeader, 0, len(s.ForwardHeaders))
+
+	for _, header := range slices.Sorted(maps.Keys(s.ForwardHeaders)) {
+		target := s.ForwardHeaders[header]
+		if target == "" {
+			continue
+		}
+
+		fh := ForwardedHeader{Header: textproto.CanonicalMIMEHeaderKey(header), Name: target}
+		if name, ok := strings.CutPrefix(target, IdempotencyMetaPrefix); ok {
+			fh.Name, fh.Meta = name, true
+		}
+
+		headers = append(headers, fh)
+	}
+
+	return headers
+}
+
+// validateForwardHeaders checks the forward_headers of servers defined
+// under path: each header needs an argument name, or "_meta." followed by
+// one.
+func validateForwardHeaders(path string, servers map[string]*ServerConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil {
+			continue
+		}
+
+		for _, header := range slices.Sorted(maps.Keys(srv.ForwardHeaders)) {
+			target := srv.ForwardHeaders[header]
+			if target == "" || target == IdempotencyMetaPrefix {
+				return fmt.Errorf("%s.%s.forward_headers: header %q needs an argument name or %q followed by a key",
+					path, name, header, IdempotencyMetaPrefix)
+			}
+		}
+	}
+
+	return nil
+}
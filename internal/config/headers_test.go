@@ -0,0 +1,74 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestServerConfigForwardedHeaders(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ServerConfig{ForwardHeaders: map[string]string{
+		"x-user":    "_meta.user",
+		"X-Team-Id": "team",
+	}}
+
+	want := []ForwardedHeader{
+		{Header: "X-Team-Id", Name: "team"},
+		{Header: "X-User", Name: "user", Meta: true},
+	}
+
+	if got := cfg.ForwardedHeaders(); !slices.Equal(got, want) {
+		t.Errorf("ForwardedHeaders() = %+v, want %+v", got, want)
+	}
+
+	var none *ServerConfig
+	if got := none.ForwardedHeaders(); got != nil {
+		t.Errorf("ForwardedHeaders() of nil config = %+v, want nil", got)
+	}
+}
+
+func TestParseForwardHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "argument and meta",
+			data: "projects:\n  work:\n    servers:\n      github:\n        forward_headers:\n          X-User: _meta.user\n          X-Team: team\n",
+		},
+		{
+			name:    "empty target",
+			data:    "projects:\n  work:\n    servers:\n      github:\n        forward_headers:\n          X-User: \"\"\n",
+			wantErr: `projects.work.servers.github.forward_headers: header "X-User" needs an argument name`,
+		},
+		{
+			name:    "meta without key",
+			data:    "projects:\n  work:\n    servers:\n      github:\n        forward_headers:\n          X-User: _meta.\n",
+			wantErr: `header "X-User" needs an argument name or "_meta." followed by a key`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
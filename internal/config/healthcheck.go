@@ -0,0 +1,68 @@
+package config
+
+import "time"
+
+// Health check defaults.
+const (
+	// DefaultHealthCheckInterval is how often a backend is probed.
+	DefaultHealthCheckInterval = 5 * time.Minute
+	// DefaultHealthCheckTimeout bounds a single probe.
+	DefaultHealthCheckTimeout = 10 * time.Second
+)
+
+// HealthCheckConfig declares a periodic probe for a backend server. Probe
+// failures feed the health tracker, and with Reconnect set an unhealthy
+// server is restarted before a user-visible call has to fail.
+type HealthCheckConfig struct {
+	// Interval between probes. Zero uses DefaultHealthCheckInterval.
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// Timeout for a single probe. Zero uses DefaultHealthCheckTimeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Tool is the backend (unprefixed) tool to call, e.g. "ping". When empty
+	// the probe lists the server's tools, or sends an MCP ping with Ping set.
+	Tool string `yaml:"tool,omitempty"`
+	// Arguments passed to Tool.
+	Arguments map[string]any `yaml:"arguments,omitempty"`
+	// Ping probes with an MCP ping request instead of listing tools.
+	Ping bool `yaml:"ping,omitempty"`
+	// Reconnect restarts the server connection once it is marked unhealthy.
+	Reconnect bool `yaml:"reconnect,omitempty"`
+	// HideTools leaves the server's tools out of tools/list while it is
+	// unhealthy; they return, with a tools/list_changed notification, once
+	// it recovers.
+	HideTools bool `yaml:"hide_tools,omitempty"`
+}
+
+// EffectiveHealthCheck returns the health check of a server: its own, or
+// for URL-based (HTTP/SSE) servers without one the settings.health_check
+// default. It returns nil when the server is not probed. s may be nil.
+func EffectiveHealthCheck(s *ServerConfig, settings *Settings) *HealthCheckConfig {
+	switch {
+	case s == nil:
+		return nil
+	case s.Health != nil:
+		return s.Health
+	case s.URL != "" && settings != nil:
+		return settings.HealthCheck
+	default:
+		return nil
+	}
+}
+
+// EffectiveInterval returns the probe interval, applying the default.
+func (h *HealthCheckConfig) EffectiveInterval() time.Duration {
+	if h == nil || h.Interval <= 0 {
+		return DefaultHealthCheckInterval
+	}
+
+	return h.Interval
+}
+
+// EffectiveTimeout returns the probe timeout, applying the default.
+func (h *HealthCheckConfig) EffectiveTimeout() time.Duration {
+	if h == nil || h.Timeout <= 0 {
+		return DefaultHealthCheckTimeout
+	}
+
+	return h.Timeout
+}
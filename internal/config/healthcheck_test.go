@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestEffectiveHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	own := &HealthCheckConfig{Tool: "ping"}
+	defaults := &HealthCheckConfig{Ping: true}
+	settings := &Settings{HealthCheck: defaults}
+
+	tests := []struct {
+		name     string
+		srv      *ServerConfig
+		settings *Settings
+		want     *HealthCheckConfig
+	}{
+		{name: "nil server", srv: nil, settings: settings, want: nil},
+		{name: "own check", srv: &ServerConfig{URL: "https://api.example.com/mcp", Health: own}, settings: settings, want: own},
+		{name: "URL server default", srv: &ServerConfig{URL: "https://api.example.com/mcp"}, settings: settings, want: defaults},
+		{name: "stdio server", srv: &ServerConfig{Command: "github-mcp"}, settings: settings, want: nil},
+		{name: "no default", srv: &ServerConfig{URL: "https://api.example.com/mcp"}, settings: &Settings{}, want: nil},
+		{name: "no settings", srv: &ServerConfig{URL: "https://api.example.com/mcp"}, settings: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := EffectiveHealthCheck(tt.srv, tt.settings); got != tt.want {
+				t.Errorf("EffectiveHealthCheck() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// IdempotencyMetaPrefix marks an idempotency_keys (or forward_headers)
+// target as a key in the request's _meta rather than a tool argument, e.g.
+// "_meta.idempotency_key".
+const IdempotencyMetaPrefix = "_meta."
+
+// IdempotencyKeyTarget is where the idempotency key of a tool call goes.
+type IdempotencyKeyTarget struct {
+	// Name is the argument or _meta key that carries the key.
+	Name string
+	// Meta sends the key in the request _meta instead of the arguments.
+	Meta bool
+}
+
+// IdempotencyKey returns where to put the idempotency key of calls to tool
+// (its unprefixed name), and false when the tool is not configured for one.
+// s may be nil.
+func (s *ServerConfig) IdempotencyKey(tool string) (IdempotencyKeyTarget, bool) {
+	if s == nil {
+		return IdempotencyKeyTarget{}, false
+	}
+
+	target, ok := s.IdempotencyKeys[tool]
+	if !ok || target == "" {
+		return IdempotencyKeyTarget{}, false
+	}
+
+	if name, ok := strings.CutPrefix(target, IdempotencyMetaPrefix); ok {
+		return IdempotencyKeyTarget{Name: name, Meta: true}, true
+	}
+
+	return IdempotencyKeyTarget{Name: target}, true
+}
+
+// validateIdempotencyKeys checks the idempotency_keys of servers defined
+// under path: each tool needs an argument name, or "_meta." followed by one.
+func validateIdempotencyKeys(path string, servers map[string]*ServerConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil {
+			continue
+		}
+
+		for _, tool := range slices.Sorted(maps.Keys(srv.IdempotencyKeys)) {
+			target := srv.IdempotencyKeys[tool]
+			if target == "" || target == IdempotencyMetaPrefix {
+				return fmt.Errorf("%s.%s.idempotency_keys: tool %q needs an argument name or %q followed by a key",
+					path, name, tool, IdempotencyMetaPrefix)
+			}
+		}
+	}
+
+	return nil
+}
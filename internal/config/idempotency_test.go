@@ -0,0 +1,83 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServerConfigIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ServerConfig{IdempotencyKeys: map[string]string{
+		"create_issue": "idempotency_key",
+		"send":         "_meta.idempotencyKey",
+	}}
+
+	tests := []struct {
+		name   string
+		cfg    *ServerConfig
+		tool   string
+		want   IdempotencyKeyTarget
+		wantOK bool
+	}{
+		{name: "nil config", cfg: nil, tool: "create_issue"},
+		{name: "tool not listed", cfg: cfg, tool: "search"},
+		{name: "argument", cfg: cfg, tool: "create_issue", want: IdempotencyKeyTarget{Name: "idempotency_key"}, wantOK: true},
+		{name: "meta", cfg: cfg, tool: "send", want: IdempotencyKeyTarget{Name: "idempotencyKey", Meta: true}, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := tt.cfg.IdempotencyKey(tt.tool)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("IdempotencyKey(%q) = %+v, %v; want %+v, %v", tt.tool, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseIdempotencyKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "argument and meta",
+			data: "projects:\n  work:\n    servers:\n      github:\n        idempotency_keys:\n          create_issue: idempotency_key\n          merge: _meta.idempotencyKey\n",
+		},
+		{
+			name:    "empty target",
+			data:    "projects:\n  work:\n    servers:\n      github:\n        idempotency_keys:\n          create_issue: \"\"\n",
+			wantErr: `projects.work.servers.github.idempotency_keys: tool "create_issue" needs an argument name`,
+		},
+		{
+			name:    "meta without key",
+			data:    "projects:\n  work:\n    servers:\n      github:\n        idempotency_keys:\n          create_issue: _meta.\n",
+			wantErr: `tool "create_issue" needs an argument name or "_meta." followed by a key`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+)
+
+// DefaultInstanceName is the instance name used when settings.instance_name
+// is not set and the hostname cannot be determined.
+const DefaultInstanceName = "assern"
+
+// fingerprintLength is the number of hex characters kept from the hash.
+const fingerprintLength = 12
+
+// InstanceName returns the name an assern instance announces to clients:
+// settings.instance_name, or the hostname when unset.
+func InstanceName(cfg *Config) string {
+	if cfg != nil && cfg.Settings != nil && cfg.Settings.InstanceName != "" {
+		return cfg.Settings.InstanceName
+	}
+
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+
+	return DefaultInstanceName
+}
+
+// Fingerprint returns a short hash of the effective servers and settings, so
+// two instances (or one instance before and after a reload) can be told
+// apart. Equal configurations have equal fingerprints.
+func Fingerprint(cfg *Config) string {
+	if cfg == nil {
+		return ""
+	}
+
+	// Map keys are sorted by encoding/json, so the encoding is stable.
+	data, err := json.Marshal(struct {
+		Servers  map[string]*ServerConfig
+		Settings *Settings
+	}{
+		Servers:  GetEffectiveServers(cfg),
+		Settings: cfg.Settings,
+	})
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestInstanceName(t *testing.T) {
+	t.Parallel()
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = DefaultInstanceName
+	}
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{name: "nil config", cfg: nil, want: host},
+		{name: "no settings", cfg: &Config{}, want: host},
+		{name: "unset", cfg: &Config{Settings: &Settings{}}, want: host},
+		{name: "configured", cfg: &Config{Settings: &Settings{InstanceName: "work"}}, want: "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := InstanceName(tt.cfg); got != tt.want {
+				t.Errorf("InstanceName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	base := func() *Config {
+		cfg := NewConfig()
+		cfg.Servers = map[string]*ServerConfig{
+			"github": {Command: "gh-mcp", Env: map[string]string{"A": "1", "B": "2"}},
+			"remote": {URL: "https://example.com/mcp"},
+		}
+
+		return cfg
+	}
+
+	want := Fingerprint(base())
+	if len(want) != fingerprintLength {
+		t.Fatalf("Fingerprint() = %q, want %d hex characters", want, fingerprintLength)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		same   bool
+	}{
+		{name: "unchanged", mutate: func(*Config) {}, same: true},
+		{name: "disabled server added", mutate: func(c *Config) {
+			c.Servers["off"] = &ServerConfig{Command: "off", Disabled: true}
+		}, same: true},
+		{name: "server args changed", mutate: func(c *Config) {
+			c.Servers["github"].Args = []string{"--verbose"}
+		}},
+		{name: "server removed", mutate: func(c *Config) {
+			delete(c.Servers, "remote")
+		}},
+		{name: "settings changed", mutate: func(c *Config) {
+			c.Settings.OutputFormat = "toon"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := base()
+			tt.mutate(cfg)
+
+			if got := Fingerprint(cfg); (got == want) != tt.same {
+				t.Errorf("Fingerprint() = %q, base %q, want same=%v", got, want, tt.same)
+			}
+		})
+	}
+
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strconv"
+)
+
+// Session ID formats, for settings.ids.session_format.
+const (
+	// SessionFormatUUID ends session IDs in a random UUID (default).
+	SessionFormatUUID = "uuid"
+	// SessionFormatShort ends session IDs in 8 random hex characters.
+	SessionFormatShort = "short"
+	// SessionFormatSequential ends session IDs in a counter starting at 1
+	// for each process, e.g. socket-1, socket-2.
+	SessionFormatSequential = "sequential"
+)
+
+// IDsConfig names the instance and its client sessions in logs, metrics and
+// audit records, so telemetry shipped from many instances to one store can
+// be told apart. Instance and SessionPrefix are templates; see
+// ExpandIDTemplate for the placeholders.
+type IDsConfig struct {
+	// Instance is the instance ID added to every log line, metric and
+	// audit record, e.g. "{env:DEVCONTAINER_ID}" or "{user}@{hostname}".
+	// Nothing is added while it is empty.
+	Instance string `yaml:"instance,omitempty"`
+	// SessionPrefix starts the IDs of socket and HTTP sessions, followed by
+	// the transport and a suffix in SessionFormat, e.g. "{instance}/" gives
+	// "alice@devbox/socket-3f2a9c1e". It may also use {instance}.
+	SessionPrefix string `yaml:"session_prefix,omitempty"`
+	// SessionFormat is SessionFormatUUID (default), SessionFormatShort or
+	// SessionFormatSequential.
+	SessionFormat string `yaml:"session_format,omitempty"`
+}
+
+// CustomSessions reports whether session IDs are configured. Otherwise each
+// transport keeps its own IDs ("stdio", "socket-N", "mcp-session-<uuid>").
+func (c *IDsConfig) CustomSessions() bool {
+	return c != nil && (c.SessionPrefix != "" || c.SessionFormat != "")
+}
+
+// EffectiveSessionFormat returns the session ID format, applying the default.
+func (c *IDsConfig) EffectiveSessionFormat() string {
+	if c == nil || c.SessionFormat == "" {
+		return SessionFormatUUID
+	}
+
+	return c.SessionFormat
+}
+
+// Clone creates a copy of the IDs config.
+func (c *IDsConfig) Clone() *IDsConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	return &clone
+}
+
+// ValidateIDs checks the session format and the placeholders of the
+// templates in an IDs config.
+func ValidateIDs(c *IDsConfig) error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.SessionFormat {
+	case "", SessionFormatUUID, SessionFormatShort, SessionFormatSequential:
+	default:
+		return fmt.Errorf("session_format: invalid format %q (use %s, %s or %s)",
+			c.SessionFormat, SessionFormatUUID, SessionFormatShort, SessionFormatSequential)
+	}
+
+	if err := validateIDTemplate(c.Instance, false); err != nil {
+		return fmt.Errorf("instance: %w", err)
+	}
+
+	if err := validateIDTemplate(c.SessionPrefix, true); err != nil {
+		return fmt.Errorf("session_prefix: %w", err)
+	}
+
+	return nil
+}
+
+// idPlaceholder matches {name} and {env:NAME} in ID templates.
+var idPlaceholder = regexp.MustCompile(`\{([a-z_]+)(?::([^{}]*))?\}`)
+
+// validateIDTemplate rejects unknown placeholders; {instance} is only known
+// in session prefixes.
+func validateIDTemplate(tmpl string, session bool) error {
+	for _, m := range idPlaceholder.FindAllStringSubmatch(tmpl, -1) {
+		switch name, arg := m[1], m[2]; {
+		case name == "env" && arg != "":
+		case name == "instance" && session:
+		case arg == "" && (name == "hostname" || name == "instance_name" || name == "user" || name == "pid"):
+		default:
+			return fmt.Errorf("unknown placeholder %s", m[0])
+		}
+	}
+
+	return nil
+}
+
+// ExpandIDTemplate fills in the placeholders of an ID template: {hostname},
+// {instance_name} (see InstanceName), {user}, {pid}, {env:NAME} and, for
+// session prefixes, {instance} with instanceID. Placeholders that cannot be
+// resolved expand to nothing.
+func ExpandIDTemplate(tmpl string, cfg *Config, instanceID string) string {
+	return idPlaceholder.ReplaceAllStringFunc(tmpl, func(match string) string {
+		m := idPlaceholder.FindStringSubmatch(match)
+
+		switch m[1] {
+		case "hostname":
+			host, _ := os.Hostname()
+
+			return host
+		case "instance_name":
+			return InstanceName(cfg)
+		case "user":
+			if u, err := user.Current(); err == nil {
+				return u.Username
+			}
+
+			return os.Getenv("USER")
+		case "pid":
+			return strconv.Itoa(os.Getpid())
+		case "env":
+			return os.Getenv(m[2])
+		case "instance":
+			return instanceID
+		}
+
+		return match
+	})
+}
+
+// InstanceID returns the expanded settings.ids.instance, or "" when unset.
+func InstanceID(cfg *Config) string {
+	if cfg == nil || cfg.Settings == nil || cfg.Settings.IDs == nil {
+		return ""
+	}
+
+	return ExpandIDTemplate(cfg.Settings.IDs.Instance, cfg, "")
+}
+
+// SessionPrefix returns the expanded settings.ids.session_prefix.
+func SessionPrefix(cfg *Config) string {
+	if cfg == nil || cfg.Settings == nil || cfg.Settings.IDs == nil {
+		return ""
+	}
+
+	return ExpandIDTemplate(cfg.Settings.IDs.SessionPrefix, cfg, InstanceID(cfg))
+}
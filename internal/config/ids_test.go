@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidateIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ids     *IDsConfig
+		wantErr string
+	}{
+		{name: "nil", ids: nil},
+		{name: "all placeholders", ids: &IDsConfig{
+			Instance:      "{user}@{hostname}-{instance_name}-{pid}-{env:POD}",
+			SessionPrefix: "{instance}/",
+			SessionFormat: SessionFormatShort,
+		}},
+		{name: "bad format", ids: &IDsConfig{SessionFormat: "random"}, wantErr: `session_format: invalid format "random"`},
+		{name: "unknown placeholder", ids: &IDsConfig{Instance: "{host}"}, wantErr: "instance: unknown placeholder {host}"},
+		{name: "instance in instance", ids: &IDsConfig{Instance: "{instance}"}, wantErr: "unknown placeholder {instance}"},
+		{name: "env without name", ids: &IDsConfig{SessionPrefix: "{env:}"}, wantErr: "session_prefix: unknown placeholder {env:}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateIDs(tt.ids)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateIDs() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateIDs() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstanceIDAndSessionPrefix(t *testing.T) {
+	t.Setenv("ASSERN_TEST_POD", "pod-7")
+
+	host, _ := os.Hostname()
+
+	cfg := NewConfig()
+	cfg.Settings.InstanceName = "work"
+	cfg.Settings.IDs = &IDsConfig{
+		Instance:      "{env:ASSERN_TEST_POD}.{instance_name}.{hostname}",
+		SessionPrefix: "{instance}:{pid}:",
+	}
+
+	wantID := "pod-7.work." + host
+	if got := InstanceID(cfg); got != wantID {
+		t.Errorf("InstanceID() = %q, want %q", got, wantID)
+	}
+
+	wantPrefix := wantID + ":" + strconv.Itoa(os.Getpid()) + ":"
+	if got := SessionPrefix(cfg); got != wantPrefix {
+		t.Errorf("SessionPrefix() = %q, want %q", got, wantPrefix)
+	}
+
+	if got := InstanceID(NewConfig()); got != "" {
+		t.Errorf("InstanceID() unset = %q, want empty", got)
+	}
+
+	if !cfg.Settings.IDs.CustomSessions() || (&IDsConfig{Instance: "x"}).CustomSessions() {
+		t.Error("CustomSessions() should only report session settings")
+	}
+}
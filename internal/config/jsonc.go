@@ -0,0 +1,76 @@
+package config
+
+import "bytes"
+
+// standardizeJSON converts JSONC (JSON with // and /* */ comments and
+// trailing commas, as accepted by editors and many MCP clients) to strict
+// JSON. Comments and trailing commas are blanked with spaces rather than
+// removed, so byte offsets in decoder errors still point into the original
+// file. Strict JSON passes through unchanged.
+func standardizeJSON(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	// lastComma is the offset of a comma that may turn out to be trailing,
+	// or -1 once a value follows it.
+	lastComma := -1
+
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			i = skipString(out, i)
+			lastComma = -1
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			i = blankBlockComment(out, i)
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+
+			lastComma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			lastComma = -1
+		}
+	}
+
+	return out
+}
+
+// skipString returns the offset of the quote closing the string at start.
+func skipString(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return len(data)
+}
+
+// blankBlockComment blanks a /* */ comment starting at start, keeping
+// newlines so line numbers are preserved, and returns its last offset. An
+// unterminated comment runs to the end of data.
+func blankBlockComment(data []byte, start int) int {
+	end := len(data)
+	if idx := bytes.Index(data[start+2:], []byte("*/")); idx >= 0 {
+		end = start + 2 + idx + 2
+	}
+
+	for i := start; i < end; i++ {
+		if data[i] != '\n' {
+			data[i] = ' '
+		}
+	}
+
+	return end - 1
+}
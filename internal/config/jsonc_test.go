@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStandardizeJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"strict", `{"a": [1, 2]}`, `{"a":[1,2]}`},
+		{"line comment", "{\"a\": 1 // note\n}", `{"a":1}`},
+		{"block comment", "{/* x\ny */\"a\": 1}", `{"a":1}`},
+		{"trailing commas", `{"a": [1, 2,], "b": {"c": 3,},}`, `{"a":[1,2],"b":{"c":3}}`},
+		{"comma before comment", "{\"a\": 1, // last\n}", `{"a":1}`},
+		{"comment markers in strings", `{"url": "https://x/*y*/", "s": "a,}"}`, `{"s":"a,}","url":"https://x/*y*/"}`},
+		{"escaped quote", `{"q": "say \"//hi\"",}`, `{"q":"say \"//hi\""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := standardizeJSON([]byte(tt.input))
+			if len(out) != len(tt.input) {
+				t.Errorf("length changed: %d -> %d", len(tt.input), len(out))
+			}
+
+			var v any
+			if err := json.Unmarshal(out, &v); err != nil {
+				t.Fatalf("invalid JSON %q: %v", out, err)
+			}
+
+			got, _ := json.Marshal(v)
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMCPConfigJSONC(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+  // Copied from another client's docs
+  "mcpServers": {
+    "github": {
+      "command": "gh-mcp", /* inline */
+      "args": ["--stdio",],
+    },
+  },
+}`)
+
+	cfg, err := ParseMCPConfig(data, LoadOptions{})
+	if err != nil {
+		t.Fatalf("ParseMCPConfig() error = %v", err)
+	}
+
+	if srv := cfg.MCPServers["github"]; srv == nil || srv.Command != "gh-mcp" || len(srv.Args) != 1 {
+		t.Errorf("github = %+v", srv)
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+)
+
+// ToolDeclaration declares a tool of a lazy server so it can be advertised
+// before the server is started. InputSchema is the tool's JSON schema; when
+// empty the tool takes an object with no declared properties.
+type ToolDeclaration struct {
+	Name        string         `yaml:"name" json:"name"`
+	Description string         `yaml:"description,omitempty" json:"description,omitempty"`
+	InputSchema map[string]any `yaml:"input_schema,omitempty" json:"inputSchema,omitempty"`
+}
+
+// cloneToolDeclarations copies a tool declaration list. Schemas are copied
+// one level deep; they are never modified after loading.
+func cloneToolDeclarations(tools []ToolDeclaration) []ToolDeclaration {
+	if tools == nil {
+		return nil
+	}
+
+	clone := make([]ToolDeclaration, len(tools))
+	for i, t := range tools {
+		clone[i] = ToolDeclaration{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: maps.Clone(t.InputSchema),
+		}
+	}
+
+	return clone
+}
+
+// toolDeclarationsEqual compares two tool declaration lists, in order.
+func toolDeclarationsEqual(a, b []ToolDeclaration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Description != b[i].Description ||
+			!reflect.DeepEqual(a[i].InputSchema, b[i].InputSchema) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMCPConfigLazyServer(t *testing.T) {
+	t.Parallel()
+
+	mcpCfg, err := ParseMCPConfig([]byte(`{
+  "mcpServers": {
+    "jira": {
+      "command": "jira-mcp",
+      "lazy": true,
+      "tools": [
+        {"name": "search", "description": "Search issues",
+         "inputSchema": {"type": "object", "required": ["jql"]}}
+      ]
+    }
+  }
+}`), LoadOptions{})
+	if err != nil {
+		t.Fatalf("ParseMCPConfig() error = %v", err)
+	}
+
+	srv := mcpCfg.ToServerConfigs()["jira"]
+	if !srv.Lazy {
+		t.Error("Lazy = false, want true")
+	}
+
+	if len(srv.Tools) != 1 || srv.Tools[0].Name != "search" || srv.Tools[0].InputSchema["type"] != "object" {
+		t.Errorf("Tools = %+v", srv.Tools)
+	}
+
+	if !srv.Equal(srv.Clone()) {
+		t.Error("Clone() of lazy server is not Equal")
+	}
+}
+
+func TestMergeServerLazy(t *testing.T) {
+	t.Parallel()
+
+	base := &ServerConfig{Command: "jira-mcp", Tools: []ToolDeclaration{{Name: "search"}, {Name: "create"}}}
+
+	tests := []struct {
+		name      string
+		override  *ServerConfig
+		wantLazy  bool
+		wantTools []string
+	}{
+		{
+			name:      "lazy override keeps tools",
+			override:  &ServerConfig{Lazy: true},
+			wantLazy:  true,
+			wantTools: []string{"search", "create"},
+		},
+		{
+			name:      "tools are replaced",
+			override:  &ServerConfig{Tools: []ToolDeclaration{{Name: "get"}}},
+			wantTools: []string{"get"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := mergeServer(base, tt.override)
+			if got.Lazy != tt.wantLazy {
+				t.Errorf("Lazy = %v, want %v", got.Lazy, tt.wantLazy)
+			}
+
+			names := make([]string, 0, len(got.Tools))
+			for _, tool := range got.Tools {
+				names = append(names, tool.Name)
+			}
+
+			if !slices.Equal(names, tt.wantTools) {
+				t.Errorf("Tools = %v, want %v", names, tt.wantTools)
+			}
+		})
+	}
+}
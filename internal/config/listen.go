@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// ValidateListen checks an HTTP listen address: "host:port" or ":port".
+// An empty address (HTTP serving off) is valid.
+func ValidateListen(addr string) error {
+	if addr == "" {
+		return nil
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid listen address %q: port must be a number from 0 to 65535", addr)
+	}
+
+	return nil
+}
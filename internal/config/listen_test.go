@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestValidateListen(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{addr: ""},
+		{addr: ":8080"},
+		{addr: "127.0.0.1:8080"},
+		{addr: "[::1]:0"},
+		{addr: "8080", wantErr: true},
+		{addr: ":http", wantErr: true},
+		{addr: ":70000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if err := ValidateListen(tt.addr); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateListen(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+		})
+	}
+}
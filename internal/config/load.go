@@ -0,0 +1,315 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+)
+
+// Load reads a configuration file from the given path.
+func Load(path string, opts LoadOptions) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	return Parse(data, opts)
+}
+
+// Parse parses YAML configuration data (config.yaml).
+// Note: This only parses Projects and Settings. Servers come from mcp.json.
+func Parse(data []byte, opts LoadOptions) (*Config, error) {
+	cfg := NewConfig()
+
+	if err := decodeYAML(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	// Apply defaults
+	if cfg.Settings == nil {
+		cfg.Settings = DefaultSettings()
+	}
+
+	if opts.Strict || cfg.Settings.Strict {
+		if err := checkKnownFields(data, cfg, "yaml"); err != nil {
+			return nil, fmt.Errorf("parsing config: %w", err)
+		}
+	}
+
+	if err := validateSettings(cfg.Settings); err != nil {
+		return nil, err
+	}
+
+	if err := validateGroups(cfg.Groups, cfg.Projects); err != nil {
+		return nil, err
+	}
+
+	for name, proj := range cfg.Projects {
+		if err := validateIdempotencyKeys("projects."+name+".servers", proj.Servers); err != nil {
+			return nil, err
+		}
+
+		if err := validateForwardHeaders("projects."+name+".servers", proj.Servers); err != nil {
+			return nil, err
+		}
+
+		if err := validateMaintenance("projects."+name+".servers", proj.Servers); err != nil {
+			return nil, err
+		}
+
+		if err := validateBinaryContent("projects."+name+".servers", proj.Servers); err != nil {
+			return nil, err
+		}
+
+		if err := validateEncodings("projects."+name+".servers", proj.Servers); err != nil {
+			return nil, err
+		}
+
+		if err := validateCallTimeouts("projects."+name+".servers", proj.Servers); err != nil {
+			return nil, err
+		}
+
+		if proj.Settings != nil {
+			if err := validateFeatures("projects."+name+".settings.features", proj.Settings.Features); err != nil {
+				return nil, err
+			}
+
+			if err := validateAliases("projects."+name+".settings.aliases", proj.Settings.Aliases); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Set default merge mode for servers defined in project overrides
+	for _, proj := range cfg.Projects {
+		for _, srv := range proj.Servers {
+			if srv.MergeMode == "" {
+				srv.MergeMode = MergeModeOverlay
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadWithMCP loads both mcp.json and config.yaml from a directory and merges them.
+func LoadWithMCP(mcpPath, configPath string, opts LoadOptions) (*Config, error) {
+	// Load MCP servers from mcp.json
+	mcpCfg, err := LoadMCPConfig(mcpPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("loading mcp config: %w", err)
+	}
+
+	// Load Assern config from config.yaml
+	cfg, err := Load(configPath, opts)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// config.yaml is optional, create empty config
+			cfg = NewConfig()
+		} else {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+	}
+
+	// Populate servers from MCP config
+	cfg.Servers = mcpCfg.ToServerConfigs()
+
+	return cfg, nil
+}
+
+// LoadGlobal loads the global configuration from ~/.valksor/assern/.
+func LoadGlobal(opts LoadOptions) (*Config, error) {
+	mcpPath, err := GlobalMCPPath()
+	if err != nil {
+		return nil, err
+	}
+
+	configPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadWithMCP(mcpPath, configPath, opts)
+}
+
+// LoadEffective loads all configuration sources and builds the effective config.
+// It loads global mcp.json, global config.yaml, and optionally local .assern/ configs.
+// The projectName is used to apply project-specific overrides from global config.
+func LoadEffective(workDir, projectName string, opts LoadOptions) (*Config, error) {
+	src, err := loadSources(workDir, projectName, opts.Strict)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := BuildEffectiveConfig(src.globalMCP, src.globalConfig, src.localMCP, src.localConfig, src.projectName)
+	if err := cfg.applyProfile(opts.Profile); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadEffectiveTrace is LoadEffective that also returns the merge trace.
+func LoadEffectiveTrace(workDir, projectName string, opts LoadOptions) (*Config, *MergeTrace, error) {
+	src, err := loadSources(workDir, projectName, opts.Strict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, trace := BuildEffectiveConfigTrace(src.globalMCP, src.globalConfig, src.localMCP, src.localConfig, src.projectName)
+	if err := cfg.applyProfile(opts.Profile); err != nil {
+		return nil, nil, err
+	}
+
+	// Servers outside the profile are not part of the result
+	maps.DeleteFunc(trace.Servers, func(name string, _ []TraceEntry) bool { return cfg.Servers[name] == nil })
+	trace.Profile = cfg.Profile
+
+	return cfg, trace, nil
+}
+
+// configSources holds every configuration input to BuildEffectiveConfig.
+type configSources struct {
+	globalMCP    *MCPConfig
+	globalConfig *Config
+	localMCP     *MCPConfig
+	localConfig  *LocalProjectConfig
+	projectName  string
+}
+
+// loadSources reads the global and local configuration files. A project
+// named by the local config is used when projectName is empty. strict is
+// LoadOptions.Strict.
+func loadSources(workDir, projectName string, strict bool) (*configSources, error) {
+	// Load global Assern config first: its settings.strict applies to the
+	// other files
+	globalConfigPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global config path: %w", err)
+	}
+
+	var globalConfig *Config
+	if FileExists(globalConfigPath) {
+		globalConfig, err = Load(globalConfigPath, LoadOptions{Strict: strict})
+		if err != nil {
+			return nil, fmt.Errorf("loading global config: %w", err)
+		}
+	}
+
+	strict = strict || globalConfig != nil && globalConfig.Settings.Strict
+
+	globalConfig, err = loadGlobalACL(globalConfig, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load global MCP config
+	globalMCPPath, err := GlobalMCPPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global mcp path: %w", err)
+	}
+
+	globalMCP, err := loadMCPConfig(globalMCPPath, strict)
+	if err != nil {
+		return nil, fmt.Errorf("loading global mcp config: %w", err)
+	}
+
+	// Try to find local .assern directory
+	var localMCP *MCPConfig
+	var localConfig *LocalProjectConfig
+
+	localDir := FindLocalConfigDir(workDir)
+	if localDir != "" {
+		// Load local MCP config if exists
+		localMCPPath := LocalMCPPath(localDir)
+		if FileExists(localMCPPath) {
+			localMCP, err = loadMCPConfig(localMCPPath, strict)
+			if err != nil {
+				return nil, fmt.Errorf("loading local mcp config: %w", err)
+			}
+		}
+
+		// Load local config if exists
+		localConfigPath := LocalConfigPath(localDir)
+		if FileExists(localConfigPath) {
+			localConfig, err = loadLocalProject(localConfigPath, strict)
+			if err != nil {
+				return nil, fmt.Errorf("loading local config: %w", err)
+			}
+
+			// Use project name from local config if not specified
+			if projectName == "" && localConfig.Project != "" {
+				projectName = localConfig.Project
+			}
+		}
+	}
+
+	return &configSources{
+		globalMCP:    globalMCP,
+		globalConfig: globalConfig,
+		localMCP:     localMCP,
+		localConfig:  localConfig,
+		projectName:  projectName,
+	}, nil
+}
+
+// LoadLocalProject reads a project-local .assern/config.yaml file.
+func LoadLocalProject(path string, opts LoadOptions) (*LocalProjectConfig, error) {
+	return loadLocalProject(path, opts.Strict)
+}
+
+// loadLocalProject is LoadLocalProject, rejecting unknown keys if strict.
+func loadLocalProject(path string, strict bool) (*LocalProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading local project config: %w", err)
+	}
+
+	var cfg LocalProjectConfig
+
+	if err := decodeYAML(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing local project config: %w", err)
+	}
+
+	if strict {
+		if err := checkKnownFields(data, &cfg, "yaml"); err != nil {
+			return nil, fmt.Errorf("parsing local project config: %w", err)
+		}
+	}
+
+	if err := validateIdempotencyKeys("servers", cfg.Servers); err != nil {
+		return nil, err
+	}
+
+	if err := validateForwardHeaders("servers", cfg.Servers); err != nil {
+		return nil, err
+	}
+
+	if err := validateMaintenance("servers", cfg.Servers); err != nil {
+		return nil, err
+	}
+
+	if err := validateBinaryContent("servers", cfg.Servers); err != nil {
+		return nil, err
+	}
+
+	if err := validateEncodings("servers", cfg.Servers); err != nil {
+		return nil, err
+	}
+
+	if err := validateCallTimeouts("servers", cfg.Servers); err != nil {
+		return nil, err
+	}
+
+	if cfg.Settings != nil {
+		if err := validateFeatures("settings.features", cfg.Settings.Features); err != nil {
+			return nil, err
+		}
+
+		if err := validateAliases("settings.aliases", cfg.Settings.Aliases); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxMaintenanceDuration bounds the duration of a maintenance window.
+const MaxMaintenanceDuration = 7 * 24 * time.Hour
+
+// DefaultMaintenanceMessage is reported to clients calling a server in a
+// maintenance window without a message of its own.
+const DefaultMaintenanceMessage = "server is down for scheduled maintenance"
+
+var (
+	errScheduleFields = errors.New("schedule needs 5 fields: minute hour day-of-month month day-of-week")
+	errScheduleRange  = errors.New("value out of range")
+)
+
+// MaintenanceWindow is a recurring period during which a backend is known
+// to be unavailable, e.g. for a nightly redeploy. Calls to the server fail
+// at once with Message, and it is not probed or restarted, until the window
+// ends.
+type MaintenanceWindow struct {
+	// Schedule is a 5-field cron expression (minute hour day-of-month month
+	// day-of-week) of when windows start, e.g. "0 3 * * *" for 03:00 daily.
+	// Fields take *, lists, ranges and steps; @hourly, @daily, @weekly and
+	// @monthly are shorthands.
+	Schedule string `yaml:"schedule"`
+	// Duration is how long each window lasts.
+	Duration time.Duration `yaml:"duration"`
+	// Timezone of Schedule, e.g. "Europe/Riga". Empty uses local time.
+	Timezone string `yaml:"timezone,omitempty"`
+	// Message is shown to clients calling the server during the window.
+	Message string `yaml:"message,omitempty"`
+}
+
+// EffectiveMessage returns the message shown during the window.
+func (w MaintenanceWindow) EffectiveMessage() string {
+	if w.Message == "" {
+		return DefaultMaintenanceMessage
+	}
+
+	return w.Message
+}
+
+// ActiveAt reports whether the window is open at t and, if so, when it
+// closes. A window whose schedule does not parse is never open.
+func (w MaintenanceWindow) ActiveAt(t time.Time) (time.Time, bool) {
+	sched, err := parseSchedule(w.Schedule)
+	if err != nil || w.Duration <= 0 {
+		return time.Time{}, false
+	}
+
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// The latest start within the last Duration, if any, holds the window
+	// open longest
+	t = t.In(loc)
+	for start := t.Truncate(time.Minute); t.Sub(start) < w.Duration; start = start.Add(-time.Minute) {
+		if sched.matches(start) {
+			return start.Add(w.Duration), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (w MaintenanceWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(w.Timezone)
+}
+
+// InMaintenance returns the maintenance window of the server open at t and
+// when it closes (the latest close of overlapping windows). s may be nil.
+func (s *ServerConfig) InMaintenance(t time.Time) (MaintenanceWindow, time.Time, bool) {
+	if s == nil {
+		return MaintenanceWindow{}, time.Time{}, false
+	}
+
+	var (
+		open  MaintenanceWindow
+		until time.Time
+		found bool
+	)
+
+	for _, w := range s.Maintenance {
+		if end, ok := w.ActiveAt(t); ok && end.After(until) {
+			open, until, found = w, end, true
+		}
+	}
+
+	return open, until, found
+}
+
+// schedule is a parsed cron expression: one bit per allowed value of each
+// field.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record an unrestricted (*) day field; when both day
+	// fields are restricted, either may match, as in cron
+	domAny, dowAny bool
+}
+
+// scheduleMacros are the supported @ shorthands.
+var scheduleMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// parseSchedule parses a 5-field cron expression or @ shorthand.
+func parseSchedule(expr string) (schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := scheduleMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return schedule{}, errScheduleFields
+	}
+
+	var (
+		s   schedule
+		err error
+	)
+
+	bounds := []struct {
+		name     string
+		min, max int
+		bits     *uint64
+	}{
+		{"minute", 0, 59, &s.minute},
+		{"hour", 0, 23, &s.hour},
+		{"day-of-month", 1, 31, &s.dom},
+		{"month", 1, 12, &s.month},
+		{"day-of-week", 0, 7, &s.dow},
+	}
+
+	for i, b := range bounds {
+		if *b.bits, err = parseScheduleField(fields[i], b.min, b.max); err != nil {
+			return schedule{}, fmt.Errorf("%s %q: %w", b.name, fields[i], err)
+		}
+	}
+
+	// 7 is Sunday too
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domAny = strings.HasPrefix(fields[2], "*")
+	s.dowAny = strings.HasPrefix(fields[4], "*")
+
+	return s, nil
+}
+
+// parseScheduleField parses a comma-separated list of *, values, ranges
+// (a-b) and steps (*/n, a-b/n, a/n) into a bit set.
+func parseScheduleField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+
+			step = n
+		}
+
+		first, last := lo, hi
+
+		switch from, to, isRange := strings.Cut(rng, "-"); {
+		case rng == "*":
+		case isRange:
+			var err error
+			if first, err = scheduleValue(from, lo, hi); err != nil {
+				return 0, err
+			}
+
+			if last, err = scheduleValue(to, lo, hi); err != nil {
+				return 0, err
+			}
+
+			if first > last {
+				return 0, fmt.Errorf("range %q: %w", rng, errScheduleRange)
+			}
+		default:
+			var err error
+			if first, err = scheduleValue(rng, lo, hi); err != nil {
+				return 0, err
+			}
+
+			// A single value is itself, or the start of a step
+			if !hasStep {
+				last = first
+			}
+		}
+
+		for v := first; v <= last; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+func scheduleValue(s string, lo, hi int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	if v < lo || v > hi {
+		return 0, fmt.Errorf("%d not in %d-%d: %w", v, lo, hi, errScheduleRange)
+	}
+
+	return v, nil
+}
+
+// matches reports whether a window starts in the minute of t.
+func (s schedule) matches(t time.Time) bool {
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+
+	switch {
+	case s.domAny || s.dowAny:
+		return dom && dow
+	default:
+		return dom || dow
+	}
+}
+
+// validateMaintenance checks the maintenance windows of servers defined
+// under path: each needs a valid schedule and timezone and a duration of
+// at most MaxMaintenanceDuration.
+func validateMaintenance(path string, servers map[string]*ServerConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		srv := servers[name]
+		if srv == nil {
+			continue
+		}
+
+		for i, w := range srv.Maintenance {
+			field := fmt.Sprintf("%s.%s.maintenance[%d]", path, name, i)
+
+			if _, err := parseSchedule(w.Schedule); err != nil {
+				return fmt.Errorf("%s.schedule: %w", field, err)
+			}
+
+			if w.Duration <= 0 || w.Duration > MaxMaintenanceDuration {
+				return fmt.Errorf("%s.duration: must be positive and at most %s", field, MaxMaintenanceDuration)
+			}
+
+			if _, err := w.location(); err != nil {
+				return fmt.Errorf("%s.timezone: %w", field, err)
+			}
+		}
+	}
+
+	return nil
+}
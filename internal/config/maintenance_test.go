@@ -0,0 +1,148 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	riga, err := time.LoadLocation("Europe/Riga")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+
+	// Monday 2026-10-12 03:15
+	monday := time.Date(2026, 10, 12, 3, 15, 0, 0, riga)
+
+	tests := []struct {
+		expr    string
+		at      time.Time
+		matches bool
+		wantErr string
+	}{
+		{expr: "15 3 * * *", at: monday, matches: true},
+		{expr: "*/15 * * * *", at: monday, matches: true},
+		{expr: "0-10,20 * * * *", at: monday},
+		{expr: "15 3 * * 1-5", at: monday, matches: true},
+		{expr: "15 3 * * 0,6", at: monday},
+		{expr: "15 3 * * 7", at: monday.AddDate(0, 0, 6), matches: true},
+		// Both day fields restricted: either matches, as in cron
+		{expr: "15 3 1 * 1", at: monday, matches: true},
+		{expr: "15 3 1 * 2", at: monday},
+		{expr: "@hourly", at: monday.Add(45 * time.Minute), matches: true},
+		{expr: "* * *", wantErr: "needs 5 fields"},
+		{expr: "60 * * * *", wantErr: `minute "60"`},
+		{expr: "* * * * 2-1", wantErr: "out of range"},
+		{expr: "*/0 * * * *", wantErr: "invalid step"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := parseSchedule(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseSchedule() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseSchedule() error = %v", err)
+			}
+
+			if got := s.matches(tt.at); got != tt.matches {
+				t.Errorf("matches(%s) = %v, want %v", tt.at, got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestServerConfigInMaintenance(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ServerConfig{Maintenance: []MaintenanceWindow{
+		{Schedule: "0 2 * * *", Duration: 30 * time.Minute, Timezone: "UTC"},
+		{Schedule: "0 2 * * 0", Duration: 2 * time.Hour, Timezone: "UTC", Message: "weekly upgrade"},
+	}}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		open      bool
+		wantUntil time.Time
+		wantMsg   string
+	}{
+		{name: "before", at: time.Date(2026, 10, 14, 1, 59, 0, 0, time.UTC)},
+		{
+			name: "daily window", at: time.Date(2026, 10, 14, 2, 10, 0, 0, time.UTC), open: true,
+			wantUntil: time.Date(2026, 10, 14, 2, 30, 0, 0, time.UTC), wantMsg: DefaultMaintenanceMessage,
+		},
+		{name: "daily window ended", at: time.Date(2026, 10, 14, 2, 30, 0, 0, time.UTC)},
+		{
+			name: "overlapping windows", at: time.Date(2026, 10, 18, 2, 10, 0, 0, time.UTC), open: true,
+			wantUntil: time.Date(2026, 10, 18, 4, 0, 0, 0, time.UTC), wantMsg: "weekly upgrade",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			window, until, open := cfg.InMaintenance(tt.at)
+			if open != tt.open {
+				t.Fatalf("InMaintenance() open = %v, want %v", open, tt.open)
+			}
+
+			if open && (!until.Equal(tt.wantUntil) || window.EffectiveMessage() != tt.wantMsg) {
+				t.Errorf("InMaintenance() = %q until %s, want %q until %s", window.EffectiveMessage(), until, tt.wantMsg, tt.wantUntil)
+			}
+		})
+	}
+
+	var none *ServerConfig
+	if _, _, open := none.InMaintenance(time.Now()); open {
+		t.Error("nil config in maintenance")
+	}
+}
+
+func TestParseMaintenance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		window  string
+		wantErr string
+	}{
+		{name: "valid", window: "schedule: \"0 2 * * *\"\n            duration: 30m\n            timezone: UTC"},
+		{name: "bad schedule", window: "schedule: \"0 25 * * *\"\n            duration: 30m", wantErr: "projects.work.servers.wiki.maintenance[0].schedule: hour"},
+		{name: "no duration", window: "schedule: \"0 2 * * *\"", wantErr: "maintenance[0].duration: must be positive"},
+		{name: "bad timezone", window: "schedule: \"0 2 * * *\"\n            duration: 30m\n            timezone: Mars/Olympus", wantErr: "maintenance[0].timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := "projects:\n  work:\n    servers:\n      wiki:\n        maintenance:\n          - " + tt.window + "\n"
+
+			_, err := Parse([]byte(data), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
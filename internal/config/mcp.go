@@ -4,13 +4,18 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"maps"
 	"os"
+	"slices"
 )
 
 // MCPConfig represents the standard MCP JSON configuration format.
 // This matches the format used by Claude Desktop and other MCP clients.
 type MCPConfig struct {
+	// Schema points editors at the JSON Schema of the file (see
+	// SchemaKind.URL); assern itself ignores it
+	Schema     string                `json:"$schema,omitempty"`
 	MCPServers map[string]*MCPServer `json:"mcpServers"`
 }
 
@@ -22,6 +27,16 @@ type MCPServer struct {
 	Env     map[string]string `json:"env,omitempty"`
 	WorkDir string            `json:"workDir,omitempty"` // Working directory for stdio servers
 
+	// Encoding and Locale handle stdio servers that do not speak UTF-8 (see
+	// ServerConfig.Encoding and ServerConfig.Locale)
+	Encoding string `json:"encoding,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+
+	// Container transport fields (see ServerConfig.Image)
+	Image   string   `json:"image,omitempty"`
+	Volumes []string `json:"volumes,omitempty"`
+	Network string   `json:"network,omitempty"`
+
 	// HTTP/SSE transport fields
 	URL     string            `json:"url,omitempty"`
 	Headers map[string]string `json:"headers,omitempty"` // Custom HTTP headers (API keys, Bearer tokens)
@@ -33,8 +48,21 @@ type MCPServer struct {
 	// config.yaml. Used when OAuth is not set inline; inline OAuth wins.
 	OAuthRef string `json:"oauthRef,omitempty"`
 
-	// Transport type hint: "stdio", "sse", "http", "oauth-sse", "oauth-http" (auto-detected if not specified)
+	// Transport type hint: "stdio", "sse", "http", "oauth-sse", "oauth-http", "websocket", "container" (auto-detected if not specified)
 	Transport string `json:"transport,omitempty"`
+
+	// Lazy defers starting the server until its first tool call; Tools
+	// declares the tools to advertise until then (see ServerConfig.Lazy)
+	Lazy  bool              `json:"lazy,omitempty"`
+	Tools []ToolDeclaration `json:"tools,omitempty"`
+
+	// Prefix replaces the server name in its tool names (see
+	// ServerConfig.Prefix)
+	Prefix string `json:"prefix,omitempty"`
+
+	// Disabled keeps the server configured but stopped; 'assern mcp enable'
+	// and 'assern mcp disable' toggle it
+	Disabled bool `json:"disabled,omitempty"`
 }
 
 // NewMCPConfig creates a new empty MCPConfig.
@@ -45,7 +73,12 @@ func NewMCPConfig() *MCPConfig {
 }
 
 // LoadMCPConfig reads an MCP configuration from a JSON file.
-func LoadMCPConfig(path string) (*MCPConfig, error) {
+func LoadMCPConfig(path string, opts LoadOptions) (*MCPConfig, error) {
+	return loadMCPConfig(path, opts.Strict)
+}
+
+// loadMCPConfig is LoadMCPConfig, rejecting unknown keys if strict.
+func loadMCPConfig(path string, strict bool) (*MCPConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -55,27 +88,122 @@ func LoadMCPConfig(path string) (*MCPConfig, error) {
 		return nil, fmt.Errorf("reading mcp config: %w", err)
 	}
 
-	return ParseMCPConfig(data)
+	return parseMCPConfig(data, strict)
+}
+
+// ParseMCPConfig parses MCP JSON configuration data. Comments and trailing
+// commas (JSONC) are accepted.
+func ParseMCPConfig(data []byte, opts LoadOptions) (*MCPConfig, error) {
+	return parseMCPConfig(data, opts.Strict)
 }
 
-// ParseMCPConfig parses MCP JSON configuration data.
-func ParseMCPConfig(data []byte) (*MCPConfig, error) {
+func parseMCPConfig(data []byte, strict bool) (*MCPConfig, error) {
 	cfg := NewMCPConfig()
+	data = standardizeJSON(data)
 
 	if err := json.Unmarshal(data, cfg); err != nil {
 		return nil, fmt.Errorf("parsing mcp config: %w", err)
 	}
 
+	if strict {
+		if err := checkKnownFields(data, cfg, "json"); err != nil {
+			return nil, fmt.Errorf("parsing mcp config: %w", err)
+		}
+	}
+
 	return cfg, nil
 }
 
-// Save writes the MCP configuration to the given path as JSON.
+// ReadMCPConfig decodes one MCP configuration document from r, which may go
+// on with other data, such as the MCP stream when the document is sent on
+// stdin. It returns the configuration and a reader for what follows the
+// document, without the whitespace that ends it. Unlike files, the document
+// must be plain JSON: comments and trailing commas are not accepted.
+func ReadMCPConfig(r io.Reader, opts LoadOptions) (*MCPConfig, io.Reader, error) {
+	dec := json.NewDecoder(r)
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("reading mcp config: %w", err)
+	}
+
+	cfg, err := ParseMCPConfig(raw, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, &leadingSpaceTrimmer{r: io.MultiReader(dec.Buffered(), r)}, nil
+}
+
+// leadingSpaceTrimmer drops the whitespace at the start of a stream, so the
+// line break after a document is not read as an empty message. It does not
+// wait for more input before returning what it already has.
+type leadingSpaceTrimmer struct {
+	r       io.Reader
+	trimmed bool
+}
+
+func (t *leadingSpaceTrimmer) Read(p []byte) (int, error) {
+	for !t.trimmed {
+		n, err := t.r.Read(p)
+
+		i := 0
+		for i < n && (p[i] == ' ' || p[i] == '\t' || p[i] == '\r' || p[i] == '\n') {
+			i++
+		}
+
+		if i < n {
+			t.trimmed = true
+
+			return copy(p, p[i:n]), err
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return t.r.Read(p)
+}
+
+// NewEphemeralConfig returns a configuration with the servers of mcpCfg and
+// default settings, for runs that read no configuration files.
+func NewEphemeralConfig(mcpCfg *MCPConfig) *Config {
+	cfg := NewConfig()
+	cfg.Servers = mcpCfg.ToServerConfigs()
+
+	return cfg
+}
+
+// Save writes the MCP configuration to the given path as JSON. An existing
+// file is patched in place (see patchMCPServers) so that comments, key order
+// and unrelated entries survive; a new or unpatchable file is written whole.
 func (c *MCPConfig) Save(path string) error {
+	if existing, err := os.ReadFile(path); err == nil {
+		patched, err := patchMCPServers(existing, c.MCPServers)
+		if err == nil {
+			// Never write a file that no longer loads
+			if _, err := ParseMCPConfig(patched, LoadOptions{}); err == nil {
+				return writeMCPFile(path, patched)
+			}
+		}
+	}
+
+	return c.Overwrite(path)
+}
+
+// Overwrite writes the MCP configuration to the given path as JSON,
+// replacing any existing content.
+func (c *MCPConfig) Overwrite(path string) error {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling mcp config: %w", err)
 	}
 
+	return writeMCPFile(path, data)
+}
+
+func writeMCPFile(path string, data []byte) error {
 	// 0600: mcp.json can contain credential headers and OAuth secrets.
 	if err := os.WriteFile(path, data, 0o600); err != nil {
 		return fmt.Errorf("writing mcp config: %w", err)
@@ -94,11 +222,20 @@ func (c *MCPConfig) ToServerConfigs() map[string]*ServerConfig {
 			Args:      srv.Args,
 			Env:       srv.Env,
 			WorkDir:   srv.WorkDir,
+			Encoding:  srv.Encoding,
+			Locale:    srv.Locale,
+			Image:     srv.Image,
+			Volumes:   srv.Volumes,
+			Network:   srv.Network,
 			URL:       srv.URL,
 			Headers:   srv.Headers,
 			OAuth:     srv.OAuth.Clone(),
 			OAuthRef:  srv.OAuthRef,
 			Transport: srv.Transport,
+			Lazy:      srv.Lazy,
+			Tools:     cloneToolDeclarations(srv.Tools),
+			Prefix:    srv.Prefix,
+			Disabled:  srv.Disabled,
 			MergeMode: MergeModeOverlay, // Default merge mode
 		}
 	}
@@ -113,6 +250,7 @@ func (c *MCPConfig) Clone() *MCPConfig {
 	}
 
 	clone := NewMCPConfig()
+	clone.Schema = c.Schema
 
 	for name, srv := range c.MCPServers {
 		clone.MCPServers[name] = srv.Clone()
@@ -132,11 +270,20 @@ func (s *MCPServer) Clone() *MCPServer {
 		Args:      make([]string, len(s.Args)),
 		Env:       make(map[string]string, len(s.Env)),
 		WorkDir:   s.WorkDir,
+		Encoding:  s.Encoding,
+		Locale:    s.Locale,
+		Image:     s.Image,
+		Volumes:   slices.Clone(s.Volumes),
+		Network:   s.Network,
 		URL:       s.URL,
 		Headers:   make(map[string]string, len(s.Headers)),
 		OAuth:     s.OAuth.Clone(),
 		OAuthRef:  s.OAuthRef,
 		Transport: s.Transport,
+		Lazy:      s.Lazy,
+		Tools:     cloneToolDeclarations(s.Tools),
+		Prefix:    s.Prefix,
+		Disabled:  s.Disabled,
 	}
 
 	copy(clone.Args, s.Args)
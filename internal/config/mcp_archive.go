@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MaxArchivedVersions bounds how many deleted definitions of one server name
+// an archive keeps; the oldest are dropped first.
+const MaxArchivedVersions = 10
+
+// ArchivedServer is a server definition deleted from an mcp.json.
+type ArchivedServer struct {
+	Name      string     `json:"name"`
+	DeletedAt time.Time  `json:"deletedAt"`
+	Server    *MCPServer `json:"server"`
+}
+
+// MCPArchive holds the servers deleted from an mcp.json, oldest first, so
+// they can be restored (see MCPArchivePath).
+type MCPArchive struct {
+	Servers []ArchivedServer `json:"archivedServers"`
+}
+
+// MCPArchivePath returns the archive of the mcp.json at mcpPath, in the same
+// directory.
+func MCPArchivePath(mcpPath string) string {
+	return filepath.Join(filepath.Dir(mcpPath), MCPArchiveFile)
+}
+
+// LoadMCPArchive reads the archive at path. A missing file is an empty
+// archive.
+func LoadMCPArchive(path string) (*MCPArchive, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &MCPArchive{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading mcp archive: %w", err)
+	}
+
+	var archive MCPArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("parsing mcp archive %s: %w", path, err)
+	}
+
+	return &archive, nil
+}
+
+// Save writes the archive to path, with the same permissions as mcp.json.
+func (a *MCPArchive) Save(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling mcp archive: %w", err)
+	}
+
+	return writeMCPFile(path, data)
+}
+
+// Add archives srv as deleted at t, dropping the oldest definitions of name
+// beyond MaxArchivedVersions.
+func (a *MCPArchive) Add(name string, srv *MCPServer, t time.Time) {
+	a.Servers = append(a.Servers, ArchivedServer{Name: name, DeletedAt: t.UTC(), Server: srv.Clone()})
+
+	count := 0
+	for i := len(a.Servers) - 1; i >= 0; i-- {
+		if a.Servers[i].Name != name {
+			continue
+		}
+
+		if count++; count > MaxArchivedVersions {
+			a.Servers = append(a.Servers[:i], a.Servers[i+1:]...)
+		}
+	}
+}
+
+// Latest returns the most recently deleted definition of name.
+func (a *MCPArchive) Latest(name string) (ArchivedServer, bool) {
+	for i := len(a.Servers) - 1; i >= 0; i-- {
+		if a.Servers[i].Name == name {
+			return a.Servers[i], true
+		}
+	}
+
+	return ArchivedServer{}, false
+}
+
+// RemoveLatest drops the most recently deleted definition of name, once it
+// has been restored.
+func (a *MCPArchive) RemoveLatest(name string) {
+	for i := len(a.Servers) - 1; i >= 0; i-- {
+		if a.Servers[i].Name == name {
+			a.Servers = append(a.Servers[:i], a.Servers[i+1:]...)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMCPArchive(t *testing.T) {
+	t.Parallel()
+
+	path := MCPArchivePath(filepath.Join(t.TempDir(), "mcp.json"))
+
+	archive, err := LoadMCPArchive(path)
+	if err != nil || len(archive.Servers) != 0 {
+		t.Fatalf("LoadMCPArchive() of a missing file = %v, %v; want an empty archive", archive, err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range MaxArchivedVersions + 2 {
+		archive.Add("github", &MCPServer{URL: "https://example.com/" + string(rune('a'+i))}, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	archive.Add("other", &MCPServer{Command: "other"}, start)
+
+	if err := archive.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("archive mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	loaded, err := LoadMCPArchive(path)
+	if err != nil {
+		t.Fatalf("LoadMCPArchive() error = %v", err)
+	}
+
+	if len(loaded.Servers) != MaxArchivedVersions+1 {
+		t.Errorf("archive holds %d definitions, want %d", len(loaded.Servers), MaxArchivedVersions+1)
+	}
+
+	latest, ok := loaded.Latest("github")
+	if !ok || latest.Server.URL != "https://example.com/l" || !latest.DeletedAt.Equal(start.Add(11*time.Minute)) {
+		t.Errorf("Latest() = %+v, %v; want the last definition added", latest, ok)
+	}
+
+	loaded.RemoveLatest("github")
+
+	if latest, _ := loaded.Latest("github"); latest.Server.URL != "https://example.com/k" {
+		t.Errorf("Latest() after RemoveLatest() = %q, want the previous definition", latest.Server.URL)
+	}
+
+	if _, ok := loaded.Latest("missing"); ok {
+		t.Error("Latest() found a server never archived")
+	}
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// errNotPatchable reports that an existing mcp.json cannot be edited in place
+// and has to be rewritten.
+var errNotPatchable = errors.New("mcp config cannot be patched in place")
+
+// jsonMember is an object member located in a JSON(C) document. Offsets are
+// byte positions valid in both the original and the standardized document.
+type jsonMember struct {
+	Name       string
+	KeyStart   int
+	ValueStart int
+	ValueEnd   int
+	Comma      int // offset of the separating comma, -1 if none
+}
+
+// patchMCPServers rewrites the "mcpServers" object of doc so that it holds
+// servers, touching as little text as possible: unchanged servers keep their
+// exact text (comments, key order, unknown fields), changed servers have only
+// their value replaced, removed servers are cut out and new servers are
+// appended in name order. Everything outside "mcpServers" is kept verbatim.
+func patchMCPServers(doc []byte, servers map[string]*MCPServer) ([]byte, error) {
+	clean := standardizeJSON(doc)
+
+	root := skipSpace(clean, 0)
+	if root >= len(clean) || clean[root] != '{' {
+		return nil, errNotPatchable
+	}
+
+	topMembers, _, err := scanObject(clean, root)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(topMembers, func(m jsonMember) bool { return m.Name == "mcpServers" })
+	if idx < 0 || clean[topMembers[idx].ValueStart] != '{' {
+		return nil, errNotPatchable
+	}
+
+	top := topMembers[idx]
+	open := top.ValueStart
+
+	members, closeBrace, err := scanObject(clean, open)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := lineIndent(doc, top.KeyStart)
+	if unit == "" {
+		unit = "  "
+	}
+
+	memberIndent := lineIndent(doc, top.KeyStart) + unit
+	if len(members) > 0 {
+		if indent := lineIndent(doc, members[0].KeyStart); indent != "" {
+			memberIndent = indent
+		}
+	}
+
+	var chunks [][]byte
+
+	existing := make(map[string]bool, len(members))
+	lead := open + 1
+
+	for _, m := range members {
+		existing[m.Name] = true
+
+		end := m.ValueEnd
+		if m.Comma >= 0 {
+			end = m.Comma
+		}
+
+		srv, keep := servers[m.Name]
+		if !keep {
+			lead = end + 1
+
+			continue
+		}
+
+		chunk := bytes.Clone(doc[lead:m.ValueStart])
+
+		if serverChanged(clean[m.ValueStart:m.ValueEnd], srv) {
+			value, err := json.MarshalIndent(srv, lineIndent(doc, m.KeyStart), unit)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling server %s: %w", m.Name, err)
+			}
+
+			chunk = append(chunk, value...)
+		} else {
+			chunk = append(chunk, doc[m.ValueStart:m.ValueEnd]...)
+		}
+
+		chunks = append(chunks, append(chunk, doc[m.ValueEnd:end]...))
+		lead = end + 1
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		if existing[name] {
+			continue
+		}
+
+		value, err := json.MarshalIndent(servers[name], memberIndent, unit)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling server %s: %w", name, err)
+		}
+
+		key, _ := json.Marshal(name)
+		chunks = append(chunks, fmt.Appendf(nil, "\n%s%s: %s", memberIndent, key, value))
+	}
+
+	tail := objectTail(doc, members, open, closeBrace, lineIndent(doc, top.KeyStart), len(chunks) > 0)
+
+	var out bytes.Buffer
+
+	out.Write(doc[:open+1])
+	out.Write(bytes.Join(chunks, []byte(",")))
+	out.Write(tail)
+	out.Write(doc[closeBrace:])
+
+	return out.Bytes(), nil
+}
+
+// objectTail returns the text between the last member and the closing brace.
+// A trailing comma is kept as the author wrote it when members remain; an
+// empty object gets a newline so appended members are laid out like the rest
+// of the file.
+func objectTail(doc []byte, members []jsonMember, open, closeBrace int, indent string, nonEmpty bool) []byte {
+	if len(members) == 0 {
+		if len(bytes.TrimSpace(doc[open+1:closeBrace])) == 0 {
+			return []byte("\n" + indent)
+		}
+
+		return doc[open+1 : closeBrace]
+	}
+
+	last := members[len(members)-1]
+	if last.Comma < 0 {
+		return doc[last.ValueEnd:closeBrace]
+	}
+
+	if !nonEmpty {
+		return doc[last.Comma+1 : closeBrace]
+	}
+
+	return append([]byte(","), doc[last.Comma+1:closeBrace]...)
+}
+
+// serverChanged reports whether the JSON value raw decodes to something
+// other than srv.
+func serverChanged(raw []byte, srv *MCPServer) bool {
+	var old MCPServer
+	if err := json.Unmarshal(raw, &old); err != nil {
+		return true
+	}
+
+	oldJSON, err1 := json.Marshal(&old)
+	newJSON, err2 := json.Marshal(srv)
+
+	return err1 != nil || err2 != nil || !bytes.Equal(oldJSON, newJSON)
+}
+
+// scanObject lists the members of the object opening at open, which must be
+// standardized JSON, and returns the offset of its closing brace.
+func scanObject(data []byte, open int) ([]jsonMember, int, error) {
+	var members []jsonMember
+
+	i := skipSpace(data, open+1)
+
+	for i < len(data) && data[i] != '}' {
+		if data[i] != '"' {
+			return nil, 0, errNotPatchable
+		}
+
+		keyEnd := skipString(data, i) + 1
+		if keyEnd > len(data) {
+			return nil, 0, errNotPatchable
+		}
+
+		var name string
+		if err := json.Unmarshal(data[i:keyEnd], &name); err != nil {
+			return nil, 0, errNotPatchable
+		}
+
+		colon := skipSpace(data, keyEnd)
+		if colon >= len(data) || data[colon] != ':' {
+			return nil, 0, errNotPatchable
+		}
+
+		m := jsonMember{Name: name, KeyStart: i, ValueStart: skipSpace(data, colon+1), Comma: -1}
+
+		m.ValueEnd = skipValue(data, m.ValueStart)
+		if m.ValueEnd <= m.ValueStart || m.ValueEnd > len(data) {
+			return nil, 0, errNotPatchable
+		}
+
+		i = skipSpace(data, m.ValueEnd)
+		if i < len(data) && data[i] == ',' {
+			m.Comma = i
+			i = skipSpace(data, i+1)
+		}
+
+		members = append(members, m)
+	}
+
+	if i >= len(data) {
+		return nil, 0, errNotPatchable
+	}
+
+	return members, i, nil
+}
+
+// skipValue returns the offset just past the JSON value starting at start.
+func skipValue(data []byte, start int) int {
+	if start >= len(data) {
+		return start
+	}
+
+	switch data[start] {
+	case '"':
+		return skipString(data, start) + 1
+	case '{', '[':
+		depth := 0
+
+		for i := start; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i)
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+
+		return len(data)
+	default:
+		i := start
+		for i < len(data) && !strings.ContainsRune(",}] \t\r\n", rune(data[i])) {
+			i++
+		}
+
+		return i
+	}
+}
+
+// skipSpace returns the offset of the first non-whitespace byte from i.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+
+	return i
+}
+
+// lineIndent returns the whitespace before offset on its line, or "" when
+// other text precedes it.
+func lineIndent(doc []byte, offset int) string {
+	start := bytes.LastIndexByte(doc[:offset], '\n') + 1
+
+	indent := doc[start:offset]
+	if len(bytes.Trim(indent, " \t")) > 0 {
+		return ""
+	}
+
+	return string(indent)
+}
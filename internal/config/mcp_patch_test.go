@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const patchDoc = `{
+  // Servers copied from the team wiki
+  "mcpServers": {
+    "zeta": {"command": "zeta-mcp", "type": "stdio"},
+    /* keep this one first */
+    "alpha": {
+      "command": "alpha-mcp"
+    },
+    "mid": {
+      "url": "https://mid.example/mcp",
+    },
+  },
+  "otherTool": {"keep": true}
+}`
+
+func TestPatchMCPServers(t *testing.T) {
+	t.Parallel()
+
+	base := func() map[string]*MCPServer {
+		return map[string]*MCPServer{
+			"zeta":  {Command: "zeta-mcp"},
+			"alpha": {Command: "alpha-mcp"},
+			"mid":   {URL: "https://mid.example/mcp"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		edit   func(map[string]*MCPServer)
+		want   string
+		reload func(*testing.T, *MCPConfig)
+	}{
+		{
+			name: "unchanged",
+			edit: func(map[string]*MCPServer) {},
+			want: patchDoc,
+		},
+		{
+			name: "edit one",
+			edit: func(s map[string]*MCPServer) { s["alpha"].Args = []string{"--fast"} },
+			want: `{
+  // Servers copied from the team wiki
+  "mcpServers": {
+    "zeta": {"command": "zeta-mcp", "type": "stdio"},
+    /* keep this one first */
+    "alpha": {
+      "command": "alpha-mcp",
+      "args": [
+        "--fast"
+      ]
+    },
+    "mid": {
+      "url": "https://mid.example/mcp",
+    },
+  },
+  "otherTool": {"keep": true}
+}`,
+		},
+		{
+			name: "delete middle and add",
+			edit: func(s map[string]*MCPServer) {
+				delete(s, "alpha")
+				s["new"] = &MCPServer{Command: "new-mcp"}
+			},
+			want: `{
+  // Servers copied from the team wiki
+  "mcpServers": {
+    "zeta": {"command": "zeta-mcp", "type": "stdio"},
+    "mid": {
+      "url": "https://mid.example/mcp",
+    },
+    "new": {
+      "command": "new-mcp"
+    },
+  },
+  "otherTool": {"keep": true}
+}`,
+		},
+		{
+			name: "delete all",
+			edit: func(s map[string]*MCPServer) { clear(s) },
+			reload: func(t *testing.T, cfg *MCPConfig) {
+				t.Helper()
+
+				if len(cfg.MCPServers) != 0 {
+					t.Errorf("servers = %v, want none", cfg.MCPServers)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := base()
+			tt.edit(servers)
+
+			got, err := patchMCPServers([]byte(patchDoc), servers)
+			if err != nil {
+				t.Fatalf("patchMCPServers() error = %v", err)
+			}
+
+			if tt.want != "" && string(got) != tt.want {
+				t.Errorf("patchMCPServers() =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			cfg, err := ParseMCPConfig(got, LoadOptions{})
+			if err != nil {
+				t.Fatalf("patched document does not parse: %v\n%s", err, got)
+			}
+
+			if tt.reload != nil {
+				tt.reload(t, cfg)
+			}
+		})
+	}
+}
+
+func TestPatchMCPServersEmptyObject(t *testing.T) {
+	t.Parallel()
+
+	got, err := patchMCPServers([]byte("{\n\t\"mcpServers\": {}\n}\n"), map[string]*MCPServer{"a": {Command: "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\n\t\"mcpServers\": {\n\t\t\"a\": {\n\t\t\t\"command\": \"a\"\n\t\t}\n\t}\n}\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPatchMCPServersNotPatchable(t *testing.T) {
+	t.Parallel()
+
+	for _, doc := range []string{"", "[]", `{"servers": {}}`, `{"mcpServers": []}`, `{"mcpServers": {`} {
+		if _, err := patchMCPServers([]byte(doc), nil); err == nil {
+			t.Errorf("patchMCPServers(%q) succeeded, want error", doc)
+		}
+	}
+}
+
+func TestMCPConfigSavePreservesFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	if err := os.WriteFile(path, []byte(patchDoc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadMCPConfig(path, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != patchDoc {
+		t.Errorf("Save() of an unmodified config changed the file:\n%s", data)
+	}
+}
@@ -1,9 +1,6 @@
 package config
 
-import (
-	"maps"
-	"slices"
-)
+import "slices"
 
 // BuildEffectiveConfig creates the final merged configuration from all sources.
 // Resolution order (highest priority first):
@@ -11,27 +8,52 @@ import (
 //  2. Local config.yaml (project-specific overrides)
 //  3. Global config.yaml project definition
 //  4. Global mcp.json (base server definitions)
+//
+// Settings follow the same order: local config.yaml settings override the
+// project's settings, which override the global settings.
 func BuildEffectiveConfig(
 	globalMCP *MCPConfig,
 	globalConfig *Config,
 	localMCP *MCPConfig,
 	localConfig *LocalProjectConfig,
 	projectName string,
+) *Config {
+	return buildEffectiveConfig(globalMCP, globalConfig, localMCP, localConfig, projectName, nil)
+}
+
+// BuildEffectiveConfigTrace is BuildEffectiveConfig that also reports which
+// source contributed each server field and setting.
+func BuildEffectiveConfigTrace(
+	globalMCP *MCPConfig,
+	globalConfig *Config,
+	localMCP *MCPConfig,
+	localConfig *LocalProjectConfig,
+	projectName string,
+) (*Config, *MergeTrace) {
+	trace := newMergeTrace()
+
+	return buildEffectiveConfig(globalMCP, globalConfig, localMCP, localConfig, projectName, trace), trace
+}
+
+// buildEffectiveConfig implements BuildEffectiveConfig, recording each step
+// in trace when it is non-nil.
+func buildEffectiveConfig(
+	globalMCP *MCPConfig,
+	globalConfig *Config,
+	localMCP *MCPConfig,
+	localConfig *LocalProjectConfig,
+	projectName string,
+	trace *MergeTrace,
 ) *Config {
 	// Start with empty config
 	result := NewConfig()
+	trace.settings(TraceDefault, []string{"log_level", "output_format", "timeout"})
 
 	// 1. Copy settings from global config
 	if globalConfig != nil && globalConfig.Settings != nil {
-		result.Settings = &Settings{
-			LogLevel:     globalConfig.Settings.LogLevel,
-			LogFile:      globalConfig.Settings.LogFile,
-			Timeout:      globalConfig.Settings.Timeout,
-			OutputFormat: globalConfig.Settings.OutputFormat,
-			Aliases:      maps.Clone(globalConfig.Settings.Aliases),
-			Discovery:    globalConfig.Settings.Discovery.Clone(),
-			CodeMode:     globalConfig.Settings.CodeMode.Clone(),
-		}
+		trace.settings(TraceGlobalConfig, settingsFields(globalConfig.Settings))
+
+		result.Settings = cloneSettings(globalConfig.Settings)
 	}
 
 	// 2. Copy projects and auth profiles from global config
@@ -47,18 +69,30 @@ func BuildEffectiveConfig(
 
 			result.Auth[name] = profile.Clone()
 		}
+
+		result.Groups = cloneGroups(globalConfig.Groups)
 	}
 
 	// 3. Load base servers from global mcp.json
 	if globalMCP != nil {
 		result.Servers = globalMCP.ToServerConfigs()
+
+		for name, srv := range result.Servers {
+			trace.server(name, TraceGlobalMCP, serverFields(srv))
+		}
 	}
 
 	// 4. Apply project-level overrides from global config.yaml
 	if projectName != "" && globalConfig != nil {
 		if projectCfg, ok := globalConfig.Projects[projectName]; ok {
+			source := traceProject(projectName)
+			if trace != nil {
+				trace.Project = projectName
+			}
+
 			// Apply project-level environment variables to all servers
 			for name, srv := range result.Servers {
+				trace.server(name, source, mapFields("env", projectCfg.Env, srv.MergeMode))
 				srv.Env = mergeEnv(srv.Env, projectCfg.Env, srv.MergeMode)
 				result.Servers[name] = srv
 			}
@@ -66,9 +100,16 @@ func BuildEffectiveConfig(
 			// Apply project-level server overrides
 			for name, projSrv := range projectCfg.Servers {
 				if existing, ok := result.Servers[name]; ok {
+					trace.server(name, source, overrideFields(existing, projSrv))
 					result.Servers[name] = mergeServer(existing, projSrv)
 				}
 			}
+
+			// Apply project-level settings overrides
+			trace.settings(source, overrideSettingsFields(projectCfg.Settings))
+			projectCfg.Settings.applyTo(result.Settings)
+
+			result.Profile = projectCfg.Profile
 		}
 	}
 
@@ -78,10 +119,12 @@ func BuildEffectiveConfig(
 			if existing, ok := result.Servers[name]; ok {
 				// Merge with existing server (local MCP overrides)
 				localSrv := mcpServerToConfig(srv)
+				trace.server(name, TraceLocalMCP, overrideFields(existing, localSrv))
 				result.Servers[name] = mergeServer(existing, localSrv)
 			} else {
 				// New server from local mcp.json
 				result.Servers[name] = mcpServerToConfig(srv)
+				trace.server(name, TraceLocalMCP, serverFields(result.Servers[name]))
 			}
 		}
 	}
@@ -91,6 +134,7 @@ func BuildEffectiveConfig(
 		// Apply local environment variables
 		if len(localConfig.Env) > 0 {
 			for name, srv := range result.Servers {
+				trace.server(name, TraceLocalConfig, mapFields("env", localConfig.Env, srv.MergeMode))
 				srv.Env = mergeEnv(srv.Env, localConfig.Env, srv.MergeMode)
 				result.Servers[name] = srv
 			}
@@ -99,12 +143,29 @@ func BuildEffectiveConfig(
 		// Apply local server overrides
 		for name, localSrv := range localConfig.Servers {
 			if existing, ok := result.Servers[name]; ok {
+				trace.server(name, TraceLocalConfig, overrideFields(existing, localSrv))
 				result.Servers[name] = mergeServer(existing, localSrv)
 			}
 		}
+
+		// Apply local settings overrides
+		trace.settings(TraceLocalConfig, overrideSettingsFields(localConfig.Settings))
+		localConfig.Settings.applyTo(result.Settings)
+
+		if localConfig.Profile != "" {
+			result.Profile = localConfig.Profile
+		}
 	}
 
 	// Resolve oauth_ref references against the auth profiles.
+	if trace != nil {
+		for name, srv := range result.Servers {
+			if srv.OAuthRef != "" && srv.OAuth == nil && result.Auth[srv.OAuthRef] != nil {
+				trace.server(name, traceAuthProfile(srv.OAuthRef), []string{"oauth"})
+			}
+		}
+	}
+
 	result.resolveOAuthRefs()
 
 	return result
@@ -126,110 +187,6 @@ func (c *Config) resolveOAuthRefs() {
 	}
 }
 
-// mergeServer merges an override server config onto a base server config.
-func mergeServer(base, override *ServerConfig) *ServerConfig {
-	if base == nil {
-		return override.Clone()
-	}
-
-	if override == nil {
-		return base.Clone()
-	}
-
-	result := base.Clone()
-
-	// Override command if specified
-	if override.Command != "" {
-		result.Command = override.Command
-	}
-
-	// Override args if specified
-	if len(override.Args) > 0 {
-		result.Args = make([]string, len(override.Args))
-		copy(result.Args, override.Args)
-	}
-
-	// Override WorkDir if specified
-	if override.WorkDir != "" {
-		result.WorkDir = override.WorkDir
-	}
-
-	// Override URL if specified
-	if override.URL != "" {
-		result.URL = override.URL
-	}
-
-	// Override transport if specified
-	if override.Transport != "" {
-		result.Transport = override.Transport
-	}
-
-	// Determine merge mode (override's mode takes precedence)
-	mergeMode := result.MergeMode
-	if override.MergeMode != "" {
-		mergeMode = override.MergeMode
-		result.MergeMode = mergeMode
-	}
-
-	// Merge environment variables based on mode
-	result.Env = mergeEnv(result.Env, override.Env, mergeMode)
-
-	// Merge headers based on mode (same as env - overlay or replace)
-	result.Headers = mergeEnv(result.Headers, override.Headers, mergeMode)
-
-	// Override OAuth config if specified (full replacement, not merge)
-	if override.OAuth != nil {
-		result.OAuth = override.OAuth.Clone()
-	}
-
-	// Override OAuth profile reference if specified
-	if override.OAuthRef != "" {
-		result.OAuthRef = override.OAuthRef
-	}
-
-	// Override allowed list if specified
-	if len(override.Allowed) > 0 {
-		result.Allowed = make([]string, len(override.Allowed))
-		copy(result.Allowed, override.Allowed)
-	}
-
-	// Override disabled flag if set
-	if override.Disabled {
-		result.Disabled = true
-	}
-
-	return result
-}
-
-// mergeEnv merges environment variables based on the merge mode.
-func mergeEnv(base, override map[string]string, mode MergeMode) map[string]string {
-	if len(override) == 0 {
-		return cloneMap(base)
-	}
-
-	if len(base) == 0 || mode == MergeModeReplace {
-		return cloneMap(override)
-	}
-
-	// Overlay mode: merge override on top of base
-	result := cloneMap(base)
-	maps.Copy(result, override)
-
-	return result
-}
-
-// cloneMap creates a copy of a string map.
-func cloneMap(m map[string]string) map[string]string {
-	if m == nil {
-		return nil
-	}
-
-	result := make(map[string]string, len(m))
-	maps.Copy(result, m)
-
-	return result
-}
-
 // mcpServerToConfig converts an MCPServer to a ServerConfig with overlay merge mode.
 func mcpServerToConfig(srv *MCPServer) *ServerConfig {
 	return &ServerConfig{
@@ -237,11 +194,18 @@ func mcpServerToConfig(srv *MCPServer) *ServerConfig {
 		Args:      srv.Args,
 		Env:       srv.Env,
 		WorkDir:   srv.WorkDir,
+		Encoding:  srv.Encoding,
+		Locale:    srv.Locale,
+		Image:     srv.Image,
+		Volumes:   srv.Volumes,
+		Network:   srv.Network,
 		URL:       srv.URL,
 		Headers:   srv.Headers,
 		OAuth:     srv.OAuth.Clone(),
 		OAuthRef:  srv.OAuthRef,
 		Transport: srv.Transport,
+		Prefix:    srv.Prefix,
+		Disabled:  srv.Disabled,
 		MergeMode: MergeModeOverlay,
 	}
 }
@@ -252,8 +216,9 @@ func GetEffectiveServers(cfg *Config) map[string]*ServerConfig {
 	result := make(map[string]*ServerConfig)
 
 	for name, srv := range cfg.Servers {
-		// Server must have either command (stdio) or url (sse/http) and not be disabled
-		hasTransport := srv.Command != "" || srv.URL != ""
+		// Server must have a command (stdio), url (sse/http) or image
+		// (container) and not be disabled
+		hasTransport := srv.Command != "" || srv.URL != "" || srv.Image != ""
 		if !srv.Disabled && hasTransport {
 			result[name] = srv
 		}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// mergeServer merges an override server config onto a base server config.
+func mergeServer(base, override *ServerConfig) *ServerConfig {
+	if base == nil {
+		return override.Clone()
+	}
+
+	if override == nil {
+		return base.Clone()
+	}
+
+	result := base.Clone()
+
+	// Override command if specified
+	if override.Command != "" {
+		result.Command = override.Command
+	}
+
+	// Override args if specified
+	if len(override.Args) > 0 {
+		result.Args = make([]string, len(override.Args))
+		copy(result.Args, override.Args)
+	}
+
+	// Override WorkDir if specified
+	if override.WorkDir != "" {
+		result.WorkDir = override.WorkDir
+	}
+
+	if override.Encoding != "" {
+		result.Encoding = override.Encoding
+	}
+
+	if override.Locale != "" {
+		result.Locale = override.Locale
+	}
+
+	if override.Image != "" {
+		result.Image = override.Image
+	}
+
+	if len(override.Volumes) > 0 {
+		result.Volumes = slices.Clone(override.Volumes)
+	}
+
+	if override.Network != "" {
+		result.Network = override.Network
+	}
+
+	// Override URL if specified
+	if override.URL != "" {
+		result.URL = override.URL
+	}
+
+	// Override transport if specified
+	if override.Transport != "" {
+		result.Transport = override.Transport
+	}
+
+	// Determine merge mode (override's mode takes precedence)
+	mergeMode := result.MergeMode
+	if override.MergeMode != "" {
+		mergeMode = override.MergeMode
+		result.MergeMode = mergeMode
+	}
+
+	// Merge environment variables based on mode
+	result.Env = mergeEnv(result.Env, override.Env, mergeMode)
+
+	// Merge headers based on mode (same as env - overlay or replace)
+	result.Headers = mergeEnv(result.Headers, override.Headers, mergeMode)
+
+	// Override OAuth config if specified (full replacement, not merge)
+	if override.OAuth != nil {
+		result.OAuth = override.OAuth.Clone()
+	}
+
+	// Override OAuth profile reference if specified
+	if override.OAuthRef != "" {
+		result.OAuthRef = override.OAuthRef
+	}
+
+	// Override allowed list if specified
+	if len(override.Allowed) > 0 {
+		result.Allowed = make([]string, len(override.Allowed))
+		copy(result.Allowed, override.Allowed)
+	}
+
+	if len(override.Denied) > 0 {
+		result.Denied = slices.Clone(override.Denied)
+	}
+
+	// Override resource filter if specified (full replacement, not merge)
+	if override.ResourceCache != nil {
+		result.ResourceCache = override.ResourceCache.Clone()
+	}
+
+	if override.BinaryContent != nil {
+		result.BinaryContent = override.BinaryContent.Clone()
+	}
+
+	if override.AllowedResources != nil {
+		result.AllowedResources = override.AllowedResources.Clone()
+	}
+
+	if override.Prompts != nil {
+		result.Prompts = override.Prompts.Clone()
+	}
+
+	if override.Prefix != "" {
+		result.Prefix = override.Prefix
+	}
+
+	// Override health check if specified (full replacement, not merge)
+	if override.Health != nil {
+		result.Health = override.Health.Clone()
+	}
+
+	// Override call timeout if specified (full replacement, not merge)
+	if override.CallTimeout != nil {
+		result.CallTimeout = override.CallTimeout.Clone()
+	}
+
+	// Override maintenance windows if specified (full replacement)
+	if len(override.Maintenance) > 0 {
+		result.Maintenance = slices.Clone(override.Maintenance)
+	}
+
+	// Idempotency keys overlay per tool
+	result.IdempotencyKeys = mergeEnv(result.IdempotencyKeys, override.IdempotencyKeys, MergeModeOverlay)
+
+	// Forwarded headers overlay per header
+	result.ForwardHeaders = mergeEnv(result.ForwardHeaders, override.ForwardHeaders, MergeModeOverlay)
+
+	// Enable request coalescing if set
+	if override.Coalesce {
+		result.Coalesce = true
+	}
+
+	// Override the concurrency limit if set; queueing is enabled if set
+	if override.MaxConcurrency != 0 {
+		result.MaxConcurrency = override.MaxConcurrency
+	}
+
+	if override.Queue {
+		result.Queue = true
+	}
+
+	// Enable sampling passthrough if set
+	if override.Sampling {
+		result.Sampling = true
+	}
+
+	// Enable dry-run if set
+	if override.DryRun {
+		result.DryRun = true
+	}
+
+	// Override the restart policy if set
+	if override.RestartPolicy != "" {
+		result.RestartPolicy = override.RestartPolicy
+	}
+
+	// Enable lazy startup if set
+	if override.Lazy {
+		result.Lazy = true
+	}
+
+	// Override declared tools if specified (full replacement, not merge)
+	if len(override.Tools) > 0 {
+		result.Tools = cloneToolDeclarations(override.Tools)
+	}
+
+	// Override the deprecation note if set; per-tool notes overlay
+	if override.Deprecated != "" {
+		result.Deprecated = override.Deprecated
+	}
+
+	result.DeprecatedTools = mergeEnv(result.DeprecatedTools, override.DeprecatedTools, MergeModeOverlay)
+
+	// Override the priority if set; per-tool priorities overlay
+	if override.Priority != 0 {
+		result.Priority = override.Priority
+	}
+
+	if len(override.ToolPriority) > 0 {
+		if result.ToolPriority == nil {
+			result.ToolPriority = make(map[string]int, len(override.ToolPriority))
+		}
+
+		maps.Copy(result.ToolPriority, override.ToolPriority)
+	}
+
+	// Per-tool descriptions overlay; the budget is overridden if set
+	result.ToolDescriptions = mergeEnv(result.ToolDescriptions, override.ToolDescriptions, MergeModeOverlay)
+
+	if override.MaxDescriptionLength != 0 {
+		result.MaxDescriptionLength = override.MaxDescriptionLength
+	}
+
+	if override.StripSchemaExamples {
+		result.StripSchemaExamples = true
+	}
+
+	// Override disabled flag if set
+	if override.Disabled {
+		result.Disabled = true
+	}
+
+	return result
+}
+
+// mergeEnv merges environment variables based on the merge mode.
+func mergeEnv(base, override map[string]string, mode MergeMode) map[string]string {
+	if len(override) == 0 {
+		return cloneMap(base)
+	}
+
+	if len(base) == 0 || mode == MergeModeReplace {
+		return cloneMap(override)
+	}
+
+	// Overlay mode: merge override on top of base
+	result := cloneMap(base)
+	maps.Copy(result, override)
+
+	return result
+}
+
+// cloneMap creates a copy of a string map.
+func cloneMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(m))
+	maps.Copy(result, m)
+
+	return result
+}
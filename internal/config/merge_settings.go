@@ -0,0 +1,49 @@
+package config
+
+import "maps"
+
+// cloneSettings returns a copy of s that shares no maps or nested settings
+// with it, so merging overrides into the copy leaves s untouched.
+func cloneSettings(s *Settings) *Settings {
+	return &Settings{
+		InstanceName:         s.InstanceName,
+		LogLevel:             s.LogLevel,
+		LogFile:              s.LogFile,
+		Timeout:              s.Timeout,
+		OutputFormat:         s.OutputFormat,
+		Instructions:         s.Instructions,
+		InstructionsSummary:  s.InstructionsSummary,
+		Aliases:              maps.Clone(s.Aliases),
+		Discovery:            s.Discovery.Clone(),
+		CodeMode:             s.CodeMode.Clone(),
+		Socket:               s.Socket.Clone(),
+		Metrics:              s.Metrics.Clone(),
+		Events:               s.Events.Clone(),
+		Features:             maps.Clone(s.Features),
+		Listen:               s.Listen,
+		AuditLog:             s.AuditLog.Clone(),
+		PrefixStrategy:       s.PrefixStrategy,
+		PrefixCollision:      s.PrefixCollision,
+		WatchConfig:          s.WatchConfig,
+		DrainTimeout:         s.DrainTimeout,
+		SecretsStore:         s.SecretsStore,
+		IDs:                  s.IDs.Clone(),
+		SchemaRefs:           s.SchemaRefs.Clone(),
+		PriorityMarker:       s.PriorityMarker,
+		HealthCheck:          s.HealthCheck.Clone(),
+		CallTimeout:          s.CallTimeout.Clone(),
+		ACL:                  s.ACL.Clone(),
+		Clients:              cloneClients(s.Clients),
+		OTel:                 s.OTel.Clone(),
+		Stdio:                s.Stdio.Clone(),
+		WebUI:                s.WebUI.Clone(),
+		ServerLogs:           s.ServerLogs.Clone(),
+		ToolExposure:         s.ToolExposure,
+		MaxDescriptionLength: s.MaxDescriptionLength,
+		StripSchemaExamples:  s.StripSchemaExamples,
+		PageSize:             s.PageSize,
+		MaxResourceSize:      s.MaxResourceSize,
+		ContainerEngine:      s.ContainerEngine,
+		Strict:               s.Strict,
+	}
+}
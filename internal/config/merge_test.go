@@ -1,7 +1,10 @@
 package config_test
 
 import (
+	"maps"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/valksor/go-assern/internal/config"
 )
@@ -66,3 +69,228 @@ func TestRegisterProject(t *testing.T) {
 		t.Error("expected 2 directories")
 	}
 }
+
+func TestBuildEffectiveConfigSettingsOverrides(t *testing.T) {
+	t.Parallel()
+
+	global := &config.Config{
+		Settings: &config.Settings{
+			LogLevel:     "info",
+			Timeout:      60 * time.Second,
+			OutputFormat: "json",
+			Instructions: "Use read-only tools first.",
+		},
+		Projects: map[string]*config.ProjectConfig{
+			"data": {
+				Settings: &config.SettingsOverride{
+					Timeout:      5 * time.Minute,
+					OutputFormat: "toon",
+					Instructions: "Prefer bulk exports.",
+				},
+			},
+			"web": {},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		local   *config.LocalProjectConfig
+		want    config.Settings
+	}{
+		{
+			name:    "project without overrides keeps globals",
+			project: "web",
+			want:    config.Settings{LogLevel: "info", Timeout: 60 * time.Second, OutputFormat: "json", Instructions: "Use read-only tools first."},
+		},
+		{
+			name:    "project overrides",
+			project: "data",
+			want:    config.Settings{LogLevel: "info", Timeout: 5 * time.Minute, OutputFormat: "toon", Instructions: "Prefer bulk exports."},
+		},
+		{
+			name:    "local overrides project",
+			project: "data",
+			local: &config.LocalProjectConfig{
+				Settings: &config.SettingsOverride{LogLevel: "debug", OutputFormat: "json", Instructions: "Local notes."},
+			},
+			want: config.Settings{LogLevel: "debug", Timeout: 5 * time.Minute, OutputFormat: "json", Instructions: "Local notes."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.BuildEffectiveConfig(nil, global, nil, tt.local, tt.project)
+			got := cfg.Settings
+
+			if got.LogLevel != tt.want.LogLevel || got.Timeout != tt.want.Timeout ||
+				got.OutputFormat != tt.want.OutputFormat || got.Instructions != tt.want.Instructions {
+				t.Errorf("settings = {%s %v %s %q}, want {%s %v %s %q}",
+					got.LogLevel, got.Timeout, got.OutputFormat, got.Instructions,
+					tt.want.LogLevel, tt.want.Timeout, tt.want.OutputFormat, tt.want.Instructions)
+			}
+		})
+	}
+
+	if global.Settings.OutputFormat != "json" {
+		t.Error("BuildEffectiveConfig mutated the global settings")
+	}
+}
+
+func TestBuildEffectiveConfigAliases(t *testing.T) {
+	t.Parallel()
+
+	global := &config.Config{
+		Settings: &config.Settings{
+			Aliases: map[string]string{"search": "github_search_code", "issues": "github_list_issues"},
+		},
+		Projects: map[string]*config.ProjectConfig{
+			"work": {
+				Settings: &config.SettingsOverride{
+					Aliases: map[string]string{"issues": "jira_search", "page": "confluence_get_page"},
+				},
+			},
+		},
+	}
+	local := &config.LocalProjectConfig{
+		Settings: &config.SettingsOverride{Aliases: map[string]string{"page": "wiki_get_page"}},
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		local   *config.LocalProjectConfig
+		want    map[string]string
+	}{
+		{
+			name: "global only",
+			want: map[string]string{"search": "github_search_code", "issues": "github_list_issues"},
+		},
+		{
+			name:    "project adds and overrides",
+			project: "work",
+			want:    map[string]string{"search": "github_search_code", "issues": "jira_search", "page": "confluence_get_page"},
+		},
+		{
+			name:    "local overrides project",
+			project: "work",
+			local:   local,
+			want:    map[string]string{"search": "github_search_code", "issues": "jira_search", "page": "wiki_get_page"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.BuildEffectiveConfig(nil, global, nil, tt.local, tt.project)
+			if !maps.Equal(cfg.Settings.Aliases, tt.want) {
+				t.Errorf("aliases = %v, want %v", cfg.Settings.Aliases, tt.want)
+			}
+		})
+	}
+
+	if global.Settings.Aliases["issues"] != "github_list_issues" {
+		t.Error("BuildEffectiveConfig mutated the global aliases")
+	}
+}
+
+func TestBuildEffectiveConfigToolPriority(t *testing.T) {
+	t.Parallel()
+
+	mcp := &config.MCPConfig{
+		MCPServers: map[string]*config.MCPServer{"github": {Command: "github-mcp"}},
+	}
+	global := &config.Config{
+		Settings: &config.Settings{PriorityMarker: "★ "},
+		Projects: map[string]*config.ProjectConfig{
+			"work": {
+				Servers: map[string]*config.ServerConfig{
+					"github": {Priority: 5, ToolPriority: map[string]int{"search": 10}},
+				},
+			},
+		},
+	}
+	local := &config.LocalProjectConfig{
+		Servers: map[string]*config.ServerConfig{
+			"github": {ToolPriority: map[string]int{"search": 20, "issues": -1}},
+		},
+	}
+
+	cfg := config.BuildEffectiveConfig(mcp, global, nil, local, "work")
+
+	srv := cfg.Servers["github"]
+	if srv.Priority != 5 {
+		t.Errorf("priority = %d, want 5", srv.Priority)
+	}
+
+	if want := map[string]int{"search": 20, "issues": -1}; !maps.Equal(srv.ToolPriority, want) {
+		t.Errorf("tool_priority = %v, want %v", srv.ToolPriority, want)
+	}
+
+	if cfg.Settings.PriorityMarker != "★ " {
+		t.Errorf("priority_marker = %q, want %q", cfg.Settings.PriorityMarker, "★ ")
+	}
+
+	if global.Projects["work"].Servers["github"].ToolPriority["search"] != 10 {
+		t.Error("BuildEffectiveConfig mutated the project tool priorities")
+	}
+}
+
+func TestBuildEffectiveConfigDisabledMCPServers(t *testing.T) {
+	t.Parallel()
+
+	globalMCP := &config.MCPConfig{MCPServers: map[string]*config.MCPServer{
+		"github": {Command: "gh", Disabled: true},
+		"jira":   {URL: "https://jira.example.com/mcp"},
+		"fs":     {Command: "fs"},
+	}}
+	localMCP := &config.MCPConfig{MCPServers: map[string]*config.MCPServer{
+		"jira": {URL: "https://jira.example.com/mcp", Disabled: true},
+	}}
+
+	cfg := config.BuildEffectiveConfig(globalMCP, &config.Config{}, localMCP, nil, "")
+
+	got := slices.Sorted(maps.Keys(config.GetEffectiveServers(cfg)))
+	if !slices.Equal(got, []string{"fs"}) {
+		t.Errorf("effective servers = %v, want [fs]", got)
+	}
+}
+
+func TestBuildEffectiveConfigProfile(t *testing.T) {
+	t.Parallel()
+
+	global := &config.Config{
+		Groups:   map[string][]string{"web-dev": {"github"}, "ops": {"k8s"}},
+		Projects: map[string]*config.ProjectConfig{"work": {Profile: "web-dev"}},
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		local   *config.LocalProjectConfig
+		want    string
+	}{
+		{name: "no project", want: ""},
+		{name: "project default", project: "work", want: "web-dev"},
+		{name: "local overrides project", project: "work", local: &config.LocalProjectConfig{Profile: "ops"}, want: "ops"},
+		{name: "local without profile", project: "work", local: &config.LocalProjectConfig{}, want: "web-dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.BuildEffectiveConfig(nil, global, nil, tt.local, tt.project)
+			if cfg.Profile != tt.want {
+				t.Errorf("Profile = %q, want %q", cfg.Profile, tt.want)
+			}
+
+			if len(cfg.Groups) != 2 {
+				t.Errorf("Groups = %v, want the global groups", cfg.Groups)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import "time"
+
+// Metrics exporter defaults.
+const (
+	// DefaultStatsDAddress is the local statsd/Datadog agent UDP endpoint.
+	DefaultStatsDAddress = "127.0.0.1:8125"
+	// DefaultStatsDPrefix is prepended to every metric name.
+	DefaultStatsDPrefix = "assern."
+	// DefaultMetricsInterval is how often server health gauges are reported.
+	DefaultMetricsInterval = 10 * time.Second
+)
+
+// MetricsConfig configures metric exporters.
+type MetricsConfig struct {
+	// StatsD pushes per-tool latency/error metrics and server health gauges
+	// to a statsd or DogStatsD (Datadog agent) endpoint over UDP.
+	StatsD *StatsDConfig `yaml:"statsd,omitempty"`
+	// Interval between server health gauge reports. Zero uses DefaultMetricsInterval.
+	Interval time.Duration `yaml:"interval,omitempty"`
+}
+
+// StatsDConfig configures the statsd exporter.
+type StatsDConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Address is the UDP host:port of the agent. Empty uses DefaultStatsDAddress.
+	Address string `yaml:"address,omitempty"`
+	// Prefix is prepended to metric names. Empty uses DefaultStatsDPrefix.
+	Prefix string `yaml:"prefix,omitempty"`
+	// DogStatsD emits tags in the DogStatsD "|#key:value" format. Plain statsd
+	// has no tags, so tag values are folded into the metric name instead.
+	DogStatsD bool `yaml:"dogstatsd,omitempty"`
+	// Tags are added to every metric (DogStatsD only), e.g. ["env:dev"].
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// StatsDEnabled reports whether the statsd exporter is configured and on.
+func (m *MetricsConfig) StatsDEnabled() bool {
+	return m != nil && m.StatsD != nil && m.StatsD.Enabled
+}
+
+// EffectiveInterval returns the health gauge reporting interval.
+func (m *MetricsConfig) EffectiveInterval() time.Duration {
+	if m == nil || m.Interval <= 0 {
+		return DefaultMetricsInterval
+	}
+
+	return m.Interval
+}
+
+// EffectiveAddress returns the agent address, applying the default.
+func (s *StatsDConfig) EffectiveAddress() string {
+	if s == nil || s.Address == "" {
+		return DefaultStatsDAddress
+	}
+
+	return s.Address
+}
+
+// EffectivePrefix returns the metric name prefix, applying the default.
+func (s *StatsDConfig) EffectivePrefix() string {
+	if s == nil || s.Prefix == "" {
+		return DefaultStatsDPrefix
+	}
+
+	return s.Prefix
+}
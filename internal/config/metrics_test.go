@@ -0,0 +1,70 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestParseMetricsSettings(t *testing.T) {
+	t.Parallel()
+
+	yaml := `
+settings:
+  metrics:
+    interval: 30s
+    statsd:
+      enabled: true
+      address: "10.0.0.5:8125"
+      dogstatsd: true
+      tags: ["env:dev"]
+`
+
+	cfg, err := config.Parse([]byte(yaml), config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	m := cfg.Settings.Metrics
+	if !m.StatsDEnabled() {
+		t.Fatal("statsd should be enabled")
+	}
+
+	if got := m.EffectiveInterval(); got != 30*time.Second {
+		t.Errorf("interval = %v, want 30s", got)
+	}
+
+	if got := m.StatsD.EffectiveAddress(); got != "10.0.0.5:8125" {
+		t.Errorf("address = %q", got)
+	}
+
+	if got := m.StatsD.EffectivePrefix(); got != config.DefaultStatsDPrefix {
+		t.Errorf("prefix = %q, want default", got)
+	}
+
+	clone := cfg.Clone().Settings.Metrics
+	clone.StatsD.Tags[0] = "env:prod"
+
+	if m.StatsD.Tags[0] != "env:dev" {
+		t.Error("Clone() did not deep-copy statsd tags")
+	}
+}
+
+func TestMetricsConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	var m *config.MetricsConfig
+
+	if m.StatsDEnabled() {
+		t.Error("nil metrics config should not enable statsd")
+	}
+
+	if got := m.EffectiveInterval(); got != config.DefaultMetricsInterval {
+		t.Errorf("interval = %v, want default", got)
+	}
+
+	if (&config.MetricsConfig{StatsD: &config.StatsDConfig{}}).StatsDEnabled() {
+		t.Error("statsd without enabled: true should be off")
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Tool naming strategies (settings.prefix_strategy). Any other value is a
+// template such as "{server}.{tool}".
+const (
+	// PrefixStrategyServer names tools "<server>_<tool>" (default).
+	PrefixStrategyServer = "server"
+	// PrefixStrategyNone exposes tools under their backend names.
+	PrefixStrategyNone = "none"
+)
+
+// Template placeholders for a custom prefix_strategy.
+const (
+	PrefixPlaceholderServer = "{server}"
+	PrefixPlaceholderTool   = "{tool}"
+)
+
+// Tool name collision handling (settings.prefix_collision).
+const (
+	// PrefixCollisionSuffix appends "_<server>" to the name of the colliding
+	// tool whose server sorts last (default).
+	PrefixCollisionSuffix = "suffix"
+	// PrefixCollisionError refuses the colliding tool and fails the server.
+	PrefixCollisionError = "error"
+)
+
+// ToolNaming is how exposed tool names are built from backend tool names.
+type ToolNaming struct {
+	// Strategy is PrefixStrategyServer, PrefixStrategyNone or a template.
+	Strategy string
+	// Collision is PrefixCollisionSuffix or PrefixCollisionError.
+	Collision string
+}
+
+// DefaultToolNaming returns the naming used when settings leave it unset:
+// server_tool, with collisions suffixed.
+func DefaultToolNaming() ToolNaming {
+	return ToolNaming{Strategy: PrefixStrategyServer, Collision: PrefixCollisionSuffix}
+}
+
+// ToolNaming returns the naming settings with defaults filled in. s may be
+// nil.
+func (s *Settings) ToolNaming() ToolNaming {
+	naming := DefaultToolNaming()
+
+	if s == nil {
+		return naming
+	}
+
+	if s.PrefixStrategy != "" {
+		naming.Strategy = s.PrefixStrategy
+	}
+
+	if s.PrefixCollision != "" {
+		naming.Collision = s.PrefixCollision
+	}
+
+	return naming
+}
+
+// ToolName returns the exposed name of a backend tool. prefix replaces the
+// server name when set, and still applies under PrefixStrategyNone, so one
+// server can be namespaced while the others are not. Dashes become
+// underscores for client compatibility.
+func (n ToolNaming) ToolName(serverName, prefix, toolName string) string {
+	server := serverName
+	if prefix != "" {
+		server = prefix
+	}
+
+	var name string
+
+	switch n.Strategy {
+	case "", PrefixStrategyServer:
+		name = server + "_" + toolName
+	case PrefixStrategyNone:
+		if prefix != "" {
+			name = prefix + "_" + toolName
+		} else {
+			name = toolName
+		}
+	default:
+		name = strings.NewReplacer(PrefixPlaceholderServer, server, PrefixPlaceholderTool, toolName).Replace(n.Strategy)
+	}
+
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Exposes reports whether name can be the exposed name of a tool of the
+// server, by the shape ToolName gives its names. Under PrefixStrategyNone
+// without a prefix, every name can be.
+func (n ToolNaming) Exposes(serverName, prefix, name string) bool {
+	const marker = "\x00"
+
+	before, after, _ := strings.Cut(n.ToolName(serverName, prefix, marker), marker)
+
+	return len(name) > len(before)+len(after) && strings.HasPrefix(name, before) && strings.HasSuffix(name, after)
+}
+
+// ValidatePrefixStrategy checks settings.prefix_strategy. A template must
+// contain {tool}, and outside its placeholders only the characters allowed
+// in MCP tool names (letters, digits, "_", "-" and ".").
+func ValidatePrefixStrategy(strategy string) error {
+	switch strategy {
+	case "", PrefixStrategyServer, PrefixStrategyNone:
+		return nil
+	}
+
+	if !strings.Contains(strategy, PrefixPlaceholderTool) {
+		return fmt.Errorf("%q is not %q, %q or a template containing %s",
+			strategy, PrefixStrategyServer, PrefixStrategyNone, PrefixPlaceholderTool)
+	}
+
+	literal := strings.NewReplacer(PrefixPlaceholderServer, "", PrefixPlaceholderTool, "").Replace(strategy)
+	if !validToolNameChars(literal) {
+		return fmt.Errorf("template %q may only contain letters, digits, \"_\", \"-\" and \".\" besides its placeholders", strategy)
+	}
+
+	return nil
+}
+
+// ValidatePrefixCollision checks settings.prefix_collision.
+func ValidatePrefixCollision(collision string) error {
+	switch collision {
+	case "", PrefixCollisionSuffix, PrefixCollisionError:
+		return nil
+	}
+
+	return fmt.Errorf("%q is not %q or %q", collision, PrefixCollisionSuffix, PrefixCollisionError)
+}
+
+// ValidatePrefix checks a server's prefix override.
+func ValidatePrefix(prefix string) error {
+	if !validToolNameChars(prefix) {
+		return errors.New("may only contain letters, digits, \"_\", \"-\" and \".\"")
+	}
+
+	return nil
+}
+
+// validateAliases checks tool aliases: each alias must be a valid tool name
+// and point to a tool name.
+func validateAliases(path string, aliases map[string]string) error {
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		if alias == "" || !validToolNameChars(alias) {
+			return fmt.Errorf("%s: alias %q may only contain letters, digits, \"_\", \"-\" and \".\"", path, alias)
+		}
+
+		if aliases[alias] == "" {
+			return fmt.Errorf("%s: alias %q has no target tool", path, alias)
+		}
+	}
+
+	return nil
+}
+
+func validToolNameChars(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToolNamingToolName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		strategy string
+		prefix   string
+		want     string
+	}{
+		{name: "default", want: "my_server_search_repos"},
+		{name: "server", strategy: PrefixStrategyServer, want: "my_server_search_repos"},
+		{name: "server with prefix", strategy: PrefixStrategyServer, prefix: "gh", want: "gh_search_repos"},
+		{name: "none", strategy: PrefixStrategyNone, want: "search_repos"},
+		{name: "none with prefix", strategy: PrefixStrategyNone, prefix: "gh", want: "gh_search_repos"},
+		{name: "template", strategy: "{server}.{tool}", want: "my_server.search_repos"},
+		{name: "template with prefix", strategy: "{tool}__{server}", prefix: "gh", want: "search_repos__gh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			naming := ToolNaming{Strategy: tt.strategy}
+			if got := naming.ToolName("my-server", tt.prefix, "search-repos"); got != tt.want {
+				t.Errorf("ToolName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolNamingExposes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		strategy string
+		prefix   string
+		tool     string
+		want     bool
+	}{
+		{name: "server", tool: "my_server_search", want: true},
+		{name: "other server", tool: "github_search", want: false},
+		{name: "prefix only", tool: "my_server_", want: false},
+		{name: "prefix override", prefix: "gh", tool: "gh_search", want: true},
+		{name: "none", strategy: PrefixStrategyNone, tool: "search", want: true},
+		{name: "template", strategy: "{tool}__{server}", tool: "search__my_server", want: true},
+		{name: "template other server", strategy: "{tool}__{server}", tool: "search__github", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			naming := ToolNaming{Strategy: tt.strategy}
+			if got := naming.Exposes("my-server", tt.prefix, tt.tool); got != tt.want {
+				t.Errorf("Exposes(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettingsToolNaming(t *testing.T) {
+	t.Parallel()
+
+	if got := (*Settings)(nil).ToolNaming(); got != DefaultToolNaming() {
+		t.Errorf("nil settings ToolNaming() = %+v, want defaults", got)
+	}
+
+	s := &Settings{PrefixStrategy: PrefixStrategyNone, PrefixCollision: PrefixCollisionError}
+	if got := s.ToolNaming(); got.Strategy != PrefixStrategyNone || got.Collision != PrefixCollisionError {
+		t.Errorf("ToolNaming() = %+v", got)
+	}
+}
+
+func TestValidatePrefixStrategy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy string
+		wantErr  bool
+	}{
+		{"", false},
+		{PrefixStrategyServer, false},
+		{PrefixStrategyNone, false},
+		{"{server}.{tool}", false},
+		{"x-{tool}", false},
+		{"{server}", true},
+		{"prefix", true},
+		{"{server}/{tool}", true},
+		{"{server} {tool}", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePrefixStrategy(tt.strategy); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePrefixStrategy(%q) error = %v, wantErr %v", tt.strategy, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidatePrefixCollision(t *testing.T) {
+	t.Parallel()
+
+	for _, ok := range []string{"", PrefixCollisionSuffix, PrefixCollisionError} {
+		if err := ValidatePrefixCollision(ok); err != nil {
+			t.Errorf("ValidatePrefixCollision(%q) error = %v", ok, err)
+		}
+	}
+
+	if err := ValidatePrefixCollision("rename"); err == nil {
+		t.Error("ValidatePrefixCollision(\"rename\") expected error")
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			yaml: "settings:\n  aliases:\n    search: github_search_code\nprojects:\n  work:\n    settings:\n      aliases:\n        issues: jira_search\n",
+		},
+		{
+			name:    "invalid alias name",
+			yaml:    "settings:\n  aliases:\n    \"find code\": github_search_code\n",
+			wantErr: `settings.aliases: alias "find code" may only contain`,
+		},
+		{
+			name:    "missing target in project",
+			yaml:    "projects:\n  work:\n    settings:\n      aliases:\n        issues: \"\"\n",
+			wantErr: `projects.work.settings.aliases: alias "issues" has no target tool`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.yaml), LoadOptions{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -98,6 +98,7 @@ func TestBuildEffectiveConfigCarriesSettings(t *testing.T) {
 			Aliases:   map[string]string{"gh": "github_search_repos"},
 			Discovery: &config.DiscoveryConfig{Enabled: true, MaxResults: 5},
 			CodeMode:  &config.CodeModeConfig{Enabled: true, MaxToolCalls: 7},
+			Socket:    &config.SocketConfig{MaxSessions: 4},
 		},
 	}
 
@@ -119,6 +120,10 @@ func TestBuildEffectiveConfigCarriesSettings(t *testing.T) {
 		t.Errorf("code_mode settings were dropped by BuildEffectiveConfig: %+v", eff.Settings.CodeMode)
 	}
 
+	if eff.Settings.Socket.EffectiveMaxSessions() != 4 {
+		t.Errorf("socket settings were dropped: %+v", eff.Settings.Socket)
+	}
+
 	if eff.Settings.Aliases["gh"] != "github_search_repos" {
 		t.Errorf("aliases were dropped: %v", eff.Settings.Aliases)
 	}
@@ -0,0 +1,15 @@
+package config
+
+// LoadOptions are the command-line choices that change how configuration
+// files are loaded.
+type LoadOptions struct {
+	// Strict makes every file fail on unknown keys, as settings.strict
+	// does (--strict-config). Without it unknown keys are ignored.
+	Strict bool
+
+	// Profile limits the servers of an effective configuration to the
+	// group of that name under `groups:` in config.yaml, overriding the
+	// default profile of the project (--profile or ASSERN_PROFILE). Empty
+	// leaves the choice to the project. Only LoadEffective applies it.
+	Profile string
+}
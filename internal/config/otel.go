@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+)
+
+// OpenTelemetry tracing defaults.
+const (
+	// DefaultOTelServiceName is the service.name spans are reported under.
+	DefaultOTelServiceName = "assern"
+	// OTLPTracesPath is the OTLP/HTTP path traces are posted to.
+	OTLPTracesPath = "/v1/traces"
+)
+
+// OTelConfig enables OpenTelemetry tracing of tool calls: a span per
+// tools/call, per backend call and per backend connection, exported over
+// OTLP/HTTP (protobuf) to a collector.
+type OTelConfig struct {
+	// Endpoint is the collector's OTLP/HTTP base URL, e.g.
+	// "http://localhost:4318"; spans go to Endpoint + OTLPTracesPath unless
+	// it already ends with that path. Tracing is off while it is empty.
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// Headers are sent with every export, e.g. an API key.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Sampling is the fraction of traces recorded, from 0 to 1. Zero
+	// records every trace; traces continued from a client keep the
+	// client's decision.
+	Sampling float64 `yaml:"sampling,omitempty"`
+	// ServiceName is the service.name resource attribute. Empty uses
+	// DefaultOTelServiceName.
+	ServiceName string `yaml:"service_name,omitempty"`
+}
+
+// IsEnabled reports whether an endpoint is configured.
+func (o *OTelConfig) IsEnabled() bool {
+	return o != nil && o.Endpoint != ""
+}
+
+// TracesURL returns the URL spans are posted to.
+func (o *OTelConfig) TracesURL() string {
+	if o == nil || o.Endpoint == "" {
+		return ""
+	}
+
+	if strings.HasSuffix(o.Endpoint, OTLPTracesPath) {
+		return o.Endpoint
+	}
+
+	return strings.TrimSuffix(o.Endpoint, "/") + OTLPTracesPath
+}
+
+// EffectiveSampling returns the sampled fraction of traces.
+func (o *OTelConfig) EffectiveSampling() float64 {
+	if o == nil || o.Sampling <= 0 || o.Sampling > 1 {
+		return 1
+	}
+
+	return o.Sampling
+}
+
+// EffectiveServiceName returns the service name, applying the default.
+func (o *OTelConfig) EffectiveServiceName() string {
+	if o == nil || o.ServiceName == "" {
+		return DefaultOTelServiceName
+	}
+
+	return o.ServiceName
+}
+
+// Clone creates a deep copy of the OpenTelemetry config.
+func (o *OTelConfig) Clone() *OTelConfig {
+	if o == nil {
+		return nil
+	}
+
+	return &OTelConfig{
+		Endpoint:    o.Endpoint,
+		Headers:     maps.Clone(o.Headers),
+		Sampling:    o.Sampling,
+		ServiceName: o.ServiceName,
+	}
+}
+
+// ValidateOTel checks the endpoint URL and the sampling fraction.
+func ValidateOTel(o *OTelConfig) error {
+	if o == nil {
+		return nil
+	}
+
+	if o.Endpoint != "" {
+		u, err := url.Parse(o.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint %q is not an http(s) URL", o.Endpoint)
+		}
+	}
+
+	if o.Sampling < 0 || o.Sampling > 1 {
+		return fmt.Errorf("sampling %v is not between 0 and 1", o.Sampling)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/valksor/go-assern/internal/paths"
@@ -22,6 +23,8 @@ const (
 	GlobalConfigFile = "config.yaml"
 	// GlobalMCPFile is the name of the global MCP servers file.
 	GlobalMCPFile = "mcp.json"
+	// GlobalACLFile is the name of the global access control list file.
+	GlobalACLFile = "acl.yaml"
 	// GlobalEnvFile is the name of the global environment file.
 	GlobalEnvFile = ".env"
 	// SocketFile is the name of the Unix socket for instance sharing.
@@ -35,6 +38,10 @@ const (
 	LocalConfigFile = "config.yaml"
 	// LocalMCPFile is the name of the local MCP servers file.
 	LocalMCPFile = "mcp.json"
+
+	// MCPArchiveFile is the name of the file next to an mcp.json holding
+	// the servers deleted from it.
+	MCPArchiveFile = "mcp.archive.json"
 )
 
 // SetHomeDirForTesting overrides the home directory function for testing.
@@ -67,6 +74,12 @@ func GlobalMCPPath() (string, error) {
 	return pathsConfig.GlobalFilePath(GlobalMCPFile)
 }
 
+// GlobalACLPath returns the path to the global access control list file.
+// Default: ~/.valksor/assern/acl.yaml.
+func GlobalACLPath() (string, error) {
+	return pathsConfig.GlobalFilePath(GlobalACLFile)
+}
+
 // SocketPath returns the path to the Unix socket for instance sharing.
 // Default: ~/.valksor/assern/assern.sock.
 func SocketPath() (string, error) {
@@ -84,6 +97,39 @@ func TokensDir() (string, error) {
 	return filepath.Join(dir, "tokens"), nil
 }
 
+// ResourceCacheDir returns the directory where cached resource reads are
+// stored. Default: ~/.valksor/assern/cache/resources/.
+func ResourceCacheDir() (string, error) {
+	dir, err := GlobalDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cache", "resources"), nil
+}
+
+// ToolCacheDir returns the directory where the tool lists of lazy servers
+// are cached. Default: ~/.valksor/assern/cache/tools/.
+func ToolCacheDir() (string, error) {
+	dir, err := GlobalDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cache", "tools"), nil
+}
+
+// ServerLogsDir returns the directory where the stderr of servers is
+// written with settings.server_logs.files. Default: ~/.valksor/assern/logs/.
+func ServerLogsDir() (string, error) {
+	dir, err := GlobalDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "logs"), nil
+}
+
 // LockPath returns the path to the lock file for instance coordination.
 // Default: ~/.valksor/assern/assern.lock.
 func LockPath() (string, error) {
@@ -107,6 +153,34 @@ func LocalMCPPath(assernDir string) string {
 	return pathsConfig.LocalFilePath(assernDir, LocalMCPFile)
 }
 
+// ConfigFiles returns the files LoadEffective reads for workDir: the global
+// config.yaml, mcp.json and acl.yaml and, when workDir is inside a directory with a
+// .assern directory, its mcp.json and config.yaml. The files need not exist.
+func ConfigFiles(workDir string) ([]string, error) {
+	globalConfigPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global config path: %w", err)
+	}
+
+	globalMCPPath, err := GlobalMCPPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global mcp path: %w", err)
+	}
+
+	globalACLPath, err := GlobalACLPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting global acl path: %w", err)
+	}
+
+	files := []string{globalConfigPath, globalMCPPath, globalACLPath}
+
+	if localDir := FindLocalConfigDir(workDir); localDir != "" {
+		files = append(files, LocalMCPPath(localDir), LocalConfigPath(localDir))
+	}
+
+	return files, nil
+}
+
 // EnsureGlobalDir creates the global configuration directory if it doesn't exist.
 func EnsureGlobalDir() (string, error) {
 	return pathsConfig.EnsureGlobalDir()
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -367,3 +368,37 @@ func TestExpandPath(t *testing.T) {
 		}
 	})
 }
+
+func TestConfigFiles(t *testing.T) {
+	home := mockHomeDir(t)
+	globalDir := filepath.Join(home, ".valksor", "assern")
+
+	files, err := ConfigFiles(home)
+	if err != nil {
+		t.Fatalf("ConfigFiles() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(globalDir, "config.yaml"),
+		filepath.Join(globalDir, "mcp.json"),
+		filepath.Join(globalDir, "acl.yaml"),
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("ConfigFiles() = %v, want %v", files, want)
+	}
+
+	project := filepath.Join(home, "project")
+	if err := os.MkdirAll(filepath.Join(project, ".assern"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err = ConfigFiles(project)
+	if err != nil {
+		t.Fatalf("ConfigFiles() error = %v", err)
+	}
+
+	want = append(want, filepath.Join(project, ".assern", "mcp.json"), filepath.Join(project, ".assern", "config.yaml"))
+	if !slices.Equal(files, want) {
+		t.Errorf("ConfigFiles() = %v, want %v", files, want)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ProfileAll is the profile that selects every server, overriding a
+// project's default profile.
+const ProfileAll = "all"
+
+// EnvProfile is the environment variable that selects a profile when
+// --profile is not given.
+const EnvProfile = "ASSERN_PROFILE"
+
+// applyProfile limits the servers of c to the members of the profile name,
+// or of c.Profile when name is empty. Members no mcp.json defines are
+// ignored.
+func (c *Config) applyProfile(name string) error {
+	if name != "" {
+		c.Profile = name
+	}
+
+	if c.Profile == "" || c.Profile == ProfileAll {
+		return nil
+	}
+
+	members, ok := c.Groups[c.Profile]
+	if !ok {
+		if len(c.Groups) == 0 {
+			return fmt.Errorf("unknown profile %q: config.yaml defines no groups", c.Profile)
+		}
+
+		return fmt.Errorf("unknown profile %q (groups: %s)", c.Profile, strings.Join(slices.Sorted(maps.Keys(c.Groups)), ", "))
+	}
+
+	maps.DeleteFunc(c.Servers, func(name string, _ *ServerConfig) bool { return !slices.Contains(members, name) })
+
+	return nil
+}
+
+// validateGroups checks the group names and the default profiles of the
+// projects.
+func validateGroups(groups map[string][]string, projects map[string]*ProjectConfig) error {
+	if _, ok := groups[ProfileAll]; ok {
+		return fmt.Errorf("groups.%s: the name is reserved for the profile that selects every server", ProfileAll)
+	}
+
+	for name := range groups {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("groups: empty group name")
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(projects)) {
+		profile := projects[name].Profile
+		if _, ok := groups[profile]; ok || profile == "" || profile == ProfileAll {
+			continue
+		}
+
+		return fmt.Errorf("projects.%s.profile: unknown group %q", name, profile)
+	}
+
+	return nil
+}
+
+// cloneGroups copies groups and their member lists.
+func cloneGroups(groups map[string][]string) map[string][]string {
+	if groups == nil {
+		return nil
+	}
+
+	clone := make(map[string][]string, len(groups))
+	for name, members := range groups {
+		clone[name] = slices.Clone(members)
+	}
+
+	return clone
+}
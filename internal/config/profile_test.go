@@ -0,0 +1,110 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApplyProfile(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string][]string{
+		"web-dev": {"github", "browser", "missing"},
+		"ops":     {"k8s"},
+	}
+
+	tests := []struct {
+		name     string
+		profile  string // the project's default
+		override string // --profile
+		want     []string
+		wantErr  string
+	}{
+		{name: "no profile", want: []string{"browser", "github", "k8s", "slack"}},
+		{name: "project default", profile: "ops", want: []string{"k8s"}},
+		{name: "override wins", profile: "ops", override: "web-dev", want: []string{"browser", "github"}},
+		{name: "all overrides the default", profile: "ops", override: ProfileAll, want: []string{"browser", "github", "k8s", "slack"}},
+		{name: "unknown profile", override: "mobile", wantErr: `unknown profile "mobile" (groups: ops, web-dev)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := NewConfig()
+			cfg.Groups = groups
+			cfg.Profile = tt.profile
+
+			for _, name := range []string{"github", "browser", "k8s", "slack"} {
+				cfg.Servers[name] = &ServerConfig{Command: name}
+			}
+
+			err := cfg.applyProfile(tt.override)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("applyProfile() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyProfile() error = %v", err)
+			}
+
+			if got := slices.Sorted(maps.Keys(cfg.Servers)); !slices.Equal(got, tt.want) {
+				t.Errorf("servers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGroups(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: "groups:\n  web-dev: [github]\nprojects:\n  work:\n    profile: web-dev\n  home:\n    profile: all\n",
+		},
+		{
+			name:    "reserved name",
+			data:    "groups:\n  all: [github]\n",
+			wantErr: "groups.all: the name is reserved",
+		},
+		{
+			name:    "unknown project profile",
+			data:    "groups:\n  web-dev: [github]\nprojects:\n  work:\n    profile: mobile\n",
+			wantErr: `projects.work.profile: unknown group "mobile"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Parse([]byte(tt.data), LoadOptions{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if cfg.Projects["work"].Profile != "web-dev" || len(cfg.Groups["web-dev"]) != 1 {
+				t.Errorf("Parse() = groups %v, work profile %q", cfg.Groups, cfg.Projects["work"].Profile)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"maps"
+	"time"
+)
+
+// SettingsOverride holds the subset of Settings a project may override, so a
+// data-heavy project can default to TOON output and long timeouts while other
+// projects keep the global defaults. Zero values leave the setting unchanged.
+type SettingsOverride struct {
+	LogLevel     string        `yaml:"log_level,omitempty"`
+	Timeout      time.Duration `yaml:"timeout,omitempty"`
+	OutputFormat string        `yaml:"output_format,omitempty"` // "json" or "toon"
+	Instructions string        `yaml:"instructions,omitempty"`  // Replaces the global instructions
+	// Features sets feature flags for this project; unset flags keep the
+	// global value
+	Features map[string]bool `yaml:"features,omitempty"`
+	// Aliases adds tool aliases for this project; an alias also defined
+	// globally points to the project's target
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// applyTo overwrites the settings that the override sets.
+func (o *SettingsOverride) applyTo(s *Settings) {
+	if o == nil || s == nil {
+		return
+	}
+
+	if o.LogLevel != "" {
+		s.LogLevel = o.LogLevel
+	}
+
+	if o.Timeout > 0 {
+		s.Timeout = o.Timeout
+	}
+
+	if o.OutputFormat != "" {
+		s.OutputFormat = o.OutputFormat
+	}
+
+	if o.Instructions != "" {
+		s.Instructions = o.Instructions
+	}
+
+	if len(o.Features) > 0 {
+		if s.Features == nil {
+			s.Features = make(map[string]bool, len(o.Features))
+		}
+
+		maps.Copy(s.Features, o.Features)
+	}
+
+	if len(o.Aliases) > 0 {
+		if s.Aliases == nil {
+			s.Aliases = make(map[string]string, len(o.Aliases))
+		}
+
+		maps.Copy(s.Aliases, o.Aliases)
+	}
+}
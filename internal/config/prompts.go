@@ -0,0 +1,66 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// PromptFilter limits and renames the prompts of a server, like Allowed
+// does for tools. Names are the backend's unprefixed prompt names.
+type PromptFilter struct {
+	// Allowed lists the prompts to expose; empty exposes all of them.
+	Allowed []string `yaml:"allowed,omitempty"`
+	// Blocked lists prompts to hide, applied after Allowed.
+	Blocked []string `yaml:"blocked,omitempty"`
+	// Rename maps a prompt's name to the name it is exposed under, before
+	// the server prefix is added.
+	Rename map[string]string `yaml:"rename,omitempty"`
+}
+
+// Exposes reports whether the prompt named name passes the filter. A nil
+// filter exposes every prompt.
+func (f *PromptFilter) Exposes(name string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.Allowed) > 0 && !slices.Contains(f.Allowed, name) {
+		return false
+	}
+
+	return !slices.Contains(f.Blocked, name)
+}
+
+// ExposedName returns the name a prompt is exposed under: its Rename entry,
+// or the name itself.
+func (f *PromptFilter) ExposedName(name string) string {
+	if f == nil || f.Rename[name] == "" {
+		return name
+	}
+
+	return f.Rename[name]
+}
+
+// Clone creates a deep copy of the prompt filter.
+func (f *PromptFilter) Clone() *PromptFilter {
+	if f == nil {
+		return nil
+	}
+
+	return &PromptFilter{
+		Allowed: slices.Clone(f.Allowed),
+		Blocked: slices.Clone(f.Blocked),
+		Rename:  maps.Clone(f.Rename),
+	}
+}
+
+// Equal compares two prompt filters for equality.
+func (f *PromptFilter) Equal(other *PromptFilter) bool {
+	if f == nil || other == nil {
+		return f == other
+	}
+
+	return slices.Equal(f.Allowed, other.Allowed) &&
+		slices.Equal(f.Blocked, other.Blocked) &&
+		maps.Equal(f.Rename, other.Rename)
+}
@@ -0,0 +1,69 @@
+package config
+
+import "testing"
+
+func TestPromptFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		filter      *PromptFilter
+		prompt      string
+		wantExposed bool
+		wantName    string
+	}{
+		{
+			name:        "nil filter",
+			prompt:      "review",
+			wantExposed: true,
+			wantName:    "review",
+		},
+		{
+			name:        "allowed",
+			filter:      &PromptFilter{Allowed: []string{"review"}},
+			prompt:      "review",
+			wantExposed: true,
+			wantName:    "review",
+		},
+		{
+			name:     "not allowed",
+			filter:   &PromptFilter{Allowed: []string{"review"}},
+			prompt:   "summarize",
+			wantName: "summarize",
+		},
+		{
+			name:     "blocked wins over allowed",
+			filter:   &PromptFilter{Allowed: []string{"review"}, Blocked: []string{"review"}},
+			prompt:   "review",
+			wantName: "review",
+		},
+		{
+			name:        "renamed",
+			filter:      &PromptFilter{Rename: map[string]string{"code-review": "review"}},
+			prompt:      "code-review",
+			wantExposed: true,
+			wantName:    "review",
+		},
+		{
+			name:        "empty rename keeps name",
+			filter:      &PromptFilter{Rename: map[string]string{"code-review": ""}},
+			prompt:      "code-review",
+			wantExposed: true,
+			wantName:    "code-review",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.filter.Exposes(tt.prompt); got != tt.wantExposed {
+				t.Errorf("Exposes(%q) = %v, want %v", tt.prompt, got, tt.wantExposed)
+			}
+
+			if got := tt.filter.ExposedName(tt.prompt); got != tt.wantName {
+				t.Errorf("ExposedName(%q) = %q, want %q", tt.prompt, got, tt.wantName)
+			}
+		})
+	}
+}
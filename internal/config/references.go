@@ -0,0 +1,50 @@
+package config
+
+import "regexp"
+
+// referencePattern matches the ${VAR} references of connection settings.
+var referencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandReferences replaces the ${VAR} references in value with
+// lookup(VAR). Unlike env values, only the braced form is expanded: a bare
+// $, as in a regular expression argument, is left as written.
+func ExpandReferences(value string, lookup func(string) string) string {
+	if !referencePattern.MatchString(value) {
+		return value
+	}
+
+	return referencePattern.ReplaceAllStringFunc(value, func(ref string) string {
+		return lookup(ref[2 : len(ref)-1])
+	})
+}
+
+// WithExpandedReferences returns a copy of s whose args, work directory,
+// image, volumes, URL and header values have their ${VAR} references
+// expanded with lookup (see ExpandReferences), to connect to the server
+// with. s keeps the references, so printing or comparing it never involves
+// their values.
+func (s *ServerConfig) WithExpandedReferences(lookup func(string) string) *ServerConfig {
+	if s == nil {
+		return nil
+	}
+
+	expanded := s.Clone()
+
+	for i, arg := range expanded.Args {
+		expanded.Args[i] = ExpandReferences(arg, lookup)
+	}
+
+	for name, value := range expanded.Headers {
+		expanded.Headers[name] = ExpandReferences(value, lookup)
+	}
+
+	for i, volume := range expanded.Volumes {
+		expanded.Volumes[i] = ExpandReferences(volume, lookup)
+	}
+
+	expanded.WorkDir = ExpandReferences(expanded.WorkDir, lookup)
+	expanded.Image = ExpandReferences(expanded.Image, lookup)
+	expanded.URL = ExpandReferences(expanded.URL, lookup)
+
+	return expanded
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpandReferences(t *testing.T) {
+	t.Parallel()
+
+	lookup := func(name string) string {
+		return map[string]string{"TOKEN": "s3cret", "HOST": "example.com"}[name]
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Bearer ${TOKEN}", want: "Bearer s3cret"},
+		{value: "https://${HOST}/mcp?key=${TOKEN}", want: "https://example.com/mcp?key=s3cret"},
+		{value: "${MISSING}", want: ""},
+		{value: "$TOKEN", want: "$TOKEN"},
+		{value: "^v[0-9]+$", want: "^v[0-9]+$"},
+		{value: "${not valid}", want: "${not valid}"},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ExpandReferences(tt.value, lookup); got != tt.want {
+				t.Errorf("ExpandReferences(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithExpandedReferences(t *testing.T) {
+	t.Parallel()
+
+	lookup := func(name string) string { return "<" + name + ">" }
+
+	srv := &ServerConfig{
+		Command: "${CMD}",
+		Args:    []string{"--root", "${ROOT}"},
+		Env:     map[string]string{"KEY": "${KEY}"},
+		WorkDir: "${HOME}/src",
+		URL:     "https://${HOST}/mcp",
+		Headers: map[string]string{"Authorization": "Bearer ${API_TOKEN}"},
+	}
+
+	got := srv.WithExpandedReferences(lookup)
+
+	if want := []string{"--root", "<ROOT>"}; !slices.Equal(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+
+	if got.WorkDir != "<HOME>/src" {
+		t.Errorf("WorkDir = %q, want <HOME>/src", got.WorkDir)
+	}
+
+	if got.URL != "https://<HOST>/mcp" {
+		t.Errorf("URL = %q, want https://<HOST>/mcp", got.URL)
+	}
+
+	if got.Headers["Authorization"] != "Bearer <API_TOKEN>" {
+		t.Errorf("Authorization header = %q, want Bearer <API_TOKEN>", got.Headers["Authorization"])
+	}
+
+	// The command and env are resolved elsewhere and kept as written
+	if got.Command != "${CMD}" || got.Env["KEY"] != "${KEY}" {
+		t.Errorf("Command = %q, Env = %v; want them unexpanded", got.Command, got.Env)
+	}
+
+	if srv.Args[1] != "${ROOT}" || srv.Headers["Authorization"] != "Bearer ${API_TOKEN}" || srv.URL != "https://${HOST}/mcp" {
+		t.Errorf("original config was modified: %+v", srv)
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"slices"
+	"time"
+)
+
+// ResourceFilter limits which resources of a server are exposed, like
+// Allowed does for tools. A resource is exposed when it matches one of
+// MIMETypes (if any are listed) and one of URIs (if any are listed).
+type ResourceFilter struct {
+	// MIMETypes lists accepted MIME types, e.g. "text/markdown" or "text/*".
+	// Parameters such as "; charset=utf-8" are ignored when matching.
+	MIMETypes []string `yaml:"mime_types,omitempty"`
+	// URIs lists URI globs: "*" matches within one path segment and a
+	// trailing "/**" matches any depth, e.g. "file:///repo/docs/**".
+	URIs []string `yaml:"uris,omitempty"`
+}
+
+// Clone creates a deep copy of the resource filter.
+func (f *ResourceFilter) Clone() *ResourceFilter {
+	if f == nil {
+		return nil
+	}
+
+	return &ResourceFilter{
+		MIMETypes: slices.Clone(f.MIMETypes),
+		URIs:      slices.Clone(f.URIs),
+	}
+}
+
+// Equal compares two resource filters for equality.
+func (f *ResourceFilter) Equal(other *ResourceFilter) bool {
+	if f == nil || other == nil {
+		return f == other
+	}
+
+	return slices.Equal(f.MIMETypes, other.MIMETypes) && slices.Equal(f.URIs, other.URIs)
+}
+
+// Resource cache defaults.
+const (
+	// DefaultResourceCacheTTL is how long a cached resource is served before
+	// it is revalidated against the backend.
+	DefaultResourceCacheTTL = time.Hour
+	// DefaultResourceCacheMaxSize is the largest read that is cached.
+	DefaultResourceCacheMaxSize ByteSize = 10 << 20
+)
+
+// ResourceCacheConfig marks a server's resources as cacheable. Reads are
+// stored on disk under ResourceCacheDir, served from there until TTL
+// passes, and then revalidated against the resource's size and
+// lastModified annotation in the backend's resources/list before being
+// downloaded again.
+type ResourceCacheConfig struct {
+	// TTL is how long a read is served from disk without contacting the
+	// backend. Zero uses DefaultResourceCacheTTL.
+	TTL time.Duration `yaml:"ttl,omitempty"`
+	// URIs limits caching to matching resources, with the globs of
+	// ResourceFilter.URIs. Empty caches every resource of the server.
+	URIs []string `yaml:"uris,omitempty"`
+	// MaxSize skips caching reads larger than this. Zero uses
+	// DefaultResourceCacheMaxSize.
+	MaxSize ByteSize `yaml:"max_size,omitempty"`
+}
+
+// EffectiveTTL returns the cache TTL, applying the default.
+func (c *ResourceCacheConfig) EffectiveTTL() time.Duration {
+	if c == nil || c.TTL <= 0 {
+		return DefaultResourceCacheTTL
+	}
+
+	return c.TTL
+}
+
+// EffectiveMaxSize returns the largest cached read, applying the default.
+func (c *ResourceCacheConfig) EffectiveMaxSize() ByteSize {
+	if c == nil || c.MaxSize <= 0 {
+		return DefaultResourceCacheMaxSize
+	}
+
+	return c.MaxSize
+}
+
+// Clone creates a deep copy of the resource cache config.
+func (c *ResourceCacheConfig) Clone() *ResourceCacheConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+	clone.URIs = slices.Clone(c.URIs)
+
+	return &clone
+}
+
+// Equal compares two resource cache configs for equality.
+func (c *ResourceCacheConfig) Equal(other *ResourceCacheConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+
+	return c.TTL == other.TTL && c.MaxSize == other.MaxSize && slices.Equal(c.URIs, other.URIs)
+}
@@ -0,0 +1,448 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	jskind "github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaBaseURL is where the JSON Schemas of the configuration files are
+// published (docs/schema in the repository).
+const SchemaBaseURL = "https://valksor.com/docs/assern/schema/"
+
+// SchemaKind names a configuration file format with a JSON Schema.
+type SchemaKind string
+
+const (
+	// SchemaMCP describes mcp.json, global or local.
+	SchemaMCP SchemaKind = "mcp"
+	// SchemaConfig describes the global config.yaml.
+	SchemaConfig SchemaKind = "yaml"
+	// SchemaLocalConfig describes the config.yaml of a .assern directory.
+	SchemaLocalConfig SchemaKind = "local"
+)
+
+// SchemaKinds lists every kind of schema, in the order they are documented.
+var SchemaKinds = []SchemaKind{SchemaMCP, SchemaConfig, SchemaLocalConfig}
+
+// schemaSources holds, for each kind, the type the file decodes into, the
+// struct tag naming its keys and the schema's file name and title.
+var schemaSources = map[SchemaKind]struct {
+	value any
+	tag   string
+	file  string
+	title string
+}{
+	SchemaMCP:         {value: MCPConfig{}, tag: "json", file: "mcp.schema.json", title: "Assern mcp.json"},
+	SchemaConfig:      {value: Config{}, tag: "yaml", file: "config.schema.json", title: "Assern config.yaml"},
+	SchemaLocalConfig: {value: LocalProjectConfig{}, tag: "yaml", file: "local-config.schema.json", title: "Assern .assern/config.yaml"},
+}
+
+// schemaEnums restricts string fields, keyed by "Type.Field", to the values
+// assern accepts.
+var schemaEnums = map[string][]string{
+	"MCPServer.Transport":        validTransports,
+	"ServerConfig.Transport":     validTransports,
+	"ServerConfig.MergeMode":     {string(MergeModeOverlay), string(MergeModeReplace)},
+	"ServerConfig.RestartPolicy": {RestartOnFailure, RestartNever},
+	"Settings.ToolExposure":      {ToolExposureAll, ToolExposureSearch},
+	"Settings.ContainerEngine":   {ContainerEngineDocker, ContainerEnginePodman},
+}
+
+// ParseSchemaKind returns the kind named by s.
+func ParseSchemaKind(s string) (SchemaKind, error) {
+	kind := SchemaKind(s)
+	if _, ok := schemaSources[kind]; !ok {
+		return "", fmt.Errorf("unknown schema %q (want mcp, yaml or local)", s)
+	}
+
+	return kind, nil
+}
+
+// FileName returns the file name the schema is published under.
+func (k SchemaKind) FileName() string {
+	return schemaSources[k].file
+}
+
+// URL returns the address the schema is published at, for "$schema" in
+// mcp.json and the yaml-language-server comment of config.yaml.
+func (k SchemaKind) URL() string {
+	return SchemaBaseURL + k.FileName()
+}
+
+// SchemaComment returns the comment that points YAML editors (through
+// yaml-language-server) at the schema of a config.yaml.
+func SchemaComment(kind SchemaKind) string {
+	return "# yaml-language-server: $schema=" + kind.URL()
+}
+
+// Schema returns the JSON Schema of a configuration file, generated from the
+// Go types it decodes into so that it always matches what assern reads.
+func Schema(kind SchemaKind) ([]byte, error) {
+	doc, err := schemaDocument(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encoding %s schema: %w", kind, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func schemaDocument(kind SchemaKind) (map[string]any, error) {
+	source, ok := schemaSources[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", kind)
+	}
+
+	g := &schemaGenerator{tag: source.tag, defs: make(map[string]any)}
+
+	doc := g.object(reflect.TypeOf(source.value))
+	doc["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	doc["$id"] = kind.URL()
+	doc["title"] = source.title
+	doc["$defs"] = g.defs
+
+	return doc, nil
+}
+
+// schemaGenerator builds a schema from Go types, like unknownFields reads
+// their keys: struct fields by tag, maps as objects, slices as arrays.
+// Named structs other than the root are shared under $defs.
+type schemaGenerator struct {
+	tag  string
+	defs map[string]any
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case durationType:
+		if g.tag == "json" {
+			// encoding/json reads durations as nanoseconds
+			return map[string]any{"type": "integer", "minimum": 0}
+		}
+
+		// "30s", "2 minutes" (see ParseDuration); 0 is the only bare number
+		return map[string]any{"type": []string{"string", "integer"}}
+	case byteSizeType:
+		// 1048576 or "1MiB" (see ParseSize)
+		return map[string]any{"type": []string{"integer", "string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return map[string]any{"type": "object"}
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // Placeholder for recursive types
+			g.defs[t.Name()] = g.object(t)
+		}
+
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// object returns the schema of a struct: its tagged fields, and no others.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+
+	for name, field := range taggedFields(t, g.tag) {
+		schema := g.schemaFor(field.Type)
+		if values, ok := schemaEnums[t.Name()+"."+field.Name]; ok {
+			schema["enum"] = values
+		}
+
+		properties[name] = schema
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// schemaPrinter formats validation messages.
+var schemaPrinter = message.NewPrinter(language.English)
+
+// compiledSchemas caches the compiled schema of each kind.
+var compiledSchemas sync.Map // SchemaKind -> *jsonschema.Schema
+
+// compiledSchema returns the schema of kind, ready to validate documents.
+func compiledSchema(kind SchemaKind) (*jsonschema.Schema, error) {
+	if sch, ok := compiledSchemas.Load(kind); ok {
+		return sch.(*jsonschema.Schema), nil
+	}
+
+	data, err := Schema(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s schema: %w", kind, err)
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(kind.URL(), doc); err != nil {
+		return nil, fmt.Errorf("adding %s schema: %w", kind, err)
+	}
+
+	sch, err := c.Compile(kind.URL())
+	if err != nil {
+		return nil, fmt.Errorf("compiling %s schema: %w", kind, err)
+	}
+
+	compiledSchemas.Store(kind, sch)
+
+	return sch, nil
+}
+
+// SchemaViolation is a value that does not match the schema of its file.
+type SchemaViolation struct {
+	Field   string // Path of the value, as in FieldError
+	Line    int
+	Message string
+	// Unknown is set for keys the schema does not declare, which assern
+	// ignores unless in strict mode.
+	Unknown bool
+}
+
+// ValidateSchema checks data, a configuration file of the given kind (JSONC
+// for mcp.json), against its schema and returns every violation found.
+func ValidateSchema(kind SchemaKind, data []byte) ([]SchemaViolation, error) {
+	sch, err := compiledSchema(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == SchemaMCP {
+		data = standardizeJSON(data)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", kind, err)
+	}
+
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+
+	err = sch.Validate(nodeValue(&doc))
+	if err == nil {
+		return nil, nil
+	}
+
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, fmt.Errorf("validating %s: %w", kind, err)
+	}
+
+	var violations []SchemaViolation
+
+	for _, leaf := range schemaLeaves(verr) {
+		node, field := locateNode(&doc, leaf.InstanceLocation)
+
+		if typeErr, ok := leaf.ErrorKind.(*jskind.Type); ok && decodesAnyway(kind, node, typeErr) {
+			continue
+		}
+
+		violation := SchemaViolation{
+			Field:   field,
+			Line:    node.Line,
+			Message: leaf.ErrorKind.LocalizedString(schemaPrinter),
+		}
+
+		if enumErr, ok := leaf.ErrorKind.(*jskind.Enum); ok {
+			got, _ := json.Marshal(enumErr.Got)
+			violation.Message = fmt.Sprintf("invalid value %s: %s", got, violation.Message)
+		}
+
+		if extra, ok := leaf.ErrorKind.(*jskind.AdditionalProperties); ok {
+			// One violation per unknown key, at the key
+			for _, key := range extra.Properties {
+				violations = append(violations, SchemaViolation{
+					Field:   joinPath(field, key),
+					Line:    keyLine(node, key),
+					Message: ErrUnknownField.Error(),
+					Unknown: true,
+				})
+			}
+
+			continue
+		}
+
+		violations = append(violations, violation)
+	}
+
+	return violations, nil
+}
+
+// decodesAnyway reports whether the decoder accepts a value the schema
+// types differently: null leaves any field unset, and yaml.v3 reads any
+// scalar into a string (e.g. `PORT: 8080` in env).
+func decodesAnyway(kind SchemaKind, node *yaml.Node, typeErr *jskind.Type) bool {
+	if node.ShortTag() == "!!null" {
+		return true
+	}
+
+	return kind != SchemaMCP && node.Kind == yaml.ScalarNode && slices.Contains(typeErr.Want, "string")
+}
+
+// schemaLeaves returns the errors under err that have no causes: the
+// violations themselves rather than the schemas they were found under.
+func schemaLeaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, schemaLeaves(cause)...)
+	}
+
+	return leaves
+}
+
+// nodeValue converts a YAML node into the values encoding/json would
+// produce, which is what the validator expects. Mapping keys become
+// strings.
+func nodeValue(node *yaml.Node) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			m[node.Content[i].Value] = nodeValue(node.Content[i+1])
+		}
+
+		return m
+	case yaml.SequenceNode:
+		s := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			s = append(s, nodeValue(child))
+		}
+
+		return s
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		return nil
+	case "!!bool":
+		var b bool
+		if node.Decode(&b) == nil {
+			return b
+		}
+	case "!!int":
+		var n int64
+		if node.Decode(&n) == nil {
+			return n
+		}
+	case "!!float":
+		var f float64
+		if node.Decode(&f) == nil {
+			return f
+		}
+	}
+
+	return node.Value
+}
+
+// locateNode follows an instance location from the root of doc and returns
+// the node there with its field path ("servers.github.args[0]").
+func locateNode(doc *yaml.Node, location []string) (*yaml.Node, string) {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	path := ""
+
+	for _, token := range location {
+		switch node.Kind {
+		case yaml.MappingNode:
+			next := node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+				}
+			}
+
+			node, path = next, joinPath(path, token)
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return node, path
+			}
+
+			node, path = node.Content[i], fmt.Sprintf("%s[%d]", path, i)
+		default:
+			return node, path
+		}
+	}
+
+	return node, path
+}
+
+// keyLine returns the line of key in a mapping node, or of the node itself.
+func keyLine(node *yaml.Node, key string) int {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i].Line
+			}
+		}
+	}
+
+	return node.Line
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestPublishedSchemas keeps docs/schema, which the documentation site
+// serves at SchemaBaseURL, in step with the configuration types.
+func TestPublishedSchemas(t *testing.T) {
+	t.Parallel()
+
+	for _, kind := range SchemaKinds {
+		t.Run(string(kind), func(t *testing.T) {
+			t.Parallel()
+
+			want, err := Schema(kind)
+			if err != nil {
+				t.Fatalf("Schema() error = %v", err)
+			}
+
+			path := filepath.Join("..", "..", "docs", "schema", kind.FileName())
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading published schema: %v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s is out of date; regenerate it with: assern config schema %s > docs/schema/%s", path, kind, kind.FileName())
+			}
+
+			if _, err := compiledSchema(kind); err != nil {
+				t.Errorf("compiledSchema() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		kind SchemaKind
+		data string
+		want []string // "line field: message", sorted
+	}{
+		{
+			name: "valid mcp.json",
+			kind: SchemaMCP,
+			data: `{
+  "$schema": "https://valksor.com/docs/assern/schema/mcp.schema.json",
+  // JSONC is fine
+  "mcpServers": {"github": {"command": "gh", "args": ["mcp"], "env": null, "oauth": {"clockSkew": 1000000000}}},
+}`,
+		},
+		{
+			name: "mcp.json types and values",
+			kind: SchemaMCP,
+			data: `{
+  "mcpServers": {
+    "a": {"command": 1, "transport": "ws"},
+    "b": {"url": "https://x", "env": {"PORT": 8080}, "bogus": true}
+  }
+}`,
+			want: []string{
+				"3 mcpServers.a.command: got number, want string",
+				`3 mcpServers.a.transport: invalid value "ws": value must be one of`,
+				"4 mcpServers.b.bogus: unknown field",
+				"4 mcpServers.b.env.PORT: got number, want string",
+			},
+		},
+		{
+			name: "valid config.yaml",
+			kind: SchemaConfig,
+			data: `projects:
+  work:
+    directories: [~/work]
+    env:
+      PORT: 8080
+      DEBUG: true
+settings:
+  timeout: 1 minute
+  log_level: debug
+`,
+		},
+		{
+			name: "config.yaml types and values",
+			kind: SchemaConfig,
+			data: `projects:
+  work:
+    directories: ~/work
+    servers:
+      github:
+        merge_mode: merge
+        max_concurrency: lots
+`,
+			want: []string{
+				"3 projects.work.directories: got string, want array",
+				`6 projects.work.servers.github.merge_mode: invalid value "merge": value must be one of 'overlay', 'replace'`,
+				"7 projects.work.servers.github.max_concurrency: got string, want integer",
+			},
+		},
+		{
+			name: "local config.yaml",
+			kind: SchemaLocalConfig,
+			data: "project: demo\nservers:\n  github:\n    restart_policy: always\nprojects: {}\n",
+			want: []string{
+				`4 servers.github.restart_policy: invalid value "always": value must be one of 'on-failure', 'never'`,
+				"5 projects: unknown field",
+			},
+		},
+		{name: "empty file", kind: SchemaConfig, data: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations, err := ValidateSchema(tt.kind, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ValidateSchema() error = %v", err)
+			}
+
+			var got []string
+			for _, v := range violations {
+				got = append(got, fmt.Sprintf("%d %s: %s", v.Line, v.Field, v.Message))
+			}
+
+			slices.Sort(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateSchema() = %d violations, want %d:\n%s", len(got), len(tt.want), strings.Join(got, "\n"))
+			}
+
+			for i := range tt.want {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("violation %d = %q, want it to start with %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaReferences(t *testing.T) {
+	t.Parallel()
+
+	// "$schema" is a known key, so strict mode accepts it and it survives
+	// a round trip
+	cfg, err := parseMCPConfig([]byte(`{"$schema": "`+SchemaMCP.URL()+`", "mcpServers": {}}`), true)
+	if err != nil {
+		t.Fatalf("parseMCPConfig() error = %v", err)
+	}
+
+	if cfg.Clone().Schema != SchemaMCP.URL() {
+		t.Errorf("Clone().Schema = %q, want %q", cfg.Clone().Schema, SchemaMCP.URL())
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := (&Config{Settings: DefaultSettings()}).Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first, _, _ := strings.Cut(string(data), "\n"); first != SchemaComment(SchemaConfig) {
+		t.Errorf("first line = %q, want %q", first, SchemaComment(SchemaConfig))
+	}
+
+	if _, err := Parse(data, LoadOptions{}); err != nil {
+		t.Errorf("Parse() of saved config error = %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+// DefaultSchemaRefsMaxBytes is the largest input schema produced by inlining
+// $refs; a tool whose inlined schema would be larger keeps its references.
+const DefaultSchemaRefsMaxBytes ByteSize = 64 << 10
+
+// SchemaRefsConfig controls how the $refs in tool input schemas reach
+// clients. Some clients cannot resolve "$ref": "#/$defs/..." and reject or
+// misread such tools, so the references can be replaced by copies of their
+// targets when tools are registered.
+type SchemaRefsConfig struct {
+	// Inline replaces local $refs with their targets. A reference inside
+	// its own target (a cycle) is kept, along with the definitions it
+	// needs. Off by default.
+	Inline bool `yaml:"inline,omitempty"`
+	// MaxBytes caps the size of an inlined schema. Zero uses
+	// DefaultSchemaRefsMaxBytes.
+	MaxBytes ByteSize `yaml:"max_bytes,omitempty"`
+}
+
+// InlinesRefs reports whether $refs are inlined.
+func (c *SchemaRefsConfig) InlinesRefs() bool {
+	return c != nil && c.Inline
+}
+
+// EffectiveMaxBytes returns the size cap of inlined schemas, applying the
+// default.
+func (c *SchemaRefsConfig) EffectiveMaxBytes() int {
+	if c == nil || c.MaxBytes <= 0 {
+		return int(DefaultSchemaRefsMaxBytes)
+	}
+
+	return int(c.MaxBytes)
+}
+
+// Clone creates a copy of the schema refs config.
+func (c *SchemaRefsConfig) Clone() *SchemaRefsConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	return &clone
+}
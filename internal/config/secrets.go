@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// Secrets stores, for settings.secrets_store.
+const (
+	// SecretsStoreKeyring keeps secrets in the OS keyring (default): the
+	// macOS Keychain, the Secret Service on Linux or the Windows
+	// Credential Manager.
+	SecretsStoreKeyring = "keyring"
+	// SecretsStoreEnv reads keyring://<name> from the environment variable
+	// named like the secret in upper case (github_token as GITHUB_TOKEN),
+	// for CI runners and containers without a keyring. It is read-only.
+	SecretsStoreEnv = "env"
+)
+
+// ValidateSecretsStore checks settings.secrets_store.
+func ValidateSecretsStore(store string) error {
+	switch store {
+	case "", SecretsStoreKeyring, SecretsStoreEnv:
+		return nil
+	}
+
+	return fmt.Errorf("%q is not %q or %q", store, SecretsStoreKeyring, SecretsStoreEnv)
+}
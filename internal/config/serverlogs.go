@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+// ServerLogsLevelOff keeps stderr lines of servers out of the log; they
+// are still kept for 'assern logs' and written to files.
+const ServerLogsLevelOff = "off"
+
+// ServerLogsConfig routes what stdio servers write to stderr. Every line is
+// tagged with its server, logged at Level, kept in memory for 'assern logs'
+// and, with Files, appended to one file per server.
+type ServerLogsConfig struct {
+	// Level is the log level of stderr lines: "debug" (default), "info",
+	// "warn", "error" or "off".
+	Level string `yaml:"level,omitempty"`
+	// Files appends each server's stderr to <Dir>/<server>.log.
+	Files bool `yaml:"files,omitempty"`
+	// Dir is where the files are written. Empty uses
+	// ~/.valksor/assern/logs/ (see ServerLogsDir).
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// EffectiveLevel returns the log level of stderr lines and whether they are
+// logged at all.
+func (c *ServerLogsConfig) EffectiveLevel() (slog.Level, bool) {
+	if c == nil || c.Level == "" {
+		return slog.LevelDebug, true
+	}
+
+	if strings.EqualFold(c.Level, ServerLogsLevelOff) {
+		return 0, false
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		return slog.LevelDebug, true
+	}
+
+	return level, true
+}
+
+// FilesDir returns the directory of the per-server files, or "" when they
+// are not written.
+func (c *ServerLogsConfig) FilesDir() (string, error) {
+	if c == nil || !c.Files {
+		return "", nil
+	}
+
+	if c.Dir != "" {
+		return filepath.Clean(ExpandPath(c.Dir)), nil
+	}
+
+	return ServerLogsDir()
+}
+
+// Clone creates a copy of the server logs config.
+func (c *ServerLogsConfig) Clone() *ServerLogsConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	return &clone
+}
+
+// ValidateServerLogs checks the level of server logs.
+func ValidateServerLogs(c *ServerLogsConfig) error {
+	if c == nil || c.Level == "" || strings.EqualFold(c.Level, ServerLogsLevelOff) {
+		return nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		return fmt.Errorf("level %q: must be debug, info, warn, error or off", c.Level)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestParseServerLogsSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		yaml       string
+		wantLevel  slog.Level
+		wantLogged bool
+		wantDir    string
+		wantErr    string
+	}{
+		{name: "defaults", yaml: "files: false", wantLevel: slog.LevelDebug, wantLogged: true},
+		{name: "info", yaml: "level: info", wantLevel: slog.LevelInfo, wantLogged: true},
+		{name: "upper case warn", yaml: "level: WARN", wantLevel: slog.LevelWarn, wantLogged: true},
+		{name: "off", yaml: "level: off"},
+		{name: "files in dir", yaml: "files: true\n    dir: /var/log/assern/", wantLevel: slog.LevelDebug, wantLogged: true, wantDir: "/var/log/assern"},
+		{name: "dir without files", yaml: "dir: /var/log/assern", wantLevel: slog.LevelDebug, wantLogged: true},
+		{name: "bad level", yaml: "level: loud", wantErr: `settings.server_logs: level "loud"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.Parse([]byte("settings:\n  server_logs:\n    "+tt.yaml+"\n"), config.LoadOptions{})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			logs := cfg.Settings.ServerLogs
+
+			level, logged := logs.EffectiveLevel()
+			if logged != tt.wantLogged || (logged && level != tt.wantLevel) {
+				t.Errorf("EffectiveLevel() = %v, %v; want %v, %v", level, logged, tt.wantLevel, tt.wantLogged)
+			}
+
+			if dir, err := logs.FilesDir(); err != nil || dir != tt.wantDir {
+				t.Errorf("FilesDir() = %q, %v; want %q", dir, err, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestServerLogsConfig_DefaultDir(t *testing.T) {
+	home := t.TempDir()
+	defer config.SetHomeDirForTesting(home)()
+
+	dir, err := (&config.ServerLogsConfig{Files: true}).FilesDir()
+	if err != nil {
+		t.Fatalf("FilesDir() error = %v", err)
+	}
+
+	if want := filepath.Join(home, ".valksor", "assern", "logs"); dir != want {
+		t.Errorf("FilesDir() = %q, want %q", dir, want)
+	}
+}
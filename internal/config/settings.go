@@ -0,0 +1,293 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Settings contains global Assern settings.
+type Settings struct {
+	InstanceName string        `yaml:"instance_name,omitempty"` // Name announced to clients (default: hostname)
+	LogLevel     string        `yaml:"log_level,omitempty"`
+	LogFile      string        `yaml:"log_file,omitempty"`
+	Timeout      time.Duration `yaml:"timeout,omitempty"`
+	OutputFormat string        `yaml:"output_format,omitempty"` // "json" or "toon"
+	Instructions string        `yaml:"instructions,omitempty"`  // Usage guidance returned at initialize
+	// InstructionsSummary appends a generated summary of servers, tools and
+	// policies to the initialize instructions
+	InstructionsSummary bool              `yaml:"instructions_summary,omitempty"`
+	Aliases             map[string]string `yaml:"aliases,omitempty"`   // Tool aliases (alias -> prefixed_tool_name)
+	Discovery           *DiscoveryConfig  `yaml:"discovery,omitempty"` // Runtime tool discovery (progressive disclosure)
+	CodeMode            *CodeModeConfig   `yaml:"code_mode,omitempty"` // Sandboxed tool-composition via assern_execute
+	Socket              *SocketConfig     `yaml:"socket,omitempty"`    // Instance-sharing socket limits
+	Metrics             *MetricsConfig    `yaml:"metrics,omitempty"`   // statsd/DogStatsD exporter
+	Events              *EventsConfig     `yaml:"events,omitempty"`    // Webhook/exec event sinks
+	Features            map[string]bool   `yaml:"features,omitempty"`  // Experimental subsystem flags (see Features)
+	// Listen is an address ("host:port" or ":port") to serve the aggregated
+	// MCP server on over Streamable HTTP and SSE, in addition to stdio
+	Listen string `yaml:"listen,omitempty"`
+	// AuditLog records every tools/call as a JSON line (see AuditLogConfig)
+	AuditLog *AuditLogConfig `yaml:"audit_log,omitempty"`
+	// PrefixStrategy names exposed tools: "server" (default, server_tool),
+	// "none" or a template such as "{server}.{tool}"; PrefixCollision is
+	// "suffix" (default) or "error" (see ToolNaming)
+	PrefixStrategy  string `yaml:"prefix_strategy,omitempty"`
+	PrefixCollision string `yaml:"prefix_collision,omitempty"`
+	// Strict rejects unknown keys in every configuration file instead of
+	// ignoring them (see LoadOptions.Strict)
+	Strict bool `yaml:"strict,omitempty"`
+	// WatchConfig reloads automatically when mcp.json or config.yaml
+	// (global or local) changes; read at startup
+	WatchConfig bool `yaml:"watch_config,omitempty"`
+	// DrainTimeout bounds how long a reload waits for in-flight tool calls
+	// to a changed server before stopping it (see EffectiveDrainTimeout)
+	DrainTimeout time.Duration `yaml:"drain_timeout,omitempty"`
+	// SecretsStore serves keyring:// references in server env values:
+	// "keyring" (default, the OS keyring) or "env" (see SecretsStoreEnv)
+	SecretsStore string `yaml:"secrets_store,omitempty"`
+	// IDs names the instance and its sessions in logs, metrics and audit
+	// records (see IDsConfig); read at startup
+	IDs *IDsConfig `yaml:"ids,omitempty"`
+	// SchemaRefs inlines the $refs of tool input schemas for clients that
+	// cannot resolve them (see SchemaRefsConfig); read at startup
+	SchemaRefs *SchemaRefsConfig `yaml:"schema_refs,omitempty"`
+	// PriorityMarker prefixes the title of tools with a positive priority,
+	// e.g. "★ ", for clients that show titles
+	PriorityMarker string `yaml:"priority_marker,omitempty"`
+	// HealthCheck probes URL-based (HTTP/SSE) servers that declare no
+	// health_check of their own (see EffectiveHealthCheck)
+	HealthCheck *HealthCheckConfig `yaml:"health_check,omitempty"`
+	// CallTimeout is the call timeout of the servers that set none (see
+	// EffectiveCallTimeout)
+	CallTimeout *CallTimeoutConfig `yaml:"call_timeout,omitempty"`
+	// ACL gives HTTP clients per-token access to servers and tools (see
+	// ACLConfig); acl.yaml may hold it instead
+	ACL *ACLConfig `yaml:"acl,omitempty"`
+	// Clients limits the tools of each MCP client, by the name it sends in
+	// initialize (see ClientRules)
+	Clients map[string]*ClientRules `yaml:"clients,omitempty"`
+	// OTel traces tool call routing to an OpenTelemetry collector (see
+	// OTelConfig); read at startup
+	OTel *OTelConfig `yaml:"otel,omitempty"`
+	// Stdio tunes buffering of the stdio transport (see StdioConfig); read
+	// at startup
+	Stdio *StdioConfig `yaml:"stdio,omitempty"`
+	// WebUI serves a read-only status page on loopback (see WebUIConfig);
+	// read at startup
+	WebUI *WebUIConfig `yaml:"web_ui,omitempty"`
+	// ServerLogs routes the stderr of stdio servers to the log and files
+	// (see ServerLogsConfig); applies to servers started after a change
+	ServerLogs *ServerLogsConfig `yaml:"server_logs,omitempty"`
+	// ToolExposure is "all" (default) or "search", which lists only the
+	// meta-tools that search, describe and call the aggregated tools (see
+	// ToolExposureSearch); read at startup
+	ToolExposure string `yaml:"tool_exposure,omitempty"`
+	// MaxDescriptionLength cuts tool descriptions to that many characters
+	// (0 = no limit) and StripSchemaExamples drops examples from tool input
+	// schemas, so more servers fit in a client's context (see ToolBudget);
+	// read at startup
+	MaxDescriptionLength int  `yaml:"max_description_length,omitempty"`
+	StripSchemaExamples  bool `yaml:"strip_schema_examples,omitempty"`
+	// PageSize splits tools/list, resources/list, resources/templates/list
+	// and prompts/list into pages of that many items, linked by
+	// nextCursor (0 = one page, the default)
+	PageSize int `yaml:"page_size,omitempty"`
+	// MaxResourceSize fails resource reads larger than this with an error
+	// naming both sizes instead of relaying them whole (0 = no limit); a
+	// resource whose listing declares a larger size is not read at all
+	MaxResourceSize ByteSize `yaml:"max_resource_size,omitempty"`
+	// ContainerEngine is "docker" (default) or "podman", the engine that
+	// runs servers with an image (see ServerConfig.Image)
+	ContainerEngine string `yaml:"container_engine,omitempty"`
+}
+
+// CodeModeConfig controls the assern_execute meta-tool, which runs a sandboxed
+// Starlark script that can orchestrate several aggregated tools in one call.
+// Disabled by default; it adds a code-execution surface, so enable deliberately.
+type CodeModeConfig struct {
+	// Enabled exposes the assern_execute tool. Off by default.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Timeout bounds a single script's wall-clock execution time.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// MaxToolCalls caps how many tool calls one script may make.
+	MaxToolCalls int `yaml:"max_tool_calls,omitempty"`
+	// MaxOutputBytes caps the size of a script's captured output.
+	MaxOutputBytes ByteSize `yaml:"max_output_bytes,omitempty"`
+	// AllowedTools restricts which prefixed tool names a script may call.
+	// Empty means any aggregated tool may be called.
+	AllowedTools []string `yaml:"allowed_tools,omitempty"`
+}
+
+// IsEnabled reports whether code mode is configured and turned on.
+func (c *CodeModeConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Default values for tool discovery. They only take effect when discovery is
+// enabled; the feature is opt-in and off by default.
+const (
+	// DefaultDiscoveryMaxResults caps how many tools assern_search returns.
+	DefaultDiscoveryMaxResults = 10
+	// DefaultDiscoveryMaxLoaded caps how many tools a single session may have
+	// loaded at once. Zero means unlimited.
+	DefaultDiscoveryMaxLoaded = 30
+)
+
+// DiscoveryConfig controls runtime tool discovery (progressive disclosure).
+// When disabled (the default), every aggregated tool is exposed to the client
+// at startup, preserving the original behaviour. When enabled, only the
+// assern_* meta-tools (plus any Pinned tools) are exposed up front, and clients
+// pull in the tools they need at runtime via assern_search / assern_load.
+type DiscoveryConfig struct {
+	// Enabled turns progressive disclosure on. Off by default.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Pinned lists prefixed tool names (e.g. "github_search") that are always
+	// exposed even in discovery mode, without needing a search.
+	Pinned []string `yaml:"pinned,omitempty"`
+	// MaxResults is the default number of matches assern_search returns.
+	MaxResults int `yaml:"max_results,omitempty"`
+	// MaxLoaded caps the number of tools a session may have loaded at once.
+	// When the cap is reached, the least-recently loaded tool is evicted.
+	// Zero uses DefaultDiscoveryMaxLoaded; a negative value means unlimited.
+	MaxLoaded int `yaml:"max_loaded,omitempty"`
+}
+
+// IsEnabled reports whether discovery is configured and turned on.
+func (d *DiscoveryConfig) IsEnabled() bool {
+	return d != nil && d.Enabled
+}
+
+// EffectiveMaxResults returns the configured search limit or the default.
+func (d *DiscoveryConfig) EffectiveMaxResults() int {
+	if d == nil || d.MaxResults <= 0 {
+		return DefaultDiscoveryMaxResults
+	}
+
+	return d.MaxResults
+}
+
+// EffectiveMaxLoaded returns the per-session load ceiling. A return of zero
+// means unlimited (no eviction).
+func (d *DiscoveryConfig) EffectiveMaxLoaded() int {
+	if d == nil {
+		return DefaultDiscoveryMaxLoaded
+	}
+
+	switch {
+	case d.MaxLoaded < 0:
+		return 0 // unlimited
+	case d.MaxLoaded == 0:
+		return DefaultDiscoveryMaxLoaded
+	default:
+		return d.MaxLoaded
+	}
+}
+
+// DefaultDrainTimeout is how long a reload waits for the in-flight tool
+// calls of a changed server when settings.drain_timeout is unset.
+const DefaultDrainTimeout = 10 * time.Second
+
+// EffectiveDrainTimeout returns the drain timeout. Zero uses
+// DefaultDrainTimeout; a negative value returns zero, stopping changed
+// servers without waiting. s may be nil.
+func (s *Settings) EffectiveDrainTimeout() time.Duration {
+	switch {
+	case s == nil || s.DrainTimeout == 0:
+		return DefaultDrainTimeout
+	case s.DrainTimeout < 0:
+		return 0
+	default:
+		return s.DrainTimeout
+	}
+}
+
+// DefaultSettings returns the default settings.
+func DefaultSettings() *Settings {
+	return &Settings{
+		LogLevel:     "info",
+		Timeout:      60 * time.Second,
+		OutputFormat: "json", // Default to JSON for backward compatibility
+	}
+}
+
+// validateSettings checks the values of the settings section of
+// config.yaml, reporting the first invalid one by its path.
+func validateSettings(s *Settings) error {
+	if err := validateFeatures("settings.features", s.Features); err != nil {
+		return err
+	}
+
+	if err := validateAliases("settings.aliases", s.Aliases); err != nil {
+		return err
+	}
+
+	if err := ValidateListen(s.Listen); err != nil {
+		return fmt.Errorf("settings.listen: %w", err)
+	}
+
+	if err := ValidateAuditLog(s.AuditLog); err != nil {
+		return fmt.Errorf("settings.audit_log: %w", err)
+	}
+
+	if err := ValidateACL(s.ACL); err != nil {
+		return fmt.Errorf("settings.acl: %w", err)
+	}
+
+	if err := ValidateOTel(s.OTel); err != nil {
+		return fmt.Errorf("settings.otel: %w", err)
+	}
+
+	if err := ValidateStdio(s.Stdio); err != nil {
+		return fmt.Errorf("settings.stdio: %w", err)
+	}
+
+	if err := ValidateWebUI(s.WebUI); err != nil {
+		return fmt.Errorf("settings.web_ui: %w", err)
+	}
+
+	if err := ValidateServerLogs(s.ServerLogs); err != nil {
+		return fmt.Errorf("settings.server_logs: %w", err)
+	}
+
+	if err := ValidatePrefixStrategy(s.PrefixStrategy); err != nil {
+		return fmt.Errorf("settings.prefix_strategy: %w", err)
+	}
+
+	if err := ValidatePrefixCollision(s.PrefixCollision); err != nil {
+		return fmt.Errorf("settings.prefix_collision: %w", err)
+	}
+
+	if err := ValidateSecretsStore(s.SecretsStore); err != nil {
+		return fmt.Errorf("settings.secrets_store: %w", err)
+	}
+
+	if err := ValidateToolExposure(s.ToolExposure); err != nil {
+		return fmt.Errorf("settings.tool_exposure: %w", err)
+	}
+
+	if err := ValidateMaxDescriptionLength(s.MaxDescriptionLength); err != nil {
+		return fmt.Errorf("settings.max_description_length: %w", err)
+	}
+
+	if s.PageSize < 0 {
+		return fmt.Errorf("settings.page_size: %d is negative (0 lists everything in one page)", s.PageSize)
+	}
+
+	if s.MaxResourceSize < 0 {
+		return fmt.Errorf("settings.max_resource_size: %d is negative (0 reads resources of any size)", s.MaxResourceSize)
+	}
+
+	if err := ValidateContainerEngine(s.ContainerEngine); err != nil {
+		return fmt.Errorf("settings.container_engine: %w", err)
+	}
+
+	if err := ValidateCallTimeout(s.CallTimeout); err != nil {
+		return fmt.Errorf("settings.call_timeout: %w", err)
+	}
+
+	if err := ValidateIDs(s.IDs); err != nil {
+		return fmt.Errorf("settings.ids.%w", err)
+	}
+
+	return nil
+}
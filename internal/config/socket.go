@@ -0,0 +1,58 @@
+package config
+
+// Default session limits for the instance-sharing socket. They protect the
+// primary from local processes that open far more connections than any real
+// set of MCP clients would.
+const (
+	// DefaultSocketMaxSessions caps concurrent MCP sessions on the socket.
+	DefaultSocketMaxSessions = 64
+	// DefaultSocketMaxPerUID caps concurrent MCP sessions from one local user.
+	DefaultSocketMaxPerUID = 32
+)
+
+// SocketConfig controls the instance-sharing Unix socket served by the
+// primary. Every connection's peer UID is checked against AllowedUIDs.
+// Internal commands (ping, reload) are never counted against the session
+// limits, so detection keeps working while the session cap is reached.
+type SocketConfig struct {
+	// MaxSessions caps concurrent MCP sessions across all clients.
+	// Zero uses DefaultSocketMaxSessions; a negative value means unlimited.
+	MaxSessions int `yaml:"max_sessions,omitempty"`
+	// MaxConnectionsPerUID caps concurrent MCP sessions opened by a single
+	// peer UID. Zero uses DefaultSocketMaxPerUID; negative means unlimited.
+	MaxConnectionsPerUID int `yaml:"max_connections_per_uid,omitempty"`
+	// AllowedUIDs lists additional local users whose processes may connect.
+	// The UID running the primary is always allowed.
+	AllowedUIDs []int `yaml:"allowed_uids,omitempty"`
+}
+
+// EffectiveMaxSessions returns the session ceiling. Zero means unlimited.
+func (s *SocketConfig) EffectiveMaxSessions() int {
+	if s == nil {
+		return DefaultSocketMaxSessions
+	}
+
+	return effectiveLimit(s.MaxSessions, DefaultSocketMaxSessions)
+}
+
+// EffectiveMaxPerUID returns the per-UID session ceiling. Zero means unlimited.
+func (s *SocketConfig) EffectiveMaxPerUID() int {
+	if s == nil {
+		return DefaultSocketMaxPerUID
+	}
+
+	return effectiveLimit(s.MaxConnectionsPerUID, DefaultSocketMaxPerUID)
+}
+
+// effectiveLimit maps a configured limit onto its effective value: zero picks
+// the default and a negative value disables the limit (returned as zero).
+func effectiveLimit(configured, def int) int {
+	switch {
+	case configured < 0:
+		return 0
+	case configured == 0:
+		return def
+	default:
+		return configured
+	}
+}
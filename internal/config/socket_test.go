@@ -0,0 +1,100 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestSocketConfigEffectiveMaxSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *config.SocketConfig
+		want int
+	}{
+		{name: "nil uses default", cfg: nil, want: config.DefaultSocketMaxSessions},
+		{name: "zero uses default", cfg: &config.SocketConfig{}, want: config.DefaultSocketMaxSessions},
+		{name: "negative means unlimited", cfg: &config.SocketConfig{MaxSessions: -1}, want: 0},
+		{name: "explicit", cfg: &config.SocketConfig{MaxSessions: 3}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.cfg.EffectiveMaxSessions(); got != tt.want {
+				t.Errorf("EffectiveMaxSessions() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSocketConfigEffectiveMaxPerUID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *config.SocketConfig
+		want int
+	}{
+		{name: "nil uses default", cfg: nil, want: config.DefaultSocketMaxPerUID},
+		{name: "zero uses default", cfg: &config.SocketConfig{}, want: config.DefaultSocketMaxPerUID},
+		{name: "negative means unlimited", cfg: &config.SocketConfig{MaxConnectionsPerUID: -5}, want: 0},
+		{name: "explicit", cfg: &config.SocketConfig{MaxConnectionsPerUID: 2}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.cfg.EffectiveMaxPerUID(); got != tt.want {
+				t.Errorf("EffectiveMaxPerUID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSocketSettings(t *testing.T) {
+	t.Parallel()
+
+	yaml := `
+settings:
+  socket:
+    max_sessions: 10
+    max_connections_per_uid: 4
+    allowed_uids: [1001, 1002]
+`
+
+	cfg, err := config.Parse([]byte(yaml), config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if cfg.Settings == nil || cfg.Settings.Socket == nil {
+		t.Fatal("expected socket settings to be parsed")
+	}
+
+	if got := cfg.Settings.Socket.EffectiveMaxSessions(); got != 10 {
+		t.Errorf("max_sessions = %d, want 10", got)
+	}
+
+	if got := cfg.Settings.Socket.EffectiveMaxPerUID(); got != 4 {
+		t.Errorf("max_connections_per_uid = %d, want 4", got)
+	}
+
+	if len(cfg.Settings.Socket.AllowedUIDs) != 2 || cfg.Settings.Socket.AllowedUIDs[0] != 1001 {
+		t.Errorf("allowed_uids = %v, want [1001 1002]", cfg.Settings.Socket.AllowedUIDs)
+	}
+
+	clone := cfg.Clone()
+	if clone.Settings.Socket == cfg.Settings.Socket {
+		t.Error("Clone() shared the socket settings pointer")
+	}
+
+	clone.Settings.Socket.AllowedUIDs[0] = 0
+	if cfg.Settings.Socket.AllowedUIDs[0] != 1001 {
+		t.Error("Clone() did not deep-copy AllowedUIDs")
+	}
+}
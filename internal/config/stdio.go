@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultStdioWriteBuffer is the size of the buffer coalescing writes to
+	// the stdio client when stdio.flush_interval is set.
+	DefaultStdioWriteBuffer ByteSize = 64 << 10
+
+	// MaxStdioBuffer bounds stdio.read_buffer and stdio.write_buffer.
+	MaxStdioBuffer ByteSize = 16 << 20
+
+	// MaxStdioFlushInterval bounds stdio.flush_interval: longer delays hold
+	// back responses noticeably.
+	MaxStdioFlushInterval = time.Second
+)
+
+// StdioConfig tunes the stdio transport of the primary instance. By
+// default each message is written to the client as soon as it is ready;
+// a tool streaming many small progress notifications or result chunks
+// costs one write each, which a flush interval coalesces.
+type StdioConfig struct {
+	// ReadBuffer is the initial size of the buffer client messages are read
+	// into; larger requests grow it. Zero uses 4KiB.
+	ReadBuffer ByteSize `yaml:"read_buffer,omitempty"`
+	// WriteBuffer is the size of the buffer coalescing writes, flushed when
+	// full. Zero uses DefaultStdioWriteBuffer. Only used with FlushInterval.
+	WriteBuffer ByteSize `yaml:"write_buffer,omitempty"`
+	// FlushInterval is how long a message may wait in the write buffer for
+	// others to join it. Zero writes every message at once.
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+}
+
+// Coalesces reports whether writes to the client are coalesced.
+func (c *StdioConfig) Coalesces() bool {
+	return c != nil && c.FlushInterval > 0
+}
+
+// EffectiveReadBuffer returns the read buffer size, or 0 for the default.
+func (c *StdioConfig) EffectiveReadBuffer() int {
+	if c == nil {
+		return 0
+	}
+
+	return int(c.ReadBuffer)
+}
+
+// EffectiveWriteBuffer returns the size of the coalescing buffer, applying
+// the default.
+func (c *StdioConfig) EffectiveWriteBuffer() int {
+	if c == nil || c.WriteBuffer <= 0 {
+		return int(DefaultStdioWriteBuffer)
+	}
+
+	return int(c.WriteBuffer)
+}
+
+// Clone creates a copy of the stdio config.
+func (c *StdioConfig) Clone() *StdioConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	return &clone
+}
+
+// ValidateStdio checks that the buffer sizes and flush interval are in
+// range.
+func ValidateStdio(c *StdioConfig) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.ReadBuffer < 0 || c.ReadBuffer > MaxStdioBuffer {
+		return fmt.Errorf("read_buffer %d is not between 0 and %d bytes", c.ReadBuffer, MaxStdioBuffer)
+	}
+
+	if c.WriteBuffer < 0 || c.WriteBuffer > MaxStdioBuffer {
+		return fmt.Errorf("write_buffer %d is not between 0 and %d bytes", c.WriteBuffer, MaxStdioBuffer)
+	}
+
+	if c.FlushInterval < 0 || c.FlushInterval > MaxStdioFlushInterval {
+		return fmt.Errorf("flush_interval %s is not between 0 and %s", c.FlushInterval, MaxStdioFlushInterval)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestParseStdioSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "valid", yaml: "read_buffer: 1MiB\n    write_buffer: 128KiB\n    flush_interval: 2ms"},
+		{name: "buffer too large", yaml: "write_buffer: 1GiB", wantErr: "settings.stdio: write_buffer"},
+		{name: "negative read buffer", yaml: "read_buffer: -1", wantErr: "settings.stdio.read_buffer"},
+		{name: "flush interval too long", yaml: "flush_interval: 5s", wantErr: "settings.stdio: flush_interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.Parse([]byte("settings:\n  stdio:\n    "+tt.yaml+"\n"), config.LoadOptions{})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			stdio := cfg.Settings.Stdio
+			if stdio.EffectiveReadBuffer() != 1<<20 || stdio.EffectiveWriteBuffer() != 128<<10 ||
+				stdio.FlushInterval != 2*time.Millisecond || !stdio.Coalesces() {
+				t.Errorf("stdio settings = %+v", stdio)
+			}
+
+			if clone := cfg.Clone(); clone.Settings.Stdio == stdio {
+				t.Error("Clone() shared the stdio settings pointer")
+			}
+		})
+	}
+}
+
+func TestStdioConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	var cfg *config.StdioConfig
+
+	if cfg.Coalesces() || cfg.EffectiveReadBuffer() != 0 || cfg.EffectiveWriteBuffer() != int(config.DefaultStdioWriteBuffer) {
+		t.Errorf("nil stdio config: coalesces %v, read %d, write %d", cfg.Coalesces(), cfg.EffectiveReadBuffer(), cfg.EffectiveWriteBuffer())
+	}
+}
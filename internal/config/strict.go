@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownField is wrapped by the *FieldError reported for a key that no
+// configuration field declares, e.g. a misspelt "alowed".
+var ErrUnknownField = errors.New("unknown field")
+
+// checkKnownFields reports every key in data, YAML or JSON, that the type
+// of out does not declare under the given struct tag ("yaml" or "json").
+// Keys are reported as *FieldError wrapping ErrUnknownField, all at once.
+func checkKnownFields(data []byte, out any, tag string) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	return errors.Join(unknownFields(&doc, reflect.TypeOf(out), "", tag)...)
+}
+
+// unknownFields walks node alongside the Go type it decodes into, like
+// normalizeUnits, collecting mapping keys with no matching struct field.
+func unknownFields(node *yaml.Node, t reflect.Type, path, tag string) []error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs []error
+
+	switch {
+	case node.Kind == yaml.DocumentNode:
+		for _, child := range node.Content {
+			errs = append(errs, unknownFields(child, t, path, tag)...)
+		}
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := taggedFields(t, tag)
+		if tag == "json" {
+			// encoding/json matches keys case-insensitively
+			fields = lowerKeys(fields)
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+
+			name := key.Value
+			if tag == "json" {
+				name = strings.ToLower(name)
+			}
+
+			field, ok := fields[name]
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field: joinPath(path, key.Value),
+					Line:  key.Line,
+					Err:   unknownFieldError(key.Value, t, tag),
+				})
+
+				continue
+			}
+
+			errs = append(errs, unknownFields(node.Content[i+1], field.Type, joinPath(path, key.Value), tag)...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			errs = append(errs, unknownFields(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), tag)...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, child := range node.Content {
+			errs = append(errs, unknownFields(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i), tag)...)
+		}
+	}
+
+	return errs
+}
+
+// unknownFieldError wraps ErrUnknownField, suggesting the closest field of
+// t when the key looks like a typo of it.
+func unknownFieldError(key string, t reflect.Type, tag string) error {
+	best, bestDist := "", 3 // Suggest only within two edits
+
+	for name := range taggedFields(t, tag) {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDist || d == bestDist && name < best {
+			best, bestDist = name, d
+		}
+	}
+
+	if best == "" {
+		return ErrUnknownField
+	}
+
+	return fmt.Errorf("%w (did you mean %q?)", ErrUnknownField, best)
+}
+
+func lowerKeys(fields map[string]reflect.StructField) map[string]reflect.StructField {
+	out := make(map[string]reflect.StructField, len(fields))
+	for name, f := range fields {
+		out[strings.ToLower(name)] = f
+	}
+
+	return out
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
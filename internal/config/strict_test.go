@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseStrict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		opts    LoadOptions
+		wantErr []string
+	}{
+		{
+			name: "unknown keys ignored by default",
+			data: "settings:\n  log_levle: debug\n",
+		},
+		{
+			name:    "strict option",
+			data:    "settings:\n  log_levle: debug\n",
+			opts:    LoadOptions{Strict: true},
+			wantErr: []string{`settings.log_levle (line 2): unknown field (did you mean "log_level"?)`},
+		},
+		{
+			name: "known keys",
+			data: "settings:\n  strict: true\n  log_level: debug\nprojects:\n  work:\n    servers:\n      fs:\n        allowed: [read_file]\n",
+		},
+		{
+			name:    "typo in server override",
+			data:    "settings:\n  strict: true\nprojects:\n  work:\n    servers:\n      fs:\n        alowed: [read_file]\n",
+			wantErr: []string{`projects.work.servers.fs.alowed (line 7): unknown field (did you mean "allowed"?)`},
+		},
+		{
+			name:    "every unknown key reported",
+			data:    "settings:\n  strict: true\n  verbose: true\nproject:\n  work: {}\n",
+			wantErr: []string{"settings.verbose (line 3): unknown field", `project (line 4): unknown field (did you mean "projects"?)`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data), tt.opts)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, ErrUnknownField) {
+				t.Fatalf("Parse() error = %v, want ErrUnknownField", err)
+			}
+
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Parse() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMCPConfigStrict(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+  // JSONC is still accepted
+  "mcpServers": {
+    "github": {
+      "command": "npx",
+      "WORKDIR": "/tmp",
+      "enviroment": {"TOKEN": "x"},
+    },
+  },
+}`)
+
+	if _, err := parseMCPConfig(data, false); err != nil {
+		t.Fatalf("parseMCPConfig() lenient error = %v", err)
+	}
+
+	_, err := parseMCPConfig(data, true)
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("parseMCPConfig() error = %v, want ErrUnknownField", err)
+	}
+
+	// Keys match case-insensitively, as in encoding/json
+	if strings.Contains(err.Error(), "WORKDIR") {
+		t.Errorf("error = %q, WORKDIR should match workDir", err)
+	}
+
+	if want := "mcpServers.github.enviroment (line 7): unknown field"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestLoadEffectiveStrictSetting(t *testing.T) {
+	home := t.TempDir()
+	restore := SetHomeDirForTesting(home)
+	defer restore()
+
+	dir := filepath.Join(home, ".valksor", "assern")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "mcp.json"), []byte(`{"mcpServers": {"a": {"command": "x", "argz": []}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadEffective(home, "", LoadOptions{}); err != nil {
+		t.Fatalf("LoadEffective() error = %v", err)
+	}
+
+	if _, err := LoadEffective(home, "", LoadOptions{Strict: true}); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("LoadEffective() strict error = %v, want ErrUnknownField", err)
+	}
+
+	// settings.strict in config.yaml applies to mcp.json too
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("settings:\n  strict: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadEffective(home, "", LoadOptions{}); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("LoadEffective() error = %v, want ErrUnknownField", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"alowed", "allowed", 1},
+		{"log_levle", "log_level", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import "strings"
+
+// ToolRules decides which tools of a server are exposed, from the allowed
+// and denied lists of its ServerConfig.
+//
+// Entries are globs over the unprefixed tool name: "*" matches any run of
+// characters and "?" one character, so plain names match exactly. A
+// "server:" qualifier limits an entry to matching servers (e.g.
+// "github:*issue*"), and a leading "!" excludes what it matches from the
+// rest of its list (e.g. allowed: ["*", "!delete_*"]). An allowed list with
+// only "!" entries allows every other tool. Denied wins over allowed.
+type ToolRules struct {
+	Allowed []string
+	Denied  []string
+}
+
+// ToolRules returns the tool rules of the server, or nil when it sets
+// neither allowed nor denied.
+func (s *ServerConfig) ToolRules() *ToolRules {
+	if s == nil || (len(s.Allowed) == 0 && len(s.Denied) == 0) {
+		return nil
+	}
+
+	return &ToolRules{Allowed: s.Allowed, Denied: s.Denied}
+}
+
+// Allows reports whether a tool of server is exposed. Nil rules allow
+// every tool.
+func (r *ToolRules) Allows(server, tool string) bool {
+	if r == nil {
+		return true
+	}
+
+	if len(r.Allowed) > 0 && !matchToolList(r.Allowed, server, tool, true) {
+		return false
+	}
+
+	return !matchToolList(r.Denied, server, tool, false)
+}
+
+// matchToolList reports whether a tool matches one of the list's entries
+// and none of its "!" entries. A list without positive entries matches
+// every tool not excluded when all is set, and none otherwise.
+func matchToolList(patterns []string, server, tool string, all bool) bool {
+	return matchList(patterns, all, func(pattern string) bool { return matchToolPattern(pattern, server, tool) })
+}
+
+// matchList implements matchToolList with match deciding whether one entry,
+// without its "!", matches.
+func matchList(patterns []string, all bool, match func(pattern string) bool) bool {
+	matched, positive := false, false
+
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if !negated {
+			positive = true
+		}
+
+		if !match(strings.TrimPrefix(pattern, "!")) {
+			continue
+		}
+
+		if negated {
+			return false
+		}
+
+		matched = true
+	}
+
+	return matched || (all && !positive)
+}
+
+// matchToolPattern matches one entry, with its optional server qualifier,
+// against a tool.
+func matchToolPattern(pattern, server, tool string) bool {
+	if qualifier, name, found := strings.Cut(pattern, ":"); found {
+		if !MatchGlob(qualifier, server) {
+			return false
+		}
+
+		pattern = name
+	}
+
+	return MatchGlob(pattern, tool)
+}
+
+// MatchGlob reports whether s matches pattern, where "*" matches any run of
+// characters (including none) and "?" exactly one.
+func MatchGlob(pattern, s string) bool {
+	// Backtrack to the last "*" on a mismatch: the classic linear-space
+	// wildcard match
+	p, i := 0, 0
+	star, mark := -1, 0
+
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, i
+			p++
+		case star >= 0:
+			p = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
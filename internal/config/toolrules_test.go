@@ -0,0 +1,108 @@
+package config
+
+import "testing"
+
+func TestToolRulesAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rules  *ToolRules
+		server string
+		tools  map[string]bool
+	}{
+		{
+			name:   "nil rules",
+			server: "github",
+			tools:  map[string]bool{"anything": true},
+		},
+		{
+			name:   "exact names",
+			rules:  &ToolRules{Allowed: []string{"search", "create"}},
+			server: "github",
+			tools:  map[string]bool{"search": true, "create": true, "create_issue": false},
+		},
+		{
+			name:   "globs",
+			rules:  &ToolRules{Allowed: []string{"*issue*", "get_?"}},
+			server: "github",
+			tools:  map[string]bool{"create_issue": true, "issues": true, "get_a": true, "get_ab": false, "search": false},
+		},
+		{
+			name:   "denied wins",
+			rules:  &ToolRules{Allowed: []string{"*"}, Denied: []string{"delete_*"}},
+			server: "github",
+			tools:  map[string]bool{"delete_repo": false, "get_repo": true},
+		},
+		{
+			name:   "denied only",
+			rules:  &ToolRules{Denied: []string{"*_admin", "drop"}},
+			server: "db",
+			tools:  map[string]bool{"query": true, "user_admin": false, "drop": false},
+		},
+		{
+			name:   "negated allowed entries",
+			rules:  &ToolRules{Allowed: []string{"!delete_*"}},
+			server: "github",
+			tools:  map[string]bool{"delete_repo": false, "search": true},
+		},
+		{
+			name:   "negated denied entries",
+			rules:  &ToolRules{Denied: []string{"delete_*", "!delete_draft"}},
+			server: "github",
+			tools:  map[string]bool{"delete_repo": false, "delete_draft": true},
+		},
+		{
+			name:   "server qualifier matches",
+			rules:  &ToolRules{Allowed: []string{"github:*issue*", "jira:*"}},
+			server: "github",
+			tools:  map[string]bool{"create_issue": true, "search": false},
+		},
+		{
+			name:   "server qualifier for another server",
+			rules:  &ToolRules{Denied: []string{"jira:*"}},
+			server: "github",
+			tools:  map[string]bool{"search": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for tool, want := range tt.tools {
+				if got := tt.rules.Allows(tt.server, tool); got != want {
+					t.Errorf("Allows(%q, %q) = %v, want %v", tt.server, tool, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"a*c", "abbbc", true},
+		{"a*c", "abbbd", false},
+		{"*a*b*", "xaxxbx", true},
+		{"a?c", "abc", true},
+		{"a?c", "ac", false},
+		{"**x", "abx", true},
+		{"search", "search", true},
+		{"search", "searches", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
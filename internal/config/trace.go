@@ -0,0 +1,307 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Merge trace sources, in the order BuildEffectiveConfig applies them.
+// Project overrides are reported as "project <name>".
+const (
+	TraceDefault      = "default"
+	TraceGlobalConfig = "global config.yaml"
+	TraceGlobalMCP    = "global mcp.json"
+	TraceLocalMCP     = "local mcp.json"
+	TraceLocalConfig  = "local config.yaml"
+)
+
+// traceProject names the source for a project definition in config.yaml.
+func traceProject(name string) string {
+	return "project " + name
+}
+
+// traceAuthProfile names the source for OAuth resolved from oauth_ref.
+func traceAuthProfile(name string) string {
+	return "auth profile " + name
+}
+
+// TraceEntry records that a source set a field.
+type TraceEntry struct {
+	Field  string
+	Source string
+}
+
+// MergeTrace records which source contributed each server field and setting
+// while building the effective configuration. Env and header keys are traced
+// individually as "env.KEY" and "headers.KEY"; a plain "env" or "headers"
+// entry means the whole map was replaced (merge_mode: replace).
+type MergeTrace struct {
+	// Project is the global project whose overrides were applied, if any.
+	Project string
+	// Profile is the group servers were limited to, if any.
+	Profile  string
+	Servers  map[string][]TraceEntry
+	Settings []TraceEntry
+}
+
+// FieldOrigin is the resolved provenance of one field: the source whose value
+// is in effect and the earlier sources it overrode.
+type FieldOrigin struct {
+	Field     string
+	Source    string
+	Overrides []string
+}
+
+func newMergeTrace() *MergeTrace {
+	return &MergeTrace{Servers: make(map[string][]TraceEntry)}
+}
+
+// server records fields set on a server by source. Safe on a nil trace.
+func (t *MergeTrace) server(name, source string, fields []string) {
+	if t == nil {
+		return
+	}
+
+	for _, f := range fields {
+		t.Servers[name] = append(t.Servers[name], TraceEntry{Field: f, Source: source})
+	}
+}
+
+// settings records settings set by source. Safe on a nil trace.
+func (t *MergeTrace) settings(source string, fields []string) {
+	if t == nil {
+		return
+	}
+
+	for _, f := range fields {
+		t.Settings = append(t.Settings, TraceEntry{Field: f, Source: source})
+	}
+}
+
+// ServerOrigin returns the source that first defined a server.
+func (t *MergeTrace) ServerOrigin(name string) string {
+	if entries := t.Servers[name]; len(entries) > 0 {
+		return entries[0].Source
+	}
+
+	return ""
+}
+
+// ResolveTrace folds trace entries into per-field origins, sorted by field.
+// A later entry for a field overrides earlier ones; a whole-map replacement
+// ("env", "headers") discards earlier per-key entries of that map.
+func ResolveTrace(entries []TraceEntry) []FieldOrigin {
+	origins := make(map[string]*FieldOrigin)
+
+	for _, e := range entries {
+		if e.Field == "env" || e.Field == "headers" {
+			for field := range origins {
+				if strings.HasPrefix(field, e.Field+".") {
+					delete(origins, field)
+				}
+			}
+		}
+
+		o, ok := origins[e.Field]
+		if !ok {
+			origins[e.Field] = &FieldOrigin{Field: e.Field, Source: e.Source}
+
+			continue
+		}
+
+		if o.Source != e.Source {
+			o.Overrides = append(o.Overrides, o.Source)
+			o.Source = e.Source
+		}
+	}
+
+	result := make([]FieldOrigin, 0, len(origins))
+	for _, field := range slices.Sorted(maps.Keys(origins)) {
+		result = append(result, *origins[field])
+	}
+
+	return result
+}
+
+// serverFields lists the fields a server definition sets on its own.
+func serverFields(srv *ServerConfig) []string {
+	return overrideFields(&ServerConfig{MergeMode: srv.MergeMode}, srv)
+}
+
+// overrideFields lists the fields mergeServer takes from override when
+// merging it onto base. It must mirror mergeServer.
+func overrideFields(base, override *ServerConfig) []string {
+	if override == nil {
+		return nil
+	}
+
+	var fields []string
+
+	add := func(set bool, field string) {
+		if set {
+			fields = append(fields, field)
+		}
+	}
+
+	add(override.Command != "", "command")
+	add(len(override.Args) > 0, "args")
+	add(override.WorkDir != "", "work_dir")
+	add(override.Encoding != "", "encoding")
+	add(override.Locale != "", "locale")
+	add(override.Image != "", "image")
+	add(len(override.Volumes) > 0, "volumes")
+	add(override.Network != "", "network")
+	add(override.URL != "", "url")
+	add(override.Transport != "", "transport")
+
+	mode := base.MergeMode
+	if override.MergeMode != "" {
+		add(override.MergeMode != base.MergeMode, "merge_mode")
+		mode = override.MergeMode
+	}
+
+	fields = append(fields, mapFields("env", override.Env, mode)...)
+	fields = append(fields, mapFields("headers", override.Headers, mode)...)
+
+	add(override.OAuth != nil, "oauth")
+	add(override.OAuthRef != "", "oauth_ref")
+	add(len(override.Allowed) > 0, "allowed")
+	add(len(override.Denied) > 0, "denied")
+	add(override.AllowedResources != nil, "allowed_resources")
+	add(override.ResourceCache != nil, "resource_cache")
+	add(override.BinaryContent != nil, "binary_content")
+	add(override.Prompts != nil, "prompts")
+	add(override.Prefix != "", "prefix")
+	add(override.Health != nil, "health_check")
+	add(override.CallTimeout != nil, "call_timeout")
+	add(len(override.Maintenance) > 0, "maintenance")
+	fields = append(fields, mapFields("idempotency_keys", override.IdempotencyKeys, MergeModeOverlay)...)
+	fields = append(fields, mapFields("forward_headers", override.ForwardHeaders, MergeModeOverlay)...)
+	add(override.Coalesce, "coalesce")
+	add(override.MaxConcurrency != 0, "max_concurrency")
+	add(override.Queue, "queue")
+	add(override.Sampling, "sampling")
+	add(override.DryRun, "dry_run")
+	add(override.RestartPolicy != "", "restart_policy")
+	add(override.Lazy, "lazy")
+	add(len(override.Tools) > 0, "tools")
+	add(override.Deprecated != "", "deprecated")
+	fields = append(fields, mapFields("deprecated_tools", override.DeprecatedTools, MergeModeOverlay)...)
+	add(override.Priority != 0, "priority")
+	fields = append(fields, mapFields("tool_priority", override.ToolPriority, MergeModeOverlay)...)
+	fields = append(fields, mapFields("tool_descriptions", override.ToolDescriptions, MergeModeOverlay)...)
+	add(override.MaxDescriptionLength != 0, "max_description_length")
+	add(override.StripSchemaExamples, "strip_schema_examples")
+	add(override.Disabled, "disabled")
+
+	return fields
+}
+
+// mapFields lists the keys a map override (env, headers, ...) sets, preceded
+// by the map name itself when replace mode discards the base map.
+func mapFields[V any](name string, m map[string]V, mode MergeMode) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	var fields []string
+	if mode == MergeModeReplace {
+		fields = append(fields, name)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		fields = append(fields, name+"."+key)
+	}
+
+	return fields
+}
+
+// settingsFields lists the settings in s that differ from the defaults.
+func settingsFields(s *Settings) []string {
+	def := DefaultSettings()
+
+	var fields []string
+
+	add := func(set bool, field string) {
+		if set {
+			fields = append(fields, field)
+		}
+	}
+
+	add(s.InstanceName != "", "instance_name")
+	add(s.LogLevel != def.LogLevel, "log_level")
+	add(s.LogFile != "", "log_file")
+	add(s.Timeout != def.Timeout, "timeout")
+	add(s.OutputFormat != def.OutputFormat, "output_format")
+	add(s.Instructions != "", "instructions")
+	add(s.InstructionsSummary, "instructions_summary")
+	add(len(s.Aliases) > 0, "aliases")
+	add(s.Discovery != nil, "discovery")
+	add(s.CodeMode != nil, "code_mode")
+	add(s.Socket != nil, "socket")
+	add(s.Metrics != nil, "metrics")
+	add(s.Events != nil, "events")
+	add(len(s.Features) > 0, "features")
+	add(s.Listen != "", "listen")
+	add(s.AuditLog != nil, "audit_log")
+	add(s.PrefixStrategy != "", "prefix_strategy")
+	add(s.PrefixCollision != "", "prefix_collision")
+	add(s.Strict, "strict")
+	add(s.WatchConfig, "watch_config")
+	add(s.DrainTimeout != 0, "drain_timeout")
+	add(s.SecretsStore != "", "secrets_store")
+	add(s.IDs != nil, "ids")
+	add(s.SchemaRefs != nil, "schema_refs")
+	add(s.PriorityMarker != "", "priority_marker")
+	add(s.HealthCheck != nil, "health_check")
+	add(s.CallTimeout != nil, "call_timeout")
+	add(s.ACL != nil, "acl")
+	add(len(s.Clients) > 0, "clients")
+	add(s.OTel != nil, "otel")
+	add(s.Stdio != nil, "stdio")
+	add(s.WebUI != nil, "web_ui")
+	add(s.ServerLogs != nil, "server_logs")
+	add(s.ToolExposure != "", "tool_exposure")
+	add(s.MaxDescriptionLength != 0, "max_description_length")
+	add(s.StripSchemaExamples, "strip_schema_examples")
+	add(s.PageSize != 0, "page_size")
+	add(s.MaxResourceSize != 0, "max_resource_size")
+	add(s.ContainerEngine != "", "container_engine")
+
+	return fields
+}
+
+// overrideSettingsFields lists the settings a project override sets.
+func overrideSettingsFields(o *SettingsOverride) []string {
+	if o == nil {
+		return nil
+	}
+
+	var fields []string
+	if o.LogLevel != "" {
+		fields = append(fields, "log_level")
+	}
+
+	if o.Timeout > 0 {
+		fields = append(fields, "timeout")
+	}
+
+	if o.OutputFormat != "" {
+		fields = append(fields, "output_format")
+	}
+
+	if o.Instructions != "" {
+		fields = append(fields, "instructions")
+	}
+
+	if len(o.Features) > 0 {
+		fields = append(fields, "features")
+	}
+
+	if len(o.Aliases) > 0 {
+		fields = append(fields, "aliases")
+	}
+
+	return fields
+}
@@ -0,0 +1,116 @@
+package config_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestBuildEffectiveConfigTrace(t *testing.T) {
+	t.Parallel()
+
+	globalMCP := &config.MCPConfig{MCPServers: map[string]*config.MCPServer{
+		"github": {Command: "gh-mcp", Env: map[string]string{"TOKEN": "global", "OTHER": "x"}},
+		"db":     {URL: "https://db.example/mcp"},
+	}}
+
+	global := &config.Config{
+		Settings: config.DefaultSettings(),
+		Projects: map[string]*config.ProjectConfig{
+			"work": {
+				Env:      map[string]string{"TOKEN": "project"},
+				Settings: &config.SettingsOverride{Timeout: 5 * time.Minute},
+				Servers: map[string]*config.ServerConfig{
+					"github": {Allowed: []string{"search"}, MergeMode: config.MergeModeOverlay},
+				},
+			},
+		},
+	}
+
+	localMCP := &config.MCPConfig{MCPServers: map[string]*config.MCPServer{
+		"notes": {Command: "notes-mcp"},
+	}}
+
+	local := &config.LocalProjectConfig{
+		Servers: map[string]*config.ServerConfig{
+			"db": {MergeMode: config.MergeModeReplace, Headers: map[string]string{"X-Key": "k"}},
+		},
+	}
+
+	cfg, trace := config.BuildEffectiveConfigTrace(globalMCP, global, localMCP, local, "work")
+
+	if trace.Project != "work" {
+		t.Errorf("Project = %q, want work", trace.Project)
+	}
+
+	if got := cfg.Servers["github"].Env["TOKEN"]; got != "project" {
+		t.Errorf("github TOKEN = %q; trace must not change the merge result", got)
+	}
+
+	tests := []struct {
+		server    string
+		field     string
+		source    string
+		overrides []string
+	}{
+		{"github", "command", config.TraceGlobalMCP, nil},
+		{"github", "env.OTHER", config.TraceGlobalMCP, nil},
+		{"github", "env.TOKEN", "project work", []string{config.TraceGlobalMCP}},
+		{"github", "allowed", "project work", nil},
+		{"db", "merge_mode", config.TraceLocalConfig, nil},
+		{"db", "headers", config.TraceLocalConfig, nil},
+		{"db", "headers.X-Key", config.TraceLocalConfig, nil},
+		{"notes", "command", config.TraceLocalMCP, nil},
+	}
+
+	for _, tt := range tests {
+		origins := config.ResolveTrace(trace.Servers[tt.server])
+
+		idx := slices.IndexFunc(origins, func(o config.FieldOrigin) bool { return o.Field == tt.field })
+		if idx < 0 {
+			t.Errorf("%s.%s: not traced", tt.server, tt.field)
+
+			continue
+		}
+
+		o := origins[idx]
+		if o.Source != tt.source || !slices.Equal(o.Overrides, tt.overrides) {
+			t.Errorf("%s.%s = %s (overrides %v), want %s (overrides %v)",
+				tt.server, tt.field, o.Source, o.Overrides, tt.source, tt.overrides)
+		}
+	}
+
+	if got := trace.ServerOrigin("notes"); got != config.TraceLocalMCP {
+		t.Errorf("ServerOrigin(notes) = %q, want %q", got, config.TraceLocalMCP)
+	}
+
+	settings := config.ResolveTrace(trace.Settings)
+
+	idx := slices.IndexFunc(settings, func(o config.FieldOrigin) bool { return o.Field == "timeout" })
+	if idx < 0 || settings[idx].Source != "project work" {
+		t.Errorf("timeout origin = %+v, want project work", settings)
+	}
+}
+
+func TestResolveTraceReplaceDropsEarlierKeys(t *testing.T) {
+	t.Parallel()
+
+	origins := config.ResolveTrace([]config.TraceEntry{
+		{Field: "env.A", Source: config.TraceGlobalMCP},
+		{Field: "env.B", Source: config.TraceGlobalMCP},
+		{Field: "env", Source: config.TraceLocalConfig},
+		{Field: "env.B", Source: config.TraceLocalConfig},
+	})
+
+	var fields []string
+	for _, o := range origins {
+		fields = append(fields, o.Field+"="+o.Source)
+	}
+
+	want := []string{"env=" + config.TraceLocalConfig, "env.B=" + config.TraceLocalConfig}
+	if !slices.Equal(fields, want) {
+		t.Errorf("ResolveTrace() = %v, want %v", fields, want)
+	}
+}
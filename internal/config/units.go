@@ -0,0 +1,295 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ByteSize is a size in bytes. In config.yaml it may be written as a plain
+// number of bytes or with a unit: "512KB", "10 MB", "1.5GiB" (see ParseSize).
+type ByteSize int
+
+// FieldError reports an invalid config value, naming the field by its YAML
+// path (e.g. "settings.code_mode.timeout") and the line it is on.
+type FieldError struct {
+	Field string
+	Line  int
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (line %d): %v", e.Field, e.Line, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// durationUnits maps the accepted duration unit spellings to their length.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond, "nanosecond": time.Nanosecond, "nanoseconds": time.Nanosecond,
+	"us": time.Microsecond, "µs": time.Microsecond, "microsecond": time.Microsecond, "microseconds": time.Microsecond,
+	"ms": time.Millisecond, "msec": time.Millisecond, "msecs": time.Millisecond,
+	"millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// sizeUnits maps the accepted size unit spellings to their multiplier. KB,
+// MB and GB are decimal (1000-based); KiB, MiB and GiB are binary.
+var sizeUnits = map[string]int64{
+	"":  1,
+	"b": 1, "byte": 1, "bytes": 1,
+	"k": 1_000, "kb": 1_000,
+	"m": 1_000_000, "mb": 1_000_000,
+	"g": 1_000_000_000, "gb": 1_000_000_000,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30,
+}
+
+// ParseDuration parses a duration in Go syntax ("1h30m", "500ms") or in
+// words ("90 seconds", "1 hour 30 minutes", "2 days"). Units are case
+// insensitive and the decimal separator is always "."; a plain "0" is the
+// only number accepted without a unit.
+func ParseDuration(s string) (time.Duration, error) {
+	input := strings.TrimSpace(s)
+	if input == "" {
+		return 0, errors.New("empty duration")
+	}
+
+	if strings.HasPrefix(input, "-") {
+		return 0, fmt.Errorf("invalid duration %q: must not be negative", s)
+	}
+
+	rest := strings.ToLower(input)
+	if rest == "0" {
+		return 0, nil
+	}
+
+	var total float64
+
+	for rest != "" {
+		num, unit, remaining, err := nextComponent(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+
+		if unit == "" {
+			return 0, fmt.Errorf("invalid duration %q: missing unit after %s (e.g. %ss or %sm)", s, num, num, num)
+		}
+
+		length, ok := durationUnits[unit]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q (use ms, s, m, h or d)", s, unit)
+		}
+
+		value, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: bad number %q", s, num)
+		}
+
+		total += value * float64(length)
+		rest = remaining
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid duration %q: too large", s)
+	}
+
+	return time.Duration(total), nil
+}
+
+// ParseSize parses a size in bytes: a plain number ("1048576") or a number
+// with a unit ("512KB", "10 MB", "1.5GiB"). Units are case insensitive.
+func ParseSize(s string) (int64, error) {
+	input := strings.TrimSpace(s)
+	if input == "" {
+		return 0, errors.New("empty size")
+	}
+
+	if strings.HasPrefix(input, "-") {
+		return 0, fmt.Errorf("invalid size %q: must not be negative", s)
+	}
+
+	num, unit, rest, err := nextComponent(strings.ToLower(input))
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+
+	if rest != "" {
+		return 0, fmt.Errorf("invalid size %q: unexpected %q", s, rest)
+	}
+
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q (use B, KB, MB, GB, KiB, MiB or GiB)", s, unit)
+	}
+
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: bad number %q", s, num)
+	}
+
+	size := value * float64(multiplier)
+	if size > math.MaxInt {
+		return 0, fmt.Errorf("invalid size %q: too large", s)
+	}
+
+	return int64(size), nil
+}
+
+// nextComponent splits "<number><spaces><unit>" off the front of s, returning
+// the rest without leading spaces. A unit ends at the next digit or space.
+func nextComponent(s string) (num, unit, rest string, err error) {
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+
+	if i == 0 {
+		return "", "", "", fmt.Errorf("expected a number at %q", s)
+	}
+
+	num = s[:i]
+
+	if i < len(s) && s[i] == ',' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9' {
+		return "", "", "", errors.New(`use "." as the decimal separator`)
+	}
+
+	s = strings.TrimLeft(s[i:], " ")
+
+	j := 0
+	for j < len(s) && s[j] != ' ' && (s[j] < '0' || s[j] > '9') {
+		j++
+	}
+
+	return num, s[:j], strings.TrimLeft(s[j:], " "), nil
+}
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	byteSizeType = reflect.TypeFor[ByteSize]()
+)
+
+// decodeYAML decodes YAML into out after rewriting human-friendly durations
+// and sizes (see ParseDuration and ParseSize) into the forms yaml.v3
+// understands. Invalid values are reported as *FieldError, all at once.
+func decodeYAML(data []byte, out any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Kind == 0 {
+		return nil
+	}
+
+	if errs := normalizeUnits(&doc, reflect.TypeOf(out), ""); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return doc.Decode(out)
+}
+
+// normalizeUnits walks node alongside the Go type it decodes into and
+// rewrites the scalars of time.Duration and ByteSize fields in place.
+func normalizeUnits(node *yaml.Node, t reflect.Type, path string) []error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		var errs []error
+		for _, child := range node.Content {
+			errs = append(errs, normalizeUnits(child, t, path)...)
+		}
+
+		return errs
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag != "!!null" {
+		switch t {
+		case durationType:
+			d, err := ParseDuration(node.Value)
+			if err != nil {
+				return []error{&FieldError{Field: path, Line: node.Line, Err: err}}
+			}
+
+			node.Value, node.Tag, node.Style = d.String(), "!!str", 0
+
+			return nil
+		case byteSizeType:
+			n, err := ParseSize(node.Value)
+			if err != nil {
+				return []error{&FieldError{Field: path, Line: node.Line, Err: err}}
+			}
+
+			node.Value, node.Tag, node.Style = strconv.FormatInt(n, 10), "!!int", 0
+
+			return nil
+		}
+	}
+
+	var errs []error
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := taggedFields(t, "yaml")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if field, ok := fields[key]; ok {
+				errs = append(errs, normalizeUnits(node.Content[i+1], field.Type, joinPath(path, key))...)
+			}
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			errs = append(errs, normalizeUnits(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, child := range node.Content {
+			errs = append(errs, normalizeUnits(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return errs
+}
+
+// taggedFields indexes the exported fields of a struct by their key in the
+// given encoding's struct tag ("yaml" or "json").
+func taggedFields(t reflect.Type, tag string) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		fields[name] = f
+	}
+
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr string
+	}{
+		{input: "2m", want: 2 * time.Minute},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "1h 30m", want: 90 * time.Minute},
+		{input: "500ms", want: 500 * time.Millisecond},
+		{input: "1.5h", want: 90 * time.Minute},
+		{input: "90 seconds", want: 90 * time.Second},
+		{input: "1 Hour 30 Minutes", want: 90 * time.Minute},
+		{input: "2 days", want: 48 * time.Hour},
+		{input: "0", want: 0},
+		{input: "", wantErr: "empty duration"},
+		{input: "30", wantErr: "missing unit after 30"},
+		{input: "90 secnds", wantErr: `unknown unit "secnds"`},
+		{input: "1,5h", wantErr: `use "." as the decimal separator`},
+		{input: "-5s", wantErr: "must not be negative"},
+		{input: "soon", wantErr: "expected a number"},
+		{input: "1..5s", wantErr: "bad number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDuration(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseDuration(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr string
+	}{
+		{input: "1048576", want: 1 << 20},
+		{input: "10MB", want: 10_000_000},
+		{input: "10 mb", want: 10_000_000},
+		{input: "512KiB", want: 512 << 10},
+		{input: "1.5GiB", want: 3 << 29},
+		{input: "64k", want: 64_000},
+		{input: "100 bytes", want: 100},
+		{input: "10 MBs", wantErr: `unknown unit "mbs"`},
+		{input: "10MB 5KB", wantErr: `unexpected "5kb"`},
+		{input: "-1MB", wantErr: "must not be negative"},
+		{input: "1,5MB", wantErr: `use "." as the decimal separator`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSize(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseSize(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHumanUnits(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Parse([]byte(`
+settings:
+  timeout: 90 seconds
+  code_mode:
+    timeout: 1m30s
+    max_output_bytes: 64KiB
+projects:
+  work:
+    settings:
+      timeout: 2 minutes
+    servers:
+      db:
+        health_check:
+          interval: 1 hour
+`), LoadOptions{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if cfg.Settings.Timeout != 90*time.Second {
+		t.Errorf("settings.timeout = %v", cfg.Settings.Timeout)
+	}
+
+	if cfg.Settings.CodeMode.Timeout != 90*time.Second || cfg.Settings.CodeMode.MaxOutputBytes != 64<<10 {
+		t.Errorf("settings.code_mode = %+v", cfg.Settings.CodeMode)
+	}
+
+	if got := cfg.Projects["work"].Settings.Timeout; got != 2*time.Minute {
+		t.Errorf("projects.work.settings.timeout = %v", got)
+	}
+
+	if got := cfg.Projects["work"].Servers["db"].Health.Interval; got != time.Hour {
+		t.Errorf("health_check.interval = %v", got)
+	}
+}
+
+func TestParseInvalidUnits(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse([]byte(`settings:
+  timeout: 30
+  code_mode:
+    max_output_bytes: 10 MBs
+`), LoadOptions{})
+	if err == nil {
+		t.Fatal("Parse() succeeded, want error")
+	}
+
+	for _, want := range []string{
+		"settings.timeout (line 2): invalid duration \"30\": missing unit",
+		"settings.code_mode.max_output_bytes (line 4): invalid size \"10 MBs\"",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "settings.timeout" {
+		t.Errorf("errors.As(*FieldError) = %+v", fieldErr)
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// DefaultWebUIListen is the address of the web UI when web_ui.listen is
+// empty.
+const DefaultWebUIListen = "127.0.0.1:7878"
+
+// errWebUINotLoopback is returned for a web UI address reachable from other
+// machines.
+var errWebUINotLoopback = errors.New("must be a loopback address such as 127.0.0.1:7878 or localhost:7878")
+
+// WebUIConfig enables a read-only web page on the primary instance showing
+// its servers, tools, health and recent tool calls, for teammates who do not
+// use the CLI. It only listens on loopback and every request needs Token.
+type WebUIConfig struct {
+	// Enabled serves the page. Off by default.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Listen is the loopback address served. Empty uses DefaultWebUIListen.
+	Listen string `yaml:"listen,omitempty"`
+	// Token must be presented once as ?token= (the browser then keeps a
+	// cookie) or on every request as "Authorization: Bearer". ${VAR}
+	// references are expanded from the environment. Empty generates a new
+	// token at startup, logged with the page's URL.
+	Token string `yaml:"token,omitempty"`
+}
+
+// IsEnabled reports whether the web UI is served.
+func (c *WebUIConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// EffectiveListen returns the address served, applying the default.
+func (c *WebUIConfig) EffectiveListen() string {
+	if c == nil || c.Listen == "" {
+		return DefaultWebUIListen
+	}
+
+	return c.Listen
+}
+
+// Clone creates a copy of the web UI config.
+func (c *WebUIConfig) Clone() *WebUIConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	return &clone
+}
+
+// ValidateWebUI checks that the web UI listens on a valid loopback address.
+func ValidateWebUI(c *WebUIConfig) error {
+	if c == nil || c.Listen == "" {
+		return nil
+	}
+
+	if err := ValidateListen(c.Listen); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	host, _, _ := net.SplitHostPort(c.Listen)
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("listen %q: %w", c.Listen, errWebUINotLoopback)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestParseWebUISettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		yaml       string
+		wantListen string
+		wantErr    string
+	}{
+		{name: "default address", yaml: "enabled: true", wantListen: config.DefaultWebUIListen},
+		{name: "loopback IP", yaml: "enabled: true\n    listen: 127.0.0.1:9000", wantListen: "127.0.0.1:9000"},
+		{name: "localhost", yaml: "enabled: true\n    listen: localhost:9000", wantListen: "localhost:9000"},
+		{name: "IPv6 loopback", yaml: "enabled: true\n    listen: '[::1]:9000'", wantListen: "[::1]:9000"},
+		{name: "all interfaces", yaml: "listen: ':9000'", wantErr: "settings.web_ui: listen \":9000\": must be a loopback address"},
+		{name: "LAN address", yaml: "listen: 192.168.1.10:9000", wantErr: "must be a loopback address"},
+		{name: "invalid port", yaml: "listen: 127.0.0.1:http", wantErr: "settings.web_ui: listen: invalid listen address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.Parse([]byte("settings:\n  web_ui:\n    "+tt.yaml+"\n"), config.LoadOptions{})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			webUI := cfg.Settings.WebUI
+			if !webUI.IsEnabled() || webUI.EffectiveListen() != tt.wantListen {
+				t.Errorf("web_ui = %+v, want enabled on %s", webUI, tt.wantListen)
+			}
+
+			if clone := cfg.Clone(); clone.Settings.WebUI == webUI {
+				t.Error("Clone() shared the web_ui settings pointer")
+			}
+		})
+	}
+}
+
+func TestWebUIConfig_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var nilCfg *config.WebUIConfig
+	if nilCfg.IsEnabled() || nilCfg.EffectiveListen() != config.DefaultWebUIListen {
+		t.Error("nil web_ui should be disabled with the default address")
+	}
+
+	if (&config.WebUIConfig{Listen: "127.0.0.1:9000"}).IsEnabled() {
+		t.Error("web_ui without enabled: true should be disabled")
+	}
+}
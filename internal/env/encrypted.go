@@ -0,0 +1,158 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Encrypted env file names. They are looked up next to the plaintext .env in
+// the global directory and in a project's .assern directory, so encrypted
+// project secrets can be committed alongside the rest of the config.
+const (
+	// AgeEnvFile is a dotenv file encrypted with age.
+	AgeEnvFile = ".env.age"
+	// SopsYAMLEnvFile is a sops-encrypted YAML file of flat KEY: value pairs.
+	SopsYAMLEnvFile = ".env.sops.yaml"
+	// SopsJSONEnvFile is a sops-encrypted JSON object of flat "KEY": "value" pairs.
+	SopsJSONEnvFile = ".env.sops.json"
+)
+
+// EncryptedEnvFiles lists the encrypted env file names in load order.
+// Later files take precedence when they define the same key.
+var EncryptedEnvFiles = []string{AgeEnvFile, SopsYAMLEnvFile, SopsJSONEnvFile}
+
+// Environment variables that locate the age identity used for .env.age files.
+const (
+	// EnvAgeIdentity points at an age identity file and takes precedence.
+	EnvAgeIdentity = "ASSERN_AGE_IDENTITY"
+	// EnvSopsAgeKeyFile is honoured so an existing sops setup works unchanged.
+	EnvSopsAgeKeyFile = "SOPS_AGE_KEY_FILE"
+)
+
+// ErrNoAgeIdentity is returned when a .env.age file exists but no identity
+// file can be found to decrypt it.
+var ErrNoAgeIdentity = errors.New("no age identity found")
+
+// LoadEncryptedDir decrypts every encrypted env file present in dir and merges
+// the variables into the given layer ("global" or "project"). Missing files
+// are skipped. It returns the paths that were loaded; decryption failures are
+// joined into the error without stopping the remaining files from loading.
+func (l *Loader) LoadEncryptedDir(ctx context.Context, dir, layer string) ([]string, error) {
+	var (
+		loaded []string
+		errs   []error
+	)
+
+	for _, name := range EncryptedEnvFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		vars, err := DecryptEnvFile(ctx, path)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		for k, v := range vars {
+			l.Set(layer, k, v)
+		}
+
+		loaded = append(loaded, path)
+	}
+
+	return loaded, errors.Join(errs...)
+}
+
+// DecryptEnvFile decrypts an encrypted env file and parses the plaintext.
+// The format is chosen from the file name: .age files are decrypted with the
+// age CLI and parsed as dotenv; .sops.yaml/.sops.json files are decrypted by
+// the sops CLI, which resolves its own keys.
+func DecryptEnvFile(ctx context.Context, path string) (map[string]string, error) {
+	var (
+		plaintext []byte
+		err       error
+	)
+
+	switch {
+	case strings.HasSuffix(path, ".age"):
+		plaintext, err = decryptAge(ctx, path)
+	case strings.HasSuffix(path, ".sops.yaml"), strings.HasSuffix(path, ".sops.json"):
+		plaintext, err = runDecrypt(ctx, "sops", "--decrypt", "--output-type", "dotenv", path)
+	default:
+		return nil, fmt.Errorf("decrypt %s: unsupported encrypted env file", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", path, err)
+	}
+
+	vars, err := godotenv.Parse(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, fmt.Errorf("parse decrypted %s: %w", path, err)
+	}
+
+	return vars, nil
+}
+
+// decryptAge decrypts an age-encrypted file with the local identity.
+func decryptAge(ctx context.Context, path string) ([]byte, error) {
+	identity, err := ageIdentityPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return runDecrypt(ctx, "age", "--decrypt", "--identity", identity, path)
+}
+
+// ageIdentityPath resolves the age identity file: ASSERN_AGE_IDENTITY, then
+// SOPS_AGE_KEY_FILE, then sops' default ~/.config/sops/age/keys.txt.
+func ageIdentityPath() (string, error) {
+	for _, key := range []string{EnvAgeIdentity, EnvSopsAgeKeyFile} {
+		if path := os.Getenv(key); path != "" {
+			return path, nil
+		}
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrNoAgeIdentity, err)
+	}
+
+	path := filepath.Join(configDir, "sops", "age", "keys.txt")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("%w: set %s", ErrNoAgeIdentity, EnvAgeIdentity)
+	}
+
+	return path, nil
+}
+
+// runDecrypt runs an external decryption tool and returns its stdout. Stderr
+// is folded into the error so a missing key is reported clearly.
+func runDecrypt(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installFakeTool writes an executable shell script named name into a temp
+// directory and puts that directory first on PATH.
+func installFakeTool(t *testing.T, name, script string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake decryption tools require a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestDecryptEnvFileAge(t *testing.T) {
+	// The fake age prints the last argument (the input file) verbatim,
+	// so the "ciphertext" is plain dotenv.
+	installFakeTool(t, "age", `for last; do :; done; cat "$last"`)
+
+	identity := filepath.Join(t.TempDir(), "keys.txt")
+	t.Setenv(EnvAgeIdentity, identity)
+
+	path := filepath.Join(t.TempDir(), AgeEnvFile)
+	if err := os.WriteFile(path, []byte("API_KEY=secret\nOTHER=\"quoted value\"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	vars, err := DecryptEnvFile(t.Context(), path)
+	if err != nil {
+		t.Fatalf("DecryptEnvFile() error = %v", err)
+	}
+
+	if vars["API_KEY"] != "secret" || vars["OTHER"] != "quoted value" {
+		t.Errorf("DecryptEnvFile() = %v", vars)
+	}
+}
+
+func TestDecryptEnvFileAgeNoIdentity(t *testing.T) {
+	t.Setenv(EnvAgeIdentity, "")
+	t.Setenv(EnvSopsAgeKeyFile, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), AgeEnvFile)
+	if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := DecryptEnvFile(t.Context(), path); !errors.Is(err, ErrNoAgeIdentity) {
+		t.Errorf("DecryptEnvFile() error = %v, want ErrNoAgeIdentity", err)
+	}
+}
+
+func TestDecryptEnvFileToolFailure(t *testing.T) {
+	installFakeTool(t, "sops", `echo "no matching key" >&2; exit 1`)
+
+	path := filepath.Join(t.TempDir(), SopsYAMLEnvFile)
+	if err := os.WriteFile(path, []byte("API_KEY: ENC[...]\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	_, err := DecryptEnvFile(t.Context(), path)
+	if err == nil {
+		t.Fatal("DecryptEnvFile() expected error")
+	}
+
+	if got := err.Error(); !strings.Contains(got, "no matching key") {
+		t.Errorf("error %q should include tool stderr", got)
+	}
+}
+
+func TestLoadEncryptedDir(t *testing.T) {
+	installFakeTool(t, "sops", `for last; do :; done; cat "$last"`)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, SopsYAMLEnvFile), []byte("TOKEN=from-yaml\nSHARED=yaml\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, SopsJSONEnvFile), []byte("SHARED=json\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := NewLoader()
+
+	loaded, err := loader.LoadEncryptedDir(t.Context(), dir, "project")
+	if err != nil {
+		t.Fatalf("LoadEncryptedDir() error = %v", err)
+	}
+
+	if len(loaded) != 2 {
+		t.Errorf("LoadEncryptedDir() loaded %v, want 2 files", loaded)
+	}
+
+	if got := loader.Get("TOKEN"); got != "from-yaml" {
+		t.Errorf("TOKEN = %q, want from-yaml", got)
+	}
+
+	// Later files in EncryptedEnvFiles win.
+	if got := loader.Get("SHARED"); got != "json" {
+		t.Errorf("SHARED = %q, want json", got)
+	}
+}
+
+func TestLoadEncryptedDirEmpty(t *testing.T) {
+	t.Parallel()
+
+	loaded, err := NewLoader().LoadEncryptedDir(t.Context(), t.TempDir(), "global")
+	if err != nil || len(loaded) != 0 {
+		t.Errorf("LoadEncryptedDir() = %v, %v; want nothing loaded", loaded, err)
+	}
+}
@@ -18,13 +18,29 @@
 package env
 
 import (
+	"errors"
+	"fmt"
 	"maps"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// Plaintext env file names looked up in a project's .assern directory.
+const (
+	// ProjectEnvFile holds the project's variables.
+	ProjectEnvFile = ".env"
+	// ProjectEnvLocalFile holds a developer's own overrides and is meant to
+	// stay out of version control.
+	ProjectEnvLocalFile = ".env.local"
+)
+
+// ProjectEnvFiles lists the plaintext project env file names in load order.
+// Later files take precedence when they define the same key.
+var ProjectEnvFiles = []string{ProjectEnvFile, ProjectEnvLocalFile}
+
 // ExpandEnv expands environment variable references in a string.
 // It supports both ${VAR} and $VAR syntax.
 // If a referenced variable is not set, it will be replaced with an empty string.
@@ -91,6 +107,41 @@ func (l *Loader) LoadDotenv(path string) error {
 	return nil
 }
 
+// LoadDotenvDir loads every file of ProjectEnvFiles present in dir and
+// merges the variables into the given layer ("global" or "project"). Missing
+// files are skipped. It returns the paths that were loaded; parse failures
+// are joined into the error without stopping the remaining files from
+// loading. References in a file are expanded against that file and the
+// process environment, as for the global .env.
+func (l *Loader) LoadDotenvDir(dir, layer string) ([]string, error) {
+	var (
+		loaded []string
+		errs   []error
+	)
+
+	for _, name := range ProjectEnvFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		vars, err := godotenv.Read(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parse %s: %w", path, err))
+
+			continue
+		}
+
+		for k, v := range vars {
+			l.Set(layer, k, v)
+		}
+
+		loaded = append(loaded, path)
+	}
+
+	return loaded, errors.Join(errs...)
+}
+
 // SetLayer sets environment variables for a specific layer.
 // Valid layer names: "base", "global", "project".
 func (l *Loader) SetLayer(layer string, vars map[string]string) {
@@ -138,17 +189,25 @@ func (l *Loader) Set(layer, key, value string) {
 // Get retrieves an environment variable by key.
 // Resolution order: project → global → base (highest to lowest priority).
 func (l *Loader) Get(key string) string {
+	val, _ := l.Lookup(key)
+
+	return val
+}
+
+// Lookup is Get that also reports whether a layer sets the variable, which
+// may be set to an empty string.
+func (l *Loader) Lookup(key string) (string, bool) {
 	if val, ok := l.project[key]; ok {
-		return val
+		return val, true
 	}
 	if val, ok := l.global[key]; ok {
-		return val
+		return val, true
 	}
 	if val, ok := l.base[key]; ok {
-		return val
+		return val, true
 	}
 
-	return ""
+	return "", false
 }
 
 // Expand expands environment variable references in a string.
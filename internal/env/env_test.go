@@ -2,6 +2,8 @@ package env
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -227,3 +229,120 @@ func TestExpandEnvWithHome(t *testing.T) {
 		t.Errorf("ExpandEnv($HOME) = %q, want %q", result, home+"/.config")
 	}
 }
+
+func TestLoadDotenvDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ProjectEnvFile), []byte("TOKEN=shared\nHOST=example.com\nURL=https://${HOST}/api\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ProjectEnvLocalFile), []byte("TOKEN=mine\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := NewLoader()
+
+	loaded, err := loader.LoadDotenvDir(dir, "project")
+	if err != nil {
+		t.Fatalf("LoadDotenvDir() error = %v", err)
+	}
+
+	if len(loaded) != 2 {
+		t.Errorf("LoadDotenvDir() loaded %v, want 2 files", loaded)
+	}
+
+	// Later files in ProjectEnvFiles win.
+	if got := loader.Get("TOKEN"); got != "mine" {
+		t.Errorf("TOKEN = %q, want mine", got)
+	}
+
+	// References in a file see the variables defined above them.
+	if got := loader.Get("URL"); got != "https://example.com/api" {
+		t.Errorf("URL = %q, want https://example.com/api", got)
+	}
+}
+
+func TestLoadDotenvDirErrors(t *testing.T) {
+	t.Parallel()
+
+	loaded, err := NewLoader().LoadDotenvDir(t.TempDir(), "project")
+	if err != nil || len(loaded) != 0 {
+		t.Errorf("LoadDotenvDir(empty) = %v, %v; want nothing loaded", loaded, err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ProjectEnvFile), []byte("BROKEN='unterminated\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ProjectEnvLocalFile), []byte("OK=1\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := NewLoader()
+
+	loaded, err = loader.LoadDotenvDir(dir, "project")
+	if err == nil || !strings.Contains(err.Error(), ProjectEnvFile) {
+		t.Errorf("LoadDotenvDir() error = %v, want the broken file named", err)
+	}
+
+	if len(loaded) != 1 || loader.Get("OK") != "1" {
+		t.Errorf("LoadDotenvDir() loaded %v, want .env.local despite the broken .env", loaded)
+	}
+}
+
+func TestBuildServerEnvLayers(t *testing.T) {
+	t.Parallel()
+
+	loader := &Loader{
+		base:    map[string]string{"TOKEN": "base", "HOST": "base.example", "ONLY_BASE": "b"},
+		global:  map[string]string{"TOKEN": "global", "HOST": "global.example"},
+		project: map[string]string{"TOKEN": "project"},
+	}
+
+	tests := []struct {
+		name      string
+		serverEnv map[string]string
+		want      map[string]string
+	}{
+		{
+			name: "project over global over base",
+			want: map[string]string{"TOKEN": "project", "HOST": "global.example", "ONLY_BASE": "b"},
+		},
+		{
+			name:      "server env expanded against the layers",
+			serverEnv: map[string]string{"API": "https://${HOST}/?t=${TOKEN}&b=${ONLY_BASE}"},
+			want:      map[string]string{"API": "https://global.example/?t=project&b=b"},
+		},
+		{
+			name:      "server env over every layer",
+			serverEnv: map[string]string{"TOKEN": "server-${TOKEN}"},
+			want:      map[string]string{"TOKEN": "server-project"},
+		},
+		{
+			name:      "unknown reference",
+			serverEnv: map[string]string{"X": "${MISSING}"},
+			want:      map[string]string{"X": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := environToMap(loader.BuildServerEnv(tt.serverEnv, "work"))
+
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("BuildServerEnv() %s = %q, want %q", k, got[k], v)
+				}
+			}
+
+			if got["ASSERN_PROJECT"] != "work" {
+				t.Errorf("BuildServerEnv() ASSERN_PROJECT = %q, want work", got["ASSERN_PROJECT"])
+			}
+		})
+	}
+}
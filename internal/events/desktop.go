@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// desktopRepeatInterval suppresses repeats of the same notification so a
+// flapping server does not flood the desktop.
+const desktopRepeatInterval = 5 * time.Minute
+
+// DesktopDefaultEvents are the events a desktop sink shows when it has no
+// explicit events filter: the failures that silently remove tools.
+var DesktopDefaultEvents = []Type{ServerFailed, AuthExpired}
+
+// errNoDesktopSession is returned when no graphical session is available.
+var errNoDesktopSession = errors.New("no desktop session detected")
+
+// notifyCommand builds the command line that shows a notification.
+type notifyCommand func(title, body string) (string, []string)
+
+// desktopSink shows native notifications via osascript (macOS) or
+// notify-send (Linux and other freedesktop systems).
+type desktopSink struct {
+	command notifyCommand
+
+	mu   sync.Mutex
+	last map[string]time.Time
+	now  func() time.Time
+}
+
+// newDesktopSink creates a desktop sink when the process runs inside a
+// desktop session with a notification tool available.
+func newDesktopSink() (*desktopSink, error) {
+	command, err := detectNotifier(runtime.GOOS, os.Getenv, exec.LookPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &desktopSink{
+		command: command,
+		last:    make(map[string]time.Time),
+		now:     time.Now,
+	}, nil
+}
+
+// detectNotifier picks the notification command for goos.
+func detectNotifier(goos string, getenv func(string) string, lookPath func(string) (string, error)) (notifyCommand, error) {
+	switch goos {
+	case "darwin":
+		if _, err := lookPath("osascript"); err != nil {
+			return nil, fmt.Errorf("%w: osascript not found", errNoDesktopSession)
+		}
+
+		return func(title, body string) (string, []string) {
+			script := "display notification " + strconv.Quote(body) + " with title " + strconv.Quote(title)
+
+			return "osascript", []string{"-e", script}
+		}, nil
+	case "windows":
+		return nil, fmt.Errorf("%w: unsupported on %s", errNoDesktopSession, goos)
+	default:
+		if getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "" {
+			return nil, errNoDesktopSession
+		}
+
+		if _, err := lookPath("notify-send"); err != nil {
+			return nil, fmt.Errorf("%w: notify-send not found", errNoDesktopSession)
+		}
+
+		return func(title, body string) (string, []string) {
+			return "notify-send", []string{"--app-name=assern", "--urgency=critical", title, body}
+		}, nil
+	}
+}
+
+// Send shows the event unless the same notification was shown recently.
+func (d *desktopSink) Send(ctx context.Context, e Event) error {
+	if !d.due(e) {
+		return nil
+	}
+
+	name, args := d.command(desktopTitle(e), desktopBody(e))
+
+	if out, err := exec.CommandContext(ctx, name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, out)
+	}
+
+	return nil
+}
+
+// due records e and reports whether it should be shown now.
+func (d *desktopSink) due(e Event) bool {
+	key := string(e.Type) + "\x00" + e.Server
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if last, ok := d.last[key]; ok && now.Sub(last) < desktopRepeatInterval {
+		return false
+	}
+
+	d.last[key] = now
+
+	return true
+}
+
+// desktopTitle is the notification heading for an event.
+func desktopTitle(e Event) string {
+	switch e.Type {
+	case ServerFailed:
+		return "assern: server " + e.Server + " failed"
+	case AuthExpired:
+		return "assern: " + e.Server + " needs re-authorization"
+	default:
+		return "assern: " + string(e.Type)
+	}
+}
+
+// desktopBody is the notification text for an event.
+func desktopBody(e Event) string {
+	switch {
+	case e.Type == AuthExpired:
+		return "Its tools are unavailable until you sign in again. " + e.Message
+	case e.Message != "":
+		return e.Message
+	default:
+		return e.String()
+	}
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestDetectNotifier(t *testing.T) {
+	t.Parallel()
+
+	found := func(string) (string, error) { return "/usr/bin/x", nil }
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+
+	tests := []struct {
+		name     string
+		goos     string
+		env      map[string]string
+		lookPath func(string) (string, error)
+		wantCmd  string
+	}{
+		{name: "linux x11", goos: "linux", env: map[string]string{"DISPLAY": ":0"}, lookPath: found, wantCmd: "notify-send"},
+		{name: "linux wayland", goos: "linux", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, lookPath: found, wantCmd: "notify-send"},
+		{name: "linux headless", goos: "linux", lookPath: found},
+		{name: "linux without notify-send", goos: "linux", env: map[string]string{"DISPLAY": ":0"}, lookPath: missing},
+		{name: "macos", goos: "darwin", lookPath: found, wantCmd: "osascript"},
+		{name: "windows", goos: "windows", lookPath: found},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			getenv := func(k string) string { return tt.env[k] }
+
+			command, err := detectNotifier(tt.goos, getenv, tt.lookPath)
+			if tt.wantCmd == "" {
+				if !errors.Is(err, errNoDesktopSession) {
+					t.Fatalf("err = %v, want errNoDesktopSession", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("detectNotifier: %v", err)
+			}
+
+			name, args := command("title", `say "hi"`)
+			if name != tt.wantCmd {
+				t.Errorf("command = %q, want %q", name, tt.wantCmd)
+			}
+
+			if !strings.Contains(strings.Join(args, " "), "hi") {
+				t.Errorf("args %q missing body", args)
+			}
+		})
+	}
+}
+
+func TestDesktopSinkThrottlesRepeats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	d := &desktopSink{last: make(map[string]time.Time), now: func() time.Time { return now }}
+
+	failed := Event{Type: ServerFailed, Server: "github"}
+
+	if !d.due(failed) {
+		t.Fatal("first notification should be shown")
+	}
+
+	if d.due(failed) {
+		t.Error("repeat within interval should be suppressed")
+	}
+
+	if !d.due(Event{Type: ServerFailed, Server: "slack"}) {
+		t.Error("different server should be shown")
+	}
+
+	now = now.Add(desktopRepeatInterval)
+
+	if !d.due(failed) {
+		t.Error("repeat after interval should be shown")
+	}
+}
+
+func TestDesktopSinkSend(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := filepath.Join(dir, "notify.sh")
+
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > \""+out+"\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &desktopSink{
+		command: func(title, body string) (string, []string) { return script, []string{title, body} },
+		last:    make(map[string]time.Time),
+		now:     time.Now,
+	}
+
+	if err := d.Send(t.Context(), Event{Type: AuthExpired, Server: "jira", Message: "token expired"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "assern: jira needs re-authorization\nIts tools are unavailable until you sign in again. token expired\n"
+	if string(data) != want {
+		t.Errorf("notification = %q, want %q", data, want)
+	}
+}
+
+func TestSinkFilterDesktopDefaults(t *testing.T) {
+	t.Parallel()
+
+	wants := sinkFilter(&config.EventSinkConfig{Type: config.EventSinkDesktop})
+
+	if !wants(ServerFailed) || !wants(AuthExpired) {
+		t.Error("desktop sink should default to failure events")
+	}
+
+	if wants(ServerStarted) || wants(ReloadApplied) {
+		t.Error("desktop sink should not show routine events by default")
+	}
+
+	explicit := sinkFilter(&config.EventSinkConfig{Type: config.EventSinkDesktop, Events: []string{string(ReloadApplied)}})
+	if !explicit(ReloadApplied) || explicit(ServerFailed) {
+		t.Error("explicit events filter should override the defaults")
+	}
+}
@@ -0,0 +1,240 @@
+// Package events provides a small asynchronous event bus for notable
+// aggregator occurrences, with webhook, exec and desktop notification sinks.
+//
+// Publishing never blocks the caller: events are queued and delivered by a
+// background worker, and are dropped (with a log line) if the queue is full.
+//
+//	bus := events.NewBus(cfg.Settings.Events, expand, logger)
+//	defer bus.Close()
+//	bus.Publish(events.Event{Type: events.ServerFailed, Server: "github", Message: err.Error()})
+//
+// A nil *Bus is valid and discards every event.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Type identifies the kind of event.
+type Type string
+
+// Event types.
+const (
+	// ServerStarted is published when a backend server starts.
+	ServerStarted Type = "server_started"
+	// ServerFailed is published when a backend fails to start or turns unhealthy.
+	ServerFailed Type = "server_failed"
+	// ReloadApplied is published after a configuration reload changes servers.
+	ReloadApplied Type = "reload_applied"
+	// PolicyBlocked is published when a request is refused by access policy.
+	PolicyBlocked Type = "policy_blocked"
+	// QuotaExceeded is published when a request is refused by a limit.
+	QuotaExceeded Type = "quota_exceeded"
+	// AuthExpired is published when a server rejects its credentials, e.g.
+	// because an OAuth token expired and needs re-authorization.
+	AuthExpired Type = "auth_expired"
+)
+
+// queueSize is the number of undelivered events buffered before new ones are dropped.
+const queueSize = 64
+
+// Event is a single notable occurrence.
+type Event struct {
+	Type    Type           `json:"type"`
+	Time    time.Time      `json:"time"`
+	Server  string         `json:"server,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// String renders the event as a one-line human readable summary.
+func (e Event) String() string {
+	switch {
+	case e.Server != "" && e.Message != "":
+		return fmt.Sprintf("assern %s: %s: %s", e.Type, e.Server, e.Message)
+	case e.Server != "":
+		return fmt.Sprintf("assern %s: %s", e.Type, e.Server)
+	case e.Message != "":
+		return fmt.Sprintf("assern %s: %s", e.Type, e.Message)
+	default:
+		return "assern " + string(e.Type)
+	}
+}
+
+// Sink delivers events to one destination.
+type Sink interface {
+	Send(ctx context.Context, e Event) error
+}
+
+// subscription pairs a sink with its filter and delivery timeout.
+type subscription struct {
+	name    string
+	sink    Sink
+	wants   func(Type) bool
+	timeout time.Duration
+}
+
+// Bus fans events out to subscribed sinks.
+type Bus struct {
+	logger *slog.Logger
+	subs   []subscription
+	queue  chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// New creates a bus that delivers every event to the given sinks.
+func New(logger *slog.Logger, sinks ...Sink) *Bus {
+	subs := make([]subscription, 0, len(sinks))
+	for i, sink := range sinks {
+		subs = append(subs, subscription{
+			name:    fmt.Sprintf("sink[%d]", i),
+			sink:    sink,
+			wants:   func(Type) bool { return true },
+			timeout: config.DefaultEventSinkTimeout,
+		})
+	}
+
+	return newBus(logger, subs)
+}
+
+// NewBus builds a bus from configuration. expand is applied to webhook URLs
+// and header values (pass nil to use them verbatim). Invalid sinks are
+// logged and skipped. It returns nil when no usable sinks are configured.
+func NewBus(cfg *config.EventsConfig, expand func(string) string, logger *slog.Logger) *Bus {
+	if cfg == nil || len(cfg.Sinks) == 0 {
+		return nil
+	}
+
+	if expand == nil {
+		expand = func(s string) string { return s }
+	}
+
+	var subs []subscription
+
+	for i, sc := range cfg.Sinks {
+		sink, err := newSink(sc, expand)
+		if errors.Is(err, errNoDesktopSession) {
+			logger.Debug("desktop notifications disabled", "reason", err)
+
+			continue
+		}
+
+		if err != nil {
+			logger.Warn("skipping invalid event sink", "index", i, "error", err)
+
+			continue
+		}
+
+		subs = append(subs, subscription{
+			name:    fmt.Sprintf("%s[%d]", sc.Type, i),
+			sink:    sink,
+			wants:   sinkFilter(sc),
+			timeout: sc.EffectiveTimeout(),
+		})
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	return newBus(logger, subs)
+}
+
+// newBus creates a bus and starts its delivery worker.
+func newBus(logger *slog.Logger, subs []subscription) *Bus {
+	b := &Bus{
+		logger: logger,
+		subs:   subs,
+		queue:  make(chan Event, queueSize),
+		done:   make(chan struct{}),
+	}
+
+	go b.run()
+
+	return b
+}
+
+// Publish queues an event for delivery. It never blocks.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	select {
+	case b.queue <- e:
+	default:
+		b.logger.Warn("event queue full, dropping event", "type", e.Type)
+	}
+}
+
+// Close stops accepting events and waits for queued ones to be delivered.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.once.Do(func() {
+		close(b.queue)
+		<-b.done
+	})
+}
+
+// run delivers queued events to every interested sink, in order.
+func (b *Bus) run() {
+	defer close(b.done)
+
+	for e := range b.queue {
+		for _, sub := range b.subs {
+			if !sub.wants(e.Type) {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), sub.timeout)
+			if err := sub.sink.Send(ctx, e); err != nil {
+				b.logger.Warn("event delivery failed", "sink", sub.name, "type", e.Type, "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// newSink constructs the sink described by a config entry.
+func newSink(sc *config.EventSinkConfig, expand func(string) string) (Sink, error) {
+	if sc == nil {
+		return nil, ErrInvalidSink
+	}
+
+	switch sc.Type {
+	case config.EventSinkWebhook:
+		return newWebhookSink(sc, expand)
+	case config.EventSinkExec:
+		return newExecSink(sc)
+	case config.EventSinkDesktop:
+		return newDesktopSink()
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidSink, sc.Type)
+	}
+}
+
+// sinkFilter returns the event filter for a sink. Desktop sinks without an
+// explicit events list only show DesktopDefaultEvents.
+func sinkFilter(sc *config.EventSinkConfig) func(Type) bool {
+	if sc.Type == config.EventSinkDesktop && len(sc.Events) == 0 {
+		return func(t Type) bool { return slices.Contains(DesktopDefaultEvents, t) }
+	}
+
+	return func(t Type) bool { return sc.Wants(string(t)) }
+}
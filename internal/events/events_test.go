@@ -0,0 +1,275 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// recordingSink collects delivered events.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recordingSink) Send(_ context.Context, e Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+
+	return nil
+}
+
+func (r *recordingSink) types() []Type {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	types := make([]Type, 0, len(r.events))
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+
+	return types
+}
+
+func TestBusDeliversInOrder(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordingSink{}
+	bus := New(slog.New(slog.DiscardHandler), sink)
+
+	bus.Publish(Event{Type: ServerStarted, Server: "github"})
+	bus.Publish(Event{Type: ReloadApplied})
+	bus.Close()
+
+	got := sink.types()
+	if len(got) != 2 || got[0] != ServerStarted || got[1] != ReloadApplied {
+		t.Fatalf("delivered = %v", got)
+	}
+
+	if sink.events[0].Time.IsZero() {
+		t.Error("event time not set")
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	t.Parallel()
+
+	var bus *Bus
+
+	bus.Publish(Event{Type: ServerFailed})
+	bus.Close()
+
+	if NewBus(nil, nil, slog.New(slog.DiscardHandler)) != nil {
+		t.Error("NewBus(nil) should return nil")
+	}
+}
+
+func TestEventString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{Event{Type: ServerFailed, Server: "github", Message: "boom"}, "assern server_failed: github: boom"},
+		{Event{Type: ServerStarted, Server: "github"}, "assern server_started: github"},
+		{Event{Type: QuotaExceeded, Message: "too many"}, "assern quota_exceeded: too many"},
+		{Event{Type: ReloadApplied}, "assern reload_applied"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.event.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, body map[string]any)
+	}{
+		{
+			name: "json",
+			check: func(t *testing.T, body map[string]any) {
+				t.Helper()
+
+				if body["type"] != string(ServerFailed) || body["server"] != "github" {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+		{
+			name:   "slack",
+			format: config.WebhookFormatSlack,
+			check: func(t *testing.T, body map[string]any) {
+				t.Helper()
+
+				if body["text"] != "assern server_failed: github: boom" {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				body   map[string]any
+				header string
+			)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(data, &body)
+				header = r.Header.Get("Authorization")
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			cfg := &config.EventSinkConfig{
+				Type:    config.EventSinkWebhook,
+				URL:     "${HOOK}",
+				Format:  tt.format,
+				Headers: map[string]string{"Authorization": "Bearer ${TOKEN}"},
+			}
+			expand := func(s string) string {
+				return os.Expand(s, func(k string) string {
+					return map[string]string{"HOOK": srv.URL, "TOKEN": "secret"}[k]
+				})
+			}
+
+			sink, err := newWebhookSink(cfg, expand)
+			if err != nil {
+				t.Fatalf("newWebhookSink: %v", err)
+			}
+
+			if err := sink.Send(t.Context(), Event{Type: ServerFailed, Server: "github", Message: "boom"}); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			if header != "Bearer secret" {
+				t.Errorf("Authorization = %q", header)
+			}
+
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestWebhookSinkErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	sink, err := newWebhookSink(&config.EventSinkConfig{URL: srv.URL}, func(s string) string { return s })
+	if err != nil {
+		t.Fatalf("newWebhookSink: %v", err)
+	}
+
+	if err := sink.Send(t.Context(), Event{Type: ServerStarted}); err == nil {
+		t.Error("expected error for 500 response")
+	}
+}
+
+func TestExecSink(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := filepath.Join(dir, "hook.sh")
+
+	content := "#!/bin/sh\n{ echo \"$ASSERN_EVENT_TYPE $ASSERN_EVENT_SERVER\"; cat; } > \"$1\"\n"
+	if err := os.WriteFile(script, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := newExecSink(&config.EventSinkConfig{Command: script, Args: []string{out}})
+	if err != nil {
+		t.Fatalf("newExecSink: %v", err)
+	}
+
+	if err := sink.Send(t.Context(), Event{Type: ServerStarted, Server: "github"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, rest, _ := strings.Cut(string(data), "\n")
+	if first != "server_started github" {
+		t.Errorf("env line = %q", first)
+	}
+
+	var e Event
+	if err := json.Unmarshal([]byte(rest), &e); err != nil || e.Server != "github" {
+		t.Errorf("stdin = %q (%v)", rest, err)
+	}
+}
+
+func TestNewBusFiltersAndSkipsInvalid(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var e Event
+		_ = json.NewDecoder(r.Body).Decode(&e)
+
+		mu.Lock()
+		got = append(got, string(e.Type))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	bus := NewBus(&config.EventsConfig{Sinks: []*config.EventSinkConfig{
+		{Type: config.EventSinkWebhook, URL: srv.URL, Events: []string{string(ServerFailed)}},
+		{Type: config.EventSinkWebhook},
+		{Type: "carrier-pigeon"},
+	}}, nil, slog.New(slog.DiscardHandler))
+	if bus == nil {
+		t.Fatal("NewBus returned nil")
+	}
+
+	if len(bus.subs) != 1 {
+		t.Errorf("subscriptions = %d, want 1", len(bus.subs))
+	}
+
+	bus.Publish(Event{Type: ServerStarted})
+	bus.Publish(Event{Type: ServerFailed})
+	bus.Close()
+
+	if len(got) != 1 || got[0] != string(ServerFailed) {
+		t.Errorf("delivered = %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// ErrInvalidSink is returned for a sink configuration that cannot be used.
+var ErrInvalidSink = errors.New("invalid event sink")
+
+// webhookSink POSTs events to an HTTP endpoint.
+type webhookSink struct {
+	url     string
+	slack   bool
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookSink(sc *config.EventSinkConfig, expand func(string) string) (*webhookSink, error) {
+	url := expand(sc.URL)
+	if url == "" {
+		return nil, fmt.Errorf("%w: webhook requires url", ErrInvalidSink)
+	}
+
+	switch sc.Format {
+	case "", config.WebhookFormatJSON, config.WebhookFormatSlack:
+	default:
+		return nil, fmt.Errorf("%w: unknown webhook format %q", ErrInvalidSink, sc.Format)
+	}
+
+	headers := make(map[string]string, len(sc.Headers))
+	for k, v := range sc.Headers {
+		headers[k] = expand(v)
+	}
+
+	return &webhookSink{
+		url:     url,
+		slack:   sc.Format == config.WebhookFormatSlack,
+		headers: headers,
+		client:  &http.Client{},
+	}, nil
+}
+
+// Send posts the event. Slack format wraps the summary in {"text": ...}.
+func (w *webhookSink) Send(ctx context.Context, e Event) error {
+	var payload any = e
+	if w.slack {
+		payload = map[string]string{"text": e.String()}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("post webhook: status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// execSink runs a local command per event, with the event JSON on stdin and
+// the main fields in ASSERN_EVENT_* environment variables.
+type execSink struct {
+	command string
+	args    []string
+}
+
+func newExecSink(sc *config.EventSinkConfig) (*execSink, error) {
+	if sc.Command == "" {
+		return nil, fmt.Errorf("%w: exec requires command", ErrInvalidSink)
+	}
+
+	return &execSink{command: sc.Command, args: slices.Clone(sc.Args)}, nil
+}
+
+// Send runs the command and waits for it to exit.
+func (x *execSink) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, x.command, x.args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), eventEnv(e)...)
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", x.command, err, msg)
+		}
+
+		return fmt.Errorf("%s: %w", x.command, err)
+	}
+
+	return nil
+}
+
+// eventEnv returns the ASSERN_EVENT_* variables describing an event.
+func eventEnv(e Event) []string {
+	vars := map[string]string{
+		"ASSERN_EVENT_TYPE":    string(e.Type),
+		"ASSERN_EVENT_SERVER":  e.Server,
+		"ASSERN_EVENT_MESSAGE": e.Message,
+		"ASSERN_EVENT_SUMMARY": e.String(),
+	}
+
+	result := make([]string, 0, len(vars))
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		result = append(result, k+"="+vars[k])
+	}
+
+	return result
+}
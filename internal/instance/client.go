@@ -2,31 +2,54 @@ package instance
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+
 	"github.com/valksor/go-assern/internal/aggregator"
 )
 
 // ClientTimeout is the default timeout for client operations.
 const ClientTimeout = 10 * time.Second
 
-// ToolInfo represents tool information returned from a query.
+// ToolInfo represents tool information returned from a query. Server is
+// only known from assern/list, not from an MCP tools/list.
 type ToolInfo struct {
 	Name        string          `json:"name"`
+	Server      string          `json:"server,omitempty"`
 	Description string          `json:"description"`
 	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
 }
 
+// ResourceInfo represents resource information returned from a query.
+type ResourceInfo struct {
+	URI         string `json:"uri"`
+	Server      string `json:"server,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mimeType,omitempty"`
+}
+
+// PromptInfo represents prompt information returned from a query.
+type PromptInfo struct {
+	Name        string               `json:"name"`
+	Server      string               `json:"server,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Arguments   []mcp.PromptArgument `json:"arguments,omitempty"`
+}
+
 // ListResult contains the result of querying a running instance.
 type ListResult struct {
-	Tools          []ToolInfo
-	TokensByServer map[string]int
-	TotalTokens    int
+	Tools          []ToolInfo     `json:"tools"`
+	Resources      []ResourceInfo `json:"resources,omitempty"`
+	Prompts        []PromptInfo   `json:"prompts,omitempty"`
+	TokensByServer map[string]int `json:"tokens_by_server"`
+	TotalTokens    int            `json:"total_tokens"`
 }
 
 // Client connects to a running assern instance to query information.
@@ -35,6 +58,7 @@ type Client struct {
 	conn       net.Conn
 	reader     *bufio.Reader
 	requestID  int
+	trace      func(sent bool, msg []byte)
 }
 
 // NewClient creates a new client for the given socket path.
@@ -59,6 +83,12 @@ func (c *Client) Connect(ctx context.Context) error {
 	return nil
 }
 
+// SetTrace passes every JSON-RPC message the client sends (sent is true)
+// or reads, notifications included, to fn; nil stops tracing.
+func (c *Client) SetTrace(fn func(sent bool, msg []byte)) {
+	c.trace = fn
+}
+
 // Close closes the connection.
 func (c *Client) Close() error {
 	if c.conn != nil {
@@ -102,7 +132,7 @@ func (c *Client) Initialize(ctx context.Context) error {
 		} `json:"error"`
 	}
 
-	if err := c.readResponse(&initResp); err != nil {
+	if err := c.readResponse(ctx, &initResp); err != nil {
 		return fmt.Errorf("read initialize response: %w", err)
 	}
 
@@ -123,24 +153,65 @@ func (c *Client) Initialize(ctx context.Context) error {
 	return nil
 }
 
-// ListTools queries the available tools from the running instance.
+// ListTools queries the available tools from the running instance,
+// following nextCursor when the instance pages tools/list.
 func (c *Client) ListTools(ctx context.Context) (*ListResult, error) {
+	var (
+		tools  []ToolInfo
+		cursor string
+		seen   = make(map[string]bool)
+	)
+
+	for {
+		page, next, err := c.listToolsPage(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		tools = append(tools, page...)
+
+		if next == "" || seen[next] {
+			break
+		}
+
+		seen[next] = true
+		cursor = next
+	}
+
+	tokensByServer, totalTokens := estimateListTokens(tools)
+
+	return &ListResult{
+		Tools:          tools,
+		TokensByServer: tokensByServer,
+		TotalTokens:    totalTokens,
+	}, nil
+}
+
+// listToolsPage requests the page of tools/list starting at cursor and
+// returns its tools and the cursor of the next page.
+func (c *Client) listToolsPage(ctx context.Context, cursor string) ([]ToolInfo, string, error) {
+	params := map[string]any{}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+
 	c.requestID++
 	listReq := map[string]any{
 		keyJSONRPC: jsonrpcVersion,
 		"id":       c.requestID,
 		keyMethod:  "tools/list",
-		"params":   map[string]any{},
+		"params":   params,
 	}
 
 	if err := c.sendRequest(listReq); err != nil {
-		return nil, fmt.Errorf("send tools/list: %w", err)
+		return nil, "", fmt.Errorf("send tools/list: %w", err)
 	}
 
 	var resp struct {
 		ID     int `json:"id"`
 		Result struct {
-			Tools []ToolInfo `json:"tools"`
+			Tools      []ToolInfo `json:"tools"`
+			NextCursor string     `json:"nextCursor"`
 		} `json:"result"`
 		Error *struct {
 			Code    int    `json:"code"`
@@ -148,21 +219,15 @@ func (c *Client) ListTools(ctx context.Context) (*ListResult, error) {
 		} `json:"error"`
 	}
 
-	if err := c.readResponse(&resp); err != nil {
-		return nil, fmt.Errorf("read tools/list response: %w", err)
+	if err := c.readResponse(ctx, &resp); err != nil {
+		return nil, "", fmt.Errorf("read tools/list response: %w", err)
 	}
 
 	if resp.Error != nil {
-		return nil, fmt.Errorf("tools/list error: %s", resp.Error.Message)
+		return nil, "", fmt.Errorf("tools/list error: %s", resp.Error.Message)
 	}
 
-	tokensByServer, totalTokens := estimateListTokens(resp.Result.Tools)
-
-	return &ListResult{
-		Tools:          resp.Result.Tools,
-		TokensByServer: tokensByServer,
-		TotalTokens:    totalTokens,
-	}, nil
+	return resp.Result.Tools, resp.Result.NextCursor, nil
 }
 
 // estimateListTokens groups the estimated token cost of tool definitions by
@@ -196,6 +261,10 @@ func (c *Client) sendRequest(req any) error {
 		return err
 	}
 
+	if c.trace != nil {
+		c.trace(true, data)
+	}
+
 	data = append(data, '\n')
 
 	if _, err := c.conn.Write(data); err != nil {
@@ -205,88 +274,137 @@ func (c *Client) sendRequest(req any) error {
 	return nil
 }
 
-func (c *Client) readResponse(resp any) error {
-	if err := c.conn.SetReadDeadline(time.Now().Add(ClientTimeout)); err != nil {
-		return err
+// readResponse reads the next response into resp, skipping notifications
+// the instance sends in between (such as the startup summary). It waits
+// until ctx's deadline, or ClientTimeout without one.
+func (c *Client) readResponse(ctx context.Context, resp any) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(ClientTimeout)
 	}
-	defer func() { _ = c.conn.SetReadDeadline(time.Time{}) }()
 
-	line, err := c.reader.ReadBytes('\n')
-	if err != nil {
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
 		return err
 	}
+	defer func() { _ = c.conn.SetReadDeadline(time.Time{}) }()
+
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return err
+		}
+
+		if c.trace != nil {
+			c.trace(false, bytes.TrimSuffix(line, []byte("\n")))
+		}
+
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+
+		if err := json.Unmarshal(line, &msg); err == nil && msg.ID == nil && msg.Method != "" {
+			continue
+		}
 
-	return json.Unmarshal(line, resp)
+		return json.Unmarshal(line, resp)
+	}
 }
 
-// QueryTools connects to a running instance and returns the available tools.
-// This is a convenience function that handles the full connection lifecycle.
-func QueryTools(ctx context.Context, socketPath string) (*ListResult, error) {
-	client := NewClient(socketPath)
+// CallTool calls a tool of the running instance. A tool that fails is not
+// an error: the result has IsError set.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	c.requestID++
+	callReq := map[string]any{
+		keyJSONRPC: jsonrpcVersion,
+		"id":       c.requestID,
+		keyMethod:  "tools/call",
+		"params": map[string]any{
+			"name":      name,
+			"arguments": args,
+		},
+	}
 
-	if err := client.Connect(ctx); err != nil {
-		return nil, err
+	if err := c.sendRequest(callReq); err != nil {
+		return nil, fmt.Errorf("send tools/call: %w", err)
 	}
-	defer func() { _ = client.Close() }()
 
-	if err := client.Initialize(ctx); err != nil {
-		return nil, err
+	var resp struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
 	}
 
-	return client.ListTools(ctx)
-}
+	if err := c.readResponse(ctx, &resp); err != nil {
+		return nil, fmt.Errorf("read tools/call response: %w", err)
+	}
 
-// ReloadResult contains the result of a reload operation.
-type ReloadResult struct {
-	Added   int      `json:"added"`
-	Removed int      `json:"removed"`
-	Errors  []string `json:"errors,omitempty"`
-}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("tools/call error: %s", resp.Error.Message)
+	}
 
-// Reload triggers a configuration reload on a running instance.
-// This uses the internal command protocol (not MCP).
-func Reload(ctx context.Context, socketPath string) (*ReloadResult, error) {
-	var dialer net.Dialer
-	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	result, err := mcp.ParseCallToolResult(&resp.Result)
 	if err != nil {
-		return nil, fmt.Errorf("connect to socket: %w", err)
+		return nil, fmt.Errorf("parse tools/call result: %w", err)
 	}
-	defer func() { _ = conn.Close() }()
 
-	// Send reload request
-	reloadReq := map[string]any{
-		keyJSONRPC: jsonrpcVersion,
-		"id":       1,
-		keyMethod:  "assern/reload",
+	return result, nil
+}
+
+// Request sends an MCP request for method and returns its raw result, for
+// methods the client has no typed call for.
+func (c *Client) Request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	if params == nil {
+		params = map[string]any{}
 	}
-	if err := json.NewEncoder(conn).Encode(reloadReq); err != nil {
-		return nil, fmt.Errorf("send reload request: %w", err)
+
+	c.requestID++
+	req := map[string]any{
+		keyJSONRPC: jsonrpcVersion,
+		"id":       c.requestID,
+		keyMethod:  method,
+		"params":   params,
 	}
 
-	// Set read deadline
-	if err := conn.SetReadDeadline(time.Now().Add(ClientTimeout)); err != nil {
-		return nil, fmt.Errorf("set read deadline: %w", err)
+	if err := c.sendRequest(req); err != nil {
+		return nil, fmt.Errorf("send %s: %w", method, err)
 	}
 
-	// Read response
 	var resp struct {
-		Result *ReloadResult `json:"result"`
+		Result json.RawMessage `json:"result"`
 		Error  *struct {
 			Code    int    `json:"code"`
 			Message string `json:"message"`
 		} `json:"error"`
 	}
-	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
-		return nil, fmt.Errorf("read reload response: %w", err)
+
+	if err := c.readResponse(ctx, &resp); err != nil {
+		return nil, fmt.Errorf("read %s response: %w", method, err)
 	}
 
 	if resp.Error != nil {
-		return nil, fmt.Errorf("reload error: %s", resp.Error.Message)
+		return nil, fmt.Errorf("%s error: %s", method, resp.Error.Message)
+	}
+
+	return resp.Result, nil
+}
+
+// QueryTools connects to a running instance and returns the available tools.
+// This is a convenience function that handles the full connection lifecycle.
+func QueryTools(ctx context.Context, socketPath string) (*ListResult, error) {
+	client := NewClient(socketPath)
+
+	if err := client.Connect(ctx); err != nil {
+		return nil, err
 	}
+	defer func() { _ = client.Close() }()
 
-	if resp.Result == nil {
-		return nil, errors.New("empty reload response")
+	if err := client.Initialize(ctx); err != nil {
+		return nil, err
 	}
 
-	return resp.Result, nil
+	return client.ListTools(ctx)
 }
@@ -0,0 +1,184 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+)
+
+// ReloadResult contains the result of a reload operation.
+type ReloadResult struct {
+	Added    int      `json:"added"`
+	Removed  int      `json:"removed"`
+	Replaced int      `json:"replaced,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// ReloadParams are the params of the assern/reload command.
+type ReloadParams struct {
+	// BlueGreen starts changed servers alongside the running ones before
+	// swapping them in (see aggregator.ReloadBlueGreen)
+	BlueGreen bool `json:"blue_green,omitempty"`
+}
+
+// Reload triggers a configuration reload on a running instance.
+// This uses the internal command protocol (not MCP).
+func Reload(ctx context.Context, socketPath string) (*ReloadResult, error) {
+	return reload(ctx, socketPath, nil)
+}
+
+// ReloadBlueGreen triggers a blue-green configuration reload on a running
+// instance.
+func ReloadBlueGreen(ctx context.Context, socketPath string) (*ReloadResult, error) {
+	return reload(ctx, socketPath, ReloadParams{BlueGreen: true})
+}
+
+func reload(ctx context.Context, socketPath string, params any) (*ReloadResult, error) {
+	var result *ReloadResult
+	if err := internalCall(ctx, socketPath, "assern/reload", "reload", params, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, errors.New("empty reload response")
+	}
+
+	return result, nil
+}
+
+// QueryFeatures returns the feature flags of a running instance.
+func QueryFeatures(ctx context.Context, socketPath string) ([]aggregator.FeatureState, error) {
+	var features []aggregator.FeatureState
+	if err := internalCall(ctx, socketPath, "assern/features", "features", nil, &features); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// SetFeatureParams are the params of the assern/features/set command.
+type SetFeatureParams struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// SetFeature flips a feature flag on a running instance until it exits.
+func SetFeature(ctx context.Context, socketPath, name string, enabled bool) (*aggregator.FeatureState, error) {
+	var state *aggregator.FeatureState
+
+	params := SetFeatureParams{Name: name, Enabled: enabled}
+	if err := internalCall(ctx, socketPath, "assern/features/set", "set feature", params, &state); err != nil {
+		return nil, err
+	}
+
+	if state == nil {
+		return nil, errors.New("empty set feature response")
+	}
+
+	return state, nil
+}
+
+// ListParams are the params of the assern/list command.
+type ListParams struct {
+	Server string `json:"server,omitempty"`
+}
+
+// QueryList returns the tools, resources and prompts of a running instance
+// with the servers they come from, or those of one server when server is not
+// empty. Unlike QueryTools it lists the aggregated catalog, also when only
+// meta-tools are exposed over MCP.
+func QueryList(ctx context.Context, socketPath, server string) (*ListResult, error) {
+	var result *ListResult
+
+	params := ListParams{Server: server}
+	if err := internalCall(ctx, socketPath, "assern/list", "list", params, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, errors.New("empty list response")
+	}
+
+	return result, nil
+}
+
+// InspectParams are the params of the assern/inspect command.
+type InspectParams struct {
+	Tool string `json:"tool"`
+}
+
+// InspectTool returns the details of a tool exposed by a running instance.
+func InspectTool(ctx context.Context, socketPath, name string) (*aggregator.ToolDetails, error) {
+	var details *aggregator.ToolDetails
+
+	params := InspectParams{Tool: name}
+	if err := internalCall(ctx, socketPath, "assern/inspect", "inspect", params, &details); err != nil {
+		return nil, err
+	}
+
+	if details == nil {
+		return nil, errors.New("empty inspect response")
+	}
+
+	return details, nil
+}
+
+// internalCall sends one internal command and decodes its result. label
+// names the operation in error messages; params are omitted when nil.
+func internalCall(ctx context.Context, socketPath, method, label string, params, result any) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("connect to socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	req := map[string]any{
+		keyJSONRPC: jsonrpcVersion,
+		"id":       1,
+		keyMethod:  method,
+	}
+	if params != nil {
+		req["params"] = params
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("send %s request: %w", label, err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(ClientTimeout)); err != nil {
+		return fmt.Errorf("set read deadline: %w", err)
+	}
+
+	return readInternalResult(json.NewDecoder(conn), label, result)
+}
+
+// readInternalResult decodes the response to an internal command into
+// result.
+func readInternalResult(dec *json.Decoder, label string, result any) error {
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := dec.Decode(&resp); err != nil {
+		return fmt.Errorf("read %s response: %w", label, err)
+	}
+
+	if resp.Error != nil {
+		return fmt.Errorf("%s error: %s", label, resp.Error.Message)
+	}
+
+	if len(resp.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(resp.Result, result)
+}
@@ -0,0 +1,139 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+// LogsParams are the params of the assern/logs command.
+type LogsParams struct {
+	// Server keeps the instance log records about one server; with Stderr
+	// it names the server whose stderr lines are returned
+	Server string `json:"server,omitempty"`
+	// Stderr returns what Server wrote to stderr instead of the instance
+	// log
+	Stderr bool `json:"stderr,omitempty"`
+	// Since keeps what was logged at or after it; zero keeps all
+	Since time.Time `json:"since,omitzero"`
+	// Lines limits what is returned first to the last lines; 0 returns all
+	// kept lines
+	Lines int `json:"lines,omitempty"`
+	// Follow keeps the connection open, sending new lines as assern/log
+	// notifications
+	Follow bool `json:"follow,omitempty"`
+}
+
+// QueryLogs returns the last records of the log of a running instance,
+// oldest first.
+func QueryLogs(ctx context.Context, socketPath string, params LogsParams) ([]log.Record, error) {
+	var records []log.Record
+
+	params.Stderr, params.Follow = false, false
+	if err := internalCall(ctx, socketPath, "assern/logs", "logs", params, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// FollowLogs calls fn with the last records of the log of a running
+// instance, then with each new one until ctx ends or the instance stops.
+func FollowLogs(ctx context.Context, socketPath string, params LogsParams, fn func(log.Record) error) error {
+	params.Stderr, params.Follow = false, true
+
+	return followLogs(ctx, socketPath, params, fn)
+}
+
+// QueryStderr returns the last stderr lines of params.Server of a running
+// instance, oldest first.
+func QueryStderr(ctx context.Context, socketPath string, params LogsParams) ([]aggregator.LogLine, error) {
+	var lines []aggregator.LogLine
+
+	params.Stderr, params.Follow = true, false
+	if err := internalCall(ctx, socketPath, "assern/logs", "logs", params, &lines); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// FollowStderr calls fn with the last stderr lines of params.Server of a
+// running instance, then with each new line until ctx ends or the instance
+// stops.
+func FollowStderr(ctx context.Context, socketPath string, params LogsParams, fn func(aggregator.LogLine) error) error {
+	params.Stderr, params.Follow = true, true
+
+	return followLogs(ctx, socketPath, params, fn)
+}
+
+// followLogs sends an assern/logs request with params.Follow set and calls
+// fn with the backlog, then with each assern/log notification.
+func followLogs[T any](ctx context.Context, socketPath string, params LogsParams, fn func(T) error) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("connect to socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Unblock the read below when ctx ends
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	req := map[string]any{
+		keyJSONRPC: jsonrpcVersion,
+		"id":       1,
+		keyMethod:  "assern/logs",
+		"params":   params,
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("send logs request: %w", err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(ClientTimeout)); err != nil {
+		return fmt.Errorf("set read deadline: %w", err)
+	}
+
+	dec := json.NewDecoder(conn)
+
+	var backlog []T
+	if err := readInternalResult(dec, "logs", &backlog); err != nil {
+		return err
+	}
+
+	for _, entry := range backlog {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	// New lines may be far apart
+	_ = conn.SetReadDeadline(time.Time{})
+
+	for {
+		var notification struct {
+			Params T `json:"params"`
+		}
+
+		if err := dec.Decode(&notification); err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("read log line: %w", err)
+		}
+
+		if err := fn(notification.Params); err != nil {
+			return err
+		}
+	}
+}
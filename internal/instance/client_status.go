@@ -0,0 +1,56 @@
+package instance
+
+import (
+	"context"
+	"errors"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+)
+
+// QueryStatus returns the aggregator status of a running instance, including
+// per-server startup latency. This uses the internal command protocol.
+func QueryStatus(ctx context.Context, socketPath string) (*aggregator.Status, error) {
+	var status *aggregator.Status
+	if err := internalCall(ctx, socketPath, "assern/status", "status", nil, &status); err != nil {
+		return nil, err
+	}
+
+	if status == nil {
+		return nil, errors.New("empty status response")
+	}
+
+	return status, nil
+}
+
+// QueryStats returns the tool call statistics of a running instance,
+// including which callers still use deprecated tools.
+func QueryStats(ctx context.Context, socketPath string) (*aggregator.CallStats, error) {
+	var stats *aggregator.CallStats
+	if err := internalCall(ctx, socketPath, "assern/stats", "stats", nil, &stats); err != nil {
+		return nil, err
+	}
+
+	if stats == nil {
+		return nil, errors.New("empty stats response")
+	}
+
+	return stats, nil
+}
+
+// ErrorsParams are the params of the assern/errors command.
+type ErrorsParams struct {
+	Server string `json:"server,omitempty"`
+}
+
+// QueryErrors returns the recent errors of each server of a running
+// instance, or of one server when server is not empty.
+func QueryErrors(ctx context.Context, socketPath, server string) ([]aggregator.ServerErrors, error) {
+	var errs []aggregator.ServerErrors
+
+	params := ErrorsParams{Server: server}
+	if err := internalCall(ctx, socketPath, "assern/errors", "errors", params, &errs); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
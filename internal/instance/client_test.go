@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -186,6 +187,48 @@ func TestClient_QueryTools_ManyTools(t *testing.T) {
 	}
 }
 
+func TestClient_QueryTools_Paged(t *testing.T) {
+	// Use short path to avoid Unix socket path length limits on macOS
+	socketPath := filepath.Join("/tmp", fmt.Sprintf("assern-test-%d", os.Getpid()), "paged.sock")
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o755); err != nil {
+		t.Fatalf("mkdir error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(filepath.Dir(socketPath)) }()
+
+	// Pages of 7, so the last one is partial
+	mcpServer := server.NewMCPServer("paged-server", "1.0.0", server.WithPaginationLimit(7))
+	const numTools = 30
+
+	for i := range numTools {
+		mcpServer.AddTool(
+			mcp.NewTool(fmt.Sprintf("tool_%02d", i)),
+			func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			},
+		)
+	}
+
+	srv := NewServer(socketPath, mcpServer, nil, slog.New(slog.DiscardHandler))
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	result, err := QueryTools(t.Context(), socketPath)
+	if err != nil {
+		t.Fatalf("QueryTools() error = %v", err)
+	}
+
+	if len(result.Tools) != numTools {
+		t.Fatalf("Expected %d tools across pages, got %d", numTools, len(result.Tools))
+	}
+
+	if result.Tools[numTools-1].Name != "tool_29" {
+		t.Errorf("Last tool = %q, want tool_29", result.Tools[numTools-1].Name)
+	}
+}
+
 func TestClient_MultipleQueries(t *testing.T) {
 	tmpDir := t.TempDir()
 	socketPath := filepath.Join(tmpDir, "test.sock")
@@ -220,6 +263,128 @@ func TestClient_MultipleQueries(t *testing.T) {
 	}
 }
 
+func TestClient_CallTool(t *testing.T) {
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("echo"),
+		func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(req.GetString("message", "")), nil
+		},
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("fail"),
+		func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("boom"), nil
+		},
+	)
+
+	// Like the startup summary: a notification ahead of the next response
+	mcpServer.AddNotificationHandler(string(mcp.MethodNotificationInitialized), func(ctx context.Context, _ mcp.JSONRPCNotification) {
+		_ = mcpServer.SendNotificationToClient(ctx, string(mcp.MethodNotificationMessage), map[string]any{"level": "info", "data": "hello"})
+	})
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	srv := NewServer(socketPath, mcpServer, nil, logger)
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	client := NewClient(socketPath)
+
+	ctx := t.Context()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	result, err := client.CallTool(ctx, "echo", map[string]any{"message": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool(echo) error = %v", err)
+	}
+
+	if text, ok := result.Content[0].(mcp.TextContent); result.IsError || !ok || text.Text != "hi" {
+		t.Errorf("CallTool(echo) = %+v, want text \"hi\"", result)
+	}
+
+	result, err = client.CallTool(ctx, "fail", nil)
+	if err != nil {
+		t.Fatalf("CallTool(fail) error = %v", err)
+	}
+
+	if !result.IsError {
+		t.Error("CallTool(fail) IsError = false, want true")
+	}
+
+	if _, err := client.CallTool(ctx, "missing", nil); err == nil {
+		t.Error("CallTool(missing) error = nil, want an error")
+	}
+}
+
+func TestClient_RequestTrace(t *testing.T) {
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithResourceCapabilities(false, false))
+	mcpServer.AddResource(
+		mcp.NewResource("file:///readme", "readme"),
+		func(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, Text: "hello"}}, nil
+		},
+	)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	srv := NewServer(socketPath, mcpServer, nil, logger)
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	client := NewClient(socketPath)
+
+	ctx := t.Context()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	var traced []string
+
+	client.SetTrace(func(sent bool, msg []byte) {
+		traced = append(traced, fmt.Sprintf("%t %s", sent, msg))
+	})
+
+	raw, err := client.Request(ctx, "resources/read", map[string]any{"uri": "file:///readme"})
+	if err != nil {
+		t.Fatalf("Request(resources/read) error = %v", err)
+	}
+
+	if result, err := mcp.ParseReadResourceResult(&raw); err != nil || len(result.Contents) != 1 {
+		t.Fatalf("Request(resources/read) = %s, %v; want one content", raw, err)
+	}
+
+	if len(traced) != 2 || !strings.HasPrefix(traced[0], `true {"id":`) || !strings.Contains(traced[1], `"text":"hello"`) {
+		t.Errorf("traced = %q, want the request and the response", traced)
+	}
+
+	if _, err := client.Request(ctx, "prompts/get", map[string]any{"name": "missing"}); err == nil {
+		t.Error("Request(prompts/get) error = nil, want an error")
+	}
+}
+
 func TestClient_Initialize(t *testing.T) {
 	tmpDir := t.TempDir()
 	socketPath := filepath.Join(tmpDir, "test.sock")
@@ -0,0 +1,309 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+// handleErrors returns the recent errors of all servers, or of the server
+// named in the optional params {"server"}.
+func (s *Server) handleErrors(conn net.Conn, id any, params json.RawMessage) {
+	if s.aggregator == nil {
+		s.sendInternalError(conn, id, "aggregator not available")
+
+		return
+	}
+
+	var p ErrorsParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			s.sendInternalError(conn, id, "invalid params: expected server")
+
+			return
+		}
+	}
+
+	s.sendInternalResponse(conn, id, s.aggregator.RecentErrors(p.Server))
+}
+
+// handleSetFeature flips a feature flag; params are {"name", "enabled"}.
+func (s *Server) handleSetFeature(conn net.Conn, id any, params json.RawMessage) {
+	if s.aggregator == nil {
+		s.sendInternalError(conn, id, "aggregator not available")
+
+		return
+	}
+
+	var p SetFeatureParams
+	if err := json.Unmarshal(params, &p); err != nil || p.Name == "" {
+		s.sendInternalError(conn, id, "invalid params: expected name and enabled")
+
+		return
+	}
+
+	state, err := s.aggregator.SetFeature(p.Name, p.Enabled)
+	if err != nil {
+		s.sendInternalError(conn, id, err.Error())
+
+		return
+	}
+
+	s.sendInternalResponse(conn, id, state)
+}
+
+// handleInspect returns the details of the tool named in params {"tool"}.
+func (s *Server) handleReload(conn net.Conn, id any, params json.RawMessage) {
+	if s.aggregator == nil {
+		s.sendInternalError(conn, id, "aggregator not available")
+
+		return
+	}
+
+	var p ReloadParams
+	if len(params) > 0 && string(params) != "null" {
+		if err := json.Unmarshal(params, &p); err != nil {
+			s.sendInternalError(conn, id, "invalid params: "+err.Error())
+
+			return
+		}
+	}
+
+	reload := s.aggregator.Reload
+	if p.BlueGreen {
+		reload = s.aggregator.ReloadBlueGreen
+	}
+
+	result, err := reload(context.Background())
+	if err != nil {
+		s.sendInternalError(conn, id, err.Error())
+
+		return
+	}
+
+	s.sendInternalResponse(conn, id, result)
+}
+
+func (s *Server) handleInspect(conn net.Conn, id any, params json.RawMessage) {
+	if s.aggregator == nil {
+		s.sendInternalError(conn, id, "aggregator not available")
+
+		return
+	}
+
+	var p InspectParams
+	if err := json.Unmarshal(params, &p); err != nil || p.Tool == "" {
+		s.sendInternalError(conn, id, "invalid params: expected tool")
+
+		return
+	}
+
+	details, err := s.aggregator.InspectTool(p.Tool)
+	if err != nil {
+		s.sendInternalError(conn, id, err.Error())
+
+		return
+	}
+
+	s.sendInternalResponse(conn, id, details)
+}
+
+// handleList returns the tools, resources and prompts of all servers, or of
+// the server named in the optional params {"server"}.
+func (s *Server) handleList(conn net.Conn, id any, params json.RawMessage) {
+	if s.aggregator == nil {
+		s.sendInternalError(conn, id, "aggregator not available")
+
+		return
+	}
+
+	var p ListParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			s.sendInternalError(conn, id, "invalid params: expected server")
+
+			return
+		}
+	}
+
+	result, err := NewListResult(s.aggregator, p.Server)
+	if err != nil {
+		s.sendInternalError(conn, id, err.Error())
+
+		return
+	}
+
+	s.sendInternalResponse(conn, id, result)
+}
+
+// handleLogs returns the last records of the instance log, or with
+// params.Stderr the last stderr lines of params.Server (see LogsParams).
+// With params.Follow, the connection stays open and each new record or line
+// is sent as an assern/log notification until the client disconnects or the
+// instance stops.
+func (s *Server) handleLogs(conn net.Conn, id any, params json.RawMessage) {
+	var p LogsParams
+	if err := json.Unmarshal(params, &p); err != nil || (p.Stderr && p.Server == "") {
+		s.sendInternalError(conn, id, "invalid params: expected server")
+
+		return
+	}
+
+	if p.Stderr {
+		s.handleStderr(conn, id, p)
+
+		return
+	}
+
+	if s.logRing == nil {
+		s.sendInternalError(conn, id, "log not available")
+
+		return
+	}
+
+	filter := log.Filter{Server: p.Server, Since: p.Since, Limit: p.Lines}
+
+	if !p.Follow {
+		s.sendInternalResponse(conn, id, s.logRing.Records(filter))
+
+		return
+	}
+
+	records, next, stop := s.logRing.Follow(filter)
+	defer stop()
+
+	streamLogs(s, conn, id, records, next)
+}
+
+// handleStderr answers assern/logs for the stderr lines of a server.
+func (s *Server) handleStderr(conn net.Conn, id any, p LogsParams) {
+	if s.aggregator == nil {
+		s.sendInternalError(conn, id, "aggregator not available")
+
+		return
+	}
+
+	if !p.Follow {
+		lines, err := s.aggregator.ServerLogs(p.Server, 0)
+		if err != nil {
+			s.sendInternalError(conn, id, err.Error())
+
+			return
+		}
+
+		s.sendInternalResponse(conn, id, recentLines(lines, p.Since, p.Lines))
+
+		return
+	}
+
+	lines, next, stop, err := s.aggregator.FollowServerLogs(p.Server, 0)
+	if err != nil {
+		s.sendInternalError(conn, id, err.Error())
+
+		return
+	}
+	defer stop()
+
+	streamLogs(s, conn, id, recentLines(lines, p.Since, p.Lines), next)
+}
+
+// recentLines returns the lines logged at or after since (all when zero),
+// limited to the last n when n > 0.
+func recentLines(lines []aggregator.LogLine, since time.Time, n int) []aggregator.LogLine {
+	if !since.IsZero() {
+		lines = slices.DeleteFunc(lines, func(l aggregator.LogLine) bool { return l.Time.Before(since) })
+	}
+
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines
+}
+
+// streamLogs sends backlog as the response to id, then each entry received
+// from next as an assern/log notification until the client hangs up or the
+// instance stops.
+func streamLogs[T any](s *Server, conn net.Conn, id any, backlog []T, next <-chan T) {
+	s.sendInternalResponse(conn, id, backlog)
+
+	// The client sends nothing more; a read returns once it hangs up
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	for {
+		select {
+		case <-gone:
+			return
+		case <-s.done:
+			return
+		case entry := <-next:
+			notification := map[string]any{
+				keyJSONRPC: jsonrpcVersion,
+				keyMethod:  "assern/log",
+				"params":   entry,
+			}
+
+			if err := s.writeJSONResponse(conn, notification); err != nil {
+				s.logger.Debug("failed to write log line", "error", err)
+
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) sendInternalResponse(conn net.Conn, id any, result any) {
+	resp := map[string]any{
+		keyJSONRPC: jsonrpcVersion,
+		"id":       id,
+		"result":   result,
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		s.logger.Debug("failed to marshal response", "error", err)
+
+		return
+	}
+
+	data = append(data, '\n')
+
+	if _, err := conn.Write(data); err != nil {
+		s.logger.Debug("failed to write response", "error", err)
+	}
+}
+
+func (s *Server) sendInternalError(conn net.Conn, id any, message string) {
+	resp := map[string]any{
+		keyJSONRPC: jsonrpcVersion,
+		"id":       id,
+		"error": map[string]any{
+			"code":    -32603, // Internal error
+			"message": message,
+		},
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		s.logger.Debug("failed to marshal error response", "error", err)
+
+		return
+	}
+
+	data = append(data, '\n')
+
+	if _, err := conn.Write(data); err != nil {
+		s.logger.Debug("failed to write error response", "error", err)
+	}
+}
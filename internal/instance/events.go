@@ -0,0 +1,12 @@
+package instance
+
+import "github.com/valksor/go-assern/internal/events"
+
+// publish forwards an event to the aggregator's event bus, if any.
+func (s *Server) publish(typ events.Type, message string, data map[string]any) {
+	if s.aggregator == nil {
+		return
+	}
+
+	s.aggregator.Events().Publish(events.Event{Type: typ, Message: message, Data: data})
+}
@@ -0,0 +1,101 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// codeSessionLimit is the JSON-RPC error code returned when a connection is
+// refused because a session limit has been reached. It sits in the range the
+// JSON-RPC spec reserves for implementation-defined server errors.
+const codeSessionLimit = -32001
+
+// unknownUID is used when the peer's credentials cannot be determined.
+// Per-UID limits are not applied to such connections; the global cap still is.
+const unknownUID = -1
+
+// Session limit errors.
+var (
+	ErrTooManySessions    = errors.New("too many concurrent sessions")
+	ErrTooManyUIDSessions = errors.New("too many concurrent sessions for peer uid")
+)
+
+// Limits bounds the number of concurrent MCP sessions the socket server
+// accepts. A zero value for either field disables that limit.
+type Limits struct {
+	MaxSessions int
+	MaxPerUID   int
+}
+
+// LimitsFromConfig resolves the effective socket limits from configuration.
+// A nil config yields the defaults.
+func LimitsFromConfig(cfg *config.SocketConfig) Limits {
+	return Limits{
+		MaxSessions: cfg.EffectiveMaxSessions(),
+		MaxPerUID:   cfg.EffectiveMaxPerUID(),
+	}
+}
+
+// sessionLimiter tracks active sessions globally and per peer UID.
+type sessionLimiter struct {
+	mu     sync.Mutex
+	limits Limits
+	total  int
+	byUID  map[int]int
+}
+
+func newSessionLimiter() *sessionLimiter {
+	return &sessionLimiter{byUID: make(map[int]int)}
+}
+
+// setLimits replaces the limits. Sessions already admitted are unaffected.
+func (l *sessionLimiter) setLimits(limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits = limits
+}
+
+// acquire reserves a session slot for uid. Each successful acquire must be
+// paired with a release.
+func (l *sessionLimiter) acquire(uid int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limits.MaxSessions > 0 && l.total >= l.limits.MaxSessions {
+		return fmt.Errorf("%w (limit %d)", ErrTooManySessions, l.limits.MaxSessions)
+	}
+
+	if uid != unknownUID && l.limits.MaxPerUID > 0 && l.byUID[uid] >= l.limits.MaxPerUID {
+		return fmt.Errorf("%w %d (limit %d)", ErrTooManyUIDSessions, uid, l.limits.MaxPerUID)
+	}
+
+	l.total++
+	l.byUID[uid]++
+
+	return nil
+}
+
+// release frees a slot previously reserved by acquire.
+func (l *sessionLimiter) release(uid int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total--
+
+	l.byUID[uid]--
+	if l.byUID[uid] <= 0 {
+		delete(l.byUID, uid)
+	}
+}
+
+// active returns the number of admitted sessions.
+func (l *sessionLimiter) active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.total
+}
@@ -0,0 +1,192 @@
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestLimitsFromConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *config.SocketConfig
+		want Limits
+	}{
+		{
+			name: "nil uses defaults",
+			cfg:  nil,
+			want: Limits{MaxSessions: config.DefaultSocketMaxSessions, MaxPerUID: config.DefaultSocketMaxPerUID},
+		},
+		{
+			name: "explicit",
+			cfg:  &config.SocketConfig{MaxSessions: 5, MaxConnectionsPerUID: 2},
+			want: Limits{MaxSessions: 5, MaxPerUID: 2},
+		},
+		{
+			name: "negative disables",
+			cfg:  &config.SocketConfig{MaxSessions: -1, MaxConnectionsPerUID: -1},
+			want: Limits{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := LimitsFromConfig(tt.cfg); got != tt.want {
+				t.Errorf("LimitsFromConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := newSessionLimiter()
+	l.setLimits(Limits{MaxSessions: 3, MaxPerUID: 2})
+
+	if err := l.acquire(1000); err != nil {
+		t.Fatalf("acquire #1 error = %v", err)
+	}
+
+	if err := l.acquire(1000); err != nil {
+		t.Fatalf("acquire #2 error = %v", err)
+	}
+
+	if err := l.acquire(1000); !errors.Is(err, ErrTooManyUIDSessions) {
+		t.Errorf("acquire over per-uid limit error = %v, want ErrTooManyUIDSessions", err)
+	}
+
+	// Unknown peers are only subject to the global cap.
+	if err := l.acquire(unknownUID); err != nil {
+		t.Fatalf("acquire unknown uid error = %v", err)
+	}
+
+	if err := l.acquire(1001); !errors.Is(err, ErrTooManySessions) {
+		t.Errorf("acquire over global limit error = %v, want ErrTooManySessions", err)
+	}
+
+	l.release(1000)
+
+	if err := l.acquire(1001); err != nil {
+		t.Errorf("acquire after release error = %v", err)
+	}
+
+	if got := l.active(); got != 3 {
+		t.Errorf("active() = %d, want 3", got)
+	}
+}
+
+func TestSessionLimiter_Unlimited(t *testing.T) {
+	t.Parallel()
+
+	l := newSessionLimiter()
+
+	for i := range 100 {
+		if err := l.acquire(1000); err != nil {
+			t.Fatalf("acquire #%d error = %v", i, err)
+		}
+	}
+}
+
+func TestServer_RejectsSessionsOverLimit(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	srv := NewServer(socketPath, mcpServer, nil, logger)
+	srv.SetLimits(Limits{MaxSessions: 1})
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	var dialer net.Dialer
+
+	// The first connection occupies the only session slot.
+	first, err := dialer.DialContext(t.Context(), "unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = first.Close() }()
+
+	deadline := time.Now().Add(time.Second)
+	for srv.limiter.active() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("first session was never admitted")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Internal commands must keep working while the cap is reached.
+	ping, err := dialer.DialContext(t.Context(), "unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = ping.Close() }()
+
+	if _, err := ping.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"assern/ping"}` + "\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	_ = ping.SetReadDeadline(time.Now().Add(time.Second))
+
+	pong, err := bufio.NewReader(ping).ReadBytes('\n')
+	if err != nil || !strings.Contains(string(pong), `"result"`) {
+		t.Fatalf("ping while at session limit: response = %s, error = %v", pong, err)
+	}
+
+	second, err := dialer.DialContext(t.Context(), "unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = second.Close() }()
+
+	initReq := `{"jsonrpc":"2.0","id":7,"method":"initialize","params":{}}` + "\n"
+	if _, err := second.Write([]byte(initReq)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	_ = second.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	line, err := bufio.NewReader(second).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	var resp struct {
+		ID    int `json:"id"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v, response = %s", err, line)
+	}
+
+	if resp.ID != 7 {
+		t.Errorf("response id = %d, want 7", resp.ID)
+	}
+
+	if resp.Error == nil || resp.Error.Code != codeSessionLimit {
+		t.Fatalf("expected session limit error, got %s", line)
+	}
+}
@@ -0,0 +1,77 @@
+package instance
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+)
+
+// NewListResult lists the tools, resources and prompts of agg with their
+// servers, sorted by name, or only those of server when it is not empty.
+// Tokens are estimated for the listed tools only.
+func NewListResult(agg *aggregator.Aggregator, server string) (*ListResult, error) {
+	result := &ListResult{Tools: []ToolInfo{}}
+
+	for _, entry := range agg.ListTools() {
+		if server != "" && entry.ServerName != server {
+			continue
+		}
+
+		// Marshalling applies RawInputSchema when set
+		data, err := json.Marshal(entry.Tool)
+		if err != nil {
+			return nil, fmt.Errorf("encoding tool %s: %w", entry.PrefixedName, err)
+		}
+
+		var tool ToolInfo
+		if err := json.Unmarshal(data, &tool); err != nil {
+			return nil, fmt.Errorf("decoding tool %s: %w", entry.PrefixedName, err)
+		}
+
+		tool.Name = entry.PrefixedName
+		tool.Server = entry.ServerName
+		result.Tools = append(result.Tools, tool)
+	}
+
+	for _, entry := range agg.ListResources() {
+		if server != "" && entry.ServerName != server {
+			continue
+		}
+
+		result.Resources = append(result.Resources, ResourceInfo{
+			URI:         entry.PrefixedURI,
+			Server:      entry.ServerName,
+			Name:        entry.Resource.Name,
+			Description: entry.Resource.Description,
+			MIMEType:    entry.Resource.MIMEType,
+		})
+	}
+
+	for _, entry := range agg.ListPrompts() {
+		if server != "" && entry.ServerName != server {
+			continue
+		}
+
+		result.Prompts = append(result.Prompts, PromptInfo{
+			Name:        entry.PrefixedName,
+			Server:      entry.ServerName,
+			Description: entry.Prompt.Description,
+			Arguments:   entry.Prompt.Arguments,
+		})
+	}
+
+	slices.SortFunc(result.Tools, func(a, b ToolInfo) int { return cmp.Compare(a.Name, b.Name) })
+	slices.SortFunc(result.Resources, func(a, b ResourceInfo) int { return cmp.Compare(a.URI, b.URI) })
+	slices.SortFunc(result.Prompts, func(a, b PromptInfo) int { return cmp.Compare(a.Name, b.Name) })
+
+	result.TokensByServer, result.TotalTokens = agg.TokenStats()
+	if server != "" {
+		result.TotalTokens = result.TokensByServer[server]
+		result.TokensByServer = map[string]int{server: result.TotalTokens}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package instance
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/log"
+)
+
+func TestLogsCommand(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+
+	cfg := config.NewConfig()
+	cfg.Servers["github"] = &config.ServerConfig{Command: "github-mcp"}
+
+	agg, err := aggregator.New(aggregator.Options{Config: cfg, Logger: slog.New(slog.DiscardHandler)})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, slog.New(slog.DiscardHandler))
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	lines, err := QueryStderr(ctx, socketPath, LogsParams{Server: "github", Lines: 10})
+	if err != nil || len(lines) != 0 {
+		t.Errorf("QueryStderr(github) = %v, %v; want no lines", lines, err)
+	}
+
+	if _, err := QueryStderr(ctx, socketPath, LogsParams{Server: "missing"}); err == nil || !strings.Contains(err.Error(), "server not found") {
+		t.Errorf("QueryStderr(missing) error = %v, want server not found", err)
+	}
+
+	// Without a ring only stderr is served
+	if _, err := QueryLogs(ctx, socketPath, LogsParams{}); err == nil || !strings.Contains(err.Error(), "log not available") {
+		t.Errorf("QueryLogs() without a ring error = %v, want log not available", err)
+	}
+
+	// Following returns once the context ends
+	followCtx, stop := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer stop()
+
+	err = FollowStderr(followCtx, socketPath, LogsParams{Server: "github", Lines: 10}, func(line aggregator.LogLine) error {
+		t.Errorf("unexpected line %v", line)
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("FollowStderr() error = %v, want nil after the context ended", err)
+	}
+
+	if err := FollowStderr(ctx, socketPath, LogsParams{Server: "missing"}, nil); err == nil {
+		t.Error("FollowStderr(missing) succeeded")
+	}
+}
+
+func TestLogsCommandRing(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+
+	ring := log.NewRing(10)
+	logger := slog.New(ring.Handler(slog.LevelInfo))
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), nil, slog.New(slog.DiscardHandler))
+	sockServer.SetLogRing(ring)
+
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	logger.Info("server started", "server", "github")
+	logger.Info("server started", "server", "jira")
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	records, err := QueryLogs(ctx, socketPath, LogsParams{Server: "github"})
+	if err != nil || len(records) != 1 || records[0].Server() != "github" {
+		t.Fatalf("QueryLogs(server github) = %v, %v; want the github record", records, err)
+	}
+
+	if records, err := QueryLogs(ctx, socketPath, LogsParams{Since: time.Now().Add(time.Hour)}); err != nil || len(records) != 0 {
+		t.Errorf("QueryLogs(since the future) = %v, %v; want none", records, err)
+	}
+
+	// Following sends the backlog, then new records about the server
+	got := make(chan log.Record, 4)
+	followCtx, stop := context.WithCancel(ctx)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- FollowLogs(followCtx, socketPath, LogsParams{Server: "jira"}, func(r log.Record) error {
+			got <- r
+
+			return nil
+		})
+	}()
+
+	if r := <-got; r.Server() != "jira" {
+		t.Errorf("first followed record = %+v, want the jira backlog", r)
+	}
+
+	// The follower registers before the backlog is sent, so this is seen
+	logger.Info("ignored", "server", "github")
+	logger.Warn("tool call failed", "server", "jira")
+
+	select {
+	case r := <-got:
+		if r.Message != "tool call failed" || r.Level != "WARN" {
+			t.Errorf("followed record = %+v, want the jira warning", r)
+		}
+	case <-ctx.Done():
+		t.Fatal("no new record followed")
+	}
+
+	stop()
+
+	if err := <-done; err != nil {
+		t.Errorf("FollowLogs() error = %v, want nil after the context ended", err)
+	}
+}
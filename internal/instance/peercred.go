@@ -0,0 +1,68 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/events"
+)
+
+// codePeerNotAllowed is the JSON-RPC error code sent to a peer whose UID is
+// not on the allowlist.
+const codePeerNotAllowed = -32002
+
+// Peer credential errors.
+var (
+	errNotUnixConn         = errors.New("not a unix socket connection")
+	errPeerCredUnsupported = errors.New("peer credentials not supported on this platform")
+)
+
+// AllowedUIDsFromConfig returns the UIDs permitted to connect: the current
+// user plus any configured in settings.socket.allowed_uids.
+func AllowedUIDsFromConfig(cfg *config.SocketConfig) []int {
+	uids := []int{os.Getuid()}
+
+	if cfg != nil {
+		for _, uid := range cfg.AllowedUIDs {
+			if !slices.Contains(uids, uid) {
+				uids = append(uids, uid)
+			}
+		}
+	}
+
+	return uids
+}
+
+// SetAllowedUIDs replaces the list of peer UIDs allowed to connect. By default
+// only the UID running the server is allowed. Must be called before Start.
+func (s *Server) SetAllowedUIDs(uids []int) {
+	s.allowed = slices.Clone(uids)
+}
+
+// authorizePeer checks the connecting process's UID against the allowlist.
+// It returns the peer UID (unknownUID when it cannot be determined) and
+// whether the connection may proceed. On platforms without peer credential
+// support the check is skipped and the socket's file permissions are the
+// only protection.
+func (s *Server) authorizePeer(conn net.Conn) (int, bool) {
+	uid, err := peerUID(conn)
+	if err != nil {
+		s.logger.Debug("peer credentials unavailable, relying on socket permissions", "error", err)
+
+		return unknownUID, true
+	}
+
+	if slices.Contains(s.allowed, uid) {
+		return uid, true
+	}
+
+	s.logger.Warn("rejecting socket connection from disallowed uid", "uid", uid)
+	s.publish(events.PolicyBlocked, fmt.Sprintf("socket connection from disallowed uid %d", uid), map[string]any{"uid": uid})
+	s.writeErrorResponse(conn, nil, codePeerNotAllowed, "peer uid not allowed")
+
+	return uid, false
+}
@@ -0,0 +1,41 @@
+//go:build linux
+
+package instance
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerUID returns the UID of the process on the other end of a Unix socket
+// connection using SO_PEERCRED.
+func peerUID(conn net.Conn) (int, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return unknownUID, errNotUnixConn
+	}
+
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return unknownUID, fmt.Errorf("syscall conn: %w", err)
+	}
+
+	var (
+		cred    *unix.Ucred
+		credErr error
+	)
+
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return unknownUID, fmt.Errorf("control: %w", err)
+	}
+
+	if credErr != nil {
+		return unknownUID, fmt.Errorf("SO_PEERCRED: %w", credErr)
+	}
+
+	return int(cred.Uid), nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package instance
+
+import "net"
+
+// peerUID is not supported on this platform.
+func peerUID(_ net.Conn) (int, error) {
+	return unknownUID, errPeerCredUnsupported
+}
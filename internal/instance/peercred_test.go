@@ -0,0 +1,145 @@
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestAllowedUIDsFromConfig(t *testing.T) {
+	t.Parallel()
+
+	owner := os.Getuid()
+
+	tests := []struct {
+		name string
+		cfg  *config.SocketConfig
+		want []int
+	}{
+		{name: "nil allows owner only", cfg: nil, want: []int{owner}},
+		{name: "empty allows owner only", cfg: &config.SocketConfig{}, want: []int{owner}},
+		{
+			name: "extra uids appended",
+			cfg:  &config.SocketConfig{AllowedUIDs: []int{owner + 1, owner + 2}},
+			want: []int{owner, owner + 1, owner + 2},
+		},
+		{
+			name: "owner not duplicated",
+			cfg:  &config.SocketConfig{AllowedUIDs: []int{owner}},
+			want: []int{owner},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := AllowedUIDsFromConfig(tt.cfg); !slices.Equal(got, tt.want) {
+				t.Errorf("AllowedUIDsFromConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeerUID(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("peer credentials not supported on " + runtime.GOOS)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "peer.sock")
+
+	var lc net.ListenConfig
+	listener, err := lc.Listen(t.Context(), "unix", socketPath)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	var dialer net.Dialer
+	client, err := dialer.DialContext(t.Context(), "unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	uid, err := peerUID(conn)
+	if err != nil {
+		t.Fatalf("peerUID() error = %v", err)
+	}
+
+	if uid != os.Getuid() {
+		t.Errorf("peerUID() = %d, want %d", uid, os.Getuid())
+	}
+}
+
+func TestServer_RejectsDisallowedPeer(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("peer credentials not supported on " + runtime.GOOS)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	srv := NewServer(socketPath, mcpServer, nil, logger)
+	srv.SetAllowedUIDs([]int{os.Getuid() + 1})
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(t.Context(), "unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"assern/ping"}` + "\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	var resp struct {
+		Result *Info `json:"result"`
+		Error  *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v, response = %s", err, line)
+	}
+
+	if resp.Result != nil {
+		t.Error("disallowed peer received ping result")
+	}
+
+	if resp.Error == nil || resp.Error.Code != codePeerNotAllowed {
+		t.Errorf("expected peer-not-allowed error, got %s", line)
+	}
+}
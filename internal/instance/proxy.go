@@ -1,12 +1,20 @@
 package instance
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net"
 	"os"
 	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/aggregator"
 )
 
 const (
@@ -62,7 +70,8 @@ func (p *Proxy) Close() error {
 }
 
 // ServeStdio bridges stdin/stdout to the socket connection.
-// This makes the proxy transparent to the calling LLM.
+// This makes the proxy transparent to the calling LLM: the only change it
+// makes is giving each tools/call a correlation ID as the first hop.
 func (p *Proxy) ServeStdio(ctx context.Context) error {
 	if p.conn == nil {
 		if err := p.Connect(ctx); err != nil {
@@ -77,14 +86,7 @@ func (p *Proxy) ServeStdio(ctx context.Context) error {
 
 	// stdin -> socket
 	wg.Go(func() {
-		bufPtr, ok := bufferPool.Get().(*[]byte)
-		if !ok {
-			errCh <- io.ErrShortBuffer
-
-			return
-		}
-		defer bufferPool.Put(bufPtr)
-		_, err := io.CopyBuffer(p.conn, os.Stdin, *bufPtr)
+		err := p.forwardRequests(p.conn, os.Stdin)
 		if err != nil && ctx.Err() == nil {
 			errCh <- err
 		}
@@ -118,3 +120,101 @@ func (p *Proxy) ServeStdio(ctx context.Context) error {
 
 	return nil
 }
+
+// forwardRequests copies the newline-delimited JSON-RPC messages of src to
+// dst, adding a correlation ID to each tools/call (see correlateMessage).
+func (p *Proxy) forwardRequests(dst io.Writer, src io.Reader) error {
+	reader := bufio.NewReaderSize(src, proxyBufferSize)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			out, tool, id := correlateMessage(line)
+			if id != "" {
+				p.logger.Debug("forwarding tool call", "tool", tool, "correlation_id", id)
+			}
+
+			if _, werr := dst.Write(out); werr != nil {
+				return werr
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// correlateMessage returns line with a new correlation ID in the _meta of
+// a tools/call request that has none, with the tool name and the call's
+// correlation ID. Any other message is returned unchanged, with "" IDs.
+func correlateMessage(line []byte) (out []byte, tool, id string) {
+	if !bytes.Contains(line, []byte(mcp.MethodToolsCall)) {
+		return line, "", ""
+	}
+
+	var (
+		msg    map[string]json.RawMessage
+		method string
+		params map[string]json.RawMessage
+		meta   map[string]any
+	)
+
+	if json.Unmarshal(line, &msg) != nil ||
+		json.Unmarshal(msg["method"], &method) != nil || method != string(mcp.MethodToolsCall) ||
+		json.Unmarshal(msg["params"], &params) != nil || params == nil {
+		return line, "", ""
+	}
+
+	_ = json.Unmarshal(params["name"], &tool)
+
+	if raw, ok := params["_meta"]; ok && json.Unmarshal(raw, &meta) != nil {
+		return line, "", ""
+	}
+
+	// A client or an earlier hop may have set one already
+	if prev, ok := meta[aggregator.CorrelationMeta].(string); ok && prev != "" {
+		return line, tool, prev
+	}
+
+	if meta == nil {
+		meta = make(map[string]any, 1)
+	}
+
+	id = aggregator.NewCorrelationID()
+	meta[aggregator.CorrelationMeta] = id
+
+	var err error
+	if params["_meta"], err = encodeJSON(meta); err != nil {
+		return line, "", ""
+	}
+
+	if msg["params"], err = encodeJSON(params); err != nil {
+		return line, "", ""
+	}
+
+	if out, err = encodeJSON(msg); err != nil {
+		return line, "", ""
+	}
+
+	return out, tool, id
+}
+
+// encodeJSON encodes v as one line ending in a newline, leaving HTML
+// characters unescaped so the rest of a message is forwarded as sent.
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
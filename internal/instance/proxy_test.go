@@ -1,14 +1,19 @@
 package instance
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/aggregator"
 )
 
 func TestNewProxy(t *testing.T) {
@@ -179,3 +184,106 @@ func TestProxy_MultipleConnections(t *testing.T) {
 		}
 	}
 }
+
+func TestCorrelateMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		line     string
+		wantTool string
+		wantID   string // "new" expects a generated ID; "" an unchanged line
+	}{
+		{
+			name:     "tools/call gets an ID",
+			line:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"github_search","arguments":{"q":"<a&b>"}}}` + "\n",
+			wantTool: "github_search",
+			wantID:   "new",
+		},
+		{
+			name:     "existing ID is kept",
+			line:     `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"x","_meta":{"assern/correlation_id":"abc"}}}` + "\n",
+			wantTool: "x",
+			wantID:   "abc",
+		},
+		{
+			name: "other methods pass through",
+			line: `{"jsonrpc":"2.0","id":3,"method":"tools/list"}` + "\n",
+		},
+		{
+			name: "responses pass through",
+			line: `{"jsonrpc":"2.0","id":4,"result":{"text":"tools/call"}}` + "\n",
+		},
+		{
+			name: "invalid JSON passes through",
+			line: `{"method":"tools/call"` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, tool, id := correlateMessage([]byte(tt.line))
+
+			if tool != tt.wantTool {
+				t.Errorf("tool = %q, want %q", tool, tt.wantTool)
+			}
+
+			if tt.wantID != "new" {
+				if id != tt.wantID || string(out) != tt.line {
+					t.Errorf("correlateMessage() = %q, %q; want %q unchanged with ID %q", out, id, tt.line, tt.wantID)
+				}
+
+				return
+			}
+
+			var msg struct {
+				Params struct {
+					Arguments map[string]any `json:"arguments"`
+					Meta      map[string]any `json:"_meta"`
+				} `json:"params"`
+			}
+
+			if err := json.Unmarshal(out, &msg); err != nil {
+				t.Fatalf("forwarded message is not JSON: %v", err)
+			}
+
+			if id == "" || msg.Params.Meta[aggregator.CorrelationMeta] != id {
+				t.Errorf("_meta = %v, want correlation ID %q", msg.Params.Meta, id)
+			}
+
+			if msg.Params.Arguments["q"] != "<a&b>" || !bytes.HasSuffix(out, []byte("\n")) || bytes.Contains(out, []byte(`\u003c`)) {
+				t.Errorf("forwarded message = %q, want the rest unchanged and newline-terminated", out)
+			}
+		})
+	}
+}
+
+func TestProxy_ForwardRequests(t *testing.T) {
+	t.Parallel()
+
+	proxy := NewProxy("", slog.New(slog.DiscardHandler))
+
+	in := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"x"}}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`
+
+	var out bytes.Buffer
+	if err := proxy.forwardRequests(&out, strings.NewReader(in)); err != nil {
+		t.Fatalf("forwardRequests() error = %v", err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("forwarded %d lines, want 3: %q", len(lines), out.String())
+	}
+
+	if !strings.Contains(lines[1], aggregator.CorrelationMeta) {
+		t.Errorf("tools/call forwarded as %q, want a correlation ID", lines[1])
+	}
+
+	if lines[0]+"\n" != strings.SplitAfter(in, "\n")[0] || lines[2] != `{"jsonrpc":"2.0","method":"notifications/initialized"}` {
+		t.Errorf("other messages changed: %q", out.String())
+	}
+}
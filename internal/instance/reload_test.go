@@ -7,13 +7,16 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/valksor/go-assern/internal/aggregator"
 	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
 )
 
 func TestReload_Success(t *testing.T) {
@@ -84,6 +87,16 @@ func TestReload_Success(t *testing.T) {
 	if result.Removed != 0 {
 		t.Errorf("expected 0 removed, got %d", result.Removed)
 	}
+
+	// The blue-green mode goes through the same command
+	result, err = ReloadBlueGreen(ctx, socketPath)
+	if err != nil {
+		t.Fatalf("blue-green reload failed: %v", err)
+	}
+
+	if result.Added != 0 || result.Removed != 0 || result.Replaced != 0 {
+		t.Errorf("expected no changes, got %+v", result)
+	}
 }
 
 func TestReload_NoSocket(t *testing.T) {
@@ -209,3 +222,252 @@ func TestReloadResult_Fields(t *testing.T) {
 		t.Errorf("expected 2 errors, got %d", len(result.Errors))
 	}
 }
+
+func TestQueryStatus(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	logger := slog.New(slog.DiscardHandler)
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: logger})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, logger)
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	status, err := QueryStatus(ctx, socketPath)
+	if err != nil {
+		t.Fatalf("QueryStatus() error = %v", err)
+	}
+
+	if status.StartedAt.IsZero() || status.Uptime == "" {
+		t.Errorf("status = %+v, want start time and uptime", status)
+	}
+}
+
+func TestQueryStats(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	logger := slog.New(slog.DiscardHandler)
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: logger})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, logger)
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	stats, err := QueryStats(ctx, socketPath)
+	if err != nil {
+		t.Fatalf("QueryStats() error = %v", err)
+	}
+
+	if len(stats.Servers) != 0 || len(stats.Deprecated) != 0 {
+		t.Errorf("stats = %+v, want no activity", stats)
+	}
+}
+
+func TestSetFeature(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	logger := slog.New(slog.DiscardHandler)
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: logger})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, logger)
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	state, err := SetFeature(ctx, socketPath, config.FeatureTOONDefault, true)
+	if err != nil {
+		t.Fatalf("SetFeature() error = %v", err)
+	}
+
+	if !state.Enabled || state.Source != aggregator.FeatureSourceRuntime {
+		t.Errorf("state = %+v, want enabled at runtime", state)
+	}
+
+	features, err := QueryFeatures(ctx, socketPath)
+	if err != nil {
+		t.Fatalf("QueryFeatures() error = %v", err)
+	}
+
+	if len(features) != len(config.Features) {
+		t.Errorf("QueryFeatures() = %d flags, want %d", len(features), len(config.Features))
+	}
+
+	if _, err := SetFeature(ctx, socketPath, "warp_drive", true); err == nil {
+		t.Error("SetFeature() with unknown feature succeeded, want error")
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	logger := slog.New(slog.DiscardHandler)
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: logger})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, logger)
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	for _, server := range []string{"", "github"} {
+		errs, err := QueryErrors(ctx, socketPath, server)
+		if err != nil {
+			t.Fatalf("QueryErrors(%q) error = %v", server, err)
+		}
+
+		if len(errs) != 0 {
+			t.Errorf("QueryErrors(%q) = %+v, want none", server, errs)
+		}
+	}
+}
+
+func TestInspectTool(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	logger := slog.New(slog.DiscardHandler)
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: logger})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search", mcp.WithString("query"))})
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, logger)
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	details, err := InspectTool(ctx, socketPath, "github_search")
+	if err != nil {
+		t.Fatalf("InspectTool() error = %v", err)
+	}
+
+	if details.OriginalName != "search" || details.Server != "github" || !strings.Contains(string(details.InputSchema), `"query"`) {
+		t.Errorf("InspectTool() = %+v", details)
+	}
+
+	if _, err := InspectTool(ctx, socketPath, "github_missing"); err == nil || !strings.Contains(err.Error(), "github_missing") {
+		t.Errorf("InspectTool() for a missing tool error = %v", err)
+	}
+}
+
+func TestQueryList(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	logger := slog.New(slog.DiscardHandler)
+
+	agg, err := aggregator.New(aggregator.Options{Config: config.NewConfig(), Logger: logger})
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+
+	github := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search", mcp.WithString("query"))})
+	github.Resources = []mcp.Resource{mcp.NewResource("file:///README.md", "readme", mcp.WithMIMEType("text/markdown"))}
+	github.Prompts = []mcp.Prompt{mcp.NewPrompt("review", mcp.WithArgument("pr", mcp.RequiredArgument()))}
+
+	fs := testutil.NewMockServer("fs", []mcp.Tool{mcp.NewTool("read"), mcp.NewTool("write")})
+
+	for _, mock := range []*testutil.MockServer{github, fs} {
+		if err := mock.Start(t.Context()); err != nil {
+			t.Fatalf("mock.Start: %v", err)
+		}
+
+		if err := agg.AddServer(t.Context(), mock); err != nil {
+			t.Fatalf("AddServer: %v", err)
+		}
+	}
+
+	sockServer := NewServer(socketPath, server.NewMCPServer("test", "1.0.0"), agg, logger)
+	if err := sockServer.Start(); err != nil {
+		t.Fatalf("failed to start socket server: %v", err)
+	}
+	defer func() { _ = sockServer.Stop() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	all, err := QueryList(ctx, socketPath, "")
+	if err != nil {
+		t.Fatalf("QueryList() error = %v", err)
+	}
+
+	if len(all.Tools) != 3 || all.Tools[0].Name != "fs_read" || all.Tools[0].Server != "fs" {
+		t.Errorf("QueryList() tools = %+v, want the three tools sorted with their servers", all.Tools)
+	}
+
+	if len(all.TokensByServer) != 2 || all.TotalTokens != all.TokensByServer["fs"]+all.TokensByServer["github"] {
+		t.Errorf("QueryList() tokens = %v, total %d", all.TokensByServer, all.TotalTokens)
+	}
+
+	one, err := QueryList(ctx, socketPath, "github")
+	if err != nil {
+		t.Fatalf("QueryList(github) error = %v", err)
+	}
+
+	if len(one.Tools) != 1 || !strings.Contains(string(one.Tools[0].InputSchema), `"query"`) {
+		t.Errorf("QueryList(github) tools = %+v, want github_search with its schema", one.Tools)
+	}
+
+	if len(one.Resources) != 1 || one.Resources[0].URI != "assern://github/file:///README.md" || one.Resources[0].MIMEType != "text/markdown" {
+		t.Errorf("QueryList(github) resources = %+v", one.Resources)
+	}
+
+	if len(one.Prompts) != 1 || one.Prompts[0].Name != "github_review" || len(one.Prompts[0].Arguments) != 1 {
+		t.Errorf("QueryList(github) prompts = %+v", one.Prompts)
+	}
+
+	if one.TotalTokens != all.TokensByServer["github"] || len(one.TokensByServer) != 1 {
+		t.Errorf("QueryList(github) tokens = %v, total %d, want github's only", one.TokensByServer, one.TotalTokens)
+	}
+}
@@ -31,10 +31,15 @@ type socketSession struct {
 	sessionTools map[string]server.ServerTool
 }
 
-// newSocketSession creates a new session with a unique ID for a socket connection.
-func newSocketSession() *socketSession {
+// newSocketSession creates a new session with a unique ID for a socket
+// connection: id, or socket-N when id is empty.
+func newSocketSession(id string) *socketSession {
+	if id == "" {
+		id = fmt.Sprintf("socket-%d", sessionCounter.Add(1))
+	}
+
 	return &socketSession{
-		id:            fmt.Sprintf("socket-%d", sessionCounter.Add(1)),
+		id:            id,
 		notifications: make(chan mcp.JSONRPCNotification, 100),
 		sessionTools:  make(map[string]server.ServerTool),
 	}
@@ -10,7 +10,7 @@ import (
 func TestNewSocketSession(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 
 	if session == nil {
 		t.Fatal("newSocketSession() returned nil")
@@ -27,6 +27,10 @@ func TestNewSocketSession(t *testing.T) {
 	if session.notifications == nil {
 		t.Error("newSocketSession() notifications channel should not be nil")
 	}
+
+	if got := newSocketSession("devbox/socket-1").SessionID(); got != "devbox/socket-1" {
+		t.Errorf("newSocketSession(id) id = %s, want devbox/socket-1", got)
+	}
 }
 
 func TestSocketSession_UniqueIDs(t *testing.T) {
@@ -37,7 +41,7 @@ func TestSocketSession_UniqueIDs(t *testing.T) {
 	ids := make(map[string]bool)
 
 	for i := range sessions {
-		sessions[i] = newSocketSession()
+		sessions[i] = newSocketSession("")
 		id := sessions[i].SessionID()
 
 		if ids[id] {
@@ -51,7 +55,7 @@ func TestSocketSession_UniqueIDs(t *testing.T) {
 func TestSocketSession_SessionID(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 	id := session.SessionID()
 
 	if id != session.id {
@@ -62,7 +66,7 @@ func TestSocketSession_SessionID(t *testing.T) {
 func TestSocketSession_NotificationChannel(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 	ch := session.NotificationChannel()
 
 	if ch == nil {
@@ -88,7 +92,7 @@ func TestSocketSession_NotificationChannel(t *testing.T) {
 func TestSocketSession_Initialize(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 
 	if session.Initialized() {
 		t.Error("Initialized() should be false before Initialize()")
@@ -110,7 +114,7 @@ func TestSocketSession_Initialize(t *testing.T) {
 func TestSocketSession_LogLevel(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 
 	// Default should be error level
 	if level := session.GetLogLevel(); level != mcp.LoggingLevelError {
@@ -137,7 +141,7 @@ func TestSocketSession_LogLevel(t *testing.T) {
 func TestSocketSession_ClientInfo(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 
 	// Default should be empty
 	info := session.GetClientInfo()
@@ -161,7 +165,7 @@ func TestSocketSession_ClientInfo(t *testing.T) {
 func TestSocketSession_ClientCapabilities(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 
 	// Default should be empty
 	caps := session.GetClientCapabilities()
@@ -183,7 +187,7 @@ func TestSocketSession_ClientCapabilities(t *testing.T) {
 func TestSocketSession_Close(t *testing.T) {
 	t.Parallel()
 
-	session := newSocketSession()
+	session := newSocketSession("")
 
 	// Close should close the notification channel
 	session.close()
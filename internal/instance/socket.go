@@ -17,6 +17,8 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/events"
+	"github.com/valksor/go-assern/internal/log"
 )
 
 // handshakeTimeout is the time to wait for the first message to determine
@@ -37,6 +39,9 @@ type Server struct {
 	aggregator *aggregator.Aggregator
 	logger     *slog.Logger
 	info       *Info
+	limiter    *sessionLimiter
+	allowed    []int
+	logRing    *log.Ring // nil = assern/logs only serves server stderr
 
 	listener net.Listener
 	clients  map[net.Conn]struct{}
@@ -60,11 +65,25 @@ func NewServer(socketPath string, mcpServer *server.MCPServer, agg *aggregator.A
 			StartTime:  time.Now(),
 			WorkDir:    cwd,
 		},
+		limiter: newSessionLimiter(),
+		allowed: []int{os.Getuid()},
 		clients: make(map[net.Conn]struct{}),
 		done:    make(chan struct{}),
 	}
 }
 
+// SetLimits configures the concurrent MCP session limits. Internal commands
+// are never subject to limits. Must be called before Start.
+func (s *Server) SetLimits(limits Limits) {
+	s.limiter.setLimits(limits)
+}
+
+// SetLogRing sets the ring of log records served by assern/logs. Must be
+// called before Start.
+func (s *Server) SetLogRing(ring *log.Ring) {
+	s.logRing = ring
+}
+
 // Start begins listening on the Unix socket.
 func (s *Server) Start() error {
 	// Remove stale socket if exists
@@ -155,6 +174,13 @@ func (s *Server) handleConnection(conn net.Conn) {
 
 	s.logger.Debug("client connected", "remote", conn.RemoteAddr())
 
+	// Reject peers running as a user that is not allowed, before any command
+	// (including reload) is processed
+	uid, ok := s.authorizePeer(conn)
+	if !ok {
+		return
+	}
+
 	// Check for internal handshake command (ping/info) before starting MCP
 	// This allows the detector to quickly check if an instance is running
 	reader, handled := s.tryHandleInternalCommand(conn)
@@ -164,11 +190,40 @@ func (s *Server) handleConnection(conn net.Conn) {
 		return
 	}
 
-	// Not an internal command - proceed with MCP protocol
+	// Not an internal command - enforce session limits before serving MCP
+	if err := s.limiter.acquire(uid); err != nil {
+		s.logger.Warn("rejecting socket session", "uid", uid, "error", err)
+		s.publish(events.QuotaExceeded, err.Error(), map[string]any{"uid": uid})
+		s.rejectSession(conn, reader, err)
+
+		return
+	}
+	defer s.limiter.release(uid)
+
 	// reader may contain buffered data from the handshake check
 	s.serveMCP(conn, reader)
 }
 
+// rejectSession answers the client's first request with a session limit error
+// so it fails fast with a clear message instead of seeing a bare disconnect.
+func (s *Server) rejectSession(conn net.Conn, reader io.Reader, cause error) {
+	var id any
+
+	if err := conn.SetReadDeadline(time.Now().Add(ClientTimeout)); err == nil {
+		line, _ := bufio.NewReader(reader).ReadBytes('\n')
+
+		var req struct {
+			ID any `json:"id"`
+		}
+
+		if json.Unmarshal(line, &req) == nil {
+			id = req.ID
+		}
+	}
+
+	s.writeErrorResponse(conn, id, codeSessionLimit, cause.Error())
+}
+
 // tryHandleInternalCommand checks if the first message is an internal command.
 // Returns the reader to use for subsequent reads and whether the command was handled.
 // If handled is true, the connection should be closed.
@@ -202,9 +257,10 @@ func (s *Server) tryHandleInternalCommand(conn net.Conn) (io.Reader, bool) {
 
 	// Try to parse as internal command
 	var req struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      any    `json:"id"`
-		Method  string `json:"method"`
+		JSONRPC string          `json:"jsonrpc"`
+		ID      any             `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
 	}
 
 	if err := json.Unmarshal(line, &req); err != nil {
@@ -219,68 +275,57 @@ func (s *Server) tryHandleInternalCommand(conn net.Conn) (io.Reader, bool) {
 
 		return nil, true
 	case "assern/reload":
+		s.handleReload(conn, req.ID, req.Params)
+
+		return nil, true
+	case "assern/status":
 		if s.aggregator == nil {
 			s.sendInternalError(conn, req.ID, "aggregator not available")
 		} else {
-			ctx := context.Background()
-			result, err := s.aggregator.Reload(ctx)
-			if err != nil {
-				s.sendInternalError(conn, req.ID, err.Error())
-			} else {
-				s.sendInternalResponse(conn, req.ID, result)
-			}
+			s.sendInternalResponse(conn, req.ID, s.aggregator.Status())
 		}
 
 		return nil, true
-	}
-
-	// Not an internal command - prepend the message for MCP to process
-	return io.MultiReader(bytes.NewReader(line), reader), false
-}
-
-func (s *Server) sendInternalResponse(conn net.Conn, id any, result any) {
-	resp := map[string]any{
-		keyJSONRPC: jsonrpcVersion,
-		"id":       id,
-		"result":   result,
-	}
+	case "assern/stats":
+		if s.aggregator == nil {
+			s.sendInternalError(conn, req.ID, "aggregator not available")
+		} else {
+			s.sendInternalResponse(conn, req.ID, s.aggregator.CallStats())
+		}
 
-	data, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Debug("failed to marshal response", "error", err)
+		return nil, true
+	case "assern/errors":
+		s.handleErrors(conn, req.ID, req.Params)
 
-		return
-	}
+		return nil, true
+	case "assern/features":
+		if s.aggregator == nil {
+			s.sendInternalError(conn, req.ID, "aggregator not available")
+		} else {
+			s.sendInternalResponse(conn, req.ID, s.aggregator.Features())
+		}
 
-	data = append(data, '\n')
+		return nil, true
+	case "assern/features/set":
+		s.handleSetFeature(conn, req.ID, req.Params)
 
-	if _, err := conn.Write(data); err != nil {
-		s.logger.Debug("failed to write response", "error", err)
-	}
-}
+		return nil, true
+	case "assern/inspect":
+		s.handleInspect(conn, req.ID, req.Params)
 
-func (s *Server) sendInternalError(conn net.Conn, id any, message string) {
-	resp := map[string]any{
-		keyJSONRPC: jsonrpcVersion,
-		"id":       id,
-		"error": map[string]any{
-			"code":    -32603, // Internal error
-			"message": message,
-		},
-	}
+		return nil, true
+	case "assern/list":
+		s.handleList(conn, req.ID, req.Params)
 
-	data, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Debug("failed to marshal error response", "error", err)
+		return nil, true
+	case "assern/logs":
+		s.handleLogs(conn, req.ID, req.Params)
 
-		return
+		return nil, true
 	}
 
-	data = append(data, '\n')
-
-	if _, err := conn.Write(data); err != nil {
-		s.logger.Debug("failed to write error response", "error", err)
-	}
+	// Not an internal command - prepend the message for MCP to process
+	return io.MultiReader(bytes.NewReader(line), reader), false
 }
 
 func (s *Server) serveMCP(conn net.Conn, reader io.Reader) {
@@ -293,7 +338,12 @@ func (s *Server) serveMCP(conn net.Conn, reader io.Reader) {
 
 	// Create a unique session for this socket connection.
 	// This avoids conflicts with the "stdio" session used by the primary instance.
-	session := newSocketSession()
+	var id string
+	if s.aggregator != nil {
+		id = s.aggregator.NewSessionID(aggregator.SessionSocket)
+	}
+
+	session := newSocketSession(id)
 
 	if err := s.mcpServer.RegisterSession(ctx, session); err != nil {
 		s.logger.Debug("failed to register session", "error", err)
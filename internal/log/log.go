@@ -55,6 +55,8 @@ type Options struct {
 	Level   Level
 	JSON    bool
 	Verbose bool
+	// Ring, when set, also keeps the records at Level or above
+	Ring *Ring
 }
 
 // Configure sets up the global logger.
@@ -83,6 +85,10 @@ func Configure(opts Options) {
 		handler = slog.NewTextHandler(output, handlerOpts)
 	}
 
+	if opts.Ring != nil {
+		handler = slog.NewMultiHandler(handler, opts.Ring.Handler(level))
+	}
+
 	logger = slog.New(handler)
 }
 
@@ -0,0 +1,232 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	// RingSize is how many records the ring of a primary instance keeps for
+	// 'assern logs'.
+	RingSize = 1000
+
+	// followerBuffer is how many records a follower may fall behind before
+	// records are dropped for it; logging is never held up.
+	followerBuffer = 256
+)
+
+// Record is a log record kept by a Ring.
+type Record struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// Server returns the server the record is about: its "server" attribute.
+func (r Record) Server() string {
+	return r.Attrs["server"]
+}
+
+// Filter selects records of a Ring.
+type Filter struct {
+	// Server keeps the records about one server; "" keeps all
+	Server string
+	// Since keeps the records logged at or after it; zero keeps all
+	Since time.Time
+	// Limit keeps only the last records returned first; 0 keeps all
+	Limit int
+}
+
+// Match reports whether r passes the server and time filters.
+func (f Filter) Match(r Record) bool {
+	if f.Server != "" && r.Server() != f.Server {
+		return false
+	}
+
+	return f.Since.IsZero() || !r.Time.Before(f.Since)
+}
+
+// Ring keeps the last records logged through its handler, so that the log
+// of an instance started by an MCP client, whose stderr nobody sees, can
+// still be read over the socket. It is safe for concurrent use.
+type Ring struct {
+	mu        sync.Mutex
+	records   []Record // Circular once full; next is the oldest
+	next      int
+	size      int
+	followers map[*ringFollower]struct{}
+}
+
+// ringFollower receives the new records matching its filter.
+type ringFollower struct {
+	filter Filter
+	ch     chan Record
+}
+
+// NewRing returns a ring keeping the last size records.
+func NewRing(size int) *Ring {
+	return &Ring{
+		records:   make([]Record, 0, size),
+		size:      size,
+		followers: make(map[*ringFollower]struct{}),
+	}
+}
+
+func (r *Ring) add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.records) < r.size {
+		r.records = append(r.records, rec)
+	} else {
+		r.records[r.next] = rec
+		r.next = (r.next + 1) % r.size
+	}
+
+	for f := range r.followers {
+		if !f.filter.Match(rec) {
+			continue
+		}
+
+		select {
+		case f.ch <- rec:
+		default:
+		}
+	}
+}
+
+// Records returns the kept records passing filter, oldest first.
+func (r *Ring) Records(filter Filter) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.recordsLocked(filter)
+}
+
+func (r *Ring) recordsLocked(filter Filter) []Record {
+	var out []Record
+
+	for i := range r.records {
+		if rec := r.records[(r.next+i)%len(r.records)]; filter.Match(rec) {
+			out = append(out, rec)
+		}
+	}
+
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[len(out)-filter.Limit:]
+	}
+
+	return out
+}
+
+// Follow returns the records passing filter like Records, and a channel
+// receiving the matching records logged after them until stop is called.
+func (r *Ring) Follow(filter Filter) ([]Record, <-chan Record, func()) {
+	f := &ringFollower{filter: filter, ch: make(chan Record, followerBuffer)}
+
+	r.mu.Lock()
+	r.followers[f] = struct{}{}
+	records := r.recordsLocked(filter)
+	r.mu.Unlock()
+
+	return records, f.ch, func() {
+		r.mu.Lock()
+		delete(r.followers, f)
+		r.mu.Unlock()
+	}
+}
+
+// Handler returns a handler adding the records at level or above to r.
+func (r *Ring) Handler(level slog.Leveler) slog.Handler {
+	return &ringHandler{ring: r, level: level}
+}
+
+// ringHandler turns slog records into Records. Attributes of groups are
+// keyed "group.name".
+type ringHandler struct {
+	ring   *Ring
+	level  slog.Leveler
+	attrs  map[string]string // Added by WithAttrs, keys already prefixed
+	prefix string            // Of the groups opened by WithGroup
+}
+
+func (h *ringHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *ringHandler) Handle(_ context.Context, record slog.Record) error {
+	rec := Record{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: record.Message,
+	}
+
+	if len(h.attrs) > 0 || record.NumAttrs() > 0 {
+		rec.Attrs = make(map[string]string, len(h.attrs)+record.NumAttrs())
+	}
+
+	maps.Copy(rec.Attrs, h.attrs)
+
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(rec.Attrs, h.prefix, a)
+
+		return true
+	})
+
+	h.ring.add(rec)
+
+	return nil
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = maps.Clone(h.attrs)
+
+	if clone.attrs == nil {
+		clone.attrs = make(map[string]string, len(attrs))
+	}
+
+	for _, a := range attrs {
+		addAttr(clone.attrs, h.prefix, a)
+	}
+
+	return &clone
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+
+	return &clone
+}
+
+// addAttr adds a to attrs under prefix, flattening groups.
+func addAttr(attrs map[string]string, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+
+		for _, ga := range a.Value.Group() {
+			addAttr(attrs, prefix, ga)
+		}
+
+		return
+	}
+
+	if a.Key == "" {
+		return
+	}
+
+	attrs[prefix+a.Key] = a.Value.String()
+}
@@ -0,0 +1,118 @@
+package log
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestRingRecords(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRing(3)
+	logger := slog.New(ring.Handler(slog.LevelInfo))
+
+	logger.Debug("not kept")
+
+	for _, server := range []string{"a", "b", "a", "b"} {
+		logger.Info("started", "server", server)
+	}
+
+	start := time.Now()
+
+	logger.With("server", "a").WithGroup("call").Warn("failed", "tool", "search", slog.Group("args", "q", "x y"))
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string // Servers of the records returned
+	}{
+		{name: "all, oldest first", want: []string{"a", "b", "a"}},
+		{name: "server", filter: Filter{Server: "b"}, want: []string{"b"}},
+		{name: "limit", filter: Filter{Limit: 1}, want: []string{"a"}},
+		{name: "since", filter: Filter{Since: start}, want: []string{"a"}},
+		{name: "nothing", filter: Filter{Server: "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			records := ring.Records(tt.filter)
+
+			var got []string
+			for _, r := range records {
+				got = append(got, r.Server())
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Records(%+v) servers = %v, want %v", tt.filter, got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Records(%+v) servers = %v, want %v", tt.filter, got, tt.want)
+				}
+			}
+		})
+	}
+
+	last := ring.Records(Filter{Limit: 1})[0]
+	want := map[string]string{"server": "a", "call.tool": "search", "call.args.q": "x y"}
+
+	if last.Message != "failed" || last.Level != "WARN" || len(last.Attrs) != len(want) {
+		t.Fatalf("last record = %+v", last)
+	}
+
+	for k, v := range want {
+		if last.Attrs[k] != v {
+			t.Errorf("attr %s = %q, want %q", k, last.Attrs[k], v)
+		}
+	}
+}
+
+func TestRingFollow(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRing(10)
+	logger := slog.New(ring.Handler(slog.LevelInfo))
+
+	logger.Info("before", "server", "a")
+
+	backlog, next, stop := ring.Follow(Filter{Server: "a"})
+
+	if len(backlog) != 1 || backlog[0].Message != "before" {
+		t.Fatalf("backlog = %+v, want the earlier record", backlog)
+	}
+
+	logger.Info("other", "server", "b")
+	logger.Info("after", "server", "a")
+
+	if r := <-next; r.Message != "after" {
+		t.Errorf("followed record = %+v, want only the matching one", r)
+	}
+
+	stop()
+	logger.Info("stopped", "server", "a")
+
+	select {
+	case r := <-next:
+		t.Errorf("record %+v received after stop", r)
+	default:
+	}
+}
+
+func TestConfigureRing(t *testing.T) {
+	ring := NewRing(10)
+
+	Configure(Options{Output: io.Discard, Level: LevelWarn, Ring: ring})
+	defer Configure(Options{})
+
+	Info("below the level")
+	Warn("kept", "server", "a")
+
+	if records := ring.Records(Filter{}); len(records) != 1 || records[0].Message != "kept" {
+		t.Errorf("ring records = %+v, want the warning only", records)
+	}
+}
@@ -0,0 +1,137 @@
+// Package metrics exports aggregator measurements to external systems.
+//
+// The statsd exporter speaks both plain statsd and the DogStatsD dialect used
+// by the Datadog agent, sending one UDP datagram per measurement:
+//
+//	sink, err := metrics.NewStatsD(cfg.Settings.Metrics.StatsD)
+//	sink.Timing("tool.call.duration", 42*time.Millisecond, map[string]string{"server": "github"})
+//
+// Sending is fire-and-forget: a missing agent never slows down tool calls.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// StatsD sends metrics to a statsd or DogStatsD agent over UDP.
+type StatsD struct {
+	conn       net.Conn
+	prefix     string
+	dogstatsd  bool
+	globalTags []string
+}
+
+// NewStatsD creates a statsd exporter from configuration. The UDP socket is
+// connectionless, so this succeeds even when no agent is listening yet.
+func NewStatsD(cfg *config.StatsDConfig) (*StatsD, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(context.Background(), "udp", cfg.EffectiveAddress())
+	if err != nil {
+		return nil, fmt.Errorf("statsd dial %s: %w", cfg.EffectiveAddress(), err)
+	}
+
+	s := &StatsD{
+		conn:   conn,
+		prefix: cfg.EffectivePrefix(),
+	}
+
+	if cfg != nil {
+		s.dogstatsd = cfg.DogStatsD
+		s.globalTags = slices.Clone(cfg.Tags)
+	}
+
+	return s, nil
+}
+
+// Timing records a duration in milliseconds.
+func (s *StatsD) Timing(name string, d time.Duration, tags map[string]string) {
+	ms := strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+	s.send(name, ms, "ms", tags)
+}
+
+// Count increments a counter by n.
+func (s *StatsD) Count(name string, n int64, tags map[string]string) {
+	s.send(name, strconv.FormatInt(n, 10), "c", tags)
+}
+
+// Gauge sets a gauge to v.
+func (s *StatsD) Gauge(name string, v float64, tags map[string]string) {
+	s.send(name, strconv.FormatFloat(v, 'f', -1, 64), "g", tags)
+}
+
+// Close releases the UDP socket.
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+// send writes one datagram. Write errors (typically "connection refused"
+// when no agent is running) are deliberately ignored.
+func (s *StatsD) send(name, value, kind string, tags map[string]string) {
+	_, _ = s.conn.Write([]byte(s.format(name, value, kind, tags)))
+}
+
+// format renders a metric line. DogStatsD carries tags natively; plain statsd
+// folds the tag values into the name in key order, e.g.
+// assern.tool.call.duration.github.search_repos.
+func (s *StatsD) format(name, value, kind string, tags map[string]string) string {
+	var b strings.Builder
+
+	b.WriteString(s.prefix)
+	b.WriteString(name)
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	if !s.dogstatsd {
+		for _, k := range keys {
+			b.WriteByte('.')
+			b.WriteString(sanitize(tags[k], true))
+		}
+	}
+
+	b.WriteByte(':')
+	b.WriteString(value)
+	b.WriteByte('|')
+	b.WriteString(kind)
+
+	if s.dogstatsd && (len(keys) > 0 || len(s.globalTags) > 0) {
+		b.WriteString("|#")
+
+		all := slices.Clone(s.globalTags)
+		for _, k := range keys {
+			all = append(all, k+":"+sanitize(tags[k], false))
+		}
+
+		b.WriteString(strings.Join(all, ","))
+	}
+
+	return b.String()
+}
+
+// sanitize replaces characters that have meaning in the statsd line protocol.
+// Dots are also replaced when the value becomes part of a metric name.
+func sanitize(s string, inName bool) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ':', r == '|', r == '@', r == '#', r == ',', r == ' ':
+			return '_'
+		case r == '.' && inName:
+			return '_'
+		default:
+			return r
+		}
+	}, s)
+}
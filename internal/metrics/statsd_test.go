@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+func TestStatsDFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		s     *StatsD
+		kind  string
+		value string
+		tags  map[string]string
+		want  string
+	}{
+		{
+			name:  "plain statsd folds tags into name",
+			s:     &StatsD{prefix: "assern."},
+			kind:  "ms",
+			value: "12.5",
+			tags:  map[string]string{"server": "github", "tool": "search.repos"},
+			want:  "assern.tool.call.duration.github.search_repos:12.5|ms",
+		},
+		{
+			name:  "dogstatsd tags",
+			s:     &StatsD{prefix: "assern.", dogstatsd: true, globalTags: []string{"env:dev"}},
+			kind:  "c",
+			value: "1",
+			tags:  map[string]string{"tool": "search", "server": "github"},
+			want:  "assern.tool.call.duration:1|c|#env:dev,server:github,tool:search",
+		},
+		{
+			name:  "dogstatsd without tags",
+			s:     &StatsD{prefix: "x.", dogstatsd: true},
+			kind:  "g",
+			value: "3",
+			want:  "x.tool.call.duration:3|g",
+		},
+		{
+			name:  "protocol characters sanitized",
+			s:     &StatsD{prefix: "assern.", dogstatsd: true},
+			kind:  "c",
+			value: "1",
+			tags:  map[string]string{"server": "a|b:c"},
+			want:  "assern.tool.call.duration:1|c|#server:a_b_c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.s.format("tool.call.duration", tt.value, tt.kind, tt.tags); got != tt.want {
+				t.Errorf("format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsDSendsOverUDP(t *testing.T) {
+	t.Parallel()
+
+	var lc net.ListenConfig
+
+	pc, err := lc.ListenPacket(t.Context(), "udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	sink, err := NewStatsD(&config.StatsDConfig{
+		Enabled:   true,
+		Address:   pc.LocalAddr().String(),
+		DogStatsD: true,
+	})
+	if err != nil {
+		t.Fatalf("NewStatsD() error = %v", err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	sink.Timing("tool.call.duration", 1500*time.Microsecond, map[string]string{"server": "github"})
+
+	_ = pc.SetReadDeadline(time.Now().Add(time.Second))
+
+	buf := make([]byte, 512)
+
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+
+	if got, want := string(buf[:n]), "assern.tool.call.duration:1.5|ms|#server:github"; got != want {
+		t.Errorf("datagram = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+// Package proctitle names the running assern process after its role and
+// project, so ps and top tell several assern processes apart.
+package proctitle
+
+// Roles an assern process announces in its title.
+const (
+	// RolePrimary serves the backends and the instance-sharing socket.
+	RolePrimary = "primary"
+	// RoleProxy forwards stdio to a running primary instance.
+	RoleProxy = "proxy"
+	// RoleStdin serves a configuration read from stdin (--config-stdin).
+	RoleStdin = "stdin"
+)
+
+// Title returns the full title for a role and optional project, e.g.
+// "assern: primary [work]".
+func Title(role, project string) string {
+	title := "assern: " + role
+	if project != "" {
+		title += " [" + project + "]"
+	}
+
+	return title
+}
+
+// Name returns the short process name for a role and optional project:
+// "assern:<project>" when there is a project, since a primary is told
+// apart by it, and "assern:<role>" otherwise.
+func Name(role, project string) string {
+	if project != "" {
+		return "assern:" + project
+	}
+
+	return "assern:" + role
+}
+
+// Set names the process where the platform allows it and reports whether
+// it did. See set for what each platform supports.
+func Set(role, project string) (bool, error) {
+	return set(Title(role, project), Name(role, project))
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package proctitle
+
+import (
+	"fmt"
+	"os"
+)
+
+// commLen is the longest process name the kernel keeps (TASK_COMM_LEN - 1).
+const commLen = 15
+
+// set writes the short name to /proc/self/comm, the name ps, top and pstree
+// show; it names the main thread whichever thread the caller runs on. The
+// command line, and so the full title, cannot be changed without
+// overwriting the argument memory Go strings still point to.
+func set(_, name string) (bool, error) {
+	if len(name) > commLen {
+		name = name[:commLen]
+	}
+
+	if err := os.WriteFile("/proc/self/comm", []byte(name), 0); err != nil {
+		return false, fmt.Errorf("setting process name: %w", err)
+	}
+
+	return true, nil
+}
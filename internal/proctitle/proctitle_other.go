@@ -0,0 +1,9 @@
+//go:build !linux
+
+package proctitle
+
+// set is not supported on this platform: renaming a process needs cgo on
+// macOS and has no equivalent on Windows.
+func set(_, _ string) (bool, error) {
+	return false, nil
+}
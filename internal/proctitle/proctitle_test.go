@@ -0,0 +1,27 @@
+package proctitle
+
+import "testing"
+
+func TestTitleAndName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		role, project string
+		wantTitle     string
+		wantName      string
+	}{
+		{RolePrimary, "work", "assern: primary [work]", "assern:work"},
+		{RolePrimary, "", "assern: primary", "assern:primary"},
+		{RoleProxy, "", "assern: proxy", "assern:proxy"},
+	}
+
+	for _, tt := range tests {
+		if got := Title(tt.role, tt.project); got != tt.wantTitle {
+			t.Errorf("Title(%q, %q) = %q, want %q", tt.role, tt.project, got, tt.wantTitle)
+		}
+
+		if got := Name(tt.role, tt.project); got != tt.wantName {
+			t.Errorf("Name(%q, %q) = %q, want %q", tt.role, tt.project, got, tt.wantName)
+		}
+	}
+}
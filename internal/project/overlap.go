@@ -0,0 +1,96 @@
+package project
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/valksor/go-assern/internal/paths"
+)
+
+// sampleSegment stands in for wildcards when generating sample paths.
+const sampleSegment = "example"
+
+// Overlap describes two projects whose directory patterns match a common
+// path, making project detection for that path depend on priority.
+type Overlap struct {
+	// Sample is a directory matched by both projects.
+	Sample string
+	// Matches lists every project matching Sample, winner first.
+	Matches []*RegistryMatch
+	// Tied is true when the first two matches share the same priority, so
+	// the winner was picked by name rather than an explicit priority.
+	Tied bool
+}
+
+// Winner returns the project detected for the sample path.
+func (o Overlap) Winner() string {
+	return o.Matches[0].Name
+}
+
+// Overlaps finds directory patterns from different projects that match the
+// same paths. Each pattern is expanded into sample paths (wildcards replaced
+// by a placeholder segment) which are then matched against every project.
+func (r *Registry) Overlaps() []Overlap {
+	var (
+		result []Overlap
+		seen   = make(map[string]bool)
+	)
+
+	for _, name := range r.sortedNames() {
+		for _, pattern := range r.projects[name].Directories {
+			for _, sample := range samplePaths(pattern) {
+				if seen[sample] {
+					continue
+				}
+
+				seen[sample] = true
+
+				matches := r.MatchAll(sample)
+				if len(matches) < 2 {
+					continue
+				}
+
+				result = append(result, Overlap{
+					Sample:  sample,
+					Matches: matches,
+					Tied:    r.projects[matches[0].Name].Priority == r.projects[matches[1].Name].Priority,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// Priority returns a registered project's priority (0 if unknown).
+func (r *Registry) Priority(name string) int {
+	if proj := r.Get(name); proj != nil {
+		return proj.Priority
+	}
+
+	return 0
+}
+
+// samplePaths returns concrete directories matched by a pattern: the pattern
+// with wildcards filled in and, for "**" patterns, the directory the
+// wildcard is rooted at.
+func samplePaths(pattern string) []string {
+	expanded := filepath.Clean(paths.ExpandPath(pattern))
+	if !strings.Contains(expanded, "*") {
+		return []string{expanded}
+	}
+
+	filled := strings.ReplaceAll(expanded, "**", sampleSegment)
+	filled = strings.ReplaceAll(filled, "*", sampleSegment)
+	samples := []string{filled}
+
+	if before, _, ok := strings.Cut(expanded, "**"); ok {
+		root := filepath.Clean(before)
+		if !strings.Contains(root, "*") && !slices.Contains(samples, root) {
+			samples = append(samples, root)
+		}
+	}
+
+	return samples
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRegistry_MatchPriority(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "work", "acme")
+
+	tests := []struct {
+		name       string
+		priorities map[string]int
+		want       string
+	}{
+		{name: "tie broken by name", priorities: map[string]int{}, want: "alpha"},
+		{name: "higher priority wins", priorities: map[string]int{"zulu": 10}, want: "zulu"},
+		{name: "negative priority loses", priorities: map[string]int{"alpha": -1}, want: "zulu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := NewRegistry()
+			registry.RegisterWithPriority("zulu", []string{filepath.Join(root, "work", "*")}, tt.priorities["zulu"], nil)
+			registry.RegisterWithPriority("alpha", []string{filepath.Join(root, "**")}, tt.priorities["alpha"], nil)
+
+			// Repeat to catch map-order dependent results
+			for range 20 {
+				if got := registry.Match(repo); got == nil || got.Name != tt.want {
+					t.Fatalf("Match() = %+v, want %s", got, tt.want)
+				}
+			}
+
+			if n := len(registry.MatchAll(repo)); n != 2 {
+				t.Errorf("MatchAll() returned %d matches, want 2", n)
+			}
+		})
+	}
+}
+
+func TestRegistry_Overlaps(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	registry := NewRegistry()
+	registry.Register("acme", []string{filepath.Join(root, "work", "acme", "**")}, nil)
+	registry.Register("work", []string{filepath.Join(root, "work", "*")}, nil)
+	registry.RegisterWithPriority("personal", []string{filepath.Join(root, "home")}, 5, nil)
+	registry.Register("home", []string{filepath.Join(root, "home")}, nil)
+	registry.Register("solo", []string{filepath.Join(root, "solo")}, nil)
+
+	overlaps := registry.Overlaps()
+
+	bySample := make(map[string]Overlap, len(overlaps))
+	for _, o := range overlaps {
+		bySample[o.Sample] = o
+	}
+
+	acme, ok := bySample[filepath.Join(root, "work", "acme")]
+	if !ok {
+		t.Fatalf("missing overlap for acme root; got %v", slices.Collect(maps.Keys(bySample)))
+	}
+
+	if acme.Winner() != "acme" || !acme.Tied {
+		t.Errorf("acme overlap winner = %s tied = %v, want acme tied", acme.Winner(), acme.Tied)
+	}
+
+	home, ok := bySample[filepath.Join(root, "home")]
+	if !ok {
+		t.Fatal("missing overlap for identical directories")
+	}
+
+	if home.Winner() != "personal" || home.Tied {
+		t.Errorf("home overlap winner = %s tied = %v, want personal by priority", home.Winner(), home.Tied)
+	}
+
+	if _, ok := bySample[filepath.Join(root, "solo")]; ok {
+		t.Error("non-overlapping project reported")
+	}
+}
+
+func TestSamplePaths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"/srv/app", []string{"/srv/app"}},
+		{"/srv/*", []string{"/srv/example"}},
+		{"/srv/**", []string{"/srv/example", "/srv"}},
+		{"/srv/**/api", []string{"/srv/example/api", "/srv"}},
+	}
+
+	for _, tt := range tests {
+		if got := samplePaths(tt.pattern); !slices.Equal(got, tt.want) {
+			t.Errorf("samplePaths(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
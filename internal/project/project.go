@@ -40,6 +40,8 @@ const (
 	SourceLocal DetectionSource = "local"
 	// SourceRegistry means project was matched from registry.
 	SourceRegistry DetectionSource = "registry"
+	// SourceGitRemote means project was matched by the repository's git remote.
+	SourceGitRemote DetectionSource = "git-remote"
 	// SourceExplicit means project was explicitly specified via flag.
 	SourceExplicit DetectionSource = "explicit"
 	// SourceAutoDetect means project name was auto-detected from directory name.
@@ -84,7 +86,8 @@ func (d *Detector) SetConfigLoader(loader ConfigLoader) {
 // Detection priority:
 // 1. Local config directory with explicit project name
 // 2. Local config directory (use directory name as project name)
-// 3. Match directory against global registry.
+// 3. Match directory against global registry
+// 4. Match the repository's git remotes against global registry.
 func (d *Detector) Detect(dir string) (*Context, error) {
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
@@ -141,6 +144,14 @@ func (d *Detector) Detect(dir string) (*Context, error) {
 
 			return ctx, nil
 		}
+
+		if match := d.registry.MatchRemote(absDir); match != nil {
+			ctx.Name = match.Name
+			ctx.Config = match.Config
+			ctx.Source = SourceGitRemote
+
+			return ctx, nil
+		}
 	}
 
 	// Step 3: Auto-detect from directory basename
@@ -1,7 +1,10 @@
 package project
 
 import (
+	"cmp"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/valksor/go-assern/internal/paths"
@@ -24,6 +27,11 @@ type RegistryProject struct {
 	Name        string
 	Directories []string
 	Config      any
+	// Remotes are git remote patterns (e.g. "github.com/acme/*") matched
+	// when no directory pattern matches.
+	Remotes []string
+	// Priority breaks ties when several projects match a directory.
+	Priority int
 }
 
 // NewRegistry creates a new project registry.
@@ -43,20 +51,44 @@ func NewRegistryFromMap(projects map[string]*RegistryProject) *Registry {
 
 // Register registers a project with the registry.
 func (r *Registry) Register(name string, directories []string, config any) {
-	if r.projects == nil {
-		r.projects = make(map[string]*RegistryProject)
-	}
+	r.RegisterWithPriority(name, directories, 0, config)
+}
 
-	r.projects[name] = &RegistryProject{
+// RegisterWithPriority registers a project with an explicit priority.
+func (r *Registry) RegisterWithPriority(name string, directories []string, priority int, config any) {
+	r.Add(&RegistryProject{
 		Name:        name,
 		Directories: directories,
 		Config:      config,
+		Priority:    priority,
+	})
+}
+
+// Add registers a fully described project, replacing any with the same name.
+func (r *Registry) Add(proj *RegistryProject) {
+	if r.projects == nil {
+		r.projects = make(map[string]*RegistryProject)
 	}
+
+	r.projects[proj.Name] = proj
 }
 
 // Match attempts to match a directory against registered projects.
-// Returns the first matching project or nil if no match found.
+// When several projects match, the highest priority wins and ties go to the
+// alphabetically first name, so the result never depends on map order.
+// Returns nil if no match found.
 func (r *Registry) Match(dir string) *RegistryMatch {
+	matches := r.MatchAll(dir)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	return matches[0]
+}
+
+// MatchAll returns every project matching a directory, best match first
+// (see Match for the ordering).
+func (r *Registry) MatchAll(dir string) []*RegistryMatch {
 	if r.projects == nil {
 		return nil
 	}
@@ -67,13 +99,49 @@ func (r *Registry) Match(dir string) *RegistryMatch {
 		return nil
 	}
 
-	for name, proj := range r.projects {
+	var matches []*RegistryMatch
+
+	for _, name := range r.sortedNames() {
+		proj := r.projects[name]
 		for _, pattern := range proj.Directories {
 			if matchDirectory(absDir, pattern) {
-				return &RegistryMatch{
+				matches = append(matches, &RegistryMatch{
 					Name:    name,
 					Pattern: pattern,
 					Config:  proj.Config,
+				})
+
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// MatchRemote matches the git remotes of the repository containing dir
+// against the projects' remote patterns, in the same order as Match.
+// Returns nil if dir is not in a repository or no pattern matches.
+func (r *Registry) MatchRemote(dir string) *RegistryMatch {
+	if r.projects == nil {
+		return nil
+	}
+
+	remotes := GitRemotes(dir)
+	if len(remotes) == 0 {
+		return nil
+	}
+
+	for _, name := range r.sortedNames() {
+		proj := r.projects[name]
+		for _, pattern := range proj.Remotes {
+			for _, remote := range remotes {
+				if matchRemote(remote, pattern) {
+					return &RegistryMatch{
+						Name:    name,
+						Pattern: pattern,
+						Config:  proj.Config,
+					}
 				}
 			}
 		}
@@ -82,6 +150,21 @@ func (r *Registry) Match(dir string) *RegistryMatch {
 	return nil
 }
 
+// sortedNames returns the project names by descending priority, then name.
+func (r *Registry) sortedNames() []string {
+	names := slices.Collect(maps.Keys(r.projects))
+
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(r.projects[b].Priority, r.projects[a].Priority); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	return names
+}
+
 // List returns all registered project names.
 func (r *Registry) List() []string {
 	if len(r.projects) == 0 {
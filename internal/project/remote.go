@@ -0,0 +1,186 @@
+package project
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GitRemotes returns the normalized remote URLs (see NormalizeRemote) of the
+// git repository containing dir, "origin" first. It reads the repository's
+// config file directly rather than running git, and returns nil when dir is
+// not inside a repository.
+func GitRemotes(dir string) []string {
+	configPath := findGitConfig(dir)
+	if configPath == "" {
+		return nil
+	}
+
+	f, err := os.Open(configPath)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		remotes []string
+		origin  string
+		section string
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+
+			continue
+		}
+
+		if !strings.HasPrefix(section, "remote ") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "url" {
+			continue
+		}
+
+		remote := NormalizeRemote(strings.TrimSpace(value))
+		if remote == "" {
+			continue
+		}
+
+		if section == `remote "origin"` {
+			origin = remote
+		} else {
+			remotes = append(remotes, remote)
+		}
+	}
+
+	if origin != "" {
+		remotes = append([]string{origin}, remotes...)
+	}
+
+	return remotes
+}
+
+// findGitConfig walks up from dir to the repository root and returns the
+// path of its config file. Worktrees and submodules, whose .git is a file
+// pointing elsewhere, resolve to the common repository's config.
+func findGitConfig(dir string) string {
+	current := dir
+
+	for {
+		dotGit := filepath.Join(current, ".git")
+
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			if info.IsDir() {
+				return filepath.Join(dotGit, "config")
+			}
+
+			return gitConfigFromFile(current, dotGit)
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return ""
+		}
+
+		current = parent
+	}
+}
+
+// gitConfigFromFile follows a "gitdir: <path>" .git file. Linked worktrees
+// keep their config in the main repository, named by the commondir file.
+func gitConfigFromFile(root, dotGit string) string {
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+
+		gitDir = commonDir
+	}
+
+	return filepath.Join(gitDir, "config")
+}
+
+// NormalizeRemote reduces a git remote URL to "host/path" without scheme,
+// user, port or ".git" suffix, so that the SSH and HTTPS forms of a remote
+// compare equal:
+//
+//	git@github.com:acme/api.git        -> github.com/acme/api
+//	https://github.com/acme/api        -> github.com/acme/api
+//	ssh://git@github.com:22/acme/api   -> github.com/acme/api
+func NormalizeRemote(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return ""
+	}
+
+	var host, repoPath string
+
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return ""
+		}
+
+		host, repoPath = u.Hostname(), u.Path
+	} else if before, after, ok := strings.Cut(remote, ":"); ok && !strings.Contains(before, "/") {
+		// scp-like syntax: [user@]host:path
+		if _, h, found := strings.Cut(before, "@"); found {
+			before = h
+		}
+
+		host, repoPath = before, after
+	} else {
+		// Local path remotes are not matched
+		return ""
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return ""
+	}
+
+	return strings.ToLower(host) + "/" + repoPath
+}
+
+// matchRemote reports whether a normalized remote matches a pattern such as
+// "github.com/acme/*" ("*" matches one path segment) or "gitlab.com/acme/**"
+// (any depth). Patterns are normalized like remotes, so full URLs also work.
+func matchRemote(remote, pattern string) bool {
+	if normalized := NormalizeRemote(pattern); normalized != "" {
+		pattern = normalized
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return remote == prefix || strings.HasPrefix(remote, prefix+"/")
+	}
+
+	matched, err := path.Match(pattern, remote)
+
+	return err == nil && matched
+}
@@ -0,0 +1,349 @@
+// Package redact hides secrets in configuration before it is printed or
+// logged. What shows configuration goes through it ('assern config show',
+// 'assern mcp list', server endpoints in 'assern status' and the web UI,
+// recorded server errors, and debug logs naming a server's command or URL),
+// so secrets are masked the same way everywhere:
+//
+//   - env values are always hidden: names do not tell tokens apart;
+//   - headers, URL query parameters and command-line flags are hidden when
+//     their name looks like a credential (see SensitiveName), and URLs lose
+//     their password;
+//   - OAuth client secrets, ACL tokens and the web UI token are hidden.
+//
+// References such as ${GITHUB_TOKEN} or keyring://github are kept: they
+// reveal nothing and show where the value comes from.
+package redact
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Placeholder replaces hidden values.
+const Placeholder = "<redacted>"
+
+// sensitiveParts are name fragments of credentials, matched
+// case-insensitively anywhere in a name.
+var sensitiveParts = []string{
+	"authorization", "token", "secret", "password", "passwd", "credential",
+	"cookie", "apikey", "api-key", "api_key", "private-key", "private_key",
+	"access-key", "access_key", "signature",
+}
+
+// IsReference reports whether value only refers to a secret stored
+// elsewhere: a ${VAR} reference, optionally after an authorization scheme
+// as in "Bearer ${TOKEN}", or a keyring:// URI.
+func IsReference(value string) bool {
+	if strings.HasPrefix(value, "keyring://") {
+		return true
+	}
+
+	if scheme, ref, ok := strings.Cut(value, " "); ok && !strings.Contains(scheme, "$") {
+		value = ref
+	}
+
+	return strings.HasPrefix(value, "${") && strings.Index(value, "}") == len(value)-1
+}
+
+// SensitiveName reports whether a header, parameter, flag or variable name
+// looks like it holds a credential, e.g. Authorization, X-Api-Key,
+// GITHUB_TOKEN, --password or OPENAI_KEY.
+func SensitiveName(name string) bool {
+	lower := strings.ToLower(strings.TrimLeft(name, "-"))
+
+	for _, part := range sensitiveParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+
+	return lower == "key" || strings.HasSuffix(lower, "_key") || strings.HasSuffix(lower, "-key")
+}
+
+// Value returns Placeholder for a literal value, or value itself when it is
+// empty or a reference.
+func Value(value string) string {
+	if value == "" || IsReference(value) {
+		return value
+	}
+
+	return Placeholder
+}
+
+// Named hides value when name is sensitive.
+func Named(name, value string) string {
+	if !SensitiveName(name) {
+		return value
+	}
+
+	return Value(value)
+}
+
+// Env returns a copy of env with every literal value hidden.
+func Env(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(env))
+	for k, v := range env {
+		out[k] = Value(v)
+	}
+
+	return out
+}
+
+// Headers returns a copy of headers with the values of sensitive ones
+// hidden.
+func Headers(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		out[k] = Named(k, v)
+	}
+
+	return out
+}
+
+// URL hides the password and sensitive query parameters of raw. Values
+// that do not parse as a URL with a scheme are returned unchanged.
+func URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || (u.User == nil && u.RawQuery == "") {
+		return raw
+	}
+
+	changed := false
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), Placeholder)
+		changed = true
+	}
+
+	query := u.Query()
+	for k, values := range query {
+		for i, v := range values {
+			if values[i] = Named(k, v); values[i] != v {
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return raw
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = query.Encode()
+	}
+
+	// Keep the placeholder readable rather than escaped
+	return strings.ReplaceAll(u.String(), url.QueryEscape(Placeholder), Placeholder)
+}
+
+// Webhook hides the path and query of a literal webhook URL, which often
+// carry its secret (as Slack's do), keeping the scheme and host.
+func Webhook(raw string) string {
+	if raw == "" || IsReference(raw) {
+		return raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return Placeholder
+	}
+
+	if u.Path == "" && u.RawQuery == "" && u.User == nil {
+		return raw
+	}
+
+	return u.Scheme + "://" + u.Host + "/" + Placeholder
+}
+
+// urlPattern finds URLs in free text such as error messages.
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// Text applies URL to every URL in s, e.g. in an error message quoting the
+// request that failed.
+func Text(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+
+	return urlPattern.ReplaceAllStringFunc(s, URL)
+}
+
+// Args returns a copy of command-line arguments with the values of
+// sensitive flags (--token=x, --token x), sensitive NAME=value pairs,
+// "Name: value" headers and URL credentials hidden.
+func Args(args []string) []string {
+	if args == nil {
+		return nil
+	}
+
+	out := make([]string, len(args))
+
+	for i, arg := range args {
+		switch {
+		case i > 0 && strings.HasPrefix(args[i-1], "-") && !strings.Contains(args[i-1], "=") &&
+			SensitiveName(args[i-1]) && !strings.HasPrefix(arg, "-"):
+			out[i] = Value(arg)
+		case strings.Contains(arg, "="):
+			name, value, _ := strings.Cut(arg, "=")
+			out[i] = name + "=" + Named(name, URL(value))
+		case strings.Contains(arg, ": "):
+			name, value, _ := strings.Cut(arg, ": ")
+			out[i] = name + ": " + Named(name, value)
+		default:
+			out[i] = URL(arg)
+		}
+	}
+
+	return out
+}
+
+// CommandLine joins command and its arguments with Args applied.
+func CommandLine(command string, args []string) string {
+	return strings.Join(append([]string{command}, Args(args)...), " ")
+}
+
+// OAuth returns a copy of o with a literal client secret hidden.
+func OAuth(o *config.OAuthConfig) *config.OAuthConfig {
+	if o == nil {
+		return nil
+	}
+
+	clone := o.Clone()
+	clone.ClientSecret = Value(clone.ClientSecret)
+
+	return clone
+}
+
+// Server returns a copy of cfg with its env values, sensitive headers,
+// URL and argument credentials and OAuth client secret hidden.
+func Server(cfg *config.ServerConfig) *config.ServerConfig {
+	if cfg == nil {
+		return nil
+	}
+
+	clone := cfg.Clone()
+	clone.Args = Args(clone.Args)
+	clone.Env = Env(clone.Env)
+	clone.URL = URL(clone.URL)
+	clone.Headers = Headers(clone.Headers)
+	clone.OAuth = OAuth(clone.OAuth)
+
+	return clone
+}
+
+// MCPServer is Server for an mcp.json entry.
+func MCPServer(s *config.MCPServer) *config.MCPServer {
+	if s == nil {
+		return nil
+	}
+
+	clone := s.Clone()
+	clone.Args = Args(clone.Args)
+	clone.Env = Env(clone.Env)
+	clone.URL = URL(clone.URL)
+	clone.Headers = Headers(clone.Headers)
+	clone.OAuth = OAuth(clone.OAuth)
+
+	return clone
+}
+
+// Servers applies Server to every server of servers.
+func Servers(servers map[string]*config.ServerConfig) map[string]*config.ServerConfig {
+	if servers == nil {
+		return nil
+	}
+
+	out := make(map[string]*config.ServerConfig, len(servers))
+	for name, srv := range servers {
+		out[name] = Server(srv)
+	}
+
+	return out
+}
+
+// Project returns a copy of p with its env values and server overrides
+// redacted.
+func Project(p *config.ProjectConfig) *config.ProjectConfig {
+	if p == nil {
+		return nil
+	}
+
+	clone := p.Clone()
+	clone.Env = Env(clone.Env)
+	clone.Servers = Servers(clone.Servers)
+
+	return clone
+}
+
+// Settings returns a copy of s with ACL tokens, the web UI token, webhook
+// URLs and the sensitive headers and URL credentials of event sinks and
+// OTel hidden.
+func Settings(s *config.Settings) *config.Settings {
+	if s == nil {
+		return nil
+	}
+
+	clone := *s
+
+	if s.ACL != nil {
+		clone.ACL = s.ACL.Clone()
+		for i := range clone.ACL.Tokens {
+			clone.ACL.Tokens[i].Token = Value(clone.ACL.Tokens[i].Token)
+		}
+	}
+
+	if s.WebUI != nil {
+		clone.WebUI = s.WebUI.Clone()
+		clone.WebUI.Token = Value(clone.WebUI.Token)
+	}
+
+	if s.Events != nil {
+		clone.Events = s.Events.Clone()
+		for _, sink := range clone.Events.Sinks {
+			sink.URL = Webhook(sink.URL)
+			sink.Headers = Headers(sink.Headers)
+			sink.Args = Args(sink.Args)
+		}
+	}
+
+	if s.OTel != nil {
+		clone.OTel = s.OTel.Clone()
+		clone.OTel.Endpoint = URL(clone.OTel.Endpoint)
+		clone.OTel.Headers = Headers(clone.OTel.Headers)
+	}
+
+	return &clone
+}
+
+// Config returns a copy of cfg with Servers, Projects, Settings and Auth
+// redacted.
+func Config(cfg *config.Config) *config.Config {
+	if cfg == nil {
+		return nil
+	}
+
+	clone := cfg.Clone()
+	clone.Servers = Servers(clone.Servers)
+	clone.Settings = Settings(clone.Settings)
+
+	for name, p := range clone.Projects {
+		clone.Projects[name] = Project(p)
+	}
+
+	for name, profile := range clone.Auth {
+		clone.Auth[name] = OAuth(profile)
+	}
+
+	return clone
+}
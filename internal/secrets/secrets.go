@@ -0,0 +1,172 @@
+// Package secrets resolves secret references in server env values, so tokens
+// do not have to be written into mcp.json or config.yaml:
+//
+//	env:
+//	  GITHUB_TOKEN: keyring://github_token   # stored with 'assern secret set'
+//	  SLACK_TOKEN: op://work/slack/token     # read with the 1Password CLI
+//
+// A Resolver hands each reference to the Provider of its scheme. The keyring
+// scheme is served by a Store, the OS keyring or, with settings.secrets_store
+// "env", the environment only.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// Reference schemes.
+const (
+	// SchemeKeyring references a secret of the configured Store by name,
+	// e.g. keyring://github_token.
+	SchemeKeyring = "keyring"
+	// SchemeOnePassword references a 1Password field, e.g.
+	// op://vault/item/field, read with the op CLI.
+	SchemeOnePassword = "op"
+)
+
+var (
+	// ErrNotFound indicates a referenced secret does not exist.
+	ErrNotFound = errors.New("secret not found")
+
+	// ErrReadOnly indicates the store cannot be written to.
+	ErrReadOnly = errors.New("secrets store is read-only")
+
+	// ErrInvalidName indicates a secret name the stores do not accept.
+	ErrInvalidName = errors.New("secret names may only contain letters, digits, \".\", \"_\" and \"-\"")
+)
+
+// validName matches the secret names a Store accepts.
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ValidateName checks a secret name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	return nil
+}
+
+// Provider resolves the references of one scheme.
+type Provider interface {
+	// Resolve returns the secret ref (the full reference, scheme included)
+	// points at.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// Store keeps named secrets for keyring:// references and the
+// 'assern secret' commands.
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+// NewStore returns the store named by settings.secrets_store.
+func NewStore(kind string) (Store, error) {
+	switch kind {
+	case "", config.SecretsStoreKeyring:
+		return KeyringStore{}, nil
+	case config.SecretsStoreEnv:
+		return EnvStore{}, nil
+	default:
+		return nil, config.ValidateSecretsStore(kind)
+	}
+}
+
+// Resolver resolves secret references through the providers of their
+// schemes.
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver returns a resolver serving keyring:// from store and op://
+// with the 1Password CLI.
+func NewResolver(store Store) *Resolver {
+	return &Resolver{providers: map[string]Provider{
+		SchemeKeyring:     storeProvider{store: store},
+		SchemeOnePassword: onePassword{command: "op"},
+	}}
+}
+
+// Register adds or replaces the provider of scheme.
+func (r *Resolver) Register(scheme string, provider Provider) {
+	r.providers[scheme] = provider
+}
+
+// IsRef reports whether value is a reference of a registered scheme.
+func (r *Resolver) IsRef(value string) bool {
+	scheme, _, found := strings.Cut(value, "://")
+
+	return found && r.providers[scheme] != nil
+}
+
+// Resolve returns the secret ref points at.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, _, _ := strings.Cut(ref, "://")
+
+	provider, ok := r.providers[scheme]
+	if !ok {
+		return "", fmt.Errorf("%s: unknown secret scheme %q", ref, scheme)
+	}
+
+	value, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+
+	return value, nil
+}
+
+// ResolveEnv resolves the values of env that are secret references and
+// returns them by key; other values are left out. A nil Resolver resolves
+// nothing.
+func (r *Resolver) ResolveEnv(ctx context.Context, env map[string]string) (map[string]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	var resolved map[string]string
+
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		if !r.IsRef(env[key]) {
+			continue
+		}
+
+		value, err := r.Resolve(ctx, env[key])
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %w", key, err)
+		}
+
+		if resolved == nil {
+			resolved = make(map[string]string)
+		}
+
+		resolved[key] = value
+	}
+
+	return resolved, nil
+}
+
+// storeProvider resolves keyring://<name> from a Store.
+type storeProvider struct {
+	store Store
+}
+
+func (p storeProvider) Resolve(_ context.Context, ref string) (string, error) {
+	name := strings.TrimPrefix(ref, SchemeKeyring+"://")
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	return p.store.Get(name)
+}
@@ -0,0 +1,134 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// staticProvider resolves every reference to its own text, reversed.
+type staticProvider struct{}
+
+func (staticProvider) Resolve(_ context.Context, ref string) (string, error) {
+	runes := []rune(ref)
+	slices.Reverse(runes)
+
+	return string(runes), nil
+}
+
+func TestResolveEnv(t *testing.T) {
+	keyring.MockInit()
+
+	store := KeyringStore{}
+	if err := store.Set("github_token", "ghp_secret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	resolver := NewResolver(store)
+	resolver.Register("test", staticProvider{})
+
+	got, err := resolver.ResolveEnv(t.Context(), map[string]string{
+		"GITHUB_TOKEN": "keyring://github_token",
+		"CUSTOM":       "test://abc",
+		"HOME_DIR":     "${HOME}/x",
+		"URL":          "https://example.com",
+	})
+	if err != nil {
+		t.Fatalf("ResolveEnv: %v", err)
+	}
+
+	want := map[string]string{"GITHUB_TOKEN": "ghp_secret", "CUSTOM": "cba//:tset"}
+	if !maps.Equal(got, want) {
+		t.Errorf("ResolveEnv() = %v, want %v", got, want)
+	}
+
+	_, err = resolver.ResolveEnv(t.Context(), map[string]string{"TOKEN": "keyring://missing"})
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "env TOKEN: keyring://missing") {
+		t.Errorf("missing secret error = %v, want ErrNotFound naming the variable", err)
+	}
+
+	var nilResolver *Resolver
+	if got, err := nilResolver.ResolveEnv(t.Context(), map[string]string{"T": "keyring://x"}); got != nil || err != nil {
+		t.Errorf("nil Resolver = %v, %v; want nothing resolved", got, err)
+	}
+}
+
+func TestKeyringStore(t *testing.T) {
+	keyring.MockInit()
+
+	store := KeyringStore{}
+
+	for _, name := range []string{"slack", "github_token", "slack"} {
+		if err := store.Set(name, "v-"+name); err != nil {
+			t.Fatalf("Set(%q): %v", name, err)
+		}
+	}
+
+	names, err := store.List()
+	if err != nil || !slices.Equal(names, []string{"github_token", "slack"}) {
+		t.Fatalf("List() = %v, %v; want [github_token slack]", names, err)
+	}
+
+	if err := store.Delete("slack"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := store.Get("slack"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+
+	if names, _ := store.List(); !slices.Equal(names, []string{"github_token"}) {
+		t.Errorf("List() after Delete = %v", names)
+	}
+
+	if err := store.Set("../etc", "x"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Set with invalid name error = %v, want ErrInvalidName", err)
+	}
+}
+
+func TestEnvStore(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "from-env")
+
+	resolver := NewResolver(EnvStore{})
+
+	got, err := resolver.Resolve(t.Context(), "keyring://github-token")
+	if err != nil || got != "from-env" {
+		t.Errorf("Resolve() = %q, %v; want the GITHUB_TOKEN value", got, err)
+	}
+
+	if err := (EnvStore{}).Set("github_token", "x"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Set() error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestOnePassword(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the op CLI")
+	}
+
+	// A stand-in for op that echoes the reference it was asked to read
+	op := filepath.Join(t.TempDir(), "op")
+	script := "#!/bin/sh\n[ \"$1 $2\" = 'read --no-newline' ] || exit 2\nprintf 'secret-for-%s' \"$3\"\n"
+
+	if err := os.WriteFile(op, []byte(script), 0o755); err != nil {
+		t.Fatalf("writing fake op: %v", err)
+	}
+
+	resolver := NewResolver(EnvStore{})
+	resolver.Register(SchemeOnePassword, onePassword{command: op})
+
+	got, err := resolver.Resolve(t.Context(), "op://work/slack/token")
+	if err != nil || got != "secret-for-op://work/slack/token" {
+		t.Errorf("Resolve() = %q, %v", got, err)
+	}
+}
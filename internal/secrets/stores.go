@@ -0,0 +1,176 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringService is the service name secrets are filed under in the OS
+// keyring.
+const KeyringService = "assern"
+
+// keyringIndex is the keyring entry listing the stored names, since OS
+// keyrings cannot be enumerated portably. Its name is not a valid secret
+// name, so it cannot be overwritten.
+const keyringIndex = "assern:index"
+
+// KeyringStore keeps secrets in the OS keyring.
+type KeyringStore struct{}
+
+// Get returns the secret name.
+func (KeyringStore) Get(name string) (string, error) {
+	value, err := keyring.Get(KeyringService, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("%w: %s (store it with 'assern secret set %s')", ErrNotFound, name, name)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("reading keyring: %w", err)
+	}
+
+	return value, nil
+}
+
+// Set stores the secret name, replacing any previous value.
+func (s KeyringStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	if err := keyring.Set(KeyringService, name, value); err != nil {
+		return fmt.Errorf("writing keyring: %w", err)
+	}
+
+	return s.updateIndex(func(names []string) []string {
+		if slices.Contains(names, name) {
+			return names
+		}
+
+		return append(names, name)
+	})
+}
+
+// Delete removes the secret name.
+func (s KeyringStore) Delete(name string) error {
+	err := keyring.Delete(KeyringService, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		err = fmt.Errorf("%w: %s", ErrNotFound, name)
+	} else if err != nil {
+		err = fmt.Errorf("writing keyring: %w", err)
+	}
+
+	// Drop the name even when the entry was removed by other means
+	if indexErr := s.updateIndex(func(names []string) []string {
+		return slices.DeleteFunc(names, func(n string) bool { return n == name })
+	}); indexErr != nil && err == nil {
+		err = indexErr
+	}
+
+	return err
+}
+
+// List returns the names of the stored secrets, sorted.
+func (KeyringStore) List() ([]string, error) {
+	index, err := keyring.Get(KeyringService, keyringIndex)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading keyring: %w", err)
+	}
+
+	names := strings.Fields(index)
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// updateIndex rewrites the index entry with the names update returns.
+func (s KeyringStore) updateIndex(update func([]string) []string) error {
+	names, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	names = update(names)
+	slices.Sort(names)
+
+	if len(names) == 0 {
+		if err := keyring.Delete(KeyringService, keyringIndex); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("writing keyring: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := keyring.Set(KeyringService, keyringIndex, strings.Join(names, "\n")); err != nil {
+		return fmt.Errorf("writing keyring: %w", err)
+	}
+
+	return nil
+}
+
+// EnvStore reads secrets from environment variables (see EnvName), for CI
+// runners and containers without a keyring. It is read-only.
+type EnvStore struct{}
+
+// EnvName returns the environment variable EnvStore reads the secret name
+// from: the name in upper case with "." and "-" as "_".
+func EnvName(name string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+}
+
+// Get returns the secret name from the environment.
+func (EnvStore) Get(name string) (string, error) {
+	value, ok := os.LookupEnv(EnvName(name))
+	if !ok {
+		return "", fmt.Errorf("%w: %s (set %s)", ErrNotFound, name, EnvName(name))
+	}
+
+	return value, nil
+}
+
+// Set fails: the environment store is read-only.
+func (EnvStore) Set(string, string) error { return ErrReadOnly }
+
+// Delete fails: the environment store is read-only.
+func (EnvStore) Delete(string) error { return ErrReadOnly }
+
+// List fails: the environment store cannot tell secrets from other
+// variables.
+func (EnvStore) List() ([]string, error) {
+	return nil, fmt.Errorf("%w: secrets are read from environment variables", ErrReadOnly)
+}
+
+// onePassword resolves op:// references with the 1Password CLI, which
+// handles sign-in and biometric unlock itself.
+type onePassword struct {
+	command string
+}
+
+func (p onePassword) Resolve(ctx context.Context, ref string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.command, "read", "--no-newline", ref)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", p.command, err, msg)
+		}
+
+		return "", fmt.Errorf("%s: %w", p.command, err)
+	}
+
+	return stdout.String(), nil
+}
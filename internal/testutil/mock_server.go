@@ -29,6 +29,8 @@ type MockServer struct {
 
 	// Configurable responses
 	ToolResults map[string]*mcp.CallToolResult
+	// ResourceContents replaces the mock content read from a URI
+	ResourceContents map[string][]mcp.ResourceContents
 
 	// Call tracking
 	mu            sync.RWMutex
@@ -170,6 +172,10 @@ func (m *MockServer) ReadResource(ctx context.Context, uri string) (*mcp.ReadRes
 		return nil, m.ResourcesErr
 	}
 
+	if contents, ok := m.ResourceContents[uri]; ok {
+		return &mcp.ReadResourceResult{Contents: contents}, nil
+	}
+
 	return &mcp.ReadResourceResult{
 		Contents: []mcp.ResourceContents{
 			mcp.TextResourceContents{
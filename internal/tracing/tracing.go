@@ -0,0 +1,64 @@
+// Package tracing maps assern's tool calls and backend connections to
+// OpenTelemetry span attributes. Spans are recorded and exported by the
+// OpenTelemetry SDK; this package only names what they carry:
+//
+//	ctx, span := tracer.Start(ctx, "tools/call github_search",
+//		trace.WithSpanKind(trace.SpanKindServer),
+//		trace.WithAttributes(tracing.ToolCall("github_search", "github")...))
+//	defer span.End()
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+
+	"github.com/valksor/go-assern/internal/version"
+)
+
+// ScopeName is the instrumentation scope of assern's spans.
+const ScopeName = "github.com/valksor/go-assern"
+
+// Span attribute keys. The mcp.* keys follow the OpenTelemetry semantic
+// conventions for MCP; the assern.* ones are assern's own.
+const (
+	MethodName    = attribute.Key("mcp.method.name")
+	ToolName      = attribute.Key("mcp.tool.name")
+	SessionID     = attribute.Key("mcp.session.id")
+	Server        = attribute.Key("assern.server")
+	Transport     = attribute.Key("assern.transport")
+	Client        = attribute.Key("assern.client")
+	Identity      = attribute.Key("assern.identity")
+	CorrelationID = attribute.Key("assern.correlation_id")
+)
+
+// methodToolsCall is the MCP method of tool calls.
+const methodToolsCall = "tools/call"
+
+// ToolCall returns the attributes of a call of tool on server, the
+// prefixed name for a client's call and the backend's name for a backend
+// call.
+func ToolCall(tool, server string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		MethodName.String(methodToolsCall),
+		ToolName.String(tool),
+		Server.String(server),
+	}
+}
+
+// Connection returns the attributes of a connection to server over
+// transport.
+func Connection(server, transport string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		Server.String(server),
+		Transport.String(transport),
+	}
+}
+
+// Resource describes assern, running as service, to the collector.
+func Resource(service string) *resource.Resource {
+	return resource.NewSchemaless(
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(version.Version),
+	)
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/valksor/go-assern/internal/version"
+)
+
+func TestToolCall(t *testing.T) {
+	t.Parallel()
+
+	got := ToolCall("github_search", "github")
+	want := []attribute.KeyValue{
+		attribute.String("mcp.method.name", "tools/call"),
+		attribute.String("mcp.tool.name", "github_search"),
+		attribute.String("assern.server", "github"),
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("ToolCall() = %v, want %v", got, want)
+	}
+}
+
+func TestConnection(t *testing.T) {
+	t.Parallel()
+
+	got := Connection("github", "stdio")
+	want := []attribute.KeyValue{
+		attribute.String("assern.server", "github"),
+		attribute.String("assern.transport", "stdio"),
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Connection() = %v, want %v", got, want)
+	}
+}
+
+func TestResource(t *testing.T) {
+	t.Parallel()
+
+	res := Resource("gateway")
+
+	for key, want := range map[attribute.Key]string{
+		"service.name":    "gateway",
+		"service.version": version.Version,
+	} {
+		if got, ok := res.Set().Value(key); !ok || got.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, got.AsString(), want)
+		}
+	}
+}
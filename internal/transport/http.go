@@ -0,0 +1,272 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+)
+
+// Paths served by HTTPServer.
+const (
+	// StreamableHTTPPath is the Streamable HTTP endpoint.
+	StreamableHTTPPath = "/mcp"
+	// SSEPath and SSEMessagePath are the legacy HTTP+SSE endpoints.
+	SSEPath        = "/sse"
+	SSEMessagePath = "/message"
+)
+
+// Authenticator checks the bearer tokens of HTTP requests against the
+// settings.acl tokens; *aggregator.Aggregator implements it.
+type Authenticator interface {
+	// ACLEnabled reports whether requests must present a token.
+	ACLEnabled() bool
+	// Authenticate returns the name of the token, false if it is unknown.
+	Authenticate(token string) (string, bool)
+}
+
+// httpShutdownTimeout bounds how long Stop waits for open requests.
+const httpShutdownTimeout = 5 * time.Second
+
+// HTTPServer serves an MCP server over Streamable HTTP and the legacy SSE
+// transport on one listener, so remote clients can share the instance.
+type HTTPServer struct {
+	addr       string
+	mcpServer  *server.MCPServer
+	streamable *server.StreamableHTTPServer
+	sse        *server.SSEServer
+	logger     *slog.Logger
+
+	// ids names new sessions; nil uses mcp-go's generators
+	ids aggregator.SessionIDFunc
+
+	// sessions caps the open sessions (see SetMaxSessions)
+	sessions *sessionLimit
+
+	// auth, when set, rejects requests without a known ACL token
+	auth Authenticator
+
+	srv      *http.Server
+	listener net.Listener
+	done     chan error
+}
+
+// NewHTTPServer creates an HTTP server for mcpServer listening on addr.
+func NewHTTPServer(addr string, mcpServer *server.MCPServer, logger *slog.Logger) *HTTPServer {
+	h := &HTTPServer{
+		addr:      addr,
+		mcpServer: mcpServer,
+		logger:    logger,
+		sessions:  newSessionLimit(),
+		done:      make(chan error, 1),
+	}
+
+	return h
+}
+
+// build creates the Streamable HTTP and SSE servers.
+func (h *HTTPServer) build() {
+	sse := []server.SSEOption{
+		server.WithSSEEndpoint(SSEPath),
+		server.WithMessageEndpoint(SSEMessagePath),
+		server.WithUseFullURLForMessageEndpoint(false),
+	}
+
+	// mcp-go's default manager, which does not track sessions
+	var ids server.SessionIdManager = &server.StatelessGeneratingSessionIdManager{}
+
+	if h.ids != nil {
+		ids = sessionIDManager(h.ids)
+		sse = append(sse, server.WithSessionIDGenerator(func(context.Context, *http.Request) (string, error) {
+			return h.ids(aggregator.SessionSSE), nil
+		}))
+	}
+
+	h.streamable = server.NewStreamableHTTPServer(h.mcpServer,
+		server.WithEndpointPath(StreamableHTTPPath),
+		server.WithSessionIdManager(endingIDManager{SessionIdManager: ids, limit: h.sessions}),
+		server.WithSessionIdleTTL(httpSessionIdleTTL),
+	)
+	h.sse = server.NewSSEServer(h.mcpServer, sse...)
+}
+
+// SetSessionIDs names new Streamable HTTP and SSE sessions with ids instead
+// of mcp-go's UUIDs. Call before Start.
+func (h *HTTPServer) SetSessionIDs(ids aggregator.SessionIDFunc) {
+	h.ids = ids
+}
+
+// sessionIDManager generates Streamable HTTP session IDs with a
+// SessionIDFunc. Like mcp-go's default manager it does not track sessions,
+// so any non-empty ID is accepted.
+type sessionIDManager aggregator.SessionIDFunc
+
+func (m sessionIDManager) Generate() string {
+	return m(aggregator.SessionHTTP)
+}
+
+func (m sessionIDManager) Validate(sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, errors.New("empty session id")
+	}
+
+	return false, nil
+}
+
+func (m sessionIDManager) Terminate(string) (bool, error) {
+	return false, nil
+}
+
+// SetMaxSessions caps the open sessions: Streamable HTTP sessions, from
+// initialize until the client deletes them or they go unused for
+// httpSessionIdleTTL, and SSE streams. A new session beyond it gets 503
+// Service Unavailable with a JSON-RPC error. Requests within a session are
+// not limited. Zero disables the limit. Call before Start.
+func (h *HTTPServer) SetMaxSessions(n int) {
+	h.sessions.max = n
+}
+
+// SetAuthenticator makes requests authenticate with a bearer token while
+// auth has ACL tokens configured. Call before Start.
+func (h *HTTPServer) SetAuthenticator(auth Authenticator) {
+	h.auth = auth
+}
+
+// Handler returns the HTTP handler routing the MCP endpoints. Call it once,
+// after the Set methods.
+func (h *HTTPServer) Handler() http.Handler {
+	h.build()
+
+	mux := http.NewServeMux()
+	mux.Handle(StreamableHTTPPath, h.streamable)
+	mux.Handle(SSEPath, h.sse.SSEHandler())
+	mux.Handle(SSEMessagePath, h.sse.MessageHandler())
+
+	return h.limit(h.authenticate(mux))
+}
+
+// authenticate rejects requests without a known bearer token when ACL
+// tokens are configured, and passes the token's name on to the aggregator
+// (see aggregator.WithIdentity). The ACL is checked per request, so a
+// reload changing it applies to open sessions too.
+func (h *HTTPServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.auth == nil || !h.auth.ACLEnabled() {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		identity, ok := h.auth.Authenticate(bearerToken(r))
+		if !ok {
+			h.logger.Warn("rejected unauthenticated HTTP request", "remote", r.RemoteAddr, "path", r.URL.Path)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="assern"`)
+			http.Error(w, "missing or unknown bearer token", http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(aggregator.WithIdentity(r.Context(), identity)))
+	})
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header, or "".
+func bearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// Start listens on the configured address and serves in the background.
+// Errors binding the address are returned; later serve errors are reported
+// by Wait.
+func (h *HTTPServer) Start() error {
+	listener, err := net.Listen("tcp", h.addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", h.addr, err)
+	}
+
+	h.listener = listener
+	h.srv = &http.Server{
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		err := h.srv.Serve(listener)
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+
+		h.done <- err
+	}()
+
+	h.logger.Info("serving MCP over HTTP",
+		"address", listener.Addr().String(),
+		"streamable", StreamableHTTPPath,
+		"sse", SSEPath,
+	)
+
+	authenticated := h.auth != nil && h.auth.ACLEnabled()
+	if host, _, _ := net.SplitHostPort(h.addr); !isLoopback(host) && !authenticated {
+		h.logger.Warn("HTTP listener is reachable from other machines and has no authentication",
+			"address", listener.Addr().String())
+	}
+
+	return nil
+}
+
+// Addr returns the address the server listens on, or "" before Start.
+func (h *HTTPServer) Addr() string {
+	if h.listener == nil {
+		return ""
+	}
+
+	return h.listener.Addr().String()
+}
+
+// Wait blocks until the server stops and returns its serve error, if any.
+func (h *HTTPServer) Wait() error {
+	return <-h.done
+}
+
+// Stop closes open SSE streams and shuts the server down.
+func (h *HTTPServer) Stop() error {
+	if h.srv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+
+	h.sse.CloseSessions()
+
+	if err := h.streamable.Shutdown(ctx); err != nil {
+		h.logger.Debug("error stopping streamable HTTP sessions", "error", err)
+	}
+
+	return h.srv.Shutdown(ctx)
+}
+
+// isLoopback reports whether host only accepts local connections. An empty
+// host listens on all interfaces.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
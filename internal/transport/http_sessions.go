@@ -0,0 +1,182 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// codeSessionLimit is the JSON-RPC error code of a session refused by the
+// session limit, the same the instance socket uses.
+const codeSessionLimit = -32001
+
+// httpSessionIdleTTL is how long a Streamable HTTP session may go without
+// requests before it is ended, so clients that go away without deleting
+// theirs do not hold a slot of the session limit forever.
+const httpSessionIdleTTL = 30 * time.Minute
+
+// sessionLimit counts the open sessions of an HTTPServer: Streamable HTTP
+// sessions from their initialize request until the client deletes them or
+// they expire, and SSE streams while they are connected.
+type sessionLimit struct {
+	max int // zero = unlimited
+
+	mu   sync.Mutex
+	open int
+	ids  map[string]struct{} // Streamable HTTP sessions holding a slot
+}
+
+func newSessionLimit() *sessionLimit {
+	return &sessionLimit{ids: make(map[string]struct{})}
+}
+
+// acquire takes a slot for a new session, false if all are taken.
+func (l *sessionLimit) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && l.open >= l.max {
+		return false
+	}
+
+	l.open++
+
+	return true
+}
+
+// release gives a slot back.
+func (l *sessionLimit) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.open--
+}
+
+// hold passes a slot taken by acquire on to the Streamable HTTP session id,
+// which keeps it until end.
+func (l *sessionLimit) hold(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.ids[id]; ok {
+		l.open--
+
+		return
+	}
+
+	l.ids[id] = struct{}{}
+}
+
+// end gives back the slot of the Streamable HTTP session id, if it holds one.
+func (l *sessionLimit) end(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.ids[id]; ok {
+		delete(l.ids, id)
+		l.open--
+	}
+}
+
+// endingIDManager ends the session limit's hold on a Streamable HTTP session
+// when mcp-go terminates it, on a DELETE request or after
+// httpSessionIdleTTL.
+type endingIDManager struct {
+	server.SessionIdManager
+
+	limit *sessionLimit
+}
+
+func (m endingIDManager) Terminate(sessionID string) (bool, error) {
+	notAllowed, err := m.SessionIdManager.Terminate(sessionID)
+	if err == nil && !notAllowed {
+		m.limit.end(sessionID)
+	}
+
+	return notAllowed, err
+}
+
+// limit refuses new sessions beyond maxSessions with a JSON-RPC error.
+// Requests within a session are not counted.
+func (h *HTTPServer) limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, opens := opensSession(r)
+		if !opens {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if !h.sessions.acquire() {
+			h.logger.Warn("rejected HTTP session", "remote", r.RemoteAddr, "limit", h.sessions.max)
+			writeSessionLimitError(w, id, h.sessions.max)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+
+		// An initialize request that got a session ID opened a Streamable
+		// HTTP session; a stream closes with its request
+		if sessionID := w.Header().Get(server.HeaderKeySessionID); r.Method == http.MethodPost && sessionID != "" {
+			h.sessions.hold(sessionID)
+		} else {
+			h.sessions.release()
+		}
+	})
+}
+
+// opensSession reports whether r opens a session: an initialize request
+// (whose JSON-RPC ID it returns) or a stream without a session ID.
+func opensSession(r *http.Request) (any, bool) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == SSEPath:
+		return nil, true
+	case r.Method == http.MethodGet && r.URL.Path == StreamableHTTPPath:
+		return nil, r.Header.Get(server.HeaderKeySessionID) == ""
+	case r.Method != http.MethodPost || r.URL.Path != StreamableHTTPPath:
+		return nil, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return nil, false
+	}
+
+	var req struct {
+		ID     any    `json:"id"`
+		Method string `json:"method"`
+	}
+
+	if json.Unmarshal(body, &req) != nil || req.Method != string(mcp.MethodInitialize) {
+		return nil, false
+	}
+
+	return req.ID, true
+}
+
+// writeSessionLimitError answers a refused session with 503 Service
+// Unavailable and a JSON-RPC error for the request id.
+func writeSessionLimitError(w http.ResponseWriter, id any, limit int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"error": map[string]any{
+			"code":    codeSessionLimit,
+			"message": fmt.Sprintf("too many concurrent sessions (limit %d)", limit),
+		},
+	})
+}
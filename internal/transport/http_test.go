@@ -0,0 +1,404 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	mcptransport "github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/testutil"
+)
+
+// newHTTPTestServer serves an aggregator with one mock backend over
+// HTTPServer's handler, after configure (if any) adjusts the server.
+func newHTTPTestServer(t *testing.T, configure func(*HTTPServer)) *httptest.Server {
+	t.Helper()
+
+	return newHTTPTestServerWith(t, config.DefaultSettings(), configure)
+}
+
+// newHTTPTestServerWith is newHTTPTestServer with the aggregator's settings.
+func newHTTPTestServerWith(t *testing.T, settings *config.Settings, configure func(*HTTPServer)) *httptest.Server {
+	t.Helper()
+
+	agg, err := aggregator.New(aggregator.Options{
+		Config: &config.Config{Servers: map[string]*config.ServerConfig{}, Settings: settings},
+		Logger: slog.New(slog.DiscardHandler),
+	})
+	if err != nil {
+		t.Fatalf("aggregator.New: %v", err)
+	}
+
+	mock := testutil.NewMockServer("github", []mcp.Tool{mcp.NewTool("search_repos"), mcp.NewTool("delete_repo")})
+	if err := mock.Start(t.Context()); err != nil {
+		t.Fatalf("mock.Start: %v", err)
+	}
+
+	if err := agg.AddServer(t.Context(), mock); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	h := NewHTTPServer("127.0.0.1:0", agg.CreateMCPServer(), slog.New(slog.DiscardHandler))
+	h.SetAuthenticator(agg)
+
+	if configure != nil {
+		configure(h)
+	}
+
+	ts := httptest.NewServer(h.Handler())
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestHTTPServerTransports(t *testing.T) {
+	t.Parallel()
+
+	ts := newHTTPTestServer(t, nil)
+
+	tests := []struct {
+		name    string
+		connect func() (*client.Client, error)
+	}{
+		{
+			name:    "streamable http",
+			connect: func() (*client.Client, error) { return client.NewStreamableHttpClient(ts.URL + StreamableHTTPPath) },
+		},
+		{
+			name:    "sse",
+			connect: func() (*client.Client, error) { return client.NewSSEMCPClient(ts.URL + SSEPath) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			c, err := tt.connect()
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			defer func() { _ = c.Close() }()
+
+			if err := c.Start(ctx); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+
+			initReq := mcp.InitializeRequest{}
+			initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+			initReq.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "1"}
+
+			if _, err := c.Initialize(ctx, initReq); err != nil {
+				t.Fatalf("Initialize: %v", err)
+			}
+
+			result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatalf("ListTools: %v", err)
+			}
+
+			if !slices.ContainsFunc(result.Tools, func(tool mcp.Tool) bool { return tool.Name == "github_search_repos" }) {
+				t.Errorf("tools/list over %s missing github_search_repos: %v", tt.name, result.Tools)
+			}
+		})
+	}
+}
+
+func TestHTTPServerSessionLimit(t *testing.T) {
+	t.Parallel()
+
+	ts := newHTTPTestServer(t, func(h *HTTPServer) { h.SetMaxSessions(1) })
+
+	// A Streamable HTTP session holds the only slot across its requests
+	c, err := initializeClient(t, ts.URL)
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	for range 3 {
+		if _, err := c.ListTools(t.Context(), mcp.ListToolsRequest{}); err != nil {
+			t.Fatalf("ListTools within the session: %v", err)
+		}
+	}
+
+	checkSessionLimitError(t, postInitialize(t, ts.URL, 7), float64(7))
+
+	resp, err := http.Get(ts.URL + SSEPath)
+	if err != nil {
+		t.Fatalf("opening SSE stream: %v", err)
+	}
+
+	checkSessionLimitError(t, resp, nil)
+
+	// Deleting the session frees the slot for an SSE stream
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	stream, err := http.Get(ts.URL + SSEPath)
+	if err != nil {
+		t.Fatalf("opening SSE stream: %v", err)
+	}
+	defer func() { _ = stream.Body.Close() }()
+
+	if stream.StatusCode != http.StatusOK {
+		t.Fatalf("SSE stream status = %d, want 200", stream.StatusCode)
+	}
+
+	checkSessionLimitError(t, postInitialize(t, ts.URL, "second"), "second")
+}
+
+// initializeClient opens a Streamable HTTP session on the server at url.
+func initializeClient(t *testing.T, url string) (*client.Client, error) {
+	t.Helper()
+
+	c, err := client.NewStreamableHttpClient(url + StreamableHTTPPath)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+
+	if err := c.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	initReq := mcp.InitializeRequest{}
+	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+
+	if _, err := c.Initialize(t.Context(), initReq); err != nil {
+		_ = c.Close()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// postInitialize sends an initialize request with id to the Streamable
+// HTTP endpoint of the server at url.
+func postInitialize(t *testing.T, url string, id any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "initialize",
+		"params":  map[string]any{"protocolVersion": mcp.LATEST_PROTOCOL_VERSION},
+	})
+	if err != nil {
+		t.Fatalf("encoding initialize: %v", err)
+	}
+
+	resp, err := http.Post(url+StreamableHTTPPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	return resp
+}
+
+// checkSessionLimitError checks that resp refused a session with a JSON-RPC
+// error for the request id.
+func checkSessionLimitError(t *testing.T, resp *http.Response, id any) {
+	t.Helper()
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	var body struct {
+		ID    any `json:"id"`
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding error response: %v", err)
+	}
+
+	if body.ID != id || body.Error.Code != codeSessionLimit || body.Error.Message != "too many concurrent sessions (limit 1)" {
+		t.Errorf("error response = %+v, want a session limit error for id %v", body, id)
+	}
+}
+
+func TestHTTPServerSessionIDs(t *testing.T) {
+	t.Parallel()
+
+	ts := newHTTPTestServer(t, func(h *HTTPServer) {
+		h.SetSessionIDs(aggregator.NewSessionIDFunc("devbox/", config.SessionFormatSequential))
+	})
+
+	c, err := client.NewStreamableHttpClient(ts.URL + StreamableHTTPPath)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if err := c.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	initReq := mcp.InitializeRequest{}
+	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+
+	if _, err := c.Initialize(t.Context(), initReq); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	if got := c.GetSessionId(); got != "devbox/http-1" {
+		t.Errorf("streamable session id = %q, want devbox/http-1", got)
+	}
+
+	if _, err := c.ListTools(t.Context(), mcp.ListToolsRequest{}); err != nil {
+		t.Errorf("ListTools with the custom session id: %v", err)
+	}
+
+	// The SSE endpoint event carries the session id
+	stream, err := http.Get(ts.URL + SSEPath)
+	if err != nil {
+		t.Fatalf("opening SSE stream: %v", err)
+	}
+	defer func() { _ = stream.Body.Close() }()
+
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		if endpoint, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			if !strings.Contains(endpoint, "sessionId=devbox/sse-2") {
+				t.Errorf("SSE endpoint = %q, want session id devbox/sse-2", endpoint)
+			}
+
+			return
+		}
+	}
+
+	t.Error("no SSE endpoint event")
+}
+
+func TestHTTPServerACL(t *testing.T) {
+	t.Parallel()
+
+	settings := config.DefaultSettings()
+	settings.ACL = &config.ACLConfig{Tokens: []config.ACLToken{
+		{Name: "alice", Token: "alice-secret"},
+		{Name: "bob", Token: "bob-secret", Servers: []string{"github"}, Tools: []string{"search_*"}},
+	}}
+
+	ts := newHTTPTestServerWith(t, settings, nil)
+
+	for _, token := range []string{"", "wrong"} {
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, ts.URL+SSEPath, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("request with token %q: status = %d, want 401", token, resp.StatusCode)
+		}
+	}
+
+	tests := []struct {
+		token       string
+		wantTools   []string
+		wantDeleted bool // whether github_delete_repo may be called
+	}{
+		{token: "alice-secret", wantTools: []string{"github_delete_repo", "github_search_repos"}, wantDeleted: true},
+		{token: "bob-secret", wantTools: []string{"github_search_repos"}, wantDeleted: false},
+	}
+
+	for _, tt := range tests {
+		c, err := client.NewStreamableHttpClient(ts.URL+StreamableHTTPPath,
+			mcptransport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + tt.token}))
+		if err != nil {
+			t.Fatalf("creating client: %v", err)
+		}
+		defer func() { _ = c.Close() }()
+
+		if err := c.Start(t.Context()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+
+		initReq := mcp.InitializeRequest{}
+		initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+
+		if _, err := c.Initialize(t.Context(), initReq); err != nil {
+			t.Fatalf("Initialize: %v", err)
+		}
+
+		result, err := c.ListTools(t.Context(), mcp.ListToolsRequest{})
+		if err != nil {
+			t.Fatalf("ListTools: %v", err)
+		}
+
+		var tools []string
+
+		for _, tool := range result.Tools {
+			if strings.HasPrefix(tool.Name, "github_") {
+				tools = append(tools, tool.Name)
+			}
+		}
+
+		slices.Sort(tools)
+
+		if !slices.Equal(tools, tt.wantTools) {
+			t.Errorf("%s: tools = %v, want %v", tt.token, tools, tt.wantTools)
+		}
+
+		callReq := mcp.CallToolRequest{}
+		callReq.Params.Name = "github_delete_repo"
+
+		call, err := c.CallTool(t.Context(), callReq)
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+
+		if call.IsError == tt.wantDeleted {
+			t.Errorf("%s: calling github_delete_repo IsError = %v, want %v", tt.token, call.IsError, !tt.wantDeleted)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "", want: false},
+		{host: "0.0.0.0", want: false},
+		{host: "localhost", want: true},
+		{host: "127.0.0.1", want: true},
+		{host: "::1", want: true},
+		{host: "192.168.1.10", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopback(tt.host); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package transport
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -30,6 +31,17 @@ func ServeStdio(ctx context.Context, agg *aggregator.Aggregator, logger *slog.Lo
 // ServeStdioWithServer serves an existing MCP server over stdio.
 // This allows the MCP server to be shared with other transports (e.g., socket).
 func ServeStdioWithServer(ctx context.Context, agg *aggregator.Aggregator, mcpServer *server.MCPServer, logger *slog.Logger) error {
+	return ServeStdioFrom(ctx, agg, mcpServer, os.Stdin, logger)
+}
+
+// ServeStdioFrom is ServeStdioWithServer reading client messages from in
+// instead of os.Stdin, e.g. what follows a configuration sent on stdin.
+func ServeStdioFrom(ctx context.Context, agg *aggregator.Aggregator, mcpServer *server.MCPServer, in io.Reader, logger *slog.Logger) error {
+	// Buffer per settings.stdio
+	in = newStdioReader(in, agg.StdioConfig())
+	out, flush := newStdioWriter(os.Stdout, agg.StdioConfig())
+	defer func() { _ = flush() }()
+
 	// Setup signal handlers
 	shutdownCh := make(chan os.Signal, 1)
 	reloadCh := make(chan os.Signal, 1)
@@ -47,6 +59,8 @@ func ServeStdioWithServer(ctx context.Context, agg *aggregator.Aggregator, mcpSe
 					logger.Error("error stopping aggregator", "error", err)
 				}
 
+				_ = flush()
+
 				os.Exit(0)
 			case <-reloadCh:
 				logger.Info("received SIGHUP, reloading configuration")
@@ -66,10 +80,9 @@ func ServeStdioWithServer(ctx context.Context, agg *aggregator.Aggregator, mcpSe
 		}
 	}()
 
-	// Redirect any log output to stderr to keep stdout clean for MCP protocol
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	})))
+	// Send library log output where logger writes, never to stdout (the
+	// MCP protocol) and not to stderr when logger discards it
+	slog.SetDefault(logger)
 
 	logger.Info(
 		"starting MCP server on stdio",
@@ -83,11 +96,15 @@ func ServeStdioWithServer(ctx context.Context, agg *aggregator.Aggregator, mcpSe
 	// mcp-go's built-in stdio session does not. Drive stdio ourselves in that
 	// case; otherwise use the library's stdio server unchanged.
 	if agg.DiscoveryEnabled() {
-		return serveStdioWithDiscovery(ctx, mcpServer, logger)
+		return serveStdioWithDiscovery(ctx, mcpServer, in, out, logger)
 	}
 
-	// Start serving
-	if err := server.ServeStdio(mcpServer); err != nil {
+	// Start serving. Shutdown signals are handled above, so the stdio server
+	// runs until in is closed.
+	stdio := server.NewStdioServer(mcpServer)
+	stdio.SetErrorLogger(slog.NewLogLogger(logger.Handler(), slog.LevelError))
+
+	if err := stdio.Listen(context.Background(), in, out); err != nil {
 		return fmt.Errorf("serving stdio: %w", err)
 	}
 
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -142,8 +141,8 @@ func (l *lockedWriter) writeMessage(v any) error {
 // serveStdioWithDiscovery serves the MCP server over stdio using a tool-capable
 // session, enabling per-session progressive tool disclosure. It mirrors the
 // socket serve loop used for proxied clients.
-func serveStdioWithDiscovery(ctx context.Context, mcpServer *server.MCPServer, logger *slog.Logger) error {
-	return runSessionLoop(ctx, mcpServer, newStdioSession(), os.Stdin, os.Stdout, logger)
+func serveStdioWithDiscovery(ctx context.Context, mcpServer *server.MCPServer, in io.Reader, out io.Writer, logger *slog.Logger) error {
+	return runSessionLoop(ctx, mcpServer, newStdioSession(), in, out, logger)
 }
 
 // runSessionLoop registers session on mcpServer, then reads newline-delimited
@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// stdioWriter coalesces writes to the stdio client: messages are buffered
+// and written together at most interval after the first of them, or as soon
+// as the buffer fills. Bursts of small messages, such as progress
+// notifications, then cost one write instead of one each. It is safe for
+// concurrent use.
+type stdioWriter struct {
+	interval time.Duration
+
+	mu    sync.Mutex
+	buf   *bufio.Writer
+	timer *time.Timer
+	err   error // Last failed timed flush, returned by the next Write
+}
+
+// newStdioWriter returns w wrapped per cfg (settings.stdio), and a function
+// flushing what is still buffered. Without a flush interval w is returned
+// as is.
+func newStdioWriter(w io.Writer, cfg *config.StdioConfig) (io.Writer, func() error) {
+	if !cfg.Coalesces() {
+		return w, func() error { return nil }
+	}
+
+	sw := &stdioWriter{
+		interval: cfg.FlushInterval,
+		buf:      bufio.NewWriterSize(w, cfg.EffectiveWriteBuffer()),
+	}
+
+	return sw, sw.Flush
+}
+
+// newStdioReader returns r with a read buffer per cfg (settings.stdio).
+// mcp-go's stdio server keeps a *bufio.Reader it is given when it is large
+// enough.
+func newStdioReader(r io.Reader, cfg *config.StdioConfig) io.Reader {
+	if size := cfg.EffectiveReadBuffer(); size > 0 {
+		return bufio.NewReaderSize(r, size)
+	}
+
+	return r
+}
+
+func (w *stdioWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.err; err != nil {
+		w.err = nil
+
+		return 0, err
+	}
+
+	n, err := w.buf.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	if w.buf.Buffered() > 0 && w.timer == nil {
+		w.timer = time.AfterFunc(w.interval, w.flushTimed)
+	}
+
+	return n, nil
+}
+
+// flushTimed writes out the buffer when the flush interval has passed.
+func (w *stdioWriter) flushTimed() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.timer = nil
+
+	if err := w.buf.Flush(); err != nil {
+		w.err = err
+	}
+}
+
+// Flush writes out everything buffered, e.g. before exiting.
+func (w *stdioWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+
+	return w.buf.Flush()
+}
@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/valksor/go-assern/internal/config"
+)
+
+// countingWriter records the writes it receives.
+type countingWriter struct {
+	mu     sync.Mutex
+	writes int
+	buf    bytes.Buffer
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes++
+
+	return c.buf.Write(p)
+}
+
+func (c *countingWriter) stats() (int, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.writes, c.buf.String()
+}
+
+func TestStdioWriter(t *testing.T) {
+	t.Parallel()
+
+	msg := `{"jsonrpc":"2.0","method":"notifications/progress"}` + "\n"
+
+	tests := []struct {
+		name       string
+		cfg        *config.StdioConfig
+		messages   int
+		wantWrites int
+	}{
+		{"default writes each message", nil, 5, 5},
+		{"flush interval coalesces", &config.StdioConfig{FlushInterval: 20 * time.Millisecond}, 5, 1},
+		{"full buffer is written early", &config.StdioConfig{FlushInterval: time.Second, WriteBuffer: config.ByteSize(2 * len(msg))}, 5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dst := &countingWriter{}
+			w, flush := newStdioWriter(dst, tt.cfg)
+
+			for range tt.messages {
+				if _, err := io.WriteString(w, msg); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			if tt.cfg.Coalesces() && tt.cfg.FlushInterval < time.Second {
+				// Written out by the timer, without Flush
+				deadline := time.Now().Add(2 * time.Second)
+				for writes, _ := dst.stats(); writes == 0 && time.Now().Before(deadline); writes, _ = dst.stats() {
+					time.Sleep(5 * time.Millisecond)
+				}
+			} else if err := flush(); err != nil {
+				t.Fatalf("flush() error = %v", err)
+			}
+
+			writes, got := dst.stats()
+			if writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d", writes, tt.wantWrites)
+			}
+
+			if got != strings.Repeat(msg, tt.messages) {
+				t.Errorf("output = %q, want every message in order", got)
+			}
+		})
+	}
+}
+
+// BenchmarkStdioWriter measures the writes reaching stdout for a burst of
+// small messages, as a tool streaming progress notifications produces.
+func BenchmarkStdioWriter(b *testing.B) {
+	msg := []byte(`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"t","progress":1}}` + "\n")
+
+	configs := []struct {
+		name string
+		cfg  *config.StdioConfig
+	}{
+		{"unbuffered", nil},
+		{"flush_1ms", &config.StdioConfig{FlushInterval: time.Millisecond}},
+		{"flush_1ms_4KiB", &config.StdioConfig{FlushInterval: time.Millisecond, WriteBuffer: 4 << 10}},
+	}
+
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			dst := &countingWriter{}
+
+			b.ReportAllocs()
+
+			for range b.N {
+				dst.buf.Reset()
+
+				w, flush := newStdioWriter(dst, c.cfg)
+				for range 100 {
+					_, _ = w.Write(msg)
+				}
+
+				_ = flush()
+			}
+
+			writes, _ := dst.stats()
+			b.ReportMetric(float64(writes)/float64(b.N), "writes/op")
+		})
+	}
+}
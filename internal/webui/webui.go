@@ -0,0 +1,315 @@
+// Package webui serves a read-only web page of the primary instance showing
+// its servers, tools, health and recent tool calls: a friendlier view than
+// 'assern status' for teammates who just want to know whether a server is up
+// and what it offers.
+//
+// The page listens on loopback only and every request must present the
+// configured token (see config.WebUIConfig). Nothing it serves changes the
+// aggregator.
+package webui
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/audit"
+)
+
+// Paths served by Server.
+const (
+	// PagePath is the HTML page.
+	PagePath = "/"
+	// StatusPath serves what the page shows as JSON.
+	StatusPath = "/api/status"
+)
+
+const (
+	// cookieName holds the token once the browser presented it as ?token=.
+	cookieName = "assern_ui"
+
+	// refreshSeconds is how often the page reloads itself.
+	refreshSeconds = 10
+
+	// shutdownTimeout bounds how long Stop waits for open requests.
+	shutdownTimeout = 5 * time.Second
+)
+
+//go:embed page.html
+var pageFS embed.FS
+
+var pageTemplate = template.Must(template.New("page.html").Funcs(template.FuncMap{
+	"ms": func(ms float64) string { return fmt.Sprintf("%.0f ms", ms) },
+	"clock": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+
+		return t.Local().Format("15:04:05")
+	},
+}).ParseFS(pageFS, "page.html"))
+
+// Source is what the page shows; *aggregator.Aggregator implements it.
+type Source interface {
+	Status() aggregator.Status
+	ListTools() []aggregator.ToolEntry
+	RecentCalls() []audit.Record
+}
+
+// Tool is a tool as listed by StatusPath.
+type Tool struct {
+	Name        string `json:"name"`
+	Server      string `json:"server"`
+	Description string `json:"description,omitempty"`
+}
+
+// Snapshot is the document served at StatusPath.
+type Snapshot struct {
+	Status      aggregator.Status `json:"status"`
+	Tools       []Tool            `json:"tools"`
+	RecentCalls []audit.Record    `json:"recent_calls"`
+}
+
+// Server serves the page and its JSON form.
+type Server struct {
+	addr   string
+	token  string
+	src    Source
+	logger *slog.Logger
+
+	srv      *http.Server
+	listener net.Listener
+}
+
+// NewServer creates a web UI for src on addr, answering requests that
+// present token.
+func NewServer(addr, token string, src Source, logger *slog.Logger) *Server {
+	return &Server{addr: addr, token: token, src: src, logger: logger}
+}
+
+// NewToken returns a random token for a web UI configured without one.
+func NewToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// Handler returns the HTTP handler of the page and StatusPath.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+StatusPath, s.serveStatus)
+	mux.HandleFunc("GET "+PagePath+"{$}", s.servePage)
+
+	return s.guard(mux)
+}
+
+// guard lets through read-only requests for a loopback host that present
+// the token, setting the cookie when it came as ?token=.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "the web UI is read-only", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		// A page on another site resolving its name to 127.0.0.1 must not
+		// reach the UI
+		if !loopbackHost(r.Host) {
+			http.Error(w, "unexpected host", http.StatusForbidden)
+
+			return
+		}
+
+		if token := r.URL.Query().Get("token"); token != "" && s.valid(token) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     cookieName,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+
+			// Drop the token from the address bar and history
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+
+			return
+		}
+
+		if !s.authorized(r) {
+			s.logger.Debug("rejected web UI request", "remote", r.RemoteAddr, "path", r.URL.Path)
+			http.Error(w, "open the URL with ?token= logged at startup, or send Authorization: Bearer <token>", http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized reports whether r carries the token as a bearer token or in
+// the cookie.
+func (s *Server) authorized(r *http.Request) bool {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return s.valid(strings.TrimSpace(token))
+	}
+
+	cookie, err := r.Cookie(cookieName)
+
+	return err == nil && s.valid(cookie.Value)
+}
+
+func (s *Server) valid(token string) bool {
+	return s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// loopbackHost reports whether the Host header names this machine.
+func loopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// snapshot collects what the page shows.
+func (s *Server) snapshot() Snapshot {
+	entries := s.src.ListTools()
+	tools := make([]Tool, 0, len(entries))
+
+	for _, e := range entries {
+		tools = append(tools, Tool{Name: e.PrefixedName, Server: e.ServerName, Description: e.Tool.Description})
+	}
+
+	slices.SortFunc(tools, func(a, b Tool) int {
+		return strings.Compare(a.Server+"\x00"+a.Name, b.Server+"\x00"+b.Name)
+	})
+
+	return Snapshot{Status: s.src.Status(), Tools: tools, RecentCalls: s.src.RecentCalls()}
+}
+
+func (s *Server) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(s.snapshot()); err != nil {
+		s.logger.Debug("writing web UI status", "error", err)
+	}
+}
+
+// serverView is a server on the page with its tools.
+type serverView struct {
+	aggregator.ServerStatus
+
+	ToolList []Tool
+}
+
+func (s *Server) servePage(w http.ResponseWriter, _ *http.Request) {
+	snap := s.snapshot()
+
+	servers := make([]serverView, 0, len(snap.Status.Servers))
+	for _, srv := range snap.Status.Servers {
+		view := serverView{ServerStatus: srv}
+
+		for _, tool := range snap.Tools {
+			if tool.Server == srv.Name {
+				view.ToolList = append(view.ToolList, tool)
+			}
+		}
+
+		servers = append(servers, view)
+	}
+
+	data := map[string]any{
+		"Status":  snap.Status,
+		"Servers": servers,
+		"Calls":   snap.RecentCalls,
+		"Refresh": refreshSeconds,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := pageTemplate.Execute(w, data); err != nil {
+		s.logger.Debug("rendering web UI page", "error", err)
+	}
+}
+
+// Start listens on the configured address and serves in the background.
+// Errors binding the address are returned; later serve errors are logged.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.addr, err)
+	}
+
+	s.listener = listener
+	s.srv = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Warn("web UI stopped", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server listens on, or "" before Start.
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+
+	return s.listener.Addr().String()
+}
+
+// URL returns the address of the page with the token, for opening it in a
+// browser; "" before Start.
+func (s *Server) URL() string {
+	if s.listener == nil {
+		return ""
+	}
+
+	return "http://" + s.Addr() + PagePath + "?token=" + s.token
+}
+
+// Stop shuts the server down.
+func (s *Server) Stop() error {
+	if s.srv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return s.srv.Shutdown(ctx)
+}
@@ -0,0 +1,186 @@
+package webui
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/audit"
+)
+
+const testToken = "s3cret"
+
+// fakeSource serves a fixed status, tools and calls.
+type fakeSource struct{}
+
+func (fakeSource) Status() aggregator.Status {
+	return aggregator.Status{
+		Version:   "1.0.0",
+		Instance:  "work",
+		ServersUp: 1,
+		Tools:     1,
+		Servers: []aggregator.ServerStatus{
+			{Name: "github", State: "up", Health: aggregator.HealthHealthy, Tools: 1, Transport: "stdio"},
+		},
+	}
+}
+
+func (fakeSource) ListTools() []aggregator.ToolEntry {
+	return []aggregator.ToolEntry{
+		{ServerName: "github", PrefixedName: "github_search", Tool: mcp.NewTool("search", mcp.WithDescription("Search code"))},
+	}
+}
+
+func (fakeSource) RecentCalls() []audit.Record {
+	return []audit.Record{
+		{Time: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), Tool: "github_search", Server: "github", Status: "ok", DurationMS: 12},
+	}
+}
+
+func newTestServer() *Server {
+	return NewServer("127.0.0.1:0", testToken, fakeSource{}, slog.New(slog.DiscardHandler))
+}
+
+func TestHandler_Access(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		host       string
+		bearer     string
+		cookie     string
+		wantStatus int
+	}{
+		{name: "no token", target: "/", wantStatus: http.StatusUnauthorized},
+		{name: "wrong bearer", target: "/", bearer: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "bearer", target: "/", bearer: testToken, wantStatus: http.StatusOK},
+		{name: "cookie", target: StatusPath, cookie: testToken, wantStatus: http.StatusOK},
+		{name: "wrong cookie", target: StatusPath, cookie: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "token query", target: "/?token=" + testToken, wantStatus: http.StatusSeeOther},
+		{name: "wrong token query", target: "/?token=nope", wantStatus: http.StatusUnauthorized},
+		{name: "localhost host", target: "/", host: "localhost:7878", bearer: testToken, wantStatus: http.StatusOK},
+		{name: "foreign host", target: "/", host: "evil.example:7878", bearer: testToken, wantStatus: http.StatusForbidden},
+		{name: "write method", method: http.MethodPost, target: StatusPath, bearer: testToken, wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown path", target: "/admin", bearer: testToken, wantStatus: http.StatusNotFound},
+	}
+
+	handler := newTestServer().Handler()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, tt.target, nil)
+			req.Host = "127.0.0.1:7878"
+
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: cookieName, Value: tt.cookie})
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			if tt.wantStatus == http.StatusSeeOther {
+				if loc := rec.Header().Get("Location"); loc != "/" {
+					t.Errorf("Location = %q, want the path without the token", loc)
+				}
+
+				cookies := rec.Result().Cookies()
+				if len(cookies) != 1 || cookies[0].Value != testToken || !cookies[0].HttpOnly {
+					t.Errorf("cookies = %v, want an HttpOnly %s cookie", cookies, cookieName)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_Content(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestServer().Handler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = "127.0.0.1:7878"
+		req.Header.Set("Authorization", "Bearer "+testToken)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	var snap Snapshot
+	if err := json.NewDecoder(get(StatusPath).Body).Decode(&snap); err != nil {
+		t.Fatalf("decoding %s: %v", StatusPath, err)
+	}
+
+	if snap.Status.Instance != "work" || len(snap.Tools) != 1 || snap.Tools[0].Description != "Search code" ||
+		len(snap.RecentCalls) != 1 {
+		t.Errorf("snapshot = %+v", snap)
+	}
+
+	page := get(PagePath).Body.String()
+	for _, want := range []string{"Assern · work", "github", "github_search", "Search code", "12 ms"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+}
+
+func TestServer_StartStop(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer()
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if !strings.HasSuffix(s.URL(), "/?token="+testToken) {
+		t.Errorf("URL() = %q", s.URL())
+	}
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+s.Addr()+StatusPath, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+// Package aggregator is the stable Go API of the Assern aggregation core:
+// it starts MCP servers, prefixes and merges their tools, resources and
+// prompts, and offers them through one mcp-go server. Unlike pkg/assern it
+// reads no configuration files; the servers are given in code.
+//
+// The types of this package are the supported surface; the internal
+// packages behind it may change between releases.
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+)
+
+// Errors returned by the aggregator, for use with errors.Is.
+var (
+	ErrNoServers        = aggregator.ErrNoServers
+	ErrAllServersFailed = aggregator.ErrAllServersFailed
+	ErrServerNotFound   = aggregator.ErrServerNotFound
+	ErrToolNotFound     = aggregator.ErrToolNotFound
+	ErrInvalidTransport = aggregator.ErrInvalidTransport
+	ErrServerBusy       = aggregator.ErrServerBusy
+	ErrResourceTooLarge = aggregator.ErrResourceTooLarge
+)
+
+// Transports of ServerConfig.Transport.
+const (
+	TransportStdio     = string(aggregator.TransportStdio)
+	TransportSSE       = string(aggregator.TransportSSE)
+	TransportHTTP      = string(aggregator.TransportHTTP)
+	TransportWebSocket = string(aggregator.TransportWebSocket)
+	TransportContainer = string(aggregator.TransportContainer)
+)
+
+// ServerConfig describes a backend MCP server: a command run over stdio, an
+// image run with docker, or a URL reached over Streamable HTTP, SSE or
+// WebSocket.
+type ServerConfig struct {
+	// Command, Args, Env and WorkDir start a stdio server. Env is added to
+	// the environment of this process; ${VAR} references in the fields of
+	// the server are expanded from it.
+	Command string
+	Args    []string
+	Env     map[string]string
+	WorkDir string
+
+	// Image runs the server in a docker container instead of Command, with
+	// Args, Env and WorkDir applying inside it, Volumes mounted
+	// ("host:container[:ro]") and Network joined.
+	Image   string
+	Volumes []string
+	Network string
+
+	// URL and Headers reach a remote server.
+	URL     string
+	Headers map[string]string
+
+	// Transport is TransportStdio, TransportContainer, TransportSSE,
+	// TransportHTTP or TransportWebSocket. Empty picks stdio for a Command,
+	// container for an Image, WebSocket for a ws:// or wss:// URL and
+	// Streamable HTTP for any other URL.
+	Transport string
+
+	// Allowed limits the tools exposed to those named, by their unprefixed
+	// name; empty exposes every tool. Denied leaves out those named.
+	Allowed []string
+	Denied  []string
+}
+
+// Options configures an Aggregator.
+type Options struct {
+	// Servers are the backend servers Start starts, by the server name
+	// their tools, resources and prompts are prefixed with.
+	Servers map[string]ServerConfig
+
+	// InProcess are MCP servers running in this process, by server name;
+	// Start starts them next to Servers. A name must not be in both.
+	InProcess map[string]*server.MCPServer
+
+	// Logger receives the aggregator's logs. Nil discards them.
+	Logger *slog.Logger
+
+	// OutputFormat is "json" (default) or "toon", a token-optimized
+	// encoding of tool results.
+	OutputFormat string
+}
+
+// Tool is a tool of a backend server as the aggregator exposes it.
+type Tool struct {
+	// Name is the exposed name, the tool's name prefixed with its server's
+	// (e.g. "github_search").
+	Name string
+	// Server is the name of the backend server.
+	Server string
+	// Definition is the tool as the backend defines it.
+	Definition mcp.Tool
+}
+
+// Aggregator aggregates the tools, resources and prompts of MCP servers.
+// Its methods are safe for concurrent use.
+type Aggregator struct {
+	core *aggregator.Aggregator
+
+	once      sync.Once
+	mcpServer *server.MCPServer
+}
+
+// New creates an aggregator for opts; Start starts its servers.
+func New(opts Options) (*Aggregator, error) {
+	cfg := config.NewConfig()
+	for name, srv := range opts.Servers {
+		cfg.Servers[name] = srv.config()
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	core, err := aggregator.New(aggregator.Options{
+		Config:       cfg,
+		EnvLoader:    env.NewLoader(),
+		Logger:       logger,
+		OutputFormat: opts.OutputFormat,
+		FixedConfig:  true,
+		InProcess:    opts.InProcess,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Aggregator{core: core}, nil
+}
+
+// Start starts the servers of Options and discovers their tools, resources
+// and prompts. Servers that fail to start are logged and left out; Start
+// fails with ErrAllServersFailed only when none starts.
+func (a *Aggregator) Start(ctx context.Context) error {
+	return a.core.Start(ctx)
+}
+
+// Stop stops every server.
+func (a *Aggregator) Stop() error {
+	return a.core.Stop()
+}
+
+// AddServer starts a further server under name and exposes its tools,
+// also on an MCP server CreateMCPServer already returned.
+func (a *Aggregator) AddServer(ctx context.Context, name string, cfg ServerConfig) error {
+	return a.core.StartServer(ctx, name, cfg.config())
+}
+
+// ListTools returns the tools of the running servers, sorted by name.
+func (a *Aggregator) ListTools() []Tool {
+	entries := a.core.ListTools()
+	tools := make([]Tool, 0, len(entries))
+
+	for _, entry := range entries {
+		tools = append(tools, Tool{Name: entry.PrefixedName, Server: entry.ServerName, Definition: entry.Tool})
+	}
+
+	slices.SortFunc(tools, func(x, y Tool) int { return strings.Compare(x.Name, y.Name) })
+
+	return tools
+}
+
+// CallTool calls the tool exposed as name with args, as an MCP client's
+// tools/call would.
+func (a *Aggregator) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	return a.core.CallTool(ctx, name, args)
+}
+
+// ServerNames returns the names of the running servers, sorted.
+func (a *Aggregator) ServerNames() []string {
+	return slices.Sorted(slices.Values(a.core.ServerNames()))
+}
+
+// CreateMCPServer returns the MCP server offering the aggregated tools,
+// resources and prompts, to serve over any mcp-go transport, e.g.
+// server.ServeStdio. Call it after Start; later calls return the same
+// server.
+func (a *Aggregator) CreateMCPServer() *server.MCPServer {
+	a.once.Do(func() { a.mcpServer = a.core.CreateMCPServer() })
+
+	return a.mcpServer
+}
+
+// config returns the internal configuration of c.
+func (c ServerConfig) config() *config.ServerConfig {
+	return &config.ServerConfig{
+		Command:   c.Command,
+		Args:      slices.Clone(c.Args),
+		Env:       maps.Clone(c.Env),
+		WorkDir:   c.WorkDir,
+		Image:     c.Image,
+		Volumes:   slices.Clone(c.Volumes),
+		Network:   c.Network,
+		URL:       c.URL,
+		Headers:   maps.Clone(c.Headers),
+		Transport: c.Transport,
+		Allowed:   slices.Clone(c.Allowed),
+		Denied:    slices.Clone(c.Denied),
+	}
+}
@@ -0,0 +1,109 @@
+package aggregator_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/pkg/aggregator"
+)
+
+// newMathServer returns an in-process MCP server with add and sub tools.
+func newMathServer() *server.MCPServer {
+	srv := server.NewMCPServer("math", "1.0.0", server.WithToolCapabilities(false))
+
+	for _, name := range []string{"sub", "add"} {
+		srv.AddTool(mcp.NewTool(name, mcp.WithNumber("a"), mcp.WithNumber("b")), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			a, b := req.GetFloat("a", 0), req.GetFloat("b", 0)
+			if name == "sub" {
+				b = -b
+			}
+
+			return mcp.NewToolResultText(strconv.FormatFloat(a+b, 'f', -1, 64)), nil
+		})
+	}
+
+	return srv
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	agg, err := aggregator.New(aggregator.Options{InProcess: map[string]*server.MCPServer{"math": newMathServer()}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	tools := agg.ListTools()
+	if len(tools) != 2 || tools[0].Name != "math_add" || tools[0].Server != "math" || tools[0].Definition.Name != "add" {
+		t.Fatalf("ListTools() = %+v, want math_add and math_sub", tools)
+	}
+
+	result, err := agg.CallTool(t.Context(), "math_sub", map[string]any{"a": 5, "b": 3})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "2" {
+		t.Errorf("CallTool() = %+v, want 2", result.Content)
+	}
+
+	if _, err := agg.CallTool(t.Context(), "math_mul", nil); !errors.Is(err, aggregator.ErrToolNotFound) {
+		t.Errorf("CallTool(unknown) error = %v, want ErrToolNotFound", err)
+	}
+
+	c, err := client.NewInProcessClient(agg.CreateMCPServer())
+	if err != nil {
+		t.Fatalf("NewInProcessClient: %v", err)
+	}
+
+	if _, err := c.Initialize(t.Context(), mcp.InitializeRequest{}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	listed, err := c.ListTools(t.Context(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+
+	if !slices.ContainsFunc(listed.Tools, func(tool mcp.Tool) bool { return tool.Name == "math_add" }) {
+		t.Errorf("tools/list = %+v, want math_add", listed.Tools)
+	}
+}
+
+func TestAggregatorAddServer(t *testing.T) {
+	t.Parallel()
+
+	agg, err := aggregator.New(aggregator.Options{InProcess: map[string]*server.MCPServer{"math": newMathServer()}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := agg.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = agg.Stop() })
+
+	if err := agg.AddServer(t.Context(), "math", aggregator.ServerConfig{Command: "math-mcp"}); err == nil {
+		t.Error("AddServer() of a running server's name succeeded")
+	}
+
+	if err := agg.AddServer(t.Context(), "empty", aggregator.ServerConfig{}); !errors.Is(err, aggregator.ErrInvalidTransport) {
+		t.Errorf("AddServer() without command or URL error = %v, want ErrInvalidTransport", err)
+	}
+
+	if got := agg.ServerNames(); len(got) != 1 || got[0] != "math" {
+		t.Errorf("ServerNames() = %v, want [math]", got)
+	}
+}
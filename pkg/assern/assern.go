@@ -0,0 +1,147 @@
+// Package assern embeds Assern in a Go program: MCP servers built with
+// mcp-go run in the same process and are aggregated next to the backends
+// of the assern configuration, as 'assern serve' does, without spawning
+// the CLI.
+package assern
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/internal/aggregator"
+	"github.com/valksor/go-assern/internal/config"
+	"github.com/valksor/go-assern/internal/env"
+)
+
+// Options configures an embedded Assern.
+type Options struct {
+	// Servers are the in-process MCP servers to aggregate, by the server
+	// name their tools, resources and prompts are prefixed with. A name
+	// must not also be configured in mcp.json.
+	Servers map[string]*server.MCPServer
+
+	// WorkDir is where the project and its .assern directory are looked
+	// up from, as the working directory is for the CLI. Empty uses the
+	// current directory.
+	WorkDir string
+
+	// Project selects a project of config.yaml, as --project does. Empty
+	// uses the project the .assern directory of WorkDir names, if any.
+	Project string
+
+	// NoConfig leaves the configuration and .env files out: only Servers
+	// are aggregated, with default settings.
+	NoConfig bool
+
+	// Logger receives the aggregator's logs. Nil discards them.
+	Logger *slog.Logger
+}
+
+// Assern aggregates in-process and configured MCP servers behind one MCP
+// server.
+type Assern struct {
+	agg *aggregator.Aggregator
+
+	once      sync.Once
+	mcpServer *server.MCPServer
+}
+
+// New loads the configuration and prepares the aggregator; Start starts
+// the servers.
+func New(opts Options) (*Assern, error) {
+	if len(opts.Servers) == 0 && opts.NoConfig {
+		return nil, errors.New("no servers: register in-process servers or use the configuration")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	workDir := opts.WorkDir
+	if workDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("getting working directory: %w", err)
+		}
+
+		workDir = wd
+	}
+
+	cfg := config.NewConfig()
+	envLoader := env.NewLoader()
+
+	if !opts.NoConfig {
+		loaded, err := config.LoadEffective(workDir, opts.Project, config.LoadOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+
+		cfg = loaded
+		loadEnv(envLoader, workDir, logger)
+	}
+
+	agg, err := aggregator.New(aggregator.Options{
+		Config:      cfg,
+		EnvLoader:   envLoader,
+		Logger:      logger,
+		Timeout:     cfg.Settings.Timeout,
+		WorkDir:     workDir,
+		ProjectName: opts.Project,
+		FixedConfig: opts.NoConfig,
+		InProcess:   opts.Servers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating aggregator: %w", err)
+	}
+
+	return &Assern{agg: agg}, nil
+}
+
+// Start starts the in-process and configured servers and discovers their
+// tools. It fails only when no server starts.
+func (a *Assern) Start(ctx context.Context) error {
+	return a.agg.Start(ctx)
+}
+
+// Stop stops every server.
+func (a *Assern) Stop() error {
+	return a.agg.Stop()
+}
+
+// MCPServer returns the MCP server offering the aggregated tools,
+// resources and prompts, to serve over any mcp-go transport. Call it after
+// Start.
+func (a *Assern) MCPServer() *server.MCPServer {
+	a.once.Do(func() { a.mcpServer = a.agg.CreateMCPServer() })
+
+	return a.mcpServer
+}
+
+// ServeStdio serves the aggregated servers on stdin and stdout until stdin
+// closes or the process is signalled.
+func (a *Assern) ServeStdio() error {
+	return server.ServeStdio(a.MCPServer())
+}
+
+// loadEnv loads the global and project .env files into envLoader, as the
+// CLI does; files that fail to load are logged and skipped.
+func loadEnv(envLoader *env.Loader, workDir string, logger *slog.Logger) {
+	if path, err := config.GlobalEnvPath(); err == nil {
+		if err := envLoader.LoadDotenv(path); err != nil {
+			logger.Warn("failed to load global env file", "error", err)
+		}
+	}
+
+	if dir := config.FindLocalConfigDir(workDir); dir != "" {
+		if _, err := envLoader.LoadDotenvDir(dir, "project"); err != nil {
+			logger.Warn("failed to load project env file", "error", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package assern_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/valksor/go-assern/pkg/assern"
+)
+
+func TestEmbedded(t *testing.T) {
+	t.Parallel()
+
+	greeter := server.NewMCPServer("greeter", "1.0.0", server.WithToolCapabilities(false))
+	greeter.AddTool(mcp.NewTool("greet", mcp.WithString("name")), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello " + req.GetString("name", "")), nil
+	})
+
+	a, err := assern.New(assern.Options{Servers: map[string]*server.MCPServer{"greeter": greeter}, NoConfig: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := a.Start(t.Context()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = a.Stop() })
+
+	c, err := client.NewInProcessClient(a.MCPServer())
+	if err != nil {
+		t.Fatalf("NewInProcessClient: %v", err)
+	}
+
+	if _, err := c.Initialize(t.Context(), mcp.InitializeRequest{}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "greeter_greet"
+	req.Params.Arguments = map[string]any{"name": "world"}
+
+	result, err := c.CallTool(t.Context(), req)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "hello world" {
+		t.Errorf("CallTool() = %+v, want the greeting of the in-process server", result.Content)
+	}
+}
+
+func TestNewWithoutServers(t *testing.T) {
+	t.Parallel()
+
+	if _, err := assern.New(assern.Options{NoConfig: true}); err == nil {
+		t.Error("New() without servers or configuration succeeded")
+	}
+}